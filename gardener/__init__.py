@@ -1,3 +1,27 @@
 """
 Gardener package root
+
+The programmatic API lives in gardener.api and is re-exported here
 """
+
+from gardener.api import (
+    AnalysisOptions,
+    CancellationToken,
+    FileEvidence,
+    RepoResult,
+    ResolutionResult,
+    analyze_file,
+    analyze_repo,
+    resolve_repository_url,
+)
+
+__all__ = [
+    "AnalysisOptions",
+    "CancellationToken",
+    "FileEvidence",
+    "RepoResult",
+    "ResolutionResult",
+    "analyze_file",
+    "analyze_repo",
+    "resolve_repository_url",
+]
@@ -0,0 +1,91 @@
+"""
+Canonical ordering of analysis results, so that repeated runs serialize identically
+
+Filesystem walk order, worker threads and network timing all influence the
+order in which packages, files and evidence are discovered. The canonical form
+removes that variation: every object's keys are sorted, except that
+external_packages is ordered by (ecosystem, name, version), and lists whose order
+carries no meaning are sorted. Lists whose order is meaningful, such as per-file
+import evidence (source order) and cycles, are kept; top dependencies stay ranked
+by score. Floats are rounded to FLOAT_DIGITS significant digits
+"""
+
+import json
+
+# Significant digits kept in floats; centrality scores differ in their last bits with graph insertion order
+FLOAT_DIGITS = 12
+
+# Lists of strings or records treated as unordered sets, by key name
+_UNORDERED_LISTS = {
+    "errors",
+    "found_in_manifests",
+    "go_modules",
+    "languages_detected",
+    "required_by",
+    "seen_in",
+    "version_conflicts",
+}
+
+# analyzer_details maps whose values are unordered lists of package names or files
+_UNORDERED_FILE_MAPS = ("file_imports", "local_imports_map")
+
+
+def _sort_key(value):
+    """
+    Order mixed JSON values: records by their "file" (then their full content), scalars by value
+    """
+    if isinstance(value, dict):
+        return (0, str(value.get("file", "")), json.dumps(value, sort_keys=True, default=str))
+    return (1, "", json.dumps(value, sort_keys=True, default=str))
+
+
+def _canonical(value, key=None):
+    """
+    Return a copy of a JSON value with sorted keys and rounded floats, sorting the lists named in _UNORDERED_LISTS
+    """
+    if isinstance(value, dict):
+        return {name: _canonical(value[name], name) for name in sorted(value, key=str)}
+    if isinstance(value, (list, tuple)):
+        items = [_canonical(item) for item in value]
+        if key in _UNORDERED_LISTS:
+            items.sort(key=_sort_key)
+        return items
+    if isinstance(value, float):
+        return float(f"{value:.{FLOAT_DIGITS}g}")
+    return value
+
+
+def _package_order(item):
+    name, info = item
+    info = info if isinstance(info, dict) else {}
+    return (str(info.get("ecosystem", "")), name, str(info.get("version", "")))
+
+
+def canonical_results(results):
+    """
+    Return a canonically ordered copy of analysis results
+
+    Args:
+        results (dict): Analysis results as returned by DependencyAnalyzer.analyze
+
+    Returns:
+        dict: The same data with a fixed key and list order; `results` is left unchanged
+    """
+    canonical = _canonical(results)
+    packages = results.get("external_packages")
+    if isinstance(packages, dict):
+        canonical["external_packages"] = {
+            name: _canonical(info) for name, info in sorted(packages.items(), key=_package_order)
+        }
+    details = canonical.get("analyzer_details")
+    if isinstance(details, dict):
+        for map_name in _UNORDERED_FILE_MAPS:
+            if isinstance(details.get(map_name), dict):
+                details[map_name] = {path: sorted(values, key=_sort_key) for path, values in details[map_name].items()}
+    if isinstance(canonical.get("top_dependencies"), list):
+        # Ranked by score; ties are broken by name instead of by discovery order
+        canonical["top_dependencies"] = sorted(
+            canonical["top_dependencies"],
+            key=lambda dep: (-float(dep.get("percentage", 0) or 0), str(dep.get("package_name", ""))),
+        )
+    return canonical
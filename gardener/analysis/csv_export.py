@@ -0,0 +1,81 @@
+"""
+CSV serialization of analysis results
+
+Flattens the external packages detected by an analysis into one RFC 4180 row
+per dependency for spreadsheet import
+"""
+
+import csv
+import io
+
+CSV_COLUMNS = ("ecosystem", "package", "version", "repository_url", "resolution_status", "scope")
+
+# Delimiter -> file suffix used by persistence backends
+CSV_SUFFIXES = {
+    ",": "_dependencies.csv",
+    "\t": "_dependencies.tsv",
+}
+DEFAULT_CSV_SUFFIX = CSV_SUFFIXES[","]
+
+
+def resolution_status(package_info):
+    """
+    Summarize how a package's repository URL was resolved
+
+    Args:
+        package_info (dict): Package metadata from external_packages
+
+    Returns:
+        str: "resolved", the resolution receipt's failure reason (e.g. "offline-skipped"), or "unresolved"
+    """
+    if package_info.get("repository_url"):
+        return "resolved"
+    return (package_info.get("resolution") or {}).get("reason") or "unresolved"
+
+
+def csv_rows(results):
+    """
+    Build one row per external package, sorted by ecosystem then package name
+
+    Args:
+        results (dict): Analysis results with an external_packages mapping
+
+    Returns:
+        list: Row lists in CSV_COLUMNS order
+    """
+    external_packages = results.get("external_packages", {})
+    rows = []
+    for package_name, package_info in external_packages.items():
+        version = package_info.get("version")
+        rows.append(
+            [
+                package_info.get("ecosystem") or "",
+                package_name,
+                version if isinstance(version, str) else "",
+                package_info.get("repository_url") or "",
+                resolution_status(package_info),
+                package_info.get("scope") or "",
+            ]
+        )
+    return sorted(rows, key=lambda row: (row[0], row[1]))
+
+
+def to_csv(results, delimiter=","):
+    """
+    Serialize analysis results as delimited text with a header row
+
+    Fields containing the delimiter, quotes or line breaks are quoted and inner
+    quotes doubled; records end in CRLF as RFC 4180 requires
+
+    Args:
+        results (dict): Analysis results with an external_packages mapping
+        delimiter (str): Single-character field separator ("\\t" for TSV)
+
+    Returns:
+        str: CSV document
+    """
+    buffer = io.StringIO()
+    writer = csv.writer(buffer, delimiter=delimiter, quoting=csv.QUOTE_MINIMAL, lineterminator="\r\n")
+    writer.writerow(CSV_COLUMNS)
+    writer.writerows(csv_rows(results))
+    return buffer.getvalue()
@@ -0,0 +1,92 @@
+"""
+Known clusters of tightly coupled Go modules
+
+Code generated from .proto files imports the protobuf runtime, gRPC and the
+googleapis types together, so one service definition pulls in several modules
+that are really one toolchain. With GoAnalysisConfig.GROUP_CLUSTERS their
+packages are tagged with the cluster and summarized in a `clusters` section,
+while each module stays listed on its own
+"""
+
+# Cluster name -> module paths; a module belongs to a cluster when its path equals one of them
+# or extends it by whole path segments (google.golang.org/genproto/googleapis/rpc, .../grpc-gateway/v2)
+GO_DEPENDENCY_CLUSTERS = {
+    "protobuf-toolchain": (
+        "google.golang.org/protobuf",
+        "google.golang.org/grpc",
+        "google.golang.org/genproto",
+        "github.com/golang/protobuf",
+        "github.com/grpc-ecosystem/grpc-gateway",
+    ),
+}
+
+# Package scopes in the order the first one present gives a cluster its scope (as for a package)
+_SCOPE_ORDER = ("production", "tool", "test", "example", "ignored-build")
+
+
+def dependency_cluster(module_path, clusters=None):
+    """
+    Return the name of the cluster a Go module belongs to
+
+    Args:
+        module_path (str): Go module path, e.g. "google.golang.org/genproto/googleapis/api"
+        clusters (dict): Cluster name -> module paths; defaults to GO_DEPENDENCY_CLUSTERS
+
+    Returns:
+        str|None
+    """
+    for name, members in (clusters or GO_DEPENDENCY_CLUSTERS).items():
+        for member in members:
+            if module_path == member or module_path.startswith(member + "/"):
+                return name
+    return None
+
+
+def group_dependency_clusters(external_packages, clusters=None):
+    """
+    Tag the Go packages of each known cluster and summarize the clusters they form
+
+    Each member package gets `cluster` set to the cluster name. Run after the import
+    provenance is merged, so the `seen_in` files of the members are known
+
+    Args:
+        external_packages (dict): External packages, modified in place
+        clusters (dict): Cluster name -> module paths; defaults to GO_DEPENDENCY_CLUSTERS
+
+    Returns:
+        dict: Cluster name -> {"ecosystem": "go", "modules": sorted member packages, "files": sorted
+            importing files, "generated_files": those marked generated, "usage_count": number of
+            importing files, ["scope"]} for the clusters with at least one member package
+    """
+    grouped = {}
+    for package_name, package_info in external_packages.items():
+        if package_info.get("ecosystem") != "go":
+            continue
+        name = dependency_cluster(package_name, clusters)
+        if name is None:
+            continue
+        package_info["cluster"] = name
+        cluster = grouped.setdefault(name, {"modules": [], "files": set(), "generated_files": set(), "scopes": set()})
+        cluster["modules"].append(package_name)
+        for entry in package_info.get("seen_in", []):
+            cluster["files"].add(entry["file"])
+            if entry.get("generated"):
+                cluster["generated_files"].add(entry["file"])
+        if package_info.get("scope"):
+            cluster["scopes"].add(package_info["scope"])
+
+    sections = {}
+    for name in sorted(grouped):
+        cluster = grouped[name]
+        section = {
+            "ecosystem": "go",
+            "modules": sorted(cluster["modules"]),
+            "files": sorted(cluster["files"]),
+            "generated_files": sorted(cluster["generated_files"]),
+            "usage_count": len(cluster["files"]),
+        }
+        scope = next((scope for scope in _SCOPE_ORDER if scope in cluster["scopes"]), None)
+        if scope:
+            section["scope"] = scope
+        sections[name] = section
+    return sections
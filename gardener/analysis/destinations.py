@@ -0,0 +1,150 @@
+"""
+Extra output destinations written from one analysis
+
+Each destination renders the finished results in one format (--table-out,
+--json-out, --csv-out, --sbom-out, --markdown-out) to a file, or to stdout for "-", so CI can
+print a table and keep the JSON and an SBOM without analyzing twice. A file
+destination named ".gz" is gzipped, as is every destination with compress
+"""
+
+import gzip
+import json
+import os
+import sys
+
+from gardener.analysis.csv_export import to_csv
+from gardener.analysis.markdown_report import render_markdown_report
+from gardener.analysis.sbom import render_sbom
+from gardener.common.compression import open_text_output
+
+STDOUT = "-"
+
+# Destination kinds in the order they are written
+DESTINATION_KINDS = ("json", "csv", "sbom", "markdown", "table")
+
+TABLE_COLUMNS = ("SCORE", "PACKAGE", "ECOSYSTEM", "VERSION", "REPOSITORY")
+
+
+def render_table(results):
+    """
+    Render the top dependencies as an aligned plain-text table
+
+    Args:
+        results (dict): Analysis results
+
+    Returns:
+        str: Header and one line per top dependency, or a note when there are none
+    """
+    packages = results.get("external_packages", {})
+    rows = []
+    for dep in results.get("top_dependencies") or []:
+        name = dep["package_name"]
+        rows.append(
+            (
+                f"{dep['percentage']:.2f}%",
+                name,
+                dep.get("ecosystem") or "",
+                packages.get(name, {}).get("version") or "",
+                dep.get("package_url") or "",
+            )
+        )
+    if not rows:
+        return "No dependencies found\n"
+    widths = [max(len(row[i]) for row in rows + [TABLE_COLUMNS]) for i in range(len(TABLE_COLUMNS))]
+    lines = ["  ".join(cell.ljust(width) for cell, width in zip(row, widths)).rstrip() for row in [TABLE_COLUMNS] + rows]
+    return "\n".join(lines) + "\n"
+
+
+def render_destination(kind, results, root_name, sbom_format="cyclonedx", csv_delimiter=",", json_results=None):
+    """
+    Render the results for one destination kind
+
+    Args:
+        kind (str): One of DESTINATION_KINDS
+        results (dict): Analysis results
+        root_name (str): Name of the analyzed project, the SBOM root
+        sbom_format (str): "cyclonedx" or "spdx"
+        csv_delimiter (str): Field separator of the csv kind
+        json_results (dict|None): The results as serialized by the json kind, e.g. grouped by
+            ecosystem (see group_by_ecosystem); defaults to results
+
+    Returns:
+        str: Document text
+    """
+    if kind == "json":
+        return json.dumps(results if json_results is None else json_results, indent=2, default=str) + "\n"
+    if kind == "csv":
+        return to_csv(results, delimiter=csv_delimiter)
+    if kind == "sbom":
+        return json.dumps(render_sbom(results, sbom_format, root_name), indent=2) + "\n"
+    if kind == "markdown":
+        return render_markdown_report(results, root_name)
+    return render_table(results)
+
+
+def write_destination(destination, content, compress=False):
+    """
+    Write rendered output to a file path, or to stdout for "-"
+
+    Args:
+        destination (str): File path or STDOUT
+        content (str): Document text; CSV line terminators are written unchanged
+        compress (bool): Gzip the output, also to stdout; files named ".gz" are gzipped regardless
+    """
+    if destination == STDOUT:
+        if compress:
+            sys.stdout.flush()
+            sys.stdout.buffer.write(gzip.compress(content.encode("utf-8"), mtime=0))
+            sys.stdout.buffer.flush()
+            return
+        sys.stdout.write(content)
+        sys.stdout.flush()
+        return
+    parent = os.path.dirname(destination)
+    if parent:
+        os.makedirs(parent, exist_ok=True)
+    with open_text_output(destination, compress=compress, newline="") as f:
+        f.write(content)
+
+
+def write_destinations(
+    results,
+    destinations,
+    root_name,
+    logger,
+    sbom_format="cyclonedx",
+    csv_delimiter=",",
+    json_results=None,
+    compress=False,
+):
+    """
+    Render and write every requested destination
+
+    Args:
+        results (dict): Analysis results
+        destinations (dict): Destination kind -> file path or STDOUT
+        root_name (str): Name of the analyzed project, the SBOM root
+        logger (Logger): Logger instance
+        sbom_format (str): "cyclonedx" or "spdx"
+        csv_delimiter (str): Field separator of the csv destination
+        json_results (dict|None): The results as serialized by the json destination (see render_destination)
+        compress (bool): Gzip every destination (see write_destination)
+
+    Returns:
+        list: Kinds that failed to render or write
+    """
+    failed = []
+    for kind in DESTINATION_KINDS:
+        destination = destinations.get(kind)
+        if not destination:
+            continue
+        try:
+            content = render_destination(kind, results, root_name, sbom_format, csv_delimiter, json_results)
+            write_destination(destination, content, compress)
+        except Exception as e:
+            logger.error(f"Error writing {kind} output to {destination}: {e}")
+            failed.append(kind)
+            continue
+        if destination != STDOUT:
+            logger.info(f"Wrote {kind} output to: {destination}")
+    return failed
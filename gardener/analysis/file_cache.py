@@ -0,0 +1,151 @@
+"""
+Persistent per-file import extraction cache for incremental analysis
+
+Entries are keyed on each file's content hash and are only reused while the
+cache schema, the gardener version and the analysis context (source file set,
+manifests and alias configuration) are unchanged
+"""
+
+import hashlib
+import json
+import os
+import threading
+
+from gardener.common.utils import tool_version
+
+# Bump whenever extraction output changes shape or meaning so stale entries are discarded
+CACHE_SCHEMA_VERSION = 6
+
+INDEX_FILENAME = "file_imports.json"
+
+
+def content_hash(code):
+    """
+    Hash source text for cache lookups
+
+    Args:
+        code (str): File content
+
+    Returns:
+        str: Hex sha256 digest
+    """
+    return hashlib.sha256(code.encode("utf-8", errors="surrogatepass")).hexdigest()
+
+
+def context_fingerprint(context):
+    """
+    Hash the analysis inputs that affect per-file extraction besides the file itself
+
+    Args:
+        context (dict): JSON-serializable analysis context
+
+    Returns:
+        str: Hex sha256 digest
+    """
+    payload = json.dumps(context, sort_keys=True, default=str)
+    return hashlib.sha256(payload.encode("utf-8")).hexdigest()
+
+
+class FileAnalysisCache:
+    """
+    Thread-safe store of per-file extraction results persisted as JSON
+
+    Args:
+        cache_dir (str): Directory holding the cache index
+        context (dict): Analysis context; any change invalidates every entry
+        logger (Logger|None): Optional logger
+    """
+
+    def __init__(self, cache_dir, context, logger=None):
+        self.cache_dir = os.path.abspath(cache_dir)
+        self.index_path = os.path.join(self.cache_dir, INDEX_FILENAME)
+        self.logger = logger
+        self.key = {
+            "schema": CACHE_SCHEMA_VERSION,
+            "gardener_version": tool_version(),
+            "context": context_fingerprint(context),
+        }
+        self.hits = 0
+        self.misses = 0
+        self._lock = threading.Lock()
+        self._previous = self._load()
+        self._entries = {}
+
+    def _load(self):
+        """
+        Read entries from a previous run when its key matches
+
+        Returns:
+            dict: rel_path -> {"hash", "result"}
+        """
+        try:
+            with open(self.index_path, "r", encoding="utf-8") as handle:
+                data = json.load(handle)
+        except FileNotFoundError:
+            return {}
+        except (OSError, ValueError) as exc:
+            if self.logger:
+                self.logger.warning(f"Ignoring unreadable analysis cache {self.index_path}: {exc}")
+            return {}
+        if data.get("key") != self.key:
+            if self.logger:
+                self.logger.debug("Analysis cache key changed; starting with an empty cache")
+            return {}
+        return data.get("files", {})
+
+    def get(self, rel_path, file_hash):
+        """
+        Return the cached extraction result for a file, counting the hit or miss
+
+        Args:
+            rel_path (str): Repo-relative file path
+            file_hash (str): Content hash of the file
+
+        Returns:
+            dict|None: Result in the shape produced by imports._extract_file_imports
+        """
+        with self._lock:
+            entry = self._previous.get(rel_path)
+            if entry is None or entry.get("hash") != file_hash:
+                self.misses += 1
+                return None
+            self.hits += 1
+            self._entries[rel_path] = entry
+
+        result = dict(entry["result"])
+        # JSON has no tuples; component entries are (package, component) pairs
+        result["components"] = {
+            key: [tuple(component) for component in components] for key, components in result["components"].items()
+        }
+        return result
+
+    def put(self, rel_path, file_hash, result):
+        """
+        Record a freshly extracted result
+
+        Args:
+            rel_path (str): Repo-relative file path
+            file_hash (str): Content hash of the file
+            result (dict): Extraction result
+        """
+        with self._lock:
+            self._entries[rel_path] = {"hash": file_hash, "result": result}
+
+    def save(self):
+        """
+        Write the entries seen during this run, dropping files that no longer exist
+
+        Returns:
+            bool: True when the index was written
+        """
+        try:
+            os.makedirs(self.cache_dir, exist_ok=True)
+            tmp_path = f"{self.index_path}.tmp"
+            with open(tmp_path, "w", encoding="utf-8") as handle:
+                json.dump({"key": self.key, "files": self._entries}, handle)
+            os.replace(tmp_path, self.index_path)
+            return True
+        except OSError as exc:
+            if self.logger:
+                self.logger.warning(f"Could not write analysis cache {self.index_path}: {exc}")
+            return False
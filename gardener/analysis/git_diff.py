@@ -0,0 +1,104 @@
+"""
+Restrict an analysis to the files changed since a git ref
+"""
+
+import os
+from pathlib import Path
+
+from gardener.common.input_validation import ValidationError
+from gardener.common.subprocess import SecureSubprocess, SubprocessSecurityError
+from gardener.common.utils import RepositoryError
+
+
+def _run_git(runner, args, repo_path):
+    """
+    Run a git command in the repository
+
+    Args:
+        runner (SecureSubprocess): Runner bound to the repository
+        args (list): Arguments after `git`
+        repo_path (str): Working directory
+
+    Returns:
+        subprocess.CompletedProcess
+
+    Raises:
+        RepositoryError: If git is missing or the command is rejected
+    """
+    try:
+        return runner.run(["git"] + args, cwd=repo_path, capture_output=True, check=False)
+    except FileNotFoundError:
+        raise RepositoryError("--since needs the git executable, but it was not found in PATH")
+    except (SubprocessSecurityError, ValidationError) as exc:
+        raise RepositoryError(f"Refusing to run git: {exc}")
+
+
+def check_since_ref(repo_path, ref):
+    """
+    Verify that a --since ref names a commit of the git work tree around a directory
+
+    Args:
+        repo_path (str): Absolute path to the analyzed directory
+        ref (str): Branch, tag or commit to diff against
+
+    Returns:
+        SecureSubprocess: Runner bound to the repository, for further git commands
+
+    Raises:
+        RepositoryError: If the ref is malformed or unknown, or repo_path is not in a git repository
+    """
+    if not ref or ref.startswith("-"):
+        raise RepositoryError(f"Invalid git ref for --since: {ref!r}")
+
+    runner = SecureSubprocess(allowed_root=repo_path, timeout=60)
+    result = _run_git(runner, ["rev-parse", "--is-inside-work-tree"], repo_path)
+    if result.returncode != 0 or result.stdout.strip() != "true":
+        raise RepositoryError(f"--since requires a git repository, but {repo_path} is not inside a git work tree")
+    result = _run_git(runner, ["rev-parse", "--verify", "--quiet", f"{ref}^{{commit}}"], repo_path)
+    if result.returncode != 0:
+        raise RepositoryError(f"Cannot diff against '{ref}': no such branch, tag or commit in {repo_path}")
+    return runner
+
+
+def changed_files_since(repo_path, ref, logger=None):
+    """
+    List the files changed between a ref and HEAD
+
+    Uses `git diff --name-only <ref>...HEAD`, i.e. the changes on HEAD since it
+    diverged from ref. Paths outside repo_path are ignored, and paths that no
+    longer exist in the working tree (deleted or renamed away) are reported
+    separately so callers can skip them
+
+    Args:
+        repo_path (str): Absolute path to the analyzed directory, inside a git work tree
+        ref (str): Branch, tag or commit to diff against
+        logger (Logger|None): Optional logger instance
+
+    Returns:
+        dict: {"since", "changed_files", "deleted_files"} with sorted repo-relative paths
+
+    Raises:
+        RepositoryError: If repo_path is not in a git repository or ref cannot be diffed (see check_since_ref)
+    """
+    runner = check_since_ref(repo_path, ref)
+
+    # --relative limits the diff to repo_path and reports paths relative to it
+    result = _run_git(runner, ["diff", "--name-only", "-z", "--relative", f"{ref}...HEAD", "--"], repo_path)
+    if result.returncode != 0:
+        detail = (result.stderr or "").strip().splitlines()
+        raise RepositoryError(f"Cannot diff against '{ref}': {detail[-1] if detail else 'git diff failed'}")
+
+    changed_files = []
+    deleted_files = []
+    for name in result.stdout.split("\0"):
+        if not name:
+            continue
+        rel_path = str(Path(name))
+        if os.path.isfile(os.path.join(repo_path, rel_path)):
+            changed_files.append(rel_path)
+        else:
+            deleted_files.append(rel_path)
+            if logger:
+                logger.debug(f"Skipping {rel_path}: changed since {ref} but no longer present")
+
+    return {"since": ref, "changed_files": sorted(changed_files), "deleted_files": sorted(deleted_files)}
@@ -0,0 +1,150 @@
+"""
+Deprecated modules and retracted versions among Go dependencies
+
+As with the go command, a module is deprecated by a `// Deprecated:` comment on
+the module directive of its go.mod, and a version is retracted by a `retract`
+directive in the go.mod of the module's latest version, which is fetched from
+the module proxy unless the analysis is offline
+"""
+
+import re
+
+from gardener.common.cancellation import is_cancelled
+from gardener.package_metadata.url_resolver import fetch_go_mod, fetch_go_proxy_metadata
+from gardener.treewalk.go import parse_go_mod_file
+
+_RE_SEMVER = re.compile(r"^v(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$")
+
+
+def _semver_order(version):
+    """
+    Sort key placing Go versions in semantic version order, prereleases before their release
+
+    Args:
+        version (str): Go module version, e.g. "v1.2.3" or "v0.0.0-20240101000000-abcdef123456"
+
+    Returns:
+        tuple|None: Comparable key, or None for a version that is not semver
+    """
+    match = _RE_SEMVER.match(version or "")
+    if not match:
+        return None
+    major, minor, patch, prerelease = match.groups()
+    identifiers = tuple(
+        (0, int(part), "") if part.isdigit() else (1, 0, part) for part in (prerelease or "").split(".") if part
+    )
+    return (int(major), int(minor), int(patch), 0 if prerelease else 1, identifiers)
+
+
+def find_retraction(retractions, version):
+    """
+    Return the retract directive covering a version
+
+    Args:
+        retractions (list): {"low", "high", "rationale"} entries from parse_go_mod_file
+        version (str): Module version
+
+    Returns:
+        dict|None: The first covering entry
+    """
+    key = _semver_order(version)
+    if key is None:
+        return None
+    for retraction in retractions:
+        low, high = _semver_order(retraction["low"]), _semver_order(retraction["high"])
+        if low is not None and high is not None and low <= key <= high:
+            return retraction
+    return None
+
+
+def deprecation_fields(go_mod_info, version):
+    """
+    Describe how a module version is deprecated according to its module's go.mod
+
+    Args:
+        go_mod_info (dict): {"deprecated", "retract"} as returned by parse_go_mod_file
+        version (str): Version the repository depends on
+
+    Returns:
+        dict: {"deprecated": True, "deprecation_message", ["retracted": True]}, or {} when the module
+            is not deprecated and the version not retracted
+    """
+    messages = []
+    fields = {}
+    retraction = find_retraction(go_mod_info.get("retract", []), version)
+    if retraction is not None:
+        fields["retracted"] = True
+        messages.append(f"{version} is retracted" + (f": {retraction['rationale']}" if retraction["rationale"] else ""))
+    if go_mod_info.get("deprecated"):
+        messages.append(f"module deprecated: {go_mod_info['deprecated']}")
+    if not messages:
+        return {}
+    return {"deprecated": True, "deprecation_message": "; ".join(messages), **fields}
+
+
+def _latest_go_mod(module_path, latest_version, logger):
+    """
+    Fetch the go.mod of a module's latest version from the module proxy
+
+    Args:
+        module_path (str): Go module path
+        latest_version (str): Latest version when already known, or None to ask the proxy
+        logger (Logger): Optional logger instance
+
+    Returns:
+        dict|None: {"deprecated", "retract"}, or None when the proxy had no answer
+    """
+    if not latest_version:
+        metadata, reason = fetch_go_proxy_metadata(module_path, logger)
+        if not metadata:
+            logger and logger.debug(f"No latest version of {module_path} for deprecation checks: {reason}")
+            return None
+        latest_version = metadata["latest_version"]
+    content, reason = fetch_go_mod(module_path, latest_version, logger)
+    if content is None:
+        logger and logger.debug(f"Could not fetch go.mod of {module_path}@{latest_version}: {reason}")
+        return None
+    parsed = parse_go_mod_file(content)
+    return {"deprecated": parsed["deprecated"], "retract": parsed["retract"]}
+
+
+def attach_go_deprecations(external_packages, logger=None, cancellation=None):
+    """
+    Flag Go dependencies whose module is deprecated or whose version is retracted
+
+    Modules replaced by a local directory are skipped; other replaces are checked
+    under their replacement module and version. Flagged packages get the
+    deprecation_fields
+
+    Args:
+        external_packages (dict): External packages mapping, updated in place
+        logger (Logger): Optional logger instance
+        cancellation (CancellationToken): Optional token; once cancelled no further go.mod is fetched
+
+    Returns:
+        int: Number of packages flagged
+    """
+    fetched = {}
+    flagged = 0
+    for package_name, package_info in external_packages.items():
+        if package_info.get("ecosystem") != "go" or not package_info.get("version"):
+            continue
+        replace = package_info.get("replace") or {}
+        if replace.get("local"):
+            continue
+        module_path = replace.get("path") or package_info.get("module_path") or package_name
+        version = replace.get("version") or package_info["version"]
+        if is_cancelled(cancellation):
+            break
+        if module_path not in fetched:
+            latest = None if replace else package_info.get("latest_version")
+            fetched[module_path] = _latest_go_mod(module_path, latest, logger)
+        if not fetched[module_path]:
+            continue
+
+        fields = deprecation_fields(fetched[module_path], version)
+        if fields:
+            package_info.update(fields)
+            flagged += 1
+            logger and logger.debug(f"{package_name} {version}: {fields['deprecation_message']}")
+    return flagged
@@ -0,0 +1,154 @@
+"""
+Tools invoked by `//go:generate` directives
+
+`go generate` runs code generators that never appear as imports, so they are
+recorded from the directives themselves (see parse_go_generate_directives) as
+dependencies scoped "generate". Common generators are mapped to the Go module
+or project that provides them; other commands are reported by name unresolved
+"""
+
+import re
+
+# Tool name -> (package key, ecosystem, repository URL); Go tools are keyed by the module providing
+# them, tools built outside the Go ecosystem by "generate:<tool>"
+GO_GENERATE_TOOLS = {
+    "mockgen": ("go.uber.org/mock", "go", "https://github.com/uber-go/mock"),
+    "stringer": ("golang.org/x/tools", "go", "https://github.com/golang/tools"),
+    "goyacc": ("golang.org/x/tools", "go", "https://github.com/golang/tools"),
+    "protoc-gen-go": ("google.golang.org/protobuf", "go", "https://github.com/protocolbuffers/protobuf-go"),
+    "protoc-gen-go-grpc": ("google.golang.org/grpc/cmd/protoc-gen-go-grpc", "go", "https://github.com/grpc/grpc-go"),
+    "protoc": ("generate:protoc", "go-generate", "https://github.com/protocolbuffers/protobuf"),
+    "buf": ("github.com/bufbuild/buf", "go", "https://github.com/bufbuild/buf"),
+    "mockery": ("github.com/vektra/mockery/v2", "go", "https://github.com/vektra/mockery"),
+    "moq": ("github.com/matryer/moq", "go", "https://github.com/matryer/moq"),
+    "counterfeiter": (
+        "github.com/maxbrunsfeld/counterfeiter/v6", "go", "https://github.com/maxbrunsfeld/counterfeiter"
+    ),
+    "enumer": ("github.com/dmarkham/enumer", "go", "https://github.com/dmarkham/enumer"),
+    "go-enum": ("github.com/abice/go-enum", "go", "https://github.com/abice/go-enum"),
+    "easyjson": ("github.com/mailru/easyjson", "go", "https://github.com/mailru/easyjson"),
+    "wire": ("github.com/google/wire", "go", "https://github.com/google/wire"),
+    "sqlc": ("github.com/sqlc-dev/sqlc", "go", "https://github.com/sqlc-dev/sqlc"),
+    "oapi-codegen": ("github.com/oapi-codegen/oapi-codegen/v2", "go", "https://github.com/oapi-codegen/oapi-codegen"),
+    "go-bindata": ("github.com/go-bindata/go-bindata", "go", "https://github.com/go-bindata/go-bindata"),
+    "controller-gen": ("sigs.k8s.io/controller-tools", "go", "https://github.com/kubernetes-sigs/controller-tools"),
+    "swag": ("github.com/swaggo/swag", "go", "https://github.com/swaggo/swag"),
+    "ent": ("entgo.io/ent", "go", "https://github.com/ent/ent"),
+    "gqlgen": ("github.com/99designs/gqlgen", "go", "https://github.com/99designs/gqlgen"),
+    "templ": ("github.com/a-h/templ", "go", "https://github.com/a-h/templ"),
+}
+
+# Modules providing a tool under another path; a repository requiring one of them gets it instead
+GO_GENERATE_ALTERNATIVES = {
+    "mockgen": ("github.com/golang/mock",),
+}
+
+# Shell utilities and the go command itself, which are not dependencies
+_IGNORED_COMMANDS = frozenset(
+    {"go", "gofmt", "sh", "bash", "echo", "cp", "mv", "rm", "mkdir", "cat", "sed", "awk", "touch", "env", "make"}
+)
+
+_RE_HOSTED_PACKAGE = re.compile(r"^((?:github\.com|gitlab\.com|bitbucket\.org)/[^/]+/[^/]+)")
+
+
+def go_run_module(package_path, external_packages):
+    """
+    Return the module providing a package run with `go run`
+
+    Args:
+        package_path (str): Package path, e.g. "golang.org/x/tools/cmd/stringer"
+        external_packages (dict): External packages mapping
+
+    Returns:
+        tuple: (module path, repository URL or "") where the module is the longest Go package key or
+            curated tool module enclosing package_path, else the host/owner/repo prefix of a code host
+            path, else package_path
+    """
+    curated = {key: url for key, ecosystem, url in GO_GENERATE_TOOLS.values() if ecosystem == "go"}
+    path = package_path
+    while path:
+        if external_packages.get(path, {}).get("ecosystem") == "go":
+            return path, external_packages[path].get("repository_url") or curated.get(path, "")
+        if path in curated:
+            return path, curated[path]
+        path = path.rpartition("/")[0]
+    match = _RE_HOSTED_PACKAGE.match(package_path)
+    if match:
+        return match.group(1), f"https://{match.group(1)}"
+    return package_path, ""
+
+
+def _tool_package(directive, external_packages):
+    """
+    Map one generate directive to the package key and metadata of the tool it runs
+
+    Args:
+        directive (dict): Evidence entry {"generate", "command", "line", ["version"]}
+        external_packages (dict): External packages mapping
+
+    Returns:
+        tuple|None: (package key, package info for a newly detected package), or None for commands
+            that are not dependencies
+    """
+    tool = directive["generate"]
+    if tool in _IGNORED_COMMANDS:
+        return None
+    if "/" in tool:
+        module_path, repository_url = go_run_module(tool, external_packages)
+        info = {"ecosystem": "go", "scope": "generate", "repository_url": repository_url}
+        if directive.get("version"):
+            info["version"] = directive["version"]
+        confidence = "high" if module_path in external_packages else "medium"
+        info["resolution"] = {"source": "go-run", "confidence": confidence}
+        return module_path, info
+    if tool not in GO_GENERATE_TOOLS:
+        info = {"ecosystem": "go-generate", "scope": "generate", "package": tool, "repository_url": ""}
+        info["resolution"] = {"reason": "unknown-generate-tool"}
+        return f"generate:{tool}", info
+    for alternative in GO_GENERATE_ALTERNATIVES.get(tool, ()):
+        if alternative in external_packages:
+            return alternative, None
+    package_key, ecosystem, repository_url = GO_GENERATE_TOOLS[tool]
+    info = {"ecosystem": ecosystem, "scope": "generate", "repository_url": repository_url}
+    if ecosystem == "go-generate":
+        info["package"] = tool
+    info["resolution"] = {"source": "generate-tools", "confidence": "medium"}
+    return package_key, info
+
+
+def attach_go_generate_tools(external_packages, file_import_evidence):
+    """
+    Record the tools run by `//go:generate` directives as dependencies
+
+    Every package gets a `generate` list of {"file", "line", "command"} sorted by
+    file and line. Packages already detected, e.g. a tool module required in
+    go.mod, keep their metadata and are scoped "generate" only when no import
+    scoped them; tools not yet known are added with scope "generate", a version
+    from `go run <package>@<version>` and the repository of the curated mapping
+
+    Args:
+        external_packages (dict): External packages mapping, updated in place
+        file_import_evidence (dict): Repo-relative file path -> evidence entries
+
+    Returns:
+        list: Keys of the packages added
+    """
+    added = []
+    for rel_path in sorted(file_import_evidence):
+        for entry in file_import_evidence[rel_path]:
+            if "generate" not in entry:
+                continue
+            mapped = _tool_package(entry, external_packages)
+            if mapped is None:
+                continue
+            package_key, info = mapped
+            if package_key not in external_packages:
+                external_packages[package_key] = info
+                added.append(package_key)
+            package_info = external_packages[package_key]
+            package_info.setdefault("scope", "generate")
+            package_info.setdefault("generate", []).append(
+                {"file": rel_path, "line": entry["line"], "command": entry["command"]}
+            )
+    return added
+
@@ -0,0 +1,90 @@
+"""
+Imports of Go `internal` packages from outside the tree allowed to use them
+
+The go command only lets code rooted at the parent of an `internal` directory
+import the packages below it: `example.com/app/a/internal/x` is importable from
+`example.com/app/a` and its subpackages, and nowhere else. Importing another
+first-party package's internals is an architectural violation the build
+rejects; importing an external module's internals couples the repository
+to code its authors may change at will (and only compiles when vendored or
+patched). Both are found from the package-level import graph of Go files
+and reported as warnings
+"""
+
+from gardener.analysis.import_graph import NODE_EXTERNAL, NODE_STDLIB
+
+
+def internal_import_root(import_path):
+    """
+    Return the package tree that may import a path, when the path is internal
+
+    With several `internal` elements the deepest applies, as it restricts the
+    most
+
+    Args:
+        import_path (str): Go import path
+
+    Returns:
+        str|None: Parent of the last `internal` element ("" for standard-library
+            paths such as `internal/cpu`), or None for paths without one
+    """
+    elements = import_path.split("/")
+    if "internal" not in elements:
+        return None
+    last = len(elements) - 1 - elements[::-1].index("internal")
+    return "/".join(elements[:last])
+
+
+def _may_import(importer, root):
+    if not root:
+        return False
+    return importer == root or importer.startswith(f"{root}/")
+
+
+def find_go_internal_imports(import_graph):
+    """
+    Find Go imports of internal packages from outside the tree that may use them
+
+    Only edges with Go importing files and a module-qualified importer are
+    checked; files no go.mod owns are named by directory, which the rule cannot
+    be applied to
+
+    Args:
+        import_graph (dict): Output of build_import_graph
+
+    Returns:
+        list: Warnings sorted by import then importer, each {"warning": "internal-import", "import",
+            "importer", "allowed_under", "target", "external", "files", "message"}; target is the
+            imported node (the module for external imports), external tells whether it is outside
+            the repository and allowed_under is the tree that may import it ("" for the standard library)
+    """
+    nodes = {node["id"]: node for node in import_graph.get("nodes", [])}
+    warnings = []
+    for edge in import_graph.get("edges", []):
+        files = [path for path in edge.get("files", []) if path.endswith(".go")]
+        source = nodes.get(edge["source"], {})
+        if not files or source.get("path") in (None, edge["source"]):
+            continue
+        import_path = edge.get("import", "")
+        root = internal_import_root(import_path)
+        if root is None or _may_import(edge["source"], root):
+            continue
+        kind = nodes.get(edge["target"], {}).get("kind")
+        if kind == NODE_STDLIB:
+            owner = "the standard library"
+        else:
+            owner = root
+        warnings.append(
+            {
+                "warning": "internal-import",
+                "import": import_path,
+                "importer": edge["source"],
+                "allowed_under": root,
+                "target": edge["target"],
+                "external": kind in (NODE_EXTERNAL, NODE_STDLIB),
+                "files": files,
+                "message": f"{edge['source']} imports {import_path}, which is internal to {owner} "
+                f"({', '.join(files)})",
+            }
+        )
+    return sorted(warnings, key=lambda warning: (warning["import"], warning["importer"]))
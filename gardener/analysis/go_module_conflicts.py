@@ -0,0 +1,154 @@
+"""
+Conflicting Go module declarations within a repository
+
+Two go.mod files declaring the same module path (for instance two directories
+a go.work `use`s), or a replace directive redirecting a module the repository
+itself declares, make the go command silently pick one of them or fail with
+errors far from the cause. Both are reported as warnings. Modules of the same
+repository requiring different versions of a dependency are listed too: they
+build against different code, and a go.work build of all of them upgrades the
+others to the highest. As with the go command, go.mod files under `testdata`
+or `_`-prefixed directories do not declare modules of the repository
+"""
+
+import os
+
+from gardener.common.file_helpers import to_posix_path
+from gardener.package_metadata.url_resolver import go_semver_key
+
+
+def _relative(path, repo_path):
+    return to_posix_path(os.path.relpath(path, repo_path))
+
+
+def _is_ignored_module_dir(rel_dir):
+    """
+    Return whether the go command ignores a directory when looking for modules
+
+    Args:
+        rel_dir (str): Repo-relative POSIX directory
+
+    Returns:
+        bool
+    """
+    return any(part == "testdata" or part.startswith("_") for part in rel_dir.split("/"))
+
+
+def _replace_target(target):
+    return f"{target['path']} {target['version']}" if target.get("version") else target["path"]
+
+
+def find_go_module_conflicts(go_mod_files, replace_directives, workspace_uses, repo_path):
+    """
+    Find module paths declared more than once and replace directives shadowing repository modules
+
+    A local replace pointing at the directory that declares the module is how
+    modules of a monorepo refer to each other and is not a conflict
+
+    Args:
+        go_mod_files (dict): Absolute go.mod path -> declared module path
+        replace_directives (dict): Absolute go.mod or go.work path -> replace map (see parse_go_mod_file)
+        workspace_uses (dict): Absolute go.work path -> [(absolute use directory, declared module path)]
+        repo_path (str): Absolute repository path
+
+    Returns:
+        list: Warnings sorted by module, each {"warning", "module", "go_mod_files", "message"}, go_mod_files
+            being the repo-relative go.mod files declaring the module. "duplicate-module" warnings add
+            "go_work", the go.work files using more than one of those directories, when there are any;
+            "replace-shadows-module" warnings add the "replace" target ({"path", "version", "local"}) and
+            "declared_in", the go.mod or go.work holding the directive
+    """
+    declarations = {}
+    for go_mod_path, module_path in go_mod_files.items():
+        if not _is_ignored_module_dir(_relative(os.path.dirname(go_mod_path), repo_path)):
+            declarations.setdefault(module_path, set()).add(os.path.normpath(go_mod_path))
+    work_files = {}
+    for go_work_path, uses in workspace_uses.items():
+        used_dirs = {}
+        for module_dir, module_path in uses:
+            used_dirs.setdefault(module_path, set()).add(module_dir)
+            declarations.setdefault(module_path, set()).add(os.path.normpath(os.path.join(module_dir, "go.mod")))
+        for module_path, module_dirs in used_dirs.items():
+            if len(module_dirs) > 1:
+                work_files.setdefault(module_path, []).append(_relative(go_work_path, repo_path))
+
+    warnings = []
+    for module_path, go_mod_paths in declarations.items():
+        if len(go_mod_paths) < 2:
+            continue
+        files = sorted(_relative(path, repo_path) for path in go_mod_paths)
+        warning = {
+            "warning": "duplicate-module",
+            "module": module_path,
+            "go_mod_files": files,
+            "message": f"Go module {module_path} is declared by {len(files)} go.mod files: {', '.join(files)}",
+        }
+        if module_path in work_files:
+            warning["go_work"] = sorted(work_files[module_path])
+        warnings.append(warning)
+
+    for manifest_path, replaces in replace_directives.items():
+        for source, target in replaces.items():
+            module_path = source.split("@", 1)[0]
+            go_mod_paths = declarations.get(module_path)
+            if not go_mod_paths:
+                continue
+            if target.get("local"):
+                target_dir = os.path.normpath(os.path.join(os.path.dirname(manifest_path), target["path"]))
+                if os.path.join(target_dir, "go.mod") in go_mod_paths:
+                    continue
+            files = sorted(_relative(path, repo_path) for path in go_mod_paths)
+            declared_in = _relative(manifest_path, repo_path)
+            warnings.append(
+                {
+                    "warning": "replace-shadows-module",
+                    "module": module_path,
+                    "go_mod_files": files,
+                    "replace": dict(target),
+                    "declared_in": declared_in,
+                    "message": (
+                        f"{declared_in} replaces Go module {module_path}, declared by {', '.join(files)}, "
+                        f"with {_replace_target(target)}"
+                    ),
+                }
+            )
+
+    warnings.sort(key=lambda warning: (warning["module"], warning["warning"], warning.get("declared_in", "")))
+    return warnings
+
+
+def find_go_version_conflicts(module_requirements, go_modules, local_modules=()):
+    """
+    Find external dependencies the repository's Go modules require at different versions
+
+    Args:
+        module_requirements (dict): Declared module path -> {required module path: version} from its go.mod
+        go_modules (dict): Module path -> repo-relative POSIX directory of its go.mod
+        local_modules (iterable): Module paths that are code of the repository rather than dependencies
+
+    Returns:
+        list: Conflicts sorted by dependency, each {"dependency", "versions": {requiring module: version},
+            "selected"}, selected being the highest of the versions, which minimal version selection
+            picks when the modules build together
+    """
+    local_modules = set(go_modules) | set(local_modules)
+    requiring = {}
+    for module_path, requirements in module_requirements.items():
+        if module_path not in go_modules or _is_ignored_module_dir(go_modules[module_path]):
+            continue
+        for dependency, version in requirements.items():
+            if dependency not in local_modules:
+                requiring.setdefault(dependency, {})[module_path] = version
+    conflicts = []
+    for dependency in sorted(requiring):
+        versions = requiring[dependency]
+        if len(set(versions.values())) < 2:
+            continue
+        conflicts.append(
+            {
+                "dependency": dependency,
+                "versions": dict(sorted(versions.items())),
+                "selected": max(versions.values(), key=go_semver_key),
+            }
+        )
+    return conflicts
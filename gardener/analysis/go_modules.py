@@ -0,0 +1,90 @@
+"""
+Transitive Go module resolution over the go.mod require graph
+
+Starting from the requires of the repository's go.mod, each module version's
+own go.mod is fetched from the module proxy and its requires are followed up
+to a bounded depth. As with minimal version selection, the highest version
+required anywhere in the graph is the one reported for a module
+"""
+
+from collections import deque
+
+from gardener.package_metadata.url_resolver import fetch_go_mod, go_semver_key
+from gardener.treewalk.go import find_go_replacement, parse_go_mod_file
+
+
+def _fetch_target(module_path, version, replacements):
+    """
+    Return the module version whose go.mod describes a require, honoring replaces
+
+    Args:
+        module_path (str): Required module path
+        version (str): Required version
+        replacements (dict): replace map in the shape of parse_go_mod_file
+
+    Returns:
+        tuple: (module_path, version), or None when the module is replaced by a local directory
+    """
+    replacement = find_go_replacement(module_path, version, replacements)
+    if not replacement:
+        return module_path, version
+    if replacement.get("local"):
+        return None
+    return replacement["path"], replacement.get("version") or version
+
+
+def resolve_go_transitive(
+    root_requires, max_depth, replacements=None, root_module=None, fetch_mod=None, offline=False, logger=None
+):
+    """
+    Walk the require graph breadth-first from the root go.mod
+
+    Each distinct module version is fetched at most once
+
+    Args:
+        root_requires (dict): Module path -> version required by the root go.mod (depth 1)
+        max_depth (int): Deepest require level to report; go.mod files are fetched for levels below it
+        replacements (dict): Root go.mod replace map; replaces in dependencies are ignored, as by the go command
+        root_module (str): The repository's own module path, never reported as a dependency
+        fetch_mod (callable): fetch_mod(module_path, version) -> (go.mod text or None, reason);
+            defaults to the module proxy
+        offline (bool): Fetch nothing; modules that need their go.mod get fetch_reason "offline-skipped"
+        logger (Logger): Optional logger instance
+
+    Returns:
+        dict: module path -> {"version", "depth", "required_by": [module paths]} plus "fetch_reason"
+            when the module's go.mod could not be fetched
+    """
+    replacements = replacements or {}
+    fetch_mod = fetch_mod or (lambda module_path, version: fetch_go_mod(module_path, version, logger))
+    modules = {}
+    expanded = set()
+    queue = deque((module_path, version, 1, None) for module_path, version in sorted(root_requires.items()))
+
+    while queue:
+        module_path, version, depth, parent = queue.popleft()
+        node = modules.setdefault(module_path, {"version": version, "depth": depth, "required_by": []})
+        if parent and parent not in node["required_by"]:
+            node["required_by"].append(parent)
+        if go_semver_key(version) > go_semver_key(node["version"]):
+            node["version"] = version
+
+        if depth >= max_depth or (module_path, version) in expanded:
+            continue
+        expanded.add((module_path, version))
+        target = _fetch_target(module_path, version, replacements)
+        if target is None:
+            continue
+        content, reason = (None, "offline-skipped") if offline else fetch_mod(*target)
+        if content is None:
+            node["fetch_reason"] = reason
+            logger and logger.debug(f"Could not fetch go.mod for {module_path}@{version}: {reason}")
+            continue
+        for child_path, child_version in sorted(parse_go_mod_file(content)["require"].items()):
+            if child_path == root_module:
+                continue
+            queue.append((child_path, child_version, depth + 1, module_path))
+
+    for node in modules.values():
+        node["required_by"].sort()
+    return modules
@@ -0,0 +1,109 @@
+"""
+Go requires that disagree with the imports of the repository's source
+
+go.mod marks a require `// indirect` when no package of the module imports
+it. A module marked indirect that nothing first-party imports, and that no
+module reachable from those imports requires, is a candidate for removal by
+`go mod tidy`; a module marked indirect that first-party files do import is
+mislabeled. The mislabeling, and the unimported indirect requires, are found
+from the import graph alone. Ruling out candidates some other dependency
+still needs takes the go.mod of every required module: since Go 1.17 a go.mod
+lists every module its build needs, so the edges between its own requires
+(see resolve_go_transitive with max_depth 2) are enough
+"""
+
+from collections import deque
+
+
+def _is_used(package_info):
+    # `//go:generate go run <tool>` and a Makefile's `go run <tool>` need the tool's require like an import does
+    return bool(package_info.get("seen_in") or package_info.get("generate") or package_info.get("make"))
+
+
+def _reachable(used, require_graph):
+    """
+    Return the modules some used module requires, directly or through other modules
+
+    Args:
+        used (iterable): Module paths first-party code imports
+        require_graph (dict): Module path -> {"required_by": [module paths], ...}
+
+    Returns:
+        set: Module paths, including `used`
+    """
+    requires = {}
+    for module_path, node in require_graph.items():
+        for parent in node.get("required_by", []):
+            requires.setdefault(parent, set()).add(module_path)
+    reachable = set(used)
+    queue = deque(reachable)
+    while queue:
+        for child in requires.get(queue.popleft(), ()):
+            if child not in reachable:
+                reachable.add(child)
+                queue.append(child)
+    return reachable
+
+
+def find_go_require_mismatches(external_packages, require_graph=None):
+    """
+    Compare the `// indirect` flags of the repository's go.mod requires with its imports
+
+    Only modules a repository go.mod requires (those with `go_modules`) are checked
+
+    Args:
+        external_packages (dict): External packages with import provenance merged (seen_in)
+        require_graph (dict): Optional output of resolve_go_transitive for the same requires;
+            without it every unimported indirect require is a candidate
+
+    Returns:
+        tuple: (candidates, warnings). candidates are sorted by module, each {"module", "version",
+            "go_modules", "verified"} plus "required_by" (the requires listing it, none of them used)
+            when the graph names any; verified tells whether the go.mod of every module reachable from
+            the imports was read, so that no dependency can still need it. warnings are
+            {"warning": "imported-indirect", "module", "go_modules", "files", "message"} for modules
+            first-party files import although go.mod marks them indirect
+    """
+    requires = {
+        name: info
+        for name, info in external_packages.items()
+        if info.get("ecosystem") == "go" and info.get("go_modules")
+    }
+    used = {name for name, info in requires.items() if _is_used(info)}
+    reachable = _reachable(used, require_graph or {})
+    verified = require_graph is not None and not any(
+        require_graph[name].get("fetch_reason") for name in reachable if name in require_graph
+    )
+
+    candidates = []
+    warnings = []
+    for name in sorted(requires):
+        info = requires[name]
+        if info.get("direct") is not False:
+            continue
+        go_modules = sorted(info["go_modules"])
+        if name in used:
+            entries = info.get("seen_in", []) + info.get("generate", []) + info.get("make", [])
+            files = sorted({entry["file"] for entry in entries})
+            warnings.append(
+                {
+                    "warning": "imported-indirect",
+                    "module": name,
+                    "go_modules": go_modules,
+                    "files": files,
+                    "message": f"go.mod marks {name} as indirect, but it is used by {', '.join(files)}; "
+                    "go mod tidy drops the // indirect comment",
+                }
+            )
+        elif name not in reachable:
+            candidate = {
+                "module": name,
+                "version": info.get("version", ""),
+                "go_modules": go_modules,
+                "verified": verified,
+            }
+            required_by = (require_graph or {}).get(name, {}).get("required_by")
+            if required_by:
+                candidate["required_by"] = list(required_by)
+            candidates.append(candidate)
+    return candidates, warnings
@@ -0,0 +1,124 @@
+"""
+Verification of the commits Go pseudo-versions pin
+
+A pseudo-version (`v0.0.0-20230615120000-abc123def456`) names a commit by its
+first 12 hex digits instead of a tag. For GitHub-hosted modules the commit is
+looked up through the GitHub API, which gives its full SHA and date; a prefix
+the repository has no commit for means the version was not built from that
+repository as it stands, a supply-chain red flag. Commits do not change, so the
+ones found are kept in the resolution cache without expiry
+"""
+
+from concurrent.futures import ThreadPoolExecutor
+
+from gardener.analysis.pin_kinds import parse_go_pseudo_version
+from gardener.common.cancellation import is_cancelled
+from gardener.package_metadata.url_resolver import (
+    close_exited_connections,
+    fetch_github_commit,
+    repository_coordinates,
+    resolver_concurrency,
+)
+
+
+def _pinned_commit(package_info):
+    """
+    Return the GitHub repository and commit prefix of the pseudo-version a Go package record pins
+
+    Non-local replaces are followed: the replacement version names a commit of the
+    resolved (replacement) repository
+
+    Returns:
+        tuple|None: (repository URL, commit prefix), or None when the package pins no
+            pseudo-version or is not hosted on github.com
+    """
+    if package_info.get("ecosystem") != "go":
+        return None
+    replace = package_info.get("replace") or {}
+    if replace.get("local"):
+        return None
+    pseudo = parse_go_pseudo_version(replace.get("version") or package_info.get("version"))
+    if not pseudo:
+        return None
+    coordinates = repository_coordinates(package_info.get("repository_url"))
+    if coordinates["host"] != "github.com":
+        return None
+    return f"https://github.com/{coordinates['owner']}/{coordinates['repo']}", pseudo["commit"]
+
+
+def attach_go_pseudo_commits(external_packages, logger=None, offline=False, cancellation=None, resolution_cache=None):
+    """
+    Verify the commit of each Go pseudo-version against the module's GitHub repository
+
+    Packages whose commit was found get `commit_sha`, `commit_date` and `commit_verified:
+    true`; those whose repository has no such commit get `commit_verified: false` and
+    `resolution.commit_reason: "commit-not-found"`. Other lookups that fail leave
+    `commit_verified` out and record `resolution.commit_reason` ("offline-skipped",
+    "cancelled", or a reason from fetch_github_commit). Packages not hosted on GitHub
+    are skipped. Each repository commit is looked up once, on up to
+    resolver_concurrency() worker threads
+
+    Args:
+        external_packages (dict): External packages mapping with repository URLs resolved, updated in place
+        logger (Logger): Optional logger instance
+        offline (bool): Only use cached commits; record "offline-skipped" for the others
+        cancellation (CancellationToken): Optional token; once cancelled, commits not yet looked up
+            get the reason "cancelled"
+        resolution_cache (ResolutionCache): Optional cache of commits from earlier runs
+
+    Returns:
+        list: Sorted names of the packages whose pinned commit the repository does not have
+    """
+    wanted = {}
+    for name, package_info in external_packages.items():
+        pinned = _pinned_commit(package_info)
+        if pinned:
+            wanted.setdefault(pinned, []).append(name)
+    if not wanted:
+        return []
+
+    found = {}
+    pending = []
+    for repo_url, revision in wanted:
+        cached = resolution_cache.lookup_commit(repo_url, revision) if resolution_cache else None
+        if cached:
+            found[(repo_url, revision)] = (cached, None)
+        elif offline:
+            found[(repo_url, revision)] = (None, "offline-skipped")
+        else:
+            pending.append((repo_url, revision))
+
+    def _fetch(key):
+        if is_cancelled(cancellation):
+            return None, "cancelled"
+        return fetch_github_commit(*key, logger=logger)
+
+    if pending:
+        workers = min(resolver_concurrency(), len(pending))
+        if workers <= 1:
+            results = [_fetch(key) for key in pending]
+        else:
+            with ThreadPoolExecutor(max_workers=workers, thread_name_prefix="gardener-commit") as executor:
+                results = list(executor.map(_fetch, pending))
+            close_exited_connections()
+        for key, (commit, reason) in zip(pending, results):
+            found[key] = (commit, reason)
+            if commit and resolution_cache is not None:
+                resolution_cache.store_commit(*key, commit)
+
+    missing = []
+    for key, names in wanted.items():
+        commit, reason = found[key]
+        for name in names:
+            package_info = external_packages[name]
+            if commit:
+                package_info["commit_sha"] = commit["sha"]
+                if commit.get("date"):
+                    package_info["commit_date"] = commit["date"]
+                package_info["commit_verified"] = True
+                continue
+            package_info.setdefault("resolution", {})["commit_reason"] = reason
+            if reason == "commit-not-found":
+                package_info["commit_verified"] = False
+                missing.append(name)
+    return sorted(missing)
@@ -0,0 +1,156 @@
+"""
+Drift between a Go module's vendor directory and its go.mod and go.sum
+
+`go mod vendor` records every module it copies in vendor/modules.txt, with the
+version and replacement go.mod selected and an `## explicit` mark on each
+module go.mod requires. Hand edits of vendor/ (or of go.mod without
+re-vendoring) leave the two disagreeing, and builds with `-mod=vendor` then use
+code go.mod does not describe. Each disagreement is reported as a
+"vendor-drift" warning, as is a vendored version go.sum holds no hash for
+"""
+
+import os
+
+from gardener.common.file_helpers import to_posix_path
+from gardener.treewalk.go import find_go_replacement
+
+# Values of the "drift" field, in report order for one module
+DRIFT_KINDS = ("version", "replace", "not-vendored", "not-required", "go-sum")
+
+
+def _relative(path, repo_path):
+    return to_posix_path(os.path.relpath(path, repo_path))
+
+
+def _replacement(replace):
+    """
+    Return the comparable (path, version) of a replacement, or None
+    """
+    if not replace:
+        return None
+    return replace["path"], replace.get("version") or ""
+
+
+def _describe(replace):
+    if not replace:
+        return "no replacement"
+    return f"{replace['path']} {replace['version']}" if replace.get("version") else replace["path"]
+
+
+def _module_drift(module_path, required, vendored, replaces, explicit_marks):
+    """
+    Compare one module's go.mod requirement with its modules.txt entry
+
+    Returns:
+        list: (drift kind, extra warning fields, message tail) tuples
+    """
+    drifts = []
+    if required is not None and vendored is not None and vendored["version"]:
+        if vendored["version"] != required:
+            drifts.append(
+                (
+                    "version",
+                    {"go_mod_version": required, "vendored_version": vendored["version"]},
+                    f"go.mod requires {required} but {vendored['version']} is vendored",
+                )
+            )
+        wanted = find_go_replacement(module_path, required, replaces)
+        if _replacement(wanted) != _replacement(vendored["replace"]):
+            drifts.append(
+                (
+                    "replace",
+                    {"go_mod_replace": dict(wanted) if wanted else None, "vendored_replace": vendored["replace"]},
+                    f"go.mod replaces it with {_describe(wanted)} but vendor/ has {_describe(vendored['replace'])}",
+                )
+            )
+    if explicit_marks:
+        if required is not None and (vendored is None or not vendored["explicit"]):
+            drifts.append(
+                ("not-vendored", {"go_mod_version": required}, f"go.mod requires {required} but it is not vendored")
+            )
+        elif required is None and vendored is not None and vendored["explicit"]:
+            drifts.append(
+                (
+                    "not-required",
+                    {"vendored_version": vendored["version"]},
+                    f"vendor/modules.txt marks {vendored['version'] or 'it'} explicit but go.mod does not require it",
+                )
+            )
+    return drifts
+
+
+def _go_sum_drift(module_path, vendored, go_sum):
+    """
+    Check that go.sum hashes the code of a vendored module version
+
+    Returns:
+        tuple|None: (drift kind, extra warning fields, message tail)
+    """
+    replace = vendored["replace"]
+    if replace and replace["local"]:
+        return None
+    sum_path, version = (replace["path"], replace["version"]) if replace else (module_path, vendored["version"])
+    if not version:
+        return None
+    hashed = sorted(v for v, entry in go_sum.get(sum_path, {}).items() if entry["zip"])
+    if version in hashed:
+        return None
+    listed = f"only {', '.join(hashed)}" if hashed else "no version"
+    return (
+        "go-sum",
+        {"vendored_version": version, "go_sum_versions": hashed},
+        f"{version} is vendored but go.sum hashes {listed} of {sum_path}",
+    )
+
+
+def find_go_vendor_drift(
+    vendor_modules, go_mod_files, module_requirements, replace_directives, go_sum_modules, repo_path
+):
+    """
+    Compare each vendor/modules.txt with the go.mod and go.sum of its module
+
+    Modules.txt files written before Go 1.14 carry no `## explicit` marks; for
+    them only the versions and replacements of vendored modules are compared
+
+    Args:
+        vendor_modules (dict): Absolute modules.txt path -> its modules (see parse_vendor_modules_txt)
+        go_mod_files (dict): Absolute go.mod path -> declared module path
+        module_requirements (dict): Declared module path -> {required module path: version} from its go.mod
+        replace_directives (dict): Absolute go.mod or go.work path -> replace map (see parse_go_mod_file)
+        go_sum_modules (dict): Absolute go.sum path -> module versions (see parse_go_sum_modules)
+        repo_path (str): Absolute repository path
+
+    Returns:
+        list: Warnings sorted by modules.txt, module and DRIFT_KINDS, each {"warning": "vendor-drift",
+            "drift", "module", "modules_txt", "go_mod", "message"} plus "go_mod_version",
+            "vendored_version", "go_mod_replace" and "vendored_replace", or "go_sum_versions" (the versions
+            go.sum hashes the code of) as the drift involves them; "go_mod" is None without a go.mod
+    """
+    warnings = []
+    for modules_txt in sorted(vendor_modules):
+        vendored_modules = vendor_modules[modules_txt]
+        module_dir = os.path.dirname(os.path.dirname(modules_txt))
+        go_mod = os.path.join(module_dir, "go.mod")
+        declared = go_mod_files.get(go_mod)
+        requirements = module_requirements.get(declared, {}) if declared else {}
+        replaces = replace_directives.get(go_mod, {})
+        go_sum = go_sum_modules.get(os.path.join(module_dir, "go.sum"))
+        explicit_marks = declared is not None and any(entry["explicit"] for entry in vendored_modules.values())
+        base = {
+            "warning": "vendor-drift",
+            "modules_txt": _relative(modules_txt, repo_path),
+            "go_mod": _relative(go_mod, repo_path) if declared else None,
+        }
+        for module_path in sorted(set(requirements) | set(vendored_modules)):
+            vendored = vendored_modules.get(module_path)
+            drifts = _module_drift(module_path, requirements.get(module_path), vendored, replaces, explicit_marks)
+            if go_sum is not None and vendored is not None:
+                drift = _go_sum_drift(module_path, vendored, go_sum)
+                if drift:
+                    drifts.append(drift)
+            for kind, fields, message in drifts:
+                warnings.append(
+                    dict(base, drift=kind, module=module_path, **fields, message=f"{module_path}: {message}")
+                )
+    warnings.sort(key=lambda w: (w["modules_txt"], w["module"], DRIFT_KINDS.index(w["drift"])))
+    return warnings
@@ -0,0 +1,131 @@
+"""
+Publish time and age of the Go module versions a repository depends on
+
+The module proxy's `@v/<version>.info` endpoint reports when each version was
+tagged (or, for a pseudo-version, committed). A published version never
+changes, so its time is kept in the resolution cache without expiry and is
+reused even when the analysis is offline
+"""
+
+import time
+from concurrent.futures import ThreadPoolExecutor
+from datetime import datetime, timezone
+
+from gardener.common.cancellation import is_cancelled
+from gardener.package_metadata.url_resolver import close_exited_connections, fetch_go_version_info, resolver_concurrency
+
+SECONDS_PER_DAY = 86400
+
+
+def version_age_days(published_at, now):
+    """
+    Return the whole days between a version's publish time and the analysis
+
+    Args:
+        published_at (str): RFC 3339 timestamp, e.g. "2021-03-01T10:00:00Z"
+        now (float): Analysis time in epoch seconds
+
+    Returns:
+        int|None: Days, 0 for a timestamp in the future, or None when it cannot be parsed
+    """
+    try:
+        published = datetime.fromisoformat(published_at.replace("Z", "+00:00"))
+    except (AttributeError, ValueError):
+        return None
+    if published.tzinfo is None:
+        published = published.replace(tzinfo=timezone.utc)
+    return max(0, int((now - published.timestamp()) // SECONDS_PER_DAY))
+
+
+def _required_version(package_name, package_info):
+    """
+    Return the module version a Go package record pins, following non-local replaces
+
+    Returns:
+        tuple|None: (module_path, version), or None when nothing is pinned or the module is replaced locally
+    """
+    if package_info.get("ecosystem") != "go" or not package_info.get("version"):
+        return None
+    replace = package_info.get("replace") or {}
+    if replace.get("local"):
+        return None
+    module_path = replace.get("path") or package_info.get("module_path") or package_name
+    return module_path, replace.get("version") or package_info["version"]
+
+
+def attach_go_version_times(
+    external_packages, logger=None, offline=False, cancellation=None, resolution_cache=None, now=None
+):
+    """
+    Record when each pinned Go module version was published and how old it is
+
+    Packages get `version_published_at` and `version_age_days`; those whose time
+    could not be found get `resolution.version_time_reason` ("offline-skipped",
+    "cancelled", or a reason from fetch_go_version_info). Each module version is
+    looked up once, on up to resolver_concurrency() worker threads sharing the
+    request slots
+
+    Args:
+        external_packages (dict): External packages mapping, updated in place
+        logger (Logger): Optional logger instance
+        offline (bool): Only use cached times; record "offline-skipped" for the others
+        cancellation (CancellationToken): Optional token; once cancelled, versions not yet looked up
+            get the reason "cancelled"
+        resolution_cache (ResolutionCache): Optional cache of publish times from earlier runs
+        now (float): Analysis time in epoch seconds (default: time.time())
+
+    Returns:
+        int: Number of packages given a publish time
+    """
+    now = time.time() if now is None else now
+    wanted = {}
+    for package_name, package_info in external_packages.items():
+        required = _required_version(package_name, package_info)
+        if required:
+            wanted.setdefault(required, []).append(package_info)
+    if not wanted:
+        return 0
+
+    found = {}
+    pending = []
+    for module_path, version in wanted:
+        cached = resolution_cache.lookup_version_time("go", module_path, version) if resolution_cache else None
+        if cached:
+            found[(module_path, version)] = (cached, None)
+        elif offline:
+            found[(module_path, version)] = (None, "offline-skipped")
+        else:
+            pending.append((module_path, version))
+
+    def _fetch(required):
+        if is_cancelled(cancellation):
+            return None, "cancelled"
+        info, reason = fetch_go_version_info(*required, logger=logger)
+        return (info["published_at"], None) if info else (None, reason)
+
+    if pending:
+        workers = min(resolver_concurrency(), len(pending))
+        if workers <= 1:
+            results = [_fetch(required) for required in pending]
+        else:
+            with ThreadPoolExecutor(max_workers=workers, thread_name_prefix="gardener-version-time") as executor:
+                results = list(executor.map(_fetch, pending))
+            close_exited_connections()
+        for (module_path, version), (published_at, reason) in zip(pending, results):
+            found[(module_path, version)] = (published_at, reason)
+            if published_at and resolution_cache is not None:
+                resolution_cache.store_version_time("go", module_path, version, published_at)
+
+    dated = 0
+    for required, packages in wanted.items():
+        published_at, reason = found[required]
+        for package_info in packages:
+            if not published_at:
+                package_info.setdefault("resolution", {})["version_time_reason"] = reason
+                continue
+            package_info["version_published_at"] = published_at
+            age = version_age_days(published_at, now)
+            if age is not None:
+                package_info["version_age_days"] = age
+            dated += 1
+    return dated
@@ -9,6 +9,7 @@ from gardener.common.language_detection import filename_to_lang
 
 from gardener.analysis.centrality import CentralityCalculator
 from gardener.common.defaults import GraphAnalysisConfig as cfg
+from gardener.common.go_stdlib import is_go_stdlib
 
 
 class DependencyGraphBuilder:
@@ -54,6 +55,7 @@ class DependencyGraphBuilder:
         self.local_imports_map = {}
         self.source_files = {}
         self.file_imports = {}
+        self.file_package_imports = {}  # (file, package node) -> import names that produced the edge
         self.centrality_calculator = CentralityCalculator(logger=logger)
 
         # Initialize instance-level edge weights from configuration so CLI overrides apply
@@ -92,6 +94,7 @@ class DependencyGraphBuilder:
         self.file_imports = file_imports
         self.file_package_components = file_package_components
         self.local_imports_map = local_imports_map
+        self.file_package_imports = {}
 
         # Build import-to-distribution map (logs ambiguous warnings identically)
         self.import_to_dist = self._build_import_to_dist_map(external_packages)
@@ -221,7 +224,7 @@ class DependencyGraphBuilder:
         Returns:
             Language name string inferred from filename or 'unknown'
         """
-        lang_result = filename_to_lang(rel_path)
+        lang_result = filename_to_lang(rel_path) or self.source_files.get(rel_path, {}).get("language")
         language = lang_result if lang_result else "unknown"
         if rel_path.endswith(".mjs"):
             language = "javascript"
@@ -404,9 +407,7 @@ class DependencyGraphBuilder:
         ecosystem = "unknown"
         # Language-aware stdlib classification
         if file_lang == "go":
-            # Only mark Go stdlib when first path segment has no dot
-            first_seg = package_name.split("/", 1)[0]
-            if "." not in first_seg:
+            if is_go_stdlib(package_name):
                 ecosystem = self._stdlib_ecosystem_for_language(file_lang)
         elif file_lang == "python":
             ecosystem = self._stdlib_ecosystem_for_language(file_lang)
@@ -481,6 +482,7 @@ class DependencyGraphBuilder:
         """
         Add file -> imports_package -> package edge using internal constants
         """
+        self.file_package_imports.setdefault((file_path, dist_node_for_edge), set()).add(package_name)
         edge_attrs = {"ident": package_name}
         if package_name in self._ambiguous_choices:
             edge_attrs["ambiguity_resolution"] = "lexicographic"
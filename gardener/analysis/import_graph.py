@@ -0,0 +1,294 @@
+"""
+Package-level import graph built from per-file import evidence
+
+Collapses file-level evidence into first-party packages (one per source
+directory) with directed edges to the packages they import, so consumers can
+tell which internal package pulls in a given dependency, finds the import
+cycles among first-party packages, and indexes the files importing each
+external package
+"""
+
+import posixpath
+
+from gardener.common.defaults import ResourceLimits
+from gardener.treewalk.go import is_go_relative_import
+
+NODE_FIRST_PARTY = "first_party"
+NODE_EXTERNAL = "external"
+NODE_STDLIB = "stdlib"
+
+
+def _package_id(directory, go_module_path, go_workspace_modules):
+    """
+    Name the first-party package that lives in a repository directory
+
+    Args:
+        directory (str): Repo-relative POSIX directory ("." for the root)
+        go_module_path (str): Root go.mod module path, or None
+        go_workspace_modules (dict): Workspace module path -> repo-relative module directory
+
+    Returns:
+        str: Module-qualified import path when a module owns the directory, otherwise the directory
+    """
+    if directory == ".." or directory.startswith("../"):
+        # Relative imports can point outside the repository, where no module is known
+        return directory
+    owners = [(go_module_path, ".")] if go_module_path else []
+    owners.extend(
+        (module_path, posixpath.normpath(module_dir)) for module_path, module_dir in go_workspace_modules.items()
+    )
+    best, best_depth = None, -1
+    for module_path, module_dir in owners:
+        if module_dir == ".":
+            relative, depth = directory, 0
+        elif directory == module_dir or directory.startswith(f"{module_dir}/"):
+            relative, depth = posixpath.relpath(directory, module_dir), module_dir.count("/") + 1
+        else:
+            continue
+        # The most deeply nested module owns the directory
+        if depth > best_depth:
+            best, best_depth = (module_path, relative), depth
+    if best is None:
+        return directory
+    module_path, relative = best
+    return module_path if relative == "." else f"{module_path}/{relative}"
+
+
+def _edge_target(entry, directory, go_module_path, go_workspace_modules):
+    """
+    Return the node an evidence entry points at
+
+    Args:
+        entry (dict): Import evidence entry
+        directory (str): Directory of the importing file
+        go_module_path (str): Root go.mod module path, or None
+        go_workspace_modules (dict): Workspace module path -> repo-relative module directory
+
+    Returns:
+        tuple: (node id, node kind)
+    """
+    import_path = entry.get("import", "")
+    scope = entry.get("scope")
+    if scope == "local":
+        if is_go_relative_import(import_path):
+            target_dir = posixpath.normpath(posixpath.join(directory, import_path))
+            return _package_id(target_dir, go_module_path, go_workspace_modules), NODE_FIRST_PARTY
+        return import_path, NODE_FIRST_PARTY
+    if scope == "stdlib":
+        return import_path, NODE_STDLIB
+    return entry.get("module") or import_path, NODE_EXTERNAL
+
+
+def build_import_graph(file_import_evidence, go_module_path=None, go_workspace_modules=None):
+    """
+    Build the package-level import graph
+
+    Args:
+        file_import_evidence (dict): Repo-relative file path -> list of evidence entries
+        go_module_path (str): Root go.mod module path, or None
+        go_workspace_modules (dict): Workspace module path -> repo-relative module directory
+
+    Returns:
+        dict: {"nodes": [{"id", "kind", ["path"]}], "edges": [{"source", "target", "import",
+            "import_kind", "files"}]}, sorted for stable output
+    """
+    go_workspace_modules = go_workspace_modules or {}
+    nodes = {}
+    edges = {}
+
+    for rel_path, entries in file_import_evidence.items():
+        directory = posixpath.dirname(rel_path.replace("\\", "/")) or "."
+        source = _package_id(directory, go_module_path, go_workspace_modules)
+        nodes[source] = {"id": source, "kind": NODE_FIRST_PARTY, "path": directory}
+        for entry in entries:
+            target, kind = _edge_target(entry, directory, go_module_path, go_workspace_modules)
+            if not target or target == source:
+                continue
+            nodes.setdefault(target, {"id": target, "kind": kind})
+            key = (source, target, entry.get("import", ""), entry.get("import_kind", "named"))
+            edges.setdefault(key, set()).add(rel_path)
+
+    return {
+        "nodes": [nodes[node_id] for node_id in sorted(nodes)],
+        "edges": [
+            {
+                "source": source,
+                "target": target,
+                "import": import_path,
+                "import_kind": import_kind,
+                "files": sorted(files),
+            }
+            for (source, target, import_path, import_kind), files in sorted(edges.items())
+        ],
+    }
+
+
+def build_reverse_index(external_packages):
+    """
+    Map each external package to the files that import it directly
+
+    Built from the `seen_in` provenance of each package, so it answers "which
+    files pull in X?" without walking the import graph
+
+    Args:
+        external_packages (dict): Package metadata map keyed by distribution name
+
+    Returns:
+        tuple: (reverse_index, reverse_index_scopes); reverse_index maps each imported package to its
+            sorted importing files, reverse_index_scopes maps the packages whose importing files have a
+            scope (Go) to {scope: sorted files}; both sorted by package name
+    """
+    index, scopes = {}, {}
+    for package_name in sorted(external_packages):
+        seen_in = external_packages[package_name].get("seen_in") or []
+        files = sorted({entry["file"] for entry in seen_in})
+        if not files:
+            continue
+        index[package_name] = files
+        by_scope = {}
+        for entry in seen_in:
+            if entry.get("scope"):
+                by_scope.setdefault(entry["scope"], set()).add(entry["file"])
+        if by_scope:
+            scopes[package_name] = {scope: sorted(by_scope[scope]) for scope in sorted(by_scope)}
+    return index, scopes
+
+
+
+def _strongly_connected_components(nodes, successors):
+    """
+    Split a directed graph into strongly connected components (iterative Tarjan)
+
+    Args:
+        nodes (set): Nodes to consider
+        successors (callable): successors(node) -> iterable of nodes within `nodes`
+
+    Returns:
+        list: Components as sets of nodes
+    """
+    index, low = {}, {}
+    stack, on_stack, components = [], set(), []
+    for root in sorted(nodes):
+        if root in index:
+            continue
+        index[root] = low[root] = len(index)
+        stack.append(root)
+        on_stack.add(root)
+        work = [(root, iter(sorted(successors(root))))]
+        while work:
+            node, children = work[-1]
+            child = next(children, None)
+            if child is not None:
+                if child not in index:
+                    index[child] = low[child] = len(index)
+                    stack.append(child)
+                    on_stack.add(child)
+                    work.append((child, iter(sorted(successors(child)))))
+                elif child in on_stack:
+                    low[node] = min(low[node], index[child])
+                continue
+            work.pop()
+            if work:
+                parent = work[-1][0]
+                low[parent] = min(low[parent], low[node])
+            if low[node] == index[node]:
+                component = set()
+                while True:
+                    member = stack.pop()
+                    on_stack.discard(member)
+                    component.add(member)
+                    if member == node:
+                        break
+                components.append(component)
+    return components
+
+
+def _unblock(node, blocked, blocked_by):
+    """
+    Unblock a node and, transitively, the nodes waiting on it
+    """
+    pending = [node]
+    while pending:
+        node = pending.pop()
+        if node in blocked:
+            blocked.discard(node)
+            pending.extend(blocked_by.pop(node, ()))
+
+
+def _circuits(successors, start, limit):
+    """
+    Find the elementary cycles through a start node (Johnson's circuit search)
+
+    Args:
+        successors (callable): successors(node) -> set of nodes, restricted to a strongly connected component
+        start (str): Node every returned cycle begins with
+        limit (int): Maximum number of cycles to return
+
+    Returns:
+        list: Cycles as node lists starting at start
+    """
+    cycles = []
+    path, blocked, closed, blocked_by = [start], {start}, set(), {}
+    # Children are sorted in reverse so that pop() visits them in sorted order
+    stack = [(start, sorted(successors(start), reverse=True))]
+    while stack and len(cycles) < limit:
+        node, children = stack[-1]
+        if children:
+            child = children.pop()
+            if child == start:
+                cycles.append(list(path))
+                closed.update(path)
+            elif child not in blocked:
+                path.append(child)
+                blocked.add(child)
+                closed.discard(child)
+                stack.append((child, sorted(successors(child), reverse=True)))
+                continue
+        if not children:
+            if node in closed:
+                _unblock(node, blocked, blocked_by)
+            else:
+                for child in successors(node):
+                    blocked_by.setdefault(child, set()).add(node)
+            stack.pop()
+            path.pop()
+    return cycles
+
+
+def find_import_cycles(import_graph, max_cycles=None):
+    """
+    Find the import cycles among first-party packages
+
+    Only edges between first-party nodes are followed; external and stdlib
+    targets end the traversal. Every elementary cycle is reported once, as the
+    packages along the loop in import order starting at its lexicographically
+    smallest package; the last package imports the first
+
+    Args:
+        import_graph (dict): Output of build_import_graph
+        max_cycles (int): Stop after this many cycles (default: ResourceLimits.MAX_IMPORT_CYCLES)
+
+    Returns:
+        list: Cycles sorted by length, then by their package lists
+    """
+    max_cycles = ResourceLimits.MAX_IMPORT_CYCLES if max_cycles is None else max_cycles
+    first_party = {node["id"] for node in import_graph.get("nodes", []) if node.get("kind") == NODE_FIRST_PARTY}
+    graph = {}
+    for edge in import_graph.get("edges", []):
+        if edge["source"] in first_party and edge["target"] in first_party:
+            graph.setdefault(edge["source"], set()).add(edge["target"])
+
+    def within(nodes):
+        return lambda node: graph.get(node, set()) & nodes
+
+    cycles = []
+    pending = [c for c in _strongly_connected_components(set(graph), within(set(graph))) if len(c) > 1]
+    while pending and len(cycles) < max_cycles:
+        component = pending.pop()
+        # Cycles through the smallest package start there; the rest of the component may hold more
+        start = min(component)
+        cycles.extend(_circuits(within(component), start, max_cycles - len(cycles)))
+        rest = component - {start}
+        pending.extend(c for c in _strongly_connected_components(rest, within(rest)) if len(c) > 1)
+
+    return sorted(cycles, key=lambda cycle: (len(cycle), cycle))
@@ -3,18 +3,26 @@ Import extraction helpers and LocalImportResolver for RepositoryAnalyzer
 
 Provides a timeout context manager, the LocalImportResolver used for per‑language
 local path resolution, and a file walker that extracts external and local imports
-using tree‑sitter parsers
+with the registered language analyzers
 """
 
 import logging
 import os
+import posixpath
 import signal
+import threading
+import time
 from collections import defaultdict
+from concurrent.futures import ThreadPoolExecutor
 from contextlib import contextmanager
 from pathlib import Path
 
-from gardener.common.defaults import ResourceLimits
-from gardener.common.tsl import get_parser
+from gardener.analysis.file_cache import content_hash
+from gardener.common.cancellation import is_cancelled
+from gardener.common.defaults import GoAnalysisConfig, ResourceLimits
+from gardener.common.file_helpers import normalize_line_endings, read_file_content, to_posix_path
+from gardener.treewalk.go import find_go_module_for_import, is_go_ignore_constraint
+from gardener.treewalk.registry import AnalyzerUnavailableError, ExtractionContext
 
 JS_TS_SOURCE_EXTS = [".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs"]
 JSONLIKE_EXTS = [".json"]
@@ -42,7 +50,7 @@ def timeout(seconds):
     Returns:
         None
     """
-    if hasattr(signal, "SIGALRM"):
+    if hasattr(signal, "SIGALRM") and threading.current_thread() is threading.main_thread():
 
         def handler(signum, frame):
             raise TimeoutError(f"Operation timed out after {seconds} seconds")
@@ -55,7 +63,8 @@ def timeout(seconds):
             signal.alarm(0)
             signal.signal(signal.SIGALRM, previous)
     else:
-        logging.debug("Timeout protection not available on this platform")
+        # SIGALRM is unavailable on some platforms and can only be installed from the main thread
+        logging.debug("Timeout protection not available on this platform or thread")
         yield
 
 
@@ -70,6 +79,8 @@ class LocalImportResolver:
         js_ts_base_url (str|None): baseUrl from ts/js config used by legacy resolver
         js_ts_path_aliases (dict): legacy paths map from ts/js config
         go_module_path (str|None): Module path from go.mod for absolute imports
+        go_workspace_modules (dict|None): go.work member module paths mapped to repo-relative directories
+        go_modules (dict|None): Module paths of every go.mod in the repository mapped to repo-relative directories
         remappings (dict): Solidity remappings from remappings.txt
         hardhat_remappings (dict): Solidity remappings derived from Hardhat config
         solidity_src_path (str|None): Foundry src path when available
@@ -78,13 +89,19 @@ class LocalImportResolver:
 
     def __init__(self, repo_path, source_files, alias_resolver, js_ts_base_url,
                  js_ts_path_aliases, go_module_path, remappings, hardhat_remappings,
-                 solidity_src_path, logger):
+                 solidity_src_path, logger, go_workspace_modules=None, go_modules=None):
         self.repo_path = repo_path
+        # Keys are compared against POSIX candidate paths; the dict is shared with the caller and grows as
+        # JSON and data files are resolved, so it is normalized in place
+        for rel_path in [rel_path for rel_path in source_files if "\\" in rel_path]:
+            source_files[to_posix_path(rel_path)] = source_files.pop(rel_path)
         self.source_files = source_files
         self.alias_resolver = alias_resolver
         self.js_ts_base_url = js_ts_base_url
         self.js_ts_path_aliases = js_ts_path_aliases or {}
         self.go_module_path = go_module_path
+        self.go_workspace_modules = go_workspace_modules or {}
+        self.go_modules = go_modules or {}
         self.remappings = remappings or {}
         self.hardhat_remappings = hardhat_remappings or {}
         self.solidity_src_path = solidity_src_path
@@ -95,15 +112,15 @@ class LocalImportResolver:
         return not module_str and level == 0
 
     def _py_base_dir_for_relative(self, importing_file_rel_path, level):
-        importing_file_rel_path_norm = str(Path(importing_file_rel_path))
-        base_dir = str(Path(importing_file_rel_path_norm).parent)
+        importing_file_rel_path_norm = to_posix_path(Path(importing_file_rel_path))
+        base_dir = to_posix_path(Path(importing_file_rel_path_norm).parent)
         if level <= 0:
             return base_dir
         current_dir = base_dir
         for _ in range(level - 1):
             if current_dir == "":
                 return None
-            current_dir = str(Path(current_dir).parent)
+            current_dir = to_posix_path(Path(current_dir).parent)
         return current_dir
 
     def _py_target_paths(self, importing_file_rel_path, module_str, level):
@@ -111,26 +128,26 @@ class LocalImportResolver:
         if level > 0 and not module_str:
             if current_dir is None:
                 return []
-            return [str(Path(current_dir) / "__init__.py")]
+            return [to_posix_path(Path(current_dir) / "__init__.py")]
 
         if level > 0:
             if current_dir is None:
                 return []
             module_parts = module_str.split(".") if module_str else []
             import_path_base = (
-                str(Path(current_dir).joinpath(*module_parts)) if module_parts else current_dir
+                to_posix_path(Path(current_dir).joinpath(*module_parts)) if module_parts else current_dir
             )
         else:
             module_parts = module_str.split(".") if module_str else []
-            import_path_base = str(Path(*module_parts)) if module_parts else "."
+            import_path_base = to_posix_path(Path(*module_parts)) if module_parts else "."
 
-        standard = str(Path(f"{import_path_base}.py"))
-        init_file = str(Path(import_path_base) / "__init__.py")
+        standard = to_posix_path(Path(f"{import_path_base}.py"))
+        init_file = to_posix_path(Path(import_path_base) / "__init__.py")
         return [standard, init_file]
 
     def _py_first_existing(self, candidates):
         for path in candidates:
-            normalized = str(Path(path))
+            normalized = to_posix_path(Path(path))
             if normalized in self.source_files:
                 return normalized
         return None
@@ -147,6 +164,7 @@ class LocalImportResolver:
         Returns:
             str|None: Repo‑relative target path if resolved, otherwise None
         """
+        importing_file_rel_path = to_posix_path(importing_file_rel_path)
         if self._py_is_invalid_blank_absolute(module_str, relative_level):
             return None
         candidates = self._py_target_paths(importing_file_rel_path, module_str, relative_level)
@@ -154,14 +172,14 @@ class LocalImportResolver:
 
     # --- JS/TS helpers ---
     def _join_norm(self, *parts):
-        return str(Path(*parts)) if parts else "."
+        return to_posix_path(Path(*parts)) if parts else "."
 
     def _rel_to_repo(self, abs_path):
         try:
-            rel = str(Path(abs_path).relative_to(self.repo_path))
+            rel = to_posix_path(Path(abs_path).relative_to(self.repo_path))
         except ValueError:
             rel = os.path.relpath(abs_path, self.repo_path)
-        return str(Path(rel))
+        return to_posix_path(Path(rel))
 
     def _source_has(self, rel_path):
         return rel_path in self.source_files
@@ -203,7 +221,7 @@ class LocalImportResolver:
             return None
         resolved = self.alias_resolver.resolve(importing_file_rel_path, module_str)
         if resolved:
-            return resolved
+            return to_posix_path(resolved)
         return None
 
     def _js_legacy_path_alias(self, importing_file_rel_path, module_str):
@@ -240,7 +258,7 @@ class LocalImportResolver:
                     if target_template.endswith("/*"):
                         base_target = target_template[:-2]
                         resolved_segment = (
-                            str(Path(base_target) / module_wildcard_part)
+                            to_posix_path(Path(base_target) / module_wildcard_part)
                             if module_wildcard_part
                             else base_target
                         )
@@ -257,16 +275,16 @@ class LocalImportResolver:
                     resolved_segment = target_template
 
                 if self.js_ts_base_url and self.js_ts_base_url != ".":
-                    path_from_root = str(Path(self.js_ts_base_url) / resolved_segment)
+                    path_from_root = to_posix_path(Path(self.js_ts_base_url) / resolved_segment)
                 else:
                     path_from_root = resolved_segment
 
-                path_from_root = str(Path(path_from_root))
+                path_from_root = to_posix_path(Path(path_from_root))
 
                 if path_from_root in self.source_files:
                     return path_from_root
 
-                candidate_path = str(Path(self.repo_path) / path_from_root)
+                candidate_path = to_posix_path(Path(self.repo_path) / path_from_root)
                 if (
                     os.path.splitext(path_from_root)[1]
                     and Path(candidate_path).exists()
@@ -279,7 +297,7 @@ class LocalImportResolver:
                     return path_from_root
 
                 for ext in JS_TS_SOURCE_EXTS:
-                    target_with_ext = str(Path(f"{path_from_root}{ext}"))
+                    target_with_ext = to_posix_path(Path(f"{path_from_root}{ext}"))
                     if target_with_ext in self.source_files:
                         return target_with_ext
 
@@ -288,8 +306,8 @@ class LocalImportResolver:
                 )
                 if not has_known_extension:
                     for ext in JS_TS_SOURCE_EXTS:
-                        index_path = str(Path(path_from_root) / f"index{ext}")
-                        index_path = str(Path(index_path))
+                        index_path = to_posix_path(Path(path_from_root) / f"index{ext}")
+                        index_path = to_posix_path(Path(index_path))
                         if index_path in self.source_files:
                             return index_path
 
@@ -298,15 +316,15 @@ class LocalImportResolver:
     def _js_resolve_relative_base(self, importing_file_rel_path, module_str):
         if not module_str.startswith("."):
             return None
-        abs_dir = str((Path(self.repo_path) / importing_file_rel_path).parent)
-        abs_target = str(Path(abs_dir) / module_str)
-        normalized = str(Path(abs_target).resolve())
+        abs_dir = to_posix_path((Path(self.repo_path) / importing_file_rel_path).parent)
+        abs_target = to_posix_path(Path(abs_dir) / module_str)
+        normalized = to_posix_path(Path(abs_target).resolve())
         return self._rel_to_repo(normalized)
 
     def _js_try_as_is_or_data_like(self, rel_base):
         if self._source_has(rel_base):
             return rel_base
-        full_path = str(Path(self.repo_path) / rel_base)
+        full_path = to_posix_path(Path(self.repo_path) / rel_base)
         full_path_path = Path(full_path)
         if full_path_path.exists() and full_path_path.is_file():
             if rel_base.endswith(tuple(JSONLIKE_EXTS + [".cjs", ".mjs"])):
@@ -319,7 +337,7 @@ class LocalImportResolver:
 
     def _js_try_with_source_exts(self, rel_base, module_str):
         for ext in self._js_extensions(module_str):
-            target = str(Path(f"{rel_base}{ext}"))
+            target = to_posix_path(Path(f"{rel_base}{ext}"))
             if self._source_has(target):
                 return target
         return None
@@ -328,8 +346,8 @@ class LocalImportResolver:
         if os.path.splitext(rel_base)[1]:
             return None
         for ext in self._js_extensions(module_str):
-            candidate = str(Path(rel_base) / f"index{ext}")
-            candidate = str(Path(candidate))
+            candidate = to_posix_path(Path(rel_base) / f"index{ext}")
+            candidate = to_posix_path(Path(candidate))
             if self._source_has(candidate):
                 return candidate
         return None
@@ -346,6 +364,7 @@ class LocalImportResolver:
         Returns:
             str|None: Repo‑relative target path or None when treated as external
         """
+        importing_file_rel_path = to_posix_path(importing_file_rel_path)
         if self.alias_resolver:
             pkg_marker = self._js_resolve_framework_package_alias(importing_file_rel_path, module_str)
             if pkg_marker:
@@ -387,10 +406,10 @@ class LocalImportResolver:
         if first_part == "crate":
             return "src", "crate", use_path_parts[1:]
         if first_part == "self":
-            return str(Path(importing_file_rel_path).parent), "self", use_path_parts[1:]
+            return to_posix_path(Path(importing_file_rel_path).parent), "self", use_path_parts[1:]
         if first_part == "super":
-            return str(Path(importing_file_rel_path).parent.parent), "super", use_path_parts[1:]
-        importing_dir = str(Path(importing_file_rel_path).parent)
+            return to_posix_path(Path(importing_file_rel_path).parent.parent), "super", use_path_parts[1:]
+        importing_dir = to_posix_path(Path(importing_file_rel_path).parent)
         if importing_dir == "src" and Path(importing_file_rel_path).name in ["main.rs", "lib.rs"]:
             current_dir = "src"
         else:
@@ -405,32 +424,32 @@ class LocalImportResolver:
     def _rust_handle_empty_or_wildcard(self, first_part, importing_file_rel_path, current_dir, remainder):
         if not remainder:
             if first_part == "crate":
-                lib_path = str(Path(current_dir) / "lib.rs")
+                lib_path = to_posix_path(Path(current_dir) / "lib.rs")
                 if lib_path in self.source_files:
                     return lib_path, True
-                main_path = str(Path(current_dir) / "main.rs")
+                main_path = to_posix_path(Path(current_dir) / "main.rs")
                 if main_path in self.source_files:
                     return main_path, True
             return None, True
 
         if len(remainder) == 1 and remainder[0] == "*":
             if first_part == "crate":
-                lib_path = str(Path(current_dir) / "lib.rs")
+                lib_path = to_posix_path(Path(current_dir) / "lib.rs")
                 if lib_path in self.source_files:
                     return lib_path, True
-                main_path = str(Path(current_dir) / "main.rs")
+                main_path = to_posix_path(Path(current_dir) / "main.rs")
                 if main_path in self.source_files:
                     return main_path, True
                 return None, True
             if first_part == "self":
                 return importing_file_rel_path, True
             if first_part == "super":
-                parent_dir = str(Path(importing_file_rel_path).parent)
+                parent_dir = to_posix_path(Path(importing_file_rel_path).parent)
                 segment = Path(parent_dir).name
-                target_rs = str(Path(current_dir) / f"{segment}.rs")
+                target_rs = to_posix_path(Path(current_dir) / f"{segment}.rs")
                 if target_rs in self.source_files:
                     return target_rs, True
-                target_mod = str(Path(current_dir) / segment / "mod.rs")
+                target_mod = to_posix_path(Path(current_dir) / segment / "mod.rs")
                 if target_mod in self.source_files:
                     return target_mod, True
                 return None, True
@@ -446,13 +465,13 @@ class LocalImportResolver:
             if len(module_segments) > 1:
                 path_parts_rs.extend(module_segments[:-1])
             path_parts_rs.append(f"{module_segments[-1]}.rs")
-            candidate_rs = str(Path(*path_parts_rs)) if path_parts_rs else "."
+            candidate_rs = to_posix_path(Path(*path_parts_rs)) if path_parts_rs else "."
             if candidate_rs in self.source_files:
                 return candidate_rs
             path_parts_mod = [current_dir]
             path_parts_mod.extend(module_segments)
             path_parts_mod.append("mod.rs")
-            candidate_mod = str(Path(*path_parts_mod)) if path_parts_mod else "."
+            candidate_mod = to_posix_path(Path(*path_parts_mod)) if path_parts_mod else "."
             if candidate_mod in self.source_files:
                 return candidate_mod
         return None
@@ -468,6 +487,7 @@ class LocalImportResolver:
         Returns:
             str|None: Repo‑relative module path if resolved, otherwise None
         """
+        importing_file_rel_path = to_posix_path(importing_file_rel_path)
         if not use_path_parts:
             return None
 
@@ -493,28 +513,38 @@ class LocalImportResolver:
 
     # --- Go helpers ---
     def _go_is_module_absolute(self, module_str):
-        return bool(self.go_module_path and module_str.startswith(self.go_module_path))
+        if not self.go_module_path:
+            return False
+        return module_str == self.go_module_path or module_str.startswith(self.go_module_path + "/")
 
     def _go_import_path_for_relative(self, importing_file_rel_path, module_str):
-        abs_dir = str((Path(self.repo_path) / importing_file_rel_path).parent)
-        abs_target = str((Path(abs_dir) / module_str).resolve())
+        abs_dir = to_posix_path((Path(self.repo_path) / importing_file_rel_path).parent)
+        abs_target = to_posix_path((Path(abs_dir) / module_str).resolve())
         try:
-            rel = str(Path(abs_target).relative_to(self.repo_path))
+            rel = to_posix_path(Path(abs_target).relative_to(self.repo_path))
         except ValueError:
             rel = os.path.relpath(abs_target, self.repo_path)
-        return str(Path(rel))
+        return to_posix_path(Path(rel))
+
+    def _go_workspace_import_path(self, module_str):
+        workspace_module = find_go_module_for_import(module_str, self.go_workspace_modules)
+        if workspace_module is None:
+            return None
+        relative_part = module_str[len(workspace_module) :].lstrip("/")
+        module_dir = to_posix_path(self.go_workspace_modules[workspace_module])
+        return posixpath.normpath(posixpath.join(module_dir, relative_part))
 
     def _go_candidate_files(self, import_path):
         package_dir = Path(import_path).name
-        yield str(Path(f"{import_path}.go"))
-        yield str(Path(import_path) / f"{package_dir}.go")
+        yield to_posix_path(Path(f"{import_path}.go"))
+        yield to_posix_path(Path(import_path) / f"{package_dir}.go")
 
     def _go_find_single_go_in_dir(self, import_path):
-        prefix = "" if import_path == "." else import_path + os.sep
+        prefix = "" if import_path == "." else import_path + "/"
         found = []
         for rel_path in self.source_files:
             if prefix == "":
-                if os.sep not in rel_path and rel_path.endswith(".go"):
+                if "/" not in rel_path and rel_path.endswith(".go"):
                     found.append(rel_path)
             elif rel_path.startswith(prefix) and rel_path.endswith(".go"):
                 found.append(rel_path)
@@ -535,17 +565,26 @@ class LocalImportResolver:
         Returns:
             str|None: Repo‑relative `.go` file if uniquely determined, otherwise None
         """
+        importing_file_rel_path = to_posix_path(importing_file_rel_path)
         if not module_str.startswith("."):
-            if self._go_is_module_absolute(module_str):
+            # The longest module path owns a package: a nested module even under the root module's path,
+            # the analyzed directory within the module enclosing it
+            import_path = None
+            workspace_module = find_go_module_for_import(module_str, self.go_workspace_modules)
+            if self._go_is_module_absolute(module_str) and (
+                workspace_module is None or len(workspace_module) <= len(self.go_module_path)
+            ):
                 relative_part = module_str[len(self.go_module_path) :].lstrip("/")
-                import_path = str(Path(relative_part).resolve())
-            else:
+                import_path = posixpath.normpath(relative_part) if relative_part else "."
+            elif workspace_module is not None:
+                import_path = self._go_workspace_import_path(module_str)
+            if import_path is None:
                 return None
         else:
             import_path = self._go_import_path_for_relative(importing_file_rel_path, module_str)
 
         for candidate in self._go_candidate_files(import_path):
-            normalized = str(Path(candidate))
+            normalized = to_posix_path(Path(candidate))
             if normalized in self.source_files:
                 return normalized
 
@@ -553,7 +592,8 @@ class LocalImportResolver:
         if isinstance(single_or_list, str):
             return single_or_list
         if isinstance(single_or_list, list) and self.logger:
-            self.logger.warning(
+            # Most packages span several files; the import is still recorded as local by its import path
+            self.logger.debug(
                 f"Go resolver: Found multiple .go files in directory '{import_path}' "
                 f"for import '{module_str}' from '{importing_file_rel_path}': {single_or_list}. Resolution is ambiguous."  # noqa
             )
@@ -566,26 +606,26 @@ class LocalImportResolver:
         for prefix, remapped_base in remappings_dict.items():
             if import_path_str.startswith(prefix):
                 path_after = import_path_str[len(prefix) :]
-                remapped_segment = str(Path(remapped_base) / path_after)
-                full_path = str((Path(self.repo_path) / remapped_segment).resolve())
+                remapped_segment = to_posix_path(Path(remapped_base) / path_after)
+                full_path = to_posix_path((Path(self.repo_path) / remapped_segment).resolve())
                 try:
-                    rel = str(Path(full_path).relative_to(self.repo_path))
+                    rel = to_posix_path(Path(full_path).relative_to(self.repo_path))
                 except ValueError:
                     rel = os.path.relpath(full_path, self.repo_path)
-                rel = str(Path(rel))
+                rel = to_posix_path(Path(rel))
                 if rel in self.source_files:
                     return rel
         return None
 
     def _solidity_relative_target(self, importing_file_rel_path, import_path_str):
-        base_dir = str(Path(importing_file_rel_path).parent)
+        base_dir = to_posix_path(Path(importing_file_rel_path).parent)
         abs_base_dir = os.path.join(self.repo_path, base_dir)
         target_abs = os.path.normpath(os.path.join(abs_base_dir, import_path_str))
         try:
-            target_rel = str(Path(target_abs).relative_to(self.repo_path))
+            target_rel = to_posix_path(Path(target_abs).relative_to(self.repo_path))
         except ValueError:
             target_rel = os.path.relpath(target_abs, self.repo_path)
-        target_rel = str(Path(target_rel))
+        target_rel = to_posix_path(Path(target_rel))
         if not target_rel.endswith(".sol"):
             return None
         if target_rel in self.source_files:
@@ -593,11 +633,11 @@ class LocalImportResolver:
         if (
             self.solidity_src_path
             and import_path_str.startswith("../")
-            and importing_file_rel_path.startswith(self.solidity_src_path + os.sep)
+            and importing_file_rel_path.startswith(to_posix_path(self.solidity_src_path) + "/")
         ):
             remainder = import_path_str[3:]
-            fallback = str(Path(self.solidity_src_path) / remainder)
-            fallback = str(Path(fallback))
+            fallback = to_posix_path(Path(self.solidity_src_path) / remainder)
+            fallback = to_posix_path(Path(fallback))
             if fallback in self.source_files:
                 return fallback
         return None
@@ -613,6 +653,7 @@ class LocalImportResolver:
         Returns:
             str|None: Repo‑relative path if resolved, otherwise None
         """
+        importing_file_rel_path = to_posix_path(importing_file_rel_path)
         if not import_path_str.startswith("."):
             resolved = self._solidity_try_remappings(import_path_str, self.hardhat_remappings)
             if resolved:
@@ -627,118 +668,336 @@ class LocalImportResolver:
             return target
         return None
 
+    def resolve_github_action(self, importing_file_rel_path, action_path):
+        """
+        Resolve a local `uses: ./path` reference of a GitHub Actions workflow or action
+
+        Local references are relative to the repository root, not to the referencing file
+
+        Args:
+            importing_file_rel_path (str): Workflow or action file path relative to the repo
+            action_path (str): Reference as written, e.g. "./.github/actions/setup"
+
+        Returns:
+            str|None: The referenced workflow file, or the action's action.yml/action.yaml, if scanned
+        """
+        importing_file_rel_path = to_posix_path(importing_file_rel_path)
+        relative_part = posixpath.normpath(action_path)
+        if relative_part.startswith(".."):
+            return None
+        candidates = [relative_part]
+        if not relative_part.endswith((".yml", ".yaml")):
+            candidates = [posixpath.join(relative_part, name) for name in ("action.yml", "action.yaml")]
+        for candidate in candidates:
+            normalized = to_posix_path(Path(candidate))
+            if normalized in self.source_files:
+                return normalized
+        return None
+
 
-def extract_imports(source_files, language_handlers, repo_path, secure_file_ops, local_resolver, logger):
+def analysis_jobs():
     """
-    Extract imports from source files using provided handlers
+    Return the number of parser worker threads to use
+
+    Returns:
+        int: ResourceLimits.ANALYSIS_JOBS, or the CPU count when it is 0 or negative
+    """
+    if ResourceLimits.ANALYSIS_JOBS > 0:
+        return ResourceLimits.ANALYSIS_JOBS
+    return os.cpu_count() or 1
+
+
+def _file_error(rel_path, error, detail):
+    """
+    Build the diagnostic reported for a file that could not be analyzed completely
 
     Args:
-        source_files (dict): Map of repo‑relative paths to file metadata
-        language_handlers (dict): Registered language handlers keyed by language name
-        repo_path (str): Absolute repository root path
+        rel_path (str): Repo‑relative path of the file
+        error (str): Failure kind, "read-failed" or "parse-failed"
+        detail (str): Human-readable cause
+
+    Returns:
+        dict: {"file", "error", "detail"}
+    """
+    return {"file": rel_path, "error": error, "detail": detail}
+
+
+def _failed_result(rel_path, error, detail):
+    """
+    Per-file result for a file whose imports could not be extracted
+
+    Returns:
+        dict: {"failed": True, "errors": [diagnostic]}
+    """
+    return {"failed": True, "errors": [_file_error(rel_path, error, detail)]}
+
+
+def _extract_file_imports(
+    rel_path, file_info, language_handlers, secure_file_ops, local_resolver, logger, file_cache=None
+):
+    """
+    Parse one source file and extract its imports
+
+    Results are collected into per-file containers so files can be processed
+    concurrently and merged afterwards in a stable order. Unreadable or unparsable
+    files never abort the run: they yield a diagnostic under "errors", and imports
+    recovered from a tree with syntax errors are still reported
+
+    Args:
+        rel_path (str): Repo‑relative path of the file
+        file_info (dict): File metadata with absolute_path and language, and archive_source for files
+            extracted from an archive in the tree, which then marks every evidence entry
+        language_handlers (dict): Registered analyzers (see treewalk.registry) keyed by language name
         secure_file_ops (SecureFileOps|None): Secure file operations or None
         local_resolver (LocalImportResolver): Resolver for local file imports
         logger (Logger|None): Optional logger for progress and warnings
+        file_cache (FileAnalysisCache|None): Optional cache of results keyed on file content
 
     Returns:
-        Tuple of (file_imports, local_imports_map, file_package_components)
+        dict|None: {"external", "local", "components", "evidence", "generated", "ignored_build", "errors"}
+            for the file,
+            {"failed", "errors"} when it could not be read or parsed, or None when skipped
     """
-    file_imports = defaultdict(list)
-    local_imports_map = defaultdict(list)
-    file_package_components = defaultdict(list)
-
-    processed_files = 0
-
-    for rel_path, file_info in list(source_files.items()):
-        abs_path = file_info["absolute_path"]
-        language = file_info["language"]
-        if not language or language not in language_handlers:
-            continue
-        handler = language_handlers[language]
+    abs_path = file_info["absolute_path"]
+    language = file_info["language"]
+    if not language or language not in language_handlers:
+        return None
+    analyzer = language_handlers[language]
 
+    try:
         try:
-            try:
-                parser = get_parser(language)
-            except Exception as exc:
+            file_size = Path(abs_path).stat().st_size
+            if file_size > ResourceLimits.MAX_FILE_SIZE:
                 if logger:
-                    logger.warning(f"Failed to get parser for {language}: {str(exc)}, skipping file {rel_path}")
-                continue
+                    logger.warning(
+                        f"Skipping {rel_path}: file size ({file_size / 1024 / 1024:.1f}MB) "
+                        f"exceeds limit ({ResourceLimits.MAX_FILE_SIZE / 1024 / 1024}MB)",
+                        event="file-skipped",
+                        path=rel_path,
+                        reason="too-large",
+                    )
+                return None
+        except Exception as exc:
+            if logger:
+                logger.warning(f"Could not check file size for {abs_path}: {exc}")
 
-            try:
-                file_size = Path(abs_path).stat().st_size
-                if file_size > ResourceLimits.MAX_FILE_SIZE:
-                    if logger:
-                        logger.warning(
-                            f"Skipping {rel_path}: file size ({file_size / 1024 / 1024:.1f}MB) "
-                            f"exceeds limit ({ResourceLimits.MAX_FILE_SIZE / 1024 / 1024}MB)"
-                        )
-                    continue
-            except Exception as exc:
-                if logger:
-                    logger.warning(f"Could not check file size for {abs_path}: {exc}")
+        try:
+            # Files extracted by --scan-archives live outside the repository secure_file_ops is confined to
+            if secure_file_ops and not file_info.get("archive_source"):
+                code = secure_file_ops.read_file(rel_path, encoding="utf-8")
+            else:
+                code = read_file_content(abs_path, logger=logger)
+        except Exception as exc:
+            if logger:
+                logger.error(
+                    f"Could not read file {abs_path}: {exc}, skipping", event="file-skipped", path=rel_path,
+                    reason="read-failed",
+                )
+            return _failed_result(rel_path, "read-failed", str(exc))
+        code = normalize_line_endings(code)
 
-            try:
-                if secure_file_ops:
-                    code = secure_file_ops.read_file(rel_path, encoding="utf-8")
-                else:
-                    with open(abs_path, "r", encoding="utf-8", errors="ignore") as handle:
-                        code = handle.read()
-            except Exception as exc:
-                if logger:
-                    logger.error(f"Could not read file {abs_path}: {exc}, skipping")
-                continue
+        file_hash = None
+        if file_cache is not None:
+            file_hash = content_hash(code)
+            cached = file_cache.get(rel_path, file_hash)
+            if logger:
+                status = "miss" if cached is None else "hit"
+                logger.debug(
+                    f"Analysis cache {status} for {rel_path}", event="file-cache", path=rel_path, status=status
+                )
+            if cached is not None:
+                return cached
+
+        if logger:
+            logger.debug(f"Parsing {rel_path} ({len(code)} bytes)")
 
+        context = ExtractionContext(code, local_resolver=local_resolver, logger=logger)
+        try:
+            with timeout(ResourceLimits.PARSE_TIMEOUT):
+                entries = list(analyzer.extract(rel_path, context) or [])
+        except AnalyzerUnavailableError as exc:
+            if logger:
+                logger.warning(
+                    f"{exc}, skipping file {rel_path}", event="file-skipped", path=rel_path, reason="no-parser"
+                )
+            return None
+        except TimeoutError as exc:
+            if logger:
+                logger.warning(
+                    f"Parsing timed out for {rel_path}: {str(exc)}, skipping", event="file-skipped", path=rel_path,
+                    reason="timeout",
+                )
+            return _failed_result(rel_path, "parse-failed", f"timed out: {exc}")
+        except Exception as exc:
             if logger:
-                logger.debug(f"Parsing {rel_path} ({len(code)} bytes)")
+                logger.warning(
+                    f"Failed to parse {rel_path}: {str(exc)}, skipping", event="file-skipped", path=rel_path,
+                    reason="parse-failed",
+                )
+            return _failed_result(rel_path, "parse-failed", str(exc))
+
+        external_imports, local_imports = context.imports_from(entries)
+        if file_info.get("archive_source"):
+            for entry in entries:
+                entry["archive_source"] = file_info["archive_source"]
+        evidence = defaultdict(list)
+        if entries:
+            evidence[rel_path] = entries
+
+        errors = []
+        for detail in context.syntax_errors:
+            if logger:
+                logger.warning(f"Syntax error in {rel_path} ({detail}); keeping the imports that could be recovered")
+            errors.append(_file_error(rel_path, "parse-failed", detail))
+
+        result = {
+            "external": external_imports,
+            "local": local_imports,
+            "components": context.components,
+            "evidence": evidence,
+            "generated": any(entry.get("generated") for entry in entries),
+            # Tagged `//go:build ignore`: a standalone program outside the normal build
+            "ignored_build": any(is_go_ignore_constraint(entry.get("build_constraint") or "") for entry in entries),
+            "errors": errors,
+        }
+        if file_cache is not None:
+            file_cache.put(rel_path, file_hash, result)
+        return result
+
+    except Exception as exc:
+        if logger:
+            logger.exception(f"Unexpected error processing file {rel_path}")
+        return _failed_result(rel_path, "parse-failed", str(exc))
+
+
+def _dedupe(entries):
+    """
+    Drop repeated entries while keeping first-seen order
 
-            try:
-                with timeout(ResourceLimits.PARSE_TIMEOUT):
-                    tree = parser.parse(bytes(code, "utf-8"))
-            except TimeoutError as exc:
-                if logger:
-                    logger.warning(f"Parsing timed out for {rel_path}: {str(exc)}, skipping")
-                continue
-            except Exception as exc:
-                if logger:
-                    logger.warning(f"Failed to parse {rel_path}: {str(exc)}, skipping")
-                continue
+    Args:
+        entries (list): Hashable values or dicts
 
-            resolver_func = None
-            if language == "python":
-                resolver_func = local_resolver.resolve_python
-            elif language in ["javascript", "typescript"]:
-                resolver_func = local_resolver.resolve_js
-            elif language == "rust":
-                resolver_func = local_resolver.resolve_rust
-            elif language == "go":
-                resolver_func = local_resolver.resolve_go
-            elif language == "solidity":
-                resolver_func = local_resolver.resolve_solidity
+    Returns:
+        list
+    """
+    unique = []
+    for entry in entries:
+        if entry not in unique:
+            unique.append(entry)
+    return unique
+
+
+def extract_imports(
+    source_files,
+    language_handlers,
+    repo_path,
+    secure_file_ops,
+    local_resolver,
+    logger,
+    jobs=None,
+    file_cache=None,
+    on_file=None,
+    cancellation=None,
+    timings=None,
+):
+    """
+    Extract imports from source files using provided handlers
 
-            try:
-                external_imports, local_imports = handler.extract_imports(
-                    tree.root_node,
-                    rel_path,
-                    file_package_components,
-                    resolver_func,
-                    logger=logger,
-                )
+    Files are parsed on a bounded thread pool when more than one job is
+    configured; per-file results are merged in source-file order as soon as
+    they are available, so output is identical regardless of worker scheduling
 
-                if external_imports:
-                    file_imports[rel_path] = external_imports
-                if local_imports:
-                    local_imports_map[rel_path] = local_imports
+    Args:
+        source_files (dict): Map of repo‑relative paths to file metadata
+        language_handlers (dict): Registered language handlers keyed by language name
+        repo_path (str): Absolute repository root path
+        secure_file_ops (SecureFileOps|None): Secure file operations or None
+        local_resolver (LocalImportResolver): Resolver for local file imports
+        logger (Logger|None): Optional logger for progress and warnings
+        jobs (int|None): Worker count; defaults to analysis_jobs()
+        file_cache (FileAnalysisCache|None): Optional cache used to skip parsing unchanged files
+        on_file (callable|None): Called as on_file(rel_path, result) for each merged file, in
+            source-file order, while later files are still being parsed
+        cancellation (CancellationToken|None): Once cancelled, the files not yet started are skipped
+        timings (PhaseTimings|None): Receives the time spent on each file under its language
 
-                processed_files += 1
-            except Exception as exc:
-                if logger:
-                    logger.warning(f"Error extracting imports from {rel_path}: {str(exc)}")
+    Returns:
+        Tuple of (file_imports, local_imports_map, file_package_components, file_import_evidence,
+        file_errors), where file_errors lists per-file diagnostics in source-file order
+    """
+    file_imports = defaultdict(list)
+    local_imports_map = defaultdict(list)
+    file_package_components = defaultdict(list)
+    file_import_evidence = defaultdict(list)
+    file_errors = []
+
+    jobs = jobs or analysis_jobs()
+    items = [(to_posix_path(rel_path), file_info) for rel_path, file_info in source_files.items()]
+
+    def _process(item):
+        if is_cancelled(cancellation):
+            return {"cancelled": True}
+        rel_path, file_info = item
+        started = time.perf_counter()
+        result = _extract_file_imports(
+            rel_path, file_info, language_handlers, secure_file_ops, local_resolver, logger, file_cache
+        )
+        if timings is not None and result is not None:
+            timings.add_language(file_info["language"], time.perf_counter() - started)
+        return result
+
+    counts = {"processed": 0, "generated": 0, "ignored_build": 0, "cancelled": 0}
+
+    def _merge(rel_path, result):
+        if result is None:
+            return
+        if result.get("cancelled"):
+            counts["cancelled"] += 1
+            return
+        file_errors.extend(result.get("errors", []))
+        if result.get("failed"):
+            return
+        if result.get("generated") and GoAnalysisConfig.EXCLUDE_GENERATED:
+            counts["generated"] += 1
+            return
+        if result.get("ignored_build") and not GoAnalysisConfig.INCLUDE_IGNORED_BUILD:
+            counts["ignored_build"] += 1
+            return
+        if result["external"]:
+            file_imports[rel_path] = result["external"]
+        if result["local"]:
+            local_imports_map[rel_path] = result["local"]
+        for key, components in result["components"].items():
+            file_package_components[key].extend(components)
+        for key, entries in result["evidence"].items():
+            file_import_evidence[key] = _dedupe(file_import_evidence[key] + entries)
+        counts["processed"] += 1
+        if on_file is not None:
+            on_file(rel_path, result)
+
+    if jobs > 1 and len(items) > 1:
+        with ThreadPoolExecutor(max_workers=jobs) as executor:
+            # executor.map yields in submission order as each result completes
+            for (rel_path, _), result in zip(items, executor.map(_process, items)):
+                _merge(rel_path, result)
+    else:
+        for item in items:
+            _merge(item[0], _process(item))
 
-        except Exception as exc:
-            if logger:
-                logger.exception(f"Unexpected error processing file {rel_path}")
+    processed_files = counts["processed"]
+    skipped_generated = counts["generated"]
 
     if logger:
         logger.info(f"... Processed {processed_files}/{len(source_files)} files for imports")
-
-    return file_imports, local_imports_map, file_package_components
+        if counts["cancelled"]:
+            logger.warning(f"... Interrupted: imports of {counts['cancelled']} files were not extracted")
+        if skipped_generated:
+            logger.info(f"... Excluded {skipped_generated} generated Go files")
+        if counts["ignored_build"]:
+            logger.info(f"... Excluded {counts['ignored_build']} Go files tagged ignore (see --include-ignored-build)")
+        if file_errors:
+            logger.warning(f"... {len(file_errors)} files could not be analyzed cleanly; see the errors section")
+        if file_cache is not None:
+            logger.info(f"... Analysis cache: {file_cache.hits} hits, {file_cache.misses} misses")
+
+    return file_imports, local_imports_map, file_package_components, file_import_evidence, file_errors
@@ -2,23 +2,120 @@
 Main analysis module with persistence abstraction
 """
 
+import contextlib
+import json
 import os
+import sys
 
 import networkx as nx
 
+from gardener.analysis.canonical import canonical_results
 from gardener.analysis.centrality import CentralityCalculator
+from gardener.analysis.csv_export import CSV_SUFFIXES, DEFAULT_CSV_SUFFIX, to_csv
+from gardener.analysis.dependency_clusters import group_dependency_clusters
+from gardener.analysis.destinations import render_destination, write_destinations
+from gardener.analysis.go_deprecations import attach_go_deprecations
+from gardener.analysis.go_generate import attach_go_generate_tools
+from gardener.analysis.go_internal_imports import find_go_internal_imports
+from gardener.analysis.go_module_conflicts import find_go_module_conflicts, find_go_version_conflicts
+from gardener.analysis.go_modules import resolve_go_transitive
+from gardener.analysis.go_prunable import find_go_require_mismatches
+from gardener.analysis.go_pseudo_commits import attach_go_pseudo_commits
+from gardener.analysis.go_vendor_drift import find_go_vendor_drift
+from gardener.analysis.go_version_age import attach_go_version_times
 from gardener.analysis.graph import DependencyGraphBuilder
+from gardener.analysis.import_graph import build_import_graph, build_reverse_index, find_import_cycles
+from gardener.analysis.make_tools import attach_makefile_tools
+from gardener.analysis.manifests import go_replace_identities
+from gardener.analysis.markdown_report import MARKDOWN_SUFFIX, render_markdown_report
+from gardener.analysis.multi_root import combine_root_results, default_output_prefix, root_names
+from gardener.analysis.ndjson_export import NDJSON_SUFFIX, NDJSONStreamWriter, file_evidence_record
+from gardener.analysis.osv_coordinates import osv_coordinates
+from gardener.analysis.output_schema import OutputValidationError, check_output
+from gardener.analysis.package_list import PACKAGES_SUFFIX, package_list
+from gardener.analysis.pin_kinds import classify_pin
+from gardener.analysis.provenance import analysis_timestamp, tool_section
+from gardener.analysis.record_ids import package_id, with_evidence_ids
+from gardener.analysis.result_diff import diff_results, render_diff_summary
+from gardener.analysis.sbom import SBOM_SUFFIXES, package_purl, render_sbom
+from gardener.analysis.summary import build_summary, group_by_ecosystem, only_unresolved
+from gardener.analysis.test_frameworks import is_test_framework, load_test_frameworks
+from gardener.analysis.timings import PhaseTimings, timed
 from gardener.analysis.tree import RepositoryAnalyzer
-from gardener.common.defaults import ConfigOverride, GraphAnalysisConfig as cfg, apply_config_overrides
-from gardener.common.utils import Logger, get_repo
-from gardener.package_metadata.url_resolver import resolve_package_urls
+from gardener.analysis.webhook import WebhookError, deliver_webhook
+from gardener.common.archives import archive_format, archive_root_name, extracted_archive
+from gardener.common.cancellation import CancellationToken, cancel_on_interrupt, is_cancelled
+from gardener.common.defaults import (
+    CacheConfig,
+    ConfigOverride,
+    GoAnalysisConfig,
+    GraphAnalysisConfig as cfg,
+    NetworkConfig,
+    apply_config_overrides,
+)
+from gardener.common.file_helpers import to_posix_path
+from gardener.common.go_stdlib import go_stdlib_deprecation
+from gardener.common.language_detection import parse_language_filter
+from gardener.common.utils import Logger, RepositoryError, cloned_repository, get_repo, is_repository_url
+from gardener.package_metadata.url_resolver import (
+    fetch_go_proxy_metadata,
+    repository_coordinates,
+    request_attempts,
+    reset_request_attempts,
+    resolve_default_branches,
+    resolve_licenses,
+    resolve_package_urls,
+    resolution_deadline,
+)
+from gardener.package_metadata.resolution_cache import ResolutionCache
 from gardener.persistence.file import FilePersistence
-from gardener.treewalk.go import GoLanguageHandler
-from gardener.treewalk.javascript import JavaScriptLanguageHandler
-from gardener.treewalk.python import PythonLanguageHandler
-from gardener.treewalk.rust import RustLanguageHandler
-from gardener.treewalk.solidity import SolidityLanguageHandler
-from gardener.treewalk.typescript import TypeScriptLanguageHandler
+from gardener.treewalk.go import go_build_reachable
+from gardener.treewalk.registry import create_analyzers
+
+
+def _go_file_scope(rel_path, package_name, evidence):
+    """
+    Return the scope of a Go file's imports of one external package
+
+    Test frameworks (see is_test_framework) get no scope of their own: imported by a
+    non-test file, they are compiled into it like any other package
+
+    Args:
+        rel_path (str): Repo-relative Go file path
+        package_name (str): External package key
+        evidence (list): The file's import evidence
+
+    Returns:
+        str: "ignored-build" for files that need the `ignore` build tag, "example" for files
+            holding only documentation examples (see is_go_example_file), "test" for other _test.go
+            files (external `package foo_test` tests included), "tool" when the file blank-imports
+            the package under a tools build constraint, else "production"
+    """
+    if any(entry.get("scope") == "ignored-build" for entry in evidence):
+        return "ignored-build"
+    if any(entry.get("scope") == "example" for entry in evidence):
+        return "example"
+    if rel_path.endswith("_test.go"):
+        return "test"
+    for entry in evidence:
+        if entry.get("scope") == "tool" and package_name in (entry.get("module"), entry.get("import")):
+            return "tool"
+    return "production"
+
+
+def _go_file_import(evidence):
+    """
+    Return the first import entry of a Go file's evidence, which carries the file's
+    build constraint, build tags and generated flag; cgo native_dependency entries
+    (see parse_cgo_native_dependencies) do not
+
+    Args:
+        evidence (list): The file's import evidence
+
+    Returns:
+        dict: The entry, or {} when the file has none
+    """
+    return next((entry for entry in evidence if "import" in entry), {})
 
 
 class DependencyAnalyzer:
@@ -28,32 +125,42 @@ class DependencyAnalyzer:
     This class is persistence-agnostic and returns pure data structures
     """
 
-    def __init__(self, verbose=False):
+    def __init__(
+        self, verbose=False, on_file_evidence=None, since=None, cancellation=None, timings=None, files=None
+    ):
         """
         Args:
             verbose (bool): Enable verbose logging
+            on_file_evidence (callable): Optional callback receiving a `file_evidence` record
+                (see gardener.analysis.ndjson_export) as each source file's imports are extracted
+            since (str): Optional git ref; only source files changed since it are analyzed
+            cancellation (CancellationToken): Optional token; once cancelled, the remaining URL lookups
+                and file extractions are skipped and the results are marked `partial`
+            timings (PhaseTimings): Optional accumulator receiving the duration of each analysis phase
+            files (list): Optional source file paths, relative to the repository root or absolute; only
+                they are analyzed, whatever .gitignore says (see listed_source_files)
         """
         self.verbose = verbose
         self.logger = Logger(verbose=verbose)
+        self.on_file_evidence = on_file_evidence
+        self.since = since
+        self.files = files
+        self.cancellation = cancellation
+        self.timings = timings
 
         # Initialize components that persist across analysis phases
         self.repo_analyzer = None
+        self.go_require_graph = None  # resolve_go_transitive output used by the CHECK_PRUNABLE verification
+        self.dependency_clusters = {}  # group_dependency_clusters output with GROUP_CLUSTERS
+        self.build_reachability = {}  # Go build constraint -> _go_build_reachability verdict
         self.graph_builder = DependencyGraphBuilder(self.logger)
         self.centrality_calculator = CentralityCalculator(self.logger)
 
     def _register_language_handlers(self):
         """
-        Register language handlers on self.repo_analyzer
+        Register the built-in, entry-point and explicitly registered analyzers on self.repo_analyzer
         """
-        language_handlers = {
-            "javascript": JavaScriptLanguageHandler(self.logger),
-            "typescript": TypeScriptLanguageHandler(self.logger),
-            "python": PythonLanguageHandler(self.logger),
-            "go": GoLanguageHandler(self.logger),
-            "rust": RustLanguageHandler(self.logger),
-            "solidity": SolidityLanguageHandler(self.logger),
-        }
-        for language, handler in language_handlers.items():
+        for language, handler in create_analyzers(self.logger).items():
             self.repo_analyzer.register_language_handler(language, handler)
 
     def _scan_and_process_manifests(self):
@@ -63,8 +170,10 @@ class DependencyAnalyzer:
         Returns:
             Dict of external packages
         """
-        self.repo_analyzer.scan_repo()
-        return self.repo_analyzer.process_manifest_files()
+        with timed(self.timings, "directory_walk"):
+            self.repo_analyzer.scan_repo()
+        with timed(self.timings, "manifests"):
+            return self.repo_analyzer.process_manifest_files()
 
     def discover_packages(self, repo_path, specific_languages=None):
         """
@@ -78,9 +187,26 @@ class DependencyAnalyzer:
             Dictionary of external packages found
         """
         self.repo_analyzer = RepositoryAnalyzer(repo_path, specific_languages, self.logger)
+        if self.on_file_evidence is not None:
+            self.repo_analyzer.on_file_extracted = self._emit_file_evidence
+        self.repo_analyzer.changed_since = self.since
+        self.repo_analyzer.listed_files = self.files
+        self.repo_analyzer.cancellation = self.cancellation
+        self.repo_analyzer.timings = self.timings
         self._register_language_handlers()
         return self._scan_and_process_manifests()
 
+    def _emit_file_evidence(self, rel_path, result):
+        """
+        Forward one file's extraction result to on_file_evidence as a record
+
+        Args:
+            rel_path (str): Repo-relative path of the file
+            result (dict): Per-file extraction result
+        """
+        language = self.repo_analyzer.source_files.get(rel_path, {}).get("language")
+        self.on_file_evidence(file_evidence_record(rel_path, result, language))
+
     def _build_dependency_graph(self):
         """
         Build and attach dependency graph using the repo_analyzer state
@@ -128,6 +254,184 @@ class DependencyAnalyzer:
                 all_self_package_names.update(import_names)
         return all_self_package_names
 
+    def _filter_go_stdlib(self, top_deps_tuples):
+        """
+        Drop Go standard-library packages from top dependencies unless INCLUDE_STDLIB is set
+
+        Args:
+            top_deps_tuples: List of (package_name, score)
+
+        Returns:
+            List of (package_name, score)
+        """
+        if GoAnalysisConfig.INCLUDE_STDLIB:
+            return top_deps_tuples
+        graph = self.graph_builder.graph
+        return [
+            (package_name, score)
+            for package_name, score in top_deps_tuples
+            if not (graph is not None and graph.nodes.get(package_name, {}).get("ecosystem") == "go_stdlib")
+        ]
+
+    def _go_build_reachability(self, expression):
+        """
+        Return the build_reachability of the imports of a Go file under a build constraint
+
+        Args:
+            expression (str): The file's `//go:build` constraint
+
+        Returns:
+            str|None: "unlikely" when no BUILD_PLATFORMS target, with or without the BUILD_TAGS,
+                compiles the file (see go_build_reachable), else None
+        """
+        if not expression:
+            return None
+        if expression not in self.build_reachability:
+            reachable = go_build_reachable(expression, GoAnalysisConfig.BUILD_PLATFORMS, GoAnalysisConfig.BUILD_TAGS)
+            self.build_reachability[expression] = "unlikely" if reachable is False else None
+        return self.build_reachability[expression]
+
+    def _collect_import_evidence(self):
+        """
+        Return per-file import evidence, honoring INCLUDE_STDLIB
+
+        Entries of Go files whose build constraint no common build configuration
+        satisfies get `build_reachability: "unlikely"` (see _go_build_reachability)
+
+        Returns:
+            Dict mapping file path -> list of evidence entries, each with its stable `id`
+            (see with_evidence_ids)
+        """
+        evidence = {}
+        for rel_path, entries in self.repo_analyzer.file_import_evidence.items():
+            if not GoAnalysisConfig.INCLUDE_STDLIB:
+                entries = [entry for entry in entries if entry.get("scope") != "stdlib"]
+            if entries:
+                evidence[rel_path] = with_evidence_ids(rel_path, entries)
+                # Judged once per file, so its native_dependency entries are flagged with its imports
+                file_import = _go_file_import(self.repo_analyzer.file_import_evidence[rel_path])
+                if self._go_build_reachability(file_import.get("build_constraint")):
+                    for entry in evidence[rel_path]:
+                        entry["build_reachability"] = "unlikely"
+        return evidence
+
+    def _merge_import_provenance(self, graph):
+        """
+        Record on each external package the files that import it (`seen_in`) and how widely it is used
+
+        Args:
+            graph: NetworkX graph built by the graph builder, which tracks the imports behind each edge
+
+        Returns:
+            None
+        """
+        if not graph:
+            return
+        count_imports = cfg.USAGE_COUNT_BASIS.lower() == "imports"
+        if not count_imports and cfg.USAGE_COUNT_BASIS.lower() != "files":
+            self.logger.warning(f"Invalid usage count basis: {cfg.USAGE_COUNT_BASIS}; counting files")
+        external_packages = self.repo_analyzer.external_packages
+        test_frameworks = load_test_frameworks(
+            self.repo_analyzer.secure_file_ops, self.logger, self.repo_analyzer.repo_path
+        )
+        imports_by_package = {}
+        for (rel_path, package_name), idents in self.graph_builder.file_package_imports.items():
+            if package_name in external_packages:
+                imports_by_package.setdefault(package_name, {})[rel_path] = idents
+
+        for package_name, files in imports_by_package.items():
+            seen_in = []
+            test_framework = False
+            for rel_path in sorted(files):
+                entry = {"file": rel_path, "imports": sorted(files[rel_path])}
+                if rel_path.endswith(".go"):
+                    evidence = self.repo_analyzer.file_import_evidence.get(rel_path) or []
+                    file_import = _go_file_import(evidence)
+                    entry["scope"] = _go_file_scope(rel_path, package_name, evidence)
+                    if entry["scope"] == "test" and is_test_framework(package_name, test_frameworks):
+                        entry["category"] = "test-framework"
+                        test_framework = True
+                    if file_import.get("build_constraint"):
+                        entry["build_tags"] = list(file_import.get("build_tags", []))
+                        entry["build_constraint"] = file_import["build_constraint"]
+                        if self._go_build_reachability(entry["build_constraint"]):
+                            entry["build_reachability"] = "unlikely"
+                    if file_import.get("generated"):
+                        entry["generated"] = True
+                archive_source = self.repo_analyzer.source_files.get(rel_path, {}).get("archive_source")
+                if archive_source:
+                    entry["archive_source"] = archive_source
+                seen_in.append(entry)
+            package_info = external_packages[package_name]
+            package_info["seen_in"] = seen_in
+            if count_imports:
+                package_info["usage_count"] = sum(len(entry["imports"]) for entry in seen_in)
+            else:
+                package_info["usage_count"] = len(seen_in)
+            # The package takes the first of these scopes any of its files has (see _go_file_scope)
+            scopes = {entry["scope"] for entry in seen_in if "scope" in entry}
+            if scopes:
+                package_info["scope"] = next(
+                    scope for scope in ("production", "tool", "test", "example", "ignored-build") if scope in scopes
+                )
+            if test_framework and package_info.get("scope") == "test":
+                package_info["category"] = "test-framework"
+            # Unreachable only when no importing file is compiled by a common build
+            if all(entry.get("build_reachability") for entry in seen_in):
+                package_info["build_reachability"] = "unlikely"
+
+        if GoAnalysisConfig.GROUP_CLUSTERS:
+            self.dependency_clusters = group_dependency_clusters(external_packages)
+
+    def _attach_go_generate_tools(self):
+        """
+        Record the tools run by `//go:generate` directives (see attach_go_generate_tools)
+
+        Tools not already among the external packages are added, with their licenses and
+        default branches looked up
+
+        Returns:
+            None
+        """
+        external_packages = self.repo_analyzer.external_packages
+        added = attach_go_generate_tools(external_packages, self.repo_analyzer.file_import_evidence)
+        if not added:
+            return
+        self.logger.info(f"... Found {len(added)} tool(s) run by go:generate")
+        try:
+            resolve_licenses(
+                {package_name: external_packages[package_name] for package_name in added},
+                self.logger,
+                offline=NetworkConfig.OFFLINE,
+                cancellation=self.cancellation,
+            )
+        except Exception as e:
+            self.logger.warning(f"Error during license lookup: {e}")
+        self._attach_default_branches({package_name: external_packages[package_name] for package_name in added})
+
+    def _attach_makefile_tools(self):
+        """
+        Record the Go tools installed or run by Makefiles (see attach_makefile_tools)
+
+        Modules not already among the external packages are added, with their repository
+        URLs, licenses and default branches looked up
+
+        Returns:
+            None
+        """
+        external_packages = self.repo_analyzer.external_packages
+        added = attach_makefile_tools(external_packages, self.repo_analyzer.file_import_evidence)
+        if not added:
+            return
+        self.logger.info(f"... Found {len(added)} Go tool(s) installed or run by Makefiles")
+        added_packages = {package_name: external_packages[package_name] for package_name in added}
+        self._resolve_repository_urls(added_packages)
+        try:
+            resolve_licenses(added_packages, self.logger, offline=NetworkConfig.OFFLINE, cancellation=self.cancellation)
+        except Exception as e:
+            self.logger.warning(f"Error during license lookup: {e}")
+        self._attach_default_branches(added_packages)
+
     def _normalize_top_dependencies(self, top_deps_tuples):
         """
         Convert top dependency tuples into enriched dicts with percentages and URLs
@@ -160,20 +464,41 @@ class DependencyAnalyzer:
 
     def _assemble_results(self, graph, top_deps):
         """
-        Assemble final results dict with graph data and analyzer details
+        Assemble the results dict from the resolved packages, graphs and analyzer details
+
+        Args:
+            graph: NetworkX graph built by the graph builder, or None when nothing was imported
+            top_deps (list): Normalized top dependencies (see _normalize_top_dependencies)
 
         Returns:
-            Dict with keys: external_packages, dependency_graph, top_dependencies, analyzer_details
+            dict: external_packages, dependency_graph, import_graph, cycles, reverse_index,
+                reverse_index_scopes, top_dependencies, analyzer_details and summary, plus the Go
+                sections, errors, analysis_scope and partial where they apply
         """
+        file_import_evidence = self._collect_import_evidence()
+        for package_name, package_info in self.repo_analyzer.external_packages.items():
+            package_info["id"] = package_id(package_name, package_info)
+            package_info["purl"] = package_purl(package_name, package_info)
+            package_info.update(classify_pin(package_info))
+            package_info.update(osv_coordinates(package_name, package_info))
+            package_info.update(go_replace_identities(package_name, package_info))
+            package_info["repository"] = repository_coordinates(
+                package_info.get("repository_url"), (package_info.get("resolution") or {}).get("code_host")
+            )
         results = {
             "external_packages": self.repo_analyzer.external_packages,
             "dependency_graph": self.graph_builder.get_graph_data() if graph else {},
+            "import_graph": build_import_graph(
+                file_import_evidence, self.repo_analyzer.go_module_path, self.repo_analyzer.go_workspace_modules
+            ),
             "top_dependencies": top_deps,
             "analyzer_details": {
                 "local_imports_map": self.repo_analyzer.local_imports_map,
                 "file_imports": self.repo_analyzer.file_imports,
                 "file_package_components": self.repo_analyzer.file_package_components,
+                "file_import_evidence": file_import_evidence,
                 "total_files": len(self.repo_analyzer.source_files),
+                "skipped_by_depth": self.repo_analyzer.skipped_by_depth,
                 "languages_detected": (
                     list(
                         set(
@@ -186,8 +511,140 @@ class DependencyAnalyzer:
                 ),
             },
         }
+        results["cycles"] = find_import_cycles(results["import_graph"])
+        results["reverse_index"], results["reverse_index_scopes"] = build_reverse_index(
+            self.repo_analyzer.external_packages
+        )
+        if results["cycles"]:
+            self.logger.warning(
+                f"... {len(results['cycles'])} import cycles among first-party packages; see the cycles section"
+            )
+        results["summary"] = build_summary(results)
+        if self.repo_analyzer.go_toolchain:
+            results["go_toolchain"] = dict(self.repo_analyzer.go_toolchain)
+        if self.repo_analyzer.enclosing_go_mod:
+            results["enclosing_go_mod"] = dict(self.repo_analyzer.enclosing_go_mod)
+        if self.dependency_clusters:
+            results["clusters"] = self.dependency_clusters
+        stdlib_deprecations = self._go_stdlib_deprecations()
+        if stdlib_deprecations:
+            results["go_stdlib_deprecations"] = stdlib_deprecations
+        go_handler = self.repo_analyzer.language_handlers.get("go")
+        if len(self.repo_analyzer.go_modules) > 1 or getattr(go_handler, "module_deprecations", {}):
+            results["go_modules"] = self._go_modules_section()
+        version_conflicts = find_go_version_conflicts(
+            getattr(go_handler, "module_requirements", {}),
+            self.repo_analyzer.go_modules,
+            self.repo_analyzer.go_workspace_modules,
+        )
+        for conflict in version_conflicts:
+            versions = ", ".join(f"{module} {version}" for module, version in conflict["versions"].items())
+            self.logger.info(f"... {conflict['dependency']} is required at different versions: {versions}")
+        if version_conflicts:
+            results["version_conflicts"] = version_conflicts
+        if go_handler is not None and getattr(go_handler, "go_mod_files", None) is not None:
+            conflicts = find_go_module_conflicts(
+                go_handler.go_mod_files,
+                go_handler.replace_directives,
+                go_handler.workspace_uses,
+                self.repo_analyzer.repo_path,
+            )
+            for conflict in conflicts:
+                self.logger.warning(conflict["message"])
+            if conflicts:
+                results["warnings"] = conflicts
+        if getattr(go_handler, "vendor_modules", None):
+            drift = find_go_vendor_drift(
+                go_handler.vendor_modules,
+                go_handler.go_mod_files,
+                go_handler.module_requirements,
+                go_handler.replace_directives,
+                go_handler.go_sum_modules,
+                self.repo_analyzer.repo_path,
+            )
+            for warning in drift:
+                self.logger.warning(f"Vendor drift in {warning['modules_txt']}: {warning['message']}")
+            if drift:
+                results.setdefault("warnings", []).extend(drift)
+        internal_imports = find_go_internal_imports(results["import_graph"])
+        for violation in internal_imports:
+            self.logger.warning(violation["message"])
+        if internal_imports:
+            results.setdefault("warnings", []).extend(internal_imports)
+        if self.repo_analyzer.file_errors:
+            results["errors"] = list(self.repo_analyzer.file_errors)
+        if is_cancelled(self.cancellation):
+            results["partial"] = True
+        self._go_require_mismatches(results)
+        scope = {}
+        if self.repo_analyzer.file_list_scope:
+            scope = dict(self.repo_analyzer.file_list_scope, mode="files")
+        if self.repo_analyzer.diff_scope:
+            scope = dict(scope, **self.repo_analyzer.diff_scope, mode="diff")
+        if scope:
+            results["analysis_scope"] = scope
         return results
 
+    def _go_stdlib_deprecations(self):
+        """
+        List the deprecated standard-library packages Go files import, as of the root go.mod's Go version
+
+        Returns:
+            list: {"import", "deprecated_since", "suggested_replacement", "files"} entries sorted by
+                import path, files being the sorted importing files (see go_stdlib_deprecation)
+        """
+        go_version = (self.repo_analyzer.go_toolchain or {}).get("go_version")
+        importers = {}
+        for rel_path, entries in self.repo_analyzer.file_import_evidence.items():
+            for entry in entries:
+                if entry.get("scope") == "stdlib" and go_stdlib_deprecation(entry.get("import"), go_version):
+                    importers.setdefault(entry["import"], set()).add(rel_path)
+        deprecations = []
+        for import_path in sorted(importers):
+            deprecation = go_stdlib_deprecation(import_path, go_version)
+            deprecation["files"] = sorted(importers[import_path])
+            deprecations.append(deprecation)
+            self.logger.info(
+                f"... {import_path} is deprecated since Go {deprecation['deprecated_since']}; "
+                f"use {deprecation['suggested_replacement']} ({len(deprecation['files'])} files)"
+            )
+        return deprecations
+
+    def _go_modules_section(self):
+        """
+        Group Go dependencies by the go.mod module that requires them
+
+        Returns:
+            dict: Module path -> {"directory": repo-relative POSIX directory of its go.mod,
+                "dependencies": sorted required modules that are external packages}, plus
+                "deprecated" (the go.mod's `Deprecated:` message), "retract" (its retract
+                directives) and "local_replacements" (replaced module -> repo-relative
+                directory of its local replace target) when present
+        """
+        go_handler = self.repo_analyzer.language_handlers.get("go")
+        requirements = getattr(go_handler, "module_requirements", {})
+        deprecations = getattr(go_handler, "module_deprecations", {})
+        local_replacements = getattr(go_handler, "module_local_replacements", {})
+        external = self.repo_analyzer.external_packages
+        section = {}
+        for module_path, module_dir in sorted(self.repo_analyzer.go_modules.items()):
+            entry = {
+                "directory": to_posix_path(module_dir),
+                "dependencies": sorted(name for name in requirements.get(module_path, {}) if name in external),
+            }
+            notice = deprecations.get(module_path, {})
+            if notice.get("deprecated"):
+                entry["deprecated"] = notice["deprecated"]
+            if notice.get("retract"):
+                entry["retract"] = [dict(retraction) for retraction in notice["retract"]]
+            if local_replacements.get(module_path):
+                entry["local_replacements"] = {
+                    replaced: to_posix_path(os.path.relpath(replacement_dir, self.repo_analyzer.repo_path))
+                    for replaced, replacement_dir in sorted(local_replacements[module_path].items())
+                }
+            section[module_path] = entry
+        return section
+
     def analyze_dependencies(self, external_packages_with_urls):
         """
         Analyze dependencies after URLs have been resolved for external packages
@@ -205,21 +662,30 @@ class DependencyAnalyzer:
         self.repo_analyzer.external_packages = external_packages_with_urls
 
         # Extract imports from files
-        self.repo_analyzer.extract_imports_from_all_files()
-
-        # Build dependency graph and calculate scores
-        graph = self._build_dependency_graph()
-        ranked_scores = self._calculate_importance_scores(graph)
-
-        # Get top dependencies tuples and normalize
-        all_self_package_names = self._collect_self_package_names()
-        top_deps_tuples = self.graph_builder.get_top_dependencies(
-            ranked_scores, all_self_package_names=all_self_package_names
-        )
-        top_deps = self._normalize_top_dependencies(top_deps_tuples)
+        with timed(self.timings, "parsing"):
+            try:
+                self.repo_analyzer.extract_imports_from_all_files()
+            finally:
+                self.repo_analyzer.remove_extracted_archives()
+
+        with timed(self.timings, "graph"):
+            # Build dependency graph and calculate scores
+            graph = self._build_dependency_graph()
+            self._merge_import_provenance(graph)
+            self._attach_go_generate_tools()
+            self._attach_makefile_tools()
+            ranked_scores = self._calculate_importance_scores(graph)
+
+            # Get top dependencies tuples and normalize
+            all_self_package_names = self._collect_self_package_names()
+            top_deps_tuples = self.graph_builder.get_top_dependencies(
+                ranked_scores, all_self_package_names=all_self_package_names
+            )
+            top_deps_tuples = self._filter_go_stdlib(top_deps_tuples)
+            top_deps = self._normalize_top_dependencies(top_deps_tuples)
 
-        # Assemble and return results
-        return self._assemble_results(graph, top_deps)
+            # Assemble and return results
+            return self._assemble_results(graph, top_deps)
 
     def _resolve_repository_urls(self, external_packages, url_cache=None):
         """
@@ -230,14 +696,42 @@ class DependencyAnalyzer:
             url_cache (dict): Optional URL cache
 
         Returns:
-            Dict of external_packages with 'repository_url' keys ensured
+            Dict of external_packages with 'repository_url' keys ensured, plus 'repository_subpath'
+            for Go modules that live in a subdirectory of their repository and 'private' for Go
+            modules matching GOPRIVATE
         """
         self.logger.info("... Resolving repository URLs for external packages")
+        resolution_cache = None
+        if CacheConfig.CACHE_DIR:
+            resolution_cache = ResolutionCache(CacheConfig.CACHE_DIR, CacheConfig.RESOLUTION_CACHE_TTL, self.logger)
         try:
-            resolved_urls = resolve_package_urls(external_packages, self.logger, cache=url_cache)
+            receipts = {}
+            resolved_urls = resolve_package_urls(
+                external_packages,
+                self.logger,
+                cache=url_cache,
+                receipts=receipts,
+                offline=NetworkConfig.OFFLINE,
+                resolution_cache=resolution_cache,
+                cancellation=self.cancellation,
+            )
+            if resolution_cache is not None:
+                resolution_cache.save()
+                self.logger.debug(
+                    f"Resolution cache: {resolution_cache.hits} hits, {resolution_cache.updates} entries updated"
+                )
             for package_name, url in resolved_urls.items():
                 if package_name in external_packages:
                     external_packages[package_name]["repository_url"] = url
+            for package_name, receipt in receipts.items():
+                if receipt and package_name in external_packages:
+                    # The in-repo directory describes the package rather than how its URL was found
+                    subpath = receipt.pop("repository_subpath", None)
+                    if subpath:
+                        external_packages[package_name]["repository_subpath"] = subpath
+                    if receipt.pop("private", False):
+                        external_packages[package_name]["private"] = True
+                    external_packages[package_name]["resolution"] = receipt
             for package_name in external_packages:
                 if "repository_url" not in external_packages[package_name]:
                     external_packages[package_name]["repository_url"] = ""
@@ -247,6 +741,384 @@ class DependencyAnalyzer:
                 external_packages[package_name].setdefault("repository_url", "")
         return external_packages
 
+    def _resolve_go_transitive(self, external_packages):
+        """
+        Add the transitive Go module closure when MAX_TRANSITIVE_DEPTH is set
+
+        Root go.mod requires keep the `direct` flag read from their `// indirect`
+        comments; modules reached only through other modules' go.mod files are added
+        with `direct: false`. Requires flagged indirect that no resolved module depends
+        on are logged
+
+        Args:
+            external_packages (dict): External packages mapping
+
+        Returns:
+            Dict of external_packages including transitively required Go modules
+        """
+        max_depth = GoAnalysisConfig.MAX_TRANSITIVE_DEPTH
+        root_requires = {
+            package_name: package_info["version"]
+            for package_name, package_info in external_packages.items()
+            if package_info.get("ecosystem") == "go" and package_info.get("version")
+        }
+        if max_depth <= 0 or not root_requires or is_cancelled(self.cancellation):
+            return external_packages
+
+        self.logger.info(f"... Resolving Go module requires up to depth {max_depth}")
+        replacements = {
+            package_name: package_info["replace"]
+            for package_name, package_info in external_packages.items()
+            if package_info.get("ecosystem") == "go" and package_info.get("replace")
+        }
+        modules = resolve_go_transitive(
+            root_requires,
+            max_depth,
+            replacements=replacements,
+            root_module=self.repo_analyzer.go_module_path,
+            offline=NetworkConfig.OFFLINE,
+            logger=self.logger,
+        )
+
+        if max_depth > 1:
+            self.go_require_graph = modules
+        # Without every go.mod the graph may miss edges, so only cross-check a complete one
+        complete = not any(node.get("fetch_reason") for node in modules.values())
+        added = 0
+        for module_path, node in modules.items():
+            package_info = external_packages.get(module_path)
+            if package_info is None:
+                package_info = {
+                    "ecosystem": "go",
+                    "version": node["version"],
+                    "module_path": module_path,
+                    "direct": False,
+                }
+                external_packages[module_path] = package_info
+                added += 1
+            flagged_indirect = module_path in root_requires and package_info.get("direct") is False
+            # Vendor-only records carry `explicit` from modules.txt instead of go.mod comments
+            package_info.setdefault("direct", package_info.get("explicit", True))
+            package_info["depth"] = node["depth"]
+            if node["required_by"]:
+                package_info["required_by"] = node["required_by"]
+            if node.get("fetch_reason"):
+                package_info["go_mod_reason"] = node["fetch_reason"]
+            if flagged_indirect and not node["required_by"] and max_depth > 1 and complete:
+                self.logger.warning(f"go.mod marks {module_path} as indirect, but no resolved module requires it")
+
+        self.logger.info(f"... Found {added} transitive Go modules")
+        return external_packages
+
+    def _resolve_go_require_graph(self, external_packages):
+        """
+        Read the go.mod of each required Go module when CHECK_PRUNABLE is set and the analysis is online
+
+        The edges between the requires are kept in go_require_graph for find_go_require_mismatches;
+        a MAX_TRANSITIVE_DEPTH walk of 2 or more levels already provides them
+
+        Args:
+            external_packages (dict): External packages mapping
+
+        Returns:
+            None
+        """
+        if not GoAnalysisConfig.CHECK_PRUNABLE or NetworkConfig.OFFLINE or self.go_require_graph is not None:
+            return
+        root_requires = {
+            package_name: package_info["version"]
+            for package_name, package_info in external_packages.items()
+            if package_info.get("ecosystem") == "go" and package_info.get("go_modules") and package_info.get("version")
+        }
+        if not root_requires or is_cancelled(self.cancellation):
+            return
+        self.logger.info(f"... Reading the go.mod of {len(root_requires)} required Go modules")
+        replacements = {
+            package_name: package_info["replace"]
+            for package_name, package_info in external_packages.items()
+            if package_info.get("ecosystem") == "go" and package_info.get("replace")
+        }
+        try:
+            self.go_require_graph = resolve_go_transitive(
+                root_requires,
+                2,
+                replacements=replacements,
+                root_module=self.repo_analyzer.go_module_path,
+                logger=self.logger,
+            )
+        except Exception as e:
+            self.logger.warning(f"Error while reading the go.mod of Go requires: {e}")
+
+    def _go_require_mismatches(self, results):
+        """
+        Add prunable_candidates, and imported-indirect warnings, when CHECK_PRUNABLE is set
+
+        Skipped for analyses that did not see every source file (--since, --files-from or a
+        cancelled run), whose imports cannot tell a module is unused
+
+        Args:
+            results (dict): Results being assembled, updated in place
+
+        Returns:
+            None
+        """
+        if not GoAnalysisConfig.CHECK_PRUNABLE:
+            return
+        if self.repo_analyzer.diff_scope or self.repo_analyzer.file_list_scope or results.get("partial"):
+            self.logger.info("... Not checking for prunable Go requires in a partial analysis")
+            return
+        candidates, mislabeled = find_go_require_mismatches(self.repo_analyzer.external_packages, self.go_require_graph)
+        for warning in mislabeled:
+            self.logger.warning(warning["message"])
+        if mislabeled:
+            results.setdefault("warnings", []).extend(mislabeled)
+        if candidates:
+            unverified = sum(1 for candidate in candidates if not candidate["verified"])
+            note = f" ({unverified} unverified without their go.mod files)" if unverified else ""
+            self.logger.info(f"... {len(candidates)} Go requires marked // indirect look prunable{note}")
+        results["prunable_candidates"] = candidates
+
+    def _attach_go_proxy_metadata(self, external_packages):
+        """
+        Look up the latest proxy version for Go modules without a pinned version
+
+        Args:
+            external_packages (dict): External packages mapping
+
+        Returns:
+            Dict of external_packages with latest_version/published_at where available
+        """
+        for package_name, package_info in external_packages.items():
+            if package_info.get("ecosystem") != "go" or package_info.get("version"):
+                continue
+            replace = package_info.get("replace")
+            if replace and replace.get("local"):
+                continue
+            if NetworkConfig.OFFLINE:
+                package_info.setdefault("resolution", {})["proxy_reason"] = "offline-skipped"
+                continue
+            if is_cancelled(self.cancellation):
+                package_info.setdefault("resolution", {})["proxy_reason"] = "cancelled"
+                continue
+            module_path = replace["path"] if replace else package_info.get("module_path") or package_name
+            reset_request_attempts()
+            metadata, reason = fetch_go_proxy_metadata(module_path, self.logger)
+            resolution = package_info.setdefault("resolution", {})
+            resolution["proxy_attempts"] = request_attempts()
+            if metadata:
+                package_info.update(metadata)
+            else:
+                resolution["proxy_reason"] = reason
+        return external_packages
+
+    def _attach_go_deprecations(self, external_packages):
+        """
+        Flag Go dependencies that are deprecated or pinned to a retracted version
+
+        Args:
+            external_packages (dict): External packages mapping
+
+        Returns:
+            Dict of external_packages with deprecated/deprecation_message where applicable
+            (see attach_go_deprecations)
+        """
+        if NetworkConfig.OFFLINE or not GoAnalysisConfig.CHECK_PROXY_DEPRECATIONS:
+            return external_packages
+        try:
+            flagged = attach_go_deprecations(external_packages, logger=self.logger, cancellation=self.cancellation)
+        except Exception as e:
+            self.logger.warning(f"Error during Go deprecation checks: {e}")
+            return external_packages
+        if flagged:
+            self.logger.warning(f"... {flagged} Go dependencies are deprecated or pinned to a retracted version")
+        return external_packages
+
+    def _attach_go_version_times(self, external_packages):
+        """
+        Record when the pinned version of each Go dependency was published (see attach_go_version_times)
+
+        Args:
+            external_packages (dict): External packages mapping
+
+        Returns:
+            Dict of external_packages with version_published_at/version_age_days where available
+        """
+        if not GoAnalysisConfig.CHECK_VERSION_TIMES:
+            return external_packages
+        resolution_cache = None
+        if CacheConfig.CACHE_DIR:
+            resolution_cache = ResolutionCache(CacheConfig.CACHE_DIR, CacheConfig.RESOLUTION_CACHE_TTL, self.logger)
+        try:
+            dated = attach_go_version_times(
+                external_packages,
+                logger=self.logger,
+                offline=NetworkConfig.OFFLINE,
+                cancellation=self.cancellation,
+                resolution_cache=resolution_cache,
+            )
+        except Exception as e:
+            self.logger.warning(f"Error during Go version time lookup: {e}")
+            return external_packages
+        if resolution_cache is not None:
+            resolution_cache.save()
+        if dated:
+            self.logger.info(f"... Found publish times for {dated} Go dependency versions")
+        return external_packages
+
+    def _attach_go_pseudo_commits(self, external_packages):
+        """
+        Verify the commits Go pseudo-versions pin against their GitHub repositories (see attach_go_pseudo_commits)
+
+        Args:
+            external_packages (dict): External packages mapping with repository URLs resolved
+
+        Returns:
+            Dict of external_packages with commit_sha/commit_date/commit_verified where looked up
+        """
+        if not GoAnalysisConfig.CHECK_PSEUDO_COMMITS:
+            return external_packages
+        resolution_cache = None
+        if CacheConfig.CACHE_DIR:
+            resolution_cache = ResolutionCache(CacheConfig.CACHE_DIR, CacheConfig.RESOLUTION_CACHE_TTL, self.logger)
+        try:
+            missing = attach_go_pseudo_commits(
+                external_packages,
+                logger=self.logger,
+                offline=NetworkConfig.OFFLINE,
+                cancellation=self.cancellation,
+                resolution_cache=resolution_cache,
+            )
+        except Exception as e:
+            self.logger.warning(f"Error during pseudo-version commit lookup: {e}")
+            return external_packages
+        if resolution_cache is not None:
+            resolution_cache.save()
+        for package_name in missing:
+            self.logger.warning(
+                f"{package_name} {external_packages[package_name].get('version')} pins a commit "
+                f"{external_packages[package_name].get('repository_url')} does not have"
+            )
+        verified = sum(1 for package_info in external_packages.values() if package_info.get("commit_verified"))
+        if verified:
+            self.logger.info(f"... Verified the commits of {verified} Go pseudo-versions")
+        return external_packages
+
+    def _attach_licenses(self, external_packages):
+        """
+        Look up the license of each package's GitHub repository
+
+        Args:
+            external_packages (dict): External packages mapping with repository URLs resolved
+
+        Returns:
+            Dict of external_packages with `license` set on every package (see resolve_licenses)
+        """
+        self.logger.info("... Looking up dependency licenses")
+        try:
+            return resolve_licenses(
+                external_packages, self.logger, offline=NetworkConfig.OFFLINE, cancellation=self.cancellation
+            )
+        except Exception as e:
+            self.logger.warning(f"Error during license lookup: {e}")
+            return external_packages
+
+    def _attach_default_branches(self, external_packages):
+        """
+        Look up the default branch of each package's GitHub repository (see resolve_default_branches)
+
+        Args:
+            external_packages (dict): External packages mapping with repository URLs resolved
+
+        Returns:
+            Dict of external_packages with default_branch, or default_branch_reason, on GitHub-hosted packages
+        """
+        resolution_cache = None
+        if CacheConfig.CACHE_DIR:
+            resolution_cache = ResolutionCache(CacheConfig.CACHE_DIR, CacheConfig.RESOLUTION_CACHE_TTL, self.logger)
+        try:
+            found = resolve_default_branches(
+                external_packages,
+                self.logger,
+                offline=NetworkConfig.OFFLINE,
+                cancellation=self.cancellation,
+                resolution_cache=resolution_cache,
+            )
+        except Exception as e:
+            self.logger.warning(f"Error during default branch lookup: {e}")
+            return external_packages
+        if resolution_cache is not None:
+            resolution_cache.save()
+        if found:
+            self.logger.info(f"... Found the default branch of {found} GitHub-hosted dependencies")
+        return external_packages
+
+    def _report_resolution_deadline(self, external_packages):
+        """
+        Warn about the packages the resolution deadline left unresolved
+        """
+        skipped = sorted(
+            name
+            for name, package_info in external_packages.items()
+            if package_info.get("resolution", {}).get("reason") == "resolution-deadline-exceeded"
+        )
+        if skipped:
+            self.logger.warning(
+                f"Resolution deadline of {NetworkConfig.RESOLUTION_DEADLINE:g}s exceeded: "
+                f"{len(skipped)} packages left unresolved",
+                event="resolution-deadline",
+                deadline=NetworkConfig.RESOLUTION_DEADLINE,
+                packages=len(skipped),
+            )
+
+    def list_packages(self, external_packages):
+        """
+        Finish a SERIALIZE_PACKAGES_ONLY analysis with the package list alone
+
+        Source files are still parsed, so the language filter, EXCLUDE_GENERATED and
+        INCLUDE_IGNORED_BUILD apply as in a full analysis and the tools `//go:generate`
+        directives and Makefiles run are listed, but nothing is resolved and no graph,
+        evidence or other results section is assembled
+
+        Args:
+            external_packages (dict): The external packages found by discover_packages
+
+        Returns:
+            Dict with keys: packages (see package_list), analyzer_details (total_files,
+            skipped_by_depth, languages_detected), errors when source files failed to read or
+            parse, analysis_scope for a --since or --files-from analysis, and partial: True
+            when the analysis was cancelled
+        """
+        self.repo_analyzer.external_packages = external_packages
+        with timed(self.timings, "parsing"):
+            try:
+                self.repo_analyzer.extract_imports_from_all_files()
+            finally:
+                self.repo_analyzer.remove_extracted_archives()
+        attach_go_generate_tools(external_packages, self.repo_analyzer.file_import_evidence)
+        attach_makefile_tools(external_packages, self.repo_analyzer.file_import_evidence)
+
+        source_files = self.repo_analyzer.source_files
+        results = {
+            "packages": package_list(external_packages),
+            "analyzer_details": {
+                "total_files": len(source_files),
+                "skipped_by_depth": self.repo_analyzer.skipped_by_depth,
+                "languages_detected": sorted({info.get("language", "unknown") for info in source_files.values()}),
+            },
+        }
+        if self.repo_analyzer.file_errors:
+            results["errors"] = list(self.repo_analyzer.file_errors)
+        if is_cancelled(self.cancellation):
+            results["partial"] = True
+        scope = {}
+        if self.repo_analyzer.file_list_scope:
+            scope = dict(self.repo_analyzer.file_list_scope, mode="files")
+        if self.repo_analyzer.diff_scope:
+            scope = dict(scope, **self.repo_analyzer.diff_scope, mode="diff")
+        if scope:
+            results["analysis_scope"] = scope
+        return results
+
     def analyze(self, repo_path, specific_languages=None, url_cache=None):
         """
         Analyze a repository and return the results as a data structure
@@ -262,20 +1134,49 @@ class DependencyAnalyzer:
             Dictionary containing:
                 - external_packages: Package metadata with URLs
                 - dependency_graph: NetworkX graph as dictionary
+                - import_graph: Package-level import nodes and edges
                 - top_dependencies: List of top dependencies with percentages
                 - analyzer_details: Additional analysis metadata
+            or, with SERIALIZE_PACKAGES_ONLY, the package list of list_packages
         """
-        # Step 1: Discover packages from manifests
-        external_packages = self.discover_packages(repo_path, specific_languages)
-
-        # Step 2: Resolve repository URLs for external packages
-        external_packages = self._resolve_repository_urls(external_packages, url_cache)
-
-        # Step 3: Analyze dependencies with resolved URLs
-        return self.analyze_dependencies(external_packages)
-
-
-def analyze_repository(repo_path, specific_languages=None, verbose=False, overrides=None, url_cache=None):
+        try:
+            # Step 1: Discover packages from manifests
+            external_packages = self.discover_packages(repo_path, specific_languages)
+            if cfg.SERIALIZE_PACKAGES_ONLY:
+                return self.list_packages(external_packages)
+
+            # Step 2: Resolve repository URLs for external packages
+            with timed(self.timings, "resolution"), resolution_deadline(NetworkConfig.RESOLUTION_DEADLINE):
+                external_packages = self._resolve_go_transitive(external_packages)
+                self._resolve_go_require_graph(external_packages)
+                external_packages = self._resolve_repository_urls(external_packages, url_cache)
+                external_packages = self._attach_go_proxy_metadata(external_packages)
+                external_packages = self._attach_go_deprecations(external_packages)
+                external_packages = self._attach_go_version_times(external_packages)
+                external_packages = self._attach_go_pseudo_commits(external_packages)
+                external_packages = self._attach_licenses(external_packages)
+                external_packages = self._attach_default_branches(external_packages)
+            self._report_resolution_deadline(external_packages)
+
+            # Step 3: Analyze dependencies with resolved URLs
+            return self.analyze_dependencies(external_packages)
+        finally:
+            if self.repo_analyzer:
+                self.repo_analyzer.remove_extracted_archives()
+
+
+def analyze_repository(
+    repo_path,
+    specific_languages=None,
+    verbose=False,
+    overrides=None,
+    url_cache=None,
+    on_file_evidence=None,
+    since=None,
+    cancellation=None,
+    timings=None,
+    files=None,
+):
     """
     Convenience function to analyze a repository
 
@@ -286,11 +1187,23 @@ def analyze_repository(repo_path, specific_languages=None, verbose=False, overri
         specific_languages (list): Optional list of languages to analyze
         verbose (bool): Enable verbose logging
         url_cache (dict): Optional pre-populated cache for package URLs
+        on_file_evidence (callable): Optional callback receiving each file's `file_evidence` record
+        since (str): Optional git ref; only source files changed since it are analyzed
+        cancellation (CancellationToken): Optional token that stops the analysis early, see DependencyAnalyzer
+        timings (PhaseTimings): Optional accumulator of phase durations, see DependencyAnalyzer
+        files (list): Optional source file paths to analyze instead of walking the tree, see DependencyAnalyzer
 
     Returns:
         Dictionary containing analysis results
     """
-    analyzer = DependencyAnalyzer(verbose=verbose)
+    analyzer = DependencyAnalyzer(
+        verbose=verbose,
+        on_file_evidence=on_file_evidence,
+        since=since,
+        cancellation=cancellation,
+        timings=timings,
+        files=files,
+    )
     # Prefer scoped overrides when provided to avoid global mutation during tests
     if overrides:
         with ConfigOverride(overrides, logger=analyzer.logger):
@@ -321,6 +1234,125 @@ def save_analysis_results(results, output_prefix, persistence, logger):
         return False
 
 
+def save_sbom(results, output_format, root_name, output_prefix, persistence, logger):
+    """
+    Render and save an SBOM of the analysis results
+
+    Args:
+        results (dict): Analysis results dictionary
+        output_format (str): SBOM format name (see gardener.analysis.sbom.SBOM_SUFFIXES)
+        root_name (str): Name of the analyzed project
+        output_prefix (str): Prefix for output files
+        persistence (object): Persistence backend to use
+        logger (Logger): Logger instance
+
+    Returns:
+        True if successful, False otherwise
+    """
+    try:
+        document = render_sbom(results, output_format, root_name)
+        persistence.save_sbom(document, output_prefix, SBOM_SUFFIXES[output_format])
+        return True
+    except Exception as e:
+        logger.error(f"Error saving {output_format} SBOM: {str(e)}")
+        return False
+
+
+def save_package_list(packages, output_prefix, persistence, logger):
+    """
+    Save the package list of a --packages-only analysis
+
+    Args:
+        packages (list): Package list (see package_list)
+        output_prefix (str): Prefix for output files
+        persistence (object): Persistence backend to use
+        logger (Logger): Logger instance
+
+    Returns:
+        True if successful, False otherwise
+    """
+    try:
+        persistence.save_packages(packages, output_prefix, PACKAGES_SUFFIX)
+        return True
+    except Exception as e:
+        logger.error(f"Error saving package list: {str(e)}")
+        return False
+
+
+def save_markdown_report(results, root_name, output_prefix, persistence, logger):
+    """
+    Render and save the Markdown dependency report
+
+    Args:
+        results (dict): Analysis results dictionary
+        root_name (str): Name of the analyzed project
+        output_prefix (str): Prefix for output files
+        persistence (object): Persistence backend to use
+        logger (Logger): Logger instance
+
+    Returns:
+        True if successful, False otherwise
+    """
+    try:
+        persistence.save_report(render_markdown_report(results, root_name), output_prefix, MARKDOWN_SUFFIX)
+        return True
+    except Exception as e:
+        logger.error(f"Error saving Markdown report: {str(e)}")
+        return False
+
+
+def save_csv(results, output_prefix, persistence, logger, delimiter=","):
+    """
+    Render and save the detected dependencies as CSV (or TSV)
+
+    Args:
+        results (dict): Analysis results dictionary
+        output_prefix (str): Prefix for output files
+        persistence (object): Persistence backend to use
+        logger (Logger): Logger instance
+        delimiter (str): Single-character field separator
+
+    Returns:
+        True if successful, False otherwise
+    """
+    try:
+        content = to_csv(results, delimiter=delimiter)
+        persistence.save_csv(content, output_prefix, CSV_SUFFIXES.get(delimiter, DEFAULT_CSV_SUFFIX))
+        return True
+    except Exception as e:
+        logger.error(f"Error saving CSV export: {str(e)}")
+        return False
+
+
+def save_timings(section, path, logger):
+    """
+    Write a timings section to its own JSON file
+
+    Args:
+        section (dict): Timings section (see PhaseTimings.section)
+        path (str): Output file path
+        logger (Logger): Logger instance
+
+    Returns:
+        True if successful, False otherwise
+    """
+    try:
+        with open(path, "w", encoding="utf-8") as f:
+            json.dump(section, f, indent=2)
+        logger.info(f"Timings saved to: {path}")
+        return True
+    except Exception as e:
+        logger.error(f"Error saving timings: {str(e)}")
+        return False
+
+
+def _enclosing_go_mod_path(results):
+    """
+    Return the path of the go.mod above the analyzed directory the results used, if any
+    """
+    return (results.get("enclosing_go_mod") or {}).get("path")
+
+
 def _maybe_generate_graph_viz(results, output_prefix, persistence, logger):
     """
     If dependency graph present, generate graph HTML and save via persistence
@@ -369,18 +1401,38 @@ def generate_and_save_visualizations(results, output_prefix, persistence, logger
         return False
 
 
-def _prepare_repository_path(repo_path, logger):
+def _prepare_repository_path(repo_path, logger, offline=False, checkout=None, branch=None):
     """
     Clone or resolve local repo path, return absolute path
 
+    Repository URLs are cloned into a temporary directory (NetworkConfig.CLONE_DEPTH
+    commits deep), and source archives extracted into one; either is removed when
+    `checkout` is closed
+
     Args:
-        repo_path (str): Repository path or URL
+        repo_path (str): Repository path, archive path, or URL optionally suffixed with "@ref"
         logger (Logger): Logger instance
+        offline (bool): Refuse to clone remote repositories
+        checkout (contextlib.ExitStack|None): Owns the temporary clone or extracted archive; without
+            one URLs are cloned persistently under input/ and archives are rejected
+        branch (str|None): Branch, tag or commit to check out from a repository URL
 
     Returns:
         str: Absolute repository path
+
+    Raises:
+        RepositoryError: If a branch is given for a local path
     """
-    repo_path = get_repo(repo_path)
+    if is_repository_url(repo_path) and checkout is not None:
+        repo_path = checkout.enter_context(
+            cloned_repository(repo_path, depth=NetworkConfig.CLONE_DEPTH, ref=branch, offline=offline)
+        )
+    elif branch:
+        raise RepositoryError(f"--branch {branch} only applies to repository URLs, not to the local path {repo_path}")
+    elif archive_format(repo_path) and checkout is not None:
+        repo_path = checkout.enter_context(extracted_archive(repo_path))
+    else:
+        repo_path = get_repo(repo_path, offline=offline)
     abs_path = os.path.abspath(repo_path)
     return abs_path
 
@@ -390,15 +1442,17 @@ def _parse_focus_languages(focus_languages_str, logger):
     Parse comma-separated focus languages into a normalized list or None
 
     Args:
-        focus_languages_str (str): Comma-separated languages
+        focus_languages_str (str): Comma-separated languages or aliases (see language_detection.LANGUAGE_ALIASES)
         logger (Logger): Logger instance
 
     Returns:
         list|None: List of normalized languages, or None
+
+    Raises:
+        ValueError: If a language is not supported
     """
-    focus_languages = None
-    if focus_languages_str:
-        focus_languages = [lang.strip().lower() for lang in focus_languages_str.split(",")]
+    focus_languages = parse_language_filter(focus_languages_str)
+    if focus_languages:
         logger.info(f"Focusing analysis on languages: {focus_languages}")
     return focus_languages
 
@@ -432,7 +1486,7 @@ def _determine_output_prefix(abs_path, output_prefix):
     return output_prefix
 
 
-def _persist_and_visualize(results, output_prefix, persistence, logger, minimal_outputs):
+def _persist_and_visualize(results, output_prefix, persistence, logger, minimal_outputs, document=None):
     """
     Save analysis results and generate visualizations (delegates to existing functions)
 
@@ -442,8 +1496,9 @@ def _persist_and_visualize(results, output_prefix, persistence, logger, minimal_
         persistence (object): Persistence backend
         logger (Logger): Logger instance
         minimal_outputs (bool): Whether to skip visualizations
+        document (dict|None): The results as saved, e.g. grouped by ecosystem; defaults to results
     """
-    save_success = save_analysis_results(results, output_prefix, persistence, logger)
+    save_success = save_analysis_results(results if document is None else document, output_prefix, persistence, logger)
     if not save_success:
         logger.error("Failed to save analysis results")
 
@@ -474,6 +1529,70 @@ def _report_top_dependencies(results, logger):
         logger.info("\nNo dependencies were found, or calculation failed")
 
 
+def _archive_root(archive_path):
+    """
+    Describe the source archive an analysis root was extracted from
+
+    Args:
+        archive_path (str): Archive path as given to the run
+
+    Returns:
+        dict: The `analysis_root` record: {"name", "archive", "format"}
+    """
+    return {
+        "name": archive_root_name(archive_path),
+        "archive": os.path.basename(archive_path),
+        "format": archive_format(archive_path),
+    }
+
+
+def _analyze_roots(targets, options, tool_options, analyzed_at, config_overrides, checkout, logger):
+    """
+    Analyze several repository roots one after another and combine their results
+
+    Each root is cloned or extracted like a single one, and named after its directory
+    or archive (see root_names). Once an interrupt leaves a root's analysis partial,
+    the roots after it are not analyzed
+
+    Args:
+        targets (list): Repository paths, archives or URLs, in order
+        options (AnalysisOptions): Options shared by the analyses of the roots
+        tool_options (dict): Run options recorded in the tool section
+        analyzed_at (str): Start of the run, see analysis_timestamp
+        config_overrides (dict): Configuration overrides of the run
+        checkout (contextlib.ExitStack): Owns the temporary clones and extracted archives
+        logger (Logger): Logger instance
+
+    Returns:
+        tuple: (combined results, see combine_root_results; root names in order)
+    """
+    # Imported here because gardener.api builds on this module
+    from gardener.api import analyze_repo
+
+    offline = bool((config_overrides or {}).get("OFFLINE", NetworkConfig.OFFLINE))
+    analyzed = []
+    for target in targets:
+        with ConfigOverride(dict(config_overrides or {})):
+            abs_path = _prepare_repository_path(target, logger, offline=offline, checkout=checkout)
+        logger.info(f"Analyzing repository: {abs_path}")
+        with cancel_on_interrupt(options.cancellation, logger):
+            results = analyze_repo(abs_path, options).raw
+        results["tool"] = tool_section(
+            target, abs_path, analyzed_at, tool_options, config_overrides, _enclosing_go_mod_path(results)
+        )
+        label = os.path.basename(abs_path.rstrip("/"))
+        if archive_format(target):
+            results["analysis_root"] = _archive_root(target)
+            label = results["analysis_root"]["name"]
+        analyzed.append((label, results))
+        if results.get("partial"):
+            break
+    names = root_names([label for label, _ in analyzed])
+    combined = combine_root_results(list(zip(names, (results for _, results in analyzed))), logger)
+    logger.info(f"Combined the results of {len(names)} repository roots: {', '.join(names)}")
+    return combined, names
+
+
 def run_analysis(
     repo_path,
     output_prefix=None,
@@ -482,43 +1601,233 @@ def run_analysis(
     focus_languages_str=None,
     config_overrides=None,
     persistence=None,
+    output_format="json",
+    csv_delimiter=",",
+    since=None,
+    branch=None,
+    timings=False,
+    timings_output=None,
+    destinations=None,
+    sbom_format="cyclonedx",
+    webhook_url=None,
+    webhook_headers=None,
+    require_webhook=False,
+    baseline=None,
+    files=None,
+    ndjson_stdout=False,
 ):
     """
     Run the full dependency analysis with the specified persistence backend
 
     Args:
-        repo_path (str): Local path to the repo or URL of hosted git repo
+        repo_path (str|list): Local path to the repo or a source archive, or URL of hosted git repo; or a
+            list of them to analyze together
         output_prefix (str): Prefix for output files
         verbose (bool): Whether to enable verbose logging
         minimal_outputs (bool): Whether to skip visualization generation
         focus_languages_str (str): Comma-separated list of languages to focus on
         config_overrides (dict): Optional dictionary of configuration parameter overrides
-        persistence (object): Persistence backend to use (defaults to FilePersistence)
+        persistence (object): Persistence backend to use (defaults to FilePersistence, compressed with
+            SERIALIZE_COMPRESS)
+        output_format (str): "json" for analysis results only, or "csv", "ndjson", "markdown" or an SBOM
+            format ("cyclonedx", "spdx") written alongside them
+        csv_delimiter (str): Field separator for the "csv" format ("\t" writes TSV)
+        since (str): Optional git ref; analyze only the source files changed between it and HEAD
+        branch (str): Branch, tag or commit to check out when repo_path is a URL (same as a "@ref" suffix)
+        timings (bool): Record per-phase durations
+        timings_output (str): Optional path of a JSON file receiving the timings instead of the results
+        destinations (dict): Optional destination kind ("table", "json", "csv", "sbom") -> file path, or "-"
+            for stdout
+        sbom_format (str): SBOM format of the "sbom" destination ("cyclonedx" or "spdx")
+        webhook_url (str): Optional http(s) URL receiving the results once the analysis completes
+        webhook_headers (list): (name, value) pairs sent with the webhook request, e.g. for auth
+        require_webhook (bool): Raise WebhookError when the results could not be delivered
+        baseline (dict): Optional earlier analysis results to compare with (see load_result_file)
+        files (list): Optional source file paths, relative to the repository root; only they are analyzed
+        ndjson_stdout (bool): With the "ndjson" format, also stream the records to stdout as they are written
 
     Returns:
         Dict of analysis results
+
+    Raises:
+        RepositoryError: When options needing a single repository are given several
+        WebhookError: With require_webhook, when the webhook delivery failed
+        OutputValidationError: With SERIALIZE_VALIDATE, when the analysis JSON does not match its schema
+            (before any of it is written)
     """
     logger = Logger(verbose=verbose)
 
+    compress = bool((config_overrides or {}).get("SERIALIZE_COMPRESS", cfg.SERIALIZE_COMPRESS))
     # Use default file persistence if none provided
     if persistence is None:
-        persistence = FilePersistence()
+        persistence = FilePersistence(compress=compress)
 
+    phase_timings = PhaseTimings() if timings or timings_output else None
+    analyzed_at = analysis_timestamp()
+    checkout = contextlib.ExitStack()
     try:
-        abs_path = _prepare_repository_path(repo_path, logger)
-        logger.info(f"Analyzing repository: {abs_path}")
+        offline = bool((config_overrides or {}).get("OFFLINE", NetworkConfig.OFFLINE))
+        targets = [repo_path] if isinstance(repo_path, str) else list(repo_path)
+        packages_only = (config_overrides or {}).get("SERIALIZE_PACKAGES_ONLY", cfg.SERIALIZE_PACKAGES_ONLY)
+        if len(targets) > 1 and (output_format == "ndjson" or since or branch or files is not None or packages_only):
+            raise RepositoryError(
+                "--format ndjson, --since, --branch, --files-from and --packages-only apply to a single repository"
+            )
+
+        # Imported here because gardener.api builds on this module
+        from gardener.api import AnalysisOptions, analyze_repo
 
         focus_languages = _parse_focus_languages(focus_languages_str, logger)
         # Use scoped overrides for the run to avoid global state bleed-through
-        results = analyze_repository(
-            repo_path=abs_path, specific_languages=focus_languages, verbose=verbose, overrides=config_overrides
+        options = AnalysisOptions(
+            languages=focus_languages,
+            config_overrides=dict(config_overrides or {}),
+            since=since,
+            files=files,
+            verbose=verbose,
+            cancellation=CancellationToken(),
+            timings=phase_timings,
         )
+        tool_options = {
+            "format": output_format,
+            "languages": focus_languages,
+            "since": since,
+            "branch": branch,
+            "files": len(files) if files is not None else None,
+            "baseline": True if baseline is not None else None,
+        }
+        if len(targets) > 1:
+            results, names = _analyze_roots(
+                targets, options, tool_options, analyzed_at, config_overrides, checkout, logger
+            )
+            output_prefix = output_prefix or default_output_prefix(names)
+            root_name = ", ".join(names)
+        else:
+            # Clone depth and auth token come from the run's overrides
+            with ConfigOverride(dict(config_overrides or {})):
+                abs_path = _prepare_repository_path(
+                    repo_path, logger, offline=offline, checkout=checkout, branch=branch
+                )
+            logger.info(f"Analyzing repository: {abs_path}")
+            output_prefix = _determine_output_prefix(abs_path, output_prefix)
+            root_name = os.path.basename(abs_path.rstrip("/"))
+            if output_format == "ndjson":
+                # File records are written as extraction proceeds; packages and the summary follow at the end
+                with persistence.open_stream(output_prefix, NDJSON_SUFFIX) as stream:
+                    writer = NDJSONStreamWriter(
+                        stream, keep_lines=bool(webhook_url), mirror=sys.stdout if ndjson_stdout else None
+                    )
+                    options.on_file_evidence = writer.write
+                    with cancel_on_interrupt(options.cancellation, logger):
+                        results = analyze_repo(abs_path, options).raw
+                    results["tool"] = tool_section(
+                        repo_path,
+                        abs_path,
+                        analyzed_at,
+                        tool_options,
+                        config_overrides,
+                        _enclosing_go_mod_path(results),
+                    )
+                    writer.write_results(results)
+                logger.info(
+                    f"Streamed {writer.lines_written} NDJSON records to: "
+                    f"{persistence.get_output_path(output_prefix, NDJSON_SUFFIX)}"
+                )
+            else:
+                with cancel_on_interrupt(options.cancellation, logger):
+                    results = analyze_repo(abs_path, options).raw
+                results["tool"] = tool_section(
+                    repo_path, abs_path, analyzed_at, tool_options, config_overrides, _enclosing_go_mod_path(results)
+                )
+            if archive_format(repo_path):
+                results["analysis_root"] = _archive_root(repo_path)
+
+        if baseline is not None:
+            results["baseline_diff"] = diff_results(baseline, results)
+            logger.info(f"Changes since the baseline: {render_diff_summary(results['baseline_diff']).rstrip()}")
+        sort_keys = (config_overrides or {}).get("SERIALIZE_SORT_KEYS", cfg.SERIALIZE_SORT_KEYS)
+        with timed(phase_timings, "serialization"):
+            if sort_keys:
+                results = canonical_results(results)
+        if phase_timings is not None and not timings_output:
+            results["timings"] = phase_timings.section(results["analyzer_details"]["total_files"])
+            if sort_keys:
+                results = dict(sorted(results.items()))
+
+        if results.get("partial"):
+            logger.warning("Partial analysis: interrupted before every file and package was analyzed")
+        scope = results.get("analysis_scope")
+        if scope and scope["mode"] == "diff":
+            logger.warning(
+                f"Partial analysis: only the {results['analyzer_details']['total_files']} source files "
+                f"changed since {scope['since']} were analyzed"
+            )
+        elif scope:
+            logger.warning(
+                f"Partial analysis: only the {results['analyzer_details']['total_files']} listed source files "
+                f"were analyzed"
+            )
 
-        output_prefix = _determine_output_prefix(abs_path, output_prefix)
-        _persist_and_visualize(results, output_prefix, persistence, logger, minimal_outputs)
-        _report_top_dependencies(results, logger)
+        with timed(phase_timings, "serialization"):
+            if packages_only:
+                document = results["packages"]
+                if not save_package_list(document, output_prefix, persistence, logger):
+                    logger.error("Failed to save package list")
+            else:
+                document = results
+                if (config_overrides or {}).get("SERIALIZE_ONLY_UNRESOLVED", cfg.SERIALIZE_ONLY_UNRESOLVED):
+                    document = only_unresolved(results)
+                if not (config_overrides or {}).get("SERIALIZE_FLAT_PACKAGES", cfg.SERIALIZE_FLAT_PACKAGES):
+                    document = group_by_ecosystem(document)
+                    if sort_keys:
+                        document = dict(sorted(document.items()))
+                if (config_overrides or {}).get("SERIALIZE_VALIDATE", cfg.SERIALIZE_VALIDATE):
+                    check_output(document)
+                _persist_and_visualize(results, output_prefix, persistence, logger, minimal_outputs, document)
+            if output_format == "csv":
+                if not save_csv(results, output_prefix, persistence, logger, delimiter=csv_delimiter):
+                    logger.error("Failed to save CSV export")
+            elif output_format == "markdown":
+                if not save_markdown_report(results, root_name, output_prefix, persistence, logger):
+                    logger.error("Failed to save Markdown report")
+            elif output_format not in ("json", "ndjson"):
+                if not save_sbom(results, output_format, root_name, output_prefix, persistence, logger):
+                    logger.error(f"Failed to save {output_format} SBOM")
+            if destinations:
+                write_destinations(
+                    results,
+                    destinations,
+                    root_name,
+                    logger,
+                    sbom_format=sbom_format,
+                    csv_delimiter=csv_delimiter,
+                    json_results=document,
+                    compress=compress,
+                )
+        if timings_output:
+            save_timings(phase_timings.section(results["analyzer_details"]["total_files"]), timings_output, logger)
+        if packages_only:
+            logger.info(f"Listed {len(results['packages'])} packages")
+        else:
+            _report_top_dependencies(results, logger)
+        if webhook_url:
+            if output_format == "ndjson":
+                body, body_format = "".join(writer.lines), "ndjson"
+            else:
+                body, body_format = render_destination("json", results, root_name, json_results=document), "json"
+            try:
+                status = deliver_webhook(webhook_url, body, body_format, webhook_headers, logger)
+                logger.info(f"Delivered results to webhook {webhook_url} (HTTP {status})")
+            except WebhookError as e:
+                logger.error(str(e))
+                if require_webhook:
+                    raise
         return results
 
+    except (WebhookError, OutputValidationError):
+        raise
     except Exception as e:
         logger.error(f"Analysis failed: {e}")
         raise
+    finally:
+        checkout.close()
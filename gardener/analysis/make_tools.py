@@ -0,0 +1,50 @@
+"""
+Go tools installed or run from Makefiles
+
+`go install <package>@<version>` and `go run <package>` lines of Makefiles
+(see gardener.treewalk.makefile) name development tools that no source file
+imports. Each is recorded on the Go module providing it, found as for
+`go run` in `//go:generate` directives (see go_run_module), as a dependency
+scoped "tool"
+"""
+
+from gardener.analysis.go_generate import go_run_module
+
+
+def attach_makefile_tools(external_packages, file_import_evidence):
+    """
+    Record the Go tools installed or run by Makefiles as dependencies
+
+    Every package gets a `make` list of {"file", "line", "command"} sorted by file
+    and line. Packages already detected, e.g. a tool module required in go.mod,
+    keep their metadata and are scoped "tool" only when no import scoped them;
+    modules not yet known are added with scope "tool" and the version of the
+    first `@version` given for them
+
+    Args:
+        external_packages (dict): External packages mapping, updated in place
+        file_import_evidence (dict): Repo-relative file path -> evidence entries
+
+    Returns:
+        list: Keys of the packages added
+    """
+    added = []
+    for rel_path in sorted(file_import_evidence):
+        for entry in file_import_evidence[rel_path]:
+            if entry.get("kind") not in ("go-install", "go-run") or entry.get("scope") != "tool":
+                continue
+            module_path, repository_url = go_run_module(entry["import"], external_packages)
+            if module_path not in external_packages:
+                info = {"ecosystem": "go", "scope": "tool"}
+                if repository_url:
+                    info["repository_url"] = repository_url
+                external_packages[module_path] = info
+                added.append(module_path)
+            package_info = external_packages[module_path]
+            if module_path in added and entry.get("version") and not package_info.get("version"):
+                package_info["version"] = entry["version"]
+            package_info.setdefault("scope", "tool")
+            package_info.setdefault("make", []).append(
+                {"file": rel_path, "line": entry["line"], "command": entry["command"]}
+            )
+    return added
@@ -10,10 +10,14 @@ import os
 import re
 from pathlib import Path
 
+from gardener.common.file_helpers import to_posix_path
 from gardener.package_metadata.name_resolvers.go import GoResolver
 from gardener.package_metadata.name_resolvers.json_manifest import JsonManifestResolver
 from gardener.package_metadata.name_resolvers.python import PythonResolver
 from gardener.package_metadata.name_resolvers.rust import RustResolver
+from gardener.package_metadata.url_resolver import go_semver_key, resolve_go_package
+from gardener.treewalk.base import is_manifest_name
+from gardener.treewalk.go import find_go_replacement, parse_go_mod_file
 
 
 def _read_file(path, secure_file_ops):
@@ -114,6 +118,66 @@ def collect_root_package_names_and_workspaces(root_manifest_files, secure_file_o
     return root_names, go_module_path
 
 
+def find_enclosing_go_mod(repo_path):
+    """
+    Find the go.mod of the module an analyzed subdirectory belongs to
+
+    Walks up from the parent of repo_path and stops at the first go.mod, or
+    without one after the root of a git work tree (a directory holding `.git`)
+    or a mount point. A repo_path that is itself a work tree root has no
+    enclosing module
+
+    Args:
+        repo_path (str): Absolute path of the analyzed directory
+
+    Returns:
+        str|None: Absolute path of the nearest go.mod above repo_path
+    """
+    current = os.path.abspath(repo_path)
+    if os.path.exists(os.path.join(current, ".git")) or os.path.ismount(current):
+        return None
+    while True:
+        parent = os.path.dirname(current)
+        if parent == current:
+            return None
+        current = parent
+        go_mod = os.path.join(current, "go.mod")
+        if os.path.isfile(go_mod):
+            return go_mod
+        if os.path.exists(os.path.join(current, ".git")) or os.path.ismount(current):
+            return None
+
+
+def read_go_toolchain(root_manifest_files, secure_file_ops, logger):
+    """
+    Read the Go version requirements declared by the root go.mod
+
+    Args:
+        root_manifest_files (list): Absolute paths to manifests in repo root
+        secure_file_ops (SecureFileOps|None): Secure file ops or None
+        logger (Logger|None): Optional logger for warnings
+
+    Returns:
+        dict|None: {"go_version", ["toolchain"]}, or None without a root go.mod `go` directive
+    """
+    for manifest_file in root_manifest_files:
+        if Path(manifest_file).name != "go.mod":
+            continue
+        try:
+            go_mod = parse_go_mod_file(_read_file(manifest_file, secure_file_ops))
+        except Exception as exc:
+            if logger:
+                logger.warning(f"Could not read Go version from {manifest_file}: {exc}")
+            return None
+        if not go_mod["go"]:
+            return None
+        toolchain = {"go_version": go_mod["go"]}
+        if go_mod["toolchain"]:
+            toolchain["toolchain"] = go_mod["toolchain"]
+        return toolchain
+    return None
+
+
 def _get_package_name_from_manifest(path, basename, secure_file_ops, logger, repo_path):
     """
     Extract a canonical package or module name from a manifest
@@ -173,9 +237,10 @@ def process_manifests(manifest_files, language_handlers, secure_file_ops, logger
     external_packages = {}
 
     for manifest_path in list(manifest_files):
-        basename = Path(manifest_path).name
         for handler_lang, handler in language_handlers.items():
-            if basename not in handler.get_manifest_files():
+            if not hasattr(handler, "process_manifest") or not is_manifest_name(
+                manifest_path, handler.get_manifest_files()
+            ):
                 continue
             try:
                 temp_packages = {}
@@ -189,7 +254,7 @@ def process_manifests(manifest_files, language_handlers, secure_file_ops, logger
                             manifest_path,
                         )
                     else:
-                        package_info["found_in_manifests"] = [manifest_path]
+                        package_info["found_in_manifests"] = [to_posix_path(manifest_path)]
                         external_packages[package_name] = package_info
             except Exception as exc:
                 if logger:
@@ -214,7 +279,10 @@ def _deduplicate_package(package_name, existing_package, new_package_info, manif
     """
     if "found_in_manifests" not in existing_package:
         existing_package["found_in_manifests"] = []
-    existing_package["found_in_manifests"].append(manifest_path)
+    manifest_path = to_posix_path(manifest_path)
+    # Several handlers read the same manifest (package.json); list each file once
+    if manifest_path not in existing_package["found_in_manifests"]:
+        existing_package["found_in_manifests"].append(manifest_path)
 
     existing_version = existing_package.get("version", "")
     new_version = new_package_info.get("version", "")
@@ -243,10 +311,203 @@ def _deduplicate_package(package_name, existing_package, new_package_info, manif
             continue
         if key not in existing_package:
             existing_package[key] = value
+    # Repository go.mod modules requiring the same Go module
+    for module_path in new_package_info.get("go_modules", []):
+        if module_path not in existing_package["go_modules"]:
+            existing_package["go_modules"].append(module_path)
+    # A Go module any go.mod requires without `// indirect` was chosen by a maintainer
+    if new_package_info.get("direct"):
+        existing_package["direct"] = True
 
     return existing_package
 
 
+def apply_go_workspace(external_packages, workspace_modules, workspace_replacements, logger):
+    """
+    Reconcile Go packages with a go.work workspace
+
+    Modules that are members of the workspace are local code, not dependencies,
+    and go.work replace directives override any replace from the go.mod files
+
+    Args:
+        external_packages (dict): Package metadata map keyed by distribution name
+        workspace_modules (iterable): Module paths listed by go.work `use` directives
+        workspace_replacements (dict): go.work replace map as returned by parse_go_work
+        logger (Logger|None): Optional logger
+
+    Returns:
+        dict: Package metadata map without workspace members
+    """
+    for module_path in workspace_modules:
+        if module_path in external_packages and external_packages[module_path].get("ecosystem") == "go":
+            del external_packages[module_path]
+            if logger:
+                logger.debug(f"Treating Go workspace module '{module_path}' as local")
+
+    for package_name, package_info in external_packages.items():
+        if package_info.get("ecosystem") != "go":
+            continue
+        replacement = find_go_replacement(package_name, package_info.get("version", ""), workspace_replacements)
+        if replacement:
+            package_info["replace"] = dict(replacement)
+    return external_packages
+
+
+def apply_go_local_replacements(external_packages, replacement_dirs, go_modules, repo_path, logger):
+    """
+    Record where locally replaced Go modules are taken from
+
+    Every Go package replaced by a directory (`replace github.com/org/lib => ../lib`)
+    gets `replacement_path`, the directory relative to the repository root, and
+    `replacement_missing: true` when that directory does not exist. A replacement
+    directory holding one of the repository's go.mod files for the same module
+    makes the module local code, like a go.work member
+
+    Args:
+        external_packages (dict): Package metadata map keyed by distribution name, updated in place
+        replacement_dirs (dict): Replaced module path -> absolute replacement directory
+        go_modules (dict): Declared module path -> repo-relative directory of every go.mod found
+        repo_path (str): Absolute repository path
+        logger (Logger|None): Optional logger
+
+    Returns:
+        dict: Module path -> repo-relative directory of the replacements that are repository modules
+    """
+    local_modules = {}
+    for module_path, replacement_dir in sorted(replacement_dirs.items()):
+        package_info = external_packages.get(module_path)
+        if not package_info or package_info.get("ecosystem") != "go":
+            continue
+        rel_dir = to_posix_path(os.path.relpath(replacement_dir, repo_path))
+        package_info["replacement_path"] = rel_dir
+        if not os.path.isdir(replacement_dir):
+            package_info["replacement_missing"] = True
+            if logger:
+                logger.warning(f"Go module '{module_path}' is replaced by {rel_dir}, which does not exist")
+        elif go_modules.get(module_path) == rel_dir:
+            local_modules[module_path] = rel_dir
+    return local_modules
+
+
+def go_replace_identities(package_name, package_info):
+    """
+    Return both identities of a Go module a `replace` redirects
+
+    Source files keep importing the original module path while the code comes
+    from the replacement, e.g. a fork. The import side is the module path with
+    the repository URL its path gives without any lookup (only hosts like
+    github.com, where the path names the repository); the resolved side is the
+    replacement module, its version and the resolved repository URL, or the
+    repo-relative directory of a local replacement, which has no URL
+
+    Args:
+        package_name (str): Package key, the original Go module path
+        package_info (dict): Package metadata
+
+    Returns:
+        dict: {"import_path", ["import_url"], "resolved_module", ["resolved_version"], "resolved_url"}
+            for a replaced Go module, else {}
+    """
+    replace = package_info.get("replace")
+    if package_info.get("ecosystem") != "go" or not replace:
+        return {}
+    import_path = package_info.get("module_path") or package_name
+    identities = {"import_path": import_path}
+    import_url = resolve_go_package(import_path, module_path=import_path, offline=True)
+    if import_url:
+        identities["import_url"] = import_url
+    if replace.get("local"):
+        identities["resolved_module"] = package_info.get("replacement_path") or replace["path"]
+        identities["resolved_url"] = None
+        return identities
+    identities["resolved_module"] = replace["path"]
+    if replace.get("version"):
+        identities["resolved_version"] = replace["version"]
+    identities["resolved_url"] = package_info.get("repository_url") or None
+    return identities
+
+
+def add_git_submodules(external_packages, submodules, repo_path, logger):
+    """
+    Add the repository's git submodules as dependencies
+
+    Each submodule becomes a `git-submodule` package named after its path, with
+    `submodule_path`, `ref` (the .gitmodules `branch`, or None) and the declared
+    URL as `gitmodules_url`, from which its repository_url is resolved
+
+    Args:
+        external_packages (dict): Package metadata map keyed by distribution name, updated in place
+        submodules (list): {"path", "url", "branch"} entries from scanner.read_gitmodules
+        repo_path (str): Absolute repository path
+        logger (Logger|None): Optional logger
+
+    Returns:
+        dict: The updated external_packages
+    """
+    gitmodules_path = to_posix_path(os.path.join(repo_path, ".gitmodules"))
+    for submodule in submodules:
+        path = submodule["path"]
+        if not path or path in external_packages:
+            continue
+        external_packages[path] = {
+            "ecosystem": "git-submodule",
+            "submodule_path": path,
+            "gitmodules_url": submodule["url"],
+            "ref": submodule.get("branch"),
+            "found_in_manifests": [gitmodules_path],
+        }
+    if submodules and logger:
+        logger.info(f"... Found {len(submodules)} git submodules in .gitmodules")
+    return external_packages
+
+
+def add_go_sum_only_modules(external_packages, go_sum_modules, ignored_modules, logger):
+    """
+    Add the modules go.sum files list but no manifest requires as Go dependencies
+
+    go.sum keeps hashes of modules an earlier build used after `go mod tidy` drops
+    their require. Each becomes a package with `source: "go.sum-only"`, `direct: False`
+    and, as its representative version, the highest one whose code go.sum hashes (the
+    highest version listed when only go.mod files are hashed), with that version's checksum
+
+    Args:
+        external_packages (dict): Package metadata map keyed by distribution name, updated in place
+        go_sum_modules (dict): Absolute go.sum path -> {module path: {version: {"checksum", "zip"}}}
+            (see parse_go_sum_modules)
+        ignored_modules (set): Module paths never added: the repository's own modules and replacement targets
+        logger (Logger|None): Optional logger
+
+    Returns:
+        dict: The updated external_packages
+    """
+    added = {}
+    for go_sum_path in sorted(go_sum_modules):
+        manifest = to_posix_path(go_sum_path)
+        for module_path, versions in go_sum_modules[go_sum_path].items():
+            if module_path in ignored_modules or (module_path in external_packages and module_path not in added):
+                continue
+            downloaded = [version for version, entry in versions.items() if entry["zip"]]
+            version = max(downloaded or versions, key=go_semver_key)
+            package_info = added.get(module_path)
+            if package_info is None:
+                package_info = added[module_path] = external_packages[module_path] = {
+                    "ecosystem": "go",
+                    "version": version,
+                    "module_path": module_path,
+                    "direct": False,
+                    "source": "go.sum-only",
+                    "checksum": versions[version]["checksum"],
+                    "found_in_manifests": [manifest],
+                }
+                continue
+            package_info["found_in_manifests"].append(manifest)
+            if go_semver_key(version) > go_semver_key(package_info["version"]):
+                package_info.update(version=version, checksum=versions[version]["checksum"])
+    if added and logger:
+        logger.info(f"... Found {len(added)} Go modules listed only in go.sum")
+    return external_packages
+
+
 def attach_import_names(external_packages, secure_file_ops, logger):
     """
     Attach import names for known ecosystems
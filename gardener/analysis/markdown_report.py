@@ -0,0 +1,156 @@
+"""
+Markdown dependency report of analysis results
+
+Renders the external packages as one table per ecosystem under a summary of
+the counts, followed by the packages whose repository URL could not be
+resolved and why, for PR comments and human review. The report holds nothing
+that changes between runs over the same tree (no timestamps, no timings), and
+rows are sorted, so two reports diff line by line
+"""
+
+import re
+
+from gardener.analysis.csv_export import resolution_status
+
+MARKDOWN_SUFFIX = "_dependency_report.md"
+
+PACKAGE_COLUMNS = ("Package", "Version", "Repository", "Scope", "License")
+UNRESOLVED_COLUMNS = ("Package", "Ecosystem", "Reason")
+
+# Characters with inline meaning in CommonMark, or that end a GFM table cell
+_RE_MARKDOWN_SPECIAL = re.compile(r"([\\`*_{}\[\]<>()#+!|~&])")
+_RE_AUTOLINK_URL = re.compile(r"^https?://[^\s<>|`\\]+$")
+_RE_BACKTICKS = re.compile(r"`+")
+
+
+def _one_line(text):
+    return " ".join(str(text).split())
+
+
+def escape_markdown(text):
+    """
+    Escape text for a table cell, so it renders literally
+
+    Args:
+        text (str): Cell text
+
+    Returns:
+        str: Text on one line with every Markdown special character backslash-escaped
+    """
+    return _RE_MARKDOWN_SPECIAL.sub(r"\\\1", _one_line(text))
+
+
+def markdown_code(text):
+    """
+    Render text as an inline code span for a table cell
+
+    The fence is one backtick longer than the longest backtick run inside, and
+    pipes are escaped, which GFM tables require even within code spans
+
+    Args:
+        text (str): Code text, e.g. a package name
+
+    Returns:
+        str: Code span, or "" for empty text
+    """
+    text = _one_line(text)
+    if not text:
+        return ""
+    fence = "`" * (max((len(run) for run in _RE_BACKTICKS.findall(text)), default=0) + 1)
+    padding = " " if text.startswith("`") or text.endswith("`") else ""
+    escaped = text.replace("|", "\\|")
+    return f"{fence}{padding}{escaped}{padding}{fence}"
+
+
+def markdown_link(url):
+    """
+    Render a repository URL as an autolink, or as escaped text when it is not a plain http(s) URL
+
+    Args:
+        url (str): Repository URL
+
+    Returns:
+        str
+    """
+    url = _one_line(url or "")
+    if _RE_AUTOLINK_URL.match(url):
+        return f"<{url}>"
+    return escape_markdown(url)
+
+
+def _table(columns, rows):
+    lines = ["| " + " | ".join(columns) + " |", "|" + "|".join(" --- " for _ in columns) + "|"]
+    lines.extend("| " + " | ".join(row) + " |" for row in rows)
+    return lines
+
+
+def _version(package_info):
+    version = package_info.get("version")
+    return version if isinstance(version, str) else ""
+
+
+def render_markdown_report(results, root_name):
+    """
+    Render the dependency report of an analysis
+
+    Args:
+        results (dict): Analysis results with a flat external_packages mapping and a summary
+        root_name (str): Name of the analyzed project, the report title
+
+    Returns:
+        str: Markdown document ending in a newline
+    """
+    packages = results.get("external_packages", {})
+    summary = results.get("summary") or {}
+    by_ecosystem = {}
+    for name, package_info in packages.items():
+        by_ecosystem.setdefault(package_info.get("ecosystem") or "unknown", []).append(name)
+    unresolved = sorted(
+        (package_info.get("ecosystem") or "unknown", name, resolution_status(package_info))
+        for name, package_info in packages.items()
+        if resolution_status(package_info) != "resolved"
+    )
+
+    lines = [f"# Dependency report: {escape_markdown(root_name)}", ""]
+    files = summary.get("files_analyzed", (results.get("analyzer_details") or {}).get("total_files", 0))
+    lines.append(f"- Source files analyzed: {files}")
+    lines.append(
+        f"- External packages: {len(packages)} ({len(packages) - len(unresolved)} resolved, "
+        f"{len(unresolved)} unresolved)"
+    )
+    if by_ecosystem:
+        counts = ", ".join(f"{escape_markdown(name)} {len(by_ecosystem[name])}" for name in sorted(by_ecosystem))
+        lines.append(f"- Ecosystems: {counts}")
+    if results.get("partial"):
+        lines.append("- Partial analysis: interrupted before every file and package was analyzed")
+    lines.append("")
+
+    for ecosystem in sorted(by_ecosystem):
+        lines.append(f"## {escape_markdown(ecosystem)} ({len(by_ecosystem[ecosystem])})")
+        lines.append("")
+        rows = []
+        for name in sorted(by_ecosystem[ecosystem]):
+            package_info = packages[name]
+            rows.append(
+                (
+                    markdown_code(name),
+                    markdown_code(_version(package_info)),
+                    markdown_link(package_info.get("repository_url")),
+                    escape_markdown(package_info.get("scope") or ""),
+                    escape_markdown(package_info.get("license") or ""),
+                )
+            )
+        lines.extend(_table(PACKAGE_COLUMNS, rows))
+        lines.append("")
+
+    lines.append(f"## Unresolved repository URLs ({len(unresolved)})")
+    lines.append("")
+    if unresolved:
+        rows = [
+            (markdown_code(name), escape_markdown(ecosystem), escape_markdown(reason))
+            for ecosystem, name, reason in unresolved
+        ]
+        lines.extend(_table(UNRESOLVED_COLUMNS, rows))
+    else:
+        lines.append("Every repository URL was resolved.")
+    return "\n".join(lines) + "\n"
@@ -0,0 +1,336 @@
+"""
+Combined results of an analysis over several repository roots
+
+Each root is analyzed on its own (see run_analysis) and the results are folded
+into one document here, for fleet-wide dependency inventories. File paths are
+prefixed with the root's name ("<root>/<path>") wherever they appear, so the
+files of different roots never collide. An external package shared by several
+roots is kept once, listing the roots that use it in `roots` and the version each
+of them pins in `root_versions`. The dependency graphs are merged and their
+importance scores recalculated, so top_dependencies ranks the packages across all
+the roots. Sections describing one root's build (go_toolchain, go_modules,
+warnings, ...) are kept per root under `roots`, and summary["roots"] holds each
+root's own summary alongside the overall counts
+"""
+
+import copy
+
+import networkx as nx
+
+from gardener.analysis.graph import DependencyGraphBuilder
+from gardener.analysis.import_graph import NODE_FIRST_PARTY, build_reverse_index, find_import_cycles
+from gardener.analysis.summary import build_summary
+from gardener.common.defaults import GoAnalysisConfig
+from gardener.common.utils import Logger
+
+# Results sections that describe a single root, moved under roots[<name>]
+ROOT_SECTIONS = (
+    "analysis_root",
+    "clusters",
+    "enclosing_go_mod",
+    "go_modules",
+    "go_stdlib_deprecations",
+    "go_toolchain",
+    "prunable_candidates",
+    "version_conflicts",
+    "warnings",
+)
+
+# Package scopes in the order a package used by several roots takes the first of (see _merge_import_provenance)
+_SCOPE_PRECEDENCE = ("production", "tool", "test", "generate", "example", "ignored-build")
+
+# Package fields listing the files that use the package
+_PACKAGE_FILE_LISTS = ("seen_in", "generate", "make")
+
+# analyzer_details maps keyed by file path
+_DETAIL_FILE_MAPS = ("local_imports_map", "file_imports", "file_package_components", "file_import_evidence")
+
+
+def root_names(labels):
+    """
+    Name each root uniquely
+
+    Args:
+        labels (list): Preferred name of each root (the directory or archive name), in order
+
+    Returns:
+        list: The labels, later repeats suffixed "-2", "-3", ...
+    """
+    names = []
+    for label in labels:
+        name, count = label, 1
+        while name in names:
+            count += 1
+            name = f"{label}-{count}"
+        names.append(name)
+    return names
+
+
+def default_output_prefix(names):
+    """
+    Output prefix of a run over several roots without -o
+
+    Args:
+        names (list): Root names
+
+    Returns:
+        str: The first three names joined by "-", followed by "-and-<n>-more" for the rest
+    """
+    prefix = "-".join(names[:3])
+    if len(names) > 3:
+        prefix += f"-and-{len(names) - 3}-more"
+    return prefix
+
+
+def _rooted(root, path):
+    return root if path in ("", ".") else f"{root}/{path}"
+
+
+def _union(values, more):
+    return values + [value for value in more if value not in values]
+
+
+def _rooted_package(root, package_info):
+    package_info = copy.deepcopy(package_info)
+    for field in _PACKAGE_FILE_LISTS:
+        for entry in package_info.get(field) or []:
+            if entry.get("file"):
+                entry["file"] = _rooted(root, entry["file"])
+            entry["root"] = root
+    for conflict in package_info.get("version_conflicts") or []:
+        if conflict.get("manifest"):
+            conflict["manifest"] = _rooted(root, conflict["manifest"])
+    return package_info
+
+
+def _merge_packages(root_results):
+    """
+    Keep each external package once, with the roots that use it
+
+    The first root's record is kept, joined by the file provenance, manifests and import
+    names of the other roots; `scope` is the first of _SCOPE_PRECEDENCE any root gives
+    the package, `direct` is true when any root requires it directly,
+    `build_reachability` is kept only when every root has it, and `usage_count` is summed
+
+    Args:
+        root_results (list): (root name, results) pairs
+
+    Returns:
+        dict: Package name -> metadata with `roots` and `root_versions`
+    """
+    merged = {}
+    for root, results in root_results:
+        for name, package_info in results.get("external_packages", {}).items():
+            package_info = _rooted_package(root, package_info)
+            version = package_info.get("version")
+            version = version if isinstance(version, str) else None
+            if name not in merged:
+                package_info["roots"] = [root]
+                package_info["root_versions"] = {root: version}
+                merged[name] = package_info
+                continue
+            combined = merged[name]
+            combined["roots"].append(root)
+            combined["root_versions"][root] = version
+            for field in _PACKAGE_FILE_LISTS + ("version_conflicts",):
+                if package_info.get(field):
+                    combined[field] = (combined.get(field) or []) + package_info[field]
+            for field in ("found_in_manifests", "import_names", "go_modules"):
+                if package_info.get(field):
+                    combined[field] = _union(combined.get(field) or [], package_info[field])
+            if package_info.get("direct"):
+                combined["direct"] = True
+            if not package_info.get("build_reachability"):
+                combined.pop("build_reachability", None)
+            if "usage_count" in package_info:
+                combined["usage_count"] = combined.get("usage_count", 0) + package_info["usage_count"]
+            scopes = {combined.get("scope"), package_info.get("scope")}
+            scope = next((scope for scope in _SCOPE_PRECEDENCE if scope in scopes), None)
+            if scope:
+                combined["scope"] = scope
+    for package_info in merged.values():
+        if "seen_in" in package_info:
+            package_info["seen_in"].sort(key=lambda entry: entry["file"])
+    return merged
+
+
+def _merge_dependency_graph(root_results):
+    """
+    Merge the dependency graphs of the roots, renaming file nodes to their rooted paths
+
+    Package and component nodes are shared, keeping the attributes of the first root that has them
+
+    Returns:
+        networkx.DiGraph|None: None when no root has a dependency graph
+    """
+    merged = None
+    for root, results in root_results:
+        data = results.get("dependency_graph")
+        if not data or not data.get("nodes"):
+            continue
+        graph = nx.node_link_graph(data)
+        merged = nx.DiGraph() if merged is None else merged
+        renames = {}
+        for node, attrs in graph.nodes(data=True):
+            renames[node] = _rooted(root, node) if attrs.get("type") == "file" else node
+            if not merged.has_node(renames[node]):
+                merged.add_node(renames[node], **attrs)
+        for source, target, attrs in graph.edges(data=True):
+            if not merged.has_edge(renames[source], renames[target]):
+                merged.add_edge(renames[source], renames[target], **attrs)
+    return merged
+
+
+def _merge_import_graph(root_results):
+    """
+    Merge the package-level import graphs of the roots
+
+    First-party packages named after a directory are renamed to the root's path of
+    that directory; those a Go module path names keep their name. External and
+    standard-library packages are shared, and the files behind each edge are joined
+
+    Returns:
+        dict: {"nodes", "edges"} as built by build_import_graph
+    """
+    nodes, edges = {}, {}
+    for root, results in root_results:
+        import_graph = results.get("import_graph") or {}
+        renames = {}
+        for node in import_graph.get("nodes", []):
+            node = dict(node)
+            if node.get("kind") == NODE_FIRST_PARTY:
+                if node.get("path", node["id"]) == node["id"]:
+                    renames[node["id"]] = _rooted(root, node["id"])
+                    node["id"] = renames[node["id"]]
+                if "path" in node:
+                    node["path"] = _rooted(root, node["path"])
+            nodes.setdefault(node["id"], node)
+        for edge in import_graph.get("edges", []):
+            key = (
+                renames.get(edge["source"], edge["source"]),
+                renames.get(edge["target"], edge["target"]),
+                edge["import"],
+                edge["import_kind"],
+            )
+            edges.setdefault(key, set()).update(_rooted(root, path) for path in edge["files"])
+    return {
+        "nodes": [nodes[node_id] for node_id in sorted(nodes)],
+        "edges": [
+            {
+                "source": source,
+                "target": target,
+                "import": import_path,
+                "import_kind": import_kind,
+                "files": sorted(files),
+            }
+            for (source, target, import_path, import_kind), files in sorted(edges.items())
+        ],
+    }
+
+
+def _merge_details(root_results):
+    """
+    Merge the analyzer_details of the roots, keyed by rooted file path
+
+    Returns:
+        dict: analyzer_details with summed file counts and the languages detected in any root
+    """
+    details = {map_name: {} for map_name in _DETAIL_FILE_MAPS}
+    details.update({"total_files": 0, "skipped_by_depth": 0})
+    languages = set()
+    for root, results in root_results:
+        root_details = results.get("analyzer_details") or {}
+        for map_name in _DETAIL_FILE_MAPS:
+            for path, values in (root_details.get(map_name) or {}).items():
+                if map_name == "local_imports_map":
+                    values = [_rooted(root, target) for target in values]
+                details[map_name][_rooted(root, path)] = values
+        details["total_files"] += root_details.get("total_files", 0)
+        details["skipped_by_depth"] += root_details.get("skipped_by_depth", 0)
+        languages.update(root_details.get("languages_detected") or [])
+    details["languages_detected"] = sorted(languages)
+    return details
+
+
+def _top_dependencies(graph, external_packages, logger):
+    """
+    Rank the packages of the merged dependency graph as DependencyAnalyzer does for one root
+
+    Returns:
+        tuple: ({"package_name", "percentage", "package_url", "ecosystem"} dicts highest score first,
+            node-link data of the graph with the recalculated scores; empty when there is no graph)
+    """
+    if graph is None:
+        return [], {}
+    builder = DependencyGraphBuilder(logger)
+    builder.graph = graph
+    scores = builder.calculate_importance()
+    ranked = builder.get_top_dependencies(scores) if scores else []
+    if not GoAnalysisConfig.INCLUDE_STDLIB:
+        ranked = [(name, score) for name, score in ranked if graph.nodes[name].get("ecosystem") != "go_stdlib"]
+    total_score = sum(score for _, score in ranked)
+    top_deps = []
+    for package_name, score in ranked:
+        package_info = external_packages.get(package_name, {})
+        top_deps.append(
+            {
+                "package_name": package_name,
+                "percentage": (score / total_score * 100) if total_score > 0 else 0,
+                "package_url": package_info.get("repository_url", ""),
+                "ecosystem": package_info.get("ecosystem", "unknown"),
+            }
+        )
+    return top_deps, builder.get_graph_data()
+
+
+def combine_root_results(root_results, logger=None):
+    """
+    Combine the results of several roots into one analysis document
+
+    Args:
+        root_results (list): (root name, results) pairs in the order the roots were given; each
+            results dict may carry the run's `tool` section, whose target and commit are
+            recorded per root
+        logger (Logger): Optional logger instance
+
+    Returns:
+        dict: Analysis results over all the roots, with file paths prefixed by the root name, a
+            `roots` section mapping each root to its target, commit and ROOT_SECTIONS, and
+            summary["roots"] holding the summary of each root; partial when any root's is
+    """
+    logger = logger or Logger()
+    external_packages = _merge_packages(root_results)
+    top_deps, dependency_graph = _top_dependencies(_merge_dependency_graph(root_results), external_packages, logger)
+    combined = {
+        "external_packages": external_packages,
+        "dependency_graph": dependency_graph,
+        "import_graph": _merge_import_graph(root_results),
+        "top_dependencies": top_deps,
+        "analyzer_details": _merge_details(root_results),
+    }
+    combined["cycles"] = find_import_cycles(combined["import_graph"])
+    combined["reverse_index"], combined["reverse_index_scopes"] = build_reverse_index(external_packages)
+    combined["summary"] = build_summary(combined)
+    combined["summary"]["roots"] = {root: results.get("summary", {}) for root, results in root_results}
+
+    roots = {}
+    errors = []
+    for root, results in root_results:
+        tool = results.get("tool") or {}
+        section = {"target": tool.get("target", root)}
+        for field in ("commit", "go_mod"):
+            if tool.get(field):
+                section[field] = tool[field]
+        section.update({name: results[name] for name in ROOT_SECTIONS if name in results})
+        roots[root] = section
+        errors.extend(dict(error, file=_rooted(root, error["file"])) for error in results.get("errors") or [])
+    combined["roots"] = roots
+    if errors:
+        combined["errors"] = errors
+    tools = [results["tool"] for _, results in root_results if results.get("tool")]
+    if tools:
+        combined["tool"] = {field: value for field, value in tools[0].items() if field not in ("commit", "go_mod")}
+        combined["tool"]["targets"] = [tool["target"] for tool in tools]
+    if any(results.get("partial") for _, results in root_results):
+        combined["partial"] = True
+    return combined
@@ -0,0 +1,162 @@
+"""
+Newline-delimited JSON streaming of analysis results
+
+Each line is one self-describing JSON object with a `type` field:
+`file_evidence` lines are written while source files are still being parsed,
+followed by one `package` line per external package once repository URLs are
+resolved, and a closing `summary` line with aggregate counts
+"""
+
+import json
+
+from gardener.analysis.csv_export import resolution_status
+from gardener.analysis.record_ids import file_id, with_evidence_ids
+from gardener.common.defaults import GoAnalysisConfig
+
+NDJSON_SUFFIX = "_dependencies.ndjson"
+
+
+def to_ndjson_line(record):
+    """
+    Serialize one record as a single NDJSON line
+
+    Args:
+        record (dict): JSON-serializable record
+
+    Returns:
+        str: Compact JSON terminated by a newline
+    """
+    return json.dumps(record, sort_keys=True, separators=(",", ":"), default=str) + "\n"
+
+
+def file_evidence_record(rel_path, result, language=None):
+    """
+    Build the record for one source file's extracted imports
+
+    Args:
+        rel_path (str): Repo-relative path of the file
+        result (dict): Per-file extraction result ({"external", "local", "evidence", ...})
+        language (str): Language the file was parsed as
+
+    Returns:
+        dict: {"type": "file_evidence", "id", "file", "language", "imports", "local_imports", ["evidence"],
+            ["generated"]}, the file and evidence ids as in the JSON results (see record_ids)
+    """
+    record = {
+        "type": "file_evidence",
+        "id": file_id(rel_path),
+        "file": rel_path,
+        "language": language,
+        "imports": list(result.get("external", [])),
+        "local_imports": list(result.get("local", [])),
+    }
+    evidence = list(result.get("evidence", {}).get(rel_path, []))
+    if not GoAnalysisConfig.INCLUDE_STDLIB:
+        evidence = [entry for entry in evidence if entry.get("scope") != "stdlib"]
+    if evidence:
+        record["evidence"] = with_evidence_ids(rel_path, evidence)
+    if result.get("generated"):
+        record["generated"] = True
+    return record
+
+
+def package_records(results):
+    """
+    Yield one record per external package, sorted by ecosystem then name
+
+    Args:
+        results (dict): Analysis results with an external_packages mapping
+
+    Yields:
+        dict: {"type": "package", "name", "resolution_status", ...package metadata}
+    """
+    external_packages = results.get("external_packages", {})
+    for name in sorted(external_packages, key=lambda n: (external_packages[n].get("ecosystem") or "", n)):
+        package_info = external_packages[name]
+        record = dict(package_info)
+        record.update({"type": "package", "name": name, "resolution_status": resolution_status(package_info)})
+        yield record
+
+
+def summary_record(results):
+    """
+    Build the closing record with aggregate counts
+
+    Args:
+        results (dict): Analysis results
+
+    Returns:
+        dict: {"type": "summary", "total_files", "files_with_imports", "packages", "resolved_packages",
+            "errors", "cycles", "top_dependencies", ["go_toolchain"], ["warnings"], ["analysis_scope"],
+            ["partial"], ["tool"]}
+    """
+    details = results.get("analyzer_details", {})
+    external_packages = results.get("external_packages", {})
+    record = {
+        "type": "summary",
+        "total_files": details.get("total_files", 0),
+        "files_with_imports": len(set(details.get("file_imports", {})) | set(details.get("local_imports_map", {}))),
+        "packages": len(external_packages),
+        "resolved_packages": sum(1 for info in external_packages.values() if info.get("repository_url")),
+        "errors": list(results.get("errors", [])),
+        "cycles": list(results.get("cycles", [])),
+        "top_dependencies": [
+            {"package_name": dep["package_name"], "percentage": dep["percentage"]}
+            for dep in results.get("top_dependencies", [])
+        ],
+    }
+    if results.get("go_toolchain"):
+        record["go_toolchain"] = results["go_toolchain"]
+    if results.get("warnings"):
+        record["warnings"] = list(results["warnings"])
+    if results.get("analysis_scope"):
+        record["analysis_scope"] = results["analysis_scope"]
+    if results.get("partial"):
+        record["partial"] = True
+    if results.get("tool"):
+        record["tool"] = results["tool"]
+    return record
+
+
+class NDJSONStreamWriter:
+    """
+    Write NDJSON records to a text stream, flushing after every line
+
+    Args:
+        stream: Writable text stream, e.g. from PersistenceInterface.open_stream
+        keep_lines (bool): Also keep every line written in `lines`, e.g. to send them on afterwards
+        mirror: Optional second text stream receiving every line as well, e.g. sys.stdout
+    """
+
+    def __init__(self, stream, keep_lines=False, mirror=None):
+        self.stream = stream
+        self.mirror = mirror
+        self.lines_written = 0
+        self.lines = [] if keep_lines else None
+
+    def write(self, record):
+        """
+        Append one record
+
+        Args:
+            record (dict): JSON-serializable record with a `type` field
+        """
+        line = to_ndjson_line(record)
+        for stream in (self.stream, self.mirror):
+            if stream is not None:
+                stream.write(line)
+                stream.flush()
+        if self.lines is not None:
+            self.lines.append(line)
+        self.lines_written += 1
+
+    def write_results(self, results):
+        """
+        Append the `package` records and the closing `summary` record
+
+        Args:
+            results (dict): Analysis results
+        """
+        for record in package_records(results):
+            self.write(record)
+        self.write(summary_record(results))
@@ -0,0 +1,76 @@
+"""
+Advisory database coordinates of each dependency
+
+OSV (and the GitHub Advisory Database it imports) keys advisories on an
+ecosystem and a package name spelled the way that ecosystem's registry does:
+Go advisories name the module path, npm ones the package name with its scope,
+PyPI ones the PEP 503 normalized name. Only coordinates that follow from the
+manifests are given; nothing is looked up, and no advisory is queried
+"""
+
+import re
+
+# Gardener ecosystem -> OSV ecosystem
+OSV_ECOSYSTEMS = {
+    "go": "Go",
+    "npm": "npm",
+    "pypi": "PyPI",
+    "cargo": "crates.io",
+    "github-actions": "GitHub Actions",
+}
+
+# Dockerfile RUN installer -> OSV ecosystem of the packages it installs; `go install` names a
+# package rather than its module, and distribution package managers need the release, so
+# neither is mapped
+INSTALLER_OSV_ECOSYSTEMS = {
+    "pip": "PyPI",
+    "npm": "npm",
+    "cargo": "crates.io",
+    "gem": "RubyGems",
+}
+
+
+def _pypi_name(name):
+    return re.sub(r"[-_.]+", "-", name).lower()
+
+
+def osv_coordinates(package_name, package_info):
+    """
+    Return the OSV ecosystem and package name advisories for a dependency are filed under
+
+    Go modules a `replace` redirects are named by their replacement module, whose code
+    is built; GitHub actions by their owner/repo, without any path inside the
+    repository; packages installed by Dockerfile RUN lines by their installer's ecosystem.
+    Dependencies pinned to the local filesystem (see classify_pin), Docker images,
+    git submodules and Solidity sources have no coordinates
+
+    Args:
+        package_name (str): Package name as reported by the analysis
+        package_info (dict): Package metadata from external_packages, with its `pin_kind`
+
+    Returns:
+        dict: {"osv_ecosystem", "osv_name"}, e.g. {"osv_ecosystem": "Go", "osv_name":
+            "github.com/gin-gonic/gin"}, or {} when they cannot be told without a lookup
+    """
+    if package_info.get("pin_kind") == "local":
+        return {}
+    ecosystem = package_info.get("ecosystem")
+    if ecosystem == "docker":
+        osv_ecosystem = INSTALLER_OSV_ECOSYSTEMS.get(package_info.get("installer"))
+        name = package_info.get("package")
+        if not osv_ecosystem or not name:
+            return {}
+        return {"osv_ecosystem": osv_ecosystem, "osv_name": _pypi_name(name) if osv_ecosystem == "PyPI" else name}
+    osv_ecosystem = OSV_ECOSYSTEMS.get(ecosystem)
+    if osv_ecosystem is None:
+        return {}
+    if ecosystem == "go":
+        replace = package_info.get("replace") or {}
+        name = replace.get("path") or package_info.get("module_path") or package_name
+    elif ecosystem == "pypi":
+        name = _pypi_name(package_name)
+    elif ecosystem == "github-actions":
+        name = "/".join(package_name.split("/")[:2])
+    else:
+        name = package_name
+    return {"osv_ecosystem": osv_ecosystem, "osv_name": name}
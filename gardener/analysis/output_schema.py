@@ -0,0 +1,166 @@
+"""
+JSON Schema of the analysis JSON, and a validator for gardener's own output
+
+The schema (dependency_analysis.schema.json, next to this module) is the formal
+contract for consumers of `<prefix>_dependency_analysis.json`. It is written in
+JSON Schema draft 2020-12, restricted to the keywords in SUPPORTED_KEYWORDS so
+that this module can check documents against it without a schema library.
+--validate-output checks each document before it is written
+"""
+
+import json
+import os
+
+SCHEMA_PATH = os.path.join(os.path.dirname(os.path.abspath(__file__)), "dependency_analysis.schema.json")
+
+# Schema keywords validate_document understands; the schema uses no others
+SUPPORTED_KEYWORDS = {
+    "$schema",
+    "$defs",
+    "$ref",
+    "title",
+    "description",
+    "type",
+    "enum",
+    "const",
+    "properties",
+    "required",
+    "additionalProperties",
+    "items",
+    "anyOf",
+}
+
+# JSON Schema type name -> check of a decoded JSON value
+_TYPE_CHECKS = {
+    "object": lambda value: isinstance(value, dict),
+    "array": lambda value: isinstance(value, list),
+    "string": lambda value: isinstance(value, str),
+    "integer": lambda value: isinstance(value, int) and not isinstance(value, bool),
+    "number": lambda value: isinstance(value, (int, float)) and not isinstance(value, bool),
+    "boolean": lambda value: isinstance(value, bool),
+    "null": lambda value: value is None,
+}
+
+_schema = None
+
+
+class OutputValidationError(ValueError):
+    """
+    The analysis JSON does not conform to the published schema
+
+    Args:
+        problems (list): Violations found by validate_document
+    """
+
+    def __init__(self, problems):
+        self.problems = list(problems)
+        shown = "; ".join(self.problems[:5])
+        more = f" (and {len(self.problems) - 5} more)" if len(self.problems) > 5 else ""
+        super().__init__(f"The analysis JSON does not match its schema: {shown}{more}")
+
+
+def load_output_schema():
+    """
+    Return the published JSON Schema of the analysis JSON
+
+    Returns:
+        dict: The parsed schema; callers must not modify it
+    """
+    global _schema
+    if _schema is None:
+        with open(SCHEMA_PATH, encoding="utf-8") as handle:
+            _schema = json.load(handle)
+    return _schema
+
+
+def _json_type(value):
+    for name in ("null", "boolean", "integer", "number", "string", "array", "object"):
+        if _TYPE_CHECKS[name](value):
+            return name
+    return type(value).__name__
+
+
+def _resolve(schema, root):
+    while "$ref" in schema:
+        reference = schema["$ref"]
+        if not reference.startswith("#/"):
+            raise ValueError(f"Unsupported schema reference {reference!r}")
+        schema = root
+        for part in reference[2:].split("/"):
+            schema = schema[part]
+    return schema
+
+
+def _validate(value, schema, root, path, problems):
+    schema = _resolve(schema, root)
+    types = schema.get("type")
+    if types is not None:
+        types = [types] if isinstance(types, str) else types
+        if not any(_TYPE_CHECKS[name](value) for name in types):
+            problems.append(f"{path}: expected {' or '.join(types)}, got {_json_type(value)}")
+            return
+    if "enum" in schema and value not in schema["enum"]:
+        problems.append(f"{path}: {value!r} is not one of {', '.join(map(repr, schema['enum']))}")
+    if "const" in schema and value != schema["const"]:
+        problems.append(f"{path}: expected {schema['const']!r}, got {value!r}")
+    if "anyOf" in schema and not any(_matches(value, option, root, path) for option in schema["anyOf"]):
+        problems.append(f"{path}: matches none of the allowed forms")
+    if isinstance(value, dict):
+        properties = schema.get("properties", {})
+        for name in schema.get("required", []):
+            if name not in value:
+                problems.append(f"{path}: missing required field {name!r}")
+        extra = schema.get("additionalProperties", True)
+        for name, item in value.items():
+            item_path = f"{path}.{name}"
+            if name in properties:
+                _validate(item, properties[name], root, item_path, problems)
+            elif extra is False:
+                problems.append(f"{path}: unexpected field {name!r}")
+            elif isinstance(extra, dict):
+                _validate(item, extra, root, item_path, problems)
+    if isinstance(value, list) and "items" in schema:
+        for index, item in enumerate(value):
+            _validate(item, schema["items"], root, f"{path}[{index}]", problems)
+
+
+def _matches(value, schema, root, path):
+    problems = []
+    _validate(value, schema, root, path, problems)
+    return not problems
+
+
+def validate_document(document, schema=None):
+    """
+    Check a document against a schema
+
+    Args:
+        document: Decoded JSON value, e.g. the analysis JSON as written
+        schema (dict): Schema using SUPPORTED_KEYWORDS (default: load_output_schema())
+
+    Returns:
+        list: One "<path>: <problem>" description per violation, e.g.
+            "$.summary.files_analyzed: expected integer, got string"; empty when the document conforms
+    """
+    schema = load_output_schema() if schema is None else schema
+    problems = []
+    _validate(document, schema, schema, "$", problems)
+    return problems
+
+
+def check_output(document):
+    """
+    Raise when a document does not conform to the published schema
+
+    The document is checked as it will be written, i.e. after the JSON encoding
+    the persistence layer applies (tuples become arrays, other values strings)
+
+    Args:
+        document (dict): Analysis JSON about to be written
+
+    Raises:
+        OutputValidationError: Listing the violations
+    """
+    problems = validate_document(json.loads(json.dumps(document, default=str)))
+    if problems:
+        raise OutputValidationError(problems)
@@ -0,0 +1,31 @@
+"""
+Compact package list of analysis results (--packages-only)
+
+For consumers that only need the third-party packages: one {"ecosystem", "name",
+"version"} object per package, deduplicated and sorted, without URLs, resolution
+receipts, evidence or graphs. The analysis producing it skips those phases too
+(see DependencyAnalyzer.list_packages)
+"""
+
+PACKAGES_SUFFIX = "_packages.json"
+
+
+def package_list(external_packages):
+    """
+    Render external packages as the compact package list
+
+    Args:
+        external_packages (dict): Package name -> metadata with `ecosystem` and `version`
+
+    Returns:
+        list: {"ecosystem", "name", "version"} dicts sorted by ecosystem, name and version; version is
+            None when the manifest pins none as a string (e.g. a local path dependency)
+    """
+    entries = set()
+    for name, package_info in external_packages.items():
+        version = package_info.get("version")
+        entries.add((package_info.get("ecosystem") or "unknown", name, version if isinstance(version, str) else None))
+    return [
+        {"ecosystem": ecosystem, "name": name, "version": version}
+        for ecosystem, name, version in sorted(entries, key=lambda entry: (entry[0], entry[1], entry[2] or ""))
+    ]
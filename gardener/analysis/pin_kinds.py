@@ -0,0 +1,131 @@
+"""
+How firmly each dependency reference is pinned
+
+A reference is "pinned-release" when it names one released version (Go
+`v1.2.3`, npm `1.2.3`, an image tag or action tag `v4.1.0`), "pinned-commit"
+when it names an immutable revision (a Go pseudo-version, a commit SHA, an
+image digest, a git submodule's recorded commit), "local" when it points into
+the filesystem (a go.mod `replace => ../x`, npm `file:` and `workspace:`) and
+"floating" when what it selects can change (ranges, branches, `latest`, a
+major-version tag). Everything is read from the manifests; nothing is looked up
+"""
+
+import re
+from datetime import datetime, timezone
+
+from gardener.analysis.sbom import exact_version
+
+PIN_KINDS = ("pinned-release", "pinned-commit", "floating", "local")
+
+# vX.0.0-yyyymmddhhmmss-<commit>, vX.Y.Z-pre.0.yyyymmddhhmmss-<commit> or vX.Y.(Z+1)-0.yyyymmddhhmmss-<commit>
+_RE_GO_PSEUDO_VERSION = re.compile(
+    r"^v\d+\.(?:0\.0-|\d+\.\d+-(?:[^+]*\.)?0\.)(\d{14})-([0-9a-f]{12})(?:\+incompatible)?$"
+)
+# A tag naming one release: v1.2.3, 1.21.3-alpine; "3.12" or "v4" still move with new patch releases
+_RE_FULL_RELEASE_TAG = re.compile(r"^v?\d+\.\d+\.\d+(?:[-+._][0-9A-Za-z.+_-]+)?$")
+_RE_COMMIT_SHA = re.compile(r"^[0-9a-f]{40}$")
+# Commit fragment of an npm git dependency: git+https://host/user/repo.git#<sha>, user/repo#<sha>
+_RE_GIT_COMMIT_FRAGMENT = re.compile(r"#([0-9a-f]{7,40})$")
+_RE_NPM_GIT = re.compile(r"^(?:git(?:\+\w+)?:|github:|gitlab:|bitbucket:|gist:|[\w.-]+/[\w.-]+(?:#|$))")
+_NPM_LOCAL_PREFIXES = ("file:", "link:", "workspace:", "portal:", "./", "../", "/", "~/")
+# package.json entries recorded without a version (bundleDependencies, pnpm patchedDependencies)
+_NPM_PLACEHOLDERS = ("bundled", "patched")
+
+
+def parse_go_pseudo_version(version):
+    """
+    Split a Go pseudo-version into the commit it pins and that commit's time
+
+    Args:
+        version (str): Module version, e.g. "v0.0.0-20230101000000-abcdef123456"
+
+    Returns:
+        dict|None: {"commit": 12-character revision prefix, "time": RFC 3339 UTC timestamp}, or None
+            when the version is not a pseudo-version
+    """
+    match = _RE_GO_PSEUDO_VERSION.match(version or "")
+    if not match:
+        return None
+    try:
+        committed = datetime.strptime(match.group(1), "%Y%m%d%H%M%S").replace(tzinfo=timezone.utc)
+    except ValueError:
+        return None
+    return {"commit": match.group(2), "time": committed.strftime("%Y-%m-%dT%H:%M:%SZ")}
+
+
+def _go_pin(package_info):
+    replace = package_info.get("replace") or {}
+    if replace.get("local"):
+        return {"pin_kind": "local"}
+    version = replace.get("version") or package_info.get("version")
+    if not version and package_info.get("commit"):
+        # A Bazel go_repository rule fetching a commit instead of a version
+        return {"pin_kind": "pinned-commit", "pinned_commit": package_info["commit"]}
+    if not version:
+        return {}
+    pseudo = parse_go_pseudo_version(version)
+    if pseudo:
+        return {"pin_kind": "pinned-commit", "pinned_commit": pseudo["commit"], "pinned_commit_time": pseudo["time"]}
+    return {"pin_kind": "pinned-release" if exact_version(version) else "floating"}
+
+
+def _npm_pin(version):
+    if version in _NPM_PLACEHOLDERS:
+        return {}
+    if version.startswith("npm:"):
+        # Aliases, e.g. "npm:lodash@4.17.21" or "npm:@scope/pkg@^1.0.0", pin whatever follows the aliased name
+        aliased = version[len("npm:") :]
+        version = aliased.rpartition("@")[2] if "@" in aliased[1:] else ""
+    if version.startswith(_NPM_LOCAL_PREFIXES):
+        return {"pin_kind": "local"}
+    if "://" in version or _RE_NPM_GIT.match(version):
+        commit = _RE_GIT_COMMIT_FRAGMENT.search(version)
+        if commit:
+            return {"pin_kind": "pinned-commit", "pinned_commit": commit.group(1)}
+        return {"pin_kind": "floating"}
+    return {"pin_kind": "pinned-release" if exact_version(version) else "floating"}
+
+
+def _docker_pin(package_info):
+    if package_info.get("installer"):
+        # A RUN `pip install` / `npm install` without a version installs whatever is newest
+        return {"pin_kind": "pinned-release" if exact_version(package_info.get("version")) else "floating"}
+    if package_info.get("digest"):
+        return {"pin_kind": "pinned-commit"}
+    tag = package_info.get("tag") or ""
+    return {"pin_kind": "pinned-release" if _RE_FULL_RELEASE_TAG.match(tag) else "floating"}
+
+
+def _action_pin(package_info):
+    ref = package_info.get("ref") or package_info.get("version") or ""
+    if _RE_COMMIT_SHA.match(ref):
+        return {"pin_kind": "pinned-commit", "pinned_commit": ref}
+    return {"pin_kind": "pinned-release" if _RE_FULL_RELEASE_TAG.match(ref) else "floating"}
+
+
+def classify_pin(package_info):
+    """
+    Classify how firmly a dependency's reference is pinned
+
+    Args:
+        package_info (dict): Package metadata from external_packages
+
+    Returns:
+        dict: {"pin_kind"} (one of PIN_KINDS), plus "pinned_commit" when the reference names a
+            commit and, for Go pseudo-versions, "pinned_commit_time"; empty when the manifest
+            records no reference to classify (pypi and cargo requirements, for instance)
+    """
+    ecosystem = package_info.get("ecosystem")
+    version = package_info.get("version")
+    if ecosystem in ("go", "go-generate"):
+        return _go_pin(package_info)
+    if ecosystem == "docker":
+        return _docker_pin(package_info)
+    if ecosystem == "github-actions":
+        return _action_pin(package_info)
+    if ecosystem == "git-submodule":
+        # The superproject records the exact commit of every submodule checkout
+        return {"pin_kind": "pinned-commit"}
+    if ecosystem in ("npm", "solidity") and isinstance(version, str):
+        return _npm_pin(version.strip())
+    return {}
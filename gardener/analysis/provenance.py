@@ -0,0 +1,122 @@
+"""
+Provenance of an analysis document: which gardener run produced it, and how
+
+The `tool` section names the gardener version, the options and effective
+configuration of the run, when it started, what was analyzed and, for git
+work trees, the commit checked out, so stored results can be told apart as
+scope filters, offline mode and resolution sources change what a run reports
+"""
+
+import json
+import os
+import subprocess
+import urllib.parse
+from datetime import datetime, timezone
+
+from gardener.analysis.sbom import TOOL_NAME
+from gardener.common.defaults import effective_config
+from gardener.common.input_validation import ValidationError
+from gardener.common.subprocess import SecureSubprocess, SubprocessSecurityError
+from gardener.common.utils import is_repository_url, tool_version
+
+# Fields of the tool section
+TOOL_FIELDS = {
+    "name": "Always gardener",
+    "version": "Installed gardener version, or unknown",
+    "analyzed_at": "UTC start of the run (SOURCE_DATE_EPOCH when set), as YYYY-MM-DDTHH:MM:SSZ",
+    "target": "Repository URL, archive or absolute path given to the run; URL credentials are dropped",
+    "commit": "Commit checked out in the analyzed git work tree, when there is one",
+    "options": "Run options that select what is analyzed: format, languages, since, branch, files, baseline",
+    "config": "Every configuration parameter with the value the run used, by class; tokens masked",
+    "go_mod": "go.mod above the target that supplied its Go module path and versions, for a module subdirectory",
+    "targets": "Every target of a run over several repository roots, in order (see combine_root_results)",
+}
+
+
+def analysis_timestamp():
+    """
+    Return the current UTC time for analyzed_at
+
+    SOURCE_DATE_EPOCH, when set to epoch seconds, replaces the clock so reruns
+    can produce byte-identical documents
+
+    Returns:
+        str: YYYY-MM-DDTHH:MM:SSZ
+    """
+    epoch = os.environ.get("SOURCE_DATE_EPOCH", "").strip()
+    moment = datetime.fromtimestamp(int(epoch), timezone.utc) if epoch.isdigit() else datetime.now(timezone.utc)
+    return moment.strftime("%Y-%m-%dT%H:%M:%SZ")
+
+
+def analysis_target(repo_path):
+    """
+    Return how the analyzed repository was named, without URL credentials
+
+    Args:
+        repo_path (str): Repository URL, archive or local path as given to the run
+
+    Returns:
+        str: The URL without user info, or the absolute path
+    """
+    if is_repository_url(repo_path):
+        parts = urllib.parse.urlsplit(repo_path)
+        host = parts.hostname or ""
+        if parts.port:
+            host = f"{host}:{parts.port}"
+        return urllib.parse.urlunsplit((parts.scheme, host, parts.path, parts.query, ""))
+    return os.path.abspath(repo_path)
+
+
+def repository_commit(repo_path):
+    """
+    Return the commit checked out in the git work tree around a directory
+
+    Args:
+        repo_path (str): Absolute path of the analyzed directory
+
+    Returns:
+        str|None: Full commit id, or None outside a git work tree, without git or before a first commit
+    """
+    try:
+        runner = SecureSubprocess(allowed_root=repo_path, timeout=60)
+        result = runner.run(["git", "rev-parse", "--verify", "--quiet", "HEAD"], cwd=repo_path, check=False)
+    except (OSError, SubprocessSecurityError, ValidationError, subprocess.TimeoutExpired):
+        return None
+    commit = result.stdout.strip() if result.returncode == 0 else ""
+    return commit or None
+
+
+def _json_value(value):
+    # Callables (URL rules from the Python API) are named, not repr'd with their address
+    return getattr(value, "__name__", type(value).__name__)
+
+
+def tool_section(target, repo_path, analyzed_at, options, config_overrides=None, go_mod=None):
+    """
+    Build the tool section of an analysis document
+
+    Args:
+        target (str): Repository URL, archive or path as given to the run
+        repo_path (str): Absolute path of the analyzed directory (the clone or extracted archive)
+        analyzed_at (str): Start of the run, see analysis_timestamp
+        options (dict): Run options; those that are None are left out
+        config_overrides (dict): Configuration overrides of the run
+        go_mod (str): Absolute path of the enclosing go.mod the analysis used, if any
+
+    Returns:
+        dict: Fields of TOOL_FIELDS
+    """
+    section = {
+        "name": TOOL_NAME,
+        "version": tool_version(),
+        "analyzed_at": analyzed_at,
+        "target": analysis_target(target),
+        "options": {key: value for key, value in options.items() if value is not None},
+        "config": json.loads(json.dumps(effective_config(config_overrides), default=_json_value)),
+    }
+    commit = repository_commit(repo_path)
+    if commit:
+        section["commit"] = commit
+    if go_mod:
+        section["go_mod"] = go_mod
+    return section
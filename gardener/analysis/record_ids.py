@@ -0,0 +1,65 @@
+"""
+Stable identifiers for package and file-evidence records
+
+IDs are canonical strings built only from what identifies a record, never from
+discovery order, so the same dependency or file gets the same ID in every scan
+and consumers can diff stored results by ID:
+
+- external package: `<ecosystem>:<name>`, e.g. `go:github.com/pkg/errors`
+  (`unknown:<name>` for packages without an ecosystem)
+- source file: `file:<repo-relative POSIX path>`, e.g. `file:cmd/server/main.go`
+- import evidence entry: `<file id>#<import>`, e.g. `file:main.go#github.com/pkg/errors`;
+  a file importing the same path more than once numbers the repeats in source
+  order (`#fmt~2`)
+"""
+
+from gardener.common.file_helpers import to_posix_path
+
+
+def package_id(package_name, package_info):
+    """
+    Return the ID of an external package
+
+    Args:
+        package_name (str): Package name as reported by the analysis
+        package_info (dict): Package metadata from external_packages
+
+    Returns:
+        str: `<ecosystem>:<name>`
+    """
+    return f"{package_info.get('ecosystem') or 'unknown'}:{package_name}"
+
+
+def file_id(rel_path):
+    """
+    Return the ID of a source file
+
+    Args:
+        rel_path (str): Repo-relative path
+
+    Returns:
+        str: `file:<path>` with forward slashes
+    """
+    return f"file:{to_posix_path(rel_path)}"
+
+
+def with_evidence_ids(rel_path, entries):
+    """
+    Return copies of one file's import evidence entries, each with its `id`
+
+    Args:
+        rel_path (str): Repo-relative path of the file
+        entries (list): Evidence entries in source order
+
+    Returns:
+        list: New entry dicts carrying `id`
+    """
+    prefix = file_id(rel_path)
+    seen = {}
+    identified = []
+    for entry in entries:
+        import_path = entry.get("import", "")
+        seen[import_path] = seen.get(import_path, 0) + 1
+        suffix = f"~{seen[import_path]}" if seen[import_path] > 1 else ""
+        identified.append(dict(entry, id=f"{prefix}#{import_path}{suffix}"))
+    return identified
@@ -0,0 +1,123 @@
+"""
+Dependency changes between two analysis runs
+
+Packages are matched by their stable IDs (see record_ids), so a result file
+written with or without --flat, or by an older gardener without `id` fields,
+compares the same way. A package whose ID only appears in the new run is
+added, one only in the old run removed, and one in both with another
+`version` changed
+"""
+
+import json
+
+from gardener.analysis.record_ids import package_id
+from gardener.common.compression import open_text_input
+
+
+def result_packages(results):
+    """
+    Return the external packages of a results document keyed by package ID
+
+    Args:
+        results (dict): Analysis results, with a flat external_packages map or grouped under `ecosystems`
+
+    Returns:
+        dict: Package ID -> {"name", "ecosystem", "version"}
+    """
+    packages = dict(results.get("external_packages") or {})
+    for ecosystem_packages in (results.get("ecosystems") or {}).values():
+        packages.update(ecosystem_packages)
+    return {
+        package_info.get("id") or package_id(name, package_info): {
+            "name": name,
+            "ecosystem": package_info.get("ecosystem") or "unknown",
+            "version": package_info.get("version") or "",
+        }
+        for name, package_info in packages.items()
+    }
+
+
+def load_result_file(path):
+    """
+    Read a saved analysis JSON (<prefix>_dependency_analysis.json or --json-out), gzipped or not
+
+    Args:
+        path (str): File path
+
+    Returns:
+        dict: The results document
+
+    Raises:
+        ValueError: If the file cannot be read or holds no analysis results
+    """
+    try:
+        with open_text_input(path) as f:
+            results = json.load(f)
+    except (OSError, EOFError, ValueError) as e:
+        raise ValueError(f"Cannot read analysis results {path}: {e}") from None
+    if not isinstance(results, dict) or not ("external_packages" in results or "ecosystems" in results):
+        raise ValueError(f"{path} is not a gardener analysis JSON file")
+    return results
+
+
+def diff_results(old_results, new_results):
+    """
+    Compare the external packages of two runs
+
+    Args:
+        old_results (dict): Baseline analysis results
+        new_results (dict): Analysis results to compare with the baseline
+
+    Returns:
+        dict: {"added", "removed", "changed", "summary"}: added and removed list {"id", "name", "ecosystem",
+            "version"} sorted by ID, changed lists {"id", "name", "ecosystem", "old_version", "new_version"},
+            and summary counts each of them
+    """
+    old_packages = result_packages(old_results)
+    new_packages = result_packages(new_results)
+    added = [dict(new_packages[key], id=key) for key in sorted(set(new_packages) - set(old_packages))]
+    removed = [dict(old_packages[key], id=key) for key in sorted(set(old_packages) - set(new_packages))]
+    changed = []
+    for key in sorted(set(old_packages) & set(new_packages)):
+        old_version = old_packages[key]["version"]
+        new_version = new_packages[key]["version"]
+        if old_version != new_version:
+            package = new_packages[key]
+            changed.append(
+                {
+                    "id": key,
+                    "name": package["name"],
+                    "ecosystem": package["ecosystem"],
+                    "old_version": old_version,
+                    "new_version": new_version,
+                }
+            )
+    return {
+        "added": added,
+        "removed": removed,
+        "changed": changed,
+        "summary": {"added": len(added), "removed": len(removed), "changed": len(changed)},
+    }
+
+
+def render_diff_summary(diff):
+    """
+    Render a dependency diff for reading
+
+    Args:
+        diff (dict): Result of diff_results
+
+    Returns:
+        str: A count line, then one `+`, `-` or `~` line per added, removed or changed package
+    """
+    summary = diff["summary"]
+    if not any(summary.values()):
+        return "No dependency changes\n"
+    lines = [f"{summary['added']} added, {summary['removed']} removed, {summary['changed']} version changes"]
+    lines.extend(f"+ {package['id']} {package['version']}".rstrip() for package in diff["added"])
+    lines.extend(f"- {package['id']} {package['version']}".rstrip() for package in diff["removed"])
+    lines.extend(
+        f"~ {package['id']} {package['old_version'] or '(none)'} -> {package['new_version'] or '(none)'}"
+        for package in diff["changed"]
+    )
+    return "\n".join(lines) + "\n"
@@ -0,0 +1,327 @@
+"""
+SBOM serialization of analysis results
+
+Converts the external packages detected by an analysis into standard software
+bill of materials documents
+"""
+
+import hashlib
+import re
+import uuid
+from datetime import datetime, timezone
+from urllib.parse import quote
+
+from gardener.common.utils import tool_version
+from gardener.treewalk.docker import DEFAULT_REGISTRY
+
+TOOL_NAME = "gardener"
+
+# Gardener ecosystem -> package-url type
+PURL_TYPES = {
+    "go": "golang",
+    "npm": "npm",
+    "pypi": "pypi",
+    "cargo": "cargo",
+    "docker": "docker",
+    "github-actions": "github",
+}
+
+# Dockerfile RUN installer -> package-url type of the packages it installs; packages of distribution
+# package managers are "generic" with the installer as namespace, as the distribution is not known
+INSTALLER_PURL_TYPES = {
+    "pip": "pypi",
+    "npm": "npm",
+    "go": "golang",
+    "cargo": "cargo",
+    "gem": "gem",
+}
+
+# Output format -> file suffix used by persistence backends
+SBOM_SUFFIXES = {
+    "cyclonedx": "_sbom.cdx.json",
+    "spdx": "_sbom.spdx.json",
+}
+
+SPDX_NOASSERTION = "NOASSERTION"
+SPDX_ROOT_ID = "SPDXRef-RootPackage"
+
+_RE_SPDXID_UNSAFE = re.compile(r"[^A-Za-z0-9.-]+")
+_RE_EXACT_VERSION = re.compile(r"^v?\d+(?:\.\d+)*(?:[-+][0-9A-Za-z.+-]+)?$")
+
+
+def exact_version(version):
+    """
+    Return a manifest version only when it pins a single release
+
+    Ranges such as "^4.17.0" or ">=2,<3" are not versions of a concrete component
+
+    Args:
+        version (str): Version or specifier as recorded in the manifest
+
+    Returns:
+        str|None: The pinned version, or None when unresolved
+    """
+    if not isinstance(version, str):
+        return None
+    candidate = version.strip()
+    if candidate.startswith("=="):
+        candidate = candidate[2:].strip()
+    elif candidate.startswith("="):
+        candidate = candidate[1:].strip()
+    return candidate if _RE_EXACT_VERSION.match(candidate) else None
+
+
+def _purl_name_parts(purl_type, package_name):
+    """
+    Split a package name into encoded purl namespace and name segments
+
+    Args:
+        purl_type (str): package-url type
+        package_name (str): Package name as reported by the analysis
+
+    Returns:
+        tuple: (namespace or "", name)
+    """
+    if purl_type == "pypi":
+        package_name = re.sub(r"[-_.]+", "-", package_name).lower()
+    elif purl_type in ("github", "docker"):
+        package_name = package_name.lower()
+    segments = [quote(segment, safe="") for segment in package_name.split("/") if segment]
+    return "/".join(segments[:-1]), segments[-1]
+
+
+def _purl_coordinates(package_name, package_info):
+    """
+    Map a detected package onto purl type, name, version, qualifiers and subpath
+
+    Docker images drop the registry from their name and record any registry other
+    than Docker Hub as the repository_url qualifier; GitHub actions keep owner/repo
+    as namespace/name and a path inside the repository as subpath; go:generate
+    tools from outside the Go ecosystem are generic under their tool name. Image tags and
+    digests and action refs are versions as written; manifest versions only when
+    they pin a single release (see exact_version)
+
+    Args:
+        package_name (str): Package name as reported by the analysis
+        package_info (dict): Package metadata from external_packages
+
+    Returns:
+        tuple: (purl type, name, version or None, {qualifier: value}, subpath or "")
+    """
+    ecosystem = package_info.get("ecosystem")
+    purl_type = PURL_TYPES.get(ecosystem, "generic")
+    version = exact_version(package_info.get("version"))
+    if ecosystem == "docker" and package_info.get("installer"):
+        installer = package_info["installer"]
+        name = package_info.get("package") or package_name
+        if installer in INSTALLER_PURL_TYPES:
+            return INSTALLER_PURL_TYPES[installer], name, version, {}, ""
+        return "generic", f"{installer}/{name}", version, {}, ""
+    if ecosystem == "docker":
+        registry, _, repository = package_name.partition("/")
+        qualifiers = {} if registry == DEFAULT_REGISTRY else {"repository_url": registry}
+        return purl_type, repository or registry, package_info.get("version") or None, qualifiers, ""
+    if ecosystem == "go-generate":
+        return "generic", package_info.get("package") or package_name, version, {}, ""
+    if ecosystem == "github-actions":
+        segments = package_name.split("/")
+        return purl_type, "/".join(segments[:2]), package_info.get("version") or None, {}, "/".join(segments[2:])
+    return purl_type, package_name, version, {}, ""
+
+
+def package_purl(package_name, package_info):
+    """
+    Build the package-url for a detected package
+
+    Args:
+        package_name (str): Package name as reported by the analysis
+        package_info (dict): Package metadata from external_packages
+
+    Returns:
+        str: purl such as "pkg:golang/github.com/gin-gonic/gin@v1.7.7", without a version
+            when none is pinned
+    """
+    purl_type, name, version, qualifiers, subpath = _purl_coordinates(package_name, package_info)
+    namespace, name = _purl_name_parts(purl_type, name)
+    purl = f"pkg:{purl_type}/{namespace}/{name}" if namespace else f"pkg:{purl_type}/{name}"
+    if version:
+        purl = f"{purl}@{quote(version, safe='')}"
+    if qualifiers:
+        purl += "?" + "&".join(f"{key}={quote(value, safe='')}" for key, value in sorted(qualifiers.items()))
+    segments = [quote(segment, safe="") for segment in subpath.split("/") if segment not in ("", ".", "..")]
+    if segments:
+        purl += "#" + "/".join(segments)
+    return purl
+
+
+def _cyclonedx_component(package_name, package_info, used_refs):
+    """
+    Convert one external package into a CycloneDX component
+
+    The purl is the bom-ref unless another component already uses it (e.g. a
+    Dockerfile `pip install requests` next to requirements.txt's requests)
+
+    Args:
+        package_name (str): Package name
+        package_info (dict): Package metadata from external_packages
+        used_refs (set): bom-refs of the components built so far, updated in place
+
+    Returns:
+        dict: CycloneDX component
+    """
+    purl = package_purl(package_name, package_info)
+    bom_ref = purl if purl not in used_refs else f"{TOOL_NAME}:package:{package_name}"
+    used_refs.add(bom_ref)
+    component = {"type": "library", "bom-ref": bom_ref, "name": package_name}
+    version = exact_version(package_info.get("version"))
+    if version:
+        component["version"] = version
+    component["purl"] = purl
+    repository_url = package_info.get("repository_url")
+    if repository_url:
+        component["externalReferences"] = [{"type": "vcs", "url": repository_url}]
+    return component
+
+
+def to_cyclonedx(results, root_name):
+    """
+    Serialize analysis results into a CycloneDX 1.5 JSON document
+
+    Args:
+        results (dict): Analysis results with an external_packages mapping
+        root_name (str): Name of the analyzed project, used for the root component
+
+    Returns:
+        dict: CycloneDX BOM
+    """
+    external_packages = results.get("external_packages", {})
+    used_refs = set()
+    components = [
+        _cyclonedx_component(name, external_packages[name], used_refs) for name in sorted(external_packages)
+    ]
+    root_ref = f"{TOOL_NAME}:root:{root_name}"
+
+    return {
+        "bomFormat": "CycloneDX",
+        "specVersion": "1.5",
+        "serialNumber": f"urn:uuid:{uuid.uuid4()}",
+        "version": 1,
+        "metadata": {
+            "timestamp": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+            "tools": {"components": [{"type": "application", "name": TOOL_NAME, "version": tool_version()}]},
+            "component": {"type": "application", "bom-ref": root_ref, "name": root_name},
+        },
+        "components": components,
+        "dependencies": [{"ref": root_ref, "dependsOn": [component["bom-ref"] for component in components]}],
+    }
+
+
+def spdx_package_id(package_name, package_info):
+    """
+    Build a deterministic SPDXID for a detected package
+
+    A short digest of the ecosystem and name keeps IDs unique when sanitizing
+    makes two names collide (e.g. "@a/b" and "a-b")
+
+    Args:
+        package_name (str): Package name
+        package_info (dict): Package metadata from external_packages
+
+    Returns:
+        str: SPDXID such as "SPDXRef-Package-golang-github.com-gin-gonic-gin-1a2b3c4d"
+    """
+    purl_type = PURL_TYPES.get(package_info.get("ecosystem"), "generic")
+    readable = _RE_SPDXID_UNSAFE.sub("-", package_name).strip("-") or "package"
+    digest = hashlib.sha256(f"{purl_type}:{package_name}".encode("utf-8")).hexdigest()[:8]
+    return f"SPDXRef-Package-{purl_type}-{readable}-{digest}"
+
+
+def _spdx_package(package_name, package_info):
+    """
+    Convert one external package into an SPDX package
+
+    Args:
+        package_name (str): Package name
+        package_info (dict): Package metadata from external_packages
+
+    Returns:
+        dict: SPDX 2.3 package
+    """
+    package = {"SPDXID": spdx_package_id(package_name, package_info), "name": package_name}
+    version = exact_version(package_info.get("version"))
+    if version:
+        package["versionInfo"] = version
+    package["downloadLocation"] = package_info.get("repository_url") or SPDX_NOASSERTION
+    package["filesAnalyzed"] = False
+    package["externalRefs"] = [
+        {
+            "referenceCategory": "PACKAGE-MANAGER",
+            "referenceType": "purl",
+            "referenceLocator": package_purl(package_name, package_info),
+        }
+    ]
+    return package
+
+
+def to_spdx(results, root_name):
+    """
+    Serialize analysis results into an SPDX 2.3 JSON document
+
+    Args:
+        results (dict): Analysis results with an external_packages mapping
+        root_name (str): Name of the analyzed project, used for the root package
+
+    Returns:
+        dict: SPDX document
+    """
+    external_packages = results.get("external_packages", {})
+    packages = [_spdx_package(name, external_packages[name]) for name in sorted(external_packages)]
+    root_package = {
+        "SPDXID": SPDX_ROOT_ID,
+        "name": root_name,
+        "downloadLocation": SPDX_NOASSERTION,
+        "filesAnalyzed": False,
+    }
+    relationships = [
+        {"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": SPDX_ROOT_ID}
+    ]
+    relationships.extend(
+        {"spdxElementId": SPDX_ROOT_ID, "relationshipType": "DEPENDS_ON", "relatedSpdxElement": package["SPDXID"]}
+        for package in packages
+    )
+
+    return {
+        "spdxVersion": "SPDX-2.3",
+        "dataLicense": "CC0-1.0",
+        "SPDXID": "SPDXRef-DOCUMENT",
+        "name": f"{root_name}-sbom",
+        "documentNamespace": f"https://spdx.org/spdxdocs/{TOOL_NAME}-{quote(root_name, safe='')}-{uuid.uuid4()}",
+        "creationInfo": {
+            "created": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+            "creators": [f"Tool: {TOOL_NAME}-{tool_version()}"],
+        },
+        "packages": [root_package] + packages,
+        "relationships": relationships,
+    }
+
+
+def render_sbom(results, output_format, root_name):
+    """
+    Render analysis results in the requested SBOM format
+
+    Args:
+        results (dict): Analysis results
+        output_format (str): One of SBOM_SUFFIXES
+        root_name (str): Name of the analyzed project
+
+    Returns:
+        dict: SBOM document
+
+    Raises:
+        ValueError: If the format is not supported
+    """
+    if output_format == "cyclonedx":
+        return to_cyclonedx(results, root_name)
+    if output_format == "spdx":
+        return to_spdx(results, root_name)
+    raise ValueError(f"Unsupported SBOM format: {output_format}")
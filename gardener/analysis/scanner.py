@@ -8,56 +8,331 @@ from pathlib import Path
 
 import pathspec
 
-from gardener.common.defaults import ResourceLimits
+from gardener.common.archives import archive_format
+from gardener.common.defaults import GoAnalysisConfig, ResourceLimits
+from gardener.common.file_helpers import to_posix_path
 from gardener.common.language_detection import filename_to_lang
+from gardener.common.secure_file_ops import FileOperationError
+from gardener.treewalk.base import is_manifest_name
+from gardener.treewalk.registry import BUILTIN_ANALYZERS
 
 # Local constants for JS/TS detection parity
 JS_TS_SOURCE_EXTS = [".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs"]
 
+# Languages for extensions filename_to_lang does not map
+_EXTENSION_FALLBACKS = {".cjs": "javascript", ".mjs": "javascript", ".svelte": "javascript"}
 
-def load_gitignore(secure_file_ops, logger):
+# Hidden directories that are scanned anyway because analyzers claim files in them (GitHub Actions workflows)
+_SCANNED_HIDDEN_DIRS = {".github"}
+
+
+class GitignoreRules:
+    """
+    Accumulated .gitignore rules for a repository, including nested .gitignore files
+
+    Patterns from a nested .gitignore are rewritten relative to the repository root
+    so that one ordered rule list reproduces git's precedence: later (deeper) files
+    override earlier ones and the last matching pattern, negations included, wins
+    """
+
+    def __init__(self):
+        self._lines = []
+        self._spec = None
+
+    def __bool__(self):
+        return bool(self._lines)
+
+    def add(self, base_dir, content):
+        """
+        Add the patterns of one .gitignore file
+
+        Args:
+            base_dir (str): Repo-relative POSIX directory holding the file ("." for the root)
+            content (str): File content
+        """
+        base = "" if base_dir in ("", ".") else base_dir.strip("/")
+        for raw_line in content.splitlines():
+            line = raw_line.rstrip()
+            if not line or line.startswith("#"):
+                continue
+            negated = line.startswith("!")
+            pattern = line[1:] if negated else line
+            if base:
+                # A slash anywhere but at the end anchors the pattern to its .gitignore directory
+                if "/" in pattern.rstrip("/"):
+                    pattern = f"{base}/{pattern.lstrip('/')}"
+                else:
+                    pattern = f"{base}/**/{pattern}"
+            self._lines.append(f"!{pattern}" if negated else pattern)
+        self._spec = None
+
+    def match_file(self, rel_path, is_dir=False):
+        """
+        Return True when a repo-relative path is ignored
+
+        Args:
+            rel_path (str): Repo-relative path
+            is_dir (bool): Whether the path is a directory, so directory-only patterns apply
+
+        Returns:
+            bool
+        """
+        if not self._lines:
+            return False
+        if self._spec is None:
+            self._spec = pathspec.PathSpec.from_lines(pathspec.patterns.GitWildMatchPattern, self._lines)
+        rel_path = str(rel_path).replace("\\", "/")
+        return self._spec.match_file(f"{rel_path}/" if is_dir else rel_path)
+
+
+GARDENER_IGNORE_FILENAME = ".gardenerignore"
+
+
+def _glob_to_regex(pattern):
+    """
+    Translate an exclude glob into a regular expression over repo-relative POSIX paths
+
+    `*` and `?` stay within one path segment, `**` spans any number of segments,
+    and `[...]` classes pass through. The whole path must match; a glob that
+    matches a directory excludes everything below it
+
+    Args:
+        pattern (str): Glob such as 'tests/fixtures/**' or '**/*_test.go'
+
+    Returns:
+        re.Pattern
+    """
+    glob = pattern.strip().replace("\\", "/")
+    while glob.startswith("./"):
+        glob = glob[2:]
+    glob = glob.strip("/")
+    out = []
+    i = 0
+    while i < len(glob):
+        if glob.startswith("**/", i):
+            out.append("(?:.*/)?")
+            i += 3
+        elif glob.startswith("/**", i) and i + 3 == len(glob):
+            out.append("(?:/.*)?")
+            i += 3
+        elif glob.startswith("**", i):
+            out.append(".*")
+            i += 2
+        elif glob[i] == "*":
+            out.append("[^/]*")
+            i += 1
+        elif glob[i] == "?":
+            out.append("[^/]")
+            i += 1
+        elif glob[i] == "[" and "]" in glob[i + 1 :]:
+            end = glob.index("]", i + 1)
+            out.append(glob[i : end + 1])
+            i = end + 1
+        else:
+            out.append(re.escape(glob[i]))
+            i += 1
+    return re.compile("^" + "".join(out) + "(?:/.*)?$")
+
+
+class ExcludeRules:
     """
-    Load .gitignore using secure file operations when available
+    Exclude globs from --exclude and the gardener ignore file
+
+    Unlike .gitignore rules there are no negations: a path matching any glob is excluded
+    """
+
+    def __init__(self, patterns=()):
+        self.patterns = []
+        self._regexes = []
+        for pattern in patterns:
+            self.add(pattern)
+
+    def __bool__(self):
+        return bool(self._regexes)
+
+    def add(self, pattern):
+        """
+        Add one glob; blank patterns are ignored
+
+        Args:
+            pattern (str): Repo-relative glob
+        """
+        if pattern and pattern.strip().strip("/"):
+            self.patterns.append(pattern.strip())
+            self._regexes.append(_glob_to_regex(pattern))
+
+    def match_file(self, rel_path, is_dir=False):
+        """
+        Return True when a repo-relative path is excluded
+
+        Args:
+            rel_path (str): Repo-relative path
+            is_dir (bool): Unused; accepted for parity with GitignoreRules.match_file
+
+        Returns:
+            bool
+        """
+        rel_path = str(rel_path).replace("\\", "/")
+        return any(regex.match(rel_path) for regex in self._regexes)
+
+
+def parse_gardener_ignore(content):
+    """
+    Parse gardener ignore file content: one glob per line, '#' starts a comment line
+
+    Args:
+        content (str): File content
+
+    Returns:
+        list: Globs in file order
+    """
+    patterns = []
+    for raw_line in content.splitlines():
+        line = raw_line.strip()
+        if line and not line.startswith("#"):
+            patterns.append(line)
+    return patterns
+
+
+def load_exclude_rules(secure_file_ops, logger, repo_path=None):
+    """
+    Collect exclude globs from ResourceLimits.EXCLUDE_PATTERNS and the gardener ignore file
+
+    The ignore file is ResourceLimits.GARDENER_IGNORE_FILE when set, else a
+    .gardenerignore at the repository root if there is one
 
     Args:
         secure_file_ops (SecureFileOps|None): SecureFileOps instance if available
         logger (Logger|None): Optional logger
+        repo_path (str|None): Absolute repository path
 
     Returns:
-        pathspec.PathSpec|None: Compiled gitignore rules
+        ExcludeRules|None: Rules, or None when there are no globs
     """
-    if not secure_file_ops:
+    patterns = ResourceLimits.EXCLUDE_PATTERNS
+    rules = ExcludeRules([patterns] if isinstance(patterns, str) else patterns)
+
+    ignore_file = ResourceLimits.GARDENER_IGNORE_FILE
+    content = None
+    try:
+        if ignore_file:
+            with open(ignore_file, "r", encoding="utf-8", errors="ignore") as handle:
+                content = handle.read()
+        elif secure_file_ops:
+            if secure_file_ops.exists(GARDENER_IGNORE_FILENAME):
+                ignore_file = GARDENER_IGNORE_FILENAME
+                content = secure_file_ops.read_file(GARDENER_IGNORE_FILENAME)
+        elif repo_path and os.path.isfile(os.path.join(repo_path, GARDENER_IGNORE_FILENAME)):
+            ignore_file = GARDENER_IGNORE_FILENAME
+            with open(os.path.join(repo_path, GARDENER_IGNORE_FILENAME), "r", encoding="utf-8", errors="ignore") as f:
+                content = f.read()
+    except Exception as exc:
+        if logger:
+            logger.warning(f"Could not read gardener ignore file {ignore_file}: {exc}")
+
+    if content is not None:
+        for pattern in parse_gardener_ignore(content):
+            rules.add(pattern)
+
+    if not rules:
         return None
+    if logger:
+        logger.info(f"Excluding paths matching: {', '.join(rules.patterns)}")
+    return rules
+
+
+def _read_gitignore(rel_dir, repo_path, secure_file_ops):
+    """
+    Read the .gitignore in a repository directory
 
-    gitignore_path = ".gitignore"
-    if not secure_file_ops.exists(gitignore_path):
+    Args:
+        rel_dir (str): Repo-relative directory ("." for the root)
+        repo_path (str): Absolute repository path
+        secure_file_ops (SecureFileOps|None): Secure file operations or None
+
+    Returns:
+        str|None: File content, or None when there is no .gitignore
+    """
+    rel_path = ".gitignore" if rel_dir in ("", ".") else f"{rel_dir}/.gitignore"
+    if secure_file_ops:
+        if not secure_file_ops.exists(rel_path):
+            return None
+        return secure_file_ops.read_file(rel_path)
+    full_path = os.path.join(repo_path, rel_path)
+    if not os.path.isfile(full_path):
         return None
+    with open(full_path, "r", encoding="utf-8", errors="ignore") as handle:
+        return handle.read()
 
+
+def _load_nested_gitignore(rules, rel_dir, repo_path, secure_file_ops, logger):
+    """
+    Add the .gitignore of a directory to the accumulated rules, if present
+
+    Args:
+        rules (GitignoreRules|None): Rules being accumulated, or None when .gitignore is not honored
+        rel_dir (str): Repo-relative POSIX directory
+        repo_path (str): Absolute repository path
+        secure_file_ops (SecureFileOps|None): Secure file operations or None
+        logger (Logger|None): Optional logger
+    """
+    if rules is None:
+        return
     try:
-        gitignore_content = secure_file_ops.read_file(gitignore_path)
-        return pathspec.PathSpec.from_lines(
-            pathspec.patterns.GitWildMatchPattern, gitignore_content.splitlines()
-        )
+        content = _read_gitignore(rel_dir, repo_path, secure_file_ops)
     except Exception as exc:
         if logger:
-            logger.warning(f"Could not load or parse .gitignore: {exc}")
+            logger.warning(f"Could not load or parse {rel_dir}/.gitignore: {exc}")
+        return
+    if content is not None:
+        rules.add(rel_dir, content)
+
+
+def load_gitignore(secure_file_ops, logger, repo_path=None):
+    """
+    Load the root .gitignore using secure file operations when available
+
+    Nested .gitignore files are added while the repository is walked
+
+    Args:
+        secure_file_ops (SecureFileOps|None): SecureFileOps instance if available
+        logger (Logger|None): Optional logger
+        repo_path (str|None): Absolute repository path, needed without secure file operations
+
+    Returns:
+        GitignoreRules|None: Root rules, or None when ResourceLimits.RESPECT_GITIGNORE is off
+    """
+    if not ResourceLimits.RESPECT_GITIGNORE or (not secure_file_ops and not repo_path):
         return None
 
+    rules = GitignoreRules()
+    try:
+        content = _read_gitignore(".", repo_path, secure_file_ops)
+        if content is not None:
+            rules.add(".", content)
+    except Exception as exc:
+        if logger:
+            logger.warning(f"Could not load or parse .gitignore: {exc}")
+    return rules
 
-def _is_ignored(path, repo_path, gitignore_spec, secure_file_ops):
+
+def _is_ignored(path, repo_path, gitignore_spec, secure_file_ops, is_dir=False, exclude_rules=None, logger=None):
     """
-    Determine whether a path should be ignored according to .gitignore
+    Determine whether a path should be ignored according to .gitignore or the exclude globs
 
     Args:
         path (str): Absolute path to test
         repo_path (str): Absolute repository root path
-        gitignore_spec (pathspec.PathSpec|None): Compiled matcher or None
+        gitignore_spec (GitignoreRules|None): Accumulated rules or None
         secure_file_ops (SecureFileOps|None): Secure file operations or None
+        is_dir (bool): Whether the path is a directory
+        exclude_rules (ExcludeRules|None): Globs from --exclude and the gardener ignore file
+        logger (Logger|None): Optional logger; ignored paths are logged at the debug level
 
     Returns:
-        bool: True when path is ignored by the matcher
+        bool: True when path is ignored by either matcher
     """
-    if not gitignore_spec:
+    if not gitignore_spec and not exclude_rules:
         return False
 
     try:
@@ -65,11 +340,191 @@ def _is_ignored(path, repo_path, gitignore_spec, secure_file_ops):
             rel_path = secure_file_ops.get_relative_path(path)
         else:
             rel_path = os.path.relpath(path, repo_path)
-    except ValueError:
+    except (ValueError, FileOperationError):
+        # A symlink leaving the repository, skipped by SymlinkGuard
+        return False
+
+    rel_path = Path(rel_path).as_posix()
+    reason = None
+    if exclude_rules and exclude_rules.match_file(rel_path, is_dir=is_dir):
+        reason = "excluded"
+    elif gitignore_spec and gitignore_spec.match_file(rel_path, is_dir=is_dir):
+        reason = "gitignore"
+    if reason and logger:
+        _log_skipped(logger, rel_path, reason, is_dir=is_dir)
+    return reason is not None
+
+
+def _log_skipped(logger, rel_path, reason, is_dir=False):
+    """
+    Log a path left out of the scan
+
+    Args:
+        logger (Logger): Logger
+        rel_path (str): Repo-relative path
+        reason (str): Why it was skipped, e.g. "gitignore", "excluded", "vendor" or "submodule"
+        is_dir (bool): Whether the whole directory was skipped
+    """
+    kind = "directory" if is_dir else "file"
+    logger.debug(f"Skipping {kind} {rel_path} ({reason})", event="path-skipped", path=rel_path, reason=reason)
+
+
+class SymlinkGuard:
+    """
+    Symlink and duplicate-path checks of one repository walk
+
+    Symlinked files and directories are skipped unless FOLLOW_SYMLINKS is set
+    (--follow-symlinks). When they are followed, links leaving the repository
+    are still skipped, a directory link whose target encloses the link is a
+    cycle, skipped with a warning, and a file or directory whose real path the
+    walk already reached is skipped as a duplicate, so the first path in walk
+    order wins
+
+    Args:
+        repo_path (str): Absolute repository path
+        logger (Logger|None): Optional logger for skipped paths and cycles
+    """
+
+    def __init__(self, repo_path, logger=None):
+        self.repo_path = repo_path
+        self.real_root = os.path.realpath(repo_path)
+        self.logger = logger
+        self.visited = {self.real_root}
+
+    def admit(self, path, is_dir, ancestors):
+        """
+        Check one directory entry of the walk and mark its real path as visited
+
+        Args:
+            path (str): Absolute path as listed by the walk
+            is_dir (bool): Whether the path is (or links to) a directory
+            ancestors (tuple): Real paths of the directories enclosing path, from the repository root down
+
+        Returns:
+            str|None: The entry's real path, or None when it is skipped
+        """
+        # The secure walk lists directories by their real path
+        base = self.real_root if path.startswith(self.real_root + os.sep) else self.repo_path
+        rel_path = to_posix_path(os.path.relpath(path, base))
+        is_link = os.path.islink(path)
+        if not is_link:
+            real_path = os.path.join(ancestors[-1], os.path.basename(path))
+        elif not ResourceLimits.FOLLOW_SYMLINKS:
+            return self._skip(rel_path, "symlink", is_dir)
+        else:
+            real_path = os.path.realpath(path)
+            # The secure walk does not see links leaving the repository as directories
+            is_dir = is_dir or os.path.isdir(real_path)
+            if is_dir and real_path in ancestors:
+                if self.logger:
+                    target = to_posix_path(os.path.relpath(real_path, self.real_root))
+                    self.logger.warning(
+                        f"Skipping symlink cycle: {rel_path} links to its enclosing directory {target}",
+                        event="symlink-cycle",
+                        path=rel_path,
+                        target=target,
+                    )
+                return None
+            if not real_path.startswith(self.real_root + os.sep):
+                return self._skip(rel_path, "symlink-outside-repo", is_dir)
+        if real_path in self.visited:
+            return self._skip(rel_path, "duplicate", is_dir)
+        self.visited.add(real_path)
+        return real_path
+
+    def _skip(self, rel_path, reason, is_dir):
+        if self.logger:
+            _log_skipped(self.logger, rel_path, reason, is_dir=is_dir)
+        return None
+
+
+def _count_files_beyond_depth(dir_path, repo_path, gitignore_spec, all_manifest_files, all_extensions,
+                              active_languages, exclude_rules=None, matchers=(), submodule_paths=()):
+    """
+    Count the files the scan would have collected below a directory left out by --max-depth
+
+    The directory is only listed, never analyzed; hidden directories, symlinks,
+    .gitignore and exclude rules are honored as in the walk, except for .gitignore
+    files nested inside the directory
+
+    Args:
+        dir_path (str): Absolute path of the directory beyond the depth limit
+        repo_path (str): Absolute repository path
+        gitignore_spec (GitignoreRules|None): Rules loaded so far
+        all_manifest_files (set): Manifest patterns of the active handlers
+        all_extensions (set): File extensions of the active built-in handlers
+        active_languages (list): Languages that are active for this scan
+        exclude_rules (ExcludeRules|None): Globs from --exclude and the gardener ignore file
+        matchers (list): (language, analyzer) pairs that claim files with analyzer.matches()
+
+    Returns:
+        int: Number of source and manifest files below dir_path
+    """
+    count = 0
+    for root, dirs, files in os.walk(dir_path, topdown=True):
+        dirs[:] = [
+            d
+            for d in dirs
+            if (not d.startswith(".") or d in _SCANNED_HIDDEN_DIRS)
+            and (ResourceLimits.FOLLOW_SYMLINKS or not Path(Path(root) / d).is_symlink())
+            and not _is_ignored(
+                str(Path(root) / d), repo_path, gitignore_spec, None, is_dir=True, exclude_rules=exclude_rules
+            )
+        ]
+        for file_name in files:
+            file_path = str(Path(root) / file_name)
+            if not ResourceLimits.FOLLOW_SYMLINKS and Path(file_path).is_symlink():
+                continue
+            if _is_ignored(file_path, repo_path, gitignore_spec, None, exclude_rules=exclude_rules):
+                continue
+            rel_path = os.path.relpath(file_path, repo_path)
+            language = _source_language(rel_path, os.path.splitext(file_name)[1], all_extensions, matchers)
+            if is_manifest_name(rel_path, all_manifest_files) or (
+                language in active_languages
+                and not _is_vendored_go_source(rel_path, language)
+                and not _is_submodule_source(rel_path, submodule_paths)
+            ):
+                count += 1
+    return count
+
+
+def _is_vendored_go_source(rel_path, language):
+    """
+    Determine whether a Go source file lives under a vendor/ directory
+
+    Vendored packages are third-party code; their own imports are not evidence
+    about the analyzed repository unless GoAnalysisConfig.SCAN_VENDOR is set
+
+    Args:
+        rel_path (str): Repository-relative file path
+        language (str): Detected language of the file
+
+    Returns:
+        bool: True when the file should be excluded from source scanning
+    """
+    if language != "go" or GoAnalysisConfig.SCAN_VENDOR:
         return False
+    return "vendor" in Path(rel_path).parts[:-1]
+
+
+def _is_submodule_source(rel_path, submodule_paths):
+    """
+    Determine whether a source file lives inside a git submodule checkout
 
-    rel_path = str(Path(rel_path))
-    return gitignore_spec.match_file(rel_path)
+    Submodules are other repositories vendored as source; their files are not
+    first-party evidence unless ResourceLimits.SCAN_SUBMODULES is set
+
+    Args:
+        rel_path (str): Repository-relative file path
+        submodule_paths (tuple): Repository-relative POSIX paths of the submodules
+
+    Returns:
+        bool: True when the file should be excluded from source scanning
+    """
+    if not submodule_paths or ResourceLimits.SCAN_SUBMODULES:
+        return False
+    posix_path = to_posix_path(rel_path)
+    return any(posix_path.startswith(f"{path}/") for path in submodule_paths)
 
 
 def _parse_foundry_src_path(secure_file_ops, logger):
@@ -118,46 +573,39 @@ def _parse_foundry_src_path(secure_file_ops, logger):
     return None
 
 
-def _scan_secure(repo_path, secure_file_ops, gitignore_spec, all_manifest_files,
-                 all_extensions, active_languages, logger):
+def _scan_secure(repo_path, secure_file_ops, gitignore_spec, all_manifest_files, all_extensions, active_languages,
+                 logger, exclude_rules=None, matchers=(), max_depth=-1, submodule_paths=()):
     """
     Secure directory traversal
 
     Args:
         repo_path (str): Absolute repository path
         secure_file_ops (SecureFileOps): Secure file operations instance
-        gitignore_spec (pathspec.PathSpec|None): Compiled matcher or None
+        gitignore_spec (GitignoreRules|None): Rules, extended with nested .gitignore files during the walk
         all_manifest_files (set): Set of manifest basenames to collect
         all_extensions (set): File extensions to include in scan
         active_languages (list): Languages that are active for this scan
         logger (Logger|None): Optional logger for progress and warnings
+        exclude_rules (ExcludeRules|None): Globs from --exclude and the gardener ignore file
+        matchers (list): (language, analyzer) pairs that claim files with analyzer.matches()
+        max_depth (int): Deepest directory level to enter (0 = repository root only); -1 is unlimited
+        submodule_paths (tuple): Repo-relative paths of git submodules, whose source files are skipped
 
     Returns:
-        Tuple of (source_files, manifest_files, root_manifest_files, js_config_files, ts_config_files)
+        Tuple of (source_files, manifest_files, root_manifest_files, js_config_files, ts_config_files,
+            skipped_by_depth), where skipped_by_depth counts the files below directories beyond max_depth
     """
     source_files = {}
     manifest_files = []
     root_manifest_files = []
     js_config_files = []
     ts_config_files = []
+    skipped_by_depth = 0
 
-    visited_dirs = set()
-
-    def _scan_dir_recursive(dir_path):
-        try:
-            resolved_path = str(Path(str(dir_path)).resolve())
-        except (OSError, RuntimeError) as exc:
-            if logger:
-                logger.debug(f"Skipping directory due to resolution error: {exc}")
-            return
-
-        if resolved_path in visited_dirs:
-            if logger:
-                logger.debug(f"Already visited directory: {resolved_path}")
-            return
-
-        visited_dirs.add(resolved_path)
+    guard = SymlinkGuard(repo_path, logger)
 
+    def _scan_dir_recursive(dir_path, depth=0, ancestors=(guard.real_root,)):
+        nonlocal skipped_by_depth
         try:
             entries = secure_file_ops.list_dir(dir_path)
         except Exception as exc:
@@ -170,23 +618,35 @@ def _scan_secure(repo_path, secure_file_ops, gitignore_spec, all_manifest_files,
         except Exception:
             entries = list(entries)
 
+        rel_dir = Path(secure_file_ops.get_relative_path(str(dir_path))).as_posix()
+        if rel_dir != ".":
+            _load_nested_gitignore(gitignore_spec, rel_dir, repo_path, secure_file_ops, logger)
+
         for entry in entries:
-            if entry.name.startswith("."):
+            if entry.name.startswith(".") and entry.name not in _SCANNED_HIDDEN_DIRS:
                 continue
 
             full_path = str(entry)
 
-            try:
-                if not ResourceLimits.FOLLOW_SYMLINKS and Path(full_path).is_symlink():
-                    continue
-            except Exception:
-                pass
-
-            if _is_ignored(full_path, repo_path, gitignore_spec, secure_file_ops):
+            is_dir = secure_file_ops.is_dir(entry)
+            if _is_ignored(full_path, repo_path, gitignore_spec, secure_file_ops, is_dir=is_dir,
+                           exclude_rules=exclude_rules, logger=logger):
+                continue
+            real_path = guard.admit(full_path, is_dir, ancestors)
+            if real_path is None:
                 continue
 
-            if secure_file_ops.is_dir(entry):
-                _scan_dir_recursive(entry)
+            if is_dir:
+                if 0 <= max_depth <= depth:
+                    skipped_by_depth += _count_files_beyond_depth(
+                        full_path, repo_path, gitignore_spec, all_manifest_files, all_extensions,
+                        active_languages, exclude_rules=exclude_rules, matchers=matchers,
+                        submodule_paths=submodule_paths,
+                    )
+                    if logger:
+                        _log_skipped(logger, secure_file_ops.get_relative_path(full_path), "max-depth", is_dir=True)
+                    continue
+                _scan_dir_recursive(entry, depth + 1, ancestors + (real_path,))
                 continue
 
             if not secure_file_ops.is_file(entry):
@@ -196,7 +656,7 @@ def _scan_secure(repo_path, secure_file_ops, gitignore_spec, all_manifest_files,
             basename = entry.name
             _, ext = os.path.splitext(basename)
 
-            if basename in all_manifest_files:
+            if is_manifest_name(rel_path, all_manifest_files):
                 manifest_files.append(full_path)
                 if str(Path(rel_path).parent) == ".":
                     root_manifest_files.append(full_path)
@@ -206,12 +666,16 @@ def _scan_secure(repo_path, secure_file_ops, gitignore_spec, all_manifest_files,
             elif basename == "tsconfig.json":
                 ts_config_files.append(full_path)
 
-            if ext in all_extensions:
-                language = filename_to_lang(full_path)
-                if language is None:
-                    language = {".cjs": "javascript", ".mjs": "javascript", ".svelte": "javascript"}.get(ext)
-                if language and language in active_languages:
-                    source_files[str(Path(rel_path))] = {
+            language = _source_language(rel_path, ext, all_extensions, matchers)
+            if language and language in active_languages:
+                if _is_vendored_go_source(rel_path, language):
+                    if logger:
+                        _log_skipped(logger, rel_path, "vendor")
+                elif _is_submodule_source(rel_path, submodule_paths):
+                    if logger:
+                        _log_skipped(logger, rel_path, "submodule")
+                else:
+                    source_files[to_posix_path(rel_path)] = {
                         "absolute_path": full_path,
                         "language": language,
                     }
@@ -223,58 +687,89 @@ def _scan_secure(repo_path, secure_file_ops, gitignore_spec, all_manifest_files,
         root_manifest_files,
         js_config_files,
         ts_config_files,
+        skipped_by_depth,
     )
 
 
-def _scan_standard(repo_path, gitignore_spec, all_manifest_files, all_extensions, active_languages, logger):
+def _scan_standard(repo_path, gitignore_spec, all_manifest_files, all_extensions, active_languages, logger,
+                   exclude_rules=None, matchers=(), max_depth=-1, submodule_paths=()):
     """
     Fallback os.walk scan
 
     Args:
         repo_path (str): Absolute repository path
-        gitignore_spec (pathspec.PathSpec|None): Compiled matcher or None
+        gitignore_spec (GitignoreRules|None): Rules, extended with nested .gitignore files during the walk
         all_manifest_files (set): Set of manifest basenames to collect
         all_extensions (set): File extensions to include in scan
         active_languages (list): Languages that are active for this scan
         logger (Logger|None): Optional logger for progress and warnings
+        exclude_rules (ExcludeRules|None): Globs from --exclude and the gardener ignore file
+        matchers (list): (language, analyzer) pairs that claim files with analyzer.matches()
+        max_depth (int): Deepest directory level to enter (0 = repository root only); -1 is unlimited
+        submodule_paths (tuple): Repo-relative paths of git submodules, whose source files are skipped
 
     Returns:
-        Tuple of (source_files, manifest_files, root_manifest_files, js_config_files, ts_config_files)
+        Tuple of (source_files, manifest_files, root_manifest_files, js_config_files, ts_config_files,
+            skipped_by_depth), where skipped_by_depth counts the files below directories beyond max_depth
     """
     source_files = {}
     manifest_files = []
     root_manifest_files = []
     js_config_files = []
     ts_config_files = []
-
-    for root, dirs, files in os.walk(repo_path, topdown=True):
+    skipped_by_depth = 0
+    guard = SymlinkGuard(repo_path, logger)
+    # Walked directory -> real paths of it and its enclosing directories
+    ancestors = {repo_path: (guard.real_root,)}
+
+    for root, dirs, files in os.walk(repo_path, topdown=True, followlinks=ResourceLimits.FOLLOW_SYMLINKS):
+        rel_dir = Path(os.path.relpath(root, repo_path)).as_posix()
+        if rel_dir != ".":
+            _load_nested_gitignore(gitignore_spec, rel_dir, repo_path, None, logger)
         filtered_dirs = [
             d
             for d in dirs
-            if not d.startswith(".")
-            and not _is_ignored(str(Path(root) / d), repo_path, gitignore_spec, None)
+            if (not d.startswith(".") or d in _SCANNED_HIDDEN_DIRS)
+            and not _is_ignored(
+                str(Path(root) / d), repo_path, gitignore_spec, None, is_dir=True, exclude_rules=exclude_rules,
+                logger=logger,
+            )
         ]
-        if not ResourceLimits.FOLLOW_SYMLINKS:
-            filtered_dirs = [
-                d for d in filtered_dirs if not Path(Path(root) / d).is_symlink()
-            ]
+        admitted_dirs = []
+        for d in sorted(filtered_dirs):
+            real_path = guard.admit(str(Path(root) / d), True, ancestors[root])
+            if real_path is not None:
+                ancestors[str(Path(root) / d)] = ancestors[root] + (real_path,)
+                admitted_dirs.append(d)
+        filtered_dirs = admitted_dirs
+        depth = 0 if rel_dir == "." else len(rel_dir.split("/"))
+        if 0 <= max_depth <= depth:
+            for d in filtered_dirs:
+                skipped_by_depth += _count_files_beyond_depth(
+                    str(Path(root) / d), repo_path, gitignore_spec, all_manifest_files, all_extensions,
+                    active_languages, exclude_rules=exclude_rules, matchers=matchers,
+                    submodule_paths=submodule_paths,
+                )
+                if logger:
+                    _log_skipped(logger, os.path.relpath(str(Path(root) / d), repo_path), "max-depth", is_dir=True)
+            filtered_dirs = []
         dirs[:] = sorted(filtered_dirs)
 
         for file_name in sorted(files):
             file_path = str(Path(root) / file_name)
-            if not ResourceLimits.FOLLOW_SYMLINKS and Path(file_path).is_symlink():
+            if _is_ignored(file_path, repo_path, gitignore_spec, None, exclude_rules=exclude_rules, logger=logger):
                 continue
-            if _is_ignored(file_path, repo_path, gitignore_spec, None):
+            if guard.admit(file_path, False, ancestors[root]) is None:
                 continue
             try:
                 rel_path = str(Path(file_path).relative_to(repo_path))
             except ValueError:
                 rel_path = os.path.relpath(file_path, repo_path)
-            rel_path = str(Path(rel_path))
+            rel_path = to_posix_path(rel_path)
             basename = Path(file_path).name
             _, ext = os.path.splitext(basename)
 
-            if basename in all_manifest_files:
+            if is_manifest_name(rel_path, all_manifest_files):
                 manifest_files.append(file_path)
                 if str(Path(rel_path).parent) == ".":
                     root_manifest_files.append(file_path)
@@ -284,11 +779,15 @@ def _scan_standard(repo_path, gitignore_spec, all_manifest_files, all_extensions
             elif basename == "tsconfig.json":
                 ts_config_files.append(file_path)
 
-            if ext in all_extensions:
-                language = filename_to_lang(file_path)
-                if language is None:
-                    language = {".cjs": "javascript", ".mjs": "javascript", ".svelte": "javascript"}.get(ext)
-                if language and language in active_languages:
+            language = _source_language(rel_path, ext, all_extensions, matchers)
+            if language and language in active_languages:
+                if _is_vendored_go_source(rel_path, language):
+                    if logger:
+                        _log_skipped(logger, rel_path, "vendor")
+                elif _is_submodule_source(rel_path, submodule_paths):
+                    if logger:
+                        _log_skipped(logger, rel_path, "submodule")
+                else:
                     source_files[rel_path] = {"absolute_path": file_path, "language": language}
 
     return (
@@ -297,12 +796,13 @@ def _scan_standard(repo_path, gitignore_spec, all_manifest_files, all_extensions
         root_manifest_files,
         js_config_files,
         ts_config_files,
+        skipped_by_depth,
     )
 
 
-def parse_gitmodules(repo_path, secure_file_ops, logger):
+def read_gitmodules(repo_path, secure_file_ops, logger):
     """
-    Parse .gitmodules and return {normalized_path: url}
+    Read the submodules declared in the repository's .gitmodules
 
     Args:
         repo_path (str): Absolute repository path
@@ -310,7 +810,9 @@ def parse_gitmodules(repo_path, secure_file_ops, logger):
         logger (Logger|None): Optional logger for warnings and errors
 
     Returns:
-        dict: Map of normalized submodule paths to repository URLs
+        list: {"path", "url", "branch"} dicts in file order, path repo-relative with
+            forward slashes and branch None when not set; sections without a path
+            or url are skipped
     """
     import configparser
 
@@ -318,11 +820,11 @@ def parse_gitmodules(repo_path, secure_file_ops, logger):
 
     if secure_file_ops:
         if not secure_file_ops.exists(gitmodules_rel_path):
-            return {}
+            return []
     else:
         gitmodules_abs = Path(repo_path) / gitmodules_rel_path
         if not gitmodules_abs.exists():
-            return {}
+            return []
 
     config = configparser.ConfigParser()
     try:
@@ -333,53 +835,292 @@ def parse_gitmodules(repo_path, secure_file_ops, logger):
             with open(Path(repo_path) / gitmodules_rel_path, "r", encoding="utf-8") as handle:
                 config.read_file(handle)
 
-        parsed = {}
+        submodules = []
         for section in config.sections():
             if config.has_option(section, "path") and config.has_option(section, "url"):
-                sub_path_raw = config.get(section, "path")
-                url = config.get(section, "url")
-                normalized_path = str(Path(sub_path_raw).resolve()).rstrip(os.sep)
-                parsed[normalized_path] = url
-        return parsed
+                path = to_posix_path(config.get(section, "path")).strip().strip("/")
+                if path.startswith("./"):
+                    path = path[2:]
+                submodules.append(
+                    {
+                        "path": path,
+                        "url": config.get(section, "url").strip(),
+                        "branch": config.get(section, "branch", fallback=None),
+                    }
+                )
+        return submodules
     except configparser.Error as exc:
         if logger:
             logger.warning(f"Could not parse .gitmodules file at '{gitmodules_rel_path}': {exc}")
-        return {}
+        return []
     except Exception as exc:
         if logger:
             logger.error(
                 f"An unexpected error occurred while parsing .gitmodules at '{gitmodules_rel_path}': {exc}"
             )
-        return {}
+        return []
 
 
-def scan_repository(repo_path, secure_file_ops, focus_languages, language_handlers, logger):
+def parse_gitmodules(repo_path, secure_file_ops, logger, submodules=None):
     """
-    High-level entry point
+    Parse .gitmodules and return {normalized_path: url}
 
     Args:
         repo_path (str): Absolute repository path
         secure_file_ops (SecureFileOps|None): Secure file operations or None
+        logger (Logger|None): Optional logger for warnings and errors
+        submodules (list|None): Entries already read by read_gitmodules, or None to read them
+
+    Returns:
+        dict: Map of normalized submodule paths to repository URLs
+    """
+    if submodules is None:
+        submodules = read_gitmodules(repo_path, secure_file_ops, logger)
+    return {str(Path(submodule["path"]).resolve()).rstrip(os.sep): submodule["url"] for submodule in submodules}
+
+
+def _source_language(rel_path, ext, all_extensions, matchers):
+    """
+    Return the language of a file, or None when no active analyzer claims it
+
+    Files with a built-in extension are mapped through filename_to_lang; other
+    files are offered to the matches() of registered analyzers in order
+
+    Args:
+        rel_path (str): Repo-relative file path
+        ext (str): File extension, with dot
+        all_extensions (set): Extensions of the active built-in handlers
+        matchers (list): (language, analyzer) pairs of active registered analyzers
+
+    Returns:
+        str|None
+    """
+    if ext in all_extensions:
+        language = filename_to_lang(rel_path) or _EXTENSION_FALLBACKS.get(ext)
+        if language:
+            return language
+    posix_path = Path(rel_path).as_posix()
+    for language, analyzer in matchers:
+        try:
+            if analyzer.matches(posix_path):
+                return language
+        except Exception:
+            continue
+    return None
+
+
+def _language_setup(focus_languages, language_handlers):
+    """
+    Collect what the active analyzers claim
+
+    Args:
         focus_languages (list|None): Subset of languages to analyze or None for all
         language_handlers (dict): Language handler instances keyed by language
-        logger (Logger|None): Optional logger for progress and warnings
 
     Returns:
-        dict: Keys: source_files, manifest_files, root_manifest_files, js_config_files,
-            ts_config_files, solidity_src_path, submodule_data, gitignore_spec
+        tuple: (active_languages, manifest basenames, built-in extensions, (language, analyzer) matchers of
+            registered analyzers)
     """
-    gitignore_spec = load_gitignore(secure_file_ops, logger)
-
     active_languages = focus_languages or list(language_handlers.keys())
     all_manifest_files = set()
     all_extensions = set()
 
+    matchers = []
+
     for lang in active_languages:
         handler = language_handlers.get(lang)
         if not handler:
             continue
-        all_manifest_files.update(handler.get_manifest_files())
-        all_extensions.update(handler.get_file_extensions())
+        if hasattr(handler, "get_manifest_files"):
+            all_manifest_files.update(handler.get_manifest_files())
+        if type(handler) is BUILTIN_ANALYZERS.get(lang) and hasattr(handler, "get_file_extensions"):
+            all_extensions.update(handler.get_file_extensions())
+        else:
+            # Registered analyzers claim their files with matches()
+            matchers.append((lang, handler))
+    return active_languages, all_manifest_files, all_extensions, matchers
+
+
+def parse_file_list(text):
+    """
+    Return the paths of a newline-delimited file list, as read by --files-from
+
+    Args:
+        text (str): One path per line; blank lines are skipped and CRLF line ends accepted
+
+    Returns:
+        list: Paths in list order, without duplicates
+    """
+    paths = []
+    for line in text.splitlines():
+        path = line.strip()
+        if path and path not in paths:
+            paths.append(path)
+    return paths
+
+
+def listed_source_files(repo_path, paths, focus_languages, language_handlers, submodule_paths=(), logger=None):
+    """
+    Build the source file map of an explicit file list instead of walking the tree
+
+    Listed files are analyzed even when .gitignore, --exclude or the gardener
+    ignore file would skip them; vendored Go sources and submodule files are
+    still left out, as is any file no active analyzer claims
+
+    Args:
+        repo_path (str): Absolute repository path
+        paths (list): File paths, relative to the repository root or absolute
+        focus_languages (list|None): Subset of languages to analyze or None for all
+        language_handlers (dict): Language handler instances keyed by language
+        submodule_paths (tuple): Repo-relative paths of git submodules
+        logger (Logger|None): Optional logger for skipped paths
+
+    Returns:
+        tuple: (source_files, missing_files), source_files shaped like a scan's and missing_files the listed
+            paths that are not files inside the repository
+    """
+    active_languages, _, all_extensions, matchers = _language_setup(focus_languages, language_handlers)
+    real_root = os.path.realpath(repo_path)
+    source_files = {}
+    missing_files = []
+    for path in paths:
+        rel_path = os.path.relpath(path, repo_path) if os.path.isabs(path) else path
+        rel_path = Path(os.path.normpath(to_posix_path(rel_path))).as_posix()
+        full_path = os.path.join(repo_path, rel_path)
+        real_path = os.path.realpath(full_path)
+        inside = real_path == real_root or real_path.startswith(real_root + os.sep)
+        if rel_path == ".." or rel_path.startswith("../") or not inside or not os.path.isfile(full_path):
+            missing_files.append(path)
+            continue
+        language = _source_language(rel_path, os.path.splitext(rel_path)[1], all_extensions, matchers)
+        if not language or language not in active_languages:
+            if logger:
+                _log_skipped(logger, rel_path, "unsupported")
+        elif _is_vendored_go_source(rel_path, language):
+            if logger:
+                _log_skipped(logger, rel_path, "vendor")
+        elif _is_submodule_source(rel_path, submodule_paths):
+            if logger:
+                _log_skipped(logger, rel_path, "submodule")
+        else:
+            source_files[rel_path] = {"absolute_path": full_path, "language": language}
+    return source_files, missing_files
+
+
+# Names of the archives --scan-archives looks inside; jar files are zip archives
+NESTED_ARCHIVE_SUFFIXES = (".zip", ".jar", ".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz")
+
+
+def find_nested_archives(repo_path, gitignore_spec=None, exclude_rules=None, submodule_paths=(), logger=None):
+    """
+    List the archives committed inside a repository, for --scan-archives
+
+    The walk skips what a scan skips: hidden directories, paths matched by
+    .gitignore or the exclude globs, git submodules and symlinks leaving the
+    repository. A file counts when its name has an archive suffix and its
+    content is a zip or tar archive
+
+    Args:
+        repo_path (str): Absolute repository path
+        gitignore_spec: Compiled gitignore rules from the scan, or None
+        exclude_rules (ExcludeRules|None): Exclude globs from the scan
+        submodule_paths (tuple): Repo-relative paths of git submodules
+        logger (Logger|None): Optional logger for skipped submodule archives
+
+    Returns:
+        list: Sorted repo-relative POSIX paths of the archives
+    """
+    real_root = os.path.realpath(repo_path)
+    archives = []
+    for root, dirs, files in os.walk(repo_path, topdown=True):
+        dirs[:] = sorted(
+            name
+            for name in dirs
+            if (not name.startswith(".") or name in _SCANNED_HIDDEN_DIRS)
+            and not _is_ignored(os.path.join(root, name), repo_path, gitignore_spec, None, True, exclude_rules)
+        )
+        for file_name in sorted(files):
+            if not file_name.lower().endswith(NESTED_ARCHIVE_SUFFIXES):
+                continue
+            file_path = os.path.join(root, file_name)
+            rel_path = to_posix_path(os.path.relpath(file_path, repo_path))
+            real_path = os.path.realpath(file_path)
+            if not real_path.startswith(real_root + os.sep):
+                continue
+            if _is_ignored(file_path, repo_path, gitignore_spec, None, False, exclude_rules):
+                continue
+            if _is_submodule_source(rel_path, submodule_paths):
+                if logger:
+                    _log_skipped(logger, rel_path, "submodule")
+                continue
+            if archive_format(file_path):
+                archives.append(rel_path)
+    return sorted(archives)
+
+
+def archive_source_files(archive_rel_path, extract_dir, focus_languages, language_handlers, logger=None):
+    """
+    Build the source file map of an extracted archive, as if it were a directory of the repository
+
+    Only the archive's own files are mapped; archives inside it are not opened
+
+    Args:
+        archive_rel_path (str): Repo-relative path of the archive
+        extract_dir (str): Directory the archive was extracted into
+        focus_languages (list|None): Subset of languages to analyze or None for all
+        language_handlers (dict): Language handler instances keyed by language
+        logger (Logger|None): Optional logger for skipped paths
+
+    Returns:
+        dict: Source files shaped like a scan's, keyed "<archive path>/<path inside the archive>", each
+            with "archive_source" set to the archive path
+    """
+    active_languages, _, all_extensions, matchers = _language_setup(focus_languages, language_handlers)
+    source_files = {}
+    for root, dirs, files in os.walk(extract_dir, topdown=True):
+        dirs[:] = sorted(name for name in dirs if not name.startswith("."))
+        for file_name in sorted(files):
+            full_path = os.path.join(root, file_name)
+            if os.path.islink(full_path):
+                continue
+            rel_path = f"{archive_rel_path}/{to_posix_path(os.path.relpath(full_path, extract_dir))}"
+            language = _source_language(rel_path, os.path.splitext(file_name)[1], all_extensions, matchers)
+            if not language or language not in active_languages:
+                continue
+            if _is_vendored_go_source(rel_path, language):
+                if logger:
+                    _log_skipped(logger, rel_path, "vendor")
+                continue
+            source_files[rel_path] = {
+                "absolute_path": full_path,
+                "language": language,
+                "archive_source": archive_rel_path,
+            }
+    return source_files
+
+
+def scan_repository(repo_path, secure_file_ops, focus_languages, language_handlers, logger):
+    """
+    High-level entry point
+
+    Args:
+        repo_path (str): Absolute repository path
+        secure_file_ops (SecureFileOps|None): Secure file operations or None
+        focus_languages (list|None): Subset of languages to analyze or None for all
+        language_handlers (dict): Language handler instances keyed by language
+        logger (Logger|None): Optional logger for progress and warnings
+
+    Returns:
+        dict: Keys: source_files, manifest_files, root_manifest_files, js_config_files,
+            ts_config_files, skipped_by_depth, solidity_src_path, submodule_data, submodules
+            (see read_gitmodules), gitignore_spec, exclude_rules
+    """
+    gitignore_spec = load_gitignore(secure_file_ops, logger, repo_path)
+    exclude_rules = load_exclude_rules(secure_file_ops, logger, repo_path)
+    submodules = read_gitmodules(repo_path, secure_file_ops, logger)
+    submodule_paths = tuple(submodule["path"] for submodule in submodules if submodule["path"])
+
+    active_languages, all_manifest_files, all_extensions, matchers = _language_setup(focus_languages, language_handlers)
 
     if secure_file_ops:
         (
@@ -388,6 +1129,7 @@ def scan_repository(repo_path, secure_file_ops, focus_languages, language_handle
             root_manifest_files,
             js_config_files,
             ts_config_files,
+            skipped_by_depth,
         ) = _scan_secure(
             repo_path,
             secure_file_ops,
@@ -396,6 +1138,10 @@ def scan_repository(repo_path, secure_file_ops, focus_languages, language_handle
             all_extensions,
             active_languages,
             logger,
+            exclude_rules=exclude_rules,
+            matchers=matchers,
+            max_depth=ResourceLimits.MAX_SCAN_DEPTH,
+            submodule_paths=submodule_paths,
         )
     else:
         (
@@ -404,6 +1150,7 @@ def scan_repository(repo_path, secure_file_ops, focus_languages, language_handle
             root_manifest_files,
             js_config_files,
             ts_config_files,
+            skipped_by_depth,
         ) = _scan_standard(
             repo_path,
             gitignore_spec,
@@ -411,10 +1158,14 @@ def scan_repository(repo_path, secure_file_ops, focus_languages, language_handle
             all_extensions,
             active_languages,
             logger,
+            exclude_rules=exclude_rules,
+            matchers=matchers,
+            max_depth=ResourceLimits.MAX_SCAN_DEPTH,
+            submodule_paths=submodule_paths,
         )
 
     solidity_src_path = _parse_foundry_src_path(secure_file_ops, logger)
-    submodule_data = parse_gitmodules(repo_path, secure_file_ops, logger)
+    submodule_data = parse_gitmodules(repo_path, secure_file_ops, logger, submodules)
 
     return {
         "source_files": source_files,
@@ -422,7 +1173,10 @@ def scan_repository(repo_path, secure_file_ops, focus_languages, language_handle
         "root_manifest_files": root_manifest_files,
         "js_config_files": js_config_files,
         "ts_config_files": ts_config_files,
+        "skipped_by_depth": skipped_by_depth,
         "solidity_src_path": solidity_src_path,
         "submodule_data": submodule_data,
+        "submodules": submodules,
         "gitignore_spec": gitignore_spec,
+        "exclude_rules": exclude_rules,
     }
@@ -10,6 +10,7 @@ import os
 import shutil
 from pathlib import Path
 
+from gardener.common.file_helpers import to_posix_path
 from gardener.common.input_validation import InputValidator, ValidationError
 from gardener.common.subprocess import SecureSubprocess, SubprocessSecurityError
 from gardener.treewalk.solidity import SolidityLanguageHandler
@@ -214,7 +215,7 @@ def associate_submodules_with_solidity_packages(external_packages, remappings,
 
         assigned_url = None
         assigned_path = None
-        normalized = str(Path(path_str)).replace(os.sep, "/").rstrip("/")
+        normalized = to_posix_path(Path(path_str)).rstrip("/")
 
         parts = normalized.split("lib/")
         if len(parts) > 1:
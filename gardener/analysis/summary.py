@@ -0,0 +1,187 @@
+"""
+Aggregate statistics for the top-level `summary` section of the results
+
+The field names below are a stable part of the output format; add new fields
+rather than renaming or repurposing existing ones
+"""
+
+from gardener.analysis.csv_export import resolution_status
+
+# Top-level summary fields
+SUMMARY_FIELDS = {
+    "files_analyzed": "Source files analyzed",
+    "skipped_by_depth": "Source and manifest files left out because they lie beyond --max-depth",
+    "total_imports": "Import statements across all files, external and local",
+    "external_packages": "Unique external packages",
+    "resolved_urls": "External packages with a repository URL",
+    "unresolved_urls": "External packages without a repository URL",
+    "scopes": "Counts by scope, see SCOPE_FIELDS",
+    "ecosystems": "Counts per package ecosystem, see ECOSYSTEM_FIELDS",
+}
+
+# Summary fields added when several repository roots are analyzed together (see combine_root_results)
+MULTI_ROOT_SUMMARY_FIELDS = {
+    "roots": "The summary of each root by root name, next to the counts over all of them",
+}
+
+# Fields of summary["scopes"]
+SCOPE_FIELDS = {
+    "production": "External packages imported by at least one non-test file, other than test frameworks",
+    "test": "External packages imported only by test files, and known test frameworks",
+    "tool": "External packages pinned as development tools (blank imports in tools-tagged Go files, go install "
+    "and go run lines of Makefiles) and not imported by non-test files",
+    "generate": "Tools run by //go:generate directives and not imported by any file",
+    "example": "External packages imported only by Go documentation examples (example_*.go, Example functions)",
+    "ignored-build": "External packages imported only by Go files tagged `//go:build ignore` (--include-ignored-build)",
+    "local": "Imports resolved to files in the repository",
+    "stdlib": "Unique standard-library packages imported",
+}
+
+# Fields of each summary["ecosystems"][<ecosystem>] entry
+ECOSYSTEM_FIELDS = {
+    "external_packages": "Unique external packages of the ecosystem",
+    "resolved_urls": "Of those, packages with a repository URL",
+    "unresolved_urls": "Of those, packages without a repository URL",
+    "stdlib": "Unique standard-library packages imported by the ecosystem's languages",
+    "local": "Local imports made by files of the ecosystem's languages",
+}
+
+# Source language -> package ecosystem
+LANGUAGE_ECOSYSTEMS = {
+    "docker": "docker",
+    "github-actions": "github-actions",
+    "go": "go",
+    "make": "go",
+    "python": "pypi",
+    "rust": "cargo",
+    "javascript": "npm",
+    "typescript": "npm",
+    "solidity": "solidity",
+}
+
+# Standard-library graph ecosystem (see DependencyGraphBuilder) -> package ecosystem
+STDLIB_ECOSYSTEMS = {
+    "go_stdlib": "go",
+    "python_stdlib": "pypi",
+    "rust_stdlib": "cargo",
+    "js_stdlib": "npm",
+    "ts_stdlib": "npm",
+    "solidity_stdlib": "solidity",
+}
+
+
+def _ecosystem_entry(ecosystems, ecosystem):
+    if ecosystem not in ecosystems:
+        ecosystems[ecosystem] = {name: 0 for name in ECOSYSTEM_FIELDS}
+    return ecosystems[ecosystem]
+
+
+def build_summary(results):
+    """
+    Compute the summary section from assembled analysis results
+
+    Args:
+        results (dict): Analysis results with external_packages, dependency_graph and analyzer_details
+
+    Returns:
+        dict: SUMMARY_FIELDS keys; ecosystems is keyed by ecosystem name in sorted order
+    """
+    details = results.get("analyzer_details", {})
+    external_packages = results.get("external_packages", {})
+    file_imports = details.get("file_imports", {})
+    local_imports_map = details.get("local_imports_map", {})
+    graph_nodes = (results.get("dependency_graph") or {}).get("nodes", [])
+
+    ecosystems = {}
+    scopes = {name: 0 for name in SCOPE_FIELDS}
+    resolved = 0
+    for package_info in external_packages.values():
+        entry = _ecosystem_entry(ecosystems, package_info.get("ecosystem") or "unknown")
+        entry["external_packages"] += 1
+        if package_info.get("repository_url"):
+            entry["resolved_urls"] += 1
+            resolved += 1
+        else:
+            entry["unresolved_urls"] += 1
+        if package_info.get("scope") in ("production", "test", "tool", "generate", "example", "ignored-build"):
+            scopes[package_info["scope"]] += 1
+
+    file_languages = {}
+    for node in graph_nodes:
+        if node.get("type") == "file":
+            file_languages[node.get("id")] = node.get("language")
+        elif node.get("type") == "package" and node.get("ecosystem") in STDLIB_ECOSYSTEMS:
+            _ecosystem_entry(ecosystems, STDLIB_ECOSYSTEMS[node["ecosystem"]])["stdlib"] += 1
+            scopes["stdlib"] += 1
+
+    for rel_path, targets in local_imports_map.items():
+        if not targets:
+            continue
+        ecosystem = LANGUAGE_ECOSYSTEMS.get(file_languages.get(rel_path), "unknown")
+        _ecosystem_entry(ecosystems, ecosystem)["local"] += len(targets)
+        scopes["local"] += len(targets)
+
+    return {
+        "files_analyzed": details.get("total_files", 0),
+        "skipped_by_depth": details.get("skipped_by_depth", 0),
+        "total_imports": sum(len(names) for names in file_imports.values())
+        + sum(len(targets) for targets in local_imports_map.values()),
+        "external_packages": len(external_packages),
+        "resolved_urls": resolved,
+        "unresolved_urls": len(external_packages) - resolved,
+        "scopes": scopes,
+        "ecosystems": {name: ecosystems[name] for name in sorted(ecosystems)},
+    }
+
+
+def group_by_ecosystem(results):
+    """
+    Return the results with external_packages nested under an `ecosystems` map
+
+    The keys are the package ecosystems counted in summary["ecosystems"] ("unknown" for
+    packages without one), so the two line up entry for entry
+
+    Args:
+        results (dict): Analysis results with a flat external_packages map
+
+    Returns:
+        dict: A shallow copy holding `ecosystems` = {ecosystem: {package: info}} in sorted ecosystem
+            order in place of external_packages; `results` is left unchanged
+    """
+    ecosystems = {}
+    for name, package_info in results.get("external_packages", {}).items():
+        ecosystems.setdefault(package_info.get("ecosystem") or "unknown", {})[name] = package_info
+    grouped = {}
+    for key, value in results.items():
+        if key == "external_packages":
+            grouped["ecosystems"] = {name: ecosystems[name] for name in sorted(ecosystems)}
+        else:
+            grouped[key] = value
+    return grouped
+
+
+def only_unresolved(results):
+    """
+    Return the results with external_packages narrowed to the packages without a repository URL
+
+    The summary is left as computed for the whole analysis, so its counts still give the
+    number of packages the unresolved ones are out of
+
+    Args:
+        results (dict): Analysis results with a flat external_packages map
+
+    Returns:
+        dict: A shallow copy whose external_packages only holds unresolved packages, with their resolution
+            receipts, and whose `unresolved` section maps each reason (see resolution_status, e.g.
+            "vanity-meta-missing") to the sorted names it applies to; `results` is left unchanged
+    """
+    unresolved = {}
+    reasons = {}
+    for name, package_info in results.get("external_packages", {}).items():
+        status = resolution_status(package_info)
+        if status != "resolved":
+            unresolved[name] = package_info
+            reasons.setdefault(status, []).append(name)
+    narrowed = dict(results, external_packages=unresolved)
+    narrowed["unresolved"] = {reason: sorted(names) for reason, names in sorted(reasons.items())}
+    return narrowed
@@ -0,0 +1,101 @@
+"""
+Known Go test frameworks
+
+Test-scoped imports of assertion, mocking and BDD frameworks get category
+"test-framework", telling them apart from ordinary libraries that tests happen
+to import; a non-test file importing one keeps it a production dependency.
+Teams extend the curated set with a file listing one module path per line
+"""
+
+import os
+
+from gardener.analysis.scanner import parse_gardener_ignore
+from gardener.common.defaults import GoAnalysisConfig
+
+TEST_FRAMEWORKS_FILENAME = ".gardener-test-frameworks"
+
+GO_TEST_FRAMEWORKS = frozenset(
+    {
+        "github.com/stretchr/testify",
+        "github.com/golang/mock",
+        "go.uber.org/mock",
+        "github.com/onsi/ginkgo",
+        "github.com/onsi/ginkgo/v2",
+        "github.com/onsi/gomega",
+        "github.com/smartystreets/goconvey",
+        "github.com/smarty/assertions",
+        "github.com/smartystreets/assertions",
+        "gotest.tools",
+        "gotest.tools/v3",
+        "github.com/matryer/is",
+        "github.com/frankban/quicktest",
+        "github.com/maxatome/go-testdeep",
+        "github.com/franela/goblin",
+        "github.com/DATA-DOG/go-sqlmock",
+        "github.com/jarcoal/httpmock",
+        "github.com/h2non/gock",
+        "github.com/gkampitakis/go-snaps",
+        "github.com/sebdah/goldie/v2",
+    }
+)
+
+
+def load_test_frameworks(secure_file_ops, logger, repo_path=None):
+    """
+    Return the curated Go test frameworks plus those listed in the test frameworks file
+
+    The file is GoAnalysisConfig.TEST_FRAMEWORKS_FILE when set, else a
+    .gardener-test-frameworks at the repository root if there is one; it lists
+    one module path per line, '#' starting a comment line
+
+    Args:
+        secure_file_ops (SecureFileOps|None): SecureFileOps instance if available
+        logger (Logger|None): Optional logger
+        repo_path (str|None): Absolute repository path
+
+    Returns:
+        frozenset: Module paths
+    """
+    frameworks_file = GoAnalysisConfig.TEST_FRAMEWORKS_FILE
+    content = None
+    try:
+        if frameworks_file:
+            with open(frameworks_file, "r", encoding="utf-8", errors="ignore") as handle:
+                content = handle.read()
+        elif secure_file_ops:
+            if secure_file_ops.exists(TEST_FRAMEWORKS_FILENAME):
+                frameworks_file = TEST_FRAMEWORKS_FILENAME
+                content = secure_file_ops.read_file(TEST_FRAMEWORKS_FILENAME)
+        elif repo_path and os.path.isfile(os.path.join(repo_path, TEST_FRAMEWORKS_FILENAME)):
+            frameworks_file = TEST_FRAMEWORKS_FILENAME
+            with open(os.path.join(repo_path, TEST_FRAMEWORKS_FILENAME), "r", encoding="utf-8", errors="ignore") as f:
+                content = f.read()
+    except Exception as exc:
+        if logger:
+            logger.warning(f"Could not read test frameworks file {frameworks_file}: {exc}")
+
+    if content is None:
+        return GO_TEST_FRAMEWORKS
+    extra = parse_gardener_ignore(content)
+    if logger and extra:
+        logger.info(f"Treating as test frameworks: {', '.join(extra)}")
+    return GO_TEST_FRAMEWORKS | frozenset(extra)
+
+
+def is_test_framework(import_path, frameworks):
+    """
+    Determine whether an import path belongs to one of the test framework modules
+
+    Args:
+        import_path (str): Go import or module path, e.g. "github.com/stretchr/testify/assert"
+        frameworks (frozenset): Module paths from load_test_frameworks
+
+    Returns:
+        bool: True when import_path equals a framework module path or extends it by whole path segments
+    """
+    path = import_path
+    while path:
+        if path in frameworks:
+            return True
+        path = path.rpartition("/")[0]
+    return False
@@ -0,0 +1,123 @@
+"""
+Per-phase durations of an analysis run, for diagnosing slow runs
+
+Collected only when requested (--timings), and reported in a `timings`
+section of their own so the dependency data stays identical run to run
+"""
+
+import contextlib
+import threading
+import time
+
+from gardener.package_metadata.url_resolver import network_time
+
+# Phases in the order an analysis runs them
+PHASES = ("directory_walk", "manifests", "resolution", "parsing", "graph", "serialization")
+
+# Fields of the timings section
+TIMINGS_FIELDS = {
+    "phases": "Wall-clock seconds per phase, see PHASES",
+    "parsing_by_language": "Seconds spent reading and parsing the files of each language, summed over worker threads",
+    "network": "HTTP requests sent while resolving packages, the connections opened for them (fewer than the "
+    "requests when kept-alive npm registry connections are reused) and the seconds spent waiting on their "
+    "responses, summed over resolver threads",
+    "total_seconds": "Wall-clock seconds from the start of the run",
+    "files": "Source files analyzed",
+    "files_per_second": "files divided by total_seconds",
+}
+
+
+class PhaseTimings:
+    """
+    Thread-safe accumulator of phase and per-language parsing durations
+    """
+
+    def __init__(self, clock=time.perf_counter):
+        """
+        Args:
+            clock (callable): Monotonic clock returning seconds
+        """
+        self._clock = clock
+        self._lock = threading.Lock()
+        self._started = clock()
+        self._network_start = network_time()
+        self.phases = {}
+        self.languages = {}
+
+    @contextlib.contextmanager
+    def phase(self, name):
+        """
+        Add the duration of the enclosed block to a phase
+
+        Args:
+            name (str): Phase name, one of PHASES
+        """
+        started = self._clock()
+        try:
+            yield
+        finally:
+            self.add(name, self._clock() - started)
+
+    def add(self, name, seconds):
+        """
+        Add seconds to a phase
+
+        Args:
+            name (str): Phase name
+            seconds (float): Duration
+        """
+        with self._lock:
+            self.phases[name] = self.phases.get(name, 0.0) + seconds
+
+    def add_language(self, language, seconds):
+        """
+        Add seconds spent on one file of a language
+
+        Args:
+            language (str): Language of the file
+            seconds (float): Time spent reading and parsing it
+        """
+        with self._lock:
+            self.languages[language] = self.languages.get(language, 0.0) + seconds
+
+    def section(self, files):
+        """
+        Render the timings collected so far
+
+        Args:
+            files (int): Source files analyzed
+
+        Returns:
+            dict: Fields of TIMINGS_FIELDS, seconds rounded to microseconds
+        """
+        total = self._clock() - self._started
+        network = network_time()
+        with self._lock:
+            phases = {name: round(self.phases[name], 6) for name in PHASES if name in self.phases}
+            languages = {language: round(seconds, 6) for language, seconds in sorted(self.languages.items())}
+        return {
+            "phases": phases,
+            "parsing_by_language": languages,
+            "network": {
+                "requests": network["requests"] - self._network_start["requests"],
+                "connections": network["connections"] - self._network_start["connections"],
+                "seconds": round(max(0.0, network["seconds"] - self._network_start["seconds"]), 6),
+            },
+            "total_seconds": round(total, 6),
+            "files": files,
+            "files_per_second": round(files / total, 3) if total > 0 else 0.0,
+        }
+
+
+def timed(timings, name):
+    """
+    Time a block as a phase when timings are collected
+
+    Args:
+        timings (PhaseTimings|None): Accumulator, or None when timings are off
+        name (str): Phase name
+
+    Returns:
+        Context manager
+    """
+    return timings.phase(name) if timings is not None else contextlib.nullcontext()
@@ -7,16 +7,24 @@ of RepositoryAnalyzer
 """
 
 import os
+import shutil
+import tempfile
 from collections import defaultdict
 from pathlib import Path
 
+from gardener.analysis import git_diff
 from gardener.analysis import imports as imports_mod
 from gardener.analysis import js_ts_aliases
 from gardener.analysis import manifests
 from gardener.analysis import scanner
 from gardener.analysis import solidity_meta
+from gardener.analysis.file_cache import FileAnalysisCache, content_hash
 from gardener.treewalk.solidity import SolidityLanguageHandler
+from gardener.common.archives import extract_nested_archive
+from gardener.common.defaults import CacheConfig, GoAnalysisConfig, ResourceLimits
+from gardener.common.file_helpers import to_posix_path
 from gardener.common.secure_file_ops import FileOperationError, SecureFileOps
+from gardener.common.utils import RepositoryError
 
 TimeoutError = imports_mod.TimeoutError
 timeout = imports_mod.timeout
@@ -53,25 +61,45 @@ class RepositoryAnalyzer:
             self.secure_file_ops = None
 
         self.gitignore_spec = self._load_gitignore()
+        self.exclude_rules = None  # scanner.ExcludeRules from --exclude and the gardener ignore file
 
         self.manifest_files = []
         self.root_manifest_files = []
         self.source_files = {}
+        self.resolvable_files = None  # Full source map for local import resolution when --since narrows source_files
+        self.changed_since = None  # Git ref; when set, only files changed since it are analyzed
+        self.diff_scope = None  # {"since", "changed_files", "deleted_files"} for a --since analysis
+        self.listed_files = None  # Paths from --files-from; when set, only they are analyzed
+        self.file_list_scope = None  # {"listed_files", "missing_files"} for a --files-from analysis
         self.external_packages = {}
         self.file_imports = defaultdict(list)
         self.file_package_components = defaultdict(list)
         self.local_imports_map = defaultdict(list)
+        self.file_import_evidence = defaultdict(list)
+        self.file_cache_stats = None
+        self.file_errors = []  # {"file", "error", "detail"} for files that failed to read or parse
+        self.archive_errors = []  # file_errors entries for archives --scan-archives could not open
+        self.archive_dir = None  # Temporary directory holding the archives extracted by --scan-archives
+        self.on_file_extracted = None  # Optional callback(rel_path, result) as each file's imports are merged
+        self.cancellation = None  # Optional CancellationToken checked between files during import extraction
+        self.timings = None  # Optional PhaseTimings receiving per-language parse times
         self.root_package_names = set()
         self.go_module_path = None
+        self.go_workspace_modules = {}
+        self.go_modules = {}
+        self.go_toolchain = None  # {"go_version", ["toolchain"]} from the root go.mod
+        self.enclosing_go_mod = None  # {"path", "module", "directory"} when a go.mod above repo_path is used
         self.hardhat_remappings = {}
         self.remappings = {}
         self.solidity_src_path = None
         self.js_config_files = []
         self.ts_config_files = []
+        self.skipped_by_depth = 0
         self.js_ts_base_url = None
         self.js_ts_path_aliases = {}
         self.alias_resolver = None
         self.submodule_data = {}
+        self.submodules = []  # {"path", "url", "branch"} from .gitmodules
 
         self.language_handlers = {}
         self._local_resolver = None
@@ -81,13 +109,13 @@ class RepositoryAnalyzer:
         Load .gitignore patterns if available
 
         Returns:
-            pathspec.PathSpec|None: Compiled matcher or None
+            scanner.GitignoreRules|None: Root .gitignore rules or None
         """
-        return scanner.load_gitignore(self.secure_file_ops, self.logger)
+        return scanner.load_gitignore(self.secure_file_ops, self.logger, self.repo_path)
 
     def is_ignored(self, path):
         """
-        Check if a path should be ignored according to .gitignore
+        Check if a path should be ignored according to .gitignore or the exclude globs
 
         Args:
             path (str): Absolute path to test
@@ -95,7 +123,7 @@ class RepositoryAnalyzer:
         Returns:
             bool: True if ignored, otherwise False
         """
-        if not self.gitignore_spec:
+        if not self.gitignore_spec and not self.exclude_rules:
             return False
         try:
             if self.secure_file_ops:
@@ -104,8 +132,11 @@ class RepositoryAnalyzer:
                 rel_path = os.path.relpath(path, self.repo_path)
         except ValueError:
             return False
-        rel_path = str(Path(rel_path))
-        return self.gitignore_spec.match_file(rel_path)
+        rel_path = Path(rel_path).as_posix()
+        is_dir = os.path.isdir(path)
+        if self.exclude_rules and self.exclude_rules.match_file(rel_path, is_dir=is_dir):
+            return True
+        return bool(self.gitignore_spec) and self.gitignore_spec.match_file(rel_path, is_dir=is_dir)
 
     def register_language_handler(self, language, handler):
         """
@@ -143,10 +174,19 @@ class RepositoryAnalyzer:
         self.root_manifest_files = result["root_manifest_files"]
         self.js_config_files = result["js_config_files"]
         self.ts_config_files = result["ts_config_files"]
+        self.skipped_by_depth = result["skipped_by_depth"]
         self.solidity_src_path = result["solidity_src_path"]
         self.submodule_data = result["submodule_data"]
+        self.submodules = result["submodules"]
         self.gitignore_spec = result["gitignore_spec"]
+        self.exclude_rules = result["exclude_rules"]
         self._local_resolver = None
+        if ResourceLimits.SCAN_ARCHIVES:
+            self._add_archive_sources()
+        if self.listed_files is not None:
+            self._use_listed_files()
+        if self.changed_since:
+            self._restrict_to_changed_files()
 
         if self.logger:
             self.logger.info(
@@ -161,6 +201,115 @@ class RepositoryAnalyzer:
 
         return self.source_files, self.manifest_files
 
+    def _add_archive_sources(self):
+        """
+        Add the source files of the archives in the tree to source_files (--scan-archives)
+
+        Each archive is extracted, one level deep, into a temporary directory that
+        remove_extracted_archives deletes. Archives that are too large, corrupt or
+        hold entries escaping the extraction directory are skipped and reported
+        as "archive-skipped" file errors
+        """
+        submodule_paths = tuple(submodule["path"] for submodule in self.submodules if submodule["path"])
+        archives = scanner.find_nested_archives(
+            self.repo_path, self.gitignore_spec, self.exclude_rules, submodule_paths, self.logger
+        )
+        self.remove_extracted_archives()
+        self.archive_errors = []
+        if not archives:
+            return
+        self.archive_dir = tempfile.mkdtemp(prefix="gardener-nested-")
+        added = 0
+        for index, archive_rel_path in enumerate(archives):
+            destination = os.path.join(self.archive_dir, str(index))
+            try:
+                extract_nested_archive(
+                    os.path.join(self.repo_path, archive_rel_path),
+                    destination,
+                    ResourceLimits.MAX_ARCHIVE_EXTRACT_SIZE,
+                    ResourceLimits.MAX_ARCHIVE_MEMBERS,
+                )
+            except RepositoryError as exc:
+                if self.logger:
+                    self.logger.warning(f"Skipping archive {archive_rel_path}: {exc}")
+                self.archive_errors.append({"file": archive_rel_path, "error": "archive-skipped", "detail": str(exc)})
+                continue
+            files = scanner.archive_source_files(
+                archive_rel_path, destination, self.focus_languages, self.language_handlers, self.logger
+            )
+            self.source_files.update(files)
+            added += len(files)
+        if self.logger:
+            self.logger.info(f"... Found {added} source files in {len(archives)} archives")
+
+    def remove_extracted_archives(self):
+        """
+        Delete the archives extracted for --scan-archives, once their files are parsed
+        """
+        if self.archive_dir:
+            shutil.rmtree(self.archive_dir, ignore_errors=True)
+            self.archive_dir = None
+
+    def _use_listed_files(self):
+        """
+        Replace the scanned source files with the files of self.listed_files
+
+        The walk still finds the manifests, so go.mod and the other manifests
+        classify imports as usual, and the scanned sources that were not listed
+        stay visible to local import resolution
+        """
+        submodule_paths = tuple(submodule["path"] for submodule in self.submodules if submodule["path"])
+        listed, missing = scanner.listed_source_files(
+            self.repo_path,
+            self.listed_files,
+            self.focus_languages,
+            self.language_handlers,
+            submodule_paths=submodule_paths,
+            logger=self.logger,
+        )
+        self.resolvable_files = {**self.source_files, **listed}
+        self.source_files = listed
+        self.file_list_scope = {"listed_files": len(self.listed_files), "missing_files": missing}
+        if self.logger:
+            for path in missing:
+                self.logger.warning(f"Listed file not found in the repository: {path}")
+            self.logger.info(
+                f"... File list analysis: {len(self.source_files)} of {len(self.listed_files)} listed files "
+                f"are analyzable source files"
+            )
+
+    def _restrict_to_changed_files(self):
+        """
+        Narrow source_files to the files changed since self.changed_since
+
+        Manifests are kept whole so versions and repository URLs still resolve, and
+        the unchanged sources stay visible to local import resolution
+
+        Raises:
+            RepositoryError: If the repository is not a git work tree or the ref is unknown
+        """
+        self.diff_scope = git_diff.changed_files_since(self.repo_path, self.changed_since, self.logger)
+        changed = set(self.diff_scope["changed_files"])
+        candidates = self.source_files
+        if self.resolvable_files is None:
+            self.resolvable_files = candidates
+        self.source_files = {rel: info for rel, info in candidates.items() if rel in changed}
+        if self.logger:
+            self.logger.info(
+                f"... Diff analysis since {self.changed_since}: {len(self.source_files)} of "
+                f"{len(candidates)} source files changed "
+                f"({len(self.diff_scope['deleted_files'])} deleted files skipped)"
+            )
+
+    def _resolution_files(self):
+        """
+        Return the source files that local imports may resolve to
+
+        Returns:
+            dict: All scanned source files, including those a --since analysis skips
+        """
+        return self.resolvable_files if self.resolvable_files is not None else self.source_files
+
     def process_manifest_files(self):
         """
         Process manifest files to extract dependencies
@@ -180,11 +329,70 @@ class RepositoryAnalyzer:
         self.root_package_names = roots
         if go_module and not self.go_module_path:
             self.go_module_path = go_module
+        self.go_toolchain = manifests.read_go_toolchain(self.root_manifest_files, self.secure_file_ops, self.logger)
 
         self.external_packages = manifests.process_manifests(
             self.manifest_files, self.language_handlers, self.secure_file_ops, self.logger
         )
 
+        go_handler = self.language_handlers.get("go")
+        if go_handler is not None and GoAnalysisConfig.FIND_ENCLOSING_GO_MOD:
+            self._use_enclosing_go_mod(go_handler)
+        self.go_modules = {}
+        for module_path, module_dir in getattr(go_handler, "go_mod_modules", {}).items():
+            module_dir = to_posix_path(os.path.relpath(module_dir, self.repo_path))
+            # An enclosing go.mod above the analyzed directory is not one of its modules
+            if module_dir != ".." and not module_dir.startswith("../"):
+                self.go_modules[module_path] = module_dir
+        local_modules = {}
+        if go_handler is not None and hasattr(go_handler, "local_replacement_dirs"):
+            local_modules = manifests.apply_go_local_replacements(
+                self.external_packages, go_handler.local_replacement_dirs(), self.go_modules, self.repo_path, self.logger
+            )
+        if getattr(go_handler, "workspace_modules", None):
+            self.go_workspace_modules = {
+                module_path: to_posix_path(os.path.relpath(module_dir, self.repo_path))
+                for module_path, module_dir in go_handler.workspace_modules.items()
+            }
+            self.external_packages = manifests.apply_go_workspace(
+                self.external_packages,
+                self.go_workspace_modules,
+                go_handler.workspace_replacements,
+                self.logger,
+            )
+            if self.logger:
+                self.logger.info(f"Identified Go workspace modules: {', '.join(sorted(self.go_workspace_modules))}")
+        elif len(self.go_modules) > 1:
+            # A monorepo of several modules without go.work: each module's imports of the others are local code
+            self.go_workspace_modules = dict(self.go_modules)
+            self.external_packages = manifests.apply_go_workspace(
+                self.external_packages, self.go_workspace_modules, {}, self.logger
+            )
+            if self.logger:
+                self.logger.info(f"Identified Go modules: {', '.join(sorted(self.go_modules))}")
+        if set(local_modules) - set(self.go_workspace_modules):
+            # Modules replaced by a directory of the repository are local code too
+            self.go_workspace_modules = {**local_modules, **self.go_workspace_modules}
+            self.external_packages = manifests.apply_go_workspace(self.external_packages, local_modules, {}, self.logger)
+        if self.enclosing_go_mod:
+            # Packages of the enclosing module outside the analyzed directory are local code too
+            self.go_workspace_modules = {
+                self.enclosing_go_mod["module"]: to_posix_path(
+                    os.path.relpath(os.path.dirname(self.enclosing_go_mod["path"]), self.repo_path)
+                ),
+                **self.go_workspace_modules,
+            }
+        if GoAnalysisConfig.INCLUDE_GO_SUM_ONLY and getattr(go_handler, "go_sum_modules", None):
+            self.external_packages = manifests.add_go_sum_only_modules(
+                self.external_packages,
+                go_handler.go_sum_modules,
+                set(self.go_modules) | set(self.go_workspace_modules) | go_handler.replacement_targets(),
+                self.logger,
+            )
+        self.external_packages = manifests.add_git_submodules(
+            self.external_packages, self.submodules, self.repo_path, self.logger
+        )
+
         if self.logger:
             self.logger.info(f"... Found {len(self.external_packages)} unique external packages")
 
@@ -209,7 +417,7 @@ class RepositoryAnalyzer:
         self.js_ts_base_url = base_url
         self.js_ts_path_aliases = paths
         self.alias_resolver = js_ts_aliases.create_alias_resolver(
-            self.repo_path, self.source_files, self.js_ts_base_url, self.js_ts_path_aliases, self.logger
+            self.repo_path, self._resolution_files(), self.js_ts_base_url, self.js_ts_path_aliases, self.logger
         )
 
         self.external_packages = solidity_meta.associate_submodules_with_solidity_packages(
@@ -230,6 +438,52 @@ class RepositoryAnalyzer:
 
         return self.external_packages
 
+    def _use_enclosing_go_mod(self, go_handler):
+        """
+        Read the go.mod of the module enclosing an analyzed subdirectory of it
+
+        Only when repo_path has no go.mod or go.work of its own but holds Go sources:
+        the nearest go.mod above it (see manifests.find_enclosing_go_mod) supplies the
+        required versions, and the analyzed directory's import path (module path plus
+        subdirectory) qualifies its first-party imports
+
+        Args:
+            go_handler (GoLanguageHandler): Registered Go handler
+
+        Mutates:
+            self.external_packages, self.go_module_path, self.go_toolchain, self.root_package_names,
+            self.enclosing_go_mod
+        """
+        if self.go_module_path or any(
+            Path(path).name in ("go.mod", "go.work") for path in self.root_manifest_files
+        ):
+            return
+        if not any(info.get("language") == "go" for info in self.source_files.values()):
+            return
+        go_mod = manifests.find_enclosing_go_mod(self.repo_path)
+        if go_mod is None:
+            return
+        module_dir = os.path.dirname(go_mod)
+        try:
+            module_file_ops = SecureFileOps(module_dir, self.logger)
+        except FileOperationError as exc:
+            if self.logger:
+                self.logger.warning(f"Cannot read the enclosing go.mod {go_mod}: {exc}")
+            return
+        packages = manifests.process_manifests([go_mod], {"go": go_handler}, module_file_ops, self.logger)
+        module_path = getattr(go_handler, "go_mod_files", {}).get(go_mod)
+        if not module_path:
+            return
+        for package_name, package_info in packages.items():
+            self.external_packages.setdefault(package_name, package_info)
+        directory = to_posix_path(os.path.relpath(self.repo_path, module_dir))
+        self.go_module_path = f"{module_path}/{directory}"
+        self.root_package_names.add(module_path)
+        self.go_toolchain = manifests.read_go_toolchain([go_mod], module_file_ops, self.logger)
+        self.enclosing_go_mod = {"path": to_posix_path(go_mod), "module": module_path, "directory": directory}
+        if self.logger:
+            self.logger.info(f"Identified Go module path: {self.go_module_path} (from the enclosing {go_mod})")
+
     def _solidity_candidates_from_remappings(self, remappings_dict, source_name, sol_handler):
         if not remappings_dict:
             return
@@ -286,29 +540,88 @@ class RepositoryAnalyzer:
 
         self._local_resolver = imports_mod.LocalImportResolver(
             repo_path=self.repo_path,
-            source_files=self.source_files,
+            source_files=self._resolution_files(),
             alias_resolver=self.alias_resolver,
             js_ts_base_url=self.js_ts_base_url,
             js_ts_path_aliases=self.js_ts_path_aliases,
             go_module_path=self.go_module_path,
+            go_workspace_modules=self.go_workspace_modules,
+            go_modules=self.go_modules,
             remappings=self.remappings,
             hardhat_remappings=self.hardhat_remappings,
             solidity_src_path=self.solidity_src_path,
             logger=self.logger,
         )
 
-        file_imports, local_imports_map, file_package_components = imports_mod.extract_imports(
+        file_cache = None
+        if CacheConfig.CACHE_DIR:
+            file_cache = FileAnalysisCache(CacheConfig.CACHE_DIR, self._file_cache_context(), self.logger)
+
+        (
+            file_imports,
+            local_imports_map,
+            file_package_components,
+            file_import_evidence,
+            file_errors,
+        ) = imports_mod.extract_imports(
             self.source_files,
             self.language_handlers,
             self.repo_path,
             self.secure_file_ops,
             self._local_resolver,
             self.logger,
+            file_cache=file_cache,
+            on_file=self.on_file_extracted,
+            cancellation=self.cancellation,
+            timings=self.timings,
         )
+        if file_cache is not None:
+            file_cache.save()
+            self.file_cache_stats = {"hits": file_cache.hits, "misses": file_cache.misses}
 
         self.file_imports = file_imports
         self.local_imports_map = local_imports_map
         self.file_package_components = file_package_components
+        self.file_import_evidence = file_import_evidence
+        self.file_errors = self.archive_errors + file_errors
+
+    def _file_cache_context(self):
+        """
+        Describe everything besides a file's own content that shapes its extraction result
+
+        Local import resolution depends on which files exist and on manifest and
+        alias configuration, so any change to these invalidates the whole cache
+
+        Returns:
+            dict: JSON-serializable context for FileAnalysisCache
+        """
+        config_hashes = {}
+        for path in sorted(set(self.manifest_files) | set(self.js_config_files) | set(self.ts_config_files)):
+            try:
+                if self.secure_file_ops:
+                    text = self.secure_file_ops.read_file(self.secure_file_ops.get_relative_path(path))
+                else:
+                    with open(path, "r", encoding="utf-8", errors="ignore") as handle:
+                        text = handle.read()
+                config_hashes[to_posix_path(os.path.relpath(path, self.repo_path))] = content_hash(text)
+            except (FileOperationError, OSError):
+                config_hashes[to_posix_path(os.path.relpath(path, self.repo_path))] = None
+
+        return {
+            "repo_path": self.repo_path,
+            "source_files": sorted(self._resolution_files()),
+            "config_files": config_hashes,
+            "go_module_path": self.go_module_path,
+            "go_workspace_modules": self.go_workspace_modules,
+            "go_modules": self.go_modules,
+            "remappings": self.remappings,
+            "hardhat_remappings": self.hardhat_remappings,
+            "solidity_src_path": self.solidity_src_path,
+            "js_ts_base_url": self.js_ts_base_url,
+            "js_ts_path_aliases": self.js_ts_path_aliases,
+            "languages": sorted(self.language_handlers),
+            "import_positions": GoAnalysisConfig.RECORD_IMPORT_POSITIONS,
+        }
 
     def _get_local_resolver(self):
         """
@@ -320,11 +633,13 @@ class RepositoryAnalyzer:
         if self._local_resolver is None:
             self._local_resolver = imports_mod.LocalImportResolver(
                 repo_path=self.repo_path,
-                source_files=self.source_files,
+                source_files=self._resolution_files(),
                 alias_resolver=self.alias_resolver,
                 js_ts_base_url=self.js_ts_base_url,
                 js_ts_path_aliases=self.js_ts_path_aliases,
                 go_module_path=self.go_module_path,
+                go_workspace_modules=self.go_workspace_modules,
+                go_modules=self.go_modules,
                 remappings=self.remappings,
                 hardhat_remappings=self.hardhat_remappings,
                 solidity_src_path=self.solidity_src_path,
@@ -0,0 +1,131 @@
+"""
+Delivery of finished results to an HTTP endpoint (--webhook-url)
+
+Once the analysis completes, the analysis JSON as saved (or, with --format
+ndjson, the NDJSON records as streamed) is POSTed to the webhook URL in one
+request. Connection errors, 429 and 5xx responses are retried like registry
+requests (MAX_RETRIES, RETRY_BASE_DELAY); any other non-2xx response fails
+the delivery at once
+"""
+
+import time
+import urllib.error
+import urllib.request
+
+from gardener.common.defaults import NetworkConfig
+from gardener.common.input_validation import InputValidator, ValidationError
+from gardener.package_metadata.url_resolver import USER_AGENT, is_retryable, retry_delay
+
+WEBHOOK_SCHEMES = {"http", "https"}
+
+# Body format -> Content-Type of the POST
+WEBHOOK_CONTENT_TYPES = {
+    "json": "application/json",
+    "ndjson": "application/x-ndjson",
+}
+
+
+class WebhookError(Exception):
+    """
+    The results could not be delivered to the webhook URL
+    """
+
+
+def validate_webhook_url(url):
+    """
+    Check a webhook URL before anything is analyzed
+
+    Args:
+        url (str): --webhook-url value
+
+    Returns:
+        str|None: Problem description, or None when the URL is usable
+    """
+    try:
+        InputValidator.validate_url(url, allowed_schemes=WEBHOOK_SCHEMES)
+    except ValidationError as e:
+        return f"--webhook-url {url}: {e}"
+    return None
+
+
+def parse_webhook_header(value):
+    """
+    Split a --webhook-header value into its name and value
+
+    Args:
+        value (str): "Name: value", e.g. "Authorization: Bearer abc123"
+
+    Returns:
+        tuple: (name, value) with surrounding whitespace removed
+
+    Raises:
+        ValueError: If there is no colon, the name is empty or either part holds a line break
+    """
+    name, separator, header_value = value.partition(":")
+    name, header_value = name.strip(), header_value.strip()
+    if not separator or not name or any(c in value for c in "\r\n"):
+        raise ValueError(f"--webhook-header {value!r} must look like 'Name: value'")
+    return name, header_value
+
+
+def _post(url, body, headers):
+    """
+    Send one POST
+
+    Returns:
+        tuple: (status_or_None, detail), detail describing a connection error
+    """
+    request = urllib.request.Request(url, data=body, headers=headers, method="POST")
+    try:
+        with urllib.request.urlopen(request, timeout=NetworkConfig.HTTP_TIMEOUT) as response:
+            return response.status, ""
+    except urllib.error.HTTPError as e:
+        return e.code, ""
+    except Exception as e:
+        return None, str(e)
+
+
+def deliver_webhook(url, body, body_format="json", headers=None, logger=None):
+    """
+    POST the results to the webhook URL, retrying transient failures
+
+    Args:
+        url (str): Webhook URL (see validate_webhook_url)
+        body (str): Serialized results
+        body_format (str): Key of WEBHOOK_CONTENT_TYPES
+        headers (list): (name, value) pairs sent with the request, e.g. from parse_webhook_header
+        logger: Optional logger
+
+    Returns:
+        int: HTTP status of the accepted delivery
+
+    Raises:
+        WebhookError: If the endpoint answered with a non-2xx status, or retries were exhausted
+    """
+    request_headers = {"User-Agent": USER_AGENT, "Content-Type": WEBHOOK_CONTENT_TYPES[body_format]}
+    for name, value in headers or []:
+        request_headers[name] = value
+    data = body.encode("utf-8")
+    max_retries = max(0, NetworkConfig.MAX_RETRIES)
+    for attempt in range(max_retries + 1):
+        status, detail = _post(url, data, request_headers)
+        outcome = "no response" if status is None else f"HTTP {status}"
+        if logger:
+            logger.trace(
+                f"POST {url} -> {outcome}", event="webhook-request", url=url, status=status, attempt=attempt + 1
+            )
+        if status is not None and 200 <= status < 300:
+            return status
+        if not is_retryable(status) or attempt == max_retries:
+            reason = f"{outcome} ({detail})" if detail else outcome
+            raise WebhookError(f"Webhook delivery to {url} failed after {attempt + 1} attempts: {reason}")
+        delay = retry_delay(attempt)
+        logger and logger.debug(
+            f"{outcome} for webhook {url} (attempt {attempt + 1}/{max_retries + 1}); retrying in {delay:.2f}s",
+            event="webhook-retry",
+            url=url,
+            status=status,
+            attempt=attempt + 1,
+            delay=round(delay, 2),
+        )
+        time.sleep(delay)
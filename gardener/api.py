@@ -0,0 +1,478 @@
+"""
+Stable programmatic API
+
+Typed entry points for embedding gardener in another service instead of
+running the CLI and parsing its JSON output. The CLI is a thin wrapper over
+analyze_repo; the result dictionaries it writes are available as RepoResult.raw
+"""
+
+import os
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Callable, Dict, List, Optional
+
+from gardener.analysis import imports as imports_mod
+from gardener.analysis.main import analyze_repository
+from gardener.common.cancellation import CancellationToken
+from gardener.common.defaults import CacheConfig, ConfigOverride, NetworkConfig
+from gardener.common.file_helpers import to_posix_path
+from gardener.common.language_detection import filename_to_lang, parse_language_filter
+from gardener.common.utils import Logger
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.resolution_cache import ResolutionCache
+from gardener.package_metadata.url_rules import compile_url_rules
+from gardener.treewalk.go import parse_go_mod_file
+from gardener.treewalk.registry import create_analyzers
+
+
+@dataclass
+class FileEvidence:
+    """Imports extracted from one source file"""
+
+    # Path of the file, relative to the module root it was resolved against
+    path: str
+
+    language: str
+
+    # External import paths as written in source, in source order
+    external_imports: List[str] = field(default_factory=list)
+
+    # Resolved repo-relative paths of local imports
+    local_imports: List[str] = field(default_factory=list)
+
+    # Per-import metadata (Go only): import_kind, scope, module, version, build tags, ...
+    import_evidence: List[Dict[str, Any]] = field(default_factory=list)
+
+    # {"file", "error", "detail"} diagnostics when the file failed to read or parse
+    errors: List[Dict[str, str]] = field(default_factory=list)
+
+    # The file carries Go's "// Code generated ... DO NOT EDIT." marker
+    generated: bool = False
+
+
+@dataclass
+class ResolutionResult:
+    """Repository URL of one package, as resolve_repository_url found it"""
+
+    ecosystem: str
+
+    name: str
+
+    version: Optional[str] = None
+
+    # Cleaned repository URL, or None when it could not be resolved
+    repository_url: Optional[str] = None
+
+    # The resolution receipt, as in the analysis JSON: source, confidence, cache ("hit", "expired",
+    # "miss"), reason when unresolved, attempts, ...
+    resolution: Dict[str, Any] = field(default_factory=dict)
+
+    # Directory of a Go module that lives below its repository root
+    repository_subpath: Optional[str] = None
+
+    # The Go module matches GOPRIVATE, GONOPROXY or GONOSUMDB
+    private: bool = False
+
+
+@dataclass
+class AnalysisOptions:
+    """Options for analyze_repo, and for resolve_repository_url (which ignores the scanning options)"""
+
+    # Language names or aliases to analyze; None analyzes every supported language
+    languages: Optional[List[str]] = None
+
+    # Configuration overrides, keyed like `--config` (e.g. {"MAX_RETRIES": 0})
+    config_overrides: Dict[str, Any] = field(default_factory=dict)
+
+    # Resolve repository URLs from local signals only
+    offline: bool = False
+
+    # Replaces network I/O for URL resolution: fn(url) -> bytes|str|None, where None means
+    # not found and raising urllib.error.HTTPError reports that status
+    http_client: Optional[Callable[[str], Any]] = None
+
+    # Pre-populated package URL cache, updated in place
+    url_cache: Optional[Dict[str, str]] = None
+
+    # Called with each file's {"type": "file_evidence", ...} record as soon as its imports are extracted
+    on_file_evidence: Optional[Callable[[Dict[str, Any]], None]] = None
+
+    # Git ref; only source files changed between it and HEAD are analyzed
+    since: Optional[str] = None
+
+    # Source file paths, relative to the repository root or absolute; only they are analyzed, without
+    # walking the tree for sources or applying .gitignore to them (manifests are still found as usual)
+    files: Optional[List[str]] = None
+
+    # CancellationToken; cancelling it from another thread or a signal handler stops the analysis
+    # between files and URL lookups, and the result is marked partial
+    cancellation: Optional[CancellationToken] = None
+
+    # PhaseTimings; receives the duration of each analysis phase (see gardener.analysis.timings)
+    timings: Optional[Any] = None
+
+    # Custom URL rules tried before any resolver, first match wins: "PATTERN URL" strings,
+    # {"pattern", "url", ["ecosystem"]} dicts or callables fn(ecosystem, name, version) -> URL or None
+    # (see gardener.package_metadata.url_rules); matches get resolution source "custom-rule"
+    url_rules: List[Any] = field(default_factory=list)
+
+    verbose: bool = False
+
+
+@dataclass
+class RepoResult:
+    """Structured results of a repository analysis"""
+
+    repo_path: str
+
+    # Package name -> metadata (ecosystem, version, repository_url, seen_in, usage_count, ...)
+    external_packages: Dict[str, Dict[str, Any]]
+
+    # [{"package_name", "percentage", "package_url", "ecosystem"}], highest score first
+    top_dependencies: List[Dict[str, Any]]
+
+    # First-party package and dependency nodes with importer -> dependency edges
+    import_graph: Dict[str, Any]
+
+    # Node-link serialization of the file/package dependency graph
+    dependency_graph: Dict[str, Any]
+
+    # {"file", "error", "detail"} diagnostics for files that failed to read or parse
+    errors: List[Dict[str, str]] = field(default_factory=list)
+
+    # Import cycles among first-party packages, each in import order from its smallest package
+    cycles: List[List[str]] = field(default_factory=list)
+
+    # External package -> sorted files importing it directly, and -> {scope: files} where files are scoped
+    reverse_index: Dict[str, List[str]] = field(default_factory=dict)
+    reverse_index_scopes: Dict[str, Dict[str, List[str]]] = field(default_factory=dict)
+
+    # Cluster name -> {"ecosystem", "modules", "files", "generated_files", "usage_count", ["scope"]} with GROUP_CLUSTERS
+    clusters: Dict[str, Dict[str, Any]] = field(default_factory=dict)
+
+    # {"go_version", ["toolchain"]} from the root go.mod, when there is one
+    go_toolchain: Optional[Dict[str, str]] = None
+
+    # {"path", "module", "directory"} of the go.mod above the analyzed directory, when it is a module subdirectory
+    enclosing_go_mod: Optional[Dict[str, str]] = None
+
+    # {"import", "deprecated_since", "suggested_replacement", "files"} for deprecated standard-library imports
+    go_stdlib_deprecations: List[Dict[str, Any]] = field(default_factory=list)
+
+    # {"dependency", "versions": {module: version}, "selected"} for external Go modules the repository's
+    # go.mod files require at different versions
+    version_conflicts: List[Dict[str, Any]] = field(default_factory=list)
+
+    # {"module", "version", "go_modules", "verified", ["required_by"]} for go.mod requires marked
+    # // indirect that no import needs, when CHECK_PRUNABLE is set
+    prunable_candidates: List[Dict[str, Any]] = field(default_factory=list)
+
+    # {"warning", "module", "go_mod_files", "message", ...} for Go modules declared by several go.mod files
+    # or shadowed by a replace directive, and {"warning": "internal-import", "importer", "import", ...} for
+    # imports of internal packages from outside their parent tree, and {"warning": "imported-indirect", ...}
+    # for imported modules go.mod marks // indirect (with CHECK_PRUNABLE), and {"warning": "vendor-drift",
+    # "drift", "module", "modules_txt", ...} for vendor/modules.txt entries go.mod or go.sum disagree with
+    warnings: List[Dict[str, Any]] = field(default_factory=list)
+
+    # {"mode": "diff", "since", "changed_files", "deleted_files"} when options.since restricted the
+    # analysis, {"mode": "files", "listed_files", "missing_files"} when options.files did (both sets of
+    # keys, in diff mode, for both), None for a full scan
+    analysis_scope: Optional[Dict[str, Any]] = None
+
+    # The analysis was cancelled and only holds the work done until then
+    partial: bool = False
+
+    # The complete results dictionary, as serialized to <prefix>_dependency_analysis.json
+    raw: Dict[str, Any] = field(default_factory=dict, repr=False)
+
+    @classmethod
+    def from_results(cls, repo_path, results):
+        """
+        Wrap a results dictionary from DependencyAnalyzer.analyze
+
+        Args:
+            repo_path (str): Analyzed repository path
+            results (dict): Analysis results
+
+        Returns:
+            RepoResult
+        """
+        return cls(
+            repo_path=repo_path,
+            external_packages=results.get("external_packages", {}),
+            top_dependencies=results.get("top_dependencies", []),
+            import_graph=results.get("import_graph", {}),
+            dependency_graph=results.get("dependency_graph", {}),
+            errors=list(results.get("errors", [])),
+            cycles=list(results.get("cycles", [])),
+            reverse_index=dict(results.get("reverse_index", {})),
+            reverse_index_scopes=dict(results.get("reverse_index_scopes", {})),
+            clusters=dict(results.get("clusters", {})),
+            go_toolchain=results.get("go_toolchain"),
+            enclosing_go_mod=results.get("enclosing_go_mod"),
+            go_stdlib_deprecations=list(results.get("go_stdlib_deprecations", [])),
+            version_conflicts=list(results.get("version_conflicts", [])),
+            prunable_candidates=list(results.get("prunable_candidates", [])),
+            warnings=list(results.get("warnings", [])),
+            analysis_scope=results.get("analysis_scope"),
+            partial=bool(results.get("partial")),
+            raw=results,
+        )
+
+
+def _find_go_mod(file_path):
+    """
+    Find the go.mod of the module containing a file
+
+    Args:
+        file_path (str): Absolute path to a Go source file
+
+    Returns:
+        str|None: Absolute path to the nearest go.mod in the file's directory or above
+    """
+    directory = os.path.dirname(file_path)
+    while True:
+        candidate = os.path.join(directory, "go.mod")
+        if os.path.isfile(candidate):
+            return candidate
+        parent = os.path.dirname(directory)
+        if parent == directory:
+            return None
+        directory = parent
+
+
+def _go_module_sources(module_root):
+    """
+    List the Go sources of a module so imports of its own packages resolve as local
+
+    Args:
+        module_root (str): Directory holding the module's go.mod
+
+    Returns:
+        dict: Repo-relative path -> file metadata, skipping vendor/ and hidden directories
+    """
+    source_files = {}
+    for root, dirs, files in os.walk(module_root):
+        dirs[:] = [d for d in dirs if d != "vendor" and not d.startswith(".")]
+        for name in files:
+            if name.endswith(".go"):
+                abs_path = os.path.join(root, name)
+                source_files[to_posix_path(os.path.relpath(abs_path, module_root))] = {
+                    "absolute_path": abs_path,
+                    "language": "go",
+                }
+    return source_files
+
+
+def analyze_file(path, language=None, verbose=False):
+    """
+    Extract the imports of a single source file
+
+    The language is detected from the file name unless given. Go files are read
+    in the context of their nearest go.mod, as analyze_repo would: evidence
+    carries the module versions it requires, and the module's other sources are
+    visible to local import resolution
+
+    Args:
+        path (str): Path to the source file
+        language (str): Optional language name or alias, overriding detection
+        verbose (bool): Enable verbose logging
+
+    Returns:
+        FileEvidence
+
+    Raises:
+        FileNotFoundError: If path is not a file
+        ValueError: If the language is unknown or has no import extractor
+    """
+    abs_path = os.path.abspath(path)
+    if not os.path.isfile(abs_path):
+        raise FileNotFoundError(f"Not a file: {path}")
+    logger = Logger(verbose=verbose)
+    analyzers = create_analyzers(logger)
+    language = parse_language_filter(language)[0] if language else filename_to_lang(abs_path)
+    if language is None:
+        basename = os.path.basename(abs_path)
+        language = next((key for key, analyzer in analyzers.items() if analyzer.matches(basename)), None)
+    if language not in analyzers:
+        raise ValueError(f"No import extractor for {path} (language: {language or 'unknown'})")
+
+    handler = analyzers[language]
+    root = os.path.dirname(abs_path)
+    go_module_path = None
+    source_files = {}
+    if language == "go":
+        go_mod = _find_go_mod(abs_path)
+        if go_mod:
+            root = os.path.dirname(go_mod)
+            handler.process_manifest(go_mod, {})
+            go_sum = os.path.join(root, "go.sum")
+            if os.path.isfile(go_sum):
+                handler.process_manifest(go_sum, {})
+            with open(go_mod, "r", encoding="utf-8", errors="ignore") as handle:
+                go_module_path = parse_go_mod_file(handle.read())["module"] or None
+            source_files = _go_module_sources(root)
+
+    rel_path = to_posix_path(os.path.relpath(abs_path, root))
+    source_files[rel_path] = {"absolute_path": abs_path, "language": language}
+    resolver = imports_mod.LocalImportResolver(
+        repo_path=root,
+        source_files=source_files,
+        alias_resolver=None,
+        js_ts_base_url=None,
+        js_ts_path_aliases={},
+        go_module_path=go_module_path,
+        remappings={},
+        hardhat_remappings={},
+        solidity_src_path=None,
+        logger=logger,
+    )
+    file_imports, local_imports_map, _, file_import_evidence, file_errors = imports_mod.extract_imports(
+        {rel_path: source_files[rel_path]},
+        {language: handler},
+        root,
+        None,
+        resolver,
+        logger if verbose else None,
+        jobs=1,
+    )
+    evidence = file_import_evidence.get(rel_path, [])
+    return FileEvidence(
+        path=rel_path,
+        language=language,
+        external_imports=list(file_imports.get(rel_path, [])),
+        local_imports=list(local_imports_map.get(rel_path, [])),
+        import_evidence=list(evidence),
+        errors=file_errors,
+        generated=any(entry.get("generated") for entry in evidence),
+    )
+
+
+def analyze_repo(path, options=None):
+    """
+    Analyze a local repository
+
+    Args:
+        path (str): Path to the repository root
+        options (AnalysisOptions): Optional analysis options
+
+    Returns:
+        RepoResult
+
+    Raises:
+        ValueError: If options.languages names an unsupported language or options.url_rules holds an invalid rule
+        RepositoryError: If options.since is set but path is not in a git repository or the ref is unknown
+    """
+    options = options or AnalysisOptions()
+    languages = options.languages
+    if languages:
+        languages = parse_language_filter(languages if isinstance(languages, str) else ",".join(languages))
+    overrides = dict(options.config_overrides or {})
+    if options.offline:
+        overrides["OFFLINE"] = True
+    if options.url_rules:
+        overrides["URL_RULES"] = compile_url_rules(options.url_rules)
+
+    repo_path = os.path.abspath(path)
+    previous_request_fn = url_resolver.get_request_fn()
+    if options.http_client is not None:
+        url_resolver.set_request_fn(options.http_client)
+    try:
+        with ConfigOverride(overrides, logger=Logger(verbose=options.verbose)):
+            results = analyze_repository(
+                repo_path,
+                specific_languages=languages,
+                verbose=options.verbose,
+                url_cache=options.url_cache,
+                on_file_evidence=options.on_file_evidence,
+                since=options.since,
+                cancellation=options.cancellation,
+                files=options.files,
+                timings=options.timings,
+            )
+    finally:
+        url_resolver.set_request_fn(previous_request_fn)
+    return RepoResult.from_results(repo_path, results)
+
+
+def resolve_repository_url(ecosystem, name, version=None, options=None):
+    """
+    Resolve the repository URL of one package, without scanning a repository
+
+    Uses the same resolver backends as analyze_repo: options.offline, http_client,
+    url_cache and config_overrides (MAX_RETRIES, CACHE_DIR, ...) apply as they do
+    there, so with a CACHE_DIR the on-disk resolution cache is read and updated
+
+    Args:
+        ecosystem (str): One of url_resolver.RESOLVER_ECOSYSTEMS (go, npm, pypi, cargo, ...)
+        name (str): Package name as the ecosystem spells it (a Go module path, an npm name, ...)
+        version (str): Optional version; the resolution cache is keyed by it
+        options (AnalysisOptions): Optional options
+
+    Returns:
+        ResolutionResult
+
+    Raises:
+        ValueError: If the ecosystem has no resolver or options.url_rules holds an invalid rule
+    """
+    if ecosystem not in url_resolver.RESOLVER_ECOSYSTEMS:
+        raise ValueError(
+            f"No repository URL resolver for ecosystem '{ecosystem}' "
+            f"(supported: {', '.join(url_resolver.RESOLVER_ECOSYSTEMS)})"
+        )
+    options = options or AnalysisOptions()
+    overrides = dict(options.config_overrides or {})
+    if options.offline:
+        overrides["OFFLINE"] = True
+    if options.url_rules:
+        overrides["URL_RULES"] = compile_url_rules(options.url_rules)
+    package_data = {"ecosystem": ecosystem}
+    if version:
+        package_data["version"] = version
+
+    logger = Logger(verbose=options.verbose)
+    receipts = {}
+    previous_request_fn = url_resolver.get_request_fn()
+    if options.http_client is not None:
+        url_resolver.set_request_fn(options.http_client)
+    try:
+        with ConfigOverride(overrides, logger=logger):
+            resolution_cache = None
+            if CacheConfig.CACHE_DIR:
+                resolution_cache = ResolutionCache(CacheConfig.CACHE_DIR, CacheConfig.RESOLUTION_CACHE_TTL, logger)
+            resolved_urls = url_resolver.resolve_package_urls(
+                {name: package_data},
+                logger,
+                cache=options.url_cache,
+                receipts=receipts,
+                offline=NetworkConfig.OFFLINE,
+                resolution_cache=resolution_cache,
+                cancellation=options.cancellation,
+            )
+            if resolution_cache is not None:
+                resolution_cache.save()
+    finally:
+        url_resolver.set_request_fn(previous_request_fn)
+
+    receipt = dict(receipts.get(name) or {})
+    return ResolutionResult(
+        ecosystem=ecosystem,
+        name=name,
+        version=version,
+        repository_url=resolved_urls.get(name) or None,
+        repository_subpath=receipt.pop("repository_subpath", None),
+        private=bool(receipt.pop("private", False)),
+        resolution=receipt,
+    )
+
+
+__all__ = [
+    "AnalysisOptions",
+    "CancellationToken",
+    "FileEvidence",
+    "RepoResult",
+    "ResolutionResult",
+    "analyze_file",
+    "analyze_repo",
+    "resolve_repository_url",
+]
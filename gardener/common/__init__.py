@@ -6,11 +6,12 @@ from gardener.common.alias_config import AliasConfiguration, AliasRule, UnifiedA
 from gardener.common.defaults import GraphAnalysisConfig, ResourceLimits, apply_config_overrides
 from gardener.common.framework_config import FRAMEWORK_CONFIGS, FrameworkAliasConfig, FrameworkAliasResolver
 from gardener.common.secure_file_ops import FileOperationError, SecureFileOps
-from gardener.common.utils import Logger, get_repo
+from gardener.common.utils import Logger, cloned_repository, get_repo
 
 __all__ = [
     "Logger",
     "get_repo",
+    "cloned_repository",
     "GraphAnalysisConfig",
     "ResourceLimits",
     "apply_config_overrides",
@@ -0,0 +1,226 @@
+"""
+Source archives (tarballs and zip files) as analysis input, and archives found inside a repository
+"""
+
+import contextlib
+import os
+import posixpath
+import shutil
+import tarfile
+import tempfile
+import zipfile
+
+from gardener.common.compression import GZIP_MAGIC
+from gardener.common.utils import RepositoryError, get_logger
+
+# (format, magic bytes at offset 0); plain tar is recognized by its "ustar" header at offset 257
+ARCHIVE_SIGNATURES = (
+    ("zip", b"PK\x03\x04"),
+    ("zip", b"PK\x05\x06"),
+    ("tar.gz", GZIP_MAGIC),
+    ("tar.bz2", b"BZh"),
+    ("tar.xz", b"\xfd7zXZ\x00"),
+)
+_TAR_MAGIC_OFFSET = 257
+
+# Suffixes dropped from an archive's file name to get the name of the project it contains
+_ARCHIVE_SUFFIXES = (".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz", ".tar", ".zip")
+
+
+def archive_format(path):
+    """
+    Detect a source archive from its leading bytes, whatever its file name
+
+    Args:
+        path (str): File path
+
+    Returns:
+        str|None: "zip", "tar", "tar.gz", "tar.bz2" or "tar.xz"; None for directories,
+            missing files and anything else
+    """
+    if not os.path.isfile(path):
+        return None
+    try:
+        with open(path, "rb") as handle:
+            header = handle.read(_TAR_MAGIC_OFFSET + 5)
+    except OSError:
+        return None
+    for archive_type, magic in ARCHIVE_SIGNATURES:
+        if header.startswith(magic):
+            return archive_type
+    if header[_TAR_MAGIC_OFFSET:] == b"ustar":
+        return "tar"
+    return None
+
+
+def archive_root_name(path):
+    """
+    Return the project name of an archive: its file name without the archive suffix
+
+    Args:
+        path (str): Archive path, e.g. "dist/widgets-1.2.0.tar.gz"
+
+    Returns:
+        str: e.g. "widgets-1.2.0"
+    """
+    name = os.path.basename(path)
+    for suffix in _ARCHIVE_SUFFIXES:
+        if name.lower().endswith(suffix) and len(name) > len(suffix):
+            return name[: -len(suffix)]
+    return os.path.splitext(name)[0] or name
+
+
+def _check_member_path(name):
+    """
+    Reject archive entries that would be written outside the extraction directory
+
+    Raises:
+        RepositoryError: For absolute paths, drive letters and ".." components
+    """
+    normalized = posixpath.normpath(name.replace("\\", "/"))
+    drive = len(normalized) > 1 and normalized[1] == ":"
+    if normalized.startswith("/") or normalized == ".." or normalized.startswith("../") or drive:
+        raise RepositoryError(f"Archive entry escapes the extraction directory: {name}")
+
+
+def _safe_tar_members(archive):
+    """
+    Yield the tar members that are safe to extract
+
+    Regular files and directories are kept; links must point inside the archive,
+    and devices and FIFOs are skipped
+
+    Raises:
+        RepositoryError: If an entry or link target escapes the extraction directory
+    """
+    for member in archive.getmembers():
+        _check_member_path(member.name)
+        if member.issym():
+            _check_member_path(posixpath.join(posixpath.dirname(member.name), member.linkname))
+        elif member.islnk():
+            _check_member_path(member.linkname)
+        elif not (member.isfile() or member.isdir()):
+            continue
+        yield member
+
+
+def _check_extract_size(path, sizes, max_size, max_members):
+    """
+    Reject archives that would unpack to more entries or bytes than allowed
+
+    Sizes come from the archive's own index; zipfile and tarfile never write
+    more than those for an entry, so the check also bounds compression bombs
+
+    Raises:
+        RepositoryError: When a limit is exceeded
+    """
+    if max_members is not None and len(sizes) > max_members:
+        raise RepositoryError(f"Archive {path} holds {len(sizes)} entries, more than the limit of {max_members}")
+    total = sum(sizes)
+    if max_size is not None and total > max_size:
+        raise RepositoryError(f"Archive {path} unpacks to {total} bytes, more than the limit of {max_size}")
+
+
+def _extract(path, archive_type, destination, max_size=None, max_members=None):
+    """
+    Extract an archive into destination after validating every entry
+
+    Args:
+        path (str): Archive path
+        archive_type (str): Format returned by archive_format
+        destination (str): Existing directory to extract into
+        max_size (int|None): Optional bound on the total uncompressed size, in bytes
+        max_members (int|None): Optional bound on the number of entries
+
+    Raises:
+        RepositoryError: If the archive is corrupt, contains unsafe entries or exceeds a limit
+    """
+    try:
+        if archive_type == "zip":
+            with zipfile.ZipFile(path) as archive:
+                members = archive.infolist()
+                for member in members:
+                    _check_member_path(member.filename)
+                _check_extract_size(path, [member.file_size for member in members], max_size, max_members)
+                archive.extractall(destination, members)
+        else:
+            compression = archive_type.partition(".")[2]
+            with tarfile.open(path, f"r:{compression}") as archive:
+                members = list(_safe_tar_members(archive))
+                _check_extract_size(path, [member.size for member in members], max_size, max_members)
+                if hasattr(tarfile, "data_filter"):
+                    # Also strips setuid bits and other unsafe metadata on Pythons that support it
+                    archive.extractall(destination, members, filter="data")
+                else:
+                    archive.extractall(destination, members)
+    except (tarfile.TarError, zipfile.BadZipFile, EOFError, OSError) as e:
+        raise RepositoryError(f"Could not extract archive {path}: {e}")
+
+
+@contextlib.contextmanager
+def extracted_archive(path):
+    """
+    Extract a source archive into a temporary directory for the duration of a block
+
+    An archive holding a single top-level directory (as GitHub release tarballs
+    do) is analyzed from inside it. The extracted tree is named after the archive
+    (see archive_root_name) so that output prefixes and SBOM roots carry the
+    archive's name. The temporary directory is removed when the block exits,
+    whether it succeeds or raises
+
+    Args:
+        path (str): Archive path
+
+    Yields:
+        str: Absolute path of the extracted project root
+
+    Raises:
+        RepositoryError: If the file is not a supported archive, is corrupt, or
+            contains entries outside the extraction directory
+    """
+    archive_type = archive_format(path)
+    if archive_type is None:
+        raise RepositoryError(f"{path} is not a zip or tar archive")
+
+    logger = get_logger()
+    temp_dir = tempfile.mkdtemp(prefix="gardener-archive-")
+    try:
+        staging = os.path.join(temp_dir, ".extract")
+        os.makedirs(staging)
+        logger.info(f"Extracting {archive_type} archive {path} to {temp_dir}...")
+        _extract(path, archive_type, staging)
+
+        entries = os.listdir(staging)
+        source_root = staging
+        if len(entries) == 1 and os.path.isdir(os.path.join(staging, entries[0])):
+            source_root = os.path.join(staging, entries[0])
+        root = os.path.join(temp_dir, archive_root_name(path))
+        os.rename(source_root, root)
+        yield root
+    finally:
+        shutil.rmtree(temp_dir, ignore_errors=True)
+        logger.debug(f"Removed extracted archive {temp_dir}")
+
+
+def extract_nested_archive(path, destination, max_size, max_members):
+    """
+    Extract an archive found inside a repository, within size and entry limits
+
+    Unlike an archive given as input, the tree is extracted as is, without
+    descending into a single top-level directory
+
+    Args:
+        path (str): Archive path
+        destination (str): Directory to create and extract into
+        max_size (int): Bound on the total uncompressed size, in bytes
+        max_members (int): Bound on the number of entries
+
+    Raises:
+        RepositoryError: If the file is not a supported archive, is corrupt, contains entries
+            outside the extraction directory or exceeds a limit
+    """
+    archive_type = archive_format(path)
+    if archive_type is None:
+        raise RepositoryError(f"{path} is not a zip or tar archive")
+    os.makedirs(destination)
+    _extract(path, archive_type, destination, max_size=max_size, max_members=max_members)
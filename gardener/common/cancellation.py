@@ -0,0 +1,81 @@
+"""
+Cooperative cancellation of a running analysis
+
+The file-parsing and URL-resolution loops check a CancellationToken between
+units of work; once it is cancelled they skip the remaining work, and the
+analysis finishes with what was collected so far and marks its results
+`partial: true`
+"""
+
+import contextlib
+import signal
+import threading
+
+
+class CancellationToken:
+    """
+    Thread-safe flag shared by the pipeline stages of one analysis
+    """
+
+    def __init__(self):
+        self._event = threading.Event()
+
+    def cancel(self):
+        """
+        Ask the analysis to stop; work already in progress finishes
+        """
+        self._event.set()
+
+    @property
+    def cancelled(self):
+        """
+        bool: Whether cancel() was called
+        """
+        return self._event.is_set()
+
+
+def is_cancelled(token):
+    """
+    Return True when token is set and cancelled
+
+    Args:
+        token (CancellationToken|None): Token or None for an analysis that cannot be cancelled
+
+    Returns:
+        bool
+    """
+    return token is not None and token.cancelled
+
+
+@contextlib.contextmanager
+def cancel_on_interrupt(token, logger=None):
+    """
+    Turn the first SIGINT (Ctrl-C) inside the block into token.cancel()
+
+    A second SIGINT raises KeyboardInterrupt as usual, so a stuck run can still
+    be stopped. Outside the main thread, where signal handlers cannot be
+    installed, the block runs unchanged
+
+    Args:
+        token (CancellationToken): Token to cancel
+        logger (Logger): Optional logger for the interrupt notice
+
+    Yields:
+        CancellationToken: token
+    """
+    if threading.current_thread() is not threading.main_thread():
+        yield token
+        return
+
+    def handler(signum, frame):
+        if token.cancelled:
+            raise KeyboardInterrupt
+        token.cancel()
+        if logger:
+            logger.warning("Interrupted: finishing with the results collected so far (press Ctrl-C again to abort)")
+
+    previous = signal.signal(signal.SIGINT, handler)
+    try:
+        yield token
+    finally:
+        signal.signal(signal.SIGINT, previous)
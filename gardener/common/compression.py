@@ -0,0 +1,88 @@
+"""
+Gzip-compressed output files
+
+Output is compressed when asked for (--compress) or when the destination is
+named like a gzip file, e.g. `--json-out results.json.gz`. Reading a result
+file back goes by its magic bytes, as archive inputs do (see archive_format),
+so a compressed file is recognized whatever it is called. Only the encoding
+of the bytes changes; the decompressed text is what would have been written
+uncompressed
+"""
+
+import gzip
+import io
+
+# Magic bytes at offset 0, also the "tar.gz" entry of ARCHIVE_SIGNATURES
+GZIP_MAGIC = b"\x1f\x8b"
+GZIP_SUFFIX = ".gz"
+
+
+def is_gzip_path(path):
+    """
+    Return whether a path is named like a gzip file
+
+    Args:
+        path (str): File path, e.g. "out/results.json.gz"
+
+    Returns:
+        bool: True for a ".gz" suffix, in any case
+    """
+    return str(path).lower().endswith(GZIP_SUFFIX)
+
+
+def is_gzip_file(path):
+    """
+    Return whether a file starts with the gzip magic bytes
+
+    Args:
+        path (str): File path
+
+    Returns:
+        bool: False as well for files that cannot be read
+    """
+    try:
+        with open(path, "rb") as f:
+            return f.read(len(GZIP_MAGIC)) == GZIP_MAGIC
+    except OSError:
+        return False
+
+
+def open_text_output(path, compress=False, newline=None, line_buffering=False):
+    """
+    Open a UTF-8 text file for writing, gzip-compressed when asked for or named ".gz"
+
+    The gzip header carries no timestamp, so the same results compress to the
+    same bytes. Flushing a compressed stream ends a deflate block, so every line
+    flushed so far can be decompressed while the file is still being written
+
+    Args:
+        path (str): File path
+        compress (bool): Compress whatever the file is called
+        newline (str|None): Newline translation, as for open()
+        line_buffering (bool): Flush after every line
+
+    Returns:
+        Writable text stream; closing it completes the file
+    """
+    if not (compress or is_gzip_path(path)):
+        return open(path, "w", encoding="utf-8", newline=newline, buffering=1 if line_buffering else -1)
+    stream = gzip.GzipFile(path, mode="wb", mtime=0)
+    return io.TextIOWrapper(stream, encoding="utf-8", newline=newline, line_buffering=line_buffering)
+
+
+def open_text_input(path):
+    """
+    Open a UTF-8 text file for reading, decompressing it when it starts with the gzip magic bytes
+
+    Args:
+        path (str): File path
+
+    Returns:
+        Readable text stream
+
+    Raises:
+        OSError: If the file cannot be opened
+    """
+    if is_gzip_file(path):
+        return gzip.open(path, "rt", encoding="utf-8")
+    return open(path, encoding="utf-8")
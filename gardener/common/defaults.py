@@ -23,8 +23,23 @@ class GraphAnalysisConfig:
     EDGE_W_CONTAINS_COMPONENT = 1.0
     EDGE_W_USES_COMPONENT = 1.0
 
+    # What each package's usage_count counts: 'files' (distinct importing files) or
+    # 'imports' (distinct import paths per importing file, summed over files)
+    USAGE_COUNT_BASIS = "files"
+
     # Serialization behavior
     SERIALIZE_SORT_KEYS = True
+    # Write external packages as one flat map instead of nested under their ecosystem
+    SERIALIZE_FLAT_PACKAGES = False
+    # Write only the external packages left without a repository URL, grouped by reason under `unresolved`
+    SERIALIZE_ONLY_UNRESOLVED = False
+    # Gzip the output files (data files only; the graph HTML stays plain)
+    SERIALIZE_COMPRESS = False
+    # Check the analysis JSON against its published schema before writing it (see output_schema)
+    SERIALIZE_VALIDATE = False
+    # Write only the deduplicated (ecosystem, name, version) package list, skipping URL resolution,
+    # evidence and graph assembly (see DependencyAnalyzer.list_packages)
+    SERIALIZE_PACKAGES_ONLY = False
 
 
 class VisualizationConfig:
@@ -55,6 +70,150 @@ class VisualizationConfig:
     NODE_SIZE_SCALING_FACTOR = 9000
 
 
+class GoAnalysisConfig:
+    """
+    Options controlling how Go sources and modules are reported
+    """
+
+    # Report standard-library imports (fmt, net/http, ...) alongside external packages
+    INCLUDE_STDLIB = False
+
+    # Parse sources under vendor/ as if they were first-party code
+    SCAN_VENDOR = False
+
+    # Drop imports of files marked "// Code generated ... DO NOT EDIT." instead of tagging them generated
+    EXCLUDE_GENERATED = False
+
+    # Keep imports of `//go:build ignore` files (standalone programs outside the build), scoped "ignored-build"
+    INCLUDE_IGNORED_BUILD = False
+
+    # GOOS/GOARCH targets a build constraint is tried against; imports no target compiles get build_reachability
+    BUILD_PLATFORMS = (
+        "linux/amd64",
+        "linux/arm64",
+        "linux/386",
+        "linux/arm",
+        "linux/ppc64le",
+        "linux/s390x",
+        "linux/riscv64",
+        "linux/loong64",
+        "linux/mips64le",
+        "darwin/amd64",
+        "darwin/arm64",
+        "windows/amd64",
+        "windows/arm64",
+        "windows/386",
+        "freebsd/amd64",
+        "freebsd/arm64",
+        "openbsd/amd64",
+        "netbsd/amd64",
+        "dragonfly/amd64",
+        "illumos/amd64",
+        "aix/ppc64",
+        "plan9/amd64",
+        "android/arm64",
+        "ios/arm64",
+        "js/wasm",
+        "wasip1/wasm",
+    )
+
+    # Tags builds may pass with -tags (--build-tags); constraints needing any other non-platform tag never hold
+    BUILD_TAGS = ("race", "msan", "asan", "purego", "netgo", "osusergo", "timetzdata", "boringcrypto")
+
+    # Record the line and column of each import in its evidence entry (--with-positions)
+    RECORD_IMPORT_POSITIONS = False
+
+    # Without a go.mod at the analyzed root, use the nearest go.mod above it (up to a git or filesystem boundary)
+    FIND_ENCLOSING_GO_MOD = True
+
+    # Report modules listed in go.sum but required by no go.mod (left over from earlier builds) as dependencies
+    INCLUDE_GO_SUM_ONLY = False
+
+    # Follow go.mod requires through the module proxy up to this many levels; 0 disables
+    MAX_TRANSITIVE_DEPTH = 0
+
+    # Compare `// indirect` requires with the imports to list prunable ones; online, each require's go.mod is read too
+    CHECK_PRUNABLE = False
+
+    # Tag the modules of known clusters (protobuf and gRPC) with their `cluster` and summarize each in `clusters`
+    GROUP_CLUSTERS = False
+
+    # File listing extra test framework module paths, one per line; "" reads <repo>/.gardener-test-frameworks if present
+    TEST_FRAMEWORKS_FILE = ""
+
+    # Fetch the latest go.mod of each dependency from the module proxy to find retractions and deprecation notices
+    CHECK_PROXY_DEPRECATIONS = True
+
+    # Fetch when each required module version was published (`@v/<version>.info`) to report its age
+    CHECK_VERSION_TIMES = True
+
+    # Look up the commit each pseudo-version pins in its GitHub repository, flagging prefixes it lacks
+    CHECK_PSEUDO_COMMITS = True
+
+
+class CacheConfig:
+    """
+    Incremental analysis cache settings
+    """
+
+    # Directory for the per-file extraction cache and the URL resolution cache; empty disables caching
+    CACHE_DIR = ""
+
+    # Seconds a cached repository URL stays fresh before it is resolved again (default: 7 days)
+    RESOLUTION_CACHE_TTL = 7 * 24 * 3600
+
+
+class NetworkConfig:
+    """
+    Outbound network access
+    """
+
+    # Never contact registries, module proxies or vanity hosts; URLs come from local signals only
+    OFFLINE = False
+
+    # Retries for connection errors, 429 and 5xx responses (404 and other 4xx are never retried)
+    MAX_RETRIES = 3
+
+    # Seconds before the first retry; doubles for each further retry, with random jitter
+    RETRY_BASE_DELAY = 1.0
+
+    # Seconds a single request may wait for the server to connect or send data
+    HTTP_TIMEOUT = 10.0
+
+    # Wall-clock seconds all network resolution of one analysis may take; once spent, no further request is
+    # sent and packages still unresolved get the reason "resolution-deadline-exceeded". 0 is unlimited
+    RESOLUTION_DEADLINE = 0
+
+    # Longest GitHub API rate-limit wait (Retry-After, X-RateLimit-Reset) sat out; longer waits give up
+    GITHUB_RATE_LIMIT_MAX_WAIT = 60.0
+
+    # Bearer token for requests to AUTH_HOSTS or, when none are configured, github.com in place of $GITHUB_TOKEN
+    AUTH_TOKEN = ""
+
+    # Hosts (and their subdomains) that receive AUTH_TOKEN; empty means github.com only
+    AUTH_HOSTS = ()
+
+    # Host globs resolution requests may go to (e.g. "*.corp.example.com"); when set, every other host is blocked
+    ALLOW_REGISTRIES = ()
+
+    # Host globs resolution requests never go to; they win over ALLOW_REGISTRIES
+    DENY_REGISTRIES = ()
+
+    # Package name pattern -> URL template rules tried before any resolver, as "PATTERN URL" strings,
+    # {"pattern", "url", ["ecosystem"]} objects or (from the Python API) callables; see url_rules
+    URL_RULES = ()
+
+    # Registry, proxy and meta-tag requests in flight at once across all resolver backends (independent of --jobs)
+    RESOLVER_CONCURRENCY = 8
+
+    # Keep each resolver thread's npm registry connection open across lookups; False opens one connection per
+    # request. The registry has no bulk metadata endpoint, so the requests themselves are the same either way
+    NPM_BATCHED_RESOLUTION = True
+
+    # Commits of history fetched when analyzing a repository URL; 0 clones the full history
+    CLONE_DEPTH = 1
+
+
 class ResourceLimits:
     """
     Resource limits for robustness and edge case handling
@@ -67,17 +226,94 @@ class ResourceLimits:
     MAX_FILE_SIZE = 10 * 1024 * 1024 * 1024  # 10GB max file size for parsing
     MAX_IMPORTS_PER_FILE = 1000000  # Maximum imports to track per file
     MAX_TREE_DEPTH = 50000  # Maximum AST tree depth
+    MAX_IMPORT_CYCLES = 1000  # Maximum first-party import cycles to report
 
     # Timeouts
-    PARSE_TIMEOUT = 300  # Seconds to timeout a single file parsing
+    PARSE_TIMEOUT = 300  # Seconds to timeout a single file parsing (only enforced when parsing serially)
+
+    # Parallelism
+    ANALYSIS_JOBS = 0  # Worker threads for per-file parsing; 0 uses the CPU count, 1 parses serially
 
     # Path and string limits (should not need retuning)
     MAX_PATH_LENGTH = 4096  # Maximum file path length
     MAX_URL_LENGTH = 2048  # Maximum URL length
 
     # Repository scan behavior
-    # Reserved for future use (e.g., disabling symlink following in scans)
-    FOLLOW_SYMLINKS = True
+    # Follow symlinked files and directories inside the repository (--follow-symlinks); cycles are always skipped
+    FOLLOW_SYMLINKS = False
+    RESPECT_GITIGNORE = True  # Skip paths matched by the root or nested .gitignore files
+    EXCLUDE_PATTERNS = ()  # Repo-relative globs to skip (`**` matches across directories), from --exclude
+    GARDENER_IGNORE_FILE = ""  # File listing one exclude glob per line; "" reads <repo>/.gardenerignore if present
+    MAX_SCAN_DEPTH = -1  # Deepest directory level scanned (0 = repository root only), from --max-depth; -1 is unlimited
+    SCAN_SUBMODULES = False  # Parse source files inside git submodule checkouts as first-party code
+    SCAN_ARCHIVES = False  # Also parse the source files of zip/jar and tar archives in the tree, from --scan-archives
+    MAX_ARCHIVE_EXTRACT_SIZE = 512 * 1024 * 1024  # Bytes an archive found in the tree may unpack to
+    MAX_ARCHIVE_MEMBERS = 100000  # Entries an archive found in the tree may hold
+
+
+def _config_classes():
+    """
+    Return the configuration classes whose attributes may be overridden
+
+    Returns:
+        dict: Mapping of class name -> class
+    """
+    return {
+        "GraphAnalysisConfig": GraphAnalysisConfig,
+        "VisualizationConfig": VisualizationConfig,
+        "GoAnalysisConfig": GoAnalysisConfig,
+        "CacheConfig": CacheConfig,
+        "NetworkConfig": NetworkConfig,
+        "ResourceLimits": ResourceLimits,
+    }
+
+
+def invalid_config_overrides(overrides):
+    """
+    List the overrides that apply_config_overrides would ignore
+
+    Args:
+        overrides (dict): Dictionary mapping parameter names to override values
+
+    Returns:
+        list: One "<key>: <reason>" string per unknown parameter or value of the wrong type
+    """
+    problems = []
+    for key, value in overrides.items():
+        config_class = next((cls for cls in _config_classes().values() if hasattr(cls, key)), None)
+        if config_class is None:
+            problems.append(f"{key}: unknown parameter")
+            continue
+        try:
+            type(getattr(config_class, key))(value)
+        except (ValueError, TypeError) as e:
+            problems.append(f"{key}: {e}")
+    return problems
+
+
+def effective_config(overrides=None):
+    """
+    Return every configuration parameter with the value a run with these overrides uses
+
+    Args:
+        overrides (dict|None): Dictionary mapping parameter names to override values
+
+    Returns:
+        dict: Class name -> {parameter: value}, tuples as lists and tokens masked as "***"
+    """
+    config = {}
+    with ConfigOverride(overrides):
+        for class_name, config_class in _config_classes().items():
+            values = {}
+            for key in sorted(vars(config_class)):
+                if not key.isupper():
+                    continue
+                value = getattr(config_class, key)
+                if key.endswith("_TOKEN") and value:
+                    value = "***"
+                values[key] = list(value) if isinstance(value, tuple) else value
+            config[class_name] = values
+    return config
 
 
 def apply_config_overrides(overrides, logger=None):
@@ -100,11 +336,7 @@ def apply_config_overrides(overrides, logger=None):
     if not overrides:
         return
 
-    config_classes = {
-        "GraphAnalysisConfig": GraphAnalysisConfig,
-        "VisualizationConfig": VisualizationConfig,
-        "ResourceLimits": ResourceLimits,
-    }
+    config_classes = _config_classes()
 
     for key, value in overrides.items():
         applied = False
@@ -119,7 +351,8 @@ def apply_config_overrides(overrides, logger=None):
                     setattr(config_class, key, value_type(value))
 
                     if logger:
-                        logger.debug(f"Config override: {class_name}.{key} = {value} (was {old_value})")
+                        shown = "***" if key.endswith("_TOKEN") else f"{value} (was {old_value})"
+                        logger.debug(f"Config override: {class_name}.{key} = {shown}")
                     applied = True
                     break  # Move to the next key
                 except (ValueError, TypeError) as e:
@@ -136,7 +369,7 @@ class ConfigOverride:
     """
     Context manager to temporarily override configuration values
 
-    Supports GraphAnalysisConfig, VisualizationConfig, and ResourceLimits. Ensures
+    Supports the attributes of every class _config_classes() returns. Ensures
     overrides are reverted when the context exits, preventing test bleed‑through.
 
     Args:
@@ -152,11 +385,7 @@ class ConfigOverride:
     def __enter__(self):
         if not self.overrides:
             return self
-        config_classes = {
-            "GraphAnalysisConfig": GraphAnalysisConfig,
-            "VisualizationConfig": VisualizationConfig,
-            "ResourceLimits": ResourceLimits,
-        }
+        config_classes = _config_classes()
         for key, value in self.overrides.items():
             applied = False
             for class_name, cls in config_classes.items():
@@ -0,0 +1,75 @@
+"""
+CLI exit statuses and the structured error record written to stderr
+
+These values are a stable contract for CI automation; add new ones rather than
+renumbering existing ones
+"""
+
+import json
+import sys
+
+EXIT_SUCCESS = 0
+# Source files failed to read or parse (with --fail-on-error), or the analysis itself crashed
+EXIT_ANALYSIS_ERRORS = 1
+# Unknown flags, invalid flag values, malformed --config, or a repository path that cannot be used
+EXIT_INVALID_ARGUMENTS = 2
+# The scan found no source files in the requested languages
+EXIT_NO_ANALYZABLE_FILES = 3
+# A repository could not be cloned, or packages were left unresolved by network errors (never with --offline)
+EXIT_NETWORK_FAILURE = 4
+# More external packages lack a repository URL than --fail-on-unresolved-urls(-pct) allows
+EXIT_UNRESOLVED_URLS = 5
+# The results could not be delivered to --webhook-url (with --require-webhook)
+EXIT_WEBHOOK_FAILURE = 6
+# With --fail-on-added, the analysis (or `gardener diff`) found packages missing from the baseline
+EXIT_ADDED_DEPENDENCIES = 7
+# With --validate-output, the analysis JSON did not match its published schema and was not written
+EXIT_INVALID_OUTPUT = 8
+# The run was interrupted (SIGINT); any results written are marked "partial": true
+EXIT_INTERRUPTED = 130
+
+# error_code values of the stderr record, with the exit status each one produces
+ERROR_CODES = {
+    "added-dependencies": EXIT_ADDED_DEPENDENCIES,
+    "analysis-errors": EXIT_ANALYSIS_ERRORS,
+    "analysis-failed": EXIT_ANALYSIS_ERRORS,
+    "interrupted": EXIT_INTERRUPTED,
+    "invalid-arguments": EXIT_INVALID_ARGUMENTS,
+    "invalid-output": EXIT_INVALID_OUTPUT,
+    "no-analyzable-files": EXIT_NO_ANALYZABLE_FILES,
+    "network-failure": EXIT_NETWORK_FAILURE,
+    "unresolved-urls": EXIT_UNRESOLVED_URLS,
+    "webhook-failed": EXIT_WEBHOOK_FAILURE,
+}
+
+
+def error_record(error_code, message):
+    """
+    Build the structured error record for stderr
+
+    Args:
+        error_code (str): Key of ERROR_CODES
+        message (str): Human-readable description
+
+    Returns:
+        dict: {"error_code", "exit_code", "message"}
+    """
+    return {"error_code": error_code, "exit_code": ERROR_CODES[error_code], "message": message}
+
+
+def exit_with_error(error_code, message, stream=None):
+    """
+    Write the error record as one JSON line and exit with its status
+
+    Args:
+        error_code (str): Key of ERROR_CODES
+        message (str): Human-readable description
+        stream: Text stream to write to (default: sys.stderr)
+
+    Raises:
+        SystemExit: Always, with ERROR_CODES[error_code]
+    """
+    stream = stream or sys.stderr
+    stream.write(json.dumps(error_record(error_code, message), sort_keys=True) + "\n")
+    stream.flush()
+    sys.exit(ERROR_CODES[error_code])
@@ -2,20 +2,89 @@
 Shared file operation utilities
 """
 
+import codecs
 import json
 
 
-def read_file_content(file_path, secure_file_ops=None, encoding="utf-8"):
+def decode_text(data, path=None, logger=None):
+    """
+    Decode file bytes as UTF-8, tolerating a byte order mark and invalid bytes
+
+    A leading UTF-8 BOM is dropped. Content that is not valid UTF-8 (e.g. Latin-1)
+    is decoded with undecodable bytes replaced by U+FFFD, and a warning is logged,
+    so that callers can still analyze the rest of the file
+
+    Args:
+        data (bytes): Raw file content
+        path (str): Optional file path for the warning
+        logger (Logger): Optional logger for the warning
+
+    Returns:
+        str: Decoded text
+    """
+    if data.startswith(codecs.BOM_UTF8):
+        data = data[len(codecs.BOM_UTF8) :]
+    try:
+        return data.decode("utf-8")
+    except UnicodeDecodeError as exc:
+        if logger:
+            logger.warning(
+                f"{path or 'File'} is not valid UTF-8 ({exc.reason} at byte {exc.start}); "
+                "undecodable bytes were replaced",
+                event="file-decoded-lossy",
+                path=str(path) if path else None,
+            )
+        return data.decode("utf-8", errors="replace")
+
+
+def normalize_line_endings(text):
+    """
+    Convert Windows (`\\r\\n`) and old Mac (`\\r`) line endings to `\\n`
+
+    Parsers and line-based scanners then see the same lines, and no carriage
+    return is left at the end of a line (`"gin\\r"`), whichever mix of endings
+    an editor or tool wrote
+
+    Args:
+        text (str): Decoded file content
+
+    Returns:
+        str: The text with every line ending as `\\n`
+    """
+    if "\r" not in text:
+        return text
+    return text.replace("\r\n", "\n").replace("\r", "\n")
+
+
+def to_posix_path(path):
+    """
+    Return a path with forward slashes only
+
+    Repository-relative paths are compared and reported in POSIX form, so paths
+    built on Windows (or written with backslashes) match the same files
+
+    Args:
+        path (str|Path): Path with any mix of `\\` and `/` separators
+
+    Returns:
+        str: The path with every `\\` replaced by `/`
+    """
+    return str(path).replace("\\", "/")
+
+
+def read_file_content(file_path, secure_file_ops=None, encoding="utf-8", logger=None):
     """
     Read file content using secure_file_ops if available, otherwise use standard file operations
 
     This utility function provides a consistent pattern for reading files across all components,
-    eliminating code duplication and standardizing error handling
+    eliminating code duplication and standardizing error handling. UTF-8 content is
+    read with decode_text, so a BOM or invalid bytes never make the read fail
 
     Args:
         file_path (str): Path to the file to read
         secure_file_ops (object): Optional SecureFileOps instance for safe file operations
         encoding (str): File encoding (default: 'utf-8')
+        logger (Logger): Optional logger for lossy-decoding warnings
 
     Returns:
         The content of the file as a string
@@ -26,9 +95,11 @@ def read_file_content(file_path, secure_file_ops=None, encoding="utf-8"):
     """
     if secure_file_ops:
         return secure_file_ops.read_file(file_path, encoding=encoding)
-    else:
+    if codecs.lookup(encoding).name != "utf-8":
         with open(file_path, "r", encoding=encoding) as f:
             return f.read()
+    with open(file_path, "rb") as f:
+        return decode_text(f.read(), file_path, logger)
 
 
 def safe_json_load(file_path, secure_file_ops=None):
@@ -52,6 +123,4 @@ def safe_json_load(file_path, secure_file_ops=None):
     """
     if secure_file_ops:
         return secure_file_ops.read_json(file_path)
-    else:
-        with open(file_path, "r", encoding="utf-8") as f:
-            return json.load(f)
+    return json.loads(read_file_content(file_path))
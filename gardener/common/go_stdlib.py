@@ -0,0 +1,240 @@
+"""
+Go standard library package inventory
+"""
+
+# Public standard-library import paths grouped by the Go release that introduced them
+# Derived from `go list std` for each release (internal/, vendor/ and cmd/ trees excluded)
+GO_STDLIB_PACKAGES_BY_RELEASE = {
+    "1.0": (
+        "archive/tar",
+        "archive/zip",
+        "bufio",
+        "bytes",
+        "compress/bzip2",
+        "compress/flate",
+        "compress/gzip",
+        "compress/lzw",
+        "compress/zlib",
+        "container/heap",
+        "container/list",
+        "container/ring",
+        "crypto",
+        "crypto/aes",
+        "crypto/cipher",
+        "crypto/des",
+        "crypto/dsa",
+        "crypto/ecdsa",
+        "crypto/elliptic",
+        "crypto/hmac",
+        "crypto/md5",
+        "crypto/rand",
+        "crypto/rc4",
+        "crypto/rsa",
+        "crypto/sha1",
+        "crypto/sha256",
+        "crypto/sha512",
+        "crypto/subtle",
+        "crypto/tls",
+        "crypto/x509",
+        "crypto/x509/pkix",
+        "database/sql",
+        "database/sql/driver",
+        "debug/dwarf",
+        "debug/elf",
+        "debug/gosym",
+        "debug/macho",
+        "debug/pe",
+        "encoding/ascii85",
+        "encoding/asn1",
+        "encoding/base32",
+        "encoding/base64",
+        "encoding/binary",
+        "encoding/csv",
+        "encoding/gob",
+        "encoding/hex",
+        "encoding/json",
+        "encoding/pem",
+        "encoding/xml",
+        "errors",
+        "expvar",
+        "flag",
+        "fmt",
+        "go/ast",
+        "go/build",
+        "go/doc",
+        "go/parser",
+        "go/printer",
+        "go/scanner",
+        "go/token",
+        "hash",
+        "hash/adler32",
+        "hash/crc32",
+        "hash/crc64",
+        "hash/fnv",
+        "html",
+        "html/template",
+        "image",
+        "image/color",
+        "image/draw",
+        "image/gif",
+        "image/jpeg",
+        "image/png",
+        "index/suffixarray",
+        "io",
+        "io/ioutil",
+        "log",
+        "log/syslog",
+        "math",
+        "math/big",
+        "math/cmplx",
+        "math/rand",
+        "mime",
+        "mime/multipart",
+        "net",
+        "net/http",
+        "net/http/cgi",
+        "net/http/fcgi",
+        "net/http/httptest",
+        "net/http/httputil",
+        "net/http/pprof",
+        "net/mail",
+        "net/rpc",
+        "net/rpc/jsonrpc",
+        "net/smtp",
+        "net/textproto",
+        "net/url",
+        "os",
+        "os/exec",
+        "os/signal",
+        "os/user",
+        "path",
+        "path/filepath",
+        "reflect",
+        "regexp",
+        "regexp/syntax",
+        "runtime",
+        "runtime/cgo",
+        "runtime/debug",
+        "runtime/pprof",
+        "sort",
+        "strconv",
+        "strings",
+        "sync",
+        "sync/atomic",
+        "syscall",
+        "testing",
+        "testing/iotest",
+        "testing/quick",
+        "text/scanner",
+        "text/tabwriter",
+        "text/template",
+        "text/template/parse",
+        "time",
+        "unicode",
+        "unicode/utf16",
+        "unicode/utf8",
+        "unsafe",
+    ),
+    "1.1": ("go/format", "net/http/cookiejar", "runtime/race"),
+    "1.2": ("encoding", "image/color/palette"),
+    "1.3": ("debug/plan9obj",),
+    "1.5": ("go/constant", "go/importer", "go/types", "mime/quotedprintable", "runtime/trace"),
+    "1.7": ("context", "net/http/httptrace"),
+    "1.8": ("plugin",),
+    "1.9": ("math/bits",),
+    "1.11": ("syscall/js",),
+    "1.13": ("crypto/ed25519",),
+    "1.14": ("hash/maphash",),
+    "1.15": ("time/tzdata",),
+    "1.16": ("embed", "go/build/constraint", "io/fs", "runtime/metrics", "testing/fstest"),
+    "1.18": ("debug/buildinfo", "net/netip"),
+    "1.19": ("go/doc/comment",),
+    "1.20": ("crypto/ecdh", "runtime/coverage"),
+    "1.21": ("cmp", "log/slog", "maps", "slices", "testing/slogtest"),
+    "1.22": ("go/version", "math/rand/v2"),
+    "1.23": ("iter", "structs", "unique"),
+    "1.24": ("crypto/fips140", "crypto/hkdf", "crypto/mlkem", "crypto/pbkdf2", "crypto/sha3", "weak"),
+    "1.25": ("testing/synctest",),
+}
+
+# Import path -> release that introduced it
+GO_STDLIB_INTRODUCED = {
+    package: release for release, packages in GO_STDLIB_PACKAGES_BY_RELEASE.items() for package in packages
+}
+
+# Deprecated import path -> (release that deprecated it, suggested replacement packages), from the release notes
+GO_STDLIB_DEPRECATED = {
+    "crypto/dsa": ("1.16", "crypto/ecdsa, crypto/ed25519"),
+    "io/ioutil": ("1.16", "io, os"),
+}
+
+
+def _release_tuple(version):
+    """
+    Convert a Go version string ('1.21', '1.21.3', 'go1.22rc1') to a comparable tuple
+
+    Args:
+        version (str): Go release or go.mod `go` directive value
+
+    Returns:
+        tuple: (major, minor) integers, or None when the string is not a Go version
+    """
+    text = str(version).strip()
+    if text.startswith("go"):
+        text = text[2:]
+    parts = text.split(".")
+    if len(parts) < 2:
+        return None
+    minor = ""
+    for char in parts[1]:
+        if not char.isdigit():
+            break
+        minor += char
+    if not parts[0].isdigit() or not minor:
+        return None
+    return int(parts[0]), int(minor)
+
+
+def is_go_stdlib(import_path, go_version=None):
+    """
+    Return True when an import path names a Go standard-library package
+
+    Args:
+        import_path (str): Import path as written in source
+        go_version (str): Optional Go release; packages introduced later are not considered stdlib
+
+    Returns:
+        bool
+    """
+    introduced = GO_STDLIB_INTRODUCED.get(import_path)
+    if introduced is None:
+        return False
+    if go_version is None:
+        return True
+    target = _release_tuple(go_version)
+    if target is None:
+        return True
+    return target >= _release_tuple(introduced)
+
+
+def go_stdlib_deprecation(import_path, go_version=None):
+    """
+    Return the deprecation notice of a standard-library package, as of a Go release
+
+    Args:
+        import_path (str): Import path as written in source
+        go_version (str): Optional Go release (e.g. the go.mod `go` directive); packages deprecated
+            by a later release are not reported
+
+    Returns:
+        dict|None: {"import", "deprecated_since", "suggested_replacement"}, or None when the package
+            is not deprecated in that release
+    """
+    deprecation = GO_STDLIB_DEPRECATED.get(import_path)
+    if deprecation is None:
+        return None
+    deprecated_since, replacement = deprecation
+    target = _release_tuple(go_version) if go_version is not None else None
+    if target is not None and target < _release_tuple(deprecated_since):
+        return None
+    return {"import": import_path, "deprecated_since": deprecated_since, "suggested_replacement": replacement}
@@ -4,33 +4,21 @@ Maps filenames to tree-sitter language keys
 
 import os
 
-from gardener.treewalk.go import GoLanguageHandler
-from gardener.treewalk.javascript import JavaScriptLanguageHandler
-from gardener.treewalk.python import PythonLanguageHandler
-from gardener.treewalk.rust import RustLanguageHandler
-from gardener.treewalk.solidity import SolidityLanguageHandler
-from gardener.treewalk.typescript import TypeScriptLanguageHandler
 from gardener.common.tsl import USING_TSL_PACK
+from gardener.treewalk.registry import BUILTIN_ANALYZERS, analyzer_languages
 
 
 def _build_parser_map():
     """
-    Build an extension → language map using Gardener's handlers
+    Build an extension → language map using Gardener's built-in handlers
+
+    Analyzers added through the registry claim files with their own matches()
 
     Returns:
         Dict mapping file extensions (with dot) or basenames to language keys
     """
-    handlers = {
-        "python": PythonLanguageHandler,
-        "javascript": JavaScriptLanguageHandler,
-        "typescript": TypeScriptLanguageHandler,
-        "go": GoLanguageHandler,
-        "rust": RustLanguageHandler,
-        "solidity": SolidityLanguageHandler,
-    }
-
     mapping = {}
-    for lang, Handler in handlers.items():
+    for lang, Handler in BUILTIN_ANALYZERS.items():
         try:
             exts = Handler(None).get_file_extensions()
         except Exception:
@@ -52,6 +40,90 @@ def _build_parser_map():
 PARSERS = _build_parser_map()
 
 
+# Built-in language keys accepted by --languages; they match the `language` labels recorded in import evidence
+SUPPORTED_LANGUAGES = (
+    "docker",
+    "github-actions",
+    "go",
+    "javascript",
+    "make",
+    "python",
+    "rust",
+    "solidity",
+    "svelte",
+    "typescript",
+)
+
+
+def supported_languages():
+    """
+    Return the language keys accepted by --languages, including registered analyzers
+
+    Returns:
+        tuple: SUPPORTED_LANGUAGES followed by the keys of analyzers added through the registry
+    """
+    extra = [language for language in analyzer_languages() if language not in SUPPORTED_LANGUAGES]
+    return SUPPORTED_LANGUAGES + tuple(extra)
+
+# Short names for convenience, normalized to their language key
+LANGUAGE_ALIASES = {
+    "dockerfile": "docker",
+    "gha": "github-actions",
+    "golang": "go",
+    "js": "javascript",
+    "jsx": "javascript",
+    "makefile": "make",
+    "py": "python",
+    "rs": "rust",
+    "sol": "solidity",
+    "ts": "typescript",
+    "tsx": "typescript",
+}
+
+# Package ecosystems whose name differs from their language keys, so `ecosystems` keys work as filters too
+ECOSYSTEM_LANGUAGES = {
+    "cargo": ("rust",),
+    "npm": ("javascript", "typescript"),
+    "pypi": ("python",),
+}
+
+
+def parse_language_filter(languages_str):
+    """
+    Parse a comma-separated language filter into supported language keys
+
+    Args:
+        languages_str (str|None): Comma-separated languages, aliases or package ecosystems, e.g. "go,js,pypi"
+
+    Returns:
+        list|None: De-duplicated language keys in the given order, or None when no filter is set
+
+    Raises:
+        ValueError: If any entry is not a supported language or alias
+    """
+    if not languages_str:
+        return None
+    supported = supported_languages()
+    languages = []
+    unknown = []
+    for raw in languages_str.split(","):
+        name = raw.strip().lower()
+        if not name:
+            continue
+        for language in ECOSYSTEM_LANGUAGES.get(name, (LANGUAGE_ALIASES.get(name, name),)):
+            if language not in supported:
+                unknown.append(raw.strip())
+            elif language not in languages:
+                languages.append(language)
+    if unknown:
+        aliases = ", ".join(f"{alias}={language}" for alias, language in sorted(LANGUAGE_ALIASES.items()))
+        raise ValueError(
+            f"Unsupported language(s): {', '.join(unknown)}. "
+            f"Supported: {', '.join(supported)} (aliases: {aliases}; ecosystems: {', '.join(ECOSYSTEM_LANGUAGES)})"
+        )
+    return languages or None
+
+
 def filename_to_lang(filename):
     """
     Return a tree-sitter language key for a given filename, or None
@@ -70,4 +142,11 @@ def filename_to_lang(filename):
     return PARSERS.get(ext)
 
 
-__all__ = ["filename_to_lang", "PARSERS"]
+__all__ = [
+    "filename_to_lang",
+    "parse_language_filter",
+    "supported_languages",
+    "LANGUAGE_ALIASES",
+    "PARSERS",
+    "SUPPORTED_LANGUAGES",
+]
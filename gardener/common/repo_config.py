@@ -0,0 +1,62 @@
+"""
+Per-repository settings committed as .gardener.yaml or .gardener.toml
+
+The file holds the same settings as the CLI flags, keyed by flag name without
+the leading dashes (`exclude`, `allow-registry`, `format`, ...). Its values take
+the place of the built-in defaults, and flags given on the command line win
+over both (see main_cli)
+"""
+
+import os
+import tomllib
+
+import yaml
+
+# Settings files searched for at the repository root, in order; the first one found is used
+REPO_CONFIG_FILENAMES = (".gardener.yaml", ".gardener.yml", ".gardener.toml")
+
+
+def find_repo_config(repo_path):
+    """
+    Return the settings file at the root of a repository directory
+
+    Args:
+        repo_path (str): Repository directory
+
+    Returns:
+        str|None: Absolute path of the first of REPO_CONFIG_FILENAMES present, or None
+    """
+    for filename in REPO_CONFIG_FILENAMES:
+        path = os.path.join(os.path.abspath(repo_path), filename)
+        if os.path.isfile(path):
+            return path
+    return None
+
+
+def load_repo_config(path):
+    """
+    Read a settings file
+
+    Args:
+        path (str): Path of a .yaml, .yml or .toml file
+
+    Returns:
+        dict: Setting name -> value; empty for an empty YAML file
+
+    Raises:
+        ValueError: If the file cannot be read or parsed, or does not hold a mapping
+    """
+    try:
+        with open(path, "rb") as f:
+            content = f.read()
+        if path.endswith(".toml"):
+            settings = tomllib.loads(content.decode("utf-8"))
+        else:
+            settings = yaml.safe_load(content)
+    except (OSError, UnicodeDecodeError, tomllib.TOMLDecodeError, yaml.YAMLError) as e:
+        raise ValueError(f"Cannot read {os.path.basename(path)}: {e}") from e
+    if settings is None:
+        return {}
+    if not isinstance(settings, dict):
+        raise ValueError(f"{os.path.basename(path)} must hold a mapping of setting names to values")
+    return settings
@@ -2,11 +2,14 @@
 Secure file operations available in lieu of standard file operations
 """
 
+import codecs
 import json
 import os
 from contextlib import contextmanager
 from pathlib import Path
 
+from gardener.common.file_helpers import decode_text
+
 
 class SecurityError(Exception):
     """Raised when a security constraint is violated"""
@@ -187,6 +190,23 @@ class SecureFileAccess:
         safe_path = self.validate_path(path)
         return safe_path.read_text(encoding=encoding)
 
+    def read_bytes(self, path):
+        """
+        Read binary file content
+
+        Args:
+            path (str): Path to the file
+
+        Returns:
+            File content as bytes
+
+        Raises:
+            SecurityError: If path validation fails
+            IOError: If file operation fails
+        """
+        safe_path = self.validate_path(path)
+        return safe_path.read_bytes()
+
     def write_text(self, path, content, encoding="utf-8"):
         """
         Write text to file
@@ -254,6 +274,9 @@ class SecureFileOps:
         """
         Read file content safely
 
+        UTF-8 content is decoded with decode_text: a leading BOM is dropped and
+        undecodable bytes are replaced, with a warning
+
         Args:
             path (str): Path to the file
             encoding (str): Text encoding
@@ -266,6 +289,8 @@ class SecureFileOps:
             SecurityError: If security constraints are violated
         """
         try:
+            if codecs.lookup(encoding).name == "utf-8":
+                return decode_text(self.secure_access.read_bytes(path), path, self.logger)
             return self.secure_access.read_text(path, encoding=encoding)
         except SecurityError as e:
             if self.logger:
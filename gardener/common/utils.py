@@ -2,10 +2,18 @@
 Utility functions for dependency analysis
 """
 
+import base64
+import contextlib
+import json
 import os
 import re
+import shutil
 import sys
+import tempfile
+import threading
 import traceback
+from datetime import datetime, timezone
+from importlib import metadata
 
 try:
     from gardener.common.input_validation import InputValidator, ValidationError
@@ -26,74 +34,202 @@ class RepositoryError(Exception):
     pass
 
 
+class RepositoryCloneError(RepositoryError):
+    """
+    Exception raised when a remote repository URL is valid but cloning it fails
+    """
+
+    pass
+
+
+# Log levels accepted by --log-level, most verbose first
+LOG_LEVELS = {"trace": -1, "debug": 0, "info": 1, "warning": 2, "error": 3}
+
+# Record formats accepted by --log-format
+LOG_FORMATS = ("text", "json")
+
+# Process-wide logging settings, see configure_logging
+_LOG_SETTINGS = {"level": None, "format": "text", "stream": None}
+_LOG_LOCK = threading.Lock()
+
+# Prefixes of text records by level
+_TEXT_PREFIXES = {
+    "trace": "... Trace: ",
+    "debug": "... Debug: ",
+    "info": "",
+    "warning": "Warning: ",
+    "error": "Error: ",
+}
+
+
+def configure_logging(level=None, log_format="text", stream=None):
+    """
+    Set the threshold and record format of every Logger
+
+    Args:
+        level (str|None): One of LOG_LEVELS; None lets each Logger derive its level from `verbose`
+        log_format (str): "text" for readable lines or "json" for one JSON object per line
+        stream (file|None): Destination of all records; None writes to the current sys.stderr
+
+    Raises:
+        ValueError: If the level or format is unknown
+    """
+    if level is not None and level not in LOG_LEVELS:
+        raise ValueError(f"Unknown log level {level!r}; use one of {', '.join(LOG_LEVELS)}")
+    if log_format not in LOG_FORMATS:
+        raise ValueError(f"Unknown log format {log_format!r}; use one of {', '.join(LOG_FORMATS)}")
+    _LOG_SETTINGS.update({"level": level, "format": log_format, "stream": stream})
+
+
+def verbosity_log_level(verbosity):
+    """
+    Map a -v count to a log level
+
+    Args:
+        verbosity (int): Number of -v flags
+
+    Returns:
+        str|None: None for 0 (the default threshold), "debug" for 1, "trace" for 2 or more
+    """
+    if not verbosity:
+        return None
+    return "debug" if verbosity == 1 else "trace"
+
+
 class Logger:
     """
-    Simple logger class with deduplication to avoid repetitive messages
+    Leveled logger writing text or JSON records to stderr, with deduplication of repeated messages
 
-    Maintains the same interface and behavior across the codebase
+    Every method takes optional keyword fields that are attached to the record,
+    e.g. `logger.debug("Skipping file", event="file-skipped", path=path, reason="excluded")`;
+    text records list them after the message. Nothing is written to stdout, so
+    results printed there stay machine-readable
     """
 
     def __init__(self, verbose=False, name=None):
         """
         Args:
-            verbose (bool): Enable verbose output
+            verbose (bool): Enable debug output unless configure_logging set a level
             name (str): Optional logger name
         """
         self.verbose = verbose
         self.name = name or "gardener"
         self.seen_messages = set()  # Track already seen messages to avoid duplication
-        self.log_level = 1 if not verbose else 0  # 0=debug, 1=info, 2=warning, 3=error
 
-    def debug(self, message):
+    @property
+    def log_level(self):
+        """
+        Numeric threshold: -1=trace, 0=debug, 1=info, 2=warning, 3=error
+        """
+        level = _LOG_SETTINGS["level"]
+        if level is not None:
+            return LOG_LEVELS[level]
+        return LOG_LEVELS["debug"] if self.verbose else LOG_LEVELS["info"]
+
+    def is_enabled(self, level):
+        """
+        Return True when records of a level are written
+
+        Args:
+            level (str): Level name
+
+        Returns:
+            bool
+        """
+        return self.log_level <= LOG_LEVELS[level]
+
+    def _emit(self, level, message, fields, dedupe=False):
+        if not self.is_enabled(level):
+            return
+        if dedupe:
+            msg_hash = hash((message, repr(sorted(fields.items())))) if fields else hash(message)
+            if msg_hash in self.seen_messages:
+                return
+            self.seen_messages.add(msg_hash)
+        if _LOG_SETTINGS["format"] == "json":
+            record = {
+                "time": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%S.%fZ"),
+                "level": level,
+                "logger": self.name,
+                "message": str(message),
+            }
+            record.update(fields)
+            line = json.dumps(record, default=str)
+        else:
+            fields = dict(fields)
+            trace = fields.pop("traceback", None)
+            line = f"{_TEXT_PREFIXES[level]}{message}"
+            if fields:
+                line += " [" + " ".join(f"{key}={value}" for key, value in fields.items()) + "]"
+            if trace:
+                line += "\n" + trace
+        stream = _LOG_SETTINGS["stream"] or sys.stderr
+        with _LOG_LOCK:
+            print(line, file=stream)
+
+    def trace(self, message, **fields):
+        """
+        Log a per-request message (only at the trace level, `-vv`)
+
+        Args:
+            message (str): Message to log
+            **fields: Structured fields of the record
+        """
+        self._emit("trace", message, fields)
+
+    def debug(self, message, **fields):
         """
-        Log a debug message (only in verbose mode)
+        Log a debug message (only in verbose mode), avoiding duplicates
 
         Args:
             message (str): Debug message to log
+            **fields: Structured fields of the record
         """
-        if self.log_level <= 0:
-            # Only log if not seen before
-            msg_hash = hash(message)
-            if msg_hash not in self.seen_messages:
-                print(f"... Debug: {message}")
-                self.seen_messages.add(msg_hash)
+        self._emit("debug", message, fields, dedupe=True)
 
-    def info(self, message):
+    def info(self, message, **fields):
         """
         Log an informational message, avoiding duplicates
 
         Args:
             message (str): Message to log
+            **fields: Structured fields of the record
         """
-        if self.log_level <= 1:
-            msg_hash = hash(message)
-            if msg_hash not in self.seen_messages:
-                print(message)
-                self.seen_messages.add(msg_hash)
+        self._emit("info", message, fields, dedupe=True)
 
-    def warning(self, message):
+    def warning(self, message, **fields):
         """
         Log a warning message, always showing warnings
 
         Args:
             message (str): Warning message to log
+            **fields: Structured fields of the record
         """
-        if self.log_level <= 2:
-            print(f"Warning: {message}", file=sys.stderr)
+        self._emit("warning", message, fields)
 
-    def error(self, message, exception=None):
+    def error(self, message, exception=None, **fields):
         """
         Log an error message with optional exception details
 
         Args:
             message (str): Error message to log
-            exception (Exception): Optional exception to include traceback for (if verbose)
+            exception (Exception): Optional exception to include traceback for (at the debug level)
+            **fields: Structured fields of the record
         """
-        if self.log_level <= 3:
-            error_msg = f"Error: {message}"
-            print(error_msg, file=sys.stderr)
-            if exception and self.verbose:
-                traceback.print_exc()
+        if exception and self.is_enabled("debug"):
+            formatted = traceback.format_exception(type(exception), exception, exception.__traceback__)
+            fields["traceback"] = "".join(formatted).rstrip()
+        self._emit("error", message, fields)
+
+    def exception(self, message, **fields):
+        """
+        Log an error message from inside an exception handler, with its traceback at the debug level
+
+        Args:
+            message (str): Error message to log
+            **fields: Structured fields of the record
+        """
+        self.error(message, exception=sys.exc_info()[1], **fields)
 
 
 # Module-level logger instance
@@ -117,27 +253,265 @@ def get_logger(name=None, verbose=False):
     return _module_logger
 
 
-def get_repo(repo_input):
+def tool_version():
+    """
+    Return the installed gardener version
+
+    Returns:
+        str: Distribution version, or "unknown" when gardener is not installed as a package
+    """
+    try:
+        return metadata.version("gardener")
+    except metadata.PackageNotFoundError:
+        return "unknown"
+
+
+# Hosted git repository URLs: scheme, a dotted host, then at least <owner>/<repo>
+REPOSITORY_URL_PATTERN = re.compile(r"(https?://)([^\s/]+\.[^\s/]+)\/([^\s/]+\/[^\s/]+)(\.git)?")
+
+# Full commit ids are fetched directly; anything else is cloned as a branch or tag name
+_COMMIT_ID_PATTERN = re.compile(r"[0-9a-f]{40}|[0-9a-f]{64}")
+
+
+def split_repository_ref(repo_input):
+    """
+    Split an optional "@ref" off the path of a repository URL
+
+    Only the path is searched, so credentials in the host part are left alone,
+    e.g. "https://github.com/org/repo@v1.2.0" -> ("https://github.com/org/repo", "v1.2.0")
+
+    Args:
+        repo_input (str): Repository URL or local path
+
+    Returns:
+        tuple: (repo_input without the ref, ref or None)
+    """
+    match = re.match(r"(https?://[^\s/]+)(/\S*)$", repo_input)
+    if not match:
+        return repo_input, None
+    path, separator, ref = match.group(2).partition("@")
+    if not separator:
+        return repo_input, None
+    return match.group(1) + path, ref or None
+
+
+def is_repository_url(repo_input):
+    """
+    Return True when the input names a hosted git repository rather than an existing local path
+
+    Args:
+        repo_input (str): Repository URL (optionally with "@ref") or local path
+
+    Returns:
+        bool
+    """
+    if os.path.exists(repo_input):
+        return False
+    return REPOSITORY_URL_PATTERN.match(split_repository_ref(repo_input)[0]) is not None
+
+
+def _repository_dir_name(repo_url):
+    """
+    Return the '<owner>_<repo>' directory name a repository URL is cloned into
+
+    Args:
+        repo_url (str): Repository URL matching REPOSITORY_URL_PATTERN
+
+    Returns:
+        str
+    """
+    match = REPOSITORY_URL_PATTERN.match(repo_url)
+    # Prefer owner_repo naming to avoid collisions across different owners
+    owner_repo = match.group(3).strip("/") if match else ""
+    if owner_repo.endswith(".git"):
+        owner_repo = owner_repo[:-4]
+    if owner_repo:
+        return owner_repo.replace("/", "_")
+    # Fallback to repo name only if parsing failed for some reason
+    repo_name = repo_url.rstrip("/").split("/")[-1]
+    return repo_name[:-4] if repo_name.endswith(".git") else repo_name
+
+
+def _validated_clone_url(clone_url):
+    """
+    Validate a git URL when the security module is available
+
+    Raises:
+        RepositoryError: If the URL is rejected
+    """
+    if not SECURITY_AVAILABLE:
+        return clone_url
+    try:
+        return InputValidator.validate_git_url(clone_url)
+    except ValidationError as e:
+        raise RepositoryError(f"Invalid git URL: {clone_url} - {e}")
+
+
+def _clone_environment(clone_url):
+    """
+    Return the environment for git commands that talk to clone_url
+
+    The --auth-token (or $GITHUB_TOKEN) is sent to the same hosts that receive it
+    for metadata lookups, as an HTTP header passed through GIT_CONFIG_* variables
+    so it appears neither in the process arguments nor in the clone's .git/config
+
+    Args:
+        clone_url (str): Repository URL
+
+    Returns:
+        dict: Environment variables for the git process
+    """
+    # Imported lazily because the resolver builds on this module
+    from gardener.package_metadata.url_resolver import auth_token_for
+
+    env = {"GIT_TERMINAL_PROMPT": "0"}
+    token = auth_token_for(clone_url)
+    if token:
+        credentials = base64.b64encode(f"x-access-token:{token}".encode()).decode()
+        env.update(
+            {
+                "GIT_CONFIG_COUNT": "1",
+                "GIT_CONFIG_KEY_0": "http.extraHeader",
+                "GIT_CONFIG_VALUE_0": f"Authorization: Basic {credentials}",
+            }
+        )
+    return env
+
+
+def _clone_once(clone_url, local_path, depth, ref):
+    """
+    Clone clone_url into local_path and check out ref
+
+    Args:
+        clone_url (str): Validated repository URL
+        local_path (str): Destination directory
+        depth (int): History depth; 0 clones the full history
+        ref (str|None): Branch, tag or full commit id; None checks out the default branch
+    """
+    # Lazy import git - only needed when cloning repositories
+    import git
+
+    env = _clone_environment(clone_url)
+    options = {"depth": depth} if depth else {}
+    commit = ref if ref and _COMMIT_ID_PATTERN.fullmatch(ref) else None
+    if ref and not commit:
+        options["branch"] = ref
+    repo = git.Repo.clone_from(clone_url, local_path, env=env, no_checkout=False, **options)
+    if commit:
+        # Commits cannot be named by --branch; fetch just that commit and detach HEAD at it
+        with repo.git.custom_environment(**env):
+            repo.git.fetch("origin", commit, **options)
+        repo.git.checkout("FETCH_HEAD")
+    return repo
+
+
+def _clone_repository(repo_url, local_path, depth=1, ref=None):
+    """
+    Clone a repository, retrying GitHub URLs with and without the ".git" suffix
+
+    Args:
+        repo_url (str): Repository URL without "@ref"
+        local_path (str): Destination directory
+        depth (int): History depth; 0 clones the full history
+        ref (str|None): Branch, tag or full commit id to check out
+
+    Raises:
+        RepositoryCloneError: If cloning fails
+        RepositoryError: If the URL or ref is rejected
+    """
+    logger = get_logger()
+    if ref and (ref.startswith("-") or re.search(r"\s|\.\.|[~^:?*\[\\]", ref)):
+        raise RepositoryError(f"Invalid git ref: {ref!r}")
+
+    # For GitHub repos, ensure the URL ends with .git for public repos
+    if "github.com" in repo_url and not repo_url.endswith(".git"):
+        clone_url = f"{repo_url}.git"
+    else:
+        clone_url = repo_url
+    clone_url = _validated_clone_url(clone_url)
+
+    shown_ref = f" at {ref}" if ref else ""
+    logger.info(f"Cloning repository from {clone_url}{shown_ref} to {local_path}...")
+    try:
+        try:
+            _clone_once(clone_url, local_path, depth, ref)
+        except Exception as e:
+            if "github.com" not in repo_url:
+                raise  # Re-raise the exception if not GitHub
+            logger.debug(f"Initial clone attempt failed, attempting alternative approach: {str(e)}")
+            # If GitHub and initial attempt fails, try without .git
+            alt_url = clone_url[:-4] if clone_url.endswith(".git") else f"{clone_url}.git"
+            alt_url = _validated_clone_url(alt_url)
+            logger.debug(f"Trying alternate URL: {alt_url}")
+            shutil.rmtree(local_path, ignore_errors=True)
+            _clone_once(alt_url, local_path, depth, ref)
+    except RepositoryError:
+        raise
+    except Exception as e:
+        raise RepositoryCloneError(f"Failed to clone repository: {str(e)}")
+
+
+@contextlib.contextmanager
+def cloned_repository(repo_input, depth=1, ref=None, offline=False):
+    """
+    Shallow-clone a repository URL into a temporary directory for the duration of a block
+
+    The clone lives at <tmp>/<owner>_<repo>, so output prefixes derived from its
+    basename match those of persistent clones. The temporary directory is removed
+    when the block exits, whether it succeeds or raises, and when cloning fails
+
+    Args:
+        repo_input (str): Repository URL, optionally suffixed with "@ref"
+        depth (int): History depth; 0 clones the full history
+        ref (str|None): Branch, tag or full commit id; must agree with an "@ref" in repo_input
+        offline (bool): Refuse to clone
+
+    Yields:
+        str: Absolute path of the checked-out repository
+
+    Raises:
+        RepositoryCloneError: If cloning fails
+        RepositoryError: If the input is not a repository URL or the refs conflict
+    """
+    repo_url, url_ref = split_repository_ref(repo_input)
+    if not REPOSITORY_URL_PATTERN.match(repo_url):
+        raise RepositoryError(f"'{repo_input}' is not a recognized repository URL")
+    if url_ref and ref and url_ref != ref:
+        raise RepositoryError(f"Conflicting refs: '@{url_ref}' in the URL and --branch {ref}")
+    if offline:
+        raise RepositoryError(f"{repo_input} is not a local directory and cannot be cloned in offline mode")
+
+    temp_dir = tempfile.mkdtemp(prefix="gardener-clone-")
+    try:
+        local_path = os.path.join(temp_dir, _repository_dir_name(repo_url))
+        _clone_repository(repo_url, local_path, depth=depth, ref=url_ref or ref)
+        yield local_path
+    finally:
+        shutil.rmtree(temp_dir, ignore_errors=True)
+        get_logger().debug(f"Removed temporary clone {temp_dir}")
+
+
+def get_repo(repo_input, offline=False):
     """
     Get a repository by cloning or using a local path
 
     Supports both local directory paths and remote git repository URLs
 
     For remote repositories, clones to an 'input/' subdirectory (named as '<owner>_<repo>'
-    when available) with fallback URL handling for common git hosting services
+    when available) that is kept for later runs; see cloned_repository for a
+    temporary clone
 
     Args:
         repo_input (str): URL of hosted git repo or local path to git repo
+        offline (bool): Refuse to clone; only local paths are accepted
 
     Returns:
         Local path to the repository
 
     Raises:
-        RepositoryError: If repository cannot be accessed or cloned
+        RepositoryCloneError: If the repository URL is valid but cloning it fails
+        RepositoryError: If the repository cannot be accessed
     """
-    # Lazy import git - only needed when cloning repositories
-    import git
-
     logger = get_logger()
     if os.path.exists(repo_input):
         if os.path.isdir(repo_input):
@@ -149,87 +523,29 @@ def get_repo(repo_input):
         else:
             raise RepositoryError(f"{repo_input} exists but is not a directory")
 
-    # If it's not a local path, check if it's a URL
-    # Basic URL pattern for git repositories - support any domain that might host git
-    url_pattern = r"(https?://)([^\s/]+\.[^\s/]+)\/([^\s/]+\/[^\s/]+)(\.git)?"
-    match = re.match(url_pattern, repo_input)
-    if match:
-        try:
-            # Prefer owner_repo naming to avoid collisions across different owners
-            owner_repo = match.group(3)  # e.g., 'owner/repo'
-            # Sanitize and normalize directory name
-            owner_repo = owner_repo.strip("/")
-            if owner_repo.endswith(".git"):
-                owner_repo = owner_repo[:-4]
-            local_dir_name = (
-                owner_repo.replace("/", "_") if owner_repo.count("/") == 1 else owner_repo.replace("/", "_")
-            )
-
-            # Fallback to repo name only if parsing failed for some reason
-            repo_name = repo_input.rstrip("/").split("/")[-1]
-            if repo_name.endswith(".git"):
-                repo_name = repo_name[:-4]
-
-            input_dir = os.path.join(os.getcwd(), "input")
-            if not os.path.exists(input_dir):
-                os.makedirs(input_dir)
-
-            # Use owner_repo when available, else just repo name
-            local_path = os.path.join(input_dir, local_dir_name or repo_name)
-
-            # If local path already exists, use it if it's a git repo
-            if os.path.exists(local_path):
-                if os.path.exists(os.path.join(local_path, ".git")):
-                    logger.info(f"Using existing git repository at {local_path}")
-                    return local_path
-                else:
-                    # Rename the existing directory to avoid conflicts
-                    timestamp = int(os.path.getmtime(local_path))
-                    backup_path = f"{local_path}_{timestamp}"
-                    logger.info(f"Renaming existing directory {local_path} to {backup_path}")
-                    os.rename(local_path, backup_path)
-
-            # For GitHub repos, ensure the URL ends with .git for public repos
-            if "github.com" in repo_input and not repo_input.endswith(".git"):
-                clone_url = f"{repo_input}.git"
-            else:
-                clone_url = repo_input
-
-            # Validate git URL for security
-            if SECURITY_AVAILABLE:
-                try:
-                    clone_url = InputValidator.validate_git_url(clone_url)
-                except ValidationError as e:
-                    raise RepositoryError(f"Invalid git URL: {clone_url} - {e}")
-
-            # Clone the repository
-            logger.info(f"Cloning repository from {clone_url} to {local_path}...")
-            try:
-                git.Repo.clone_from(clone_url, local_path, no_checkout=False, depth=1)
-                return local_path
-            except Exception as e:
-                if "github.com" in repo_input:
-                    logger.debug(f"Initial clone attempt failed, attempting alternative approach: {str(e)}")
-                    # If GitHub and initial attempt fails, try without .git
-                    if clone_url.endswith(".git"):
-                        alt_url = clone_url[:-4]
-                    else:
-                        alt_url = f"{clone_url}.git"
-
-                    # Validate alternate URL
-                    if SECURITY_AVAILABLE:
-                        try:
-                            alt_url = InputValidator.validate_git_url(alt_url)
-                        except ValidationError as e:
-                            raise RepositoryError(f"Invalid alternate git URL: {alt_url} - {e}")
-
-                    logger.debug(f"Trying alternate URL: {alt_url}")
-                    git.Repo.clone_from(alt_url, local_path, no_checkout=False, depth=1)
-                    return local_path
-                else:
-                    raise  # Re-raise the exception if not GitHub or second attempt fails
+    if offline:
+        raise RepositoryError(f"{repo_input} is not a local directory and cannot be cloned in offline mode")
 
-        except Exception as e:
-            raise RepositoryError(f"Failed to clone repository: {str(e)}")
-    else:
+    repo_url, ref = split_repository_ref(repo_input)
+    if not REPOSITORY_URL_PATTERN.match(repo_url):
         raise RepositoryError(f"'{repo_input}' is neither a valid local path nor a recognized repository URL")
+
+    input_dir = os.path.join(os.getcwd(), "input")
+    if not os.path.exists(input_dir):
+        os.makedirs(input_dir)
+    local_path = os.path.join(input_dir, _repository_dir_name(repo_url))
+
+    # If local path already exists, use it if it's a git repo
+    if os.path.exists(local_path):
+        if os.path.exists(os.path.join(local_path, ".git")):
+            logger.info(f"Using existing git repository at {local_path}")
+            return local_path
+        else:
+            # Rename the existing directory to avoid conflicts
+            timestamp = int(os.path.getmtime(local_path))
+            backup_path = f"{local_path}_{timestamp}"
+            logger.info(f"Renaming existing directory {local_path} to {backup_path}")
+            os.rename(local_path, backup_path)
+
+    _clone_repository(repo_url, local_path, depth=1, ref=ref)
+    return local_path
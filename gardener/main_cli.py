@@ -8,10 +8,612 @@ argument parsing, configuration overrides, and error handling for the analysis w
 
 import argparse
 import json
+import os
 import sys
 
+from gardener.analysis.git_diff import check_since_ref
 from gardener.analysis.main import run_analysis
-from gardener.common.utils import Logger, RepositoryError
+from gardener.analysis.output_schema import OutputValidationError
+from gardener.analysis.result_diff import diff_results, load_result_file, render_diff_summary
+from gardener.analysis.scanner import parse_file_list
+from gardener.analysis.webhook import WebhookError, parse_webhook_header, validate_webhook_url
+from gardener.common.archives import archive_format
+from gardener.common.defaults import (
+    ConfigOverride,
+    GoAnalysisConfig,
+    NetworkConfig,
+    effective_config,
+    invalid_config_overrides,
+)
+from gardener.common.exit_codes import exit_with_error
+from gardener.common.language_detection import parse_language_filter, supported_languages
+from gardener.common.repo_config import find_repo_config, load_repo_config
+from gardener.common.utils import (
+    LOG_FORMATS,
+    LOG_LEVELS,
+    Logger,
+    RepositoryCloneError,
+    RepositoryError,
+    configure_logging,
+    is_repository_url,
+    split_repository_ref,
+    verbosity_log_level,
+)
+from gardener.package_metadata.health import BACKEND_QUERIES, check_backends, failed_backends, render_checks
+from gardener.package_metadata.resolution_cache import cache_path, clear_resolution_cache, parse_ttl
+from gardener.package_metadata.url_rules import parse_url_rule
+from gardener.treewalk.registry import create_analyzers
+
+
+class _ArgumentParser(argparse.ArgumentParser):
+    """
+    Argument parser whose usage errors end with the structured error record
+    """
+
+    def error(self, message):
+        self.print_usage(sys.stderr)
+        exit_with_error("invalid-arguments", f"{self.prog}: error: {message}")
+
+
+def _fail(logger, error_code, message):
+    """
+    Log an error and exit with the status of error_code (see gardener.common.exit_codes)
+
+    Args:
+        logger (Logger): Logger instance
+        error_code (str): Key of exit_codes.ERROR_CODES
+        message (str): Human-readable description
+    """
+    logger.error(message)
+    exit_with_error(error_code, message)
+
+
+def _network_unresolved(results):
+    """
+    Return the external packages left without a repository URL because requests failed
+
+    Args:
+        results (dict): Analysis results
+
+    Returns:
+        list: Sorted package names
+    """
+    return sorted(
+        name
+        for name, package_info in results.get("external_packages", {}).items()
+        if not package_info.get("repository_url") and (package_info.get("resolution") or {}).get("network_errors")
+    )
+
+
+def _unresolved_urls(results, ignored_reasons):
+    """
+    Count the external packages left without a repository URL, for the --fail-on-unresolved-urls gate
+
+    Args:
+        results (dict): Analysis results
+        ignored_reasons (list): resolution.reason values whose packages are left out of both counts
+
+    Returns:
+        tuple: (sorted names of the unresolved packages, number of packages counted)
+    """
+    unresolved = []
+    counted = 0
+    for name, package_info in results.get("external_packages", {}).items():
+        if package_info.get("repository_url"):
+            counted += 1
+        elif (package_info.get("resolution") or {}).get("reason") not in ignored_reasons:
+            counted += 1
+            unresolved.append(name)
+    return sorted(unresolved), counted
+
+
+def _added_message(diff):
+    """
+    Describe the packages a diff adds, for the --fail-on-added gate
+
+    Args:
+        diff (dict): Result of diff_results
+
+    Returns:
+        str
+    """
+    return f"{len(diff['added'])} dependencies added since the baseline: {', '.join(p['id'] for p in diff['added'])}"
+
+
+def diff_main(argv):
+    """
+    Entry point of `gardener diff OLD NEW`, comparing two saved analysis JSON files
+
+    Prints the added, removed and version-changed packages as a readable summary,
+    or as JSON with --format json. Exits with status 2 when a file is not readable
+    analysis results, and 7 with --fail-on-added when NEW has packages OLD lacks
+
+    Args:
+        argv (list): Arguments after `diff`
+    """
+    logger = Logger(verbose=True)
+    parser = _ArgumentParser(prog="gardener diff")
+    parser.add_argument("old", help="Baseline analysis JSON, e.g. output/<prefix>_dependency_analysis.json")
+    parser.add_argument("new", help="Analysis JSON to compare with the baseline")
+    parser.add_argument(
+        "--format",
+        choices=["text", "json"],
+        default="text",
+        help="Print a readable summary (default) or the diff as JSON",
+    )
+    parser.add_argument(
+        "--fail-on-added",
+        action="store_true",
+        help="Exit with status 7 when NEW has external packages that OLD does not",
+    )
+    args = parser.parse_args(argv)
+
+    problems = []
+    loaded = []
+    for path in (args.old, args.new):
+        try:
+            loaded.append(load_result_file(path))
+        except ValueError as e:
+            problems.append(str(e))
+    if problems:
+        for problem in problems:
+            logger.error(problem)
+        exit_with_error("invalid-arguments", "; ".join(problems))
+    diff = diff_results(*loaded)
+    sys.stdout.write(json.dumps(diff, indent=2) + "\n" if args.format == "json" else render_diff_summary(diff))
+    sys.stdout.flush()
+    if args.fail_on_added and diff["added"]:
+        _fail(logger, "added-dependencies", _added_message(diff))
+
+
+def doctor_main(argv):
+    """
+    Entry point of `gardener doctor`, checking that the resolver backends can be reached
+
+    Sends one known query to each backend (npm, PyPI, crates.io, every GOPROXY proxy,
+    pkg.go.dev and the GitHub API) with the network configuration of a run, and prints
+    reachability, latency and auth status per backend, or the checks as JSON with
+    --format json. Hosts outside the allow/deny registry lists are reported as blocked
+    and --offline sends nothing. Exits with status 4 when a required backend is
+    unreachable or rejects the auth token, and 2 for invalid arguments
+
+    Args:
+        argv (list): Arguments after `doctor`
+    """
+    logger = Logger(verbose=True)
+    parser = _ArgumentParser(prog="gardener doctor")
+    parser.add_argument(
+        "--backend",
+        action="append",
+        choices=list(BACKEND_QUERIES),
+        help="Only check this backend; repeatable (default: all)",
+    )
+    parser.add_argument(
+        "--format",
+        choices=["text", "json"],
+        default="text",
+        help="Print a table (default) or the checks as JSON",
+    )
+    parser.add_argument("--offline", action="store_true", help="Send nothing; report every check as offline-skipped")
+    parser.add_argument("--max-retries", type=int, help="Retries for failing requests (default: 3)")
+    parser.add_argument("--auth-token", metavar="TOKEN", help="Bearer token as for an analysis (default: $GITHUB_TOKEN)")
+    parser.add_argument("--auth-host", action="append", metavar="HOST", help="Host receiving --auth-token; repeatable")
+    parser.add_argument("--allow-registry", action="append", metavar="GLOB", help="Only contact hosts matching GLOB")
+    parser.add_argument("--deny-registry", action="append", metavar="GLOB", help="Never contact hosts matching GLOB")
+    args = parser.parse_args(argv)
+
+    if args.max_retries is not None and args.max_retries < 0:
+        exit_with_error("invalid-arguments", "--max-retries must not be negative")
+
+    with ConfigOverride(_flag_overrides(args)):
+        checks = check_backends(args.backend, logger)
+    failed = failed_backends(checks)
+    if args.format == "json":
+        sys.stdout.write(json.dumps({"checks": checks, "failed": failed}, indent=2) + "\n")
+    else:
+        sys.stdout.write(render_checks(checks))
+    sys.stdout.flush()
+    if failed:
+        _fail(logger, "network-failure", f"Required resolver backends failed: {', '.join(failed)}")
+
+
+# Flags a settings file cannot set: a repository being analyzed must not pick where credentials
+# and results are sent, nor widen the hosts that are contacted
+_CLI_ONLY_SETTINGS = (
+    "repo_paths",
+    "help",
+    "check_config",
+    "strict_config",
+    "no_repo_config",
+    "quiet",
+    "auth_token",
+    "auth_host",
+    "allow_registry",
+    "url_rule",
+    "webhook_url",
+    "webhook_header",
+)
+
+# --format -> the destination --quiet writes to stdout; NDJSON records are streamed there instead
+_QUIET_DESTINATIONS = {"json": "json", "csv": "csv", "markdown": "markdown", "cyclonedx": "sbom", "spdx": "sbom"}
+
+# Settings naming files or directories; relative values in a settings file are relative to the repository root
+_PATH_SETTINGS = (
+    "gardener_ignore",
+    "test_frameworks",
+    "cache_dir",
+    "timings_output",
+    "table_out",
+    "json_out",
+    "csv_out",
+    "sbom_out",
+    "markdown_out",
+    "baseline",
+    "files_from",
+)
+
+# Configuration parameters the `config` setting of a settings file cannot override, for the same
+# reasons as _CLI_ONLY_SETTINGS and because their paths would not be checked against the repository root
+_CLI_ONLY_PARAMETERS = (
+    "AUTH_TOKEN",
+    "AUTH_HOSTS",
+    "ALLOW_REGISTRIES",
+    "URL_RULES",
+    "CACHE_DIR",
+    "GARDENER_IGNORE_FILE",
+    "TEST_FRAMEWORKS_FILE",
+)
+
+
+# Switches that set a configuration parameter to a fixed value: argparse dest -> (parameter, value)
+_SWITCH_SETTINGS = {
+    "include_stdlib": ("INCLUDE_STDLIB", True),
+    "group_clusters": ("GROUP_CLUSTERS", True),
+    "exclude_generated": ("EXCLUDE_GENERATED", True),
+    "include_ignored_build": ("INCLUDE_IGNORED_BUILD", True),
+    "with_positions": ("RECORD_IMPORT_POSITIONS", True),
+    "include_go_sum_only": ("INCLUDE_GO_SUM_ONLY", True),
+    "no_gitignore": ("RESPECT_GITIGNORE", False),
+    "follow_symlinks": ("FOLLOW_SYMLINKS", True),
+    "scan_vendor": ("SCAN_VENDOR", True),
+    "scan_submodules": ("SCAN_SUBMODULES", True),
+    "scan_archives": ("SCAN_ARCHIVES", True),
+    "offline": ("OFFLINE", True),
+    "flat": ("SERIALIZE_FLAT_PACKAGES", True),
+    "only_unresolved": ("SERIALIZE_ONLY_UNRESOLVED", True),
+    "packages_only": ("SERIALIZE_PACKAGES_ONLY", True),
+    "compress": ("SERIALIZE_COMPRESS", True),
+    "validate_output": ("SERIALIZE_VALIDATE", True),
+    "go_prunable": ("CHECK_PRUNABLE", True),
+}
+
+# Options whose value a configuration parameter takes as given: argparse dest -> parameter
+_OPTION_SETTINGS = {
+    "exclude": "EXCLUDE_PATTERNS",
+    "max_depth": "MAX_SCAN_DEPTH",
+    "max_retries": "MAX_RETRIES",
+    "retry_base_delay": "RETRY_BASE_DELAY",
+    "http_timeout": "HTTP_TIMEOUT",
+    "resolution_deadline": "RESOLUTION_DEADLINE",
+    "github_rate_limit_wait": "GITHUB_RATE_LIMIT_MAX_WAIT",
+    "resolver_concurrency": "RESOLVER_CONCURRENCY",
+    "auth_token": "AUTH_TOKEN",
+    "auth_host": "AUTH_HOSTS",
+    "allow_registry": "ALLOW_REGISTRIES",
+    "deny_registry": "DENY_REGISTRIES",
+    "url_rule": "URL_RULES",
+    "depth": "CLONE_DEPTH",
+    "max_transitive_depth": "MAX_TRANSITIVE_DEPTH",
+    "jobs": "ANALYSIS_JOBS",
+}
+
+
+def _flag_overrides(args):
+    """
+    Map the flags of _SWITCH_SETTINGS and _OPTION_SETTINGS that were given onto configuration parameters
+
+    Args:
+        args (argparse.Namespace): Parsed arguments; dests the parser does not define count as not given
+
+    Returns:
+        dict: Parameter name -> value, repeatable options as lists
+    """
+    overrides = {}
+    for dest, (parameter, value) in _SWITCH_SETTINGS.items():
+        if getattr(args, dest, False):
+            overrides[parameter] = value
+    for dest, parameter in _OPTION_SETTINGS.items():
+        value = getattr(args, dest, None)
+        if value is not None and value != "" and value != []:
+            overrides[parameter] = list(value) if isinstance(value, list) else value
+    return overrides
+
+
+def _setting_actions(parser):
+    """
+    Map the setting names a settings file may use to the parser actions they set, including
+    those of _CLI_ONLY_SETTINGS, which _apply_repo_config rejects
+
+    Args:
+        parser (argparse.ArgumentParser): CLI parser
+
+    Returns:
+        dict: Long flag name without dashes, with "_" for "-" (e.g. "allow_registry"), and
+            destination name (e.g. "sort" for --no-sort) -> argparse action
+    """
+    actions = {}
+    for action in parser._actions:
+        for option in action.option_strings:
+            if option.startswith("--"):
+                actions[option[2:].replace("-", "_")] = action
+    for action in parser._actions:
+        if action.dest != "repo_paths":
+            actions.setdefault(action.dest, action)
+    return actions
+
+
+def _command_line_dests(parser, argv):
+    """
+    Return the destinations the command line sets explicitly
+
+    Args:
+        parser (argparse.ArgumentParser): CLI parser
+        argv (list): Command-line arguments
+
+    Returns:
+        set: Destination names of the flags given in argv
+    """
+    defaults = [(action, action.default) for action in parser._actions]
+    try:
+        for action, _ in defaults:
+            action.default = argparse.SUPPRESS
+        given, _ = parser.parse_known_args(argv)
+    finally:
+        for action, default in defaults:
+            action.default = default
+    return set(vars(given))
+
+
+def _scalar_setting_value(action, value, repo_root):
+    """
+    Convert one settings file value the way the command line would convert the flag's argument
+
+    Args:
+        action (argparse.Action): Action of the setting's flag
+        value: Value read from the file; lists are joined with commas and mappings written as JSON
+        repo_root (str): Directory relative paths are resolved against
+
+    Returns:
+        object: Converted value
+
+    Raises:
+        ValueError: If the value does not fit the flag, a path leaves repo_root (or an output
+            prefix the output directory), or `config` sets one of _CLI_ONLY_PARAMETERS
+    """
+    if isinstance(value, bool):
+        raise ValueError("expected a value, not true or false")
+    if isinstance(value, dict):
+        value = json.dumps(value)
+    elif isinstance(value, list):
+        value = ",".join(str(item) for item in value)
+    value = str(value)
+    if action.type is not None:
+        try:
+            value = action.type(value)
+        except (TypeError, ValueError):
+            raise ValueError(f"invalid {getattr(action.type, '__name__', 'value')} value {value!r}") from None
+    if action.choices is not None and value not in action.choices:
+        raise ValueError(f"invalid choice {value!r} (choose from {', '.join(map(str, action.choices))})")
+    if action.dest in _PATH_SETTINGS and value != "-":
+        value = os.path.join(repo_root, os.path.expanduser(value))
+        root = os.path.realpath(repo_root)
+        if os.path.commonpath([root, os.path.realpath(value)]) != root:
+            raise ValueError(f"{value!r} is outside the repository")
+    elif action.dest == "output":
+        # A prefix under the output directory, which is not the repository's
+        if os.path.isabs(os.path.expanduser(value)) or os.path.normpath(value).split(os.sep)[0] == "..":
+            raise ValueError(f"{value!r} is outside the output directory")
+    elif action.dest == "config":
+        try:
+            overrides = json.loads(value)
+        except ValueError:
+            # Reported with the command line's own parse error
+            overrides = {}
+        reserved = sorted(set(overrides) & set(_CLI_ONLY_PARAMETERS)) if isinstance(overrides, dict) else []
+        if reserved:
+            raise ValueError(f"{', '.join(reserved)} can only be set on the command line")
+    return value
+
+
+def _setting_value(name, action, value, repo_root):
+    """
+    Convert a settings file value to what its flag would store
+
+    Args:
+        name (str): Normalized setting name
+        action (argparse.Action): Action of the setting's flag
+        value: Value read from the file
+        repo_root (str): Directory relative paths are resolved against
+
+    Returns:
+        object: Value for the action's destination
+
+    Raises:
+        ValueError: If the value does not fit the flag
+    """
+    if action.nargs == 0 and isinstance(action.const, bool):
+        if not isinstance(value, bool):
+            raise ValueError("expected true or false")
+        # `no-sort: true` stores the flag's constant, `sort: false` the value itself
+        return value if name == action.dest else (action.const if value else not action.const)
+    if action.nargs == 0:
+        if isinstance(value, bool) or not isinstance(value, int):
+            raise ValueError("expected an integer")
+        return value
+    if isinstance(action, argparse._AppendAction):
+        values = value if isinstance(value, list) else [value]
+        return [_scalar_setting_value(action, item, repo_root) for item in values]
+    if value is True and action.nargs == "?":
+        return action.const
+    return _scalar_setting_value(action, value, repo_root)
+
+
+def _apply_repo_config(parser, args, config_file, argv, logger):
+    """
+    Fill in the settings of a repository settings file that the command line leaves at their defaults
+
+    Args:
+        parser (argparse.ArgumentParser): CLI parser
+        args (argparse.Namespace): Parsed arguments, updated in place
+        config_file (str): Path of the settings file (see find_repo_config)
+        argv (list): Command-line arguments
+        logger (Logger): Logger for unknown settings when they are not problems
+
+    Returns:
+        list: Problems found; unknown settings are problems only with --strict-config
+    """
+    filename = os.path.basename(config_file)
+    try:
+        settings = load_repo_config(config_file)
+    except ValueError as e:
+        return [str(e)]
+    actions = _setting_actions(parser)
+    given = _command_line_dests(parser, argv)
+    repo_root = os.path.dirname(config_file)
+    problems = []
+    for key, value in settings.items():
+        name = str(key).replace("-", "_")
+        action = actions.get(name)
+        if action is None:
+            message = f"{filename}: unknown setting '{key}'"
+            if args.strict_config:
+                problems.append(message)
+            else:
+                logger.warning(f"{message} ignored")
+            continue
+        if action.dest in _CLI_ONLY_SETTINGS:
+            problems.append(f"{filename}: '{key}' can only be given on the command line")
+            continue
+        try:
+            value = _setting_value(name, action, value, repo_root)
+        except ValueError as e:
+            problems.append(f"{filename}: {key}: {e}")
+            continue
+        if action.dest not in given:
+            setattr(args, action.dest, value)
+    return problems
+
+
+def _read_file_list(source):
+    """
+    Read the --files-from list
+
+    Args:
+        source (str): File path, or "-" for stdin
+
+    Returns:
+        list: Paths in list order (see parse_file_list)
+
+    Raises:
+        OSError: If the file cannot be read
+    """
+    if source == "-":
+        return parse_file_list(sys.stdin.read())
+    with open(source, encoding="utf-8") as handle:
+        return parse_file_list(handle.read())
+
+
+def _input_problems(args):
+    """
+    Check the repository input against the options that depend on it, without cloning or walking it
+
+    Args:
+        args (argparse.Namespace): Parsed arguments
+
+    Returns:
+        list: Problem descriptions, empty when the input is usable
+    """
+    problems = []
+    if len(args.repo_paths) > 1:
+        # Each root is analyzed whole: the options naming one repository's files or ref cannot apply to all
+        single = [
+            option
+            for option, given in (
+                ("--branch", args.branch),
+                ("--since", args.since),
+                ("--files-from", args.files_from),
+                ("--format ndjson", args.format == "ndjson"),
+                ("--packages-only", args.packages_only),
+            )
+            if given
+        ]
+        if single:
+            verb = "needs" if len(single) == 1 else "need"
+            problems.append(f"{', '.join(single)} {verb} a single repository path")
+            return problems
+    for repo_path in args.repo_paths:
+        if is_repository_url(repo_path):
+            _, url_ref = split_repository_ref(repo_path)
+            if url_ref and args.branch and url_ref != args.branch:
+                problems.append(f"Conflicting refs: '@{url_ref}' in the URL and --branch {args.branch}")
+            if args.offline:
+                problems.append(
+                    f"{repo_path} is a repository URL, which --offline cannot clone; analyze a local checkout"
+                )
+            continue
+        if args.branch:
+            problems.append(
+                f"--branch {args.branch} only applies to repository URLs, not to the local path {repo_path}"
+            )
+        if not os.path.exists(repo_path):
+            problems.append(f"Repository path not found: {repo_path}")
+        elif args.since and archive_format(repo_path):
+            problems.append(f"--since needs a git repository, but {repo_path} is a source archive")
+        elif args.since and os.path.isdir(repo_path):
+            try:
+                check_since_ref(os.path.abspath(repo_path), args.since)
+            except RepositoryError as e:
+                problems.append(str(e))
+    return problems
+
+
+def _effective_configuration(args, config_overrides, destinations, sbom_format, config_file=None):
+    """
+    Describe what a run with these arguments would do, for --check-config
+
+    Args:
+        args (argparse.Namespace): Parsed arguments
+        config_overrides (dict|None): Overrides from --config and the flags mapped onto parameters
+        destinations (dict): Destination kind -> file path or "-"
+        sbom_format (str): SBOM format of the sbom destination
+        config_file (str|None): Repository settings file applied (see find_repo_config)
+
+    Returns:
+        dict: {"repo_path", "repo_paths", "config_file", "branch", "since", "files_from", "analyzers", "outputs",
+            "config"}, where repo_path is the first of repo_paths, analyzers lists
+            the languages whose analyzers would run and config every parameter with its effective value
+    """
+    languages = parse_language_filter(args.languages)
+    analyzers = sorted(language for language in create_analyzers() if languages is None or language in languages)
+    return {
+        "repo_path": args.repo_paths[0],
+        "repo_paths": args.repo_paths,
+        "config_file": config_file,
+        "branch": args.branch,
+        "since": args.since,
+        "files_from": args.files_from,
+        "analyzers": analyzers,
+        "outputs": {
+            "prefix": args.output,
+            "format": args.format,
+            "visualize": args.visualize,
+            "timings": args.timings_output or args.timings,
+            "destinations": destinations,
+            "sbom_format": sbom_format if "sbom" in destinations else None,
+            "webhook": args.webhook_url,
+        },
+        "config": effective_config(config_overrides),
+    }
 
 
 def main():
@@ -22,13 +624,62 @@ def main():
     orchestrates the dependency analysis on the specified repository. Handles
     both local paths and remote git repository URLs
 
-    Exits with status 1 on errors (repository access failures or unexpected exceptions)
+    Exits with one of the statuses in gardener.common.exit_codes, writing a
+    {"error_code", "exit_code", "message"} JSON line to stderr for any status but 0:
+    2 for invalid arguments or configuration (all the problems found are reported
+    together, before the repository is cloned or walked), 3 when no source files were found,
+    1 for failed files with --fail-on-error (or an unexpected exception), 4 when
+    cloning failed or packages went unresolved because of network errors, 5 when more
+    packages than --fail-on-unresolved-urls(-pct) allows have no repository URL, 6 when the
+    results could not be delivered to --webhook-url with --require-webhook, 7 when
+    --fail-on-added finds packages the --baseline lacks, 8 when --validate-output finds the
+    analysis JSON does not match its schema, and 130
+    when Ctrl-C interrupted the run, after the partial results were written. `gardener diff`
+    compares two saved analyses instead (see diff_main), and `gardener doctor` checks
+    the resolver backends (see doctor_main)
     """
+    if sys.argv[1:2] == ["diff"]:
+        diff_main(sys.argv[2:])
+        return
+    if sys.argv[1:2] == ["doctor"]:
+        doctor_main(sys.argv[2:])
+        return
     logger = Logger(verbose=True)  # CLI should show all messages
-    parser = argparse.ArgumentParser()
-    parser.add_argument("repo_path", help="Path to repo directory, or URL of hosted git repo")
+    parser = _ArgumentParser()
+    parser.add_argument(
+        "repo_paths",
+        nargs="+",
+        metavar="repo_path",
+        help="Path to repo directory or to a zip or tar source archive, or URL of hosted git repo (optionally "
+        "suffixed with @ref); archives and URLs are unpacked or cloned into a temporary directory for the run. "
+        "Given several, their results are combined into one report attributing each package to its roots",
+    )
     parser.add_argument("-o", "--output", help="Output file prefix")
-    parser.add_argument("-v", "--verbose", action="store_true", help="Enable verbose debug logging")
+    parser.add_argument(
+        "-v",
+        "--verbose",
+        action="count",
+        default=0,
+        help="Log skipped files, URL resolutions, cache hits and retries (-v), and every HTTP request too (-vv)",
+    )
+    parser.add_argument(
+        "--log-level",
+        choices=list(LOG_LEVELS),
+        help="Minimum level of the log records written to stderr; overrides -v (default: info)",
+    )
+    parser.add_argument(
+        "--log-format",
+        choices=list(LOG_FORMATS),
+        default="text",
+        help="Write log records as readable text (default) or as one JSON object per line",
+    )
+    parser.add_argument(
+        "-q",
+        "--quiet",
+        action="store_true",
+        help="Write the --format document (analysis JSON, NDJSON records, CSV, Markdown report or SBOM) to stdout "
+        "and nothing else, and log only errors to stderr; for calling gardener from another program",
+    )
     # Default behavior: minimal outputs (skip visualizations)
     parser.add_argument(
         "-m",
@@ -40,23 +691,590 @@ def main():
         "--visualize", action="store_true", help="Generate an HTML graph visualization in addition to JSON outputs"
     )
     parser.add_argument(
-        "-l", "--languages", help="Comma-separated list of languages to focus on (e.g., python,javascript)"
+        "-l",
+        "--languages",
+        "--language",
+        help=(
+            "Comma-separated list of languages to analyze (e.g., go,js); "
+            f"supported: {', '.join(supported_languages())} (default: all)"
+        ),
     )
     parser.add_argument("-c", "--config", help="JSON string with configuration overrides")
-    args = parser.parse_args()
+    parser.add_argument(
+        "--include-stdlib",
+        action="store_true",
+        help="Report Go standard-library imports alongside external packages (off by default)",
+    )
+    parser.add_argument(
+        "--exclude-generated",
+        action="store_true",
+        help='Omit imports of Go files marked "// Code generated ... DO NOT EDIT." (tagged generated by default)',
+    )
+    parser.add_argument(
+        "--group-clusters",
+        action="store_true",
+        help="Group tightly coupled Go modules (protobuf, gRPC, genproto) into clusters, still listing each module",
+    )
+    parser.add_argument(
+        "--include-ignored-build",
+        action="store_true",
+        help='Report imports of Go files tagged "//go:build ignore" (excluded by default) with scope ignored-build',
+    )
+    parser.add_argument(
+        "--build-tags",
+        metavar="TAGS",
+        help="Comma-separated tags your builds pass with -tags; imports of Go files needing any other "
+        "non-platform tag get build_reachability unlikely",
+    )
+    parser.add_argument(
+        "--with-positions",
+        action="store_true",
+        help="Add the line and column of each Go import (the spec's own line in grouped blocks) to its evidence",
+    )
+    parser.add_argument(
+        "--include-go-sum-only",
+        action="store_true",
+        help="Report modules listed in go.sum but required by no go.mod as dependencies (source: go.sum-only)",
+    )
+    parser.add_argument(
+        "--no-gitignore",
+        action="store_true",
+        help="Scan paths matched by .gitignore files, which are skipped by default",
+    )
+    parser.add_argument(
+        "--follow-symlinks",
+        action="store_true",
+        help="Follow symlinked files and directories that stay inside the repository, which are skipped by default",
+    )
+    parser.add_argument(
+        "--exclude",
+        action="append",
+        metavar="GLOB",
+        help="Skip repo-relative paths matching GLOB ('**' spans directories); repeatable",
+    )
+    parser.add_argument(
+        "--gardener-ignore",
+        metavar="FILE",
+        help="File listing one exclude glob per line (default: .gardenerignore at the repository root)",
+    )
+    parser.add_argument(
+        "--test-frameworks",
+        metavar="FILE",
+        help="File listing extra Go test framework module paths, one per line "
+        "(default: .gardener-test-frameworks at the repository root)",
+    )
+    parser.add_argument(
+        "--max-depth",
+        type=int,
+        metavar="N",
+        help="Descend at most N directories below the repository root (0 scans only the root directory)",
+    )
+    parser.add_argument(
+        "--scan-vendor",
+        action="store_true",
+        help="Parse Go sources under vendor/ as first-party code (skipped by default)",
+    )
+    parser.add_argument(
+        "--scan-submodules",
+        action="store_true",
+        help="Parse sources inside git submodules listed in .gitmodules as first-party code (skipped by default)",
+    )
+    parser.add_argument(
+        "--scan-archives",
+        action="store_true",
+        help="Also parse the sources inside zip, jar and tar archives in the tree, one level deep (off by default)",
+    )
+    parser.add_argument(
+        "--offline",
+        action="store_true",
+        help="Never touch the network: resolve repository URLs from local signals only",
+    )
+    parser.add_argument(
+        "--max-retries",
+        type=int,
+        help="Retries for registry and proxy requests failing with connection errors, 429 or 5xx (default: 3)",
+    )
+    parser.add_argument(
+        "--retry-base-delay",
+        type=float,
+        help="Seconds before the first retry; doubled for each further retry, with jitter (default: 1.0)",
+    )
+    parser.add_argument(
+        "--http-timeout",
+        type=float,
+        metavar="SECONDS",
+        help="Seconds a single registry, proxy or GitHub request may wait for a connection or data (default: 10)",
+    )
+    parser.add_argument(
+        "--resolution-deadline",
+        type=float,
+        metavar="SECONDS",
+        help="Wall-clock budget for all network resolution; packages still unresolved when it runs out get "
+        "reason resolution-deadline-exceeded (default: unlimited)",
+    )
+    parser.add_argument(
+        "--github-rate-limit-wait",
+        type=float,
+        metavar="SECONDS",
+        help="Longest GitHub API rate-limit wait (Retry-After, X-RateLimit-Reset) to sit out before giving up "
+        "with license_reason github-rate-limited (default: 60)",
+    )
+    parser.add_argument(
+        "--resolver-concurrency",
+        type=int,
+        metavar="N",
+        help="Maximum registry, module proxy and go-import requests in flight at once, independent of --jobs "
+        "(default: 8)",
+    )
+    parser.add_argument(
+        "--auth-token",
+        metavar="TOKEN",
+        help="Bearer token for go-import lookups and clones on --auth-host hosts, or without them for github.com "
+        "in place of $GITHUB_TOKEN, which is only ever sent to github.com",
+    )
+    parser.add_argument(
+        "--auth-host",
+        action="append",
+        metavar="HOST",
+        help="Host (with its subdomains) that receives --auth-token; repeatable",
+    )
+    parser.add_argument(
+        "--allow-registry",
+        action="append",
+        metavar="GLOB",
+        help="Only send resolution requests to hosts matching GLOB, e.g. '*.corp.example.com'; repeatable",
+    )
+    parser.add_argument(
+        "--deny-registry",
+        action="append",
+        metavar="GLOB",
+        help="Never send resolution requests to hosts matching GLOB (wins over --allow-registry); repeatable",
+    )
+    parser.add_argument(
+        "--url-rule",
+        action="append",
+        metavar="'PATTERN URL'",
+        help="Resolve packages whose name matches the regex PATTERN to URL, a template with {1}, {name}, ... "
+        "for its groups, before any resolver; repeatable, first match wins",
+    )
+    parser.add_argument(
+        "--branch",
+        metavar="REF",
+        help="Branch, tag or full commit id to check out when analyzing a repository URL (same as URL@REF)",
+    )
+    parser.add_argument(
+        "--depth",
+        type=int,
+        help="Commits of history to clone when analyzing a repository URL; 0 clones the full history, "
+        "as --since needs (default: 1)",
+    )
+    parser.add_argument(
+        "--max-transitive-depth",
+        type=int,
+        help="Resolve the Go module require graph through the module proxy up to N levels (off by default)",
+    )
+    parser.add_argument(
+        "--go-prunable",
+        action="store_true",
+        help="List go.mod requires marked // indirect that no import needs as prunable_candidates, and warn "
+        "about imported ones marked // indirect (online, the go.mod of each require is fetched to verify)",
+    )
+    parser.add_argument(
+        "-j",
+        "--jobs",
+        type=int,
+        help="Number of worker threads used to parse source files (default: CPU count)",
+    )
+    parser.add_argument(
+        "--cache-dir",
+        default=".gardener-cache",
+        help="Directory for the incremental per-file analysis cache (default: .gardener-cache)",
+    )
+    parser.add_argument(
+        "--resolution-cache-ttl",
+        metavar="DURATION",
+        help="How long resolved repository URLs are reused from the cache directory before being looked up "
+        "again, e.g. 12h or 30d; 0 always re-resolves (default: 7d)",
+    )
+    parser.add_argument(
+        "--clear-resolution-cache",
+        action="store_true",
+        help="Delete the cached repository URLs in the cache directory before analyzing",
+    )
+    parser.add_argument(
+        "--fail-on-error",
+        action="store_true",
+        help="Exit with status 1 when any source file could not be read or parsed (reported under errors)",
+    )
+    parser.add_argument(
+        "--fail-on-unresolved-urls",
+        nargs="?",
+        const=0,
+        type=int,
+        metavar="N",
+        help="Exit with status 5 when more than N external packages are left without a repository URL "
+        "(default N: 0)",
+    )
+    parser.add_argument(
+        "--fail-on-unresolved-urls-pct",
+        type=float,
+        metavar="PCT",
+        help="Exit with status 5 when more than PCT percent of the external packages are left without a "
+        "repository URL",
+    )
+    parser.add_argument(
+        "--unresolved-urls-ignore",
+        action="append",
+        metavar="REASON",
+        help="Leave packages unresolved for this reason (e.g. offline-skipped, private-module-skipped) out of "
+        "the --fail-on-unresolved-urls counts; repeatable",
+    )
+    parser.add_argument(
+        "--baseline",
+        metavar="FILE",
+        help="Compare the results with an earlier analysis JSON and report the added, removed and "
+        "version-changed packages in a baseline_diff section",
+    )
+    parser.add_argument(
+        "--fail-on-added",
+        action="store_true",
+        help="Exit with status 7 when the analysis finds external packages the --baseline does not have",
+    )
+    parser.add_argument(
+        "--since",
+        metavar="REF",
+        help="Analyze only source files changed between git REF and HEAD (git diff REF...HEAD); "
+        "the output is marked as a partial analysis",
+    )
+    parser.add_argument(
+        "--files-from",
+        metavar="FILE",
+        help="Analyze only the source files listed in FILE ('-' for stdin), one path per line relative to the "
+        "repository root, instead of walking the tree; .gitignore and excludes do not apply to listed files",
+    )
+    parser.add_argument(
+        "--no-cache",
+        action="store_true",
+        help="Parse every file without reading or writing the incremental analysis cache",
+    )
+    parser.add_argument(
+        "--format",
+        choices=["json", "csv", "ndjson", "markdown", "cyclonedx", "spdx"],
+        default="json",
+        help=(
+            "Output format: analysis JSON only (default), or also write a CSV table of external "
+            "dependencies, an NDJSON stream of file, package and summary records, a Markdown dependency "
+            "report, or a CycloneDX 1.5 or SPDX 2.3 SBOM"
+        ),
+    )
+    parser.add_argument(
+        "--table-out",
+        metavar="DEST",
+        help="Also print the top dependencies as a table to DEST, a file path or '-' for stdout",
+    )
+    parser.add_argument(
+        "--json-out",
+        metavar="DEST",
+        help="Also write the analysis JSON to DEST, a file path or '-' for stdout",
+    )
+    parser.add_argument(
+        "--csv-out",
+        metavar="DEST",
+        help="Also write the CSV table of external dependencies (see --csv-delimiter) to DEST, a file path or '-' "
+        "for stdout",
+    )
+    parser.add_argument(
+        "--sbom-out",
+        metavar="DEST",
+        help="Also write an SBOM (see --sbom-format) to DEST, a file path or '-' for stdout",
+    )
+    parser.add_argument(
+        "--markdown-out",
+        metavar="DEST",
+        help="Also write the Markdown dependency report to DEST, a file path or '-' for stdout",
+    )
+    parser.add_argument(
+        "--sbom-format",
+        choices=["cyclonedx", "spdx"],
+        help="SBOM format of --sbom-out (default: the --format SBOM format, else cyclonedx)",
+    )
+    parser.add_argument(
+        "--webhook-url",
+        metavar="URL",
+        help="POST the analysis JSON (the NDJSON records with --format ndjson) to URL once the analysis completes",
+    )
+    parser.add_argument(
+        "--webhook-header",
+        action="append",
+        metavar="'NAME: VALUE'",
+        help="Header sent with the --webhook-url request, e.g. 'Authorization: Bearer TOKEN'; repeatable",
+    )
+    parser.add_argument(
+        "--require-webhook",
+        action="store_true",
+        help="Exit with status 6 when the results could not be delivered to --webhook-url (logged otherwise)",
+    )
+    parser.add_argument(
+        "--sort",
+        dest="sort",
+        action="store_true",
+        default=True,
+        help="Write the analysis JSON in canonical order: packages by (ecosystem, name, version), file lists "
+        "and object keys sorted (default)",
+    )
+    parser.add_argument(
+        "--no-sort",
+        dest="sort",
+        action="store_false",
+        help="Keep packages, files and keys in discovery order",
+    )
+    parser.add_argument(
+        "--flat",
+        action="store_true",
+        help="Write external packages as one flat map in the JSON results instead of grouped by ecosystem",
+    )
+    parser.add_argument(
+        "--only-unresolved",
+        action="store_true",
+        help="Write only the external packages left without a repository URL to the analysis JSON, grouped by "
+        "reason in an unresolved section; the summary still counts every package",
+    )
+    parser.add_argument(
+        "--packages-only",
+        action="store_true",
+        help="Write only the deduplicated ecosystem, name and version of each external package, as a JSON array "
+        "in output/<prefix>_packages.json instead of the analysis JSON; URLs are not resolved and no evidence "
+        "or graph is assembled",
+    )
+    parser.add_argument(
+        "--compress",
+        action="store_true",
+        help="Gzip the output files (adding .gz to their names) and every --*-out destination, NDJSON included as "
+        "it streams; destinations named .gz are gzipped without it, and the graph HTML stays plain",
+    )
+    parser.add_argument(
+        "--validate-output",
+        action="store_true",
+        help="Check the analysis JSON against its published schema before writing it, and exit with status 8 "
+        "without writing it (or any other output but a streamed NDJSON file) if it does not match",
+    )
+    parser.add_argument(
+        "--timings",
+        action="store_true",
+        help="Record how long each phase took (directory walk, parsing per language, URL resolution and its "
+        "network time, serialization) in a timings section of the analysis JSON",
+    )
+    parser.add_argument(
+        "--timings-output",
+        metavar="FILE",
+        help="Write the timings to FILE instead of the analysis JSON (implies --timings)",
+    )
+    parser.add_argument(
+        "--strict-config",
+        action="store_true",
+        help="Treat unknown settings in the repository's .gardener.yaml or .gardener.toml as errors",
+    )
+    parser.add_argument(
+        "--no-repo-config",
+        action="store_true",
+        help="Ignore the repository's .gardener.yaml or .gardener.toml settings file",
+    )
+    parser.add_argument(
+        "--check-config",
+        action="store_true",
+        help="Validate the options, print the effective configuration and the analyzers that would run as JSON, "
+        "and exit without analyzing",
+    )
+    parser.add_argument(
+        "--csv-delimiter",
+        default=",",
+        help="Field separator for --format csv; use 'tab' or '\\t' for TSV (default: ',')",
+    )
+    args, unknown_args = parser.parse_known_args()
+    default_level = "error" if args.quiet else verbosity_log_level(args.verbose)
+    configure_logging(args.log_level or default_level, args.log_format)
 
+    # Every problem is collected so that one run reports them all, before anything is read or cloned
+    problems = [f"Unrecognized arguments: {' '.join(unknown_args)}"] if unknown_args else []
+    # Settings committed with the repository replace the defaults; flags on the command line win over them
+    config_file = None
+    # With several repository paths, the settings of the first apply to the run
+    if not args.no_repo_config and os.path.isdir(args.repo_paths[0]):
+        config_file = find_repo_config(args.repo_paths[0])
+    if config_file:
+        logger.info(f"Using settings from {config_file}")
+        problems.extend(_apply_repo_config(parser, args, config_file, sys.argv[1:], logger))
     config_overrides = None
     if args.config:
         try:
             config_overrides = json.loads(args.config)
+            if not isinstance(config_overrides, dict):
+                raise ValueError("not a JSON object")
+        except ValueError as e:
+            config_overrides = None
+            problems.append(
+                f"Error parsing configuration overrides: {e}. Configuration must be a JSON object, "
+                "e.g., '{\"PAGERANK_ALPHA\": 0.85}' . See gardener/common/defaults.py for overrideable parameter names"
+            )
+    if config_overrides:
+        override_problems = invalid_config_overrides(config_overrides)
+        if override_problems:
+            problems.append(f"Invalid configuration overrides: {'; '.join(override_problems)}")
+        else:
             logger.info(f"Applying {len(config_overrides)} configuration overrides")
-        except json.JSONDecodeError as e:
-            logger.error(f"Error parsing configuration overrides: {e}")
-            logger.error(
-                "Configuration must be a valid JSON string, e.g., '{\"PAGERANK_ALPHA\": 0.85}' . "
-                "See gardener/common/defaults.py for overrideable parameter names"
+
+    try:
+        parse_language_filter(args.languages)
+    except ValueError as e:
+        problems.append(str(e))
+
+    config_overrides = {**(config_overrides or {}), **_flag_overrides(args)}
+    if args.build_tags is not None:
+        tags = [tag.strip() for tag in args.build_tags.split(",") if tag.strip()]
+        config_overrides["BUILD_TAGS"] = list(config_overrides.get("BUILD_TAGS", GoAnalysisConfig.BUILD_TAGS)) + tags
+    if args.gardener_ignore:
+        if not os.path.isfile(args.gardener_ignore):
+            problems.append(f"--gardener-ignore file not found: {args.gardener_ignore}")
+        config_overrides["GARDENER_IGNORE_FILE"] = os.path.abspath(args.gardener_ignore)
+    if args.test_frameworks:
+        if not os.path.isfile(args.test_frameworks):
+            problems.append(f"--test-frameworks file not found: {args.test_frameworks}")
+        config_overrides["TEST_FRAMEWORKS_FILE"] = os.path.abspath(args.test_frameworks)
+    if not args.sort:
+        config_overrides["SERIALIZE_SORT_KEYS"] = False
+    if args.max_depth is not None and args.max_depth < 0:
+        problems.append("--max-depth must not be negative")
+    if args.max_retries is not None and args.max_retries < 0:
+        problems.append("--max-retries must not be negative")
+    if args.retry_base_delay is not None and args.retry_base_delay < 0:
+        problems.append("--retry-base-delay must not be negative")
+    if args.http_timeout is not None and args.http_timeout <= 0:
+        problems.append("--http-timeout must be positive")
+    if args.resolution_deadline is not None and args.resolution_deadline <= 0:
+        problems.append("--resolution-deadline must be positive")
+    if args.github_rate_limit_wait is not None and args.github_rate_limit_wait < 0:
+        problems.append("--github-rate-limit-wait must not be negative")
+    if args.resolver_concurrency is not None and args.resolver_concurrency < 1:
+        problems.append("--resolver-concurrency must be a positive integer")
+    for rule in config_overrides.get("URL_RULES") or []:
+        try:
+            parse_url_rule(rule)
+        except ValueError as e:
+            problems.append(str(e))
+    if args.depth is not None:
+        if args.depth < 0:
+            problems.append("--depth must not be negative")
+        if not any(is_repository_url(repo_path) for repo_path in args.repo_paths):
+            problems.append("--depth only applies to repository URLs")
+    if args.max_transitive_depth is not None and args.max_transitive_depth < 0:
+        problems.append("--max-transitive-depth must not be negative")
+    if args.jobs is not None and args.jobs < 1:
+        problems.append("--jobs must be a positive integer")
+    csv_delimiter = "\t" if args.csv_delimiter in ("tab", "\\t") else args.csv_delimiter
+    if len(csv_delimiter) != 1 or csv_delimiter in "\"\r\n":
+        problems.append("--csv-delimiter must be a single character other than a quote or line break")
+    destinations = {
+        kind: destination
+        for kind, destination in (
+            ("table", args.table_out),
+            ("json", args.json_out),
+            ("csv", args.csv_out),
+            ("sbom", args.sbom_out),
+            ("markdown", args.markdown_out),
+        )
+        if destination
+    }
+    if list(destinations.values()).count("-") > 1:
+        problems.append("Only one of --table-out, --json-out, --csv-out, --sbom-out and --markdown-out can be '-'")
+    if args.quiet:
+        if args.verbose:
+            problems.append("--quiet and --verbose are mutually exclusive")
+        if args.format == "ndjson" and "-" in destinations.values():
+            problems.append("--quiet --format ndjson streams the NDJSON records to stdout; no --*-out can be '-'")
+        elif args.format in _QUIET_DESTINATIONS and "-" not in destinations.values():
+            destinations[_QUIET_DESTINATIONS[args.format]] = "-"
+    sbom_format = args.sbom_format or (args.format if args.format in ("cyclonedx", "spdx") else "cyclonedx")
+    if not args.no_cache and args.cache_dir:
+        config_overrides["CACHE_DIR"] = args.cache_dir
+    if args.resolution_cache_ttl is not None:
+        try:
+            config_overrides["RESOLUTION_CACHE_TTL"] = parse_ttl(args.resolution_cache_ttl)
+        except ValueError as e:
+            problems.append(f"--resolution-cache-ttl: {e}")
+    config_overrides = config_overrides or None
+    if args.timings_output and not os.path.isdir(os.path.dirname(os.path.abspath(args.timings_output))):
+        problems.append(f"--timings-output directory not found: {os.path.dirname(args.timings_output)}")
+    if args.fail_on_unresolved_urls is not None and args.fail_on_unresolved_urls < 0:
+        problems.append("--fail-on-unresolved-urls must not be negative")
+    if args.fail_on_unresolved_urls_pct is not None and not 0 <= args.fail_on_unresolved_urls_pct <= 100:
+        problems.append("--fail-on-unresolved-urls-pct must be between 0 and 100")
+    url_gate = args.fail_on_unresolved_urls is not None or args.fail_on_unresolved_urls_pct is not None
+    if args.unresolved_urls_ignore and not url_gate:
+        problems.append("--unresolved-urls-ignore only applies to --fail-on-unresolved-urls(-pct)")
+    webhook_headers = []
+    if args.webhook_url:
+        problem = validate_webhook_url(args.webhook_url)
+        if problem:
+            problems.append(problem)
+        if args.offline:
+            problems.append("--webhook-url sends the results over the network, which --offline rules out")
+    for header in args.webhook_header or []:
+        try:
+            webhook_headers.append(parse_webhook_header(header))
+        except ValueError as e:
+            problems.append(str(e))
+    if (args.webhook_header or args.require_webhook) and not args.webhook_url:
+        problems.append("--webhook-header and --require-webhook only apply to --webhook-url")
+    baseline = None
+    if args.baseline:
+        try:
+            baseline = load_result_file(args.baseline)
+        except ValueError as e:
+            problems.append(str(e))
+    listed_files = None
+    if args.files_from:
+        try:
+            listed_files = _read_file_list(args.files_from)
+        except (OSError, UnicodeDecodeError) as e:
+            problems.append(f"Cannot read --files-from {args.files_from}: {e}")
+    if args.fail_on_added and not args.baseline:
+        problems.append("--fail-on-added only applies to --baseline")
+    if args.sbom_format and not args.sbom_out:
+        problems.append("--sbom-format only applies to --sbom-out (--format chooses the SBOM written to output/)")
+    if args.packages_only:
+        # Everything else needs the resolved URLs, evidence or graph the package list skips
+        conflicts = [
+            option
+            for option, given in (
+                ("--format " + args.format, args.format != "json"),
+                ("--table-out", args.table_out),
+                ("--csv-out", args.csv_out),
+                ("--sbom-out", args.sbom_out),
+                ("--markdown-out", args.markdown_out),
+                ("--flat", args.flat),
+                ("--only-unresolved", args.only_unresolved),
+                ("--validate-output", args.validate_output),
+                ("--visualize", args.visualize),
+                ("--baseline", args.baseline),
+                ("--fail-on-unresolved-urls(-pct)", url_gate),
             )
-            sys.exit(1)
+            if given
+        ]
+        if conflicts:
+            problems.append(f"--packages-only only writes the package list; drop {', '.join(conflicts)}")
+    problems.extend(_input_problems(args))
+    if problems:
+        for problem in problems:
+            logger.error(problem)
+        exit_with_error("invalid-arguments", "; ".join(problems))
+    if args.check_config:
+        configuration = _effective_configuration(args, config_overrides, destinations, sbom_format, config_file)
+        print(json.dumps(configuration, indent=2))
+        return
+
+    if args.clear_resolution_cache and args.cache_dir:
+        if clear_resolution_cache(args.cache_dir):
+            logger.info(f"Cleared resolution cache {cache_path(args.cache_dir)}")
 
     try:
         # Resolve minimal_outputs default: visualizations are opt-in
@@ -66,13 +1284,70 @@ def main():
         elif args.minimal_outputs:
             minimal_outputs = True
 
-        run_analysis(args.repo_path, args.output, args.verbose, minimal_outputs, args.languages, config_overrides)
+        results = run_analysis(
+            args.repo_paths[0] if len(args.repo_paths) == 1 else args.repo_paths,
+            args.output,
+            bool(args.verbose),
+            minimal_outputs,
+            args.languages,
+            config_overrides,
+            output_format=args.format,
+            csv_delimiter=csv_delimiter,
+            since=args.since,
+            branch=args.branch,
+            timings=args.timings,
+            timings_output=args.timings_output,
+            destinations=destinations,
+            sbom_format=sbom_format,
+            webhook_url=args.webhook_url,
+            webhook_headers=webhook_headers,
+            require_webhook=args.require_webhook,
+            baseline=baseline,
+            files=listed_files,
+            ndjson_stdout=args.quiet and args.format == "ndjson",
+        )
+    except KeyboardInterrupt:
+        _fail(logger, "interrupted", "Interrupted before any results were written")
+    except RepositoryCloneError as e:
+        _fail(logger, "network-failure", str(e))
     except RepositoryError as e:
-        logger.error(str(e))
-        sys.exit(1)
+        _fail(logger, "invalid-arguments", str(e))
+    except WebhookError as e:
+        _fail(logger, "webhook-failed", str(e))
+    except OutputValidationError as e:
+        _fail(logger, "invalid-output", str(e))
     except Exception as e:
-        logger.error(f"Unexpected error: {e}")
-        sys.exit(1)
+        _fail(logger, "analysis-failed", f"Unexpected error: {e}")
+
+    if results.get("partial"):
+        _fail(logger, "interrupted", "Interrupted; the results written are partial")
+    # A --since or --files-from run with no sources to analyze is a complete answer, not a failure
+    if not results.get("analyzer_details", {}).get("total_files") and not results.get("analysis_scope"):
+        _fail(logger, "no-analyzable-files", f"No analyzable source files found in {', '.join(args.repo_paths)}")
+    if args.fail_on_error and results.get("errors"):
+        _fail(logger, "analysis-errors", f"{len(results['errors'])} source files failed to read or parse")
+    offline = args.offline or bool((config_overrides or {}).get("OFFLINE", NetworkConfig.OFFLINE))
+    unresolved = [] if offline else _network_unresolved(results)
+    if unresolved:
+        _fail(
+            logger,
+            "network-failure",
+            f"{len(unresolved)} packages could not be resolved because of network errors: {', '.join(unresolved)}",
+        )
+    if url_gate:
+        unresolved, counted = _unresolved_urls(results, args.unresolved_urls_ignore or [])
+        percent = 100.0 * len(unresolved) / counted if counted else 0.0
+        over_count = args.fail_on_unresolved_urls is not None and len(unresolved) > args.fail_on_unresolved_urls
+        over_pct = args.fail_on_unresolved_urls_pct is not None and percent > args.fail_on_unresolved_urls_pct
+        if over_count or over_pct:
+            _fail(
+                logger,
+                "unresolved-urls",
+                f"{len(unresolved)} of {counted} packages ({percent:.1f}%) have no repository URL: "
+                f"{', '.join(unresolved)}",
+            )
+    if args.fail_on_added and results.get("baseline_diff", {}).get("added"):
+        _fail(logger, "added-dependencies", _added_message(results["baseline_diff"]))
 
 
 if __name__ == "__main__":
@@ -0,0 +1,166 @@
+"""
+Preflight connectivity check of the resolver backends (`gardener doctor`)
+
+Each backend gets one known query, sent the way its lookups are (see
+url_resolver.probe_url): the same URL validation, allow/deny registry lists,
+auth token, retries and GitHub rate-limit handling, so a check that passes
+means a run can reach the backend with the same configuration
+"""
+
+from gardener.common.defaults import NetworkConfig
+from gardener.package_metadata.url_resolver import go_proxy_chain, probe_url
+
+# Backend name -> (known query, whether a run needs it); "go-proxy" is probed once per GOPROXY proxy
+# and pkg.go.dev only backs up the Go proxy and go-import lookups
+BACKEND_QUERIES = {
+    "npm": ("https://registry.npmjs.org/left-pad", True),
+    "pypi": ("https://pypi.org/pypi/requests/json", True),
+    "crates.io": ("https://crates.io/api/v1/crates/serde", True),
+    "go-proxy": ("/github.com/pkg/errors/@latest", True),
+    "pkg.go.dev": ("https://pkg.go.dev/github.com/pkg/errors", False),
+    "github-api": ("https://api.github.com/rate_limit", True),
+}
+
+# Check statuses that fail a required backend
+FAILING_STATUSES = ("unreachable", "auth-failed")
+
+
+def _backend_urls(name, goproxy=None):
+    """
+    Return the URLs probed for a backend
+
+    Args:
+        name (str): Key of BACKEND_QUERIES
+        goproxy (str): Optional GOPROXY value used instead of the environment
+
+    Returns:
+        list: URLs; for "go-proxy" one per http(s) proxy of the chain, none for `direct` or `off`
+    """
+    query, _ = BACKEND_QUERIES[name]
+    if name != "go-proxy":
+        return [query]
+    return [proxy + query for proxy, _ in go_proxy_chain(goproxy) if proxy.startswith(("https://", "http://"))]
+
+
+def _classify(probe):
+    """
+    Turn a probe result into a check status and auth status
+
+    Returns:
+        tuple: (status, auth); status is "ok", "unreachable", "auth-failed", "rate-limited"
+            or "blocked", auth "token" (sent and accepted), "rejected" or "none" (no token for the host)
+    """
+    status = probe["status"]
+    if probe["reason"] == "registry-blocked":
+        return "blocked", "none"
+    if probe["reason"] == "github-rate-limited" or status == 429:
+        return "rate-limited", "token" if probe["token_sent"] else "none"
+    if status in (401, 403) and probe["token_sent"]:
+        return "auth-failed", "rejected"
+    if status is None or status >= 500 or probe["reason"]:
+        return "unreachable", "token" if probe["token_sent"] else "none"
+    return "ok", "token" if probe["token_sent"] else "none"
+
+
+def check_backends(backends=None, logger=None, goproxy=None):
+    """
+    Probe the resolver backends with one known query each
+
+    With NetworkConfig.OFFLINE nothing is sent and every check is "offline-skipped"
+
+    Args:
+        backends (list): Optional names of BACKEND_QUERIES to check; all by default
+        logger (Logger): Optional logger
+        goproxy (str): Optional GOPROXY value used instead of the environment
+
+    Returns:
+        list: {"backend", "url", "required", "status", "auth", ["http_status"], ["latency_seconds"],
+            ["attempts"], ["rate_limit"]} per probed URL, in BACKEND_QUERIES order; rate_limit is
+            {"limit", "remaining"} when the response reports one
+    """
+    checks = []
+    for name in backends or BACKEND_QUERIES:
+        required = BACKEND_QUERIES[name][1]
+        urls = _backend_urls(name, goproxy)
+        if not urls:
+            checks.append({"backend": name, "url": None, "required": False, "status": "not-configured", "auth": "none"})
+        for url in urls:
+            check = {"backend": name, "url": url, "required": required}
+            if NetworkConfig.OFFLINE:
+                check.update(status="offline-skipped", auth="none")
+                checks.append(check)
+                continue
+            probe = probe_url(url, logger)
+            status, auth = _classify(probe)
+            check.update(status=status, auth=auth)
+            if probe["status"] is not None:
+                check["http_status"] = probe["status"]
+            if probe["attempts"]:
+                check["latency_seconds"] = probe["seconds"]
+                check["attempts"] = probe["attempts"]
+            headers = probe["headers"]
+            if "x-ratelimit-limit" in headers and "x-ratelimit-remaining" in headers:
+                try:
+                    check["rate_limit"] = {
+                        "limit": int(headers["x-ratelimit-limit"]),
+                        "remaining": int(headers["x-ratelimit-remaining"]),
+                    }
+                except ValueError:
+                    pass
+            if logger:
+                logger.debug(
+                    f"Backend {name} ({url}): {status}",
+                    event="backend-check",
+                    backend=name,
+                    url=url,
+                    status=status,
+                    http_status=probe["status"],
+                )
+            checks.append(check)
+    return checks
+
+
+def failed_backends(checks):
+    """
+    Return the required backends whose check failed
+
+    Args:
+        checks (list): Result of check_backends
+
+    Returns:
+        list: Backend names in check order, each once
+    """
+    failed = []
+    for check in checks:
+        if check["required"] and check["status"] in FAILING_STATUSES and check["backend"] not in failed:
+            failed.append(check["backend"])
+    return failed
+
+
+def render_checks(checks):
+    """
+    Render backend checks as an aligned text table
+
+    Args:
+        checks (list): Result of check_backends
+
+    Returns:
+        str: One line per check, newline-terminated
+    """
+    rows = [("BACKEND", "STATUS", "HTTP", "LATENCY", "AUTH", "URL")]
+    for check in checks:
+        latency = check.get("latency_seconds")
+        status = check["status"] if check["required"] else f"{check['status']} (optional)"
+        rows.append(
+            (
+                check["backend"],
+                status,
+                str(check.get("http_status", "-")),
+                f"{latency:.3f}s" if latency is not None else "-",
+                check["auth"],
+                check["url"] or "-",
+            )
+        )
+    widths = [max(len(row[column]) for row in rows) for column in range(len(rows[0]) - 1)]
+    lines = ["  ".join(value.ljust(width) for value, width in zip(row, widths)) + "  " + row[-1] for row in rows]
+    return "".join(line.rstrip() + "\n" for line in lines)
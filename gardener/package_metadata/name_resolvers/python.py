@@ -9,6 +9,7 @@ import zipfile
 
 import requests
 
+from gardener.common.defaults import NetworkConfig
 from gardener.common.secure_file_ops import FileOperationError
 from gardener.package_metadata.name_resolvers.base import BaseResolver
 
@@ -168,15 +169,16 @@ class PythonResolver(BaseResolver):
         # First use the direct mapping approach
         import_names = self._process_distribution_name(package_name)
 
-        # Also use PyPI metadata
-        try:
-            pypi_names = resolve_python_import_names(package_name, version, logger)
-            if pypi_names:
-                import_names.extend(pypi_names)
-        except Exception as e:
-            # Log but continue with what we have
-            if logger:
-                logger.debug(f"PyPI fallback failed for {package_name}: {e}")
+        # Also use PyPI metadata, unless the network is off limits
+        if not NetworkConfig.OFFLINE:
+            try:
+                pypi_names = resolve_python_import_names(package_name, version, logger)
+                if pypi_names:
+                    import_names.extend(pypi_names)
+            except Exception as e:
+                # Log but continue with what we have
+                if logger:
+                    logger.debug(f"PyPI fallback failed for {package_name}: {e}")
 
         # Deduplicate the list while preserving order
         seen = set()
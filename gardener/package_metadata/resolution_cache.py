@@ -0,0 +1,321 @@
+"""
+Persistent repository URL resolution cache shared across runs
+
+Entries are keyed on (ecosystem, package, version) and record the resolved URL,
+how it was found and when; entries older than the TTL are re-resolved. Only
+successful resolutions are stored, so packages that failed to resolve are
+retried on every run. The file also keeps when Go module versions were
+published, which never changes and so never expires, and the default branch
+of GitHub repositories, which expires like a URL
+"""
+
+import json
+import os
+import time
+from datetime import datetime, timezone
+
+# Bump whenever the entry format changes so stale caches are discarded
+CACHE_SCHEMA_VERSION = 1
+
+CACHE_FILENAME = "resolution_cache.json"
+
+# Seconds per unit accepted by parse_ttl
+_TTL_UNITS = {"s": 1, "m": 60, "h": 3600, "d": 86400}
+
+
+def parse_ttl(value):
+    """
+    Parse a TTL such as "7d", "12h", "30m", "45s" or a bare number of seconds
+
+    Args:
+        value (str): TTL text
+
+    Returns:
+        int: Seconds
+
+    Raises:
+        ValueError: If the value is not a non-negative duration
+    """
+    text = str(value).strip().lower()
+    multiplier = _TTL_UNITS.get(text[-1:], None)
+    number = text[:-1] if multiplier else text
+    try:
+        seconds = int(float(number) * (multiplier or 1))
+    except (ValueError, OverflowError):
+        raise ValueError(f"invalid duration {value!r}; use e.g. 7d, 12h, 30m or a number of seconds")
+    if seconds < 0:
+        raise ValueError(f"duration must not be negative: {value!r}")
+    return seconds
+
+
+def _format_timestamp(epoch):
+    return datetime.fromtimestamp(epoch, timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
+
+
+def _parse_timestamp(text):
+    try:
+        return datetime.strptime(text, "%Y-%m-%dT%H:%M:%SZ").replace(tzinfo=timezone.utc).timestamp()
+    except (TypeError, ValueError):
+        return None
+
+
+def cache_path(cache_dir):
+    """
+    Return the resolution cache file inside a cache directory
+
+    Args:
+        cache_dir (str): Cache directory (see CacheConfig.CACHE_DIR)
+
+    Returns:
+        str: Absolute file path
+    """
+    return os.path.join(os.path.abspath(cache_dir), CACHE_FILENAME)
+
+
+def clear_resolution_cache(cache_dir):
+    """
+    Delete the resolution cache in a cache directory
+
+    Args:
+        cache_dir (str): Cache directory
+
+    Returns:
+        bool: True when a cache file was removed
+    """
+    try:
+        os.remove(cache_path(cache_dir))
+        return True
+    except FileNotFoundError:
+        return False
+
+
+class ResolutionCache:
+    """
+    On-disk store of resolved repository URLs with a time-to-live
+
+    Args:
+        cache_dir (str): Directory holding the cache file
+        ttl (int): Seconds an entry stays fresh; 0 makes every entry stale
+        logger (Logger|None): Optional logger
+        clock (callable): Returns the current time in epoch seconds (default: time.time)
+    """
+
+    def __init__(self, cache_dir, ttl, logger=None, clock=time.time):
+        self.path = cache_path(cache_dir)
+        self.ttl = ttl
+        self.logger = logger
+        self.clock = clock
+        self.hits = 0
+        self.updates = 0
+        self._version_times = {}
+        self._default_branches = {}
+        self._commits = {}
+        self._entries = self._load()
+
+    @staticmethod
+    def key(ecosystem, package, version):
+        """
+        Return the entry key of a package version
+
+        Args:
+            ecosystem (str): Package ecosystem
+            package (str): Package name (for replaced Go modules, the replacement path)
+            version (str): Version or version constraint; "" when unknown
+
+        Returns:
+            str
+        """
+        return f"{ecosystem}:{package}@{version or ''}"
+
+    def _load(self):
+        """
+        Read the entries (and version publish times) written by earlier runs
+
+        Returns:
+            dict: key -> entry
+        """
+        try:
+            with open(self.path, "r", encoding="utf-8") as handle:
+                data = json.load(handle)
+        except FileNotFoundError:
+            return {}
+        except (OSError, ValueError) as exc:
+            if self.logger:
+                self.logger.warning(f"Ignoring unreadable resolution cache {self.path}: {exc}")
+            return {}
+        if not isinstance(data, dict) or data.get("schema") != CACHE_SCHEMA_VERSION:
+            if self.logger:
+                self.logger.debug("Resolution cache schema changed; starting with an empty cache")
+            return {}
+        self._version_times = data.get("version_times", {})
+        self._default_branches = data.get("default_branches", {})
+        self._commits = data.get("commits", {})
+        return data.get("entries", {})
+
+    def lookup(self, ecosystem, package, version):
+        """
+        Return the entry for a package version, and whether it can be used
+
+        Args:
+            ecosystem (str): Package ecosystem
+            package (str): Package name
+            version (str): Version or constraint
+
+        Returns:
+            tuple: (entry or None, status) where status is "hit", "expired" or "miss"; entry is only
+                returned for a hit and holds "url", "source", "checked_at" and optionally
+                "repository_subpath" and "code_host"
+        """
+        entry = self._entries.get(self.key(ecosystem, package, version))
+        if not entry or not entry.get("url"):
+            return None, "miss"
+        checked_at = _parse_timestamp(entry.get("checked_at"))
+        if checked_at is None or self.clock() - checked_at >= self.ttl:
+            return None, "expired"
+        self.hits += 1
+        return entry, "hit"
+
+    def store(self, ecosystem, package, version, url, source, repository_subpath=None, code_host=None):
+        """
+        Record a freshly resolved URL
+
+        Args:
+            ecosystem (str): Package ecosystem
+            package (str): Package name
+            version (str): Version or constraint
+            url (str): Resolved repository URL
+            source (str): Receipt "source" of the URL
+            repository_subpath (str): Optional in-repository directory of the package
+            code_host (str): Optional forge the URL's go-get page revealed (see resolve_go_vanity_import)
+        """
+        entry = {
+            "ecosystem": ecosystem,
+            "package": package,
+            "version": version or "",
+            "url": url,
+            "source": source,
+            "checked_at": _format_timestamp(self.clock()),
+        }
+        if repository_subpath:
+            entry["repository_subpath"] = repository_subpath
+        if code_host:
+            entry["code_host"] = code_host
+        self._entries[self.key(ecosystem, package, version)] = entry
+        self.updates += 1
+
+    def lookup_version_time(self, ecosystem, package, version):
+        """
+        Return when a package version was published, as recorded by an earlier lookup
+
+        Args:
+            ecosystem (str): Package ecosystem
+            package (str): Package name
+            version (str): Exact version
+
+        Returns:
+            str|None: RFC 3339 timestamp
+        """
+        published_at = self._version_times.get(self.key(ecosystem, package, version))
+        if published_at:
+            self.hits += 1
+        return published_at
+
+    def store_version_time(self, ecosystem, package, version, published_at):
+        """
+        Record when a package version was published
+
+        Args:
+            ecosystem (str): Package ecosystem
+            package (str): Package name
+            version (str): Exact version
+            published_at (str): RFC 3339 timestamp reported by the registry
+        """
+        self._version_times[self.key(ecosystem, package, version)] = published_at
+        self.updates += 1
+
+    def lookup_default_branch(self, repo_url):
+        """
+        Return the default branch of a repository, as recorded by an earlier lookup within the TTL
+
+        Args:
+            repo_url (str): Canonical repository URL
+
+        Returns:
+            str|None: Branch name
+        """
+        entry = self._default_branches.get(repo_url) or {}
+        checked_at = _parse_timestamp(entry.get("checked_at"))
+        if not entry.get("branch") or checked_at is None or self.clock() - checked_at >= self.ttl:
+            return None
+        self.hits += 1
+        return entry["branch"]
+
+    def store_default_branch(self, repo_url, branch):
+        """
+        Record the default branch of a repository
+
+        Args:
+            repo_url (str): Canonical repository URL
+            branch (str): Branch name reported by the forge
+        """
+        self._default_branches[repo_url] = {"branch": branch, "checked_at": _format_timestamp(self.clock())}
+        self.updates += 1
+
+    def lookup_commit(self, repo_url, revision):
+        """
+        Return a commit an earlier lookup found, by the SHA prefix it was looked up with
+
+        Args:
+            repo_url (str): Canonical repository URL
+            revision (str): SHA prefix, e.g. of a Go pseudo-version
+
+        Returns:
+            dict|None: {"sha", "date"}
+        """
+        commit = self._commits.get(f"{repo_url}@{revision}")
+        if commit:
+            self.hits += 1
+        return commit
+
+    def store_commit(self, repo_url, revision, commit):
+        """
+        Record a commit found in a repository; commits do not change, so the entry does not expire
+
+        Args:
+            repo_url (str): Canonical repository URL
+            revision (str): SHA prefix it was looked up with
+            commit (dict): {"sha", "date"} reported by the forge
+        """
+        self._commits[f"{repo_url}@{revision}"] = commit
+        self.updates += 1
+
+    def save(self):
+        """
+        Write every entry back, including those of packages this run did not see
+
+        Returns:
+            bool: True when the file was written (or nothing changed)
+        """
+        if not self.updates:
+            return True
+        try:
+            os.makedirs(os.path.dirname(self.path), exist_ok=True)
+            tmp_path = f"{self.path}.tmp"
+            with open(tmp_path, "w", encoding="utf-8") as handle:
+                json.dump(
+                    {
+                        "schema": CACHE_SCHEMA_VERSION,
+                        "entries": self._entries,
+                        "version_times": self._version_times,
+                        "default_branches": self._default_branches,
+                        "commits": self._commits,
+                    },
+                    handle,
+                    sort_keys=True,
+                )
+            os.replace(tmp_path, self.path)
+            return True
+        except OSError as exc:
+            if self.logger:
+                self.logger.warning(f"Could not write resolution cache {self.path}: {exc}")
+            return False
@@ -2,11 +2,25 @@
 Package resolution methods
 """
 
+import contextlib
+import email.utils
+import fnmatch
+import html
+import http.client
 import json
+import os
+import random
 import re
+import threading
 import time
 import urllib.error
+import urllib.parse
 import urllib.request
+from concurrent.futures import ThreadPoolExecutor
+
+from gardener.common.cancellation import is_cancelled
+from gardener.common.defaults import NetworkConfig
+from gardener.package_metadata.url_rules import configured_url_rules, match_url_rules
 
 try:
     from gardener.common.input_validation import InputValidator, ValidationError
@@ -18,9 +32,65 @@ except ImportError:
 
 
 USER_AGENT = "Gardener/0.1 (https://drips.network)"
-REQUEST_TIMEOUT = 10  # seconds
-RETRY_COUNT = 3
-RETRY_DELAY = 1  # seconds (initial delay)
+
+# GOPROXY value used when the environment does not set one (matches the go command)
+GO_PROXY_DEFAULT = "https://proxy.golang.org,direct"
+
+# Environment variables whose comma-separated globs mark Go modules as private
+GO_PRIVATE_ENV_VARS = ("GOPRIVATE", "GONOPROXY", "GONOSUMDB")
+
+# Hosts that receive $GITHUB_TOKEN, or NetworkConfig.AUTH_TOKEN when no NetworkConfig.AUTH_HOSTS are configured
+GITHUB_AUTH_HOSTS = ("github.com", "api.github.com")
+
+# Confidence (0.0-1.0) in a resolved repository URL, by how it was obtained
+# Declared by the project itself: .gitmodules, a Go import path (or go.mod replace target) on a code host
+URL_CONFIDENCE_DECLARED = 1.0
+# Repository the Go module proxy fetched the exact module version from (the Origin of its `.info`)
+URL_CONFIDENCE_PROXY_ORIGIN = 0.95
+# Repository field of registry metadata, or a curated package -> repository mapping
+URL_CONFIDENCE_REGISTRY = 0.9
+# Go vanity import served by the module's own host (go-import meta tag, gopkg.in)
+URL_CONFIDENCE_VANITY = 0.85
+# Homepage or issue-tracker links in registry metadata that happen to point at a repository
+URL_CONFIDENCE_REGISTRY_LINK = 0.75
+# Repository link scraped from the pkg.go.dev page
+URL_CONFIDENCE_PKGGODEV = 0.7
+# Guessed github.com/<org>/<repo> from names, descriptions or documentation hosts
+URL_CONFIDENCE_HEURISTIC = 0.4
+
+# Resolution receipt "source" -> confidence
+URL_SOURCE_CONFIDENCE = {
+    "gitmodules": URL_CONFIDENCE_DECLARED,
+    "import-path": URL_CONFIDENCE_DECLARED,
+    "gomod-replace": URL_CONFIDENCE_DECLARED,
+    "action-reference": URL_CONFIDENCE_DECLARED,
+    "source-hint": URL_CONFIDENCE_DECLARED,
+    "custom-rule": URL_CONFIDENCE_DECLARED,
+    "go-proxy-origin": URL_CONFIDENCE_PROXY_ORIGIN,
+    "registry": URL_CONFIDENCE_REGISTRY,
+    "known-package": URL_CONFIDENCE_REGISTRY,
+    "go-import-meta": URL_CONFIDENCE_VANITY,
+    "gopkg-in": URL_CONFIDENCE_VANITY,
+    "registry-link": URL_CONFIDENCE_REGISTRY_LINK,
+    "pkg.go.dev": URL_CONFIDENCE_PKGGODEV,
+    "inferred": URL_CONFIDENCE_HEURISTIC,
+}
+
+# How the URL of each Go receipt source is obtained: "local" ones follow from the module path
+# (or the replace target) alone, "remote" ones are what a vanity host or pkg.go.dev answered
+GO_SOURCE_DERIVATION = {
+    "import-path": "local",
+    "gomod-replace": "local",
+    "gopkg-in": "local",
+    "custom-rule": "local",
+    "gitmodules": "local",
+    "go-proxy-origin": "remote",
+    "go-import-meta": "remote",
+    "pkg.go.dev": "remote",
+}
+
+# Ecosystems resolve_package_urls has a resolver backend for
+RESOLVER_ECOSYSTEMS = ("cargo", "github-actions", "go", "npm", "pypi", "solidity")
 
 # Allowed registry domains
 ALLOWED_REGISTRY_DOMAINS = {
@@ -29,6 +99,7 @@ ALLOWED_REGISTRY_DOMAINS = {
     "files.pythonhosted.org",
     "crates.io",
     "proxy.golang.org",
+    "pkg.go.dev",
     "api.github.com",
     "raw.githubusercontent.com",
 }
@@ -38,6 +109,42 @@ ALLOWED_REGISTRY_DOMAINS = {
 # real network I/O. The function signature is: fn(url: str) -> bytes | str | None
 _REQUEST_FN = None
 
+# Per-thread counts of HTTP attempts and of requests that exhausted their retries, read back into resolution receipts,
+# and the headers of the thread's last response
+_ATTEMPTS = threading.local()
+
+# Per-thread set of canonical URLs that normalize_repo_url had to rewrite, read back as receipt["normalized"]
+_NORMALIZED = threading.local()
+
+# Requests sent, connections opened for them and seconds spent on them, summed over all threads, read back by --timings
+_NETWORK_TIME = {"requests": 0, "connections": 0, "seconds": 0.0}
+
+# Per-thread keep-alive connections by host, for NetworkConfig.NPM_BATCHED_RESOLUTION
+_CONNECTIONS = threading.local()
+
+# Thread -> its _CONNECTIONS.by_host, so those of exited worker threads can be closed (see close_exited_connections)
+_CONNECTION_POOLS = {}
+_CONNECTION_POOLS_LOCK = threading.Lock()
+
+# Hosts whose requests go over kept-alive connections when NetworkConfig.NPM_BATCHED_RESOLUTION is set
+_KEEP_ALIVE_HOSTS = ("registry.npmjs.org",)
+_NETWORK_TIME_LOCK = threading.Lock()
+
+# Shared by every resolver backend: bounds the HTTP requests in flight to NetworkConfig.RESOLVER_CONCURRENCY
+_REQUEST_SLOTS = None
+_REQUEST_SLOTS_SIZE = 0
+_REQUEST_SLOTS_LOCK = threading.Lock()
+
+# Monotonic time by which all resolution requests must be done (see resolution_deadline), shared by all threads
+_RESOLUTION_DEADLINE = {"at": None}
+
+# Until when (epoch seconds) GitHub asked every GitHub API request to hold off, shared by all threads
+_GITHUB_BLOCKED_UNTIL = {"at": 0.0}
+_GITHUB_BLOCKED_LOCK = threading.Lock()
+
+# First wait for a GitHub secondary rate limit that names no time, doubled for each further one
+GITHUB_SECONDARY_LIMIT_DELAY = 60.0
+
 
 def set_request_fn(fn):
     """
@@ -50,18 +157,166 @@ def set_request_fn(fn):
     _REQUEST_FN = fn
 
 
+def get_request_fn():
+    """
+    Return the request function installed with set_request_fn, or None
+
+    Returns:
+        callable|None
+    """
+    return _REQUEST_FN
+
+
+def resolver_concurrency():
+    """
+    Return the maximum number of concurrent outbound resolution requests
+
+    Returns:
+        int: NetworkConfig.RESOLVER_CONCURRENCY, at least 1
+    """
+    return max(1, int(NetworkConfig.RESOLVER_CONCURRENCY or 1))
+
+
+def _request_slots():
+    """
+    Return the semaphore bounding in-flight HTTP requests, resized when the limit changed
+
+    Returns:
+        threading.BoundedSemaphore
+    """
+    global _REQUEST_SLOTS, _REQUEST_SLOTS_SIZE
+    size = resolver_concurrency()
+    with _REQUEST_SLOTS_LOCK:
+        if _REQUEST_SLOTS is None or _REQUEST_SLOTS_SIZE != size:
+            _REQUEST_SLOTS = threading.BoundedSemaphore(size)
+            _REQUEST_SLOTS_SIZE = size
+        return _REQUEST_SLOTS
+
+
+@contextlib.contextmanager
+def resolution_deadline(seconds):
+    """
+    Give every resolution request sent inside the block a shared wall-clock budget
+
+    Once it is spent, requests are no longer sent (see deadline_exceeded) and a
+    request in flight is given at most the time left as its timeout, so a slow or
+    hanging endpoint cannot hold the analysis past the deadline
+
+    Args:
+        seconds (float): Budget in seconds; 0 or None leaves the requests unbounded
+    """
+    previous = _RESOLUTION_DEADLINE["at"]
+    _RESOLUTION_DEADLINE["at"] = time.monotonic() + seconds if seconds else None
+    try:
+        yield
+    finally:
+        _RESOLUTION_DEADLINE["at"] = previous
+
+
+def deadline_remaining():
+    """
+    Return the seconds left before the resolution deadline
+
+    Returns:
+        float|None: Time left, 0.0 once it passed, or None without a deadline
+    """
+    at = _RESOLUTION_DEADLINE["at"]
+    return None if at is None else max(0.0, at - time.monotonic())
+
+
+def deadline_exceeded():
+    """
+    Return True once the resolution deadline of the running analysis has passed
+
+    Returns:
+        bool
+    """
+    return deadline_remaining() == 0.0
+
+
+def request_timeout():
+    """
+    Return the timeout of a request sent now
+
+    Returns:
+        float: NetworkConfig.HTTP_TIMEOUT, cut to the time left before the resolution deadline
+    """
+    remaining = deadline_remaining()
+    timeout = NetworkConfig.HTTP_TIMEOUT
+    return timeout if remaining is None else max(0.001, min(timeout, remaining))
+
+
+def _sleep_until_deadline(seconds):
+    """
+    Sleep for a retry or rate-limit wait, waking at the resolution deadline if it comes first
+
+    Returns:
+        bool: False when the deadline cut the wait short
+    """
+    remaining = deadline_remaining()
+    if remaining is not None and remaining < seconds:
+        time.sleep(remaining)
+        return False
+    time.sleep(seconds)
+    return True
+
+
+def _deadline_hit(url, logger=None):
+    """
+    Return True, counting the request on this thread (see request_deadline_hits), once the resolution
+    deadline has passed
+
+    Args:
+        url (str): Request URL
+        logger: Optional logger
+
+    Returns:
+        bool
+    """
+    if not deadline_exceeded():
+        return False
+    _ATTEMPTS.deadline = request_deadline_hits() + 1
+    logger and logger.trace(
+        f"Not requesting {url}: resolution deadline exceeded", event="deadline-exceeded", url=url
+    )
+    return True
+
+
 # Module-internal regex patterns for repository URL parsing
 # Underscore-prefixed to indicate non-public API usage
 _RE_GH_OWNER_REPO_COLON_OR_SLASH = re.compile(r"github\.com[:/]([^/\s]+/[^/\s]+?)(?:\.git)?(?:\s|$)")
 _RE_GH_OWNER_REPO_SLASH = re.compile(r"github\.com/([^/]+/[^/]+)")
 _RE_GH_PAGES = re.compile(r"https?://([^/]+)\.github\.io/([^/]+)")
 _RE_GL_PAGES = re.compile(r"https?://([^/]+)\.gitlab\.io/([^/]+)")
-_RE_GO_IMPORT_META = re.compile(
-    r'<meta\s+name=["\']go-import["\']\s+content=["\']([^ ]+)\s+(git|hg|svn|bzr)\s+([^"\']+)["\']', re.IGNORECASE
+_RE_HTML_META = re.compile(r"<meta\b([^>]*)>", re.IGNORECASE)
+_RE_HTML_ATTRIBUTE = re.compile(r"""([\w-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))""")
+_RE_GO_MAJOR_VERSION_SUFFIX = re.compile(r"/v(?:[2-9]|[1-9][0-9]+)$")
+_RE_GOPKG_IN = re.compile(
+    r"^gopkg\.in/(?:([A-Za-z0-9][-A-Za-z0-9_]*)/)?([A-Za-z0-9][-A-Za-z0-9_.]*?)\.(v\d+)(?:-unstable)?(?:/|$)"
+)
+_RE_GO_PROXY_ENTRY = re.compile(r"([^,|]+)([,|]?)")
+_RE_GO_SEMVER = re.compile(r"^v(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$")
+_RE_PKGGODEV_REPO_LINKS = (
+    re.compile(r'class="UnitMeta-repo"[^>]*>\s*<a\s[^>]*href="([^"]+)"', re.IGNORECASE),
+    re.compile(r'>\s*Repository\s*</h2>\s*(?:<[^a/][^>]*>\s*)*<a\s[^>]*href="([^"]+)"', re.IGNORECASE),
 )
 _RE_GH_CANONICAL = re.compile(r"(https?://(?:www\.)?github\.com/[^/]+/[^/]+)")
+_RE_SSH_URL = re.compile(r"^ssh://(?:[^@/]+@)?([^/:]+)(?::\d+)?/(.+)$", re.IGNORECASE)
+_RE_SCP_LIKE = re.compile(r"^[\w.-]+@([\w.-]+):(?!//)/?(.+)$")
 _RE_OWNER_REPO_SHORTHAND = re.compile(r"^[a-zA-Z0-9_-]+/[a-zA-Z0-9_.-]+$")
 
+# Code hosting software whose repository URLs split into owner and repository name
+CODE_HOST_KINDS = ("github", "gitlab", "bitbucket", "gitea")
+
+# Hosts running one of CODE_HOST_KINDS that their name does not give away
+_KNOWN_CODE_HOSTS = {"codeberg.org": "gitea"}
+
+# Version control systems of go-import meta tags; "mod" roots name a module proxy, not a repository
+_GO_IMPORT_VCS = ("git", "hg", "svn", "bzr", "fossil")
+
+# Directory-template markers of go-source meta tags, identifying the forge that served them
+_GO_SOURCE_CODE_HOSTS = (("/-/tree/", "gitlab"), ("/src/branch/", "gitea"), ("/src/commit/", "gitea"))
+
 
 def _validate_or_none(url, logger=None):
     """
@@ -86,908 +341,2851 @@ def _validate_or_none(url, logger=None):
         return None
 
 
-def _request_once(url, logger=None):
+def is_retryable(status):
     """
-    Perform a single HTTP GET with headers and decode JSON if status == 200
+    Return True for responses worth retrying: connection errors, 429 and 5xx
 
     Args:
-        url (str): Validated URL
-        logger: Optional logger
+        status (int|None): HTTP status, or None when no response was received
 
     Returns:
-        tuple: (status_code_or_None, json_dict_or_None, exception_or_None)
+        bool
     """
-    # If a request hook is provided, use it to get raw content (JSON expected here)
-    if _REQUEST_FN is not None:
-        try:
-            raw = _REQUEST_FN(url)
-            if raw is None:
-                return 404, None, None
-            if isinstance(raw, bytes):
-                text = raw.decode("utf-8", errors="ignore")
-            else:
-                text = str(raw)
-            return 200, json.loads(text), None
-        except Exception as e:
-            return None, None, e
+    return status is None or status == 429 or status >= 500
 
-    req = urllib.request.Request(url, headers={"User-Agent": USER_AGENT})
-    with urllib.request.urlopen(req, timeout=REQUEST_TIMEOUT) as response:
-        if response.status == 200:
-            return 200, json.loads(response.read().decode("utf-8")), None
-        if response.status == 404:
-            return 404, None, None
-        http_err = urllib.error.HTTPError(url, response.status, response.reason, response.headers, None)
-        return response.status, None, http_err
 
+def request_attempts():
+    """
+    Return the number of HTTP attempts made on this thread since the last reset
 
-def _make_request(url, logger=None):
+    Returns:
+        int
     """
-    Make an HTTP GET request with retries, security validation, and proper headers
+    return getattr(_ATTEMPTS, "count", 0)
 
-    Validates URLs against allowed registry domains and implements exponential
-    backoff retry logic with comprehensive error handling
 
-    Args:
-        url (str): URL to request (must be from allowed domains)
-        logger (Logger): Optional logger instance
+def request_blocks():
+    """
+    Return the number of requests on this thread since the last reset that were never
+    sent because registry_allowed rejected their host
 
     Returns:
-        JSON response data as dict, or None if request fails or returns 404
+        int
     """
-    # Validate URL for security
-    validated = _validate_or_none(url, logger)
-    if validated is None:
-        return None
-    url = validated
+    return getattr(_ATTEMPTS, "blocked", 0)
 
-    last_exception = None
-    delay = RETRY_DELAY
-    for attempt in range(RETRY_COUNT + 1):
-        try:
-            status, data, single_error = _request_once(url, logger)
-            if status == 200:
-                return data
-            if status == 404:
-                logger and logger.debug(f"Package not found (404): {url}")
-                return None
-            if status is not None:
-                last_exception = single_error
-                logger and logger.warning(f"HTTP error {status} for {url} (attempt {attempt + 1}/{RETRY_COUNT + 1})")
 
-        except urllib.error.HTTPError as e:
-            last_exception = e
-            if e.code == 404:
-                logger and logger.debug(f"Package not found (404): {url}")
-                return None  # Explicitly return None on 404
-            logger and logger.warning(
-                f"HTTP error {e.code} for {url} " f"(attempt {attempt + 1}/{RETRY_COUNT + 1}): {e.reason}"
-            )
-        except Exception as e:
-            last_exception = e
-            logger and logger.warning(f"Error fetching {url} (attempt {attempt + 1}/{RETRY_COUNT + 1}): {e}")
+def request_failures():
+    """
+    Return the number of requests on this thread since the last reset that got no
+    usable response (connection error, 429 or 5xx) after all retries
 
-        if attempt < RETRY_COUNT:
-            logger and logger.debug(f"Retrying in {delay}s...")
-            time.sleep(delay)
-            delay *= 2  # Exponential backoff
+    Returns:
+        int
+    """
+    return getattr(_ATTEMPTS, "failures", 0)
 
-    logger and logger.error(f"Failed to fetch {url} after {RETRY_COUNT + 1} attempts. Last error: {last_exception}")
-    return None
 
+def request_deadline_hits():
+    """
+    Return the number of requests on this thread since the last reset that the resolution
+    deadline kept from being sent or cut short
 
-def _strip_fragment(url_str):
+    Returns:
+        int
     """
-    Remove fragment part after '#' from repo URL string
+    return getattr(_ATTEMPTS, "deadline", 0)
+
+
+def reset_request_attempts():
+    """
+    Start counting HTTP attempts and failures afresh on this thread
+    """
+    _ATTEMPTS.count = 0
+    _ATTEMPTS.failures = 0
+    _ATTEMPTS.blocked = 0
+    _ATTEMPTS.deadline = 0
 
-    Args:
-        url_str (str): Repository URL string
+
+def response_headers():
+    """
+    Return the headers of the last HTTP response received on this thread
 
     Returns:
-        URL string without fragment
+        dict: Lowercase header name -> value; empty when no response (or a request hook's plain body) was received
     """
-    if not isinstance(url_str, str):
-        return url_str
-    if "#" in url_str:
-        return url_str.split("#")[0]
-    return url_str
+    return getattr(_ATTEMPTS, "headers", {})
 
 
-def _normalize_git_prefixes(url_str):
+def _record_response_headers(headers):
+    """
+    Keep a response's headers as this thread's last (see response_headers)
     """
-    Convert 'git+' and 'git://' prefixes to standard https forms
+    _ATTEMPTS.headers = {str(name).lower(): value for name, value in (headers or {}).items()}
 
-    Args:
-        url_str (str): Repository URL string
+
+def network_time():
+    """
+    Return the HTTP requests sent so far by all threads and the time spent on them
+
+    Time is counted from sending a request to receiving its response and summed
+    over threads, so concurrent requests can add up to more than the wall-clock time
 
     Returns:
-        Normalized URL string
+        dict: {"requests": int, "connections": int, "seconds": float}
     """
-    if not isinstance(url_str, str):
-        return url_str
-    u = url_str
-    if u.startswith("git+"):
-        u = u[4:]
-    if u.startswith("git://"):
-        u = "https://" + u[6:]
-    return u
+    with _NETWORK_TIME_LOCK:
+        return dict(_NETWORK_TIME)
 
 
-def _extract_github_owner_repo(url_str):
+def _count_connection():
+    with _NETWORK_TIME_LOCK:
+        _NETWORK_TIME["connections"] += 1
+
+
+def registry_allowed(url):
     """
-    Trim GitHub URL to 'https://github.com/<owner>/<repo>' if present
+    Tell whether a resolution request may be sent to the host of a URL
+
+    Hosts matching a NetworkConfig.DENY_REGISTRIES glob are blocked; when
+    NetworkConfig.ALLOW_REGISTRIES is set, so is every host matching none of its
+    globs. Globs are matched case-insensitively against the whole host name, so
+    `*.example.com` covers the subdomains of example.com but not example.com itself
 
     Args:
-        url_str (str): Repository URL string
+        url (str): Request URL
 
     Returns:
-        Canonical GitHub owner/repo URL if matched, else original string
+        bool
     """
-    if not isinstance(url_str, str):
-        return url_str
-    if "github.com" in url_str:
-        match = _RE_GH_CANONICAL.match(url_str)
-        if match:
-            return match.group(1)
-    return url_str
+    host = (urllib.parse.urlsplit(url).hostname or "").lower()
+    if any(fnmatch.fnmatchcase(host, pattern.lower()) for pattern in NetworkConfig.DENY_REGISTRIES):
+        return False
+    if NetworkConfig.ALLOW_REGISTRIES:
+        return any(fnmatch.fnmatchcase(host, pattern.lower()) for pattern in NetworkConfig.ALLOW_REGISTRIES)
+    return True
 
 
-def _strip_dot_git(url_str):
+def _request_blocked(url, logger=None):
     """
-    Remove trailing '.git' suffix
+    Return True, counting the request on this thread (see request_blocks), when registry_allowed
+    rejects the URL's host
 
     Args:
-        url_str (str): Repository URL string
+        url (str): Request URL
+        logger: Optional logger
 
     Returns:
-        URL without .git suffix
+        bool
     """
-    if not isinstance(url_str, str):
-        return url_str
-    if url_str.endswith(".git"):
-        return url_str[:-4]
-    return url_str
+    if registry_allowed(url):
+        return False
+    _ATTEMPTS.blocked = request_blocks() + 1
+    logger and logger.debug(
+        f"Not requesting {url}: host blocked by the registry allow/deny lists",
+        event="request-blocked",
+        url=url,
+    )
+    return True
 
 
-def _assume_github_from_owner_repo_shorthand(url_str):
+def _go_private_patterns(goprivate=None):
     """
-    If string looks like 'owner/repo', return 'https://github.com/owner/repo'
-    Else return None
+    Collect the private-module globs from GOPRIVATE, GONOPROXY and GONOSUMDB
 
     Args:
-        url_str (str): Owner/repo shorthand or other string
+        goprivate (str): Optional comma-separated globs used instead of the environment
 
     Returns:
-        Canonical GitHub URL or None
+        list: Globs in first-seen order
     """
-    if not isinstance(url_str, str):
-        return None
-    if _RE_OWNER_REPO_SHORTHAND.match(url_str):
-        return f"https://github.com/{url_str}"
-    return None
+    values = [goprivate] if goprivate is not None else [os.environ.get(name, "") for name in GO_PRIVATE_ENV_VARS]
+    patterns = []
+    for value in values:
+        for pattern in value.split(","):
+            pattern = pattern.strip().strip("/")
+            if pattern and pattern not in patterns:
+                patterns.append(pattern)
+    return patterns
 
 
-def _clean_repo_url(repo_url):
+def is_go_private_module(module_path, goprivate=None):
     """
-    Clean and normalize repository URLs for consistent formatting
+    Tell whether a Go module path matches a private-module glob
 
-    Removes common prefixes, suffixes, and fragments while extracting
-    the canonical repository URL. Handles GitHub URL normalization
-    and various git protocol conversions
+    As with the go command, a glob matches when it matches the same number of
+    leading path elements, so `*.corp.example.com` covers
+    `git.corp.example.com/team/lib` and `github.com/acme` covers every module
+    under that organization
 
     Args:
-        repo_url (str): Raw repository URL string
+        module_path (str): Go module or import path
+        goprivate (str): Optional comma-separated globs used instead of the environment
 
     Returns:
-        Cleaned repository URL string, or None if URL is invalid
+        bool
+    """
+    elements = module_path.split("/")
+    for pattern in _go_private_patterns(goprivate):
+        parts = pattern.split("/")
+        if len(parts) <= len(elements) and all(
+            fnmatch.fnmatchcase(element, part) for element, part in zip(elements, parts)
+        ):
+            return True
+    return False
+
+
+def auth_token_for(url):
     """
-    if not repo_url or not isinstance(repo_url, str):
+    Return the bearer token to send with a request, if its host is configured for one
+
+    NetworkConfig.AUTH_TOKEN goes only to NetworkConfig.AUTH_HOSTS (and their
+    subdomains) or, when none are configured, to github.com in place of
+    $GITHUB_TOKEN. $GITHUB_TOKEN itself only ever goes to github.com. Registries,
+    public proxies and GOPRIVATE hosts that are not listed never receive a token
+
+    Args:
+        url (str): Request URL
+
+    Returns:
+        str or None
+    """
+    host = (urllib.parse.urlsplit(url).hostname or "").lower()
+    if not host:
         return None
+    if NetworkConfig.AUTH_HOSTS:
+        auth_hosts = [auth_host.lower().strip(".") for auth_host in NetworkConfig.AUTH_HOSTS]
+        if NetworkConfig.AUTH_TOKEN and any(host == h or host.endswith("." + h) for h in auth_hosts):
+            return NetworkConfig.AUTH_TOKEN
+        github_token = os.environ.get("GITHUB_TOKEN", "")
+    else:
+        github_token = NetworkConfig.AUTH_TOKEN or os.environ.get("GITHUB_TOKEN", "")
+    if host in GITHUB_AUTH_HOSTS and github_token:
+        return github_token
+    return None
 
-    u = _strip_fragment(repo_url)
-    u = _normalize_git_prefixes(u)
-    u = _extract_github_owner_repo(u)
-    u = _strip_dot_git(u)
 
-    if u.startswith("http://") or u.startswith("https://"):
-        return u
+class _AuthorizationRedirectHandler(urllib.request.HTTPRedirectHandler):
+    """
+    Follow redirects as urllib does, dropping the Authorization header on one to another host
+    """
 
-    assumed = _assume_github_from_owner_repo_shorthand(u)
-    if assumed:
-        return assumed
+    def redirect_request(self, req, fp, code, msg, headers, newurl):
+        redirected = super().redirect_request(req, fp, code, msg, headers, newurl)
+        if redirected is not None:
+            old_host = (urllib.parse.urlsplit(req.full_url).hostname or "").lower()
+            if (urllib.parse.urlsplit(newurl).hostname or "").lower() != old_host:
+                redirected.remove_header("Authorization")
+        return redirected
 
-    return None
+
+def _urlopen(request, timeout=None):
+    """
+    Open a request as urllib.request.urlopen does, without forwarding its token across hosts
+    """
+    return urllib.request.build_opener(_AuthorizationRedirectHandler).open(request, timeout=timeout)
 
 
-# Main resolution logic:
+def _http_get(url, logger=None):
+    """
+    Perform a single HTTP GET and return its status and decoded body
+
+    Waits for one of the resolver_concurrency() request slots shared by all
+    resolver threads and backends. A slot is held per attempt, so a request
+    waiting out a retry backoff does not hold one. Neither the wait nor the
+    request outlasts the resolution deadline (see resolution_deadline)
+
+    Args:
+        url (str): Validated URL
+        logger: Optional logger
+
+    Returns:
+        tuple: (status_code_or_None, text_or_None); None status means no response was received
+    """
+    if _deadline_hit(url, logger):
+        return None, None
+    slots = _request_slots()
+    remaining = deadline_remaining()
+    if not slots.acquire(timeout=remaining):
+        _deadline_hit(url, logger)
+        return None, None
+    _ATTEMPTS.count = request_attempts() + 1
+    started = time.perf_counter()
+    try:
+        result = _http_get_unbounded(url, logger)
+    finally:
+        slots.release()
+        elapsed = time.perf_counter() - started
+        with _NETWORK_TIME_LOCK:
+            _NETWORK_TIME["requests"] += 1
+            _NETWORK_TIME["seconds"] += elapsed
+    if result[0] is None:
+        # A request that timed out at the deadline
+        _deadline_hit(url, logger)
+    return result
 
 
-def resolve_package_urls(packages_dict, logger=None, cache=None):
+def retry_delay(attempt):
     """
-    Resolve package names to repository URLs for all ecosystems
+    Return the seconds to wait before retrying a failed attempt
 
     Args:
-        packages_dict (dict): Dictionary of packages to resolve
-        logger (Logger): Optional logger instance
-        cache (dict): Optional pre-populated dictionary for URL caching
+        attempt (int): 0-based number of the attempt that failed
 
     Returns:
-        Dictionary containing resolved package URLs
+        float: A random time between half and all of RETRY_BASE_DELAY * 2**attempt
     """
-    resolved_urls = {}
-    cache = cache or {}
+    delay = NetworkConfig.RETRY_BASE_DELAY * (2**attempt)
+    return random.uniform(delay / 2, delay)
 
-    def _is_solidity_alias_like(name):
-        """
-        Heuristic: skip npm lookups for Solidity alias tokens
 
-        - Trailing slash (e.g., '@openzeppelin/') indicates a remapping prefix
-        - Bare scope '@scope' without a package segment
-        """
-        if not isinstance(name, str):
-            return False
-        if name.endswith("/"):
-            return True
-        if name.startswith("@") and "/" not in name:
-            return True
-        return False
+def _http_get_unbounded(url, logger=None):
+    """
+    Perform a single HTTP GET without waiting for a request slot (see _http_get)
+    """
 
-    for package_name, package_data in packages_dict.items():
-        ecosystem = package_data.get("ecosystem", "unknown")
-        url = None
+    _record_response_headers(None)
+    # If a request hook is provided, use it to get raw content instead of the network
+    if _REQUEST_FN is not None:
+        try:
+            raw = _REQUEST_FN(url)
+        except urllib.error.HTTPError as e:
+            _record_response_headers(e.headers)
+            return e.code, None
+        except Exception as e:
+            logger and logger.debug(f"Request hook failed for {url}: {e}")
+            return None, None
+        if raw is None:
+            return 404, None
+        return 200, raw.decode("utf-8", errors="ignore") if isinstance(raw, bytes) else str(raw)
+
+    headers = {"User-Agent": USER_AGENT}
+    token = auth_token_for(url)
+    if token:
+        headers["Authorization"] = f"Bearer {token}"
+    if _keeps_alive(url):
+        result = _http_get_kept_alive(url, headers, logger)
+        if result is not None:
+            return result
+    _count_connection()
+    req = urllib.request.Request(url, headers=headers)
+    try:
+        with _urlopen(req, timeout=request_timeout()) as response:
+            _record_response_headers(getattr(response, "headers", None))
+            return response.status, response.read().decode("utf-8", errors="ignore")
+    except urllib.error.HTTPError as e:
+        _record_response_headers(e.headers)
+        return e.code, None
+    except Exception as e:
+        logger and logger.debug(f"Request failed for {url}: {e}")
+        return None, None
 
-        # Check cache first
-        cache_key = f"{ecosystem}:{package_name}"
-        if cache_key in cache:
-            resolved_urls[package_name] = cache[cache_key]
-            logger and logger.debug(f"Resolved {package_name} from cache -> {cache[cache_key]}")
-            continue
 
-        # Attempt to resolve using .gitmodules URL first
-        gitmodules_url_source = package_data.get("gitmodules_url")
-        if gitmodules_url_source and isinstance(gitmodules_url_source, str):
-            cleaned_gitmodules_url = _clean_repo_url(gitmodules_url_source)
-            if cleaned_gitmodules_url:
-                url = cleaned_gitmodules_url
-                logger and logger.info(f"Resolved {package_name} using .gitmodules URL: {url}")
+def _keeps_alive(url):
+    """
+    Return True when a request goes over this thread's kept-alive connection to its host
 
-        # If URL was not resolved from gitmodules, proceed with ecosystem-specific resolution
-        if not url:
-            try:
+    Only the hosts in _KEEP_ALIVE_HOSTS qualify, with NetworkConfig.NPM_BATCHED_RESOLUTION set
+    and no HTTPS proxy configured, which urllib would have to go through
+    """
+    if not NetworkConfig.NPM_BATCHED_RESOLUTION:
+        return False
+    parts = urllib.parse.urlsplit(url)
+    if parts.scheme != "https" or parts.hostname not in _KEEP_ALIVE_HOSTS:
+        return False
+    return "https" not in urllib.request.getproxies()
+
+
+def close_connections():
+    """
+    Close the kept-alive connections of the calling thread
+    """
+    for connection in getattr(_CONNECTIONS, "by_host", {}).values():
+        connection.close()
+    _CONNECTIONS.by_host = {}
+    with _CONNECTION_POOLS_LOCK:
+        _CONNECTION_POOLS.pop(threading.current_thread(), None)
+
+
+def close_exited_connections():
+    """
+    Close the kept-alive connections of threads that have exited, e.g. the workers of a
+    ThreadPoolExecutor once its `with` block is left
+    """
+    with _CONNECTION_POOLS_LOCK:
+        exited = [thread for thread in _CONNECTION_POOLS if not thread.is_alive()]
+        pools = [_CONNECTION_POOLS.pop(thread) for thread in exited]
+    for pool in pools:
+        for connection in pool.values():
+            connection.close()
+
+
+def _http_get_kept_alive(url, headers, logger=None):
+    """
+    Perform a single HTTP GET over this thread's kept-alive connection to the URL's host
+
+    A connection the server closed while idle is reopened once. Redirects are
+    left to urllib, which follows them
+
+    Args:
+        url (str): Validated https URL
+        headers (dict): Request headers
+        logger: Optional logger
+
+    Returns:
+        tuple|None: (status_code_or_None, text_or_None) as _http_get_unbounded, or None for a redirect
+    """
+    parts = urllib.parse.urlsplit(url)
+    path = parts.path + (f"?{parts.query}" if parts.query else "")
+    if not hasattr(_CONNECTIONS, "by_host"):
+        _CONNECTIONS.by_host = {}
+    pool = _CONNECTIONS.by_host
+    with _CONNECTION_POOLS_LOCK:
+        _CONNECTION_POOLS[threading.current_thread()] = pool
+    while True:
+        connection = pool.get(parts.netloc)
+        reused = connection is not None
+        if not reused:
+            connection = http.client.HTTPSConnection(parts.hostname, parts.port, timeout=request_timeout())
+            pool[parts.netloc] = connection
+            _count_connection()
+        else:
+            # A reused connection's socket keeps the timeout it was opened with
+            connection.timeout = request_timeout()
+            if getattr(connection, "sock", None) is not None:
+                connection.sock.settimeout(connection.timeout)
+        try:
+            connection.request("GET", path, headers=headers)
+            response = connection.getresponse()
+            body = response.read()
+        except Exception as e:
+            connection.close()
+            pool.pop(parts.netloc, None)
+            if reused:
+                continue
+            logger and logger.debug(f"Request failed for {url}: {e}")
+            return None, None
+        if response.will_close:
+            connection.close()
+            pool.pop(parts.netloc, None)
+        _record_response_headers(response.headers)
+        if 300 <= response.status < 400:
+            return None
+        if response.status >= 400:
+            return response.status, None
+        return response.status, body.decode("utf-8", errors="ignore")
+
+
+def _http_get_with_retries(url, logger=None):
+    """
+    GET a URL, retrying connection errors, 429 and 5xx with exponential backoff and jitter
+
+    Up to NetworkConfig.MAX_RETRIES retries are made; the n-th waits a random time
+    between half and all of RETRY_BASE_DELAY * 2**n seconds. Other statuses,
+    including 404, are returned immediately. Requests to hosts registry_allowed
+    rejects are never sent
+
+    Args:
+        url (str): Validated URL
+        logger: Optional logger
+
+    Returns:
+        tuple: (status_code_or_None, text_or_None) of the last attempt; (None, None) for a blocked host
+    """
+    if _request_blocked(url, logger):
+        return None, None
+    max_retries = max(0, NetworkConfig.MAX_RETRIES)
+    for attempt in range(max_retries + 1):
+        status, text = _http_get(url, logger)
+        if logger:
+            logger.trace(
+                f"GET {url} -> {'no response' if status is None else status}",
+                event="http-request",
+                url=url,
+                status=status,
+                attempt=attempt + 1,
+            )
+        if not is_retryable(status) or deadline_exceeded():
+            return status, text
+        if attempt == max_retries:
+            _ATTEMPTS.failures = request_failures() + 1
+            return status, text
+        delay = retry_delay(attempt)
+        logger and logger.debug(
+            f"{'No response' if status is None else f'HTTP {status}'} for {url} "
+            f"(attempt {attempt + 1}/{max_retries + 1}); retrying in {delay:.2f}s",
+            event="http-retry",
+            url=url,
+            status=status,
+            attempt=attempt + 1,
+            delay=round(delay, 2),
+        )
+        _sleep_until_deadline(delay)
+    return status, text
+
+
+def _make_request(url, logger=None):
+    """
+    Make an HTTP GET request for registry JSON with retries and security validation
+
+    Validates URLs against allowed registry domains; transient failures are
+    retried by _http_get_with_retries
+
+    Args:
+        url (str): URL to request (must be from allowed domains)
+        logger (Logger): Optional logger instance
+
+    Returns:
+        JSON response data as dict, or None if request fails or returns 404
+    """
+    # Validate URL for security
+    validated = _validate_or_none(url, logger)
+    if validated is None:
+        return None
+    url = validated
+    if _request_blocked(url, logger):
+        return None
+
+    status, text = _http_get_with_retries(url, logger)
+    if status == 404:
+        logger and logger.debug(f"Package not found (404): {url}")
+        return None
+    if status != 200:
+        logger and logger.error(f"Failed to fetch {url}: {'no response' if status is None else f'HTTP {status}'}")
+        return None
+    try:
+        return json.loads(text)
+    except ValueError as e:
+        logger and logger.error(f"Invalid JSON from {url}: {e}")
+        return None
+
+
+def _strip_fragment(url_str):
+    """
+    Remove fragment part after '#' from repo URL string
+
+    Args:
+        url_str (str): Repository URL string
+
+    Returns:
+        URL string without fragment
+    """
+    if not isinstance(url_str, str):
+        return url_str
+    if "#" in url_str:
+        return url_str.split("#")[0]
+    return url_str
+
+
+def _normalize_git_prefixes(url_str):
+    """
+    Convert 'git+', 'git://', 'ssh://' and SCP-like 'git@host:org/repo' forms to https
+
+    Args:
+        url_str (str): Repository URL string
+
+    Returns:
+        Normalized URL string
+    """
+    if not isinstance(url_str, str):
+        return url_str
+    u = url_str
+    if u.startswith("git+"):
+        u = u[4:]
+    if u.startswith("git://"):
+        u = "https://" + u[6:]
+    match = _RE_SSH_URL.match(u) or _RE_SCP_LIKE.match(u)
+    if match:
+        u = f"https://{match.group(1)}/{match.group(2)}"
+    return u
+
+
+def _canonicalize_host(url_str):
+    """
+    Lowercase the scheme and host of an http(s) URL, dropping credentials, `www.` and the query
+
+    Args:
+        url_str (str): Repository URL string
+
+    Returns:
+        URL string with a canonical authority, or the input when it is not an http(s) URL
+    """
+    if not isinstance(url_str, str) or not re.match(r"^https?://", url_str, re.IGNORECASE):
+        return url_str
+    parts = urllib.parse.urlsplit(url_str)
+    host = (parts.hostname or "").lower()
+    if host.startswith("www."):
+        host = host[4:]
+    try:
+        port = parts.port
+    except ValueError:
+        port = None
+    if port:
+        host = f"{host}:{port}"
+    return f"{parts.scheme.lower()}://{host}{parts.path}"
+
+
+def _extract_github_owner_repo(url_str):
+    """
+    Trim GitHub URL to 'https://github.com/<owner>/<repo>' if present
+
+    Args:
+        url_str (str): Repository URL string
+
+    Returns:
+        Canonical GitHub owner/repo URL if matched, else original string
+    """
+    if not isinstance(url_str, str):
+        return url_str
+    if "github.com" in url_str:
+        match = _RE_GH_CANONICAL.match(url_str)
+        if match:
+            return match.group(1)
+    return url_str
+
+
+def _strip_dot_git(url_str):
+    """
+    Remove trailing slashes and a trailing '.git' suffix
+
+    Args:
+        url_str (str): Repository URL string
+
+    Returns:
+        URL without .git suffix or trailing slashes
+    """
+    if not isinstance(url_str, str):
+        return url_str
+    u = url_str.rstrip("/")
+    if u.endswith(".git"):
+        u = u[:-4].rstrip("/")
+    return u
+
+
+def _assume_github_from_owner_repo_shorthand(url_str):
+    """
+    If string looks like 'owner/repo', return 'https://github.com/owner/repo'
+    Else return None
+
+    Args:
+        url_str (str): Owner/repo shorthand or other string
+
+    Returns:
+        Canonical GitHub URL or None
+    """
+    if not isinstance(url_str, str):
+        return None
+    if _RE_OWNER_REPO_SHORTHAND.match(url_str):
+        return f"https://github.com/{url_str}"
+    return None
+
+
+def normalize_repo_url(repo_url):
+    """
+    Rewrite a repository URL to its canonical form
+
+    Strips fragments, `git+` prefixes, `.git` suffixes and trailing slashes,
+    rewrites `git://`, `ssh://` and SCP-like `git@host:org/repo` forms to
+    https, lowercases the host and drops `www.`, and trims GitHub URLs to
+    `https://github.com/<owner>/<repo>`. `owner/repo` shorthand is taken to
+    be on GitHub
+
+    Args:
+        repo_url (str): Raw repository URL string
+
+    Returns:
+        Canonical repository URL string, or None if the input is not a repository URL
+    """
+    if not repo_url or not isinstance(repo_url, str):
+        return None
+
+    u = _strip_fragment(repo_url.strip())
+    u = _normalize_git_prefixes(u)
+    u = _canonicalize_host(u)
+    u = _extract_github_owner_repo(u)
+    u = _strip_dot_git(u)
+
+    if u.startswith("http://") or u.startswith("https://"):
+        return u
+
+    return _assume_github_from_owner_repo_shorthand(u)
+
+
+def _code_host_kind(host):
+    """
+    Return the code hosting software a host runs, judged by its name
+
+    Args:
+        host (str): Lowercase host, possibly with a port
+
+    Returns:
+        str|None: "github", "gitlab", "bitbucket" or "gitea" for github.com, gitlab.com, bitbucket.org
+            and gitea.com, for enterprise hosts whose first label names one of them (github.acme.com,
+            gitlab.gnome.org) and for codeberg.org, else None
+    """
+    hostname = host.split(":", 1)[0]
+    if hostname in _KNOWN_CODE_HOSTS:
+        return _KNOWN_CODE_HOSTS[hostname]
+    first_label = hostname.split(".", 1)[0]
+    for kind in CODE_HOST_KINDS:
+        if first_label == kind or first_label.startswith(f"{kind}-"):
+            return kind
+    return None
+
+
+def repository_coordinates(repo_url, code_host=None):
+    """
+    Split a code host repository URL into host, owner and repository name
+
+    GitHub, Bitbucket and Gitea repositories are the first two path segments. On
+    GitLab the repository is the last segment before any `/-/` subpath, and the
+    owner the group path above it, subgroups included
+
+    Args:
+        repo_url (str|None): Repository URL, normalized or not (see normalize_repo_url)
+        code_host (str|None): One of CODE_HOST_KINDS when known otherwise, e.g. from the go-get page
+            of a self-hosted forge (see resolve_go_vanity_import); defaults to judging by the host name
+
+    Returns:
+        dict: {"host", "owner", "repo"}, e.g. {"host": "gitlab.com", "owner": "group/subgroup",
+            "repo": "project"}; all three None when there is no URL or it is not on a recognized code host
+    """
+    unknown = {"host": None, "owner": None, "repo": None}
+    normalized = normalize_repo_url(repo_url)
+    if not normalized:
+        return unknown
+    parts = urllib.parse.urlsplit(normalized)
+    host = parts.netloc
+    kind = code_host if code_host in CODE_HOST_KINDS else _code_host_kind(host)
+    segments = [segment for segment in parts.path.split("/") if segment]
+    if kind == "gitlab" and "-" in segments:
+        segments = segments[: segments.index("-")]
+    if kind is None or len(segments) < 2:
+        return unknown
+    if kind == "gitlab":
+        owner, repo = "/".join(segments[:-1]), segments[-1]
+    else:
+        owner, repo = segments[0], segments[1]
+    if repo.endswith(".git"):
+        repo = repo[:-4]
+    return {"host": host, "owner": owner, "repo": repo}
+
+
+def _reset_normalized():
+    """
+    Forget which URLs were rewritten, before resolving the next package on this thread
+    """
+    _NORMALIZED.urls = set()
+
+
+def _was_normalized(url):
+    """
+    Return True if url was produced by rewriting a differently shaped URL on this thread
+
+    Args:
+        url (str): Canonical repository URL
+
+    Returns:
+        bool
+    """
+    return url in getattr(_NORMALIZED, "urls", set())
+
+
+def _clean_repo_url(repo_url):
+    """
+    Normalize a repository URL found during resolution, remembering when it had to be rewritten
+
+    Args:
+        repo_url (str): Raw repository URL string
+
+    Returns:
+        Cleaned repository URL string, or None if URL is invalid
+    """
+    cleaned = normalize_repo_url(repo_url)
+    if cleaned and cleaned != repo_url:
+        if not hasattr(_NORMALIZED, "urls"):
+            _reset_normalized()
+        _NORMALIZED.urls.add(cleaned)
+    return cleaned
+
+
+def url_confidence(source):
+    """
+    Return the confidence tier for a resolution source
+
+    Args:
+        source (str): Receipt "source" value
+
+    Returns:
+        float: Confidence between 0.0 and 1.0; unknown sources score as heuristics
+    """
+    return URL_SOURCE_CONFIDENCE.get(source, URL_CONFIDENCE_HEURISTIC)
+
+
+def _complete_go_receipt(receipt, resolution_cache):
+    """
+    Fill in the fields every resolved Go module's receipt carries
+
+    Besides "source" and "confidence", "cache" is "disabled" without a resolution
+    cache and "skipped" when a custom rule or .gitmodules answered before it was
+    consulted, and "derivation" (see GO_SOURCE_DERIVATION) tells a URL read off
+    the module path from one a host served; it is left out for cached URLs of
+    unknown origin
+
+    Args:
+        receipt (dict): Receipt of a resolved Go module, updated in place
+        resolution_cache (ResolutionCache|None): The on-disk cache of the run, if any
+    """
+    receipt.setdefault("cache", "disabled" if resolution_cache is None else "skipped")
+    receipt.setdefault("confidence", url_confidence(receipt.get("source")))
+    derivation = GO_SOURCE_DERIVATION.get(receipt.get("source"))
+    if derivation:
+        receipt["derivation"] = derivation
+
+
+# Main resolution logic:
+
+
+def resolve_package_urls(
+    packages_dict, logger=None, cache=None, receipts=None, offline=False, resolution_cache=None, cancellation=None
+):
+    """
+    Resolve package names to repository URLs for all ecosystems
+
+    Args:
+        packages_dict (dict): Dictionary of packages to resolve
+        logger (Logger): Optional logger instance
+        cache (dict): Optional pre-populated dictionary for URL caching
+        receipts (dict): Optional dictionary receiving per-package resolution details, including
+            the "source" of each resolved URL and its "confidence" (see URL_SOURCE_CONFIDENCE), and
+            "normalized": True when the URL as found had to be rewritten (see normalize_repo_url)
+        offline (bool): Only use local signals; lookups that need the network are recorded
+            with reason "offline-skipped"
+        resolution_cache (ResolutionCache): Optional on-disk cache keyed by (ecosystem, package, version);
+            a fresh entry is used instead of resolving, and receipts record "cache": "hit", "expired"
+            or "miss". Newly resolved URLs are stored in it; callers save it
+        cancellation (CancellationToken): Optional token; once cancelled, packages not yet started
+            are left unresolved with reason "cancelled"
+
+    Packages whose lookups the resolution deadline (see resolution_deadline) kept from being sent
+    are left unresolved with reason "resolution-deadline-exceeded"
+
+    Custom rules (NetworkConfig.URL_RULES, see url_rules) are tried first, offline too; a match
+    has receipt "source" "custom-rule" and the matching "rule". Receipts also record the number of HTTP
+    "attempts" made for a package, retries included. With NetworkConfig.NPM_BATCHED_RESOLUTION, each
+    worker thread sends its npm registry lookups over one kept-alive connection (see _http_get_kept_alive)
+
+    Returns:
+        Dictionary containing resolved package URLs
+    """
+    resolved_urls = {}
+    cache = cache or {}
+    url_rules = configured_url_rules()
+    go_vanity_cache = {}
+    pkggodev_cache = {}
+    go_origin_cache = {}
+
+    def _is_solidity_alias_like(name):
+        """
+        Heuristic: skip npm lookups for Solidity alias tokens
+
+        - Trailing slash (e.g., '@openzeppelin/') indicates a remapping prefix
+        - Bare scope '@scope' without a package segment
+        """
+        if not isinstance(name, str):
+            return False
+        if name.endswith("/"):
+            return True
+        if name.startswith("@") and "/" not in name:
+            return True
+        return False
+
+    def _resolve_one(package_name, package_data, receipt):
+        """
+        Resolve one package, filling in its receipt
+
+        Runs on a resolver worker thread; request counts are kept per thread
+
+        Returns:
+            str|None: Cleaned repository URL
+        """
+        if is_cancelled(cancellation):
+            receipt["reason"] = "cancelled"
+            return None
+        ecosystem = package_data.get("ecosystem", "unknown")
+        url = None
+        reset_request_attempts()
+        _reset_normalized()
+
+        # Check cache first; replaced Go modules are cached under their replacement target
+        cache_key = f"{ecosystem}:{package_name}"
+        go_replace = package_data.get("replace") if ecosystem == "go" else None
+        if go_replace:
+            cache_key = None if go_replace.get("local") else f"{ecosystem}:{go_replace['path']}"
+
+        # Custom rules come first so they win over cached and registry answers; local replaces have no repository
+        if url_rules and not (go_replace or {}).get("local"):
+            rule_name = (go_replace or {}).get("path") or package_name
+            rule_version = (go_replace or {}).get("version") or package_data.get("version")
+            url, rule = match_url_rules(url_rules, ecosystem, rule_name, rule_version, logger)
+            if url:
+                receipt["source"] = "custom-rule"
+                receipt["rule"] = rule
+                if ecosystem == "go" and is_go_private_module(rule_name):
+                    receipt["private"] = True
+                if go_replace:
+                    receipt["replaced_from"] = package_name
+                    replaced_version = go_replace.get("version")
+                    receipt["replaced_to"] = f"{rule_name}@{replaced_version}" if replaced_version else rule_name
+
+        if not url and cache_key in cache:
+            cached_url = _clean_repo_url(cache[cache_key]) or cache[cache_key]
+            # How a cached URL was first obtained is not known, so it carries no confidence
+            receipt["source"] = "cache"
+            if _was_normalized(cached_url):
+                receipt["normalized"] = True
+            if ecosystem == "go":
+                _record_go_major_version(receipt, (go_replace or {}).get("path") or package_name)
+                # Unless the module path alone gives the same URL, which needs no network
+                local_receipt = {}
+                local_url = resolve_go_package(
+                    package_name,
+                    receipt=local_receipt,
+                    module_path=package_data.get("module_path"),
+                    replace=go_replace,
+                    offline=True,
+                )
+                if local_url and _clean_repo_url(local_url) == cached_url:
+                    receipt["source"] = local_receipt["source"]
+                receipt["cache"] = "hit"
+                _complete_go_receipt(receipt, resolution_cache)
+            logger and logger.debug(
+                f"Resolved {package_name} from cache -> {cached_url}",
+                event="url-resolved",
+                package=package_name,
+                ecosystem=ecosystem,
+                source="cache",
+                url=cached_url,
+            )
+            return cached_url
+
+        # Attempt to resolve using .gitmodules URL first
+        gitmodules_url_source = package_data.get("gitmodules_url")
+        if not url and gitmodules_url_source and isinstance(gitmodules_url_source, str):
+            cleaned_gitmodules_url = _clean_repo_url(gitmodules_url_source)
+            if cleaned_gitmodules_url:
+                url = cleaned_gitmodules_url
+                receipt["source"] = "gitmodules"
+                logger and logger.info(f"Resolved {package_name} using .gitmodules URL: {url}")
+
+        # The on-disk cache keys replaced Go modules by their replacement target as well
+        disk_key = None
+        if not url and resolution_cache is not None and cache_key is not None:
+            if go_replace:
+                disk_key = (ecosystem, go_replace["path"], go_replace.get("version", ""))
+            else:
+                disk_key = (ecosystem, package_name, package_data.get("version", ""))
+            entry, receipt["cache"] = resolution_cache.lookup(*disk_key)
+            logger and logger.debug(
+                f"Resolution cache {receipt['cache']} for {package_name}",
+                event="resolution-cache",
+                package=package_name,
+                ecosystem=ecosystem,
+                status=receipt["cache"],
+            )
+            if entry:
+                url = entry["url"]
+                receipt["source"] = entry["source"]
+                if entry.get("repository_subpath"):
+                    receipt["repository_subpath"] = entry["repository_subpath"]
+                if entry.get("code_host"):
+                    receipt["code_host"] = entry["code_host"]
+                if ecosystem == "go" and is_go_private_module(disk_key[1]):
+                    receipt["private"] = True
+                if go_replace:
+                    receipt["replaced_from"] = package_name
+                    receipt["replaced_to"] = f"{disk_key[1]}@{disk_key[2]}" if disk_key[2] else disk_key[1]
+                logger and logger.debug(f"Resolved {package_name} from the resolution cache -> {url}")
+
+        # If URL was not resolved from gitmodules, proceed with ecosystem-specific resolution
+        if not url:
+            try:
                 if ecosystem == "npm":
-                    url = resolve_npm_package(package_name, logger)
+                    url = resolve_npm_package(package_name, logger, receipt=receipt, offline=offline)
                 elif ecosystem == "pypi":
-                    url = resolve_pypi_package(package_name, logger)
+                    url = resolve_pypi_package(package_name, logger, receipt=receipt, offline=offline)
                 elif ecosystem == "cargo":
-                    url = resolve_cargo_package(package_name, logger)
+                    url = resolve_cargo_package(package_name, logger, receipt=receipt, offline=offline)
                 elif ecosystem == "go":
-                    url = resolve_go_package(package_name, logger)
+                    url = resolve_go_package(
+                        package_name,
+                        logger,
+                        receipt=receipt,
+                        vanity_cache=go_vanity_cache,
+                        module_path=package_data.get("module_path"),
+                        replace=go_replace,
+                        pkggodev_cache=pkggodev_cache,
+                        offline=offline,
+                        version=package_data.get("version"),
+                        origin_cache=go_origin_cache,
+                    )
+                elif ecosystem == "github-actions":
+                    url = resolve_github_action(package_name, logger, receipt=receipt)
                 elif ecosystem == "solidity":
                     # Solidity often uses npm. Avoid lookups for alias-like names.
                     if not _is_solidity_alias_like(package_name):
-                        url = resolve_npm_package(package_name, logger)
+                        url = resolve_npm_package(package_name, logger, receipt=receipt, offline=offline)
                     if not url:
                         # Placeholder for potential Etherscan/Sourcegraph resolution
-                        url = resolve_solidity_contract(package_name, package_data.get("source"), logger)
+                        url = resolve_solidity_contract(
+                            package_name, package_data.get("source"), logger, receipt=receipt
+                        )
             except Exception as e:
                 logger and logger.warning(f"Error resolving URL for {package_name} ({ecosystem}): {e}")
 
-        if url:
-            # Clean the resolved URL before storing
-            cleaned_url = _clean_repo_url(url)
-            if cleaned_url:
-                resolved_urls[package_name] = cleaned_url
-                logger and logger.debug(f"Resolved {package_name} ({ecosystem}) -> {cleaned_url}")
-            else:
-                logger and logger.debug(f"Could not clean URL for {package_name} ({ecosystem}): {url}")
-        else:
-            logger and logger.debug(f"Could not resolve URL for {package_name} ({ecosystem})")
+        if request_attempts():
+            receipt["attempts"] = request_attempts()
+        if request_failures():
+            receipt["network_errors"] = request_failures()
+        if not url and request_blocks():
+            receipt["reason"] = "registry-blocked"
+        elif not url and request_deadline_hits():
+            receipt["reason"] = "resolution-deadline-exceeded"
+
+        if url:
+            # Clean the resolved URL before storing
+            cleaned_url = _clean_repo_url(url)
+            if cleaned_url:
+                receipt["confidence"] = url_confidence(receipt.get("source"))
+                if _was_normalized(cleaned_url):
+                    receipt["normalized"] = True
+                if ecosystem == "go":
+                    _record_go_major_version(receipt, (go_replace or {}).get("path") or package_name)
+                    _complete_go_receipt(receipt, resolution_cache)
+                if disk_key and receipt["cache"] != "hit":
+                    resolution_cache.store(
+                        *disk_key,
+                        cleaned_url,
+                        receipt.get("source"),
+                        receipt.get("repository_subpath"),
+                        receipt.get("code_host"),
+                    )
+                logger and logger.debug(
+                    f"Resolved {package_name} ({ecosystem}) -> {cleaned_url}",
+                    event="url-resolved",
+                    package=package_name,
+                    ecosystem=ecosystem,
+                    source=receipt.get("source"),
+                    url=cleaned_url,
+                    attempts=receipt.get("attempts", 0),
+                )
+                return cleaned_url
+            else:
+                logger and logger.debug(
+                    f"Could not clean URL for {package_name} ({ecosystem}): {url}",
+                    event="url-unresolved",
+                    package=package_name,
+                    ecosystem=ecosystem,
+                    reason="invalid-url",
+                )
+        else:
+            logger and logger.debug(
+                f"Could not resolve URL for {package_name} ({ecosystem})",
+                event="url-unresolved",
+                package=package_name,
+                ecosystem=ecosystem,
+                reason=receipt.get("reason") or "not-found",
+                attempts=receipt.get("attempts", 0),
+                network_errors=receipt.get("network_errors", 0),
+            )
+        return None
+
+    # Receipts are created up front so their order follows packages_dict whatever order workers finish in
+    jobs = []
+    for package_name, package_data in packages_dict.items():
+        receipt = {}
+        if receipts is not None:
+            receipts[package_name] = receipt
+        jobs.append((package_name, package_data, receipt))
+
+    workers = min(resolver_concurrency(), len(jobs))
+    if workers <= 1:
+        urls = [_resolve_one(*job) for job in jobs]
+        close_connections()
+    else:
+        with ThreadPoolExecutor(max_workers=workers, thread_name_prefix="gardener-resolver") as executor:
+            urls = list(executor.map(lambda job: _resolve_one(*job), jobs))
+        close_exited_connections()
+    for (package_name, _, _), url in zip(jobs, urls):
+        if url:
+            resolved_urls[package_name] = url
+
+    return resolved_urls
+
+
+def _npm_is_types_package(package_name):
+    """
+    Return DefinitelyTyped URL if package is '@types/*', else None
+
+    Args:
+        package_name (str): Package name
+
+    Returns:
+        str or None: URL if @types, else None
+    """
+    if package_name.startswith("@types/"):
+        return "https://github.com/DefinitelyTyped/DefinitelyTyped"
+    return None
+
+
+def _npm_registry_url(package_name):
+    """
+    Return npm registry URL with '/' encoded as '%2F'
+
+    Args:
+        package_name (str): Package name
+
+    Returns:
+        str: Registry URL
+    """
+    safe_package_name = package_name.replace("/", "%2F")
+    return f"https://registry.npmjs.org/{safe_package_name}"
+
+
+def _npm_fetch_metadata(package_name, logger=None):
+    """
+    Use _make_request to fetch npm metadata JSON
+
+    Args:
+        package_name (str): Package name
+        logger: Optional logger
+
+    Returns:
+        dict or None: Metadata
+    """
+    url = _npm_registry_url(package_name)
+    return _make_request(url, logger)
+
+
+def _npm_pick_version_metadata(data):
+    """
+    Return metadata dict for 'dist-tags.latest' version if present
+
+    Args:
+        data (dict): Registry data
+
+    Returns:
+        dict or None: Version metadata
+    """
+    latest_version = data.get("dist-tags", {}).get("latest")
+    if latest_version and "versions" in data and latest_version in data["versions"]:
+        return data["versions"][latest_version]
+    return None
+
+
+def _npm_from_repository(repo_info, logger=None):
+    """
+    Interpret 'repository' field (dict or str) to a GitHub/GitLab URL
+
+    Returns:
+        Cleaned URL or None
+    """
+    if not repo_info:
+        return None
+    logger and logger.debug(f"Repository info: {repo_info}")
+
+    if isinstance(repo_info, dict):
+        repo_url = _clean_repo_url(repo_info.get("url"))
+        if repo_url:
+            return repo_url
+        for key, value in repo_info.items():
+            if isinstance(value, str) and "github.com" in value:
+                match = _RE_GH_OWNER_REPO_COLON_OR_SLASH.search(value)
+                if match:
+                    return f"https://github.com/{match.group(1)}"
+        return None
+
+    if isinstance(repo_info, str):
+        repo_url = _clean_repo_url(repo_info)
+        if repo_url:
+            return repo_url
+        if "github.com" in repo_info:
+            match = _RE_GH_OWNER_REPO_COLON_OR_SLASH.search(repo_info)
+            if match:
+                return f"https://github.com/{match.group(1)}"
+        elif repo_info.startswith("github:"):
+            return f"https://github.com/{repo_info[7:]}"
+    return None
+
+
+def _npm_from_bugs(bugs_info):
+    """
+    Extract GitHub URL from 'bugs' dict or string using existing regex
+
+    Args:
+        bugs_info: Bugs field value
+
+    Returns:
+        str or None
+    """
+    if not bugs_info:
+        return None
+    if isinstance(bugs_info, dict) and "url" in bugs_info:
+        bugs_url = bugs_info["url"]
+        if isinstance(bugs_url, str) and "github.com" in bugs_url:
+            match = _RE_GH_OWNER_REPO_SLASH.search(bugs_url)
+            if match:
+                return f"https://github.com/{match.group(1)}"
+    elif isinstance(bugs_info, str) and "github.com" in bugs_info:
+        match = _RE_GH_OWNER_REPO_SLASH.search(bugs_info)
+        if match:
+            return f"https://github.com/{match.group(1)}"
+    return None
+
+
+def _npm_from_homepage(homepage):
+    """
+    Extract GitHub URL from 'homepage' string using existing regex
+
+    Args:
+        homepage: Homepage field value
+
+    Returns:
+        str or None
+    """
+    if homepage and isinstance(homepage, str) and "github.com" in homepage:
+        match = _RE_GH_OWNER_REPO_SLASH.search(homepage)
+        if match:
+            return f"https://github.com/{match.group(1)}"
+    return None
+
+
+def _npm_infer_from_scoped_text(package_name, data, logger=None):
+    """
+    For scoped packages, scan description/readme for URLs under same org
+
+    Returns:
+        Inferred https://github.com/<org>/<repo> URL or None
+    """
+    if not (package_name.startswith("@") and "/" in package_name):
+        return None
+    org = package_name.split("/")[0][1:]
+    for field in ["description", "readme"]:
+        content = data.get(field)
+        if content and isinstance(content, str):
+            matches = re.findall(r"https?://(?:www\.)?github\.com/([^/\s]+)/([^/\s]+)", content)
+            for match_org, match_repo in matches:
+                if match_org.lower() == org.lower():
+                    logger and logger.debug(f"Found potential GitHub repo match: {match_org}/{match_repo}")
+                    return f"https://github.com/{match_org}/{match_repo}"
+    return None
+
+
+def resolve_npm_package(package_name, logger=None, receipt=None, offline=False):
+    """
+    Resolve npm package to repository URL
+
+    Args:
+        package_name (str): The NPM package name to resolve
+        logger (Logger): Optional logger instance
+        receipt (dict): Optional dictionary receiving the "source" of the resolved URL
+        offline (bool): Skip the registry lookup, recording reason "offline-skipped"
+
+    Returns:
+        Repository URL string or None if not found
+    """
+    receipt = receipt if receipt is not None else {}
+
+    # Special handling for TypeScript definition packages (@types/*)
+    u = _npm_is_types_package(package_name)
+    if u:
+        receipt["source"] = "known-package"
+        return u
+
+    # Fast-path: known monorepo scope fallback when registry metadata is incomplete
+    if package_name.startswith("@docusaurus/"):
+        receipt["source"] = "known-package"
+        return "https://github.com/facebook/docusaurus"
+
+    if offline:
+        receipt["reason"] = "offline-skipped"
+        return None
+
+    data = _npm_fetch_metadata(package_name, logger)
+
+    if not data:
+        logger and logger.warning(f"No data returned from npm registry for {package_name}")
+        return None
+
+    version_data = _npm_pick_version_metadata(data)
+
+    # Try to find repository information in version metadata first, then fall back to top-level
+    for metadata in [version_data, data]:
+        if not metadata:
+            continue
+        u = _npm_from_repository(metadata.get("repository"), logger)
+        if u:
+            receipt["source"] = "registry"
+            return u
+        u = _npm_from_bugs(metadata.get("bugs")) or _npm_from_homepage(metadata.get("homepage"))
+        if u:
+            receipt["source"] = "registry-link"
+            return u
+
+    # 4. Infer from package name for other scoped packages
+    u = _npm_infer_from_scoped_text(package_name, data, logger)
+    if u:
+        receipt["source"] = "inferred"
+        return u
+
+    logger and logger.debug(f"Couldn't resolve GitHub URL for {package_name}")
+    return None
+
+
+# Targeted fallbacks for well-known projects missing metadata on PyPI
+_PYPI_KNOWN_REPOSITORIES = {
+    "vispy": "https://github.com/vispy/vispy",
+}
+
+
+def _pep503_normalize(name):
+    """
+    Normalize distribution name per PEP 503 (simple repository API)
+
+    Args:
+        name (str): Candidate name
+
+    Returns:
+        str: normalized name (lowercased, runs of [-_.]+ collapsed to '-')
+    """
+    if not isinstance(name, str):
+        return name
+    # Collapse runs of -, _, . into '-'; lowercase
+    return re.sub(r"[-_.]+", "-", name.strip()).lower()
+
+
+def _pypi_fetch_metadata(package_name, logger=None):
+    """
+    Fetch PyPI metadata JSON using _make_request
+
+    Args:
+        package_name (str): Package name
+        logger: Optional logger
+
+    Returns:
+        dict or None
+    """
+    normalized = _pep503_normalize(package_name)
+    url = f"https://pypi.org/pypi/{normalized}/json"
+    return _make_request(url, logger)
+
+
+def _pypi_extract_info(data):
+    """
+    Return 'info' dict from PyPI metadata or {}
+
+    Args:
+        data (dict): PyPI metadata
+
+    Returns:
+        dict
+    """
+    return data.get("info", {}) if data else {}
+
+
+def _pypi_lowercase_urls(project_urls, logger, package_name):
+    """
+    Lowercase keys and log the original keys exactly as today
+
+    Args:
+        project_urls (dict): Project URLs
+        logger: Optional logger
+        package_name (str): Package name for logging
+
+    Returns:
+        dict: lowercase-keyed project URLs
+    """
+    project_urls = project_urls or {}
+    lowercase_urls = {k.lower(): v for k, v in project_urls.items()}
+    logger and logger.debug(f"Project URL keys for {package_name}: {list(project_urls.keys())}")
+    return lowercase_urls
+
+
+def _pypi_preferred_url(lowercase_urls):
+    """
+    Iterate preferred keys and return cleaned GitHub/GitLab URL if any
+
+    Args:
+        lowercase_urls (dict): Lowercased project URLs
+
+    Returns:
+        str or None
+    """
+    preferred_keys = [
+        "repository",
+        "source",
+        "source code",
+        "github",
+        # Common alternatives seen in the wild
+        "repo",
+        "code",
+        "code repository",
+        "vc",
+        # Fall back to homepage after these
+        "homepage",
+        "home",
+    ]
+    for key in preferred_keys:
+        proj_url = _clean_repo_url(lowercase_urls.get(key))
+        if proj_url and ("github.com" in proj_url or "gitlab.com" in proj_url):
+            return proj_url
+    return None
+
+
+def _pypi_home_page(info):
+    """
+    Return cleaned 'home_page' URL if it is GitHub/GitLab, else None
+
+    Args:
+        info (dict): Info dict from PyPI
+
+    Returns:
+        str or None
+    """
+    homepage = _clean_repo_url(info.get("home_page"))
+    if homepage and ("github.com" in homepage or "gitlab.com" in homepage):
+        return homepage
+    return None
+
+
+def _pypi_find_any_repo_in_urls(lowercase_urls):
+    """
+    Scan all project_urls values for a GitHub/GitLab URL regardless of key
+
+    Args:
+        lowercase_urls (dict): Lowercased project URLs
+
+    Returns:
+        str or None
+    """
+    if not lowercase_urls:
+        return None
+    for v in lowercase_urls.values():
+        u = _clean_repo_url(v)
+        if isinstance(u, str) and ("github.com" in u or "gitlab.com" in u):
+            return u
+    return None
+
+
+def resolve_pypi_package(package_name, logger=None, receipt=None, offline=False):
+    """
+    Resolve PyPI package to repository URL
+
+    Args:
+        package_name (str): The PyPI package name to resolve
+        logger (Logger): Optional logger instance
+        receipt (dict): Optional dictionary receiving the "source" of the resolved URL
+        offline (bool): Skip the registry lookup, recording reason "offline-skipped"
+
+    Returns:
+        Repository URL string or None if not found
+    """
+    receipt = receipt if receipt is not None else {}
+    if offline:
+        if package_name in _PYPI_KNOWN_REPOSITORIES:
+            receipt["source"] = "known-package"
+            return _PYPI_KNOWN_REPOSITORIES[package_name]
+        receipt["reason"] = "offline-skipped"
+        return None
+
+    data = _pypi_fetch_metadata(package_name, logger)
+    if not data:
+        return None
+
+    info = _pypi_extract_info(data)
+
+    # Prioritize common repo URLs in project_urls
+    lowercase_urls = _pypi_lowercase_urls(info.get("project_urls") or {}, logger, package_name)
+
+    # Prefer explicit repo/source keys first
+    preferred = _pypi_preferred_url(lowercase_urls)
+    if preferred:
+        receipt["source"] = "registry"
+        return preferred
+
+    # Fallback to top-level home_page, then scan any project_urls value for a GitHub/GitLab link
+    link = _pypi_home_page(info) or _pypi_find_any_repo_in_urls(lowercase_urls)
+    if link:
+        receipt["source"] = "registry-link"
+        return link
+
+    if package_name in _PYPI_KNOWN_REPOSITORIES:
+        receipt["source"] = "known-package"
+        return _PYPI_KNOWN_REPOSITORIES[package_name]
+
+    return None
+
+
+def _cargo_fetch_metadata(package_name, logger=None):
+    """
+    Fetch crates.io metadata JSON using _make_request
+
+    Args:
+        package_name (str): Crate name
+        logger: Optional logger
+
+    Returns:
+        dict or None
+    """
+    url = f"https://crates.io/api/v1/crates/{package_name}"
+    return _make_request(url, logger)
+
+
+def _cargo_from_repository(crate):
+    """
+    Return cleaned 'repository' URL if present
+
+    Args:
+        crate (dict): Crate metadata
+
+    Returns:
+        str or None
+    """
+    return _clean_repo_url(crate.get("repository")) if crate else None
+
+
+def _cargo_from_homepage(crate):
+    """
+    Return cleaned 'homepage' URL if it matches anchored owner/repo regex
+
+    Args:
+        crate (dict): Crate metadata
+
+    Returns:
+        str or None
+    """
+    if not crate:
+        return None
+    homepage = _clean_repo_url(crate.get("homepage"))
+    if homepage and ("github.com" in homepage or "gitlab.com" in homepage):
+        if re.match(r"https?://(?:www\.)?(?:github|gitlab)\.com/[^/]+/[^/]+/?$", homepage):
+            return homepage
+    return None
+
+
+def _cargo_from_documentation(crate):
+    """
+    Infer repo from GitHub/GitLab Pages documentation URL patterns
+
+    Args:
+        crate (dict): Crate metadata
+
+    Returns:
+        str or None
+    """
+    if not crate:
+        return None
+    documentation = crate.get("documentation")
+    if documentation and isinstance(documentation, str):
+        match_gh_pages = _RE_GH_PAGES.match(documentation)
+        if match_gh_pages:
+            user, repo = match_gh_pages.groups()
+            return f"https://github.com/{user}/{repo}"
+        match_gl_pages = _RE_GL_PAGES.match(documentation)
+        if match_gl_pages:
+            user, repo = match_gl_pages.groups()
+            return f"https://gitlab.com/{user}/{repo}"
+    return None
+
+
+def resolve_cargo_package(package_name, logger=None, receipt=None, offline=False):
+    """
+    Resolve Cargo crate to repository URL
+
+    Args:
+        package_name (str): The Cargo crate name to resolve
+        logger (Logger): Optional logger instance
+        receipt (dict): Optional dictionary receiving the "source" of the resolved URL
+        offline (bool): Skip the crates.io lookup, recording reason "offline-skipped"
+
+    Returns:
+        Repository URL string or None if not found
+    """
+    receipt = receipt if receipt is not None else {}
+    if offline:
+        receipt["reason"] = "offline-skipped"
+        return None
+
+    data = _cargo_fetch_metadata(package_name, logger)  # User-Agent is handled by helper
+
+    if data:
+        crate = data.get("crate", {})
+
+        # 1. Try repository field
+        repository = _cargo_from_repository(crate)
+        if repository:
+            receipt["source"] = "registry"
+            return repository
+
+        # 2. Try homepage field
+        homepage = _cargo_from_homepage(crate)
+        if homepage:
+            receipt["source"] = "registry-link"
+            return homepage
+
+        # 3. Try to infer from documentation URL (less reliable)
+        inferred = _cargo_from_documentation(crate)
+        if inferred:
+            receipt["source"] = "inferred"
+            return inferred
 
-    return resolved_urls
+    return None
 
 
-def _npm_is_types_package(package_name):
+def _go_direct_repo_from_path(package_name):
     """
-    Return DefinitelyTyped URL if package is '@types/*', else None
+    Infer https URL from import path containing github.com/ or gitlab.com/
 
     Args:
-        package_name (str): Package name
+        package_name (str): Go import path
 
     Returns:
-        str or None: URL if @types, else None
+        str or None: 'https://<host>/<org>/<repo>' or None
     """
-    if package_name.startswith("@types/"):
-        return "https://github.com/DefinitelyTyped/DefinitelyTyped"
+    if "github.com/" in package_name or "gitlab.com/" in package_name:
+        parts = package_name.split("/")
+        if len(parts) >= 3:
+            return f"https://{parts[0]}/{parts[1]}/{parts[2]}"
     return None
 
 
-def _npm_registry_url(package_name):
+def _go_strip_major_version_suffix(package_name, module_path=None):
     """
-    Return npm registry URL with '/' encoded as '%2F'
+    Drop a trailing `/vN` (N >= 2) major-version segment from a Go module path
+
+    When the declaring module path is known, the suffix is only stripped if it
+    terminates that module path; an import whose `/v2` is an ordinary
+    subdirectory of a module is resolved through the module path instead
 
     Args:
-        package_name (str): Package name
+        package_name (str): Go module or import path
+        module_path (str): Optional module path (from go.mod) that provides package_name
 
     Returns:
-        str: Registry URL
+        str: Path suitable for repository URL inference
     """
-    safe_package_name = package_name.replace("/", "%2F")
-    return f"https://registry.npmjs.org/{safe_package_name}"
+    return _RE_GO_MAJOR_VERSION_SUFFIX.sub("", module_path or package_name)
 
 
-def _npm_fetch_metadata(package_name, logger=None):
+def _go_meta_tag_fetch_url(package_name):
     """
-    Use _make_request to fetch npm metadata JSON
+    Return 'https://{package_name}?go-get=1'
 
     Args:
-        package_name (str): Package name
+        package_name (str): Go import path
+
+    Returns:
+        str: URL to fetch meta tags
+    """
+    return f"https://{package_name}?go-get=1"
+
+
+def _go_meta_tag_fetch_page(fetch_url, logger=None):
+    """
+    Request a go-get=1 page and return its HTML content
+
+    Args:
+        fetch_url (str): Validated URL to fetch
         logger: Optional logger
 
     Returns:
-        dict or None: Metadata
+        str or None: Page content, or None when the fetch fails
     """
-    url = _npm_registry_url(package_name)
-    return _make_request(url, logger)
+    status, content = _http_get_with_retries(fetch_url, logger)
+    return content if status == 200 else None
 
 
-def _npm_pick_version_metadata(data):
+def _html_meta_contents(content, name):
     """
-    Return metadata dict for 'dist-tags.latest' version if present
+    Return the content fields of the HTML meta tags with a given name
+
+    Attributes may come in any order and be quoted either way or not at all, as
+    forges render them differently (GitLab, Gitea and hand-written vanity pages)
 
     Args:
-        data (dict): Registry data
+        content (str): HTML page
+        name (str): Meta tag name, e.g. "go-import"
 
     Returns:
-        dict or None: Version metadata
+        list: Unescaped content values split on whitespace, one list per matching tag
     """
-    latest_version = data.get("dist-tags", {}).get("latest")
-    if latest_version and "versions" in data and latest_version in data["versions"]:
-        return data["versions"][latest_version]
-    return None
+    found = []
+    for attributes in _RE_HTML_META.findall(content):
+        values = {}
+        for key, double_quoted, single_quoted, bare in _RE_HTML_ATTRIBUTE.findall(attributes):
+            values[key.lower()] = double_quoted or single_quoted or bare
+        if values.get("name", "").lower() == name and "content" in values:
+            found.append(html.unescape(values["content"]).split())
+    return found
 
 
-def _npm_from_repository(repo_info, logger=None):
+def _go_select_import_meta(content, import_path):
     """
-    Interpret 'repository' field (dict or str) to a GitHub/GitLab URL
+    Pick the go-import meta tag whose import prefix covers the import path
+
+    Args:
+        content (str): HTML returned for the go-get=1 request
+        import_path (str): Go import path being resolved
 
     Returns:
-        Cleaned URL or None
+        tuple or None: (import_prefix, vcs, repo_root_url) for the longest matching prefix
     """
-    if not repo_info:
-        return None
-    logger and logger.debug(f"Repository info: {repo_info}")
+    best = None
+    for fields in _html_meta_contents(content, "go-import"):
+        if len(fields) != 3 or fields[1] not in _GO_IMPORT_VCS:
+            continue
+        prefix, vcs, repo_root = fields
+        if import_path == prefix or import_path.startswith(prefix + "/"):
+            if best is None or len(prefix) > len(best[0]):
+                best = (prefix, vcs, repo_root)
+    return best
 
-    if isinstance(repo_info, dict):
-        repo_url = _clean_repo_url(repo_info.get("url"))
-        if repo_url:
-            return repo_url
-        for key, value in repo_info.items():
-            if isinstance(value, str) and "github.com" in value:
-                match = _RE_GH_OWNER_REPO_COLON_OR_SLASH.search(value)
-                if match:
-                    return f"https://github.com/{match.group(1)}"
-        return None
 
-    if isinstance(repo_info, str):
-        repo_url = _clean_repo_url(repo_info)
-        if repo_url:
-            return repo_url
-        if "github.com" in repo_info:
-            match = _RE_GH_OWNER_REPO_COLON_OR_SLASH.search(repo_info)
-            if match:
-                return f"https://github.com/{match.group(1)}"
-        elif repo_info.startswith("github:"):
-            return f"https://github.com/{repo_info[7:]}"
+def _go_page_code_host(content, import_prefix):
+    """
+    Tell which forge served a go-get page, from the go-source meta tag of the import prefix
+
+    The directory template gives it away: `/-/tree/` on GitLab, `/src/branch/`
+    (or `/src/commit/`) on Gitea and Forgejo
+
+    Args:
+        content (str): HTML returned for the go-get=1 request
+        import_prefix (str): Import prefix of the selected go-import tag
+
+    Returns:
+        str|None: "gitlab" or "gitea", or None when the page does not tell
+    """
+    for fields in _html_meta_contents(content, "go-source"):
+        if len(fields) == 4 and fields[0] == import_prefix:
+            for marker, kind in _GO_SOURCE_CODE_HOSTS:
+                if marker in fields[2]:
+                    return kind
     return None
 
 
-def _npm_from_bugs(bugs_info):
+def _go_vanity_cache_lookup(host_cache, import_path):
     """
-    Extract GitHub URL from 'bugs' dict or string using existing regex
+    Return the cached (url, reason) entry covering the import path, if any
 
     Args:
-        bugs_info: Bugs field value
+        host_cache (dict): Mapping of import prefix -> (url, reason, code_host) for one host
+        import_path (str): Go import path being resolved
 
     Returns:
-        str or None
+        tuple or None: (import_prefix, (url, reason, code_host)) for the longest cached prefix
     """
-    if not bugs_info:
-        return None
-    if isinstance(bugs_info, dict) and "url" in bugs_info:
-        bugs_url = bugs_info["url"]
-        if isinstance(bugs_url, str) and "github.com" in bugs_url:
-            match = _RE_GH_OWNER_REPO_SLASH.search(bugs_url)
-            if match:
-                return f"https://github.com/{match.group(1)}"
-    elif isinstance(bugs_info, str) and "github.com" in bugs_info:
-        match = _RE_GH_OWNER_REPO_SLASH.search(bugs_info)
-        if match:
-            return f"https://github.com/{match.group(1)}"
-    return None
+    best = None
+    for prefix in host_cache:
+        if import_path == prefix or import_path.startswith(prefix + "/"):
+            if best is None or len(prefix) > len(best):
+                best = prefix
+    return (best, host_cache[best]) if best is not None else None
 
 
-def _npm_from_homepage(homepage):
+def gopkg_in_coordinates(import_path):
     """
-    Extract GitHub URL from 'homepage' string using existing regex
+    Apply the gopkg.in rewrite rules to an import path, without touching the network
+
+    `gopkg.in/pkg.vN` is github.com/go-pkg/pkg and `gopkg.in/user/pkg.vN` is
+    github.com/user/pkg, for every major version N including v0 and v1; a
+    `-unstable` suffix (`gopkg.in/pkg.v2-unstable`) selects the same repository
+    and packages below the module (`gopkg.in/yaml.v3/internal`) map to its root
 
     Args:
-        homepage: Homepage field value
+        import_path (str): Go module or import path
 
     Returns:
-        str or None
+        dict|None: {"owner", "repo", "major_version", "repo_root"}, e.g. {"owner": "go-yaml",
+            "repo": "yaml", "major_version": "v2", "repo_root": "gopkg.in/yaml.v2"}, or None
+            when the path is not a gopkg.in path
     """
-    if homepage and isinstance(homepage, str) and "github.com" in homepage:
-        match = _RE_GH_OWNER_REPO_SLASH.search(homepage)
-        if match:
-            return f"https://github.com/{match.group(1)}"
-    return None
+    match = _RE_GOPKG_IN.match(import_path or "")
+    if not match:
+        return None
+    user, package, major_version = match.groups()
+    return {
+        "owner": user or f"go-{package}",
+        "repo": package,
+        "major_version": major_version,
+        "repo_root": match.group(0).rstrip("/"),
+    }
 
 
-def _npm_infer_from_scoped_text(package_name, data, logger=None):
+def _go_gopkg_in_repo_url(import_path):
     """
-    For scoped packages, scan description/readme for URLs under same org
+    Return the GitHub repository of a gopkg.in path (see gopkg_in_coordinates)
+
+    Args:
+        import_path (str): Go import path
 
     Returns:
-        Inferred https://github.com/<org>/<repo> URL or None
+        str or None: GitHub repository URL, or None when the path is not a gopkg.in path
     """
-    if not (package_name.startswith("@") and "/" in package_name):
+    coordinates = gopkg_in_coordinates(import_path)
+    if not coordinates:
         return None
-    org = package_name.split("/")[0][1:]
-    for field in ["description", "readme"]:
-        content = data.get(field)
-        if content and isinstance(content, str):
-            matches = re.findall(r"https?://(?:www\.)?github\.com/([^/\s]+)/([^/\s]+)", content)
-            for match_org, match_repo in matches:
-                if match_org.lower() == org.lower():
-                    logger and logger.debug(f"Found potential GitHub repo match: {match_org}/{match_repo}")
-                    return f"https://github.com/{match_org}/{match_repo}"
+    return f"https://github.com/{coordinates['owner']}/{coordinates['repo']}"
+
+
+def _go_repo_root(import_path):
+    """
+    Return the import prefix that corresponds to the repository root, when known locally
+
+    GitHub and GitLab paths are rooted at `<host>/<owner>/<repo>` and gopkg.in
+    paths at `gopkg.in/[user/]pkg.vN`, matching the go-import meta tags those
+    hosts serve
+
+    Args:
+        import_path (str): Go module or import path
+
+    Returns:
+        str or None: Repository root import prefix, or None when it needs a vanity lookup
+    """
+    coordinates = gopkg_in_coordinates(import_path)
+    if coordinates:
+        return coordinates["repo_root"]
+    if _go_direct_repo_from_path(import_path):
+        return "/".join(import_path.split("/")[:3])
     return None
 
 
-def resolve_npm_package(package_name, logger=None):
+def _go_repository_subpath(import_path, repo_root):
     """
-    Resolve npm package to repository URL
+    Return the directory of a module inside its repository
 
     Args:
-        package_name (str): The NPM package name to resolve
+        import_path (str): Go module path with any major-version suffix stripped
+        repo_root (str): Import prefix of the repository root
+
+    Returns:
+        str: Slash-separated subpath, or "" for a module at the repository root
+    """
+    if not repo_root or not import_path.startswith(repo_root + "/"):
+        return ""
+    return import_path[len(repo_root) + 1 :]
+
+
+def resolve_go_vanity_import(import_path, logger=None, cache=None, receipt=None):
+    """
+    Resolve a Go vanity import path to its repository URL
+
+    Follows the Go remote import protocol: fetch `https://<import path>?go-get=1`
+    and read the `<meta name="go-import">` tag whose prefix covers the import
+    path. Results are cached per host so sibling paths under the same prefix
+    reuse a single lookup. The forge serving the repository is recorded when the
+    page tells (see _go_page_code_host), so the subgroups of a self-hosted GitLab
+    on any domain split into owner and repository (see repository_coordinates)
+
+    Args:
+        import_path (str): Go import path (e.g. 'golang.org/x/tools/go/packages')
+        logger (Logger): Optional logger instance
+        cache (dict): Optional mapping of host -> {import_prefix: (url, reason, code_host)}
+        receipt (dict): Optional dictionary receiving the "repo_root" import prefix of a resolved URL,
+            and the "code_host" the page reveals
+
+    Returns:
+        tuple: (repo_url_or_None, reason_or_None)
+    """
+    receipt = receipt if receipt is not None else {}
+    gopkg_url = _go_gopkg_in_repo_url(import_path)
+    if gopkg_url:
+        receipt["repo_root"] = _go_repo_root(import_path)
+        return gopkg_url, None
+
+    host = import_path.split("/", 1)[0]
+    host_cache = cache.setdefault(host, {}) if cache is not None else {}
+    cached = _go_vanity_cache_lookup(host_cache, import_path)
+    if cached is not None:
+        logger and logger.debug(f"Resolved Go vanity import {import_path} from host cache")
+        prefix, (url, reason, code_host) = cached
+        if url:
+            receipt["repo_root"] = prefix
+        if code_host:
+            receipt["code_host"] = code_host
+        return url, reason
+
+    fetch_url = _go_meta_tag_fetch_url(import_path)
+    if SECURITY_AVAILABLE:
+        try:
+            fetch_url = InputValidator.validate_url(fetch_url, allowed_schemes={"https"})
+        except ValidationError as e:
+            logger and logger.debug(f"Go package URL validation failed: {fetch_url} - {e}")
+            return None, "invalid-import-path"
+
+    try:
+        content = _go_meta_tag_fetch_page(fetch_url, logger)
+    except Exception as e:
+        logger and logger.debug(f"Go package lookup via 'go-get=1' failed for {import_path}: {e}")
+        content = None
+
+    if content is None:
+        host_cache[import_path] = (None, "vanity-fetch-failed", None)
+        return None, "vanity-fetch-failed"
+
+    meta = _go_select_import_meta(content, import_path)
+    repo_url = _clean_repo_url(meta[2]) if meta else None
+    if not repo_url:
+        host_cache[import_path] = (None, "vanity-meta-missing", None)
+        return None, "vanity-meta-missing"
+
+    code_host = _go_page_code_host(content, meta[0])
+    host_cache[meta[0]] = (repo_url, None, code_host)
+    receipt["repo_root"] = meta[0]
+    if code_host:
+        receipt["code_host"] = code_host
+    return repo_url, None
+
+
+def resolve_go_pkggodev(module_path, logger=None, cache=None):
+    """
+    Read the "Repository" link from a module's pkg.go.dev page
+
+    Negative results are cached as well so a module is fetched at most once per run
+
+    Args:
+        module_path (str): Go module or import path
+        logger (Logger): Optional logger instance
+        cache (dict): Optional mapping of module path -> (url, reason)
+
+    Returns:
+        tuple: (repo_url_or_None, reason_or_None) where reason is "pkggodev-fetch-failed"
+            or "pkggodev-no-repo-link"
+    """
+    cache = cache if cache is not None else {}
+    if module_path in cache:
+        return cache[module_path]
+
+    fetch_url = _validate_or_none(f"https://pkg.go.dev/{module_path}", logger)
+    content = None
+    if fetch_url:
+        try:
+            content = _go_meta_tag_fetch_page(fetch_url, logger)
+        except Exception as e:
+            logger and logger.debug(f"pkg.go.dev lookup failed for {module_path}: {e}")
+
+    if content is None:
+        result = (None, "pkggodev-fetch-failed")
+    else:
+        repo_url = None
+        for pattern in _RE_PKGGODEV_REPO_LINKS:
+            match = pattern.search(content)
+            if match:
+                repo_url = _clean_repo_url(match.group(1))
+                break
+        result = (repo_url, None) if repo_url else (None, "pkggodev-no-repo-link")
+
+    cache[module_path] = result
+    return result
+
+
+def _record_go_major_version(receipt, import_path):
+    """
+    Store the major version a gopkg.in path selects (`.v2` -> "v2") on a resolved module's receipt
+
+    Args:
+        receipt (dict): Resolution receipt
+        import_path (str): Go module path, or the path of its replacement
+    """
+    coordinates = gopkg_in_coordinates(import_path)
+    if coordinates:
+        receipt["major_version"] = coordinates["major_version"]
+
+
+def _record_go_subpath(receipt, module_path, repo_root):
+    """
+    Store a module's in-repository directory on its receipt when it is not the root
+
+    Args:
+        receipt (dict): Resolution receipt
+        module_path (str): Go module path with any major-version suffix stripped
+        repo_root (str): Import prefix of the repository root
+    """
+    subpath = _go_repository_subpath(module_path, repo_root)
+    if subpath:
+        receipt["repository_subpath"] = subpath
+
+
+def resolve_go_package(
+    package_name,
+    logger=None,
+    receipt=None,
+    vanity_cache=None,
+    module_path=None,
+    replace=None,
+    pkggodev_cache=None,
+    offline=False,
+    version=None,
+    origin_cache=None,
+):
+    """
+    Resolve Go package to repository URL
+
+    Major-version suffixes (`/v2`, `/v3`, ...) are collapsed so every major
+    version of a module maps to the same repository. When a go.mod `replace`
+    directive applies, the replacement target is resolved instead; local
+    filesystem replacements have no remote URL
+
+    A module living in a subdirectory of its repository (e.g.
+    `github.com/kubernetes/kubernetes/staging/src/k8s.io/api`) gets its
+    directory recorded as receipt["repository_subpath"], split off at the
+    repository root that the go-import meta tag (or the host's path layout)
+    declares
+
+    Paths that do not name their repository are first looked up in the `Origin`
+    the module proxy records for the required version (see fetch_go_version_origin),
+    then in go-import meta tags and on pkg.go.dev; when the proxy gave no usable
+    Origin, receipt["origin_reason"] says why
+
+    receipt["source"] is "import-path" for URLs read off the path, "gomod-replace"
+    when that path is a replace target's, "gopkg-in", "go-proxy-origin",
+    "go-import-meta" or "pkg.go.dev"
+
+    Args:
+        package_name (str): The Go package name to resolve
         logger (Logger): Optional logger instance
+        receipt (dict): Optional dictionary receiving how the URL was (or was not) resolved
+        vanity_cache (dict): Optional per-host cache shared across vanity lookups
+        module_path (str): Optional go.mod module path providing package_name
+        replace (dict): Optional replace directive ({"path", "version", "local"}) applying to the module
+        pkggodev_cache (dict): Optional cache of pkg.go.dev lookups shared across packages
+        offline (bool): Only resolve from the import path; vanity and pkg.go.dev lookups are
+            recorded with reason "offline-skipped"
+        version (str): Optional required module version, whose proxy Origin is looked up
+        origin_cache (dict): Optional cache of proxy Origin lookups by (module path, version)
+
+    Modules matching GOPRIVATE, GONOPROXY or GONOSUMDB get receipt["private"] and
+    are never looked up on pkg.go.dev; their go-import meta tags are only fetched
+    with an auth token for the host (see auth_token_for), and are otherwise
+    recorded with reason "private-module-skipped"
 
     Returns:
         Repository URL string or None if not found
     """
-    # Special handling for TypeScript definition packages (@types/*)
-    u = _npm_is_types_package(package_name)
-    if u:
-        return u
+    receipt = receipt if receipt is not None else {}
+
+    if replace:
+        receipt["replaced_from"] = package_name
+        replaced_to = replace["path"]
+        if replace.get("version"):
+            replaced_to = f"{replaced_to}@{replace['version']}"
+        receipt["replaced_to"] = replaced_to
+        if replace.get("local"):
+            receipt["reason"] = "replaced-local"
+            receipt["local_path"] = replace["path"]
+            return None
+        package_name = module_path = replace["path"]
+        version = replace.get("version")
 
-    # Fast-path: known monorepo scope fallback when registry metadata is incomplete
-    if package_name.startswith("@docusaurus/"):
-        return "https://github.com/facebook/docusaurus"
+    private = is_go_private_module(module_path or package_name)
+    if private:
+        receipt["private"] = True
 
-    data = _npm_fetch_metadata(package_name, logger)
+    # For Go packages, the import path often IS the repo URL path
+    stripped_path = _go_strip_major_version_suffix(package_name, module_path)
+    direct = _go_direct_repo_from_path(stripped_path)
+    if direct:
+        receipt["source"] = "gomod-replace" if replace else "import-path"
+        _record_go_subpath(receipt, stripped_path, _go_repo_root(stripped_path))
+        return direct
 
-    if not data:
-        logger and logger.warning(f"No data returned from npm registry for {package_name}")
+    if private and not auth_token_for(_go_meta_tag_fetch_url(package_name)):
+        receipt["reason"] = "private-module-skipped"
         return None
 
-    version_data = _npm_pick_version_metadata(data)
+    if offline:
+        gopkg_url = _go_gopkg_in_repo_url(package_name)
+        if gopkg_url:
+            receipt["source"] = "gopkg-in"
+            _record_go_subpath(receipt, stripped_path, _go_repo_root(package_name))
+            return gopkg_url
+        receipt["reason"] = "offline-skipped"
+        return None
 
-    # Try to find repository information in version metadata first, then fall back to top-level
-    for metadata in [version_data, data]:
-        if not metadata:
-            continue
-        u = _npm_from_repository(metadata.get("repository"), logger)
-        if u:
-            return u
-        u = _npm_from_bugs(metadata.get("bugs"))
-        if u:
-            return u
-        u = _npm_from_homepage(metadata.get("homepage"))
-        if u:
-            return u
+    # The proxy says where it fetched this very version from; private modules never reach a proxy
+    if version and not private:
+        key = (module_path or package_name, version)
+        if origin_cache is not None and key in origin_cache:
+            origin, origin_reason = origin_cache[key]
+        else:
+            origin, origin_reason = fetch_go_version_origin(key[0], version, logger)
+            if origin_cache is not None:
+                origin_cache[key] = (origin, origin_reason)
+        if origin:
+            receipt["source"] = "go-proxy-origin"
+            if origin.get("subdir"):
+                receipt["repository_subpath"] = origin["subdir"]
+            return origin["url"]
+        receipt["origin_reason"] = origin_reason
+
+    # Vanity servers are authoritative for every major version, so query the full path
+    url, reason = resolve_go_vanity_import(package_name, logger, cache=vanity_cache, receipt=receipt)
+    if url:
+        receipt["source"] = "gopkg-in" if package_name.startswith("gopkg.in/") else "go-import-meta"
+        _record_go_subpath(receipt, stripped_path, receipt.get("repo_root"))
+        return url
+    if private:
+        receipt["reason"] = reason
+        return None
 
-    # 4. Infer from package name for other scoped packages
-    u = _npm_infer_from_scoped_text(package_name, data, logger)
-    if u:
-        return u
+    # Last resort: pkg.go.dev usually knows the repository even when meta tags fail
+    url, fallback_reason = resolve_go_pkggodev(module_path or package_name, logger, cache=pkggodev_cache)
+    if url:
+        receipt["source"] = "pkg.go.dev"
+        return url
 
-    logger and logger.debug(f"Couldn't resolve GitHub URL for {package_name}")
+    receipt["reason"] = reason
+    receipt["fallback_reason"] = fallback_reason
     return None
 
 
-def _pep503_normalize(name):
+def go_proxy_chain(goproxy=None):
     """
-    Normalize distribution name per PEP 503 (simple repository API)
+    Split a GOPROXY value into its ordered list of proxies
+
+    Entries separated by `,` fall through to the next one only when the module is
+    missing (404/410); entries separated by `|` fall through on any error
 
     Args:
-        name (str): Candidate name
+        goproxy (str): Optional GOPROXY value; defaults to the environment, then GO_PROXY_DEFAULT
 
     Returns:
-        str: normalized name (lowercased, runs of [-_.]+ collapsed to '-')
+        list: [(proxy_url_or_keyword, fall_through_on_any_error)]
     """
-    if not isinstance(name, str):
-        return name
-    # Collapse runs of -, _, . into '-'; lowercase
-    return re.sub(r"[-_.]+", "-", name.strip()).lower()
+    value = goproxy if goproxy is not None else os.environ.get("GOPROXY", "")
+    value = value.strip() or GO_PROXY_DEFAULT
+    chain = []
+    for entry, separator in _RE_GO_PROXY_ENTRY.findall(value):
+        entry = entry.strip()
+        if entry:
+            chain.append((entry.rstrip("/"), separator == "|"))
+    return chain
 
 
-def _pypi_fetch_metadata(package_name, logger=None):
+def _go_proxy_escape(module_path):
     """
-    Fetch PyPI metadata JSON using _make_request
+    Apply the module proxy case encoding (`!` followed by the lowercase letter)
 
     Args:
-        package_name (str): Package name
-        logger: Optional logger
+        module_path (str): Go module path
 
     Returns:
-        dict or None
+        str: Escaped path safe for case-insensitive proxies
     """
-    normalized = _pep503_normalize(package_name)
-    url = f"https://pypi.org/pypi/{normalized}/json"
-    return _make_request(url, logger)
+    return "".join(f"!{char.lower()}" if char.isupper() else char for char in module_path)
 
 
-def _pypi_extract_info(data):
+def go_semver_key(version):
     """
-    Return 'info' dict from PyPI metadata or {}
+    Sort key ranking Go module versions, with releases above prereleases
 
     Args:
-        data (dict): PyPI metadata
+        version (str): Version string from a proxy @v/list response
 
     Returns:
-        dict
+        tuple: Comparable key; non-semver strings sort lowest
     """
-    return data.get("info", {}) if data else {}
+    match = _RE_GO_SEMVER.match(version)
+    if not match:
+        return (-1, 0, 0, 0, "")
+    major, minor, patch, prerelease = match.groups()
+    return (0 if prerelease else 1, int(major), int(minor), int(patch), prerelease or "")
 
 
-def _pypi_lowercase_urls(project_urls, logger, package_name):
+def _go_proxy_fetch(url, logger=None):
     """
-    Lowercase keys and log the original keys exactly as today
+    Perform a GET against a module proxy, retrying transient failures
 
     Args:
-        project_urls (dict): Project URLs
+        url (str): Proxy endpoint URL
         logger: Optional logger
-        package_name (str): Package name for logging
 
     Returns:
-        dict: lowercase-keyed project URLs
+        tuple: (status_code_or_None, text_or_None); None status means the proxy was unreachable
     """
-    project_urls = project_urls or {}
-    lowercase_urls = {k.lower(): v for k, v in project_urls.items()}
-    logger and logger.debug(f"Project URL keys for {package_name}: {list(project_urls.keys())}")
-    return lowercase_urls
+    if SECURITY_AVAILABLE:
+        try:
+            url = InputValidator.validate_url(url, allowed_schemes={"https"})
+        except ValidationError as e:
+            logger and logger.debug(f"Go proxy URL validation failed: {url} - {e}")
+            return None, None
 
+    return _http_get_with_retries(url, logger)
 
-def _pypi_preferred_url(lowercase_urls):
+
+def _go_proxy_latest(proxy, module_path, logger=None):
     """
-    Iterate preferred keys and return cleaned GitHub/GitLab URL if any
+    Ask one proxy for a module's latest version and its commit time
+
+    The highest tagged version from `@v/list` wins; `@latest` is only consulted
+    when the module has no tagged versions
 
     Args:
-        lowercase_urls (dict): Lowercased project URLs
+        proxy (str): Proxy base URL
+        module_path (str): Go module path
+        logger: Optional logger
 
     Returns:
-        str or None
-    """
-    preferred_keys = [
-        "repository",
-        "source",
-        "source code",
-        "github",
-        # Common alternatives seen in the wild
-        "repo",
-        "code",
-        "code repository",
-        "vc",
-        # Fall back to homepage after these
-        "homepage",
-        "home",
-    ]
-    for key in preferred_keys:
-        proj_url = _clean_repo_url(lowercase_urls.get(key))
-        if proj_url and ("github.com" in proj_url or "gitlab.com" in proj_url):
-            return proj_url
-    return None
+        tuple: (status_code_or_None, {"latest_version", "published_at"} or None)
+    """
+    base = f"{proxy}/{_go_proxy_escape(module_path)}/@"
+    status, listing = _go_proxy_fetch(f"{base}v/list", logger)
+    if status != 200:
+        return status, None
+
+    versions = (listing or "").split()
+    if versions:
+        latest = max(versions, key=go_semver_key)
+        status, info = _go_proxy_fetch(f"{base}v/{latest}.info", logger)
+    else:
+        latest = ""
+        status, info = _go_proxy_fetch(f"{base}latest", logger)
+    if status != 200:
+        return status, None
 
+    try:
+        data = json.loads(info or "{}")
+    except ValueError:
+        return None, None
+    version = data.get("Version") or latest
+    if not version:
+        return 404, None
+    return 200, {"latest_version": version, "published_at": data.get("Time", "")}
 
-def _pypi_home_page(info):
+
+def _walk_go_proxy_chain(query, goproxy=None):
     """
-    Return cleaned 'home_page' URL if it is GitHub/GitLab, else None
+    Run a proxy query along the GOPROXY chain until one proxy answers
 
     Args:
-        info (dict): Info dict from PyPI
+        query (callable): query(proxy) -> (status_code_or_None, result_or_None)
+        goproxy (str): Optional GOPROXY override; defaults to the environment
 
     Returns:
-        str or None
-    """
-    homepage = _clean_repo_url(info.get("home_page"))
-    if homepage and ("github.com" in homepage or "gitlab.com" in homepage):
-        return homepage
-    return None
+        tuple: (result or None, reason_or_None) where reason is one of
+            "proxy-not-found", "proxy-unreachable", "proxy-direct", "proxy-off" or "registry-blocked"
+            (every proxy tried was blocked, see registry_allowed)
+    """
+    reason = None
+    for proxy, fall_through_on_any_error in go_proxy_chain(goproxy):
+        if proxy == "off":
+            return None, reason or "proxy-off"
+        if proxy == "direct":
+            return None, reason or "proxy-direct"
+        if not registry_allowed(proxy):
+            reason = reason or "registry-blocked"
+            continue
 
+        status, result = query(proxy)
+        if result is not None:
+            return result, None
 
-def _pypi_find_any_repo_in_urls(lowercase_urls):
+        reason = "proxy-not-found" if status in (404, 410) else "proxy-unreachable"
+        if reason == "proxy-unreachable" and not fall_through_on_any_error:
+            return None, reason
+
+    return None, reason or "proxy-not-found"
+
+
+def fetch_go_proxy_metadata(module_path, logger=None, goproxy=None):
     """
-    Scan all project_urls values for a GitHub/GitLab URL regardless of key
+    Fetch the latest version of a Go module and when it was published
+
+    Honors GOPROXY, including fallback chains. `direct` (fetching from version
+    control) is not supported, and `off` disables lookups; both end the chain.
+    Private modules (see is_go_private_module) are never sent to a proxy
 
     Args:
-        lowercase_urls (dict): Lowercased project URLs
+        module_path (str): Go module path
+        logger (Logger): Optional logger instance
+        goproxy (str): Optional GOPROXY override; defaults to the environment
 
     Returns:
-        str or None
+        tuple: ({"latest_version", "published_at"} or None, reason_or_None) where reason is one of
+            "proxy-not-found", "proxy-unreachable", "proxy-direct", "proxy-off", "registry-blocked" or
+            "private-module-skipped"
     """
-    if not lowercase_urls:
-        return None
-    for v in lowercase_urls.values():
-        u = _clean_repo_url(v)
-        if isinstance(u, str) and ("github.com" in u or "gitlab.com" in u):
-            return u
-    return None
+    if is_go_private_module(module_path):
+        return None, "private-module-skipped"
+    metadata, reason = _walk_go_proxy_chain(lambda proxy: _go_proxy_latest(proxy, module_path, logger), goproxy)
+    if metadata:
+        logger and logger.debug(f"Go proxy reports {module_path} latest {metadata['latest_version']}")
+    return metadata, reason
 
 
-def resolve_pypi_package(package_name, logger=None):
+def fetch_go_version_info(module_path, version, logger=None, goproxy=None):
     """
-    Resolve PyPI package to repository URL
+    Fetch when one module version was published from the module proxy (`@v/<version>.info`)
 
     Args:
-        package_name (str): The PyPI package name to resolve
+        module_path (str): Go module path
+        version (str): Exact module version, tagged or pseudo-version
         logger (Logger): Optional logger instance
+        goproxy (str): Optional GOPROXY override; defaults to the environment
 
     Returns:
-        Repository URL string or None if not found
+        tuple: ({"version", "published_at"} or None, reason_or_None) with the reasons of
+            fetch_go_proxy_metadata
     """
-    data = _pypi_fetch_metadata(package_name, logger)
-    if not data:
-        return None
+    if is_go_private_module(module_path):
+        return None, "private-module-skipped"
 
-    info = _pypi_extract_info(data)
+    def query(proxy):
+        url = f"{proxy}/{_go_proxy_escape(module_path)}/@v/{_go_proxy_escape(version)}.info"
+        status, info = _go_proxy_fetch(url, logger)
+        if status != 200:
+            return status, None
+        try:
+            data = json.loads(info or "{}")
+        except ValueError:
+            return None, None
+        if not data.get("Time"):
+            return 404, None
+        return 200, {"version": data.get("Version") or version, "published_at": data["Time"]}
 
-    # Prioritize common repo URLs in project_urls
-    lowercase_urls = _pypi_lowercase_urls(info.get("project_urls") or {}, logger, package_name)
+    return _walk_go_proxy_chain(query, goproxy)
 
-    # Prefer explicit repo/source keys first
-    preferred = _pypi_preferred_url(lowercase_urls)
-    if preferred:
-        return preferred
 
-    # Fallback to top-level home_page
-    homepage = _pypi_home_page(info)
-    if homepage:
-        return homepage
+def fetch_go_version_origin(module_path, version, logger=None, goproxy=None):
+    """
+    Fetch the repository one module version came from, as the module proxy recorded it
 
-    # As a final attempt, scan any project_urls value for a GitHub/GitLab link
-    any_repo = _pypi_find_any_repo_in_urls(lowercase_urls)
-    if any_repo:
-        return any_repo
+    Proxies running Go 1.21 or later add an `Origin` object ({"VCS", "URL",
+    ["Subdir"], ["Ref"], ["Hash"]}) to `@v/<version>.info`; older ones leave it out
 
-    # Targeted fallbacks for well-known projects missing metadata on PyPI
-    special = {
-        "vispy": "https://github.com/vispy/vispy",
-    }
-    if package_name in special:
-        return special[package_name]
+    Args:
+        module_path (str): Go module path
+        version (str): Exact module version, tagged or pseudo-version
+        logger (Logger): Optional logger instance
+        goproxy (str): Optional GOPROXY override; defaults to the environment
 
-    return None
+    Returns:
+        tuple: ({"vcs", "url", ["subdir"], ["ref"], ["hash"]} or None, reason_or_None) with the reasons of
+            fetch_go_proxy_metadata, or "origin-missing" when the proxy answered without a version
+            control Origin
+    """
+    if is_go_private_module(module_path):
+        return None, "private-module-skipped"
+
+    def query(proxy):
+        url = f"{proxy}/{_go_proxy_escape(module_path)}/@v/{_go_proxy_escape(version)}.info"
+        status, info = _go_proxy_fetch(url, logger)
+        if status != 200:
+            return status, None
+        try:
+            data = json.loads(info or "{}")
+        except ValueError:
+            return None, None
+        origin = data.get("Origin") if isinstance(data, dict) else None
+        if not isinstance(origin, dict) or origin.get("VCS") not in _GO_IMPORT_VCS or not origin.get("URL"):
+            return 200, {}
+        found = {"vcs": origin["VCS"], "url": origin["URL"]}
+        for field, key in (("Subdir", "subdir"), ("Ref", "ref"), ("Hash", "hash")):
+            if origin.get(field):
+                found[key] = origin[field]
+        return 200, found
+
+    origin, reason = _walk_go_proxy_chain(query, goproxy)
+    if origin == {}:
+        return None, "origin-missing"
+    if origin:
+        logger and logger.debug(f"Go proxy reports {module_path}@{version} from {origin['url']}")
+    return origin, reason
+
+
+def fetch_go_mod(module_path, version, logger=None, goproxy=None):
+    """
+    Fetch the go.mod of one module version from the module proxy (`@v/<version>.mod`)
 
+    Args:
+        module_path (str): Go module path
+        version (str): Exact module version
+        logger (Logger): Optional logger instance
+        goproxy (str): Optional GOPROXY override; defaults to the environment
 
-def _cargo_fetch_metadata(package_name, logger=None):
+    Returns:
+        tuple: (go.mod text or None, reason_or_None) with the reasons of fetch_go_proxy_metadata
     """
-    Fetch crates.io metadata JSON using _make_request
+    if is_go_private_module(module_path):
+        return None, "private-module-skipped"
 
-    Args:
-        package_name (str): Crate name
-        logger: Optional logger
+    def query(proxy):
+        url = f"{proxy}/{_go_proxy_escape(module_path)}/@v/{_go_proxy_escape(version)}.mod"
+        status, content = _go_proxy_fetch(url, logger)
+        return status, content if status == 200 and content is not None else None
+
+    return _walk_go_proxy_chain(query, goproxy)
+
+
+def _github_reset_wait(headers, now):
+    """
+    Return the seconds until a used-up primary GitHub rate limit resets
 
     Returns:
-        dict or None
+        float|None: Time to X-RateLimit-Reset when X-RateLimit-Remaining is 0, else None
     """
-    url = f"https://crates.io/api/v1/crates/{package_name}"
-    return _make_request(url, logger)
+    if headers.get("x-ratelimit-remaining") != "0":
+        return None
+    try:
+        return max(0.0, float(headers.get("x-ratelimit-reset", "")) - now)
+    except ValueError:
+        return None
 
 
-def _cargo_from_repository(crate):
+def github_rate_limit_delay(status, headers, attempt, now=None):
     """
-    Return cleaned 'repository' URL if present
+    Return how long GitHub asks a rate-limited response to wait before retrying
+
+    `Retry-After` (seconds or an HTTP date) comes first; then, once the primary
+    limit is used up (`X-RateLimit-Remaining: 0`), the time until `X-RateLimit-Reset`.
+    Other 403 and 429 responses are taken for secondary limits, which GitHub asks
+    to wait out for at least a minute, longer for each further attempt
 
     Args:
-        crate (dict): Crate metadata
+        status (int|None): HTTP status
+        headers (dict): Lowercase response header name -> value (see response_headers)
+        attempt (int): 0-based number of the attempt that got the response
+        now (float): Current time in epoch seconds (default: time.time())
 
     Returns:
-        str or None
+        float|None: Seconds to wait, or None when the response is not rate-limited
     """
-    return _clean_repo_url(crate.get("repository")) if crate else None
+    if status not in (403, 429):
+        return None
+    now = time.time() if now is None else now
+    retry_after = headers.get("retry-after")
+    if retry_after is not None:
+        try:
+            return max(0.0, float(retry_after))
+        except ValueError:
+            try:
+                return max(0.0, email.utils.parsedate_to_datetime(retry_after).timestamp() - now)
+            except (TypeError, ValueError):
+                pass
+    reset_wait = _github_reset_wait(headers, now)
+    if reset_wait is not None:
+        return reset_wait
+    return GITHUB_SECONDARY_LIMIT_DELAY * (2**attempt)
 
 
-def _cargo_from_homepage(crate):
+def _hold_github_requests(until):
     """
-    Return cleaned 'homepage' URL if it matches anchored owner/repo regex
+    Keep every thread's GitHub API requests back until an epoch time GitHub named
+    """
+    with _GITHUB_BLOCKED_LOCK:
+        _GITHUB_BLOCKED_UNTIL["at"] = max(_GITHUB_BLOCKED_UNTIL["at"], until)
+
+
+def github_api_get(url, logger=None):
+    """
+    GET a GitHub REST API URL, sitting out its primary and secondary rate limits
+
+    Requests carry the auth token (see auth_token_for), which raises the hourly
+    limit from 60 to 5,000 requests. A rate-limited response (see
+    github_rate_limit_delay) is retried after the wait GitHub asks for, up to
+    NetworkConfig.MAX_RETRIES times, unless the wait exceeds
+    NetworkConfig.GITHUB_RATE_LIMIT_MAX_WAIT. A wait GitHub names in its headers,
+    or a limit a successful response reports used up, holds back the GitHub
+    requests of every thread until it ends, and requests due while a longer wait
+    is pending are given up unsent, so a used-up limit is not hammered.
+    Connection errors and 5xx responses are retried as by _http_get_with_retries
 
     Args:
-        crate (dict): Crate metadata
+        url (str): Validated api.github.com URL
+        logger: Optional logger
 
     Returns:
-        str or None
-    """
-    if not crate:
-        return None
-    homepage = _clean_repo_url(crate.get("homepage"))
-    if homepage and ("github.com" in homepage or "gitlab.com" in homepage):
-        if re.match(r"https?://(?:www\.)?(?:github|gitlab)\.com/[^/]+/[^/]+/?$", homepage):
-            return homepage
-    return None
+        tuple: (status_code_or_None, text_or_None, reason_or_None) of the last attempt; reason is
+            "github-rate-limited" when the limit did not lift in time, "resolution-deadline-exceeded"
+            or "registry-blocked"
+    """
+    if _request_blocked(url, logger):
+        return None, None, "registry-blocked"
+    max_retries = max(0, NetworkConfig.MAX_RETRIES)
+    max_wait = NetworkConfig.GITHUB_RATE_LIMIT_MAX_WAIT
+    for attempt in range(max_retries + 1):
+        with _GITHUB_BLOCKED_LOCK:
+            pending = _GITHUB_BLOCKED_UNTIL["at"] - time.time()
+        if pending > max_wait:
+            logger and logger.debug(
+                f"Not requesting {url}: GitHub rate limit lifts in {pending:.0f}s",
+                event="github-rate-limited",
+                url=url,
+                wait=round(pending, 2),
+            )
+            return None, None, "github-rate-limited"
+        if pending > 0:
+            _sleep_until_deadline(pending)
+
+        status, text = _http_get(url, logger)
+        headers = response_headers()
+        now = time.time()
+        delay = github_rate_limit_delay(status, headers, attempt, now)
+        if delay is None:
+            reset_wait = _github_reset_wait(headers, now)
+            if reset_wait:
+                _hold_github_requests(now + reset_wait)
+            if not is_retryable(status):
+                return status, text, None
+            if deadline_exceeded():
+                return status, text, "resolution-deadline-exceeded"
+            if attempt == max_retries:
+                _ATTEMPTS.failures = request_failures() + 1
+                return status, text, None
+            _sleep_until_deadline(retry_delay(attempt))
+            continue
+
+        named = "retry-after" in headers or _github_reset_wait(headers, now) is not None
+        if named:
+            _hold_github_requests(now + delay)
+        if delay > max_wait or attempt == max_retries:
+            logger and logger.debug(
+                f"GitHub rate limit for {url} (HTTP {status}) asks for {delay:.0f}s; giving up",
+                event="github-rate-limited",
+                url=url,
+                status=status,
+                wait=round(delay, 2),
+            )
+            return status, None, "github-rate-limited"
+        logger and logger.debug(
+            f"GitHub rate limit for {url} (HTTP {status}); retrying in {delay:.0f}s",
+            event="github-rate-limit-wait",
+            url=url,
+            status=status,
+            attempt=attempt + 1,
+            delay=round(delay, 2),
+        )
+        # A named wait is sat out with the other threads' at the top of the loop
+        if not named:
+            _sleep_until_deadline(delay)
+    return status, text, None
 
 
-def _cargo_from_documentation(crate):
+def probe_url(url, logger=None):
     """
-    Infer repo from GitHub/GitLab Pages documentation URL patterns
+    GET a resolver backend URL as its lookups would, for a connectivity check
+
+    Registry URLs are validated like registry lookups and sent through the same
+    request slots, retries and allow/deny lists; api.github.com URLs go through
+    github_api_get and its rate-limit handling
 
     Args:
-        crate (dict): Crate metadata
+        url (str): https URL of a known query on the backend
+        logger: Optional logger
 
     Returns:
-        str or None
-    """
-    if not crate:
-        return None
-    documentation = crate.get("documentation")
-    if documentation and isinstance(documentation, str):
-        match_gh_pages = _RE_GH_PAGES.match(documentation)
-        if match_gh_pages:
-            user, repo = match_gh_pages.groups()
-            return f"https://github.com/{user}/{repo}"
-        match_gl_pages = _RE_GL_PAGES.match(documentation)
-        if match_gl_pages:
-            user, repo = match_gl_pages.groups()
-            return f"https://gitlab.com/{user}/{repo}"
-    return None
+        dict: {"status": int|None, "reason": str|None, "seconds": float, "attempts": int,
+            "token_sent": bool, "headers": dict}; reason is "registry-blocked", "invalid-url" or
+            "github-rate-limited" when no usable response was received
+    """
+    reset_request_attempts()
+    started = time.perf_counter()
+    reason = None
+    if urllib.parse.urlsplit(url).hostname in GITHUB_AUTH_HOSTS:
+        status, _, reason = github_api_get(url, logger)
+    elif _validate_or_none(url, logger) is None:
+        status, reason = None, "invalid-url"
+    elif _request_blocked(url, logger):
+        status, reason = None, "registry-blocked"
+    else:
+        status, _ = _http_get_with_retries(url, logger)
+    return {
+        "status": status,
+        "reason": reason,
+        "seconds": round(time.perf_counter() - started, 3),
+        "attempts": request_attempts(),
+        "token_sent": auth_token_for(url) is not None,
+        "headers": response_headers() if request_attempts() else {},
+    }
 
 
-def resolve_cargo_package(package_name, logger=None):
+def fetch_repository_license(repo_url, logger=None):
     """
-    Resolve Cargo crate to repository URL
+    Look up the SPDX license identifier of a repository from the GitHub license endpoint
 
     Args:
-        package_name (str): The Cargo crate name to resolve
+        repo_url (str): Resolved repository URL
         logger (Logger): Optional logger instance
 
     Returns:
-        Repository URL string or None if not found
-    """
-    data = _cargo_fetch_metadata(package_name, logger)  # User-Agent is handled by helper
+        tuple: (SPDX identifier or None, reason_or_None) where reason is one of "unsupported-host"
+            (not a GitHub repository), "license-not-found" (GitHub detected no license file),
+            "unrecognized-license" (a license file GitHub could not match to an SPDX identifier),
+            "github-rate-limited" (see github_api_get), "github-unreachable" or "registry-blocked"
+    """
+    match = _RE_GH_CANONICAL.match(repo_url or "")
+    if not match:
+        return None, "unsupported-host"
+    owner_repo = match.group(1).split("github.com/", 1)[1]
+    url = _validate_or_none(f"https://api.github.com/repos/{owner_repo}/license", logger)
+    if url is None:
+        return None, "unsupported-host"
+
+    status, text, reason = github_api_get(url, logger)
+    if reason:
+        return None, reason
+    if status == 404:
+        return None, "license-not-found"
+    if status != 200:
+        return None, "github-unreachable"
+    try:
+        license_info = json.loads(text).get("license") or {}
+    except (ValueError, AttributeError):
+        return None, "github-unreachable"
+    spdx_id = license_info.get("spdx_id") if isinstance(license_info, dict) else None
+    if not spdx_id or spdx_id == "NOASSERTION":
+        return None, "unrecognized-license"
+    logger and logger.debug(f"GitHub reports license {spdx_id} for {owner_repo}")
+    return spdx_id, None
 
-    if data:
-        crate = data.get("crate", {})
 
-        # 1. Try repository field
-        repository = _cargo_from_repository(crate)
-        if repository:
-            return repository
+def resolve_licenses(packages_dict, logger=None, offline=False, cancellation=None):
+    """
+    Attach the license of each package's repository
 
-        # 2. Try homepage field
-        homepage = _cargo_from_homepage(crate)
-        if homepage:
-            return homepage
+    Every package gets a `license` field: the SPDX identifier with `license_source`
+    "github-api", or None with a `license_reason` ("offline-skipped", "no-repository-url",
+    or a reason from fetch_repository_license). Each repository is looked up once,
+    on up to resolver_concurrency() worker threads sharing the request slots
 
-        # 3. Try to infer from documentation URL (less reliable)
-        inferred = _cargo_from_documentation(crate)
-        if inferred:
-            return inferred
+    Args:
+        packages_dict (dict): Package metadata keyed by name, with `repository_url` resolved
+        logger (Logger): Optional logger instance
+        offline (bool): Record "offline-skipped" instead of making requests
+        cancellation (CancellationToken): Optional token; once cancelled, repositories not yet
+            looked up get the reason "cancelled"
 
-    return None
+    Returns:
+        dict: packages_dict, updated in place
+    """
+    repo_urls = []
+    for package_data in packages_dict.values():
+        repo_url = _extract_github_owner_repo(package_data.get("repository_url") or "")
+        if repo_url and repo_url not in repo_urls:
+            repo_urls.append(repo_url)
+
+    def _fetch(repo_url):
+        if is_cancelled(cancellation):
+            return None, "cancelled"
+        return fetch_repository_license(repo_url, logger)
+
+    licenses = {}
+    if not offline and repo_urls:
+        workers = min(resolver_concurrency(), len(repo_urls))
+        if workers <= 1:
+            results = [_fetch(repo_url) for repo_url in repo_urls]
+        else:
+            with ThreadPoolExecutor(max_workers=workers, thread_name_prefix="gardener-license") as executor:
+                results = list(executor.map(_fetch, repo_urls))
+            close_exited_connections()
+        licenses = dict(zip(repo_urls, results))
+
+    for package_data in packages_dict.values():
+        repo_url = _extract_github_owner_repo(package_data.get("repository_url") or "")
+        if not repo_url:
+            spdx_id, reason = None, "no-repository-url"
+        elif offline:
+            spdx_id, reason = None, "offline-skipped"
+        else:
+            spdx_id, reason = licenses[repo_url]
+        package_data["license"] = spdx_id
+        if spdx_id:
+            package_data["license_source"] = "github-api"
+        else:
+            package_data["license_reason"] = reason
+    return packages_dict
 
 
-def _go_direct_repo_from_path(package_name):
+def fetch_default_branch(repo_url, logger=None):
     """
-    Infer https URL from import path containing github.com/ or gitlab.com/
+    Look up the default branch of a repository from the GitHub repository endpoint
 
     Args:
-        package_name (str): Go import path
+        repo_url (str): Resolved repository URL
+        logger (Logger): Optional logger instance
 
     Returns:
-        str or None: 'https://<host>/<org>/<repo>' or None
-    """
-    if "github.com/" in package_name or "gitlab.com/" in package_name:
-        parts = package_name.split("/")
-        if len(parts) >= 3:
-            return f"https://{parts[0]}/{parts[1]}/{parts[2]}"
-    return None
+        tuple: (branch name or None, reason_or_None) where reason is one of "unsupported-host"
+            (not a GitHub repository), "repository-not-found" (GitHub does not know it, or it is
+            private), "github-rate-limited" (see github_api_get), "github-unreachable" or "registry-blocked"
+    """
+    match = _RE_GH_CANONICAL.match(repo_url or "")
+    if not match:
+        return None, "unsupported-host"
+    owner_repo = match.group(1).split("github.com/", 1)[1]
+    url = _validate_or_none(f"https://api.github.com/repos/{owner_repo}", logger)
+    if url is None:
+        return None, "unsupported-host"
+
+    status, text, reason = github_api_get(url, logger)
+    if reason:
+        return None, reason
+    if status == 404:
+        return None, "repository-not-found"
+    if status != 200:
+        return None, "github-unreachable"
+    try:
+        branch = json.loads(text).get("default_branch")
+    except (ValueError, AttributeError):
+        return None, "github-unreachable"
+    if not isinstance(branch, str) or not branch:
+        return None, "github-unreachable"
+    logger and logger.debug(f"GitHub reports default branch {branch} for {owner_repo}")
+    return branch, None
 
 
-def _go_meta_tag_fetch_url(package_name):
+def fetch_github_commit(repo_url, revision, logger=None):
     """
-    Return 'https://{package_name}?go-get=1'
+    Look up a commit of a GitHub repository by its (abbreviated) SHA
 
     Args:
-        package_name (str): Go import path
+        repo_url (str): Resolved repository URL
+        revision (str): Commit SHA or prefix, e.g. the 12 characters a Go pseudo-version records
+        logger (Logger): Optional logger instance
 
     Returns:
-        str: URL to fetch meta tags
-    """
-    return f"https://{package_name}?go-get=1"
+        tuple: ({"sha", "date"} or None, reason_or_None) where sha is the full commit SHA and date its
+            committer date, and reason is one of "unsupported-host" (not a GitHub repository),
+            "commit-not-found" (the repository has no commit with that prefix), "repository-not-found",
+            "github-rate-limited" (see github_api_get), "github-unreachable" or "registry-blocked"
+    """
+    match = _RE_GH_CANONICAL.match(repo_url or "")
+    if not match or not re.fullmatch(r"[0-9a-f]{4,40}", revision or ""):
+        return None, "unsupported-host"
+    owner_repo = match.group(1).split("github.com/", 1)[1]
+    url = _validate_or_none(f"https://api.github.com/repos/{owner_repo}/commits/{revision}", logger)
+    if url is None:
+        return None, "unsupported-host"
+
+    status, text, reason = github_api_get(url, logger)
+    if reason:
+        return None, reason
+    if status == 422:
+        # GitHub's answer for "No commit found for SHA"
+        return None, "commit-not-found"
+    if status == 404:
+        return None, "repository-not-found"
+    if status != 200:
+        return None, "github-unreachable"
+    try:
+        data = json.loads(text)
+        sha = data.get("sha")
+        date = ((data.get("commit") or {}).get("committer") or {}).get("date")
+    except (ValueError, AttributeError):
+        return None, "github-unreachable"
+    if not isinstance(sha, str) or not sha.startswith(revision):
+        # The revision named a branch or tag rather than a commit
+        return None, "commit-not-found"
+    logger and logger.debug(f"GitHub reports commit {sha} for {revision} in {owner_repo}")
+    return {"sha": sha, "date": date if isinstance(date, str) else None}, None
 
 
-def _go_meta_tag_repo_url(fetch_url, logger=None):
+def resolve_default_branches(packages_dict, logger=None, offline=False, cancellation=None, resolution_cache=None):
     """
-    Request page and extract repo URL from go-import meta tag using _RE_GO_IMPORT_META
-    Return cleaned URL or None
+    Attach the default branch of each package's GitHub repository
+
+    Packages resolved to a GitHub repository get `default_branch`; when it cannot
+    be determined the field is left out and `default_branch_reason` says why
+    ("offline-skipped", or a reason from fetch_default_branch), rather than
+    assuming "main". Packages without a GitHub repository get neither. Each
+    repository is looked up once, on up to resolver_concurrency() worker threads
 
     Args:
-        fetch_url (str): Validated URL to fetch
-        logger: Optional logger
+        packages_dict (dict): Package metadata keyed by name, with `repository_url` resolved
+        logger (Logger): Optional logger instance
+        offline (bool): Record "offline-skipped" instead of making requests; cached branches are still used
+        cancellation (CancellationToken): Optional token; once cancelled, repositories not yet
+            looked up get the reason "cancelled"
+        resolution_cache (ResolutionCache): Optional cache of branches from earlier runs, updated in place
 
     Returns:
-        str or None
-    """
-    # Use hook if present to fetch HTML content
-    if _REQUEST_FN is not None:
-        try:
-            raw = _REQUEST_FN(fetch_url)
-            if raw is None:
-                return None
-            if isinstance(raw, bytes):
-                content = raw.decode("utf-8", errors="ignore")
-            else:
-                content = str(raw)
-            match = _RE_GO_IMPORT_META.search(content)
-            if match:
-                repo_url = _clean_repo_url(match.group(3))
-                if repo_url:
-                    return repo_url
-            return None
-        except Exception:
-            return None
+        int: Number of packages that got a default_branch
+    """
+    repo_urls = []
+    for package_data in packages_dict.values():
+        repo_url = _extract_github_owner_repo(package_data.get("repository_url") or "")
+        if _RE_GH_CANONICAL.match(repo_url or "") and repo_url not in repo_urls:
+            repo_urls.append(repo_url)
+
+    branches = {}
+    pending = []
+    for repo_url in repo_urls:
+        cached = resolution_cache.lookup_default_branch(repo_url) if resolution_cache else None
+        if cached:
+            branches[repo_url] = (cached, None)
+        elif offline:
+            branches[repo_url] = (None, "offline-skipped")
+        else:
+            pending.append(repo_url)
 
-    req = urllib.request.Request(fetch_url, headers={"User-Agent": USER_AGENT})
-    with urllib.request.urlopen(req, timeout=REQUEST_TIMEOUT) as response:
-        if response.status == 200:
-            content = response.read().decode("utf-8", errors="ignore")
-            match = _RE_GO_IMPORT_META.search(content)
-            if match:
-                repo_url = _clean_repo_url(match.group(3))
-                if repo_url:
-                    return repo_url
-    return None
+    def _fetch(repo_url):
+        if is_cancelled(cancellation):
+            return None, "cancelled"
+        return fetch_default_branch(repo_url, logger)
+
+    if pending:
+        workers = min(resolver_concurrency(), len(pending))
+        if workers <= 1:
+            results = [_fetch(repo_url) for repo_url in pending]
+        else:
+            with ThreadPoolExecutor(max_workers=workers, thread_name_prefix="gardener-branch") as executor:
+                results = list(executor.map(_fetch, pending))
+            close_exited_connections()
+        for repo_url, (branch, reason) in zip(pending, results):
+            branches[repo_url] = (branch, reason)
+            if branch and resolution_cache is not None:
+                resolution_cache.store_default_branch(repo_url, branch)
+
+    found = 0
+    for package_data in packages_dict.values():
+        repo_url = _extract_github_owner_repo(package_data.get("repository_url") or "")
+        if repo_url not in branches:
+            continue
+        branch, reason = branches[repo_url]
+        if branch:
+            package_data["default_branch"] = branch
+            found += 1
+        else:
+            package_data["default_branch_reason"] = reason
+    return found
 
 
-def resolve_go_package(package_name, logger=None):
+def resolve_github_action(package_name, logger=None, receipt=None):
     """
-    Resolve Go package to repository URL
+    Resolve a GitHub Actions action or reusable workflow to its repository
+
+    `uses: owner/repo/path@ref` always names a GitHub repository, so no lookup is
+    needed; a path below the repository root is recorded as receipt["repository_subpath"]
 
     Args:
-        package_name (str): The Go package name to resolve
+        package_name (str): Action without its ref, e.g. "github/codeql-action/init"
         logger (Logger): Optional logger instance
+        receipt (dict): Optional dictionary receiving the "source" of the resolved URL
 
     Returns:
-        Repository URL string or None if not found
+        Repository URL string or None for malformed names
     """
-    # For Go packages, the import path often IS the repo URL path
-    direct = _go_direct_repo_from_path(package_name)
-    if direct:
-        return direct
-
-    # Attempting the go-get=1 meta tag approach
-    try:
-        fetch_url = _go_meta_tag_fetch_url(package_name)
-
-        # Validate URL for security
-        if SECURITY_AVAILABLE:
-            try:
-                validated_url = InputValidator.validate_url(fetch_url, allowed_schemes={"https"})
-                fetch_url = validated_url
-            except ValidationError as e:
-                logger and logger.debug(f"Go package URL validation failed: {fetch_url} - {e}")
-                return None
-
-        inferred = _go_meta_tag_repo_url(fetch_url, logger)
-        if inferred:
-            return inferred
-    except Exception as e:
-        logger and logger.debug(f"Go package lookup via 'go-get=1' failed for {package_name}: {e}")
-        pass
-
-    return None  # If direct URL and go-get meta tag failed
+    receipt = receipt if receipt is not None else {}
+    parts = package_name.split("/")
+    if len(parts) < 2 or not all(parts):
+        logger and logger.debug(f"Not an owner/repo action reference: {package_name}")
+        return None
+    receipt["source"] = "action-reference"
+    if len(parts) > 2:
+        receipt["repository_subpath"] = "/".join(parts[2:])
+    return f"https://github.com/{parts[0]}/{parts[1]}"
 
 
-def resolve_solidity_contract(package_name, source=None, logger=None):
+def resolve_solidity_contract(package_name, source=None, logger=None, receipt=None):
     """
     Resolve Solidity contract/library to repository URL
 
@@ -995,15 +3193,20 @@ def resolve_solidity_contract(package_name, source=None, logger=None):
         package_name (str): The Solidity package name to resolve
         source (str): Optional source URL hint
         logger (Logger): Optional logger instance
+        receipt (dict): Optional dictionary receiving the "source" of the resolved URL
 
     Returns:
         Repository URL string or None if not found
     """
+    receipt = receipt if receipt is not None else {}
+
     # If source is provided (e.g., from foundry.toml), use it
     # 1. Use source hint if provided and valid
     if source:
         cleaned_source = _clean_repo_url(source)
         if cleaned_source and ("github.com" in cleaned_source or "gitlab.com" in cleaned_source):
+            receipt.pop("reason", None)
+            receipt["source"] = "source-hint"
             return cleaned_source
 
     # 2. Placeholder for future Etherscan/Sourcegraph/etc. API integration
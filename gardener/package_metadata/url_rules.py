@@ -0,0 +1,159 @@
+"""
+Custom repository URL rules, tried before any resolver
+
+Private packages often follow a host-specific naming convention that no
+registry knows, e.g. `code.internal.example/team/x` living at
+`https://bitbucket.internal.example/projects/team/repos/x`. A rule pairs a
+regular expression with a URL template: the expression is matched from the
+start of the package name and the template is filled in with str.format
+fields, `{0}` for the matched text, `{1}`, `{2}`, ... for its groups and
+`{name}` for named groups. Rules are tried in order and the first match wins;
+the programmatic API may also register callables fn(ecosystem, name, version)
+returning a URL or None
+"""
+
+import json
+import re
+import string
+
+from gardener.common.defaults import NetworkConfig
+
+
+class UrlRule:
+    """
+    One compiled pattern -> URL template rule
+
+    Args:
+        pattern (str): Regular expression matched against the start of package names
+        url (str): URL template (see module docstring)
+        ecosystem (str): Optional ecosystem the rule is limited to, e.g. "go"
+
+    Raises:
+        ValueError: If the pattern does not compile or the template names a group the pattern lacks
+    """
+
+    def __init__(self, pattern, url, ecosystem=None):
+        try:
+            self.regex = re.compile(pattern)
+        except re.error as e:
+            raise ValueError(f"URL rule pattern {pattern!r}: {e}") from None
+        self.pattern = pattern
+        self.url = url
+        self.ecosystem = ecosystem or None
+        for _, field_name, _, _ in string.Formatter().parse(url):
+            if field_name is None:
+                continue
+            if field_name.isdigit():
+                known = int(field_name) <= self.regex.groups
+            else:
+                known = field_name in self.regex.groupindex
+            if not known:
+                raise ValueError(f"URL rule template {url!r} uses {{{field_name}}}, but {pattern!r} has no such group")
+
+    def apply(self, ecosystem, name):
+        """
+        Return the URL the rule maps a package to
+
+        Args:
+            ecosystem (str): Package ecosystem
+            name (str): Package name
+
+        Returns:
+            str|None: Filled-in template, or None when the rule does not match
+        """
+        if self.ecosystem and self.ecosystem != ecosystem:
+            return None
+        match = self.regex.match(name)
+        if not match:
+            return None
+        groups = [match.group(0)] + [group or "" for group in match.groups()]
+        named = {key: value or "" for key, value in match.groupdict().items()}
+        return self.url.format(*groups, **named)
+
+
+def parse_url_rule(value):
+    """
+    Build a rule from its --url-rule or URL_RULES form
+
+    Args:
+        value (str|dict): "PATTERN URL" (split at the last whitespace), or {"pattern", "url", ["ecosystem"]}
+            as an object or its JSON text
+
+    Returns:
+        UrlRule
+
+    Raises:
+        ValueError: If the value has neither form or the rule is invalid
+    """
+    if isinstance(value, str) and value.lstrip().startswith("{"):
+        try:
+            value = json.loads(value)
+        except ValueError as e:
+            raise ValueError(f"URL rule {value!r}: {e}") from None
+    if isinstance(value, dict):
+        unknown = set(value) - {"pattern", "url", "ecosystem"}
+        if unknown or not value.get("pattern") or not value.get("url"):
+            raise ValueError(f"URL rule {value!r} needs exactly 'pattern', 'url' and optionally 'ecosystem'")
+        return UrlRule(value["pattern"], value["url"], value.get("ecosystem"))
+    parts = str(value).strip().rsplit(None, 1)
+    if len(parts) != 2:
+        raise ValueError(f"URL rule {value!r} must look like 'PATTERN URL'")
+    return UrlRule(*parts)
+
+
+def compile_url_rules(rules):
+    """
+    Build the rules of a rule list, callables left as they are
+
+    Args:
+        rules (list): Rules in any form parse_url_rule accepts, UrlRule objects and callables
+
+    Returns:
+        list: UrlRule objects and callables, in order
+
+    Raises:
+        ValueError: If a rule is invalid
+    """
+    return [rule if callable(rule) or isinstance(rule, UrlRule) else parse_url_rule(rule) for rule in rules]
+
+
+def configured_url_rules():
+    """
+    Return the compiled rules of NetworkConfig.URL_RULES
+
+    Returns:
+        list: UrlRule objects and callables, in order
+
+    Raises:
+        ValueError: If a rule is invalid
+    """
+    return compile_url_rules(NetworkConfig.URL_RULES)
+
+
+def match_url_rules(rules, ecosystem, name, version=None, logger=None):
+    """
+    Return the URL of the first rule matching a package
+
+    Args:
+        rules (list): UrlRule objects and callables fn(ecosystem, name, version) -> URL or None
+        ecosystem (str): Package ecosystem
+        name (str): Package name (for replaced Go modules, the replacement path)
+        version (str): Optional package version
+        logger (Logger): Optional logger; callables that raise are reported and skipped
+
+    Returns:
+        tuple: (url, rule description) or (None, None); the description is the pattern, or the callable's name
+    """
+    for rule in rules:
+        if isinstance(rule, UrlRule):
+            url, description = rule.apply(ecosystem, name), rule.pattern
+        else:
+            description = getattr(rule, "__name__", repr(rule))
+            try:
+                url = rule(ecosystem, name, version)
+            except Exception as e:
+                logger and logger.warning(f"URL rule {description} failed for {name}: {e}")
+                continue
+        if url:
+            return url, description
+    return None, None
@@ -5,6 +5,7 @@ File system implementation of the persistence interface
 import json
 import os
 
+from gardener.common.compression import GZIP_SUFFIX, open_text_output
 from gardener.common.utils import Logger
 from gardener.persistence.interface import PersistenceInterface
 
@@ -13,17 +14,23 @@ class FilePersistence(PersistenceInterface):
     """
     File system implementation of the persistence interface
 
-    This maintains backward compatibility with the original file-based output
+    This maintains backward compatibility with the original file-based output.
+    With compress, every file but the graph visualization is gzipped and named ".gz"
     """
 
-    def __init__(self, output_dir="output", verbose=True):
+    # Written uncompressed even with compress, to stay viewable in a browser
+    UNCOMPRESSED_SUFFIXES = ("_dependency_graph.html",)
+
+    def __init__(self, output_dir="output", verbose=True, compress=False):
         """
         Args:
             output_dir (str): Directory where files will be saved
             verbose (bool): Enable verbose logging
+            compress (bool): Gzip the analysis JSON, package list, CSV, SBOM, Markdown report and NDJSON files
         """
         self.output_dir = output_dir
         self.logger = Logger(verbose=verbose)
+        self.compress = compress
         # Ensure output directory exists
         os.makedirs(self.output_dir, exist_ok=True)
 
@@ -31,7 +38,7 @@ class FilePersistence(PersistenceInterface):
         """Save analysis results as JSON file"""
         output_path = self.get_output_path(identifier, "_dependency_analysis.json")
 
-        with open(output_path, "w", encoding="utf-8") as f:
+        with open_text_output(output_path) as f:
             json.dump(results, f, indent=2, default=str)
 
         self.logger.info(f"\nAnalysis results saved to: {output_path}")
@@ -45,12 +52,55 @@ class FilePersistence(PersistenceInterface):
 
         self.logger.info(f"Interactive dependency graph saved to: {output_path}")
 
+    def save_sbom(self, document, identifier, suffix):
+        """Save an SBOM document as JSON file"""
+        output_path = self.get_output_path(identifier, suffix)
+
+        with open_text_output(output_path) as f:
+            json.dump(document, f, indent=2)
+
+        self.logger.info(f"SBOM saved to: {output_path}")
+
+    def save_packages(self, packages, identifier, suffix):
+        """Save the package list as JSON file"""
+        output_path = self.get_output_path(identifier, suffix)
+
+        with open_text_output(output_path) as f:
+            json.dump(packages, f, indent=2)
+
+        self.logger.info(f"\nPackage list saved to: {output_path}")
+
+    def save_csv(self, content, identifier, suffix):
+        """Save a CSV or TSV export, keeping its CRLF record terminators intact"""
+        output_path = self.get_output_path(identifier, suffix)
+
+        with open_text_output(output_path, newline="") as f:
+            f.write(content)
+
+        self.logger.info(f"Dependency table saved to: {output_path}")
+
+    def save_report(self, content, identifier, suffix):
+        """Save a Markdown (or other text) report"""
+        output_path = self.get_output_path(identifier, suffix)
+
+        with open_text_output(output_path) as f:
+            f.write(content)
+
+        self.logger.info(f"Dependency report saved to: {output_path}")
+
+    def open_stream(self, identifier, suffix):
+        """Open a line-buffered file for incrementally written output"""
+        output_path = self.get_output_path(identifier, suffix)
+        return open_text_output(output_path, newline="\n", line_buffering=True)
+
     def get_output_path(self, identifier, suffix):
-        """Get the full file path for a given identifier and suffix"""
+        """Get the full file path for a given identifier and suffix, ending in ".gz" for compressed files"""
         # Handle cases where identifier already includes 'output/' prefix
         if identifier.startswith("output/"):
             base_path = identifier
         else:
             base_path = os.path.join(self.output_dir, identifier)
 
+        if self.compress and suffix not in self.UNCOMPRESSED_SUFFIXES:
+            suffix += GZIP_SUFFIX
         return f"{base_path}{suffix}"
@@ -35,6 +35,68 @@ class PersistenceInterface(ABC):
         """
         pass
 
+    @abstractmethod
+    def save_sbom(self, document, identifier, suffix):
+        """
+        Save a software bill of materials document
+
+        Args:
+            document (dict): SBOM document (CycloneDX or SPDX JSON)
+            identifier (str): Unique identifier for this analysis
+            suffix (str): File suffix identifying the SBOM format (e.g., '_sbom.cdx.json')
+        """
+        pass
+
+    @abstractmethod
+    def save_packages(self, packages, identifier, suffix):
+        """
+        Save the compact package list of a --packages-only analysis
+
+        Args:
+            packages (list): {"ecosystem", "name", "version"} entries (see gardener.analysis.package_list)
+            identifier (str): Unique identifier for this analysis
+            suffix (str): File suffix of the list (e.g., '_packages.json')
+        """
+        pass
+
+    @abstractmethod
+    def save_csv(self, content, identifier, suffix):
+        """
+        Save a delimited-text export of the detected dependencies
+
+        Args:
+            content (str): CSV or TSV document
+            identifier (str): Unique identifier for this analysis
+            suffix (str): File suffix identifying the delimiter (e.g., '_dependencies.csv')
+        """
+        pass
+
+    @abstractmethod
+    def save_report(self, content, identifier, suffix):
+        """
+        Save a human-readable report of the analysis
+
+        Args:
+            content (str): Report text
+            identifier (str): Unique identifier for this analysis
+            suffix (str): File suffix identifying the report format (e.g., '_dependency_report.md')
+        """
+        pass
+
+    @abstractmethod
+    def open_stream(self, identifier, suffix):
+        """
+        Open a text stream for output written incrementally (e.g. NDJSON records)
+
+        Args:
+            identifier (str): Unique identifier for this analysis
+            suffix (str): File suffix identifying the stream format (e.g., '_dependencies.ndjson')
+
+        Returns:
+            Writable text stream usable as a context manager; closing it completes the output
+        """
+        pass
+
     @abstractmethod
     def get_output_path(self, identifier, suffix):
         """
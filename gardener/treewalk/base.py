@@ -2,7 +2,10 @@
 Base classes for tree traversal
 """
 
+import fnmatch
 from abc import ABC, abstractmethod
+from collections import defaultdict
+from pathlib import PurePath
 
 from gardener.common.file_helpers import read_file_content, safe_json_load
 
@@ -81,18 +84,88 @@ class TreeVisitor:
         return
 
 
+def _resolve_nothing(*args):
+    """
+    Local import resolver used when no LocalImportResolver is available
+    """
+    return None
+
+
+def syntax_error_detail(root_node):
+    """
+    Describe the first syntax error in a parse tree
+
+    Tree-sitter recovers from malformed input by inserting ERROR and missing
+    nodes instead of raising, flagging their ancestors with has_error
+
+    Args:
+        root_node (object): Tree-sitter root node with has_error set
+
+    Returns:
+        str: Location of the first error node, with 1-based line and column
+    """
+    node = root_node
+    while node.type != "ERROR" and not getattr(node, "is_missing", False):
+        child = next((c for c in node.children if c.has_error), None)
+        if child is None:
+            break
+        node = child
+    row, column = node.start_point
+    if getattr(node, "is_missing", False):
+        return f"missing {node.type} at line {row + 1}, column {column + 1}"
+    return f"syntax error at line {row + 1}, column {column + 1}"
+
+
+def is_manifest_name(path, manifest_names):
+    """
+    Return True when a file is one of an analyzer's manifests
+
+    Args:
+        path (str): File path or basename
+        manifest_names (Iterable[str]): Names from get_manifest_files; entries containing
+            "*" or "?" are shell-style patterns, e.g. "Dockerfile.*", and entries containing "/"
+            match the trailing path components one by one, e.g. ".github/workflows/*.yml"
+
+    Returns:
+        bool
+    """
+    parts = PurePath(path).parts
+    if not parts:
+        return False
+    if parts[-1] in manifest_names:
+        return True
+    for name in manifest_names:
+        if "*" not in name and "?" not in name and "/" not in name:
+            continue
+        pattern = name.split("/")
+        if len(pattern) <= len(parts) and all(
+            fnmatch.fnmatchcase(part, piece) for part, piece in zip(parts[-len(pattern) :], pattern)
+        ):
+            return True
+    return False
+
+
 class LanguageHandler(ABC):
     """
     Abstract base class defining the interface for language-specific handlers
 
-    This class will be extended as additional languages are supported
+    Handlers implement the Analyzer protocol (see treewalk.registry) on top of
+    tree-sitter: `extract` parses the file and delegates to `extract_imports`
     """
 
+    # Language key the handler is registered under; also the tree-sitter grammar name
+    language = None
+
+    # LocalImportResolver method that resolves this language's local imports, e.g. "resolve_go"
+    local_resolver_method = None
+
     @abstractmethod
     def get_manifest_files(self):
         """
         Return a list of manifest filenames for this language
 
+        Names may be shell-style patterns or end-of-path patterns, see is_manifest_name
+
         Returns:
             List of manifest filenames
         """
@@ -132,6 +205,84 @@ class LanguageHandler(ABC):
         """
         pass
 
+    def matches(self, path):
+        """
+        Return True when a file is source code of this handler's language
+
+        Args:
+            path (str): Repo-relative file path
+
+        Returns:
+            bool
+        """
+        # Lazy import to avoid circular import (language_detection imports the handlers)
+        from gardener.common.language_detection import filename_to_lang
+
+        return self.language is not None and filename_to_lang(path) == self.language
+
+    def extract_options(self, context, evidence):
+        """
+        Return extra keyword arguments for extract_imports
+
+        Args:
+            context (ExtractionContext): Context of the file being extracted
+            evidence (defaultdict): rel_path -> evidence entries, for handlers that record import evidence
+
+        Returns:
+            dict
+        """
+        return {}
+
+    def extract(self, path, context):
+        """
+        Parse a file with tree-sitter and extract its imports
+
+        External and local imports are reported through context.report_imports;
+        components and syntax errors are recorded on the context
+
+        Args:
+            path (str): Repo-relative file path
+            context (ExtractionContext): File content and resolvers
+
+        Returns:
+            list: Import evidence entries recorded by the handler, if any
+
+        Raises:
+            AnalyzerUnavailableError: If no parser is available for the language
+            ExtractionError: If import extraction fails on the parsed tree
+        """
+        from gardener.common.tsl import get_parser
+        from gardener.treewalk.registry import AnalyzerUnavailableError, ExtractionError
+
+        try:
+            parser = get_parser(self.language)
+        except Exception as exc:
+            raise AnalyzerUnavailableError(f"Failed to get parser for {self.language}: {exc}") from exc
+
+        tree = parser.parse(bytes(context.code, "utf-8"))
+
+        evidence = defaultdict(list)
+        options = self.extract_options(context, evidence)
+        resolver = _resolve_nothing
+        if self.local_resolver_method and context.local_resolver is not None:
+            resolver = getattr(context.local_resolver, self.local_resolver_method)
+        try:
+            external_imports, local_imports = self.extract_imports(
+                tree.root_node,
+                path,
+                context.components,
+                resolver,
+                logger=context.logger,
+                **options,
+            )
+        except Exception as exc:
+            raise ExtractionError(f"import extraction failed: {exc}") from exc
+
+        if tree.root_node.has_error:
+            context.syntax_errors.append(syntax_error_detail(tree.root_node))
+        context.report_imports(external_imports, local_imports)
+        return evidence.get(path, [])
+
     def get_file_extensions(self):
         """
         Get the file extensions supported by this language handler
@@ -167,7 +318,7 @@ class LanguageHandler(ABC):
         Returns:
             The content of the file as a string
         """
-        return read_file_content(file_path, secure_file_ops, encoding)
+        return read_file_content(file_path, secure_file_ops, encoding, logger=getattr(self, "logger", None))
 
     def safe_json_load(self, file_path, secure_file_ops=None):
         """
@@ -0,0 +1,469 @@
+"""
+Dockerfile analyzer: base images and packages installed by RUN instructions
+
+Dockerfiles are both manifests and source files. As manifests they declare
+`docker` ecosystem packages: images named by FROM ("base-image" scope) and,
+best-effort, packages installed with common package managers in RUN
+instructions ("installed-package" scope). As source files they report those
+same packages as their external imports, so they take part in the graph
+"""
+
+import fnmatch
+import json
+import posixpath
+import re
+import shlex
+
+from gardener.common.file_helpers import read_file_content
+
+# Dockerfile basenames, as shell-style patterns
+DOCKERFILE_NAMES = ("Dockerfile", "Containerfile", "Dockerfile.*", "*.Dockerfile", "*.dockerfile")
+
+# Registry assumed for image references without one
+DEFAULT_REGISTRY = "docker.io"
+
+_RE_VARIABLE = re.compile(r"\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(?::?-([^}]*))?\}|([A-Za-z_][A-Za-z0-9_]*))")
+_RE_COMMAND_SEPARATOR = re.compile(r"&&|\|\||[;|]")
+
+# Package manager -> (install subcommands, options that take a separate value)
+_INSTALLERS = {
+    "apt": (("install",), set("-o -t --target-release -c --config-file".split())),
+    "apk": (("add",), set("-X --repository -t --virtual -p --root --arch --keys-dir".split())),
+    "yum": (("install",), set("-c --config --enablerepo --disablerepo --setopt --releasever".split())),
+    "pip": (
+        ("install",),
+        set(
+            "-r --requirement -c --constraint -e --editable -i --index-url --extra-index-url -f --find-links "
+            "-t --target --prefix --root --trusted-host --platform --python-version --implementation --abi".split()
+        ),
+    ),
+    "npm": (("install", "i", "add"), set("--prefix --registry --cache --userconfig".split())),
+    "go": (("install",), set("-modfile -C".split())),
+    "cargo": (
+        ("install",),
+        set(
+            "--version --vers --git --branch --tag --rev --path --root --registry --index --features -F "
+            "--target --profile --bin --example -j --jobs".split()
+        ),
+    ),
+    "gem": (("install",), set("-v --version -s --source -i --install-dir -n --bindir".split())),
+}
+
+# Command names -> package manager key in _INSTALLERS
+_COMMAND_INSTALLERS = {
+    "apt-get": "apt",
+    "apt": "apt",
+    "apk": "apk",
+    "yum": "yum",
+    "dnf": "yum",
+    "microdnf": "yum",
+    "pip": "pip",
+    "pip3": "pip",
+    "npm": "npm",
+    "yarn": "npm",
+    "pnpm": "npm",
+    "go": "go",
+    "cargo": "cargo",
+    "gem": "gem",
+}
+
+# Command words that run the rest of the command line
+_COMMAND_PREFIXES = {"sudo", "env", "exec", "time", "nohup"}
+
+
+def is_dockerfile(path):
+    """
+    Return True when a file name follows a Dockerfile naming convention
+
+    Args:
+        path (str): File path or basename
+
+    Returns:
+        bool
+    """
+    basename = posixpath.basename(str(path).replace("\\", "/"))
+    return any(fnmatch.fnmatchcase(basename, pattern) for pattern in DOCKERFILE_NAMES)
+
+
+def parse_image_reference(reference):
+    """
+    Split an image reference into its parts
+
+    Args:
+        reference (str): Reference as written, e.g. "golang:1.22-alpine" or "ghcr.io/org/app@sha256:..."
+
+    Returns:
+        dict: {"image", "tag", "digest", "name"} where image is the reference without tag and digest,
+            name the fully qualified repository (e.g. "docker.io/library/golang"), and tag/digest are
+            None when absent
+    """
+    image, _, digest = reference.partition("@")
+    tag = None
+    slash = image.rfind("/")
+    colon = image.rfind(":")
+    if colon > slash:
+        image, tag = image[:colon], image[colon + 1 :]
+
+    first, _, rest = image.partition("/")
+    if rest and ("." in first or ":" in first or first == "localhost"):
+        name = image
+    elif rest:
+        name = f"{DEFAULT_REGISTRY}/{image}"
+    else:
+        name = f"{DEFAULT_REGISTRY}/library/{image}"
+    return {"image": image, "tag": tag, "digest": digest or None, "name": name}
+
+
+def image_package_info(parts, scope):
+    """
+    Return the `docker` package metadata of an image reference
+
+    An image without tag or digest is the "latest" tag; the version is the tag,
+    or the digest for digest-only references
+
+    Args:
+        parts (dict): parse_image_reference result
+        scope (str): Package scope, e.g. "base-image"
+
+    Returns:
+        dict: {"ecosystem", "scope", "image", ["tag"], ["digest"], "version"}
+    """
+    tag = parts["tag"] if parts["tag"] or parts["digest"] else "latest"
+    package = {"ecosystem": "docker", "scope": scope, "image": parts["image"]}
+    if tag:
+        package["tag"] = tag
+    if parts["digest"]:
+        package["digest"] = parts["digest"]
+    package["version"] = tag or parts["digest"]
+    return package
+
+
+def _substitute(text, variables):
+    """
+    Expand $NAME, ${NAME} and ${NAME:-default} with known build arguments, leaving unknown ones as written
+    """
+
+    def _replace(match):
+        name = match.group(1) or match.group(3)
+        if name in variables:
+            return variables[name]
+        if match.group(2) is not None:
+            return match.group(2)
+        return match.group(0)
+
+    return _RE_VARIABLE.sub(_replace, text)
+
+
+def _instructions(content):
+    """
+    Yield (line, instruction, arguments) for every instruction, joining continuation lines
+
+    Args:
+        content (str): Dockerfile text
+
+    Yields:
+        tuple: (1-based line of the instruction, upper-cased instruction, argument text)
+    """
+    pending = []
+    start = 0
+    for number, raw_line in enumerate(content.splitlines(), start=1):
+        stripped = raw_line.strip()
+        if not pending and (not stripped or stripped.startswith("#")):
+            continue
+        if pending and stripped.startswith("#"):
+            # Comment lines inside a continued instruction are dropped
+            continue
+        if not pending:
+            start = number
+        if stripped.endswith("\\"):
+            pending.append(stripped[:-1])
+            continue
+        pending.append(stripped)
+        text = " ".join(part.strip() for part in pending if part.strip())
+        pending = []
+        instruction, _, arguments = text.partition(" ")
+        yield start, instruction.upper(), arguments.strip()
+    if pending:
+        text = " ".join(part.strip() for part in pending if part.strip())
+        instruction, _, arguments = text.partition(" ")
+        yield start, instruction.upper(), arguments.strip()
+
+
+def _split_package_spec(installer, spec):
+    """
+    Split an install argument into (package, version or None) following the installer's pinning syntax
+    """
+    if installer in ("apt", "apk"):
+        name, _, version = spec.partition("=")
+        return name, version or None
+    if installer == "pip":
+        match = re.match(r"^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*(==|>=|<=|~=|!=|>|<)?\s*(.*)$", spec)
+        if not match:
+            return None, None
+        version = match.group(4) if match.group(3) == "==" else None
+        return match.group(1), version or None
+    if installer in ("npm", "go", "cargo"):
+        at = spec.rfind("@")
+        if at > 0:
+            return spec[:at], spec[at + 1 :] or None
+        return spec, None
+    return spec, None
+
+
+def _is_package_argument(token):
+    if not token or token.startswith(("-", ".", "/", "~")) or "$" in token:
+        return False
+    if "://" in token or token.endswith((".txt", ".whl", ".tar.gz", ".tgz", ".rpm", ".deb", ".apk")):
+        return False
+    return True
+
+
+def _command_packages(words):
+    """
+    Return (installer, package, version, argument) for every package one shell command installs
+
+    Args:
+        words (list): Tokens of a single shell command
+
+    Returns:
+        list: Tuples of installer key, package name, pinned version (or None) and the argument as written
+    """
+    while words and (words[0] in _COMMAND_PREFIXES or ("=" in words[0] and not words[0].startswith("-"))):
+        # sudo, env and VAR=value assignments in front of the command
+        words = words[1:]
+    if not words:
+        return []
+    command = posixpath.basename(words[0])
+    arguments = words[1:]
+    if re.fullmatch(r"python[0-9.]*", command) and arguments[:2] == ["-m", "pip"]:
+        command, arguments = "pip", arguments[2:]
+    installer = _COMMAND_INSTALLERS.get(command)
+    if installer is None:
+        return []
+    if command == "yarn":
+        # Only `yarn global add` installs outside of a package.json project
+        if arguments[:1] != ["global"]:
+            return []
+        arguments = arguments[1:] + ["--global"]
+
+    subcommands, value_options = _INSTALLERS[installer]
+    options = []
+    positional = []
+    pending_option = None
+    for word in arguments:
+        if pending_option:
+            options.append((pending_option, word))
+            pending_option = None
+        elif word.startswith("-"):
+            option, has_value, value = word.partition("=")
+            if option in value_options and not has_value:
+                pending_option = option
+            else:
+                options.append((option, value))
+        else:
+            positional.append(word)
+    if not positional or positional[0] not in subcommands:
+        return []
+    option_names = {option for option, _ in options}
+    # A local `npm install` installs package.json dependencies, which that manifest already declares
+    if installer == "npm" and not option_names & {"-g", "--global"}:
+        return []
+
+    packages = []
+    for word in positional[1:]:
+        if not _is_package_argument(word):
+            continue
+        name, version = _split_package_spec(installer, word)
+        if name:
+            packages.append((installer, name, version, word))
+    # `cargo install ripgrep --version 14.1.0` and `gem install rails -v 7.1.3` pin a single package
+    pinned = [value for option, value in options if option in ("--version", "--vers", "-v") and value]
+    if installer in ("cargo", "gem") and pinned and len(packages) == 1:
+        packages[0] = (installer, packages[0][1], pinned[-1], packages[0][3])
+    return packages
+
+
+def _run_packages(arguments):
+    """
+    Return (installer, package, version, argument) tuples for the packages a RUN instruction installs
+
+    Args:
+        arguments (str): RUN arguments, in shell or JSON exec form
+
+    Returns:
+        list
+    """
+    while arguments.startswith("--"):
+        # RUN flags such as --mount=type=cache,target=/root/.cache
+        _, _, arguments = arguments.partition(" ")
+        arguments = arguments.strip()
+    if arguments.startswith("["):
+        try:
+            words = json.loads(arguments)
+        except ValueError:
+            words = None
+        if isinstance(words, list) and all(isinstance(word, str) for word in words):
+            arguments = " ".join(shlex.quote(word) for word in words)
+
+    packages = []
+    for command in _RE_COMMAND_SEPARATOR.split(arguments):
+        try:
+            words = shlex.split(command, comments=True)
+        except ValueError:
+            words = command.split()
+        packages.extend(_command_packages(words))
+    return packages
+
+
+def parse_dockerfile(content):
+    """
+    Extract the external dependencies a Dockerfile declares
+
+    FROM instructions yield base images; build stages that reuse an earlier stage
+    and `scratch` are skipped, and ARG defaults are substituted into image
+    references. RUN instructions are searched for apt/apk/yum/dnf, pip, global
+    npm/yarn, go, cargo and gem installs. Arguments that reference variables,
+    files or URLs are skipped
+
+    Args:
+        content (str): Dockerfile text
+
+    Returns:
+        list: Dependencies in file order, each a dict with "key" (the external package key),
+            "reference" (as written), "line", "scope" ("base-image" or "installed-package") and
+            "package" (package metadata fields)
+    """
+    dependencies = []
+    variables = {}
+    stages = set()
+    stage = None
+    for line, instruction, arguments in _instructions(content):
+        if instruction == "ARG":
+            for word in arguments.split():
+                name, has_default, default = word.partition("=")
+                if has_default and name not in variables:
+                    variables[name] = _substitute(default.strip("\"'"), variables)
+        elif instruction == "FROM":
+            words = [word for word in arguments.split() if not word.startswith("--")]
+            if not words:
+                continue
+            reference = _substitute(words[0], variables)
+            stage = words[2] if len(words) >= 3 and words[1].lower() == "as" else None
+            if reference.lower() in stages or reference == "scratch" or "$" in reference:
+                if stage:
+                    stages.add(stage.lower())
+                continue
+            if stage:
+                stages.add(stage.lower())
+            parts = parse_image_reference(reference)
+            package = image_package_info(parts, "base-image")
+            if stage:
+                package["stage"] = stage
+            dependencies.append(
+                {"key": parts["name"], "reference": reference, "line": line, "scope": "base-image", "package": package}
+            )
+        elif instruction == "RUN":
+            for installer, name, version, reference in _run_packages(arguments):
+                package = {"ecosystem": "docker", "scope": "installed-package", "installer": installer, "package": name}
+                if version:
+                    package["version"] = version
+                if stage:
+                    package["stage"] = stage
+                dependencies.append(
+                    {
+                        "key": f"{installer}:{name}",
+                        "reference": reference,
+                        "line": line,
+                        "scope": "installed-package",
+                        "package": package,
+                    }
+                )
+    return dependencies
+
+
+class DockerfileAnalyzer:
+    """
+    Analyzer for Dockerfiles
+
+    Implements the Analyzer protocol (see treewalk.registry) without a
+    tree-sitter grammar; files are claimed by name, see DOCKERFILE_NAMES
+    """
+
+    language = "docker"
+
+    def __init__(self, logger=None):
+        """
+        Args:
+            logger (Logger): Optional logger instance
+        """
+        self.logger = logger
+
+    def get_manifest_files(self):
+        """
+        Get the manifest filename patterns for Dockerfiles
+
+        Returns:
+            List of shell-style basename patterns
+        """
+        return list(DOCKERFILE_NAMES)
+
+    def process_manifest(self, file_path, packages_dict, secure_file_ops=None):
+        """
+        Add the base images and installed packages of a Dockerfile to the packages dictionary
+
+        Packages are keyed by their fully qualified image name for base images and
+        by "<installer>:<package>" for installed packages; the first occurrence in
+        a file wins
+
+        Args:
+            file_path (str): Path to the Dockerfile
+            packages_dict (dict): Dictionary to update with package information
+            secure_file_ops (object): Optional SecureFileOps instance for safe file operations
+
+        Returns:
+            Updated packages_dict
+        """
+        try:
+            content = read_file_content(file_path, secure_file_ops, logger=self.logger)
+        except Exception as exc:
+            if self.logger:
+                self.logger.warning(f"Could not read Dockerfile {file_path}: {exc}")
+            return packages_dict
+        for dependency in parse_dockerfile(content):
+            packages_dict.setdefault(dependency["key"], dict(dependency["package"]))
+        return packages_dict
+
+    def matches(self, path):
+        """
+        Return True for files named like Dockerfiles
+
+        Args:
+            path (str): Repo-relative file path
+
+        Returns:
+            bool
+        """
+        return is_dockerfile(path)
+
+    def extract(self, path, context):
+        """
+        Report a Dockerfile's base images and installed packages as external import evidence
+
+        Args:
+            path (str): Repo-relative file path
+            context (ExtractionContext): File content
+
+        Returns:
+            list: {"import", "scope": "external", "module", "kind", "line"} entries, where import is the
+                reference as written, module the package key and kind the package scope
+        """
+        return [
+            {
+                "import": dependency["reference"],
+                "scope": "external",
+                "module": dependency["key"],
+                "kind": dependency["scope"],
+                "line": dependency["line"],
+            }
+            for dependency in parse_dockerfile(context.code)
+        ]
@@ -0,0 +1,209 @@
+"""
+GitHub Actions analyzer: actions and reusable workflows referenced by `uses:`
+
+Workflow files (`.github/workflows/*.yml`) and action metadata files
+(`action.yml`) are both manifests and source files. Every `uses:` reference is
+a dependency: `owner/repo[/path]@ref` actions and reusable workflows declare
+`github-actions` ecosystem packages, `docker://` references declare `docker`
+ecosystem images, and `./path` references are local actions or workflows of the
+repository. The files are scanned line by line rather than parsed as YAML,
+so no YAML library is needed
+"""
+
+import re
+
+from gardener.common.file_helpers import read_file_content
+from gardener.treewalk.base import is_manifest_name
+from gardener.treewalk.docker import image_package_info, parse_image_reference
+
+# Workflow and action metadata files, see is_manifest_name
+GITHUB_ACTIONS_FILES = (".github/workflows/*.yml", ".github/workflows/*.yaml", "action.yml", "action.yaml")
+
+_RE_USES = re.compile(r"""^\s*(?:-\s+)?uses\s*:\s*(["']?)([^\s"'#]+)\1\s*(?:#.*)?$""")
+_RE_COMMIT_SHA = re.compile(r"^[0-9a-f]{40}$")
+
+
+def is_github_actions_file(path):
+    """
+    Return True for GitHub Actions workflow and action metadata files
+
+    Args:
+        path (str): Repo-relative file path
+
+    Returns:
+        bool
+    """
+    return is_manifest_name(path, GITHUB_ACTIONS_FILES)
+
+
+def parse_action_reference(reference):
+    """
+    Classify one `uses:` reference
+
+    Args:
+        reference (str): Reference as written, e.g. "actions/checkout@v4"
+
+    Returns:
+        dict|None: {"kind": "action"|"reusable-workflow", "action", "ref", "pinned"} for remote
+            references, where pinned is True for full commit SHAs, {"kind": "docker", ...} with the
+            parse_image_reference fields for `docker://` images, {"kind": "local", "path"} for `./`
+            references, or None when the reference is an expression or malformed
+    """
+    if "${{" in reference:
+        return None
+    if reference.startswith("docker://"):
+        return {"kind": "docker", **parse_image_reference(reference[len("docker://") :])}
+    if reference.startswith("./"):
+        return {"kind": "local", "path": reference}
+    action, at, ref = reference.partition("@")
+    parts = action.split("/")
+    if not at or not ref or len(parts) < 2 or not all(parts):
+        return None
+    kind = "action"
+    if "/.github/workflows/" in action and action.endswith((".yml", ".yaml")):
+        kind = "reusable-workflow"
+    return {
+        "kind": kind,
+        "action": action,
+        "ref": ref,
+        "pinned": bool(_RE_COMMIT_SHA.match(ref)),
+    }
+
+
+def parse_workflow_uses(content):
+    """
+    Return the `uses:` references of a workflow or action file
+
+    Args:
+        content (str): File text
+
+    Returns:
+        list: (1-based line, reference as written, parse_action_reference result) tuples in file
+            order, skipping references parse_action_reference rejects
+    """
+    references = []
+    for number, line in enumerate(content.splitlines(), start=1):
+        match = _RE_USES.match(line)
+        if not match:
+            continue
+        parsed = parse_action_reference(match.group(2))
+        if parsed is not None:
+            references.append((number, match.group(2), parsed))
+    return references
+
+
+def _package_key(parsed):
+    """
+    Return the external package key of a remote or docker reference
+    """
+    return parsed["name"] if parsed["kind"] == "docker" else parsed["action"]
+
+
+def _package_info(parsed):
+    """
+    Return the package metadata recorded for a remote or docker reference
+    """
+    if parsed["kind"] == "docker":
+        return image_package_info(parsed, "action-image")
+    return {
+        "ecosystem": "github-actions",
+        "kind": parsed["kind"],
+        "action": parsed["action"],
+        "ref": parsed["ref"],
+        "version": parsed["ref"],
+        "pinned": parsed["pinned"],
+    }
+
+
+class GitHubActionsAnalyzer:
+    """
+    Analyzer for GitHub Actions workflows and action metadata files
+
+    Implements the Analyzer protocol (see treewalk.registry) without a parser;
+    files are claimed by path, see GITHUB_ACTIONS_FILES
+    """
+
+    language = "github-actions"
+
+    def __init__(self, logger=None):
+        """
+        Args:
+            logger (Logger): Optional logger instance
+        """
+        self.logger = logger
+
+    def get_manifest_files(self):
+        """
+        Get the manifest path patterns for GitHub Actions
+
+        Returns:
+            List of end-of-path patterns
+        """
+        return list(GITHUB_ACTIONS_FILES)
+
+    def process_manifest(self, file_path, packages_dict, secure_file_ops=None):
+        """
+        Add the actions, reusable workflows and docker images a workflow uses to the packages dictionary
+
+        Actions are keyed by `owner/repo[/path]` without the ref and docker images by
+        their fully qualified name; the first reference in a file wins. Local
+        references are not packages
+
+        Args:
+            file_path (str): Path to the workflow or action file
+            packages_dict (dict): Dictionary to update with package information
+            secure_file_ops (object): Optional SecureFileOps instance for safe file operations
+
+        Returns:
+            Updated packages_dict
+        """
+        try:
+            content = read_file_content(file_path, secure_file_ops, logger=self.logger)
+        except Exception as exc:
+            if self.logger:
+                self.logger.warning(f"Could not read workflow {file_path}: {exc}")
+            return packages_dict
+        for _, _, parsed in parse_workflow_uses(content):
+            if parsed["kind"] != "local":
+                packages_dict.setdefault(_package_key(parsed), _package_info(parsed))
+        return packages_dict
+
+    def matches(self, path):
+        """
+        Return True for workflow and action metadata files
+
+        Args:
+            path (str): Repo-relative file path
+
+        Returns:
+            bool
+        """
+        return is_github_actions_file(path)
+
+    def extract(self, path, context):
+        """
+        Report a file's `uses:` references as import evidence
+
+        Args:
+            path (str): Repo-relative file path
+            context (ExtractionContext): File content and local resolver
+
+        Returns:
+            list: {"import", "scope", "kind", "line"} entries with "module" for external references and
+                "resolved" for local ones; import is the reference as written and kind one of "action",
+                "reusable-workflow", "docker" or "local"
+        """
+        resolver = getattr(context.local_resolver, "resolve_github_action", None)
+        entries = []
+        for line, reference, parsed in parse_workflow_uses(context.code):
+            entry = {"import": reference, "kind": parsed["kind"], "line": line}
+            if parsed["kind"] == "local":
+                target = resolver(path, parsed["path"]) if resolver else None
+                entry["scope"] = "local"
+                entry["resolved"] = [target] if target else []
+            else:
+                entry["scope"] = "external"
+                entry["module"] = _package_key(parsed)
+            entries.append(entry)
+        return entries
+
@@ -2,29 +2,1234 @@
 Go-specific visitors and handlers
 """
 
+import ast
 import os
+import posixpath
 import re
 
+from gardener.common.defaults import GoAnalysisConfig
+from gardener.common.file_helpers import to_posix_path
+from gardener.common.go_stdlib import is_go_stdlib
 from gardener.common.secure_file_ops import FileOperationError
 from gardener.common.utils import Logger
-from gardener.treewalk.base import LanguageHandler, TreeVisitor
+from gardener.treewalk.base import LanguageHandler, TreeVisitor, is_manifest_name
 
 # Module-level logger instance
 logger = Logger(verbose=False)  # Will be configured by the caller
 
+_RE_GO_MOD_LINE_COMMENT = re.compile(r"//.*")
+_RE_GO_MOD_BLOCK_START = re.compile(r"^([a-z]+)\s*\($")
+_RE_GO_BUILD_LINE = re.compile(r"^//go:build(?:\s+(.*))?$")
+_RE_GO_PLUS_BUILD_LINE = re.compile(r"^//\s*\+build(?:\s+(.*))?$")
+_RE_GO_BUILD_TAG = re.compile(r"!?[A-Za-z0-9_.]+")
+_RE_GO_BUILD_TAG_NAME = re.compile(r"[A-Za-z0-9_.]+")
+_RE_GO_BUILD_EXPRESSION = re.compile(r"(?:[A-Za-z0-9_.\s()!]|&&|\|\|)*")
+# Same pattern as the Go convention (https://golang.org/s/generatedcode), applied per comment line
+_RE_GO_GENERATED_MARKER = re.compile(r"^// Code generated .* DO NOT EDIT\.$")
+# Top-level functions `go test` runs: TestXxx, BenchmarkXxx, FuzzXxx and ExampleXxx (Xxx not starting lowercase)
+_RE_GO_TEST_FUNC = re.compile(r"^func\s+(Test|Benchmark|Fuzz|Example)(?![a-z])\w*\s*\(", re.MULTILINE)
+_RE_CGO_DIRECTIVE = re.compile(r"^#cgo\s+(?:([^:]*?)\s+)?(pkg-config|LDFLAGS):\s*(.*)$")
+# As `go generate` scans for it: at the very start of a line, followed by a space or tab
+_RE_GO_GENERATE = re.compile(r"^//go:generate[ \t]+(\S.*?)\s*$")
+_RE_BAZEL_GO_REPOSITORY = re.compile(r"(?<![\w.])go_repository\s*\(")
+_RE_BAZEL_KEYWORD_ARGUMENT = re.compile(r"^\s*([A-Za-z_]\w*)\s*=\s*(.*?)\s*$", re.DOTALL)
+
+# go_repository attributes read by parse_bazel_go_repositories
+BAZEL_GO_REPOSITORY_ATTRIBUTES = ("name", "importpath", "version", "sum", "replace", "commit", "tag", "remote")
+
+# Bazel files that may declare Gazelle go_repository rules
+BAZEL_GO_MANIFESTS = ("WORKSPACE", "WORKSPACE.bazel", "*.bzl")
+
+
+def _iter_go_mod_directives(content):
+    """
+    Yield (verb, tokens, comment) for every directive line in go.mod content
+
+    Grouped blocks such as `require ( ... )` are flattened so each entry is
+    reported with the verb of its enclosing block
+
+    Args:
+        content (str): Text content of a go.mod file
+
+    Yields:
+        tuple: (verb, list_of_tokens, trailing comment text without the leading `//`)
+    """
+    block_verb = None
+
+    for raw_line in content.splitlines():
+        comment_match = _RE_GO_MOD_LINE_COMMENT.search(raw_line)
+        comment = comment_match.group(0)[2:].strip() if comment_match else ""
+        line = _RE_GO_MOD_LINE_COMMENT.sub("", raw_line).strip()
+        if not line:
+            continue
+
+        if block_verb is not None:
+            if line == ")":
+                block_verb = None
+                continue
+            yield block_verb, line.split(), comment
+            continue
+
+        block_start = _RE_GO_MOD_BLOCK_START.match(line)
+        if block_start:
+            block_verb = block_start.group(1)
+            continue
+        verb, _, rest = line.partition(" ")
+        yield verb, rest.split(), comment
+
+
+def _is_go_local_path(path):
+    """
+    Return True when a replace target is a filesystem path rather than a module path
+
+    Args:
+        path (str): Right-hand side of a replace directive
+
+    Returns:
+        bool
+    """
+    return path.startswith(("./", "../", "/")) or path in (".", "..") or bool(re.match(r"^[A-Za-z]:[\\/]", path))
+
+
+def parse_go_mod_file(content):
+    """
+    Parse the directives of a go.mod file that matter for dependency reporting
+
+    Args:
+        content (str): Text content of a go.mod file
+
+    Returns:
+        dict: {
+            "module": declared module path or "",
+            "go": language version from the `go` directive or "",
+            "toolchain": toolchain from the `toolchain` directive (e.g. "go1.22.3") or "",
+            "require": {module_path: version},
+            "indirect": [module paths whose require carries an `// indirect` comment],
+            "replace": {source: {"path": target, "version": target_version, "local": bool}},
+            "deprecated": `Deprecated:` message of the module directive's comment or "",
+            "retract": [{"low", "high", "rationale"}] for each retracted version or version range
+        }
+        where replace sources are keyed as `path` or `path@version` for version-specific replaces
+    """
+    parsed = {"module": "", "go": "", "toolchain": "", "require": {}, "indirect": [], "replace": {}}
+    parsed["deprecated"], parsed["retract"] = _parse_go_mod_deprecations(content)
+
+    for verb, tokens, comment in _iter_go_mod_directives(content):
+        tokens = [token.strip('"') for token in tokens]
+        if verb == "module" and tokens:
+            parsed["module"] = tokens[0]
+        elif verb in ("go", "toolchain") and tokens:
+            parsed[verb] = tokens[0]
+        elif verb == "require" and len(tokens) >= 2:
+            parsed["require"][tokens[0]] = tokens[1]
+            if comment.split(";")[0].strip() == "indirect" and tokens[0] not in parsed["indirect"]:
+                parsed["indirect"].append(tokens[0])
+        elif verb == "replace":
+            _add_go_replace_directive(parsed["replace"], tokens)
+
+    return parsed
+
+
+def _go_deprecation_message(comment_lines):
+    """
+    Return the text of the `Deprecated:` paragraph of a comment, as the go command reads it
+
+    Args:
+        comment_lines (list): Comment lines without their `//`; "" separates paragraphs
+
+    Returns:
+        str: The paragraph after its `Deprecated:` prefix, joined into one line, or ""
+    """
+    paragraph = []
+    for line in comment_lines + [""]:
+        if line:
+            paragraph.append(line)
+            continue
+        if paragraph and paragraph[0].startswith("Deprecated:"):
+            return " ".join([paragraph[0][len("Deprecated:") :].strip()] + paragraph[1:]).strip()
+        paragraph = []
+    return ""
+
+
+def _add_go_retraction(retractions, spec, rationale):
+    """
+    Record one retracted version (`v1.0.0`) or closed interval (`[v1.0.0, v1.2.0]`)
+
+    Args:
+        retractions (list): Retractions being built, mutated in place
+        spec (str): Retract directive arguments without the comment
+        rationale (str): Comment explaining the retraction, or ""
+    """
+    spec = spec.strip()
+    if spec.startswith("["):
+        low, _, high = spec.strip("[]").partition(",")
+        low, high = low.strip().strip('"'), high.strip().strip('"')
+    else:
+        low = high = spec.split()[0].strip('"') if spec else ""
+    if low and high:
+        retractions.append({"low": low, "high": high, "rationale": rationale})
+
+
+def _parse_go_mod_deprecations(content):
+    """
+    Read the module deprecation notice and the retract directives of a go.mod file
+
+    A module is deprecated by a `Deprecated:` paragraph in the comment directly
+    above its module directive or trailing it. A retraction's rationale is its
+    trailing comment or the comment directly above it (or above its retract block)
+
+    Args:
+        content (str): Text content of a go.mod file
+
+    Returns:
+        tuple: (deprecation message or "", [{"low", "high", "rationale"}])
+    """
+    deprecated = ""
+    retractions = []
+    pending = []
+    block_verb = None
+    block_rationale = ""
+
+    for raw_line in content.splitlines():
+        stripped = raw_line.strip()
+        if stripped.startswith("//"):
+            pending.append(stripped[2:].strip())
+            continue
+        if not stripped:
+            pending = []
+            continue
+        comment_match = _RE_GO_MOD_LINE_COMMENT.search(stripped)
+        comment = comment_match.group(0)[2:].strip() if comment_match else ""
+        line = _RE_GO_MOD_LINE_COMMENT.sub("", stripped).strip()
+        rationale = comment or " ".join(part for part in pending if part)
+
+        if block_verb is not None:
+            if line == ")":
+                block_verb = None
+            elif block_verb == "retract":
+                _add_go_retraction(retractions, line, rationale or block_rationale)
+        elif _RE_GO_MOD_BLOCK_START.match(line):
+            block_verb = _RE_GO_MOD_BLOCK_START.match(line).group(1)
+            block_rationale = rationale
+        else:
+            verb, _, rest = line.partition(" ")
+            if verb == "module":
+                deprecated = _go_deprecation_message(pending + ([comment] if comment else []))
+            elif verb == "retract":
+                _add_go_retraction(retractions, rest, rationale)
+        pending = []
+
+    return deprecated, retractions
+
+
+def _add_go_replace_directive(replacements, tokens):
+    """
+    Record one `replace source [version] => target [version]` directive
+
+    Args:
+        replacements (dict): Replace map being built, mutated in place
+        tokens (list): Directive tokens with quotes stripped
+    """
+    if "=>" not in tokens:
+        return
+    arrow = tokens.index("=>")
+    source, target = tokens[:arrow], tokens[arrow + 1 :]
+    if not source or not target:
+        return
+    source_key = f"{source[0]}@{source[1]}" if len(source) > 1 else source[0]
+    replacements[source_key] = {
+        "path": target[0],
+        "version": target[1] if len(target) > 1 else "",
+        "local": _is_go_local_path(target[0]),
+    }
+
+
+def parse_go_work(content):
+    """
+    Parse a go.work file describing a multi-module workspace
+
+    Args:
+        content (str): Text content of a go.work file
+
+    Returns:
+        dict: {
+            "go": Go version from the `go` directive or "",
+            "use": [module directories relative to the go.work file],
+            "replace": replace map in the same shape as parse_go_mod_file
+        }
+    """
+    parsed = {"go": "", "use": [], "replace": {}}
+
+    for verb, tokens, _ in _iter_go_mod_directives(content):
+        tokens = [token.strip('"') for token in tokens]
+        if verb == "go" and tokens:
+            parsed["go"] = tokens[0]
+        elif verb == "use" and tokens:
+            if tokens[0] not in parsed["use"]:
+                parsed["use"].append(tokens[0])
+        elif verb == "replace":
+            _add_go_replace_directive(parsed["replace"], tokens)
+
+    return parsed
+
+
+def parse_go_mod(content):
+    """
+    Parse go.mod content into a map of required module paths to versions
+
+    Handles both grouped `require ( ... )` blocks and single-line `require` directives
+
+    Args:
+        content (str): Text content of a go.mod file
+
+    Returns:
+        dict: Mapping of {module_path: version}
+    """
+    return parse_go_mod_file(content)["require"]
+
+
+def _format_go_replacement(replacement):
+    """
+    Render a replace target as `path@version`, or the bare path when unversioned
+
+    Args:
+        replacement (dict): Replace entry from parse_go_mod_file
+
+    Returns:
+        str
+    """
+    if replacement.get("version"):
+        return f"{replacement['path']}@{replacement['version']}"
+    return replacement["path"]
+
+
+def parse_go_sum(content):
+    """
+    Parse go.sum content into a map of `module@version` to its `h1:` hash
+
+    Each module version may appear twice: once for the module zip and once with a
+    `/go.mod` suffix for its go.mod file. The zip hash is preferred; the go.mod hash
+    is only used when no zip line exists for that version
+
+    Args:
+        content (str): Text content of a go.sum file
+
+    Returns:
+        dict: Mapping of {"module@version": "h1:..."}
+    """
+    zip_hashes = {}
+    go_mod_hashes = {}
+
+    for raw_line in content.splitlines():
+        tokens = raw_line.split()
+        if len(tokens) != 3 or not tokens[2].startswith("h1:"):
+            continue
+        module_path, version, checksum = tokens
+        if version.endswith("/go.mod"):
+            go_mod_hashes[f"{module_path}@{version[: -len('/go.mod')]}"] = checksum
+        else:
+            zip_hashes[f"{module_path}@{version}"] = checksum
+
+    checksums = dict(go_mod_hashes)
+    checksums.update(zip_hashes)
+    return checksums
+
+
+def parse_go_sum_modules(content):
+    """
+    Parse go.sum content into the versions it lists for each module
+
+    Args:
+        content (str): Text content of a go.sum file
+
+    Returns:
+        dict: {module path: {version: {"checksum": "h1:...", "zip": bool}}}, `zip` being False
+            for versions of which only the go.mod file is hashed (modules of the build graph
+            whose code was never downloaded); the checksum is the zip hash when there is one
+    """
+    modules = {}
+    for raw_line in content.splitlines():
+        tokens = raw_line.split()
+        if len(tokens) != 3 or not tokens[2].startswith("h1:"):
+            continue
+        module_path, version, checksum = tokens
+        versions = modules.setdefault(module_path, {})
+        if version.endswith("/go.mod"):
+            versions.setdefault(version[: -len("/go.mod")], {"checksum": checksum, "zip": False})
+        else:
+            versions[version] = {"checksum": checksum, "zip": True}
+    return modules
+
+
+def parse_vendor_modules_txt(content):
+    """
+    Parse vendor/modules.txt written by `go mod vendor`
+
+    Module lines have the form `# path version [=> target [target_version]]`,
+    optionally followed by a `## explicit[; go 1.x]` annotation line and the list
+    of vendored package paths belonging to that module
+
+    Args:
+        content (str): Text content of vendor/modules.txt
+
+    Returns:
+        dict: {module_path: {"version", "explicit", "go_version", "packages", "replace"}}
+        where replace is None or {"path", "version", "local"}
+    """
+    modules = {}
+    current = None
+
+    for raw_line in content.splitlines():
+        line = raw_line.strip()
+        if not line:
+            continue
+
+        if line.startswith("## "):
+            if current is None:
+                continue
+            for annotation in line[3:].split(";"):
+                annotation = annotation.strip()
+                if annotation == "explicit":
+                    current["explicit"] = True
+                elif annotation.startswith("go "):
+                    current["go_version"] = annotation[3:].strip()
+            continue
+
+        if line.startswith("# "):
+            tokens = line[2:].split()
+            replace = None
+            if "=>" in tokens:
+                arrow = tokens.index("=>")
+                target = tokens[arrow + 1 :]
+                tokens = tokens[:arrow]
+                if target:
+                    replace = {
+                        "path": target[0],
+                        "version": target[1] if len(target) > 1 else "",
+                        "local": _is_go_local_path(target[0]),
+                    }
+            if not tokens or (len(tokens) == 1 and tokens[0] in modules):
+                # The trailing `# path => target` lines of wildcard replaces repeat a module listed above
+                current = None
+                continue
+            current = {
+                "version": tokens[1] if len(tokens) > 1 else "",
+                "explicit": False,
+                "go_version": "",
+                "packages": [],
+                "replace": replace,
+            }
+            modules[tokens[0]] = current
+            continue
+
+        if current is not None and not line.startswith("#"):
+            current["packages"].append(line)
+
+    return modules
+
+
+def _starlark_call_arguments(content, start):
+    """
+    Split the arguments of a Starlark call at their top-level commas
+
+    Args:
+        content (str): File text with comments blanked out
+        start (int): Index just past the call's opening parenthesis
+
+    Returns:
+        list: Argument source texts, or [] when the call is not closed
+    """
+    arguments = []
+    depth = 0
+    quote = None
+    begin = index = start
+    while index < len(content):
+        char = content[index]
+        if quote:
+            if char == "\\":
+                index += 1
+            elif content.startswith(quote, index):
+                index += len(quote) - 1
+                quote = None
+        elif char in "\"'":
+            quote = content[index : index + 3] if content[index : index + 3] in ('"""', "'''") else char
+            index += len(quote) - 1
+        elif char in "([{":
+            depth += 1
+        elif char in ")]}" and depth:
+            depth -= 1
+        elif char == ")":
+            arguments.append(content[begin:index])
+            return [argument for argument in arguments if argument.strip()]
+        elif char == "," and not depth:
+            arguments.append(content[begin:index])
+            begin = index + 1
+        index += 1
+    return []
+
+
+def _blank_starlark_comments(content):
+    """
+    Replace `#` comments with spaces, leaving string literals and offsets intact
+
+    Args:
+        content (str): Starlark source text
+
+    Returns:
+        str
+    """
+    chars = list(content)
+    quote = None
+    index = 0
+    while index < len(chars):
+        char = chars[index]
+        if quote:
+            if char == "\\":
+                index += 1
+            elif content.startswith(quote, index):
+                index += len(quote) - 1
+                quote = None
+        elif char in "\"'":
+            quote = content[index : index + 3] if content[index : index + 3] in ('"""', "'''") else char
+            index += len(quote) - 1
+        elif char == "#":
+            while index < len(chars) and chars[index] != "\n":
+                chars[index] = " "
+                index += 1
+            continue
+        index += 1
+    return "".join(chars)
+
+
+def parse_bazel_go_repositories(content):
+    """
+    Parse the Gazelle `go_repository(...)` rules of a WORKSPACE or .bzl file
+
+    Only attributes given as string literals are read; rules computed from
+    variables or macros are skipped, as are rules without an `importpath`
+
+    Args:
+        content (str): Text content of a WORKSPACE, WORKSPACE.bazel or .bzl file
+
+    Returns:
+        list: One dict per rule in file order with the BAZEL_GO_REPOSITORY_ATTRIBUTES
+            present, e.g. {"name": "com_github_pkg_errors", "importpath": "github.com/pkg/errors",
+            "version": "v0.9.1", "sum": "h1:..."}
+    """
+    text = _blank_starlark_comments(content)
+    rules = []
+    for match in _RE_BAZEL_GO_REPOSITORY.finditer(text):
+        rule = {}
+        for argument in _starlark_call_arguments(text, match.end()):
+            keyword = _RE_BAZEL_KEYWORD_ARGUMENT.match(argument)
+            if not keyword or keyword.group(1) not in BAZEL_GO_REPOSITORY_ATTRIBUTES:
+                continue
+            try:
+                value = ast.literal_eval(keyword.group(2))
+            except (ValueError, SyntaxError):
+                continue
+            if isinstance(value, str):
+                rule[keyword.group(1)] = value
+        if rule.get("importpath"):
+            rules.append(rule)
+    return rules
+
+
+def go_sum_key(module_path, version, replacement=None):
+    """
+    Return the go.sum key (`module@version`) that verifies a required module
+
+    Remote replacements are verified under the replacement's own path and
+    version; local replacements have no go.sum entry
+
+    Args:
+        module_path (str): Required module path
+        version (str): Required version
+        replacement (dict): Optional applicable replace directive
+
+    Returns:
+        str or None
+    """
+    if replacement:
+        if replacement.get("local"):
+            return None
+        return f"{replacement['path']}@{replacement.get('version') or version}"
+    if not module_path or not version:
+        return None
+    return f"{module_path}@{version}"
+
+
+def find_go_replacement(module_path, version, replacements):
+    """
+    Return the replace directive that applies to a required module, if any
+
+    A version-specific replace (`old v1.2.3 => ...`) wins over a path-wide one
+
+    Args:
+        module_path (str): Required module path
+        version (str): Required version
+        replacements (dict): Replace map as returned by parse_go_mod_file
+
+    Returns:
+        dict or None
+    """
+    return replacements.get(f"{module_path}@{version}") or replacements.get(module_path)
+
+
+def parse_go_build_constraints(source):
+    """
+    Parse the build constraint that gates a Go source file
+
+    Follows the `go/build` header rules: only the leading run of blank lines and
+    comments before the package clause is considered, `//go:build` takes precedence
+    over legacy `// +build` lines, and `// +build` lines only count when their comment
+    group is followed by a blank line (otherwise they are part of the package doc)
+
+    Args:
+        source (str): Go source text
+
+    Returns:
+        Tuple of (expression, tags) where expression is the constraint in `//go:build`
+        syntax ("" when unconstrained) and tags lists the referenced tags in order
+    """
+    go_build = None
+    plus_build_lines = []
+    pending_plus_build = []  # +build lines in the current comment group
+    in_block_comment = False
+
+    for raw_line in source.splitlines():
+        line = raw_line.strip()
+        if in_block_comment:
+            if "*/" in line:
+                in_block_comment = False
+            continue
+        if not line:
+            plus_build_lines.extend(pending_plus_build)
+            pending_plus_build = []
+            continue
+        if line.startswith("/*"):
+            in_block_comment = "*/" not in line[2:]
+            continue
+        if not line.startswith("//"):
+            break
+
+        go_build_match = _RE_GO_BUILD_LINE.match(line)
+        if go_build_match:
+            if go_build is None:
+                go_build = (go_build_match.group(1) or "").strip()
+            continue
+        plus_build_match = _RE_GO_PLUS_BUILD_LINE.match(line)
+        if plus_build_match and plus_build_match.group(1):
+            pending_plus_build.append(plus_build_match.group(1).strip())
+
+    expression = go_build if go_build is not None else _plus_build_to_expression(plus_build_lines)
+
+    tags = []
+    for tag in _RE_GO_BUILD_TAG.findall(expression):
+        if tag not in tags:
+            tags.append(tag)
+    return expression, tags
+
+
+def is_go_generated_file(source):
+    """
+    Report whether a Go source file carries the machine-generated marker
+
+    Mirrors `go/ast.IsGenerated`: a `//` line comment matching
+    `^// Code generated .* DO NOT EDIT\\.$` exactly must appear before the package
+    clause. Block comments and loose variants (e.g. a missing period or trailing
+    text) do not count
+
+    Args:
+        source (str): Go source text
+
+    Returns:
+        bool
+    """
+    in_block_comment = False
+    for raw_line in source.splitlines():
+        line = raw_line.lstrip()
+        if in_block_comment:
+            if "*/" in line:
+                in_block_comment = False
+            continue
+        if not line:
+            continue
+        if line.startswith("/*"):
+            in_block_comment = "*/" not in line[2:]
+            continue
+        if not line.startswith("//"):
+            return False
+        if _RE_GO_GENERATED_MARKER.match(line):
+            return True
+    return False
+
+
+# Build tags whose constraint marks a file as outside the normal build -> scope of its imports.
+# `tools` files pin development tools with blank imports; `ignore` files are standalone programs
+# (codegen helpers, examples) that `go build` never compiles
+GO_BUILD_TAG_SCOPES = {
+    "tools": "tool",
+    "ignore": "ignored-build",
+}
+
+
+# GOOS values that also satisfy the `unix` tag, and those that imply another GOOS
+_GO_UNIX_OS = frozenset(
+    {
+        "aix",
+        "android",
+        "darwin",
+        "dragonfly",
+        "freebsd",
+        "hurd",
+        "illumos",
+        "ios",
+        "linux",
+        "netbsd",
+        "openbsd",
+        "solaris",
+    }
+)
+_GO_IMPLIED_OS = {"android": "linux", "ios": "darwin", "illumos": "solaris"}
+
+# Release tags (go1.21), which a toolchain sets for its own version and every earlier one
+_RE_GO_RELEASE_TAG = re.compile(r"go\d+\.\d+")
+
+# Build tags evaluated over both values at most; beyond that a constraint is not evaluated
+_MAX_FREE_BUILD_TAGS = 12
+
+
+def _compile_go_build_expression(expression):
+    """
+    Parse a build constraint into an expression tree over its tags
+
+    Args:
+        expression (str): Constraint in `//go:build` syntax
+
+    Returns:
+        tuple|None: (ast.expr, tags) where each ast.Name reads the tag at index <n> of tags as `_t<n>`
+            and only `and`, `or` and `not` combine them, or None when the constraint is malformed
+    """
+    if not _RE_GO_BUILD_EXPRESSION.fullmatch(expression):
+        return None
+    tags = list(dict.fromkeys(_RE_GO_BUILD_TAG_NAME.findall(expression)))
+    names = {name: f"_t{index}" for index, name in enumerate(tags)}
+    python_expression = _RE_GO_BUILD_TAG_NAME.sub(lambda match: f" {names[match.group(0)]} ", expression)
+    python_expression = python_expression.replace("&&", " and ").replace("||", " or ").replace("!", " not ")
+    try:
+        tree = ast.parse(python_expression.strip(), "<go:build>", mode="eval")
+    except SyntaxError:
+        return None
+    allowed = (ast.Expression, ast.BoolOp, ast.And, ast.Or, ast.UnaryOp, ast.Not, ast.Name, ast.Load)
+    if not all(isinstance(node, allowed) for node in ast.walk(tree)):
+        return None
+    return tree.body, tags
+
+
+def _satisfies_go_build(condition, tags, values):
+    """
+    Evaluate a parsed build constraint for one assignment of its tags
+
+    Args:
+        condition (ast.expr): Expression tree from _compile_go_build_expression
+        tags (list): The constraint's tags, in the order the tree's `_t<n>` names refer to
+        values (dict): Tag -> whether it is set; every tag of the constraint must be present
+
+    Returns:
+        bool
+    """
+    if isinstance(condition, ast.BoolOp):
+        results = (_satisfies_go_build(value, tags, values) for value in condition.values)
+        return all(results) if isinstance(condition.op, ast.And) else any(results)
+    if isinstance(condition, ast.UnaryOp):
+        return not _satisfies_go_build(condition.operand, tags, values)
+    return values[tags[int(condition.id[2:])]]
+
+
+def requires_go_build_tag(expression, tag):
+    """
+    Report whether a build constraint only holds when the given tag is set
+
+    The constraint is evaluated over every combination of its other tags, so for
+    `tools`, `tools && !windows` counts and `tools || linux` does not
+
+    Args:
+        expression (str): Constraint in `//go:build` syntax, as returned by parse_go_build_constraints
+        tag (str): Build tag, e.g. "tools"
+
+    Returns:
+        bool
+    """
+    compiled = _compile_go_build_expression(expression)
+    if compiled is None:
+        return False
+    condition, tags = compiled
+    others = [name for name in tags if name != tag]
+    if tag not in tags or len(tags) > _MAX_FREE_BUILD_TAGS:
+        return False
+    for combination in range(2 ** len(others)):
+        values = {name: bool(combination >> index & 1) for index, name in enumerate(others)}
+        if _satisfies_go_build(condition, tags, dict(values, **{tag: False})):
+            return False
+    return True
+
+
+def go_build_reachable(expression, platforms, custom_tags=()):
+    """
+    Report whether any common build configuration satisfies a build constraint
+
+    Each "GOOS/GOARCH" platform is tried with its GOOS, GOARCH, `unix` and implied GOOS
+    tags set (android also sets linux, ios darwin, illumos solaris) and every other
+    known GOOS and GOARCH unset. `cgo`, the compiler (`gc` or `gccgo`), release tags
+    such as `go1.21` and the custom tags may each be set or not; any other tag
+    (`ignore`, a misspelling, a project tag nobody passes) is never set. So
+    `linux && windows` and `ignore && linux` are unreachable, while `!go1.18` is not,
+    as older toolchains still build it
+
+    Args:
+        expression (str): Constraint in `//go:build` syntax, as returned by parse_go_build_constraints
+        platforms (list): "GOOS/GOARCH" build targets, e.g. "linux/amd64"
+        custom_tags (list): Tags a build may pass with -tags, e.g. "integration"
+
+    Returns:
+        bool|None: None when the constraint is malformed or has too many optional tags to evaluate
+    """
+    if not expression:
+        return True
+    compiled = _compile_go_build_expression(expression)
+    if compiled is None:
+        return None
+    condition, tags = compiled
+    custom_tags = set(custom_tags)
+    free = [
+        tag
+        for tag in tags
+        if tag in ("cgo", "gc", "gccgo") or tag in custom_tags or _RE_GO_RELEASE_TAG.fullmatch(tag)
+    ]
+    if len(free) > _MAX_FREE_BUILD_TAGS:
+        return None
+    for platform in platforms:
+        goos, _, goarch = platform.partition("/")
+        fixed = {tag: False for tag in tags if tag not in free}
+        for tag in (goos, goarch, _GO_IMPLIED_OS.get(goos)):
+            if tag in fixed:
+                fixed[tag] = True
+        if "unix" in fixed:
+            fixed["unix"] = goos in _GO_UNIX_OS
+        for combination in range(2 ** len(free)):
+            values = dict(fixed, **{tag: bool(combination >> index & 1) for index, tag in enumerate(free)})
+            if "gc" in values and "gccgo" in values and values["gc"] == values["gccgo"]:
+                # Exactly one compiler builds the file
+                continue
+            if _satisfies_go_build(condition, tags, values):
+                return True
+    return False
+
+
+def go_build_constraint_scope(expression):
+    """
+    Return the scope a build constraint gives a file's imports (see GO_BUILD_TAG_SCOPES)
+
+    Args:
+        expression (str): Constraint in `//go:build` syntax, as returned by parse_go_build_constraints
+
+    Returns:
+        str: "ignored-build" when the file needs the `ignore` tag, "tool" when it needs `tools`,
+            else ""
+    """
+    # A file that also needs `tools` is still never built by a plain `go build`
+    for tag in ("ignore", "tools"):
+        if requires_go_build_tag(expression, tag):
+            return GO_BUILD_TAG_SCOPES[tag]
+    return ""
+
+
+def is_go_example_file(rel_path, source):
+    """
+    Report whether a Go file only holds documentation examples
+
+    That is an `example_*.go` file (`example_test.go` included), or a `_test.go`
+    file whose test functions are all `ExampleXxx` functions, the testable and
+    runnable examples that godoc shows. A test file that also has `TestXxx`,
+    `BenchmarkXxx` or `FuzzXxx` functions is a regular test file
+
+    Args:
+        rel_path (str): Repo-relative file path
+        source (str): Go source text
+
+    Returns:
+        bool
+    """
+    name = rel_path.rsplit("/", 1)[-1]
+    if name.startswith("example_"):
+        return True
+    if not name.endswith("_test.go"):
+        return False
+    kinds = set(_RE_GO_TEST_FUNC.findall(source))
+    return kinds == {"Example"}
+
+
+def is_go_tools_constraint(expression):
+    """
+    Report whether a build constraint only holds when the `tools` tag is set
+
+    That is the convention for `tools.go` files (`//go:build tools`, or the legacy
+    `// +build tools`) that pin development tools with blank imports
+
+    Args:
+        expression (str): Constraint in `//go:build` syntax, as returned by parse_go_build_constraints
+
+    Returns:
+        bool
+    """
+    return requires_go_build_tag(expression, "tools")
+
+
+def is_go_ignore_constraint(expression):
+    """
+    Report whether a build constraint only holds when the `ignore` tag is set
+
+    `//go:build ignore` (or the legacy `// +build ignore`) keeps standalone programs,
+    typically run with `go run gen.go`, out of the package they sit in
+
+    Args:
+        expression (str): Constraint in `//go:build` syntax, as returned by parse_go_build_constraints
+
+    Returns:
+        bool
+    """
+    return requires_go_build_tag(expression, "ignore")
+
+
+def _plus_build_to_expression(lines):
+    """
+    Convert legacy `// +build` lines into `//go:build` expression syntax
+
+    Lines are ANDed together, space-separated options are ORed and
+    comma-separated terms within an option are ANDed
+
+    Args:
+        lines (list): Constraint text of each `// +build` line
+
+    Returns:
+        str: Equivalent expression, or "" when there are no lines
+    """
+    line_exprs = []
+    for line in lines:
+        options = [" && ".join(option.split(",")) for option in line.split()]
+        if len(options) > 1:
+            options = [f"({option})" if "&&" in option else option for option in options]
+        line_exprs.append(" || ".join(options))
+
+    if len(line_exprs) > 1:
+        line_exprs = [f"({expr})" if "||" in expr else expr for expr in line_exprs]
+    return " && ".join(line_exprs)
+
+
+def parse_cgo_directives(preamble):
+    """
+    Extract native library dependencies from a cgo preamble
+
+    Recognizes `#cgo pkg-config:` package names and `-l<name>` linker flags in
+    `#cgo LDFLAGS:`, with or without leading build constraints
+
+    Args:
+        preamble (str): Comment text immediately preceding `import "C"`
+
+    Returns:
+        List of (library_name, source) tuples in order of appearance
+    """
+    return [(library, source) for library, source, _ in parse_cgo_native_dependencies(preamble)]
+
+
+def parse_cgo_native_dependencies(preamble):
+    """
+    Extract native library dependencies from a cgo preamble with the build tags of their directives
+
+    Like parse_cgo_directives, plus the constraint a directive may carry before its
+    name (`#cgo linux,amd64 LDFLAGS: -lz` only links zlib on linux/amd64)
+
+    Args:
+        preamble (str): Comment text immediately preceding `import "C"`
+
+    Returns:
+        List of (library_name, source, tags) tuples in order of appearance; tags lists the
+        directive's tags as written, `!` included, or is empty
+    """
+    dependencies = []
+    for raw_line in preamble.splitlines():
+        line = raw_line.strip()
+        for prefix in ("//", "/*", "*"):
+            if line.startswith(prefix):
+                line = line[len(prefix) :].strip()
+                break
+        if line.endswith("*/"):
+            line = line[:-2].strip()
+
+        match = _RE_CGO_DIRECTIVE.match(line)
+        if not match:
+            continue
+
+        tags = list(dict.fromkeys(_RE_GO_BUILD_TAG.findall(match.group(1) or "")))
+        directive, values = match.group(2), match.group(3).split()
+        if directive == "pkg-config":
+            for value in values:
+                if not value.startswith("-"):
+                    dependencies.append((value, "cgo-pkg-config", tags))
+        else:
+            for index, value in enumerate(values):
+                if value == "-l" and index + 1 < len(values):
+                    dependencies.append((values[index + 1], "cgo-ldflags", tags))
+                elif value.startswith("-l") and len(value) > 2:
+                    dependencies.append((value[2:], "cgo-ldflags", tags))
+    return dependencies
+
+
+def go_generate_tool(command):
+    """
+    Name the tool a `//go:generate` command runs
+
+    The first word is the tool, reduced to its base name when written as a path
+    (`$GOPATH/bin/mockgen`, `../bin/protoc`). `go run <package>[@version]` runs
+    that package and `go tool <name>` a tool declared in go.mod; other `go`
+    subcommands run the go command itself
+
+    Args:
+        command (str): Directive text after `//go:generate`
+
+    Returns:
+        tuple: (tool, version) where tool is a base name ("mockgen"), the package path of
+            `go run` ("golang.org/x/tools/cmd/stringer") or "" for an empty command, and
+            version is the `@version` of `go run` or ""
+    """
+    words = command.split()
+    if not words:
+        return "", ""
+    if words[0] != "go" or len(words) < 3 or words[1] not in ("run", "tool"):
+        return posixpath.basename(to_posix_path(words[0])), ""
+    arguments = [word for word in words[2:] if not word.startswith("-")]
+    if not arguments:
+        return "go", ""
+    if words[1] == "tool":
+        return posixpath.basename(arguments[0]), ""
+    package, _, version = arguments[0].partition("@")
+    return package, version
+
+
+def parse_go_generate_directives(source):
+    """
+    Extract the `//go:generate` directives of a Go source file
+
+    Args:
+        source (str): Go source text
+
+    Returns:
+        list: {"line", "command", "tool", ["version"]} per directive in file order, with
+            tool and version from go_generate_tool; directives without a command are skipped
+    """
+    directives = []
+    for number, line in enumerate(source.splitlines(), start=1):
+        match = _RE_GO_GENERATE.match(line)
+        if not match:
+            continue
+        tool, version = go_generate_tool(match.group(1))
+        if not tool:
+            continue
+        directive = {"line": number, "command": match.group(1), "tool": tool}
+        if version:
+            directive["version"] = version
+        directives.append(directive)
+    return directives
+
+
+_GO_SIMPLE_ESCAPES = {"a": 7, "b": 8, "f": 12, "n": 10, "r": 13, "t": 9, "v": 11, "\\": 92, '"': 34}
+# Escape letter -> digit count of the hexadecimal escapes; octal escapes take three octal digits
+_GO_HEX_ESCAPES = {"x": 2, "u": 4, "U": 8}
+_HEX_DIGITS = frozenset("0123456789abcdefABCDEF")
+_OCTAL_DIGITS = frozenset("01234567")
+
+# Characters a Go compiler rejects in import paths besides spaces and non-graphic characters
+_GO_IMPORT_PATH_FORBIDDEN = frozenset('!"#$%&\'()*,:;<=>?[\\]^`{|}\ufffd')
+
+
+def decode_go_string_literal(literal):
+    """
+    Return the value of a Go string literal as the Go lexer reads it
+
+    Raw strings (`...`) are taken verbatim except for carriage returns, which Go
+    discards from them. Interpreted strings ("...") decode the escapes of the Go
+    spec: single-character, octal, \\x, \\u and \\U; octal and \\x escapes are bytes,
+    decoded as UTF-8 with replacement
+
+    Args:
+        literal (str): Literal source text including its quotes
+
+    Returns:
+        str|None: The string value, or None when literal is not a well-formed string literal
+    """
+    if len(literal) >= 2 and literal[0] == literal[-1] == "`":
+        body = literal[1:-1]
+        return None if "`" in body else body.replace("\r", "")
+    if len(literal) < 2 or literal[0] != '"' or literal[-1] != '"':
+        return None
+    body = literal[1:-1]
+    value = bytearray()
+    index = 0
+    while index < len(body):
+        char = body[index]
+        if char in '"\n':
+            return None
+        if char != "\\":
+            value.extend(char.encode("utf-8"))
+            index += 1
+            continue
+        escape = body[index + 1 : index + 2]
+        if escape in _GO_SIMPLE_ESCAPES:
+            value.append(_GO_SIMPLE_ESCAPES[escape])
+            index += 2
+        elif escape in _GO_HEX_ESCAPES:
+            digits = body[index + 2 : index + 2 + _GO_HEX_ESCAPES[escape]]
+            if len(digits) != _GO_HEX_ESCAPES[escape] or not set(digits) <= _HEX_DIGITS:
+                return None
+            code = int(digits, 16)
+            if escape == "x":
+                value.append(code)
+            elif code > 0x10FFFF or 0xD800 <= code <= 0xDFFF:
+                return None
+            else:
+                value.extend(chr(code).encode("utf-8"))
+            index += 2 + len(digits)
+        elif escape in _OCTAL_DIGITS:
+            digits = body[index + 1 : index + 4]
+            if len(digits) != 3 or not set(digits) <= _OCTAL_DIGITS or int(digits, 8) > 255:
+                return None
+            value.append(int(digits, 8))
+            index += 4
+        else:
+            return None
+    return value.decode("utf-8", errors="replace")
+
+
+def is_valid_go_import_path(import_path):
+    """
+    Determine whether a string is an import path a Go compiler accepts
+
+    Applies the implementation restriction of the Go spec: a non-empty string of
+    graphic characters without spaces, excluding !"#$%&'()*,:;<=>?[\\]^`{|} and U+FFFD
+
+    Args:
+        import_path (str): Decoded import path
+
+    Returns:
+        bool
+    """
+    return bool(import_path) and all(
+        char.isprintable() and not char.isspace() and char not in _GO_IMPORT_PATH_FORBIDDEN for char in import_path
+    )
+
+
+def is_go_relative_import(import_path):
+    """
+    Return True for filesystem-relative Go imports ("./x", "../x", ".", "..")
+
+    Args:
+        import_path (str): Import path as written in source
+
+    Returns:
+        bool
+    """
+    return import_path in (".", "..") or import_path.startswith(("./", "../"))
+
+
+def find_go_module_for_import(import_path, module_paths):
+    """
+    Return the longest module path that contains an import path
+
+    Matching is done on whole path segments so that `github.com/a/bc` never
+    matches a module declared as `github.com/a/b`
+
+    Args:
+        import_path (str): Import path as written in source
+        module_paths (iterable): Candidate module paths (e.g. go.mod requires)
+
+    Returns:
+        str|None: Best-matching module path, or None when nothing matches
+    """
+    best = None
+    for module_path in module_paths:
+        if import_path == module_path or import_path.startswith(module_path + "/"):
+            if best is None or len(module_path) > len(best):
+                best = module_path
+    return best
+
+
+def find_go_module_for_file(rel_path, go_modules):
+    """
+    Return the module whose go.mod is the nearest ancestor of a source file
+
+    Args:
+        rel_path (str): Repo-relative path of the source file
+        go_modules (dict): Module path -> repo-relative directory holding its go.mod
+
+    Returns:
+        Tuple of (module path, POSIX module directory), or (None, None) when no module encloses the file
+    """
+    directory = posixpath.dirname(to_posix_path(rel_path)) or "."
+    best, best_dir = None, None
+    for module_path, module_dir in go_modules.items():
+        module_dir = posixpath.normpath(to_posix_path(module_dir))
+        if module_dir != "." and directory != module_dir and not directory.startswith(f"{module_dir}/"):
+            continue
+        if best_dir is None or best_dir == "." or (module_dir != "." and len(module_dir) > len(best_dir)):
+            best, best_dir = module_path, module_dir
+    return best, best_dir
+
 
 class GoImportVisitor(TreeVisitor):
     """
     Visitor for extracting imports from Go tree
     """
 
-    def __init__(self, rel_path, file_components_dict, local_resolver_func):
+    def __init__(
+        self,
+        rel_path,
+        file_components_dict,
+        local_resolver_func,
+        module_versions=None,
+        module_replacements=None,
+        module_checksums=None,
+        go_module_path=None,
+        workspace_modules=None,
+        module_dir=".",
+        build_constraint="",
+        build_tags=None,
+        generated=False,
+        example=False,
+        positions=False,
+    ):
         super().__init__()
         self.rel_path = rel_path
         self.file_components_dict = file_components_dict
         self.imports = []  # External imports
         self.local_imports = []  # Resolved local import paths
+        self.import_evidence = []  # Per-import metadata for external imports
         self._resolve_local = local_resolver_func  # Store resolver
+        self.module_versions = module_versions or {}
+        self.module_replacements = module_replacements or {}
+        self.module_checksums = module_checksums or {}
+        self.go_module_path = go_module_path
+        self.workspace_modules = workspace_modules or []
+        self.module_dir = module_dir  # Repo-relative directory of go_module_path's go.mod
+        self.build_constraint = build_constraint
+        self.build_tags = build_tags or []
+        self.generated = generated  # File carries the "Code generated ... DO NOT EDIT." marker
+        # "tool" (blank imports here pin development tools), "ignored-build" (never built) or ""
+        self.constraint_scope = go_build_constraint_scope(build_constraint)
+        # The file only builds with cgo enabled: its constraint needs the `cgo` tag or it imports "C"
+        self.requires_cgo = requires_go_build_tag(build_constraint, "cgo")
+        self.example = example  # Only documentation examples (see is_go_example_file)
+        self.positions = positions  # Record the line and column of each import spec in its evidence
 
     def visit_import_declaration(self, node):
         """
@@ -45,7 +1250,45 @@ class GoImportVisitor(TreeVisitor):
 
             if package_path_node:
                 package_path = self._decode_import_path(package_path_node)
-                self._resolve_and_record_import(package_path)
+                if package_path is None:
+                    continue
+                if package_path == "C":
+                    # cgo pseudo-package; its preamble may declare native libraries
+                    self._record_cgo_preamble(node)
+                    continue
+                self._resolve_and_record_import(
+                    package_path, self._import_kind(spec), self._import_alias(spec), self._import_position(node, spec)
+                )
+
+    def _record_cgo_preamble(self, node):
+        """
+        Record native dependencies declared in the comment preceding `import "C"`
+
+        Go only compiles a file importing "C" with cgo enabled, so every native dependency
+        requires cgo and lists `cgo` among its build tags, after the file's own tags and
+        before those of its `#cgo` directive
+
+        Args:
+            node: The import_declaration node importing "C"
+
+        Mutates:
+            self.import_evidence, self.requires_cgo
+        """
+        self.requires_cgo = True
+        comments = []
+        next_row = node.start_point[0]
+        sibling = node.prev_sibling
+        # The preamble must sit directly above the import, with no blank line in between
+        while sibling is not None and sibling.type == "comment" and sibling.end_point[0] >= next_row - 1:
+            comments.insert(0, sibling.text.decode("utf-8", errors="ignore"))
+            next_row = sibling.start_point[0]
+            sibling = sibling.prev_sibling
+
+        for library, source, tags in parse_cgo_native_dependencies("\n".join(comments)):
+            build_tags = list(dict.fromkeys([*self.build_tags, "cgo", *tags]))
+            self.import_evidence.append(
+                {"native_dependency": library, "source": source, "requires_cgo": True, "build_tags": build_tags}
+            )
 
     def _collect_import_specs(self, node):
         """
@@ -82,47 +1325,263 @@ class GoImportVisitor(TreeVisitor):
                 return spec_child
         return None
 
+    def _import_position(self, node, spec):
+        """
+        Return where an import appears: its spec inside a grouped block, else the import declaration
+
+        Args:
+            node: The import_declaration node
+            spec: One of its 'import_spec' nodes
+
+        Returns:
+            dict|None: {"line", "column"}, both 1-based with the column counted in bytes as go/token
+                does, or None unless positions are recorded
+        """
+        if not self.positions:
+            return None
+        row, column = (spec if spec.parent is not node else node).start_point
+        return {"line": row + 1, "column": column + 1}
+
+    def _import_kind(self, spec):
+        """
+        Classify an import spec by the name it binds
+
+        Args:
+            spec: An 'import_spec' node
+
+        Returns:
+            str: "blank" for `import _ "pkg"` (side effects only), "dot" for `import . "pkg"`
+                (exported identifiers merged into the file scope), "aliased" for `import name "pkg"`,
+                otherwise "named"
+        """
+        for spec_child in spec.children:
+            if spec_child.type == "blank_identifier":
+                return "blank"
+            if spec_child.type == "dot":
+                return "dot"
+            if spec_child.type == "package_identifier":
+                return "aliased"
+        return "named"
+
+    def _import_alias(self, spec):
+        """
+        Return the local name bound by an aliased import spec
+
+        Args:
+            spec: An 'import_spec' node
+
+        Returns:
+            str: The alias (e.g. "log" for `import log "github.com/sirupsen/logrus"`), or None when unaliased
+        """
+        for spec_child in spec.children:
+            if spec_child.type == "package_identifier":
+                return spec_child.text.decode("utf-8")
+        return None
+
     def _decode_import_path(self, path_node):
         """
-        Decode and strip quotes from a Go string literal path node
+        Decode a Go string literal path node (see decode_go_string_literal)
 
         Args:
             path_node: The string literal node (interpreted or raw)
 
         Returns:
-            Decoded import path string
+            str|None: Decoded import path, or None when the literal is malformed or the path invalid
+                (see is_valid_go_import_path), as for a node tree-sitter inserted to recover from an error
         """
-        return path_node.text.decode("utf-8").strip('"')
+        import_path = decode_go_string_literal(path_node.text.decode("utf-8", errors="replace"))
+        return import_path if import_path is not None and is_valid_go_import_path(import_path) else None
 
-    def _resolve_and_record_import(self, package_path):
+    def _resolve_and_record_import(self, package_path, import_kind="named", alias=None, position=None):
         """
         Use self._resolve_local to classify and record imports
 
+        Args:
+            package_path (str): Import path as written in source
+            import_kind (str): How the import binds its package ("named", "aliased", "dot" or "blank")
+            alias (str): Local name for aliased imports, omitted from evidence otherwise
+            position (dict): Optional {"line", "column"} of the import, added to its evidence
+
         Mutates:
-            self.local_imports, self.imports, self.file_components_dict
+            self.local_imports, self.imports, self.import_evidence, self.file_components_dict
         """
         resolved_local_path = self._resolve_local(self.rel_path, package_path)
         if resolved_local_path:
             self.local_imports.append(resolved_local_path)
-        else:
+        workspace_module = None
+        if not is_go_relative_import(package_path):
+            workspace_module = find_go_module_for_import(package_path, self.workspace_modules)
+            if workspace_module and self._is_first_party(package_path):
+                # The owning module's packages may also extend the path of a module enclosing it
+                if len(workspace_module) < len(self.go_module_path):
+                    workspace_module = None
+        if is_go_relative_import(package_path):
+            # Relative imports always name packages in this repository, even when no file resolves
+            self._record_local_import(
+                package_path,
+                import_kind,
+                alias,
+                self.go_module_path or "",
+                self._module_relative_import(package_path),
+                position,
+            )
+        elif workspace_module:
+            # Another module of the same go.work workspace
+            self._record_local_import(package_path, import_kind, alias, workspace_module, package_path, position)
+        elif self._is_first_party(package_path):
+            # Module-qualified import of a package in this module (including internal/ packages),
+            # whether or not it resolves to a single file
+            self._record_local_import(package_path, import_kind, alias, self.go_module_path, package_path, position)
+        elif not resolved_local_path:
             self.imports.append(package_path)
+            module_path = find_go_module_for_import(package_path, self.module_versions)
+            scope = "external"
+            if is_go_stdlib(package_path):
+                scope = "stdlib"
+            elif self.constraint_scope == "ignored-build":
+                scope = "ignored-build"
+            elif self.constraint_scope == "tool" and import_kind == "blank":
+                scope = "tool"
+            elif self.example:
+                scope = "example"
+            evidence = {
+                "import": package_path,
+                "import_kind": import_kind,
+                "scope": scope,
+                "module": module_path or "",
+                "version": self.module_versions.get(module_path, ""),
+                "build_tags": list(self.build_tags),
+                "build_constraint": self.build_constraint,
+            }
+            if alias:
+                evidence["alias"] = alias
+            if position:
+                evidence.update(position)
+            if self.generated:
+                evidence["generated"] = True
+            replacement = self.module_replacements.get(module_path)
+            if replacement:
+                evidence["replaced_from"] = module_path
+                evidence["replaced_to"] = _format_go_replacement(replacement)
+            checksum = self.module_checksums.get(go_sum_key(module_path, evidence["version"], replacement))
+            if checksum:
+                evidence["checksum"] = checksum
+            self.import_evidence.append(evidence)
             import_name = package_path.split("/")[-1]
             component_name = f"{package_path}.{import_name}"
             self.file_components_dict[self.rel_path].append((package_path, component_name))
+        else:
+            # Module-qualified import of a package in this module
+            self._record_local_import(
+                package_path, import_kind, alias, self.go_module_path or "", package_path, position
+            )
+
+    def _is_first_party(self, package_path):
+        """
+        Return True when an import path names a package of the repository's own module
+
+        The path must equal the owning go.mod's module path or extend it by whole
+        segments, and must not belong to a longer module the go.mod requires
+        (a nested module published from the same repository is a dependency)
+
+        Args:
+            package_path (str): Import path as written in source
+
+        Returns:
+            bool
+        """
+        if not self.go_module_path or find_go_module_for_import(package_path, [self.go_module_path]) is None:
+            return False
+        required = find_go_module_for_import(package_path, self.module_versions)
+        return required is None or len(required) <= len(self.go_module_path)
+
+    def _record_local_import(self, package_path, import_kind, alias, module_path, resolved_import, position=None):
+        """
+        Append evidence for an import of a package that lives in this repository
+
+        Args:
+            package_path (str): Import path as written in source
+            import_kind (str): How the import binds its package
+            alias (str): Local name for aliased imports, or None
+            module_path (str): Module the package belongs to, or "" when unknown
+            resolved_import (str): Module-qualified import path, or "" when it cannot be determined
+            position (dict): Optional {"line", "column"} of the import
+
+        Mutates:
+            self.import_evidence
+        """
+        evidence = {
+            "import": package_path,
+            "import_kind": import_kind,
+            "scope": "local",
+            "module": module_path,
+            "version": "",
+            "resolved_import": resolved_import,
+            "build_tags": list(self.build_tags),
+            "build_constraint": self.build_constraint,
+        }
+        if alias:
+            evidence["alias"] = alias
+        if position:
+            evidence.update(position)
+        if self.generated:
+            evidence["generated"] = True
+        self.import_evidence.append(evidence)
+
+    def _module_relative_import(self, package_path):
+        """
+        Rewrite a relative import against the module path and the importing file's directory
+
+        Args:
+            package_path (str): Relative import such as "./utils" or "../config"
+
+        Returns:
+            str: Module-qualified import path, or "" when no module path is known
+                or the import escapes the module root
+        """
+        if not self.go_module_path:
+            return ""
+        base_dir = posixpath.dirname(to_posix_path(self.rel_path))
+        target = posixpath.normpath(posixpath.join(base_dir, package_path))
+        if self.module_dir != ".":
+            target = posixpath.relpath(target, self.module_dir)
+        if target == ".":
+            return self.go_module_path
+        if target == ".." or target.startswith("../"):
+            return ""
+        return f"{self.go_module_path}/{target}"
 
 
 class GoLanguageHandler(LanguageHandler):
     """Handler for Go language"""
 
+    language = "go"
+    local_resolver_method = "resolve_go"
+
     def __init__(self, logger=None):
         """
         Args:
             logger (Logger): Optional logger instance
         """
         self.logger = logger
+        self.module_versions = {}  # Required module path -> version, merged across go.mod files
+        self.module_replacements = {}  # Required module path -> applicable replace directive
+        self.module_checksums = {}  # "module@version" -> h1: hash from go.sum
+        self.workspace_modules = {}  # Module path -> absolute directory, from go.work `use` directives
+        self.workspace_replacements = {}  # go.work replace directives, which take precedence over go.mod
+        self.go_mod_modules = {}  # Declared module path -> absolute directory, for every go.mod found
+        self.module_requirements = {}  # Declared module path -> {required module path: version} from its go.mod
+        self.module_deprecations = {}  # Declared module path -> {"deprecated", "retract"} from its go.mod
+        # Declared module path -> {replaced module path: absolute directory} for its go.mod's local replaces
+        self.module_local_replacements = {}
+        self.go_mod_files = {}  # Absolute go.mod path -> declared module path, for every go.mod read
+        self.replace_directives = {}  # Absolute go.mod or go.work path -> its replace map (see parse_go_mod_file)
+        self.workspace_uses = {}  # Absolute go.work path -> [(absolute use directory, declared module path)]
+        self.go_sum_modules = {}  # Absolute go.sum path -> its module versions (see parse_go_sum_modules)
+        self.vendor_modules = {}  # Absolute vendor/modules.txt path -> its modules (see parse_vendor_modules_txt)
 
     def get_manifest_files(self):
-        return ["go.mod", "go.sum"]
+        return ["go.mod", "go.sum", "go.work", "modules.txt", *BAZEL_GO_MANIFESTS]
 
     def get_file_extensions(self):
         return [".go"]
@@ -133,38 +1592,257 @@ class GoLanguageHandler(LanguageHandler):
         if basename == "go.mod":
             try:
                 content = self.read_file_content(file_path, secure_file_ops)
-                # Look for require block or individual require lines
-                require_pattern = r"require\s+\((.*?)\)|require\s+([^\s]+)\s+v[^\s]+"
-                matches = re.finditer(require_pattern, content, re.DOTALL)
-
-                for match in matches:
-                    if match.group(1):  # Block require
-                        requires = match.group(1)
-                        dep_pattern = r"([^\s]+)\s+v[0-9.]+"
-                        deps = re.findall(dep_pattern, requires)
-                        for dep in deps:
-                            packages_dict[dep] = {"ecosystem": "go"}
-                    elif match.group(2):  # Single require line
-                        dep = match.group(2)
-                        packages_dict[dep] = {"ecosystem": "go"}
+                go_mod = parse_go_mod_file(content)
+                checksums = self._read_sibling_go_sum(file_path, secure_file_ops)
+                declared = go_mod["module"]
+                if go_mod["replace"]:
+                    self.replace_directives[file_path] = go_mod["replace"]
+                if declared:
+                    self.go_mod_files[file_path] = declared
+                    self.go_mod_modules.setdefault(declared, os.path.dirname(file_path))
+                    self.module_requirements.setdefault(declared, dict(go_mod["require"]))
+                    if go_mod["deprecated"] or go_mod["retract"]:
+                        self.module_deprecations.setdefault(
+                            declared, {"deprecated": go_mod["deprecated"], "retract": go_mod["retract"]}
+                        )
+                for module_path, version in go_mod["require"].items():
+                    package_data = {
+                        "ecosystem": "go",
+                        "version": version,
+                        "module_path": module_path,
+                        "direct": module_path not in go_mod["indirect"],
+                    }
+                    if declared:
+                        package_data["go_modules"] = [declared]
+                    replacement = find_go_replacement(module_path, version, go_mod["replace"])
+                    if replacement:
+                        package_data["replace"] = dict(replacement)
+                        self.module_replacements.setdefault(module_path, replacement)
+                        if replacement["local"]:
+                            # Local targets are relative to the directory of the go.mod declaring them
+                            replacement_dir = os.path.normpath(
+                                os.path.join(os.path.dirname(file_path), replacement["path"])
+                            )
+                            self.module_local_replacements.setdefault(declared, {})[module_path] = replacement_dir
+                    checksum = checksums.get(go_sum_key(module_path, version, replacement))
+                    if checksum:
+                        package_data["checksum"] = checksum
+                    packages_dict[module_path] = package_data
+                    self.module_versions.setdefault(module_path, version)
 
             except FileOperationError as e:
                 logger.error(f"Failed to process Go mod file {file_path}: {e}")
             except Exception as e:
                 logger.error(f"Unexpected error processing Go mod file {file_path}", exception=e)
+        elif basename == "modules.txt" and os.path.basename(os.path.dirname(file_path)) == "vendor":
+            try:
+                content = self.read_file_content(file_path, secure_file_ops)
+                self.vendor_modules[file_path] = parse_vendor_modules_txt(content)
+                for module_path, info in self.vendor_modules[file_path].items():
+                    package_data = {
+                        "ecosystem": "go",
+                        "version": info["version"],
+                        "module_path": module_path,
+                        "vendored": True,
+                        "explicit": info["explicit"],
+                    }
+                    if info["replace"]:
+                        package_data["replace"] = dict(info["replace"])
+                        self.module_replacements.setdefault(module_path, info["replace"])
+                    packages_dict[module_path] = package_data
+                    if info["version"]:
+                        self.module_versions.setdefault(module_path, info["version"])
+            except FileOperationError as e:
+                logger.error(f"Failed to process Go vendor file {file_path}: {e}")
+            except Exception as e:
+                logger.error(f"Unexpected error processing Go vendor file {file_path}", exception=e)
+        elif basename == "go.work":
+            try:
+                go_work = parse_go_work(self.read_file_content(file_path, secure_file_ops))
+                work_dir = os.path.dirname(file_path)
+                uses = self.workspace_uses.setdefault(file_path, [])
+                for use_dir in go_work["use"]:
+                    module_dir = os.path.normpath(os.path.join(work_dir, use_dir))
+                    module_path = self._read_go_mod_module(module_dir, secure_file_ops)
+                    if module_path:
+                        self.workspace_modules[module_path] = module_dir
+                        uses.append((module_dir, module_path))
+                self.workspace_replacements.update(go_work["replace"])
+                if go_work["replace"]:
+                    self.replace_directives[file_path] = go_work["replace"]
+            except FileOperationError as e:
+                logger.error(f"Failed to process Go work file {file_path}: {e}")
+            except Exception as e:
+                logger.error(f"Unexpected error processing Go work file {file_path}", exception=e)
+        elif is_manifest_name(basename, BAZEL_GO_MANIFESTS):
+            try:
+                self._add_bazel_go_repositories(self.read_file_content(file_path, secure_file_ops), packages_dict)
+            except FileOperationError as e:
+                logger.error(f"Failed to process Bazel file {file_path}: {e}")
+            except Exception as e:
+                logger.error(f"Unexpected error processing Bazel file {file_path}", exception=e)
+        elif basename == "go.sum":
+            try:
+                content = self.read_file_content(file_path, secure_file_ops)
+                for key, checksum in parse_go_sum(content).items():
+                    self.module_checksums.setdefault(key, checksum)
+                self.go_sum_modules[file_path] = parse_go_sum_modules(content)
+            except FileOperationError as e:
+                logger.error(f"Failed to process Go sum file {file_path}: {e}")
+            except Exception as e:
+                logger.error(f"Unexpected error processing Go sum file {file_path}", exception=e)
 
         return packages_dict
 
+    def _add_bazel_go_repositories(self, content, packages_dict):
+        """
+        Add the modules declared by Gazelle go_repository rules as Go dependencies
+
+        Each module gets the rule's `version` (or `tag`), `replace` target and `sum`
+        checksum, as a go.mod require would; a module a go.mod requires too is merged
+        like any package found in several manifests, with differing versions listed
+        under version_conflicts
+
+        Args:
+            content (str): Text of a WORKSPACE, WORKSPACE.bazel or .bzl file
+            packages_dict (dict): Packages being collected, mutated in place
+        """
+        for rule in parse_bazel_go_repositories(content):
+            module_path = rule["importpath"]
+            version = rule.get("version") or rule.get("tag") or ""
+            package_data = {"ecosystem": "go", "version": version, "module_path": module_path, "direct": True}
+            package_data["bazel_rule"] = rule.get("name", "")
+            if rule.get("commit"):
+                package_data["commit"] = rule["commit"]
+            replacement = None
+            if rule.get("replace"):
+                replacement = {"path": rule["replace"], "version": version, "local": False}
+                package_data["replace"] = dict(replacement)
+                self.module_replacements.setdefault(module_path, replacement)
+            if rule.get("sum"):
+                package_data["checksum"] = rule["sum"]
+                key = go_sum_key(module_path, version, replacement)
+                if key:
+                    self.module_checksums.setdefault(key, rule["sum"])
+            packages_dict[module_path] = package_data
+            self.module_versions.setdefault(module_path, version)
+
+    def local_replacement_dirs(self):
+        """
+        Return the directory each locally replaced module is taken from
+
+        Returns:
+            dict: Replaced module path -> absolute directory, from the first go.mod replacing it
+        """
+        dirs = {}
+        for replacements in self.module_local_replacements.values():
+            for module_path, replacement_dir in replacements.items():
+                dirs.setdefault(module_path, replacement_dir)
+        return dirs
+
+    def replacement_targets(self):
+        """
+        Return the modules replace directives (and Bazel `replace` attributes) substitute for others
+
+        Returns:
+            set: Module paths of the non-local replacement targets
+        """
+        replacements = list(self.module_replacements.values())
+        for replaces in self.replace_directives.values():
+            replacements.extend(replaces.values())
+        return {replacement["path"] for replacement in replacements if not replacement.get("local")}
+
+    def _read_sibling_go_sum(self, go_mod_path, secure_file_ops=None):
+        """
+        Parse the go.sum next to a go.mod, if present
+
+        Args:
+            go_mod_path (str): Path to the go.mod file
+            secure_file_ops (object): Optional SecureFileOps instance
+
+        Returns:
+            dict: Checksums as returned by parse_go_sum (empty when go.sum is absent)
+        """
+        go_sum_path = os.path.join(os.path.dirname(go_mod_path), "go.sum")
+        exists = secure_file_ops.exists(go_sum_path) if secure_file_ops else os.path.exists(go_sum_path)
+        if not exists:
+            return {}
+        try:
+            checksums = parse_go_sum(self.read_file_content(go_sum_path, secure_file_ops))
+        except (FileOperationError, OSError) as e:
+            logger.warning(f"Failed to read Go sum file {go_sum_path}: {e}")
+            return {}
+        for key, checksum in checksums.items():
+            self.module_checksums.setdefault(key, checksum)
+        return checksums
+
+    def _read_go_mod_module(self, module_dir, secure_file_ops=None):
+        """
+        Read the module path declared by the go.mod in a workspace directory
+
+        Args:
+            module_dir (str): Directory named by a go.work `use` directive
+            secure_file_ops (object): Optional SecureFileOps instance
+
+        Returns:
+            str: Declared module path, or "" when the directory has no readable go.mod
+        """
+        go_mod_path = os.path.join(module_dir, "go.mod")
+        exists = secure_file_ops.exists(go_mod_path) if secure_file_ops else os.path.exists(go_mod_path)
+        if not exists:
+            logger.warning(f"go.work uses {module_dir}, but it has no go.mod")
+            return ""
+        try:
+            return parse_go_mod_file(self.read_file_content(go_mod_path, secure_file_ops))["module"]
+        except (FileOperationError, OSError) as e:
+            logger.warning(f"Failed to read Go mod file {go_mod_path}: {e}")
+            return ""
+
+    def _effective_replacements(self):
+        """
+        Merge go.mod replacements with go.work replacements, the latter winning
+
+        Returns:
+            dict: Required module path -> applicable replace directive
+        """
+        if not self.workspace_replacements:
+            return self.module_replacements
+        replacements = dict(self.module_replacements)
+        for module_path, version in self.module_versions.items():
+            replacement = find_go_replacement(module_path, version, self.workspace_replacements)
+            if replacement:
+                replacements[module_path] = replacement
+        return replacements
+
     def normalize_package_name(self, package_path):
         """Go imports are usually full paths, return as is if external"""
-        if package_path.startswith("."):
+        if is_go_relative_import(package_path) or package_path.startswith("."):
             return None  # Relative import
         # Assume external if contains '/' or '.'
         if "/" in package_path or "." in package_path:
             return package_path
         return None  # Likely standard library
 
-    def extract_imports(self, tree_node, rel_path, file_components_dict, local_resolver_func, logger=None):
+    def extract_options(self, context, evidence):
+        return {
+            "import_evidence_dict": evidence,
+            "go_module_path": context.go_module_path,
+            "go_modules": context.go_modules,
+            "go_workspace_modules": context.go_workspace_modules,
+        }
+
+    def extract_imports(
+        self,
+        tree_node,
+        rel_path,
+        file_components_dict,
+        local_resolver_func,
+        logger=None,
+        import_evidence_dict=None,
+        go_module_path=None,
+        go_modules=None,
+        go_workspace_modules=None,
+    ):
         """
         Extract external package imports and resolved local imports from a Go source file
 
@@ -174,10 +1852,57 @@ class GoLanguageHandler(LanguageHandler):
             file_components_dict (dict): Dictionary to track imported external components
             local_resolver_func (callable): Function to resolve local imports
             logger (Logger): Optional logger instance for debug output
+            import_evidence_dict (dict): Optional dictionary to receive per-import evidence keyed by file
+            go_module_path (str): Optional module path of the analyzed repository, used to qualify relative imports
+            go_modules (dict): Optional module path -> repo-relative directory for every go.mod in the repository;
+                the nearest enclosing one owns the file and supplies its required versions
+            go_workspace_modules (dict): Optional modules whose imports are local code (go.work members, or
+                every go.mod module when a repository has several and no go.work)
 
         Returns:
             Tuple of (external_imports, local_imports)
         """
-        visitor = GoImportVisitor(rel_path, file_components_dict, local_resolver_func)
+        source = tree_node.text.decode("utf-8", errors="ignore")
+        build_constraint, build_tags = parse_go_build_constraints(source)
+        module_dir = "."
+        module_versions = self.module_versions
+        owner, owner_dir = find_go_module_for_file(rel_path, go_modules or {})
+        if owner:
+            go_module_path, module_dir = owner, owner_dir
+            if owner in self.module_requirements:
+                # The owning go.mod's requirements win over versions merged from the other modules
+                module_versions = {**self.module_versions, **self.module_requirements[owner]}
+        workspace_modules = go_workspace_modules or self.workspace_modules
+        visitor = GoImportVisitor(
+            rel_path,
+            file_components_dict,
+            local_resolver_func,
+            module_versions=module_versions,
+            module_replacements=self._effective_replacements(),
+            module_checksums=self.module_checksums,
+            go_module_path=go_module_path,
+            # The owning module's own packages are classified by the first-party check
+            workspace_modules=[module for module in workspace_modules if module != go_module_path],
+            module_dir=module_dir,
+            build_constraint=build_constraint,
+            build_tags=build_tags,
+            generated=is_go_generated_file(source),
+            example=is_go_example_file(rel_path, source),
+            positions=GoAnalysisConfig.RECORD_IMPORT_POSITIONS,
+        )
         visitor.visit(tree_node)
+        if visitor.requires_cgo:
+            # Gated behind cgo like the file's native dependencies, wherever `import "C"` sits
+            for entry in visitor.import_evidence:
+                if "import" in entry:
+                    entry["requires_cgo"] = True
+        for directive in parse_go_generate_directives(source):
+            # Build-time tools run by `go generate`; not imports, so only recorded as evidence
+            entry = {"generate": directive["tool"], "command": directive["command"], "line": directive["line"]}
+            if "version" in directive:
+                entry["version"] = directive["version"]
+            entry["scope"] = "generate"
+            visitor.import_evidence.append(entry)
+        if import_evidence_dict is not None and visitor.import_evidence:
+            import_evidence_dict[rel_path].extend(visitor.import_evidence)
         return visitor.imports, visitor.local_imports
@@ -351,6 +351,9 @@ class JSImportVisitor(TreeVisitor):
 class JavaScriptLanguageHandler(LanguageHandler):
     """Handler for JavaScript language"""
 
+    language = "javascript"
+    local_resolver_method = "resolve_js"
+
     def __init__(self, logger=None):
         """
         Args:
@@ -0,0 +1,192 @@
+"""
+Makefile analyzer: Go tools installed or run by `go install` and `go run`
+
+Makefiles often pin development tooling, e.g. `go install
+github.com/golangci/golangci-lint/cmd/golangci-lint@v1.55.2`, that no source
+file imports. Only those invocations are recognized; the rest of the Make
+syntax is not interpreted, except that `$(VAR)` and `${VAR}` in the package
+and version are replaced by simple variable assignments of the same file. The
+invocations are recorded as evidence scoped "tool" and become dependencies in
+gardener.analysis.make_tools
+"""
+
+import fnmatch
+import posixpath
+import re
+
+# Makefile basenames, as shell-style patterns
+MAKEFILE_NAMES = ("Makefile", "makefile", "GNUmakefile", "*.mk")
+
+# `go install` / `go run` as a command word, also through $(GO) and recipe prefixes like `@`
+_RE_GO_TOOL_COMMAND = re.compile(
+    r"(?:^|[\s;&|(`@+-])((?:go|\$\(GO\)|\$\{GO\})\s+(install|run)\s+((?:\$\([^)]*\)|\$\{[^}]*\}|[^;&|()`#$])*))"
+)
+_RE_ASSIGNMENT = re.compile(r"^(?:export\s+|override\s+)?([A-Za-z_][A-Za-z0-9_.-]*)\s*(?::::|::|[:?+!])?=\s*(.*)$")
+_RE_VARIABLE = re.compile(r"\$(?:\(([A-Za-z_][A-Za-z0-9_.-]*)\)|\{([A-Za-z_][A-Za-z0-9_.-]*)\})")
+# Build flags taking their value as the next word
+_FLAGS_WITH_VALUES = frozenset(
+    "-C -modfile -mod -tags -ldflags -gcflags -asmflags -p -exec -overlay -pkgdir -toolexec -o".split()
+)
+
+
+def is_makefile(path):
+    """
+    Return True when a file name follows a Makefile naming convention
+
+    Args:
+        path (str): File path or basename
+
+    Returns:
+        bool
+    """
+    basename = posixpath.basename(str(path).replace("\\", "/"))
+    return any(fnmatch.fnmatchcase(basename, pattern) for pattern in MAKEFILE_NAMES)
+
+
+def _logical_lines(content):
+    """
+    Join backslash-continued lines
+
+    Args:
+        content (str): Makefile text
+
+    Returns:
+        list: (first line number, joined text) pairs
+    """
+    lines = []
+    pending, start = [], 0
+    for number, line in enumerate(content.splitlines(), start=1):
+        if not pending:
+            start = number
+        if line.endswith("\\"):
+            pending.append(line[:-1])
+            continue
+        pending.append(line)
+        lines.append((start, " ".join(pending)))
+        pending = []
+    if pending:
+        lines.append((start, " ".join(pending)))
+    return lines
+
+
+def _substitute(text, variables):
+    return _RE_VARIABLE.sub(lambda match: variables.get(match.group(1) or match.group(2), match.group(0)), text)
+
+
+def _is_remote_package(path):
+    """
+    Return True for an import path of another module: "example.com/tool", not "./cmd/tool" or "main.go"
+    """
+    first = path.split("/")[0]
+    return "." in first and not path.startswith((".", "/", "$")) and not path.endswith(".go") and "$" not in path
+
+
+def parse_makefile_go_tools(content):
+    """
+    Extract the `go install` and `go run` invocations of remote packages from a Makefile
+
+    Args:
+        content (str): Makefile text
+
+    Returns:
+        list: {"line", "command", "subcommand", "package", ["version"]} per invocation in file
+            order, where subcommand is "install" or "run" and version the `@version` suffix; local
+            packages (`go run ./cmd/gen`) and packages named by unknown variables are skipped
+    """
+    logical = _logical_lines(content)
+    variables = {}
+    for _, text in logical:
+        if text.startswith("\t"):
+            continue
+        match = _RE_ASSIGNMENT.match(text.strip())
+        if match and "$(shell" not in match.group(2):
+            variables.setdefault(match.group(1), match.group(2).split("#")[0].strip())
+    invocations = []
+    for number, text in logical:
+        if text.strip().startswith("#"):
+            continue
+        for match in _RE_GO_TOOL_COMMAND.finditer(text):
+            words = match.group(3).split()
+            arguments = []
+            skip_value = False
+            for word in words:
+                if skip_value:
+                    skip_value = False
+                elif word.startswith("-"):
+                    skip_value = word in _FLAGS_WITH_VALUES
+                else:
+                    arguments.append(word)
+            if not arguments:
+                continue
+            package, _, version = _substitute(arguments[0], variables).partition("@")
+            if not _is_remote_package(package):
+                continue
+            invocation = {
+                "line": number,
+                "command": " ".join(match.group(1).split()),
+                "subcommand": match.group(2),
+                "package": package,
+            }
+            if version and "$" not in version:
+                invocation["version"] = version
+            invocations.append(invocation)
+    return invocations
+
+
+class MakefileAnalyzer:
+    """
+    Analyzer for Makefiles
+
+    Implements the Analyzer protocol (see treewalk.registry) without a
+    tree-sitter grammar; files are claimed by name, see MAKEFILE_NAMES
+    """
+
+    language = "make"
+
+    def __init__(self, logger=None):
+        """
+        Args:
+            logger (Logger): Optional logger instance
+        """
+        self.logger = logger
+
+    def matches(self, path):
+        """
+        Return True for files named like Makefiles
+
+        Args:
+            path (str): Repo-relative file path
+
+        Returns:
+            bool
+        """
+        return is_makefile(path)
+
+    def extract(self, path, context):
+        """
+        Report a Makefile's `go install` and `go run` invocations as tool evidence
+
+        The entries are not imports: the tools become dependencies afterwards, see
+        gardener.analysis.make_tools
+
+        Args:
+            path (str): Repo-relative file path
+            context (ExtractionContext): File content
+
+        Returns:
+            list: {"import", "scope": "tool", "kind", "line", "command", ["version"]} entries, where
+                import is the package path and kind "go-install" or "go-run"
+        """
+        entries = []
+        for invocation in parse_makefile_go_tools(context.code):
+            entry = {
+                "import": invocation["package"],
+                "scope": "tool",
+                "kind": f"go-{invocation['subcommand']}",
+                "line": invocation["line"],
+                "command": invocation["command"],
+            }
+            if "version" in invocation:
+                entry["version"] = invocation["version"]
+            entries.append(entry)
+        return entries
@@ -378,6 +378,9 @@ class PythonLanguageHandler(LanguageHandler):
     manifest files to extract imports, definitions, and references
     """
 
+    language = "python"
+    local_resolver_method = "resolve_python"
+
     def __init__(self, logger=None):
         """
         Args:
@@ -0,0 +1,325 @@
+"""
+Registry of language analyzers
+
+An analyzer tells the scanner which files belong to it (`matches`) and turns
+one file into import evidence (`extract`). The built-in tree-sitter handlers
+implement the protocol through LanguageHandler; further analyzers register
+themselves with register_analyzer or are discovered from installed
+distributions through the "gardener.analyzers" entry point group, e.g.
+
+    [project.entry-points."gardener.analyzers"]
+    terraform = "gardener_terraform:TerraformAnalyzer"
+"""
+
+from collections import defaultdict
+from importlib import metadata
+from typing import Protocol, runtime_checkable
+
+from gardener.treewalk.docker import DockerfileAnalyzer
+from gardener.treewalk.github_actions import GitHubActionsAnalyzer
+from gardener.treewalk.go import GoLanguageHandler
+from gardener.treewalk.javascript import JavaScriptLanguageHandler
+from gardener.treewalk.makefile import MakefileAnalyzer
+from gardener.treewalk.python import PythonLanguageHandler
+from gardener.treewalk.rust import RustLanguageHandler
+from gardener.treewalk.solidity import SolidityLanguageHandler
+from gardener.treewalk.typescript import TypeScriptLanguageHandler
+
+ENTRY_POINT_GROUP = "gardener.analyzers"
+
+# Analyzers shipped with Gardener, keyed by language; registration order is analysis order
+BUILTIN_ANALYZERS = {
+    "javascript": JavaScriptLanguageHandler,
+    "typescript": TypeScriptLanguageHandler,
+    "python": PythonLanguageHandler,
+    "go": GoLanguageHandler,
+    "rust": RustLanguageHandler,
+    "solidity": SolidityLanguageHandler,
+    "docker": DockerfileAnalyzer,
+    "github-actions": GitHubActionsAnalyzer,
+    "make": MakefileAnalyzer,
+}
+
+# Analyzers added through register_analyzer, keyed by language
+_REGISTERED = {}
+
+# Entry-point analyzers, loaded on first use
+_DISCOVERED = None
+
+
+@runtime_checkable
+class Analyzer(Protocol):
+    """
+    Interface every language analyzer implements
+
+    Analyzers are instantiated once per run with the run's logger, e.g.
+    `MyAnalyzer(logger)`. `get_manifest_files`, `process_manifest` and
+    `get_file_extensions` (see LanguageHandler) are optional
+
+    Attributes:
+        language (str): Language key, used by --languages and as the "language" of file records
+    """
+
+    language: str
+
+    def matches(self, path):
+        """
+        Return True when the file at a repo-relative path should be analyzed by this analyzer
+
+        Args:
+            path (str): Repo-relative file path
+
+        Returns:
+            bool
+        """
+
+    def extract(self, path, context):
+        """
+        Extract the imports of one file
+
+        Args:
+            path (str): Repo-relative file path
+            context (ExtractionContext): File content, resolvers and per-file output containers
+
+        Returns:
+            list: Evidence entries for the file, each a dict with at least "import" and "scope"
+                ("external", "local" or "stdlib"); "module" names the package an external import
+                belongs to and "resolved" lists the repo files a local import refers to
+        """
+
+
+class ExtractionError(Exception):
+    """
+    Raised by Analyzer.extract when a file cannot be analyzed; reported as a parse-failed diagnostic
+    """
+
+
+class AnalyzerUnavailableError(Exception):
+    """
+    Raised by Analyzer.extract when the analyzer cannot run at all (e.g. its parser is not installed)
+
+    The file is skipped with a warning instead of being reported as an error
+    """
+
+
+class ExtractionContext:
+    """
+    Inputs and per-file outputs of one Analyzer.extract call
+
+    Args:
+        code (str): File content
+        local_resolver (LocalImportResolver|None): Resolver for imports of repository files
+        logger (Logger|None): Optional logger
+
+    Attributes:
+        components (defaultdict): rel_path -> [(package, component)] pairs of imported components
+        evidence (defaultdict): rel_path -> evidence entries, for analyzers that record them directly
+        syntax_errors (list): Human-readable locations of recoverable syntax errors
+        external_imports (list|None): External packages, when reported with report_imports
+        local_imports (list|None): Resolved local files, when reported with report_imports
+    """
+
+    def __init__(self, code, local_resolver=None, logger=None):
+        self.code = code
+        self.local_resolver = local_resolver
+        self.logger = logger
+        self.components = defaultdict(list)
+        self.evidence = defaultdict(list)
+        self.syntax_errors = []
+        self.external_imports = None
+        self.local_imports = None
+
+    @property
+    def go_module_path(self):
+        """
+        Module path from the root go.mod, or None
+        """
+        return getattr(self.local_resolver, "go_module_path", None)
+
+    @property
+    def go_modules(self):
+        """
+        Module path -> repo-relative directory for every go.mod in the repository
+        """
+        return getattr(self.local_resolver, "go_modules", None) or {}
+
+    @property
+    def go_workspace_modules(self):
+        """
+        Module path -> repo-relative directory for modules whose imports are local code
+        """
+        return getattr(self.local_resolver, "go_workspace_modules", None) or {}
+
+    def report_imports(self, external_imports, local_imports):
+        """
+        Report the file's imports explicitly instead of deriving them from the returned evidence
+
+        Args:
+            external_imports (list): External package names
+            local_imports (list): Resolved repo-relative file paths
+        """
+        self.external_imports = external_imports
+        self.local_imports = local_imports
+
+    def imports_from(self, entries):
+        """
+        Return (external_imports, local_imports) for a file
+
+        Args:
+            entries (list): Evidence entries returned by Analyzer.extract
+
+        Returns:
+            tuple: Lists reported with report_imports, or else derived from the entries
+        """
+        if self.external_imports is not None or self.local_imports is not None:
+            return list(self.external_imports or []), list(self.local_imports or [])
+        external_imports = []
+        local_imports = []
+        for entry in entries:
+            if entry.get("scope") == "external":
+                name = entry.get("module") or entry.get("import")
+                if name and name not in external_imports:
+                    external_imports.append(name)
+            elif entry.get("scope") == "local":
+                for resolved in entry.get("resolved") or []:
+                    if resolved not in local_imports:
+                        local_imports.append(resolved)
+        return external_imports, local_imports
+
+
+def register_analyzer(analyzer_class):
+    """
+    Register an analyzer class under its `language`, replacing any analyzer with the same key
+
+    Usable as a class decorator
+
+    Args:
+        analyzer_class (type): Class implementing Analyzer
+
+    Returns:
+        type: analyzer_class, unchanged
+
+    Raises:
+        ValueError: If the class has no language key
+    """
+    language = getattr(analyzer_class, "language", None)
+    if not language or not isinstance(language, str):
+        raise ValueError(f"Analyzer {analyzer_class!r} must define a non-empty `language` string")
+    _REGISTERED[language] = analyzer_class
+    return analyzer_class
+
+
+def unregister_analyzer(language):
+    """
+    Remove an analyzer added with register_analyzer
+
+    Args:
+        language (str): Language key
+
+    Returns:
+        bool: True when an analyzer was removed
+    """
+    return _REGISTERED.pop(language, None) is not None
+
+
+def _discover_entry_points(logger=None):
+    """
+    Load the analyzers advertised under ENTRY_POINT_GROUP
+
+    Entry points that fail to load are skipped with a warning
+
+    Returns:
+        dict: language -> analyzer class
+    """
+    discovered = {}
+    try:
+        entry_points = metadata.entry_points(group=ENTRY_POINT_GROUP)
+    except Exception as exc:
+        if logger:
+            logger.warning(f"Could not list {ENTRY_POINT_GROUP} entry points: {exc}")
+        return discovered
+    for entry_point in entry_points:
+        try:
+            analyzer_class = entry_point.load()
+        except Exception as exc:
+            if logger:
+                logger.warning(f"Could not load analyzer entry point {entry_point.name!r}: {exc}")
+            continue
+        language = getattr(analyzer_class, "language", None) or entry_point.name
+        discovered[language] = analyzer_class
+    return discovered
+
+
+def analyzer_classes(logger=None):
+    """
+    Return every known analyzer class
+
+    Built-in analyzers come first; entry-point analyzers and then explicitly
+    registered ones override built-ins with the same language key
+
+    Args:
+        logger (Logger|None): Optional logger for entry-point load failures
+
+    Returns:
+        dict: language -> analyzer class
+    """
+    global _DISCOVERED
+    if _DISCOVERED is None:
+        _DISCOVERED = _discover_entry_points(logger)
+    classes = dict(BUILTIN_ANALYZERS)
+    classes.update(_DISCOVERED)
+    classes.update(_REGISTERED)
+    return classes
+
+
+def analyzer_languages():
+    """
+    Return the language keys of every known analyzer
+
+    Returns:
+        list: Language keys in registration order
+    """
+    return list(analyzer_classes())
+
+
+def create_analyzers(logger=None):
+    """
+    Instantiate every known analyzer for one run
+
+    Analyzers whose constructor raises are skipped with a warning
+
+    Args:
+        logger (Logger|None): Logger passed to each analyzer
+
+    Returns:
+        dict: language -> analyzer instance
+    """
+    analyzers = {}
+    for language, analyzer_class in analyzer_classes(logger).items():
+        try:
+            analyzer = analyzer_class(logger)
+        except Exception as exc:
+            if logger:
+                logger.warning(f"Could not create the {language} analyzer: {exc}")
+            continue
+        if not isinstance(analyzer, Analyzer):
+            if logger:
+                logger.warning(f"Ignoring the {language} analyzer: it does not implement matches() and extract()")
+            continue
+        analyzers[language] = analyzer
+    return analyzers
+
+
+__all__ = [
+    "Analyzer",
+    "AnalyzerUnavailableError",
+    "BUILTIN_ANALYZERS",
+    "ENTRY_POINT_GROUP",
+    "ExtractionContext",
+    "ExtractionError",
+    "analyzer_classes",
+    "analyzer_languages",
+    "create_analyzers",
+    "register_analyzer",
+    "unregister_analyzer",
+]
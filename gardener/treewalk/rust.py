@@ -424,6 +424,9 @@ class RustLanguageHandler(LanguageHandler):
     to extract imports, definitions, and references
     """
 
+    language = "rust"
+    local_resolver_method = "resolve_rust"
+
     def __init__(self, logger=None):
         """
         Args:
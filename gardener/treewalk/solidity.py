@@ -265,6 +265,9 @@ class SolidityLanguageHandler(LanguageHandler):
     Handler for Solidity language
     """
 
+    language = "solidity"
+    local_resolver_method = "resolve_solidity"
+
     def __init__(self, logger=None):
         """
         Args:
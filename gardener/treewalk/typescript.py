@@ -12,9 +12,10 @@ class TypeScriptLanguageHandler(JavaScriptLanguageHandler):
     Extends JavaScript handler with TypeScript-specific configuration
     """
 
+    language = "typescript"
+
     def __init__(self, logger):
         super().__init__(logger)
-        self.language = "typescript"
         self.logger = logger
 
     def get_file_extensions(self):
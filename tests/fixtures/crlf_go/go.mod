@@ -0,0 +1,9 @@
+module example.com/crlf
+
+go 1.21
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/google/uuid v1.6.0
+	github.com/pkg/errors v0.9.1
+)
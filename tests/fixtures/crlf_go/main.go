@@ -0,0 +1,15 @@
+// Written on Windows: every line ends with CRLF
+package main
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"example.com/crlf/mixed"
+)
+
+func main() {
+	fmt.Println(gin.Version, uuid.New(), mixed.Name)
+}
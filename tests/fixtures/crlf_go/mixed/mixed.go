@@ -0,0 +1,10 @@
+// Package mixed has CRLF, LF and lone CR line endings
+package mixed
+
+import (
+	// A comment ended by a lone carriage return	"github.com/pkg/errors"
+	"strings"
+)
+
+// Name is used by main
+var Name = strings.ToUpper(errors.New("mixed").Error())
@@ -0,0 +1,9 @@
+// Package legacy keeps messages written by an editor that saved them as Latin-1
+package legacy
+
+import "github.com/pkg/errors"
+
+// Greeting returns "Ol, seor" in the original encoding
+func Greeting() string {
+	return errors.New("Ol, seor").Error()
+}
@@ -0,0 +1,13 @@
+﻿package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"example.com/encoded/legacy"
+)
+
+func main() {
+	fmt.Println(uuid.NewString(), legacy.Greeting())
+}
@@ -0,0 +1,29 @@
+package comments
+
+import (
+	"strings" // trailing comment on a real import
+	// "encoding/xml"
+	/*
+		"encoding/csv"
+	*/
+	"unicode/utf8" /* trailing block comment */
+)
+
+// import "archive/tar"
+
+/*
+import "compress/gzip"
+*/
+
+import "sort" // Sorting helpers
+
+/* import "hash/crc32" */ import "bytes"
+
+func Words(s string) []string {
+	// import "path/filepath"
+	words := strings.Fields(s)
+	sort.Strings(words)
+	_ = utf8.RuneCountInString(s)
+	_ = bytes.NewBufferString(s)
+	return words
+}
@@ -0,0 +1,12 @@
+module example.com/importforms
+
+go 1.21
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/lib/pq v1.10.9
+	github.com/onsi/gomega v1.30.0
+	github.com/pkg/errors v0.9.1
+	github.com/sirupsen/logrus v1.9.3
+	go.uber.org/zap v1.26.0
+)
@@ -0,0 +1,4 @@
+package store
+
+// Name identifies the store
+const Name = "store"
@@ -0,0 +1,36 @@
+// Package main mixes every import form: two parenthesized blocks, single-line
+// imports before, between and after them, and aliased, blank and dot entries
+// inside the blocks, separated by blank lines and comment lines.
+package main
+
+import "fmt"
+
+import (
+	"os"
+
+	// Errors with stack traces
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus" // aliased inside a block
+
+	_ "github.com/lib/pq"
+	/* a block comment line */
+	. "github.com/onsi/gomega"
+)
+
+import zlog "go.uber.org/zap"
+
+import (
+	"strings"
+	store "example.com/importforms/internal/store"
+	_ "embed"
+)
+
+import "github.com/google/uuid"
+import . "math" // dot import after the blocks
+
+func main() {
+	fmt.Println(os.Args, strings.ToUpper("x"), Pi)
+	log.Info(errors.New("x"), uuid.New(), store.Name)
+	_ = zlog.NewNop()
+	Expect(1).To(Equal(1))
+}
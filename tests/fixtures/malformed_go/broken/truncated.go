@@ -0,0 +1,7 @@
+package broken
+
+// The import block below is never closed
+import (
+	"strings"
+
+	"github.com/pkg/errors"
@@ -0,0 +1,8 @@
+module example.com/malformed
+
+go 1.21
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/pkg/errors v0.9.1
+)
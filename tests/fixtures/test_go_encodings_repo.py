@@ -0,0 +1,31 @@
+"""
+Fixture-based check that BOM-prefixed and Latin-1 Go files are analyzed
+"""
+
+import os
+
+import pytest
+
+from gardener.analysis.main import run_analysis
+
+FIXTURE_REPO_PATH = os.path.abspath("tests/fixtures/encoded_go")
+
+
+@pytest.mark.integration
+def test_bom_and_latin1_files_keep_their_imports(tmp_path, offline_mode, capsys):
+    with offline_mode.set_responses({}):
+        results = run_analysis(
+            repo_path=FIXTURE_REPO_PATH,
+            output_prefix=os.path.join(str(tmp_path), "encoded_go"),
+            minimal_outputs=True,
+            focus_languages_str="go",
+        )
+
+    assert not results.get("errors")
+    file_imports = results["analyzer_details"]["file_imports"]
+    # The BOM does not hide the package clause or the first import
+    assert "github.com/google/uuid" in file_imports["main.go"]
+    assert results["analyzer_details"]["local_imports_map"]["main.go"] == ["legacy/greeting.go"]
+    assert file_imports["legacy/greeting.go"] == ["github.com/pkg/errors"]
+    output = capsys.readouterr()
+    assert "legacy/greeting.go is not valid UTF-8" in output.out + output.err
@@ -0,0 +1,50 @@
+"""
+Fixture-based check that every Go import form in a file is extracted with its kind and alias
+"""
+
+import os
+
+import pytest
+
+from gardener.analysis.main import run_analysis
+
+FIXTURE_REPO_PATH = os.path.abspath("tests/fixtures/go_import_forms")
+
+
+@pytest.mark.integration
+def test_every_block_and_single_line_import_is_captured(tmp_path, offline_mode):
+    with offline_mode.set_responses({}):
+        results = run_analysis(
+            repo_path=FIXTURE_REPO_PATH,
+            output_prefix=os.path.join(str(tmp_path), "go_import_forms"),
+            minimal_outputs=True,
+            focus_languages_str="go",
+            config_overrides={"INCLUDE_STDLIB": True},
+        )
+
+    assert not results.get("errors")
+    evidence = results["analyzer_details"]["file_import_evidence"]["main.go"]
+    assert [(entry["import"], entry["import_kind"], entry.get("alias"), entry["scope"]) for entry in evidence] == [
+        ("fmt", "named", None, "stdlib"),
+        ("os", "named", None, "stdlib"),
+        ("github.com/pkg/errors", "named", None, "external"),
+        ("github.com/sirupsen/logrus", "aliased", "log", "external"),
+        ("github.com/lib/pq", "blank", None, "external"),
+        ("github.com/onsi/gomega", "dot", None, "external"),
+        ("go.uber.org/zap", "aliased", "zlog", "external"),
+        ("strings", "named", None, "stdlib"),
+        ("example.com/importforms/internal/store", "aliased", "store", "local"),
+        ("embed", "blank", None, "stdlib"),
+        ("github.com/google/uuid", "named", None, "external"),
+        ("math", "dot", None, "stdlib"),
+    ]
+    assert results["analyzer_details"]["local_imports_map"]["main.go"] == ["internal/store/store.go"]
+    assert set(results["external_packages"]) == {
+        "github.com/google/uuid",
+        "github.com/lib/pq",
+        "github.com/onsi/gomega",
+        "github.com/pkg/errors",
+        "github.com/sirupsen/logrus",
+        "go.uber.org/zap",
+    }
+    assert results["external_packages"]["go.uber.org/zap"]["version"] == "v1.26.0"
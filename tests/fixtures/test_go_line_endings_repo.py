@@ -0,0 +1,44 @@
+"""
+Fixture-based check that Go files with CRLF and mixed line endings keep clean import paths
+"""
+
+import os
+
+import pytest
+
+from gardener.analysis.main import run_analysis
+
+FIXTURE_REPO_PATH = os.path.abspath("tests/fixtures/crlf_go")
+
+
+@pytest.mark.integration
+def test_crlf_and_mixed_line_endings_keep_their_imports(tmp_path, offline_mode):
+    with open(os.path.join(FIXTURE_REPO_PATH, "main.go"), "rb") as handle:
+        assert b'"github.com/gin-gonic/gin"\r\n' in handle.read()
+
+    with offline_mode.set_responses({}):
+        results = run_analysis(
+            repo_path=FIXTURE_REPO_PATH,
+            output_prefix=os.path.join(str(tmp_path), "crlf_go"),
+            minimal_outputs=True,
+            focus_languages_str="go",
+        )
+
+    assert not results.get("errors")
+    file_imports = results["analyzer_details"]["file_imports"]
+    assert file_imports["main.go"] == ["fmt", "github.com/gin-gonic/gin", "github.com/google/uuid"]
+    assert results["analyzer_details"]["local_imports_map"]["main.go"] == ["mixed/mixed.go"]
+    # The import after the lone carriage return is not swallowed by the comment before it
+    assert file_imports["mixed/mixed.go"] == ["github.com/pkg/errors", "strings"]
+    imports = [
+        entry["import"]
+        for entries in results["analyzer_details"]["file_import_evidence"].values()
+        for entry in entries
+    ]
+    assert imports and not any("\r" in import_path for import_path in imports)
+    assert set(results["external_packages"]) == {
+        "github.com/gin-gonic/gin",
+        "github.com/google/uuid",
+        "github.com/pkg/errors",
+    }
+    assert results["external_packages"]["github.com/pkg/errors"]["version"] == "v0.9.1"
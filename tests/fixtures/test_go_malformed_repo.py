@@ -0,0 +1,45 @@
+"""
+Fixture-based check that a malformed Go file does not abort the run
+"""
+
+import os
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.main import run_analysis
+
+FIXTURE_REPO_PATH = os.path.abspath("tests/fixtures/malformed_go")
+
+
+@pytest.mark.integration
+def test_truncated_import_block_is_reported_and_the_rest_analyzed(tmp_path, offline_mode):
+    with offline_mode.set_responses({}):
+        results = run_analysis(
+            repo_path=FIXTURE_REPO_PATH,
+            output_prefix=os.path.join(str(tmp_path), "malformed_go"),
+            minimal_outputs=True,
+            focus_languages_str="go",
+        )
+
+    assert results["errors"] == [
+        {"file": "broken/truncated.go", "error": "parse-failed", "detail": "missing ) at line 8, column 1"}
+    ]
+    assert "github.com/gin-gonic/gin" in results["analyzer_details"]["file_imports"]["main.go"]
+    # Imports recovered before the syntax error are still reported
+    assert "github.com/pkg/errors" in results["analyzer_details"]["file_imports"]["broken/truncated.go"]
+
+
+@pytest.mark.integration
+def test_fail_on_error_sets_a_nonzero_exit_status(tmp_path, monkeypatch):
+    monkeypatch.chdir(tmp_path)
+    argv = ["gardener", FIXTURE_REPO_PATH, "-o", "malformed", "--offline", "--no-cache", "-l", "go"]
+
+    monkeypatch.setattr(sys, "argv", argv)
+    main_cli.main()
+
+    monkeypatch.setattr(sys, "argv", argv + ["--fail-on-error"])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    assert excinfo.value.code == 1
@@ -63,3 +63,19 @@ def test_go_fixture_graph_matches_spec(tmp_path, offline_mode):
     graph_data = results.get("dependency_graph", {})
     spec = load_graph_spec("tests/data/specs/go_micro.yml")
     assert_graph_matches_spec(graph_data, spec, lax=True)
+
+    import_graph = results["import_graph"]
+    edges = {(edge["source"], edge["target"]): edge["import_kind"] for edge in import_graph["edges"]}
+    assert edges[("example.com/go-fixture", "github.com/gin-gonic/gin")] == "named"
+    assert edges[("example.com/go-fixture", "github.com/lib/pq")] == "blank"
+    assert edges[("example.com/go-fixture", "example.com/go-fixture/utils")] == "named"
+    # Standard-library edges follow --include-stdlib, like the rest of the output
+    assert not any(node["kind"] == "stdlib" for node in import_graph["nodes"])
+
+    # The fixture declares `go 1.18` without a toolchain line
+    assert results["go_toolchain"] == {"go_version": "1.18"}
+
+    assert results["summary"]["files_analyzed"] == 5
+    assert results["summary"]["ecosystems"] == {
+        "go": {"external_packages": 5, "resolved_urls": 5, "unresolved_urls": 0, "stdlib": 8, "local": 2}
+    }
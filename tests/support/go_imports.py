@@ -0,0 +1,86 @@
+"""
+Go import extraction helpers and the seed corpus shared by the Go import syntax tests
+"""
+
+import os
+from collections import defaultdict
+
+from gardener.common.tsl import get_parser
+from gardener.treewalk.go import GoLanguageHandler
+
+FIXTURE_DIR = os.path.join("tests", "fixtures", "go")
+
+
+def extract_go_import_specs(source):
+    """
+    Extract the imports of a Go source string
+
+    Args:
+        source (str): Go source text
+
+    Returns:
+        list: (import path, import kind, alias) per recorded import, in source order
+    """
+    root = get_parser("go").parse(source.encode("utf-8")).root_node
+    evidence = defaultdict(list)
+    GoLanguageHandler().extract_imports(root, "main.go", defaultdict(list), lambda *_: None, None, evidence)
+    return [
+        (entry["import"], entry["import_kind"], entry.get("alias")) for entry in evidence["main.go"] if "import" in entry
+    ]
+
+
+def _fixture(name):
+    with open(os.path.join(FIXTURE_DIR, name), "r", encoding="utf-8") as handle:
+        return handle.read()
+
+
+def _named(*paths):
+    return [(path, "named", None) for path in paths]
+
+
+# (id, source, expected import specs)
+SEED_CORPUS = [
+    (
+        "fixture-comments",
+        _fixture(os.path.join("comments", "comments.go")),
+        _named("strings", "unicode/utf8", "sort", "bytes"),
+    ),
+    (
+        "fixture-comments-crlf",
+        _fixture(os.path.join("comments", "comments.go")).replace("\n", "\r\n"),
+        _named("strings", "unicode/utf8", "sort", "bytes"),
+    ),
+    (
+        "fixture-main-commented-and-trailing",
+        "package main\n\n"
+        'import . "github.com/smartystreets/goconvey/convey" // Dot import\n'
+        'import _ "github.com/lib/pq"                       // Blank import for side effects\n'
+        '// import "archive/zip" // Commented-out import\n'
+        'import "io/ioutil" // File reading\n',
+        [
+            ("github.com/smartystreets/goconvey/convey", "dot", None),
+            ("github.com/lib/pq", "blank", None),
+            ("io/ioutil", "named", None),
+        ],
+    ),
+    (
+        "tabs-and-odd-spacing",
+        'package main\nimport\t(\n\tlog\t\t"github.com/sirupsen/logrus"\n  _   "embed"\n\n\t"fmt";"os"\n)\n',
+        [("github.com/sirupsen/logrus", "aliased", "log"), ("embed", "blank", None)] + _named("fmt", "os"),
+    ),
+    (
+        "comments-between-tokens",
+        'package main\n\nimport /* lead */ (\n\tyaml /* alias */ "gopkg.in/yaml.v3" // trailing\n) // after\n',
+        [("gopkg.in/yaml.v3", "aliased", "yaml")],
+    ),
+    (
+        "raw-and-escaped-literals",
+        'package main\r\n\r\nimport (\r\n\t`github.com/pkg/errors`\r\n\t"golang.org/x/\\u0073ync"\r\n\t"\\x66mt"\r\n)\r\n',
+        _named("github.com/pkg/errors", "golang.org/x/sync", "fmt"),
+    ),
+    (
+        "invalid-paths-skipped",
+        'package main\n\nimport (\n\t"has space"\n\t""\n\t"strings"\n)\n',
+        _named("strings"),
+    ),
+]
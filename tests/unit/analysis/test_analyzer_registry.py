@@ -0,0 +1,162 @@
+"""
+Analyzer protocol, registration and entry-point discovery
+"""
+
+import pytest
+
+from gardener.api import AnalysisOptions, analyze_file, analyze_repo
+from gardener.common.language_detection import parse_language_filter
+from gardener.treewalk import registry
+from gardener.treewalk.go import GoLanguageHandler
+from gardener.treewalk.javascript import JavaScriptLanguageHandler
+from gardener.treewalk.registry import (
+    Analyzer,
+    ExtractionContext,
+    create_analyzers,
+    register_analyzer,
+    unregister_analyzer,
+)
+from gardener.treewalk.typescript import TypeScriptLanguageHandler
+
+
+class DepsAnalyzer:
+    """Reads `use <package>` and `include <file>` lines from *.deps files"""
+
+    language = "deps"
+
+    def __init__(self, logger=None):
+        self.logger = logger
+
+    def matches(self, path):
+        return path.endswith(".deps")
+
+    def extract(self, path, context):
+        entries = []
+        for line in context.code.splitlines():
+            verb, _, argument = line.partition(" ")
+            if verb == "use":
+                entries.append({"import": argument, "scope": "external", "module": argument})
+            elif verb == "include":
+                entries.append({"import": argument, "scope": "local", "resolved": [argument]})
+        return entries
+
+
+@pytest.fixture
+def deps_analyzer():
+    register_analyzer(DepsAnalyzer)
+    yield DepsAnalyzer
+    unregister_analyzer("deps")
+
+
+def _make_repo(root):
+    (root / "lib").mkdir()
+    (root / "app.deps").write_text("use left-pad\ninclude lib/util.deps\n")
+    (root / "lib" / "util.deps").write_text("use chalk\n")
+    (root / "notes.txt").write_text("use nothing\n")
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("handler_class", [GoLanguageHandler, JavaScriptLanguageHandler, TypeScriptLanguageHandler])
+def test_builtin_handlers_implement_the_protocol(handler_class):
+    handler = handler_class(None)
+
+    assert isinstance(handler, Analyzer)
+    assert handler.language in create_analyzers()
+
+
+@pytest.mark.unit
+def test_builtin_handlers_match_their_own_files():
+    go = GoLanguageHandler(None)
+    javascript = JavaScriptLanguageHandler(None)
+    typescript = TypeScriptLanguageHandler(None)
+
+    assert go.matches("cmd/main.go") and not go.matches("web/app.ts")
+    assert javascript.matches("web/app.mjs") and not javascript.matches("web/app.ts")
+    assert typescript.matches("web/app.tsx") and not typescript.matches("cmd/main.go")
+
+
+@pytest.mark.unit
+def test_go_extract_returns_import_evidence():
+    context = ExtractionContext('package main\n\nimport "github.com/pkg/errors"\n')
+    entries = GoLanguageHandler(None).extract("main.go", context)
+
+    assert [entry["import"] for entry in entries] == ["github.com/pkg/errors"]
+    assert context.imports_from(entries) == (["github.com/pkg/errors"], [])
+
+
+@pytest.mark.unit
+def test_javascript_extract_reports_imports_through_the_context():
+    js_context = ExtractionContext("import React from 'react';\nconst x = require('lodash/fp');\n")
+    entries = JavaScriptLanguageHandler(None).extract("app.js", js_context)
+
+    assert entries == []
+    external, local = js_context.imports_from(entries)
+    assert sorted(external) == ["lodash", "react"]
+    assert local == []
+
+
+@pytest.mark.unit
+def test_registered_analyzer_takes_part_in_repository_analysis(tmp_path, deps_analyzer, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True))
+
+    details = result.raw["analyzer_details"]
+    assert details["total_files"] == 2
+    assert details["languages_detected"] == ["deps"]
+    assert dict(details["file_imports"]) == {"app.deps": ["left-pad"], "lib/util.deps": ["chalk"]}
+    assert dict(details["local_imports_map"]) == {"app.deps": ["lib/util.deps"]}
+    assert details["file_import_evidence"]["lib/util.deps"] == [
+        {"import": "chalk", "scope": "external", "module": "chalk", "id": "file:lib/util.deps#chalk"}
+    ]
+
+
+@pytest.mark.unit
+def test_registered_analyzer_is_selectable_and_detected(tmp_path, deps_analyzer):
+    _make_repo(tmp_path)
+
+    assert parse_language_filter("go,deps") == ["go", "deps"]
+    evidence = analyze_file(str(tmp_path / "app.deps"))
+    assert evidence.language == "deps"
+    assert evidence.external_imports == ["left-pad"]
+    assert evidence.local_imports == ["lib/util.deps"]
+
+    unregister_analyzer("deps")
+    with pytest.raises(ValueError):
+        parse_language_filter("deps")
+
+
+@pytest.mark.unit
+def test_register_analyzer_requires_a_language():
+    class Nameless(DepsAnalyzer):
+        language = ""
+
+    with pytest.raises(ValueError):
+        register_analyzer(Nameless)
+
+
+@pytest.mark.unit
+def test_entry_point_analyzers_are_discovered(monkeypatch):
+    class _EntryPoint:
+        def __init__(self, name, target):
+            self.name = name
+            self.target = target
+
+        def load(self):
+            if isinstance(self.target, Exception):
+                raise self.target
+            return self.target
+
+    def _entry_points(group):
+        assert group == registry.ENTRY_POINT_GROUP
+        return [_EntryPoint("deps", DepsAnalyzer), _EntryPoint("broken", ImportError("missing dependency"))]
+
+    monkeypatch.setattr(registry.metadata, "entry_points", _entry_points)
+    monkeypatch.setattr(registry, "_DISCOVERED", None)
+
+    analyzers = create_analyzers()
+
+    assert isinstance(analyzers["deps"], DepsAnalyzer)
+    assert "broken" not in analyzers
+    assert list(analyzers)[: len(registry.BUILTIN_ANALYZERS)] == list(registry.BUILTIN_ANALYZERS)
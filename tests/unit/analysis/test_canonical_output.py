@@ -0,0 +1,109 @@
+"""
+Canonical ordering of the analysis JSON
+"""
+
+import random
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis import scanner
+from gardener.analysis.canonical import canonical_results
+from gardener.analysis.main import run_analysis
+from gardener.persistence.file import FilePersistence
+
+MODULES = ["github.com/pkg/errors", "github.com/google/uuid", "golang.org/x/sync", "github.com/spf13/cobra"]
+
+
+def _make_repo(root):
+    requires = "".join(f"\t{module} v1.{index}.0\n" for index, module in enumerate(MODULES))
+    (root / "go.mod").write_text(f"module example.com/app\n\ngo 1.21\n\nrequire (\n{requires})\n")
+    for index in range(8):
+        directory = root / f"pkg{index % 3}"
+        directory.mkdir(exist_ok=True)
+        imports = "".join(f'\t"{module}"\n' for module in MODULES[index % 4 :] + MODULES[: index % 2])
+        (directory / f"file{index}.go").write_text(f"package pkg{index % 3}\n\nimport (\n{imports})\n")
+
+
+def _analyze(repo, output_dir, offline_mode, overrides=None):
+    persistence = FilePersistence(output_dir=str(output_dir), verbose=False)
+    with offline_mode.set_responses({}):
+        run_analysis(str(repo), output_prefix="app", persistence=persistence, config_overrides=overrides)
+    return (output_dir / "app_dependency_analysis.json").read_text()
+
+
+@pytest.mark.unit
+def test_output_does_not_depend_on_discovery_order(tmp_path, offline_mode, monkeypatch):
+    # Pins tool.analyzed_at, the one field that differs between runs
+    monkeypatch.setenv("SOURCE_DATE_EPOCH", "1700000000")
+    repo = tmp_path / "app"
+    repo.mkdir()
+    _make_repo(repo)
+    expected = _analyze(repo, tmp_path / "walk", offline_mode)
+
+    scan_repository = scanner.scan_repository
+    for seed in range(3):
+        shuffler = random.Random(seed)
+
+        def _shuffled_scan(*args, _shuffle=shuffler.shuffle, **kwargs):
+            result = scan_repository(*args, **kwargs)
+            files = list(result["source_files"].items())
+            _shuffle(files)
+            result["source_files"] = dict(files)
+            _shuffle(result["manifest_files"])
+            return result
+
+        monkeypatch.setattr(scanner, "scan_repository", _shuffled_scan)
+        assert _analyze(repo, tmp_path / f"shuffled{seed}", offline_mode) == expected
+
+
+@pytest.mark.unit
+def test_packages_are_ordered_by_ecosystem_name_and_version():
+    results = {
+        "external_packages": {
+            "zod": {"ecosystem": "npm", "version": "3.0.0", "found_in_manifests": ["b/package.json", "a/package.json"]},
+            "github.com/pkg/errors": {
+                "version": "v0.9.1",
+                "ecosystem": "go",
+                "seen_in": [{"file": "z.go", "imports": ["errors"]}, {"file": "a.go", "imports": ["errors"]}],
+            },
+            "axios": {"ecosystem": "npm", "version": "1.0.0"},
+        },
+        "analyzer_details": {"file_imports": {"b.go": ["y", "x"], "a.go": ["z"]}, "languages_detected": ["go", "js"]},
+        "top_dependencies": [
+            {"package_name": "zod", "percentage": 25.0},
+            {"package_name": "axios", "percentage": 25.0},
+            {"package_name": "github.com/pkg/errors", "percentage": 50.0},
+        ],
+    }
+
+    canonical = canonical_results(results)
+
+    assert list(canonical["external_packages"]) == ["github.com/pkg/errors", "axios", "zod"]
+    errors = canonical["external_packages"]["github.com/pkg/errors"]
+    assert list(errors) == ["ecosystem", "seen_in", "version"]
+    assert [entry["file"] for entry in errors["seen_in"]] == ["a.go", "z.go"]
+    assert canonical["external_packages"]["zod"]["found_in_manifests"] == ["a/package.json", "b/package.json"]
+    assert canonical["analyzer_details"]["file_imports"] == {"a.go": ["z"], "b.go": ["x", "y"]}
+    assert [dep["package_name"] for dep in canonical["top_dependencies"]] == ["github.com/pkg/errors", "axios", "zod"]
+    # The input is left as discovered
+    assert list(results["external_packages"]) == ["zod", "github.com/pkg/errors", "axios"]
+
+
+@pytest.mark.unit
+def test_no_sort_keeps_discovery_order(tmp_path, monkeypatch):
+    captured = {}
+
+    def _run_analysis(*args, **kwargs):
+        captured["overrides"] = args[5]
+        return {"analyzer_details": {"total_files": 1}}
+
+    monkeypatch.setattr(main_cli, "run_analysis", _run_analysis)
+    monkeypatch.setattr(sys, "argv", ["gardener", str(tmp_path), "--no-cache", "--no-sort"])
+    main_cli.main()
+    assert captured["overrides"]["SERIALIZE_SORT_KEYS"] is False
+
+    monkeypatch.setattr(sys, "argv", ["gardener", str(tmp_path), "--no-cache", "--sort"])
+    main_cli.main()
+    assert "SERIALIZE_SORT_KEYS" not in (captured["overrides"] or {})
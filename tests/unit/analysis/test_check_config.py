@@ -0,0 +1,112 @@
+"""
+Option validation at startup and the --check-config dry run
+"""
+
+import json
+import subprocess
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis import main as analysis_main
+
+
+def _make_repo(root):
+    root.mkdir()
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n")
+    (root / "main.go").write_text('package main\n\nimport "fmt"\n')
+    return str(root)
+
+
+@pytest.fixture
+def no_analysis(monkeypatch):
+    runs = []
+    monkeypatch.setattr(main_cli, "run_analysis", lambda *args, **kwargs: runs.append(args))
+    monkeypatch.setattr(analysis_main.RepositoryAnalyzer, "scan_repo", lambda self: runs.append("scan"))
+    return runs
+
+
+@pytest.mark.unit
+def test_every_problem_is_reported_before_the_repository_is_read(tmp_path, monkeypatch, capsys, no_analysis):
+    repo = _make_repo(tmp_path / "repo")
+    argv = [repo, "--jobs", "0", "--no-such-flag", "--gardener-ignore", str(tmp_path / "missing.txt")]
+    argv += ["--branch", "main", "--sbom-format", "spdx", "--resolution-cache-ttl", "soon"]
+    monkeypatch.setattr(sys, "argv", ["gardener", *argv])
+
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == 2
+    record = json.loads(capsys.readouterr().err.strip().splitlines()[-1])
+    assert record["error_code"] == "invalid-arguments"
+    for expected in (
+        "Unrecognized arguments: --no-such-flag",
+        "--jobs must be a positive integer",
+        "--gardener-ignore file not found",
+        "--branch main only applies to repository URLs",
+        "--sbom-format only applies to --sbom-out",
+        "--resolution-cache-ttl",
+    ):
+        assert expected in record["message"]
+    assert no_analysis == []
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "argv, expected",
+    [
+        (["{missing}"], "Repository path not found"),
+        (["https://github.com/acme/widgets", "--offline"], "--offline cannot clone"),
+        (["{repo}", "--since", "main"], "not inside a git work tree"),
+        (["{repo}", "--timings-output", "{missing}/timings.json"], "--timings-output directory not found"),
+    ],
+)
+def test_input_problems_exit_2(tmp_path, monkeypatch, capsys, no_analysis, argv, expected):
+    repo = _make_repo(tmp_path / "repo")
+    argv = [arg.format(repo=repo, missing=tmp_path / "missing") for arg in argv]
+    monkeypatch.setattr(sys, "argv", ["gardener", *argv])
+
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == 2
+    assert expected in json.loads(capsys.readouterr().err.strip().splitlines()[-1])["message"]
+    assert no_analysis == []
+
+
+@pytest.mark.unit
+def test_unknown_since_ref_is_rejected(tmp_path, monkeypatch, capsys, no_analysis):
+    repo = _make_repo(tmp_path / "repo")
+    git = ["git", "-c", "user.name=t", "-c", "user.email=t@example.com"]
+    subprocess.run(["git", "init", "-q", repo], check=True)
+    subprocess.run(git + ["-C", repo, "add", "."], check=True)
+    subprocess.run(git + ["-C", repo, "commit", "-qm", "init"], check=True)
+    monkeypatch.setattr(sys, "argv", ["gardener", repo, "--since", "no-such-branch"])
+
+    with pytest.raises(SystemExit):
+        main_cli.main()
+
+    record = json.loads(capsys.readouterr().err.strip().splitlines()[-1])
+    assert "Cannot diff against 'no-such-branch'" in record["message"]
+    assert no_analysis == []
+
+
+@pytest.mark.unit
+def test_check_config_prints_the_effective_configuration(tmp_path, monkeypatch, capsys, no_analysis):
+    repo = _make_repo(tmp_path / "repo")
+    argv = [repo, "-l", "go,js", "--offline", "--max-retries", "1", "--auth-token", "secret"]
+    argv += ["--config", '{"PAGERANK_ALPHA": 0.9}', "--json-out", "-", "--check-config"]
+    monkeypatch.setattr(sys, "argv", ["gardener", *argv])
+
+    main_cli.main()
+
+    configuration = json.loads(capsys.readouterr().out)
+    assert configuration["analyzers"] == ["go", "javascript"]
+    assert configuration["outputs"]["destinations"] == {"json": "-"}
+    assert configuration["config"]["GraphAnalysisConfig"]["PAGERANK_ALPHA"] == 0.9
+    assert configuration["config"]["NetworkConfig"]["OFFLINE"] is True
+    assert configuration["config"]["NetworkConfig"]["MAX_RETRIES"] == 1
+    assert configuration["config"]["NetworkConfig"]["AUTH_TOKEN"] == "***"
+    assert configuration["config"]["ResourceLimits"]["EXCLUDE_PATTERNS"] == []
+    assert no_analysis == []
@@ -0,0 +1,102 @@
+"""
+Gzip-compressed output (--compress, destinations named .gz)
+"""
+
+import gzip
+import json
+import os
+import sys
+import zlib
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.main import run_analysis
+from gardener.analysis.ndjson_export import NDJSON_SUFFIX
+from gardener.analysis.result_diff import load_result_file
+from gardener.common.compression import GZIP_MAGIC, open_text_output
+from gardener.persistence.file import FilePersistence
+
+
+def _make_repo(root):
+    root.mkdir()
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+
+
+@pytest.mark.unit
+def test_gz_destinations_are_compressed_and_decode_to_the_plain_output(tmp_path, monkeypatch, offline_mode):
+    repo = tmp_path / "repo"
+    _make_repo(repo)
+    monkeypatch.chdir(tmp_path)
+    # Both runs must record the same analyzed_at to be byte-identical
+    monkeypatch.setenv("SOURCE_DATE_EPOCH", "1700000000")
+    argv = ["gardener", str(repo), "-o", "demo", "--offline", "--no-cache", "-m"]
+
+    with offline_mode.set_responses({}):
+        monkeypatch.setattr(sys, "argv", argv + ["--json-out", "plain.json", "--csv-out", "deps.csv.gz"])
+        main_cli.main()
+        monkeypatch.setattr(sys, "argv", argv + ["--json-out", "out/results.json.GZ"])
+        main_cli.main()
+
+    with open("out/results.json.GZ", "rb") as handle:
+        compressed = handle.read()
+    assert compressed.startswith(GZIP_MAGIC)
+    with open("plain.json", "rb") as handle:
+        assert gzip.decompress(compressed) == handle.read()
+    with open("deps.csv.gz", "rb") as handle:
+        assert b"github.com/pkg/errors" in gzip.decompress(handle.read())
+    # The saved outputs stay plain without --compress
+    assert os.path.exists(os.path.join("output", "demo_dependency_analysis.json"))
+    # Reading results back recognizes gzip by its magic bytes, whatever the file is called
+    os.rename("out/results.json.GZ", "renamed.json")
+    assert "github.com/pkg/errors" in load_result_file("renamed.json")["ecosystems"]["go"]
+
+
+@pytest.mark.unit
+def test_compress_gzips_saved_files_and_the_ndjson_stream(tmp_path, offline_mode):
+    repo = tmp_path / "repo"
+    _make_repo(repo)
+    persistence = FilePersistence(output_dir=str(tmp_path / "out"), verbose=False, compress=True)
+
+    with offline_mode.set_responses({}):
+        run_analysis(
+            str(repo),
+            "demo",
+            persistence=persistence,
+            output_format="ndjson",
+            config_overrides={"OFFLINE": True, "SERIALIZE_COMPRESS": True},
+            destinations={"json": str(tmp_path / "results.json")},
+        )
+
+    out = tmp_path / "out"
+    assert sorted(os.listdir(out)) == ["demo_dependencies.ndjson.gz", "demo_dependency_analysis.json.gz"]
+    # The graph visualization stays viewable in a browser
+    assert persistence.get_output_path("demo", "_dependency_graph.html").endswith(".html")
+    with gzip.open(out / f"demo{NDJSON_SUFFIX}.gz", "rt", encoding="utf-8") as handle:
+        records = [json.loads(line) for line in handle]
+    assert [record["type"] for record in records] == ["file_evidence", "package", "summary"]
+    # --compress applies to destinations whatever their names
+    with gzip.open(tmp_path / "results.json", "rt", encoding="utf-8") as handle:
+        assert "github.com/pkg/errors" in json.load(handle)["ecosystems"]["go"]
+
+
+@pytest.mark.unit
+def test_compressed_stream_can_be_read_up_to_the_last_flushed_line(tmp_path):
+    path = str(tmp_path / "stream.ndjson.gz")
+
+    with open_text_output(path, newline="\n", line_buffering=True) as stream:
+        stream.write('{"n":1}\n')
+        stream.write('{"n":2}\n')
+        with open(path, "rb") as handle:
+            partial = zlib.decompressobj(16 + zlib.MAX_WBITS).decompress(handle.read())
+        assert partial == b'{"n":1}\n{"n":2}\n'
+
+    # Without a timestamp in the header, the same text compresses to the same bytes
+    with open(path, "rb") as handle:
+        first = handle.read()
+    with open_text_output(path, newline="\n", line_buffering=True) as stream:
+        stream.write('{"n":1}\n')
+        stream.write('{"n":2}\n')
+    with open(path, "rb") as handle:
+        assert handle.read() == first
@@ -0,0 +1,80 @@
+"""
+CSV serialization of analysis results
+"""
+
+import csv
+import io
+
+import pytest
+
+from gardener.analysis.csv_export import CSV_COLUMNS, resolution_status, to_csv
+from gardener.analysis.main import save_csv
+from gardener.common.utils import Logger
+from gardener.persistence.file import FilePersistence
+
+EXTERNAL_PACKAGES = {
+    "lodash": {"ecosystem": "npm", "version": "^4.17.21", "repository_url": "https://github.com/lodash/lodash"},
+    "github.com/gin-gonic/gin": {
+        "ecosystem": "go",
+        "version": "v1.9.1",
+        "repository_url": "https://github.com/gin-gonic/gin",
+        "scope": "production",
+    },
+    'odd,name "quoted"': {
+        "ecosystem": "npm",
+        "version": "1.0.0",
+        "repository_url": "https://example.com/a,b?q=\"x\"",
+    },
+    "github.com/stretchr/testify": {
+        "ecosystem": "go",
+        "version": "v1.8.4",
+        "repository_url": "",
+        "scope": "test",
+        "resolution": {"reason": "offline-skipped"},
+    },
+    "@local/pkg": {"ecosystem": "npm", "version": {"path": "../pkg"}},
+}
+
+
+@pytest.mark.unit
+def test_csv_round_trips_fields_with_commas_and_quotes_in_sorted_order():
+    content = to_csv({"external_packages": EXTERNAL_PACKAGES})
+
+    assert content.startswith(",".join(CSV_COLUMNS) + "\r\n")
+    assert '"odd,name ""quoted"""' in content
+    rows = list(csv.reader(io.StringIO(content, newline="")))
+    assert rows[0] == list(CSV_COLUMNS)
+    assert [(row[0], row[1]) for row in rows[1:]] == [
+        ("go", "github.com/gin-gonic/gin"),
+        ("go", "github.com/stretchr/testify"),
+        ("npm", "@local/pkg"),
+        ("npm", "lodash"),
+        ("npm", 'odd,name "quoted"'),
+    ]
+    by_name = {row[1]: dict(zip(CSV_COLUMNS, row)) for row in rows[1:]}
+    assert by_name['odd,name "quoted"']["repository_url"] == 'https://example.com/a,b?q="x"'
+    assert by_name["github.com/gin-gonic/gin"]["scope"] == "production"
+    assert by_name["github.com/stretchr/testify"]["resolution_status"] == "offline-skipped"
+    # Non-string manifest specs (e.g. path dependencies) leave the version blank
+    assert by_name["@local/pkg"]["version"] == ""
+    assert by_name["@local/pkg"]["resolution_status"] == "unresolved"
+
+
+@pytest.mark.unit
+def test_resolution_status_prefers_a_resolved_url():
+    assert resolution_status({"repository_url": "https://github.com/a/b", "resolution": {"reason": "x"}}) == "resolved"
+    assert resolution_status({"repository_url": "", "resolution": {"reason": "replaced-local"}}) == "replaced-local"
+    assert resolution_status({}) == "unresolved"
+
+
+@pytest.mark.unit
+def test_tab_delimiter_writes_tsv_file(tmp_path):
+    persistence = FilePersistence(output_dir=str(tmp_path), verbose=False)
+
+    assert save_csv({"external_packages": EXTERNAL_PACKAGES}, "demo", persistence, Logger(), delimiter="\t")
+
+    with open(tmp_path / "demo_dependencies.tsv", encoding="utf-8", newline="") as handle:
+        rows = list(csv.reader(handle, delimiter="\t"))
+    assert rows[0] == list(CSV_COLUMNS)
+    assert len(rows) == len(EXTERNAL_PACKAGES) + 1
+    assert ["npm", 'odd,name "quoted"', "1.0.0"] == rows[-1][:3]
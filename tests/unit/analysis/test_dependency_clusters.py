@@ -0,0 +1,94 @@
+"""
+Grouping protobuf and gRPC modules into a protobuf-toolchain cluster
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.dependency_clusters import dependency_cluster
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.output_schema import validate_document
+from gardener.common.defaults import ConfigOverride
+
+GO_MOD = """\
+module example.com/svc
+
+go 1.21
+
+require (
+\tgithub.com/pkg/errors v0.9.1
+\tgoogle.golang.org/genproto/googleapis/rpc v0.0.0-20240125205218-1f4bbc51befe
+\tgoogle.golang.org/grpc v1.61.0
+\tgoogle.golang.org/protobuf v1.32.0
+)
+"""
+
+GENERATED = "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n\n"
+
+
+def _make_service(root):
+    (root / "api").mkdir(parents=True)
+    (root / "go.mod").write_text(GO_MOD)
+    (root / "api" / "service.pb.go").write_text(
+        GENERATED + 'import (\n\t"google.golang.org/protobuf/reflect/protoreflect"\n'
+        '\t"google.golang.org/protobuf/runtime/protoimpl"\n)\n'
+    )
+    (root / "api" / "service_grpc.pb.go").write_text(
+        GENERATED + 'import (\n\t"google.golang.org/grpc"\n\t"google.golang.org/grpc/codes"\n)\n'
+    )
+    (root / "main.go").write_text(
+        'package main\n\nimport (\n\t"github.com/pkg/errors"\n\t"google.golang.org/grpc"\n'
+        '\t"google.golang.org/genproto/googleapis/rpc/status"\n)\n'
+    )
+
+
+@pytest.mark.unit
+def test_protobuf_and_grpc_modules_form_one_cluster(tmp_path, offline_mode):
+    _make_service(tmp_path / "svc")
+
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True, "GROUP_CLUSTERS": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path / "svc"), ["go"])
+
+    assert results["clusters"] == {
+        "protobuf-toolchain": {
+            "ecosystem": "go",
+            "modules": [
+                "google.golang.org/genproto/googleapis/rpc",
+                "google.golang.org/grpc",
+                "google.golang.org/protobuf",
+            ],
+            "files": ["api/service.pb.go", "api/service_grpc.pb.go", "main.go"],
+            "generated_files": ["api/service.pb.go", "api/service_grpc.pb.go"],
+            "usage_count": 3,
+            "scope": "production",
+        }
+    }
+    packages = results["external_packages"]
+    # The members are still listed one by one
+    assert {name: info.get("cluster") for name, info in packages.items()} == {
+        "github.com/pkg/errors": None,
+        "google.golang.org/genproto/googleapis/rpc": "protobuf-toolchain",
+        "google.golang.org/grpc": "protobuf-toolchain",
+        "google.golang.org/protobuf": "protobuf-toolchain",
+    }
+    assert validate_document(json.loads(json.dumps(results, default=str))) == []
+
+
+@pytest.mark.unit
+def test_clusters_are_only_grouped_on_request(tmp_path, offline_mode):
+    _make_service(tmp_path / "svc")
+
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path / "svc"), ["go"])
+
+    assert "clusters" not in results
+    assert not any("cluster" in info for info in results["external_packages"].values())
+
+
+@pytest.mark.unit
+def test_cluster_membership_follows_whole_path_segments():
+    assert dependency_cluster("github.com/grpc-ecosystem/grpc-gateway/v2") == "protobuf-toolchain"
+    assert dependency_cluster("github.com/golang/protobuf") == "protobuf-toolchain"
+    assert dependency_cluster("google.golang.org/grpcsomething") is None
+    assert dependency_cluster("github.com/gogo/protobuf") is None
@@ -0,0 +1,94 @@
+"""
+Several output destinations from one analysis run
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import api, main_cli
+from gardener.analysis.destinations import render_table
+
+
+def _make_repo(root):
+    root.mkdir()
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    return str(root)
+
+
+@pytest.mark.unit
+def test_render_table_aligns_top_dependencies():
+    results = {
+        "external_packages": {"github.com/pkg/errors": {"version": "v0.9.1"}},
+        "top_dependencies": [
+            {
+                "package_name": "github.com/pkg/errors",
+                "percentage": 75.0,
+                "package_url": "https://github.com/pkg/errors",
+                "ecosystem": "go",
+            },
+            {"package_name": "lodash", "percentage": 25.0, "package_url": "", "ecosystem": "npm"},
+        ],
+    }
+
+    assert render_table(results).splitlines() == [
+        "SCORE   PACKAGE                ECOSYSTEM  VERSION  REPOSITORY",
+        "75.00%  github.com/pkg/errors  go         v0.9.1   https://github.com/pkg/errors",
+        "25.00%  lodash                 npm",
+    ]
+    assert render_table({"external_packages": {}, "top_dependencies": []}) == "No dependencies found\n"
+
+
+@pytest.mark.unit
+def test_table_json_and_sbom_are_written_from_one_analysis(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+    analyses = []
+
+    def _analyze_repo(*args, **kwargs):
+        analyses.append(args[0])
+        return analyze_repo(*args, **kwargs)
+
+    analyze_repo = api.analyze_repo
+    monkeypatch.setattr(api, "analyze_repo", _analyze_repo)
+    monkeypatch.setattr(
+        sys,
+        "argv",
+        ["gardener", repo, "--offline", "--no-cache", "-l", "go", "-o", "app"]
+        + ["--table-out", "-", "--json-out", "out/results.json", "--sbom-out", "sbom.cdx.json"],
+    )
+
+    with offline_mode.set_responses({}):
+        main_cli.main()
+
+    assert analyses == [repo]
+    table = capsys.readouterr().out.splitlines()
+    assert table[0].split() == ["SCORE", "PACKAGE", "ECOSYSTEM", "VERSION", "REPOSITORY"]
+    assert table[1].split()[1:4] == ["github.com/pkg/errors", "go", "v0.9.1"]
+    written = json.loads((tmp_path / "out" / "results.json").read_text())
+    assert written == json.loads((tmp_path / "output" / "app_dependency_analysis.json").read_text())
+    sbom = json.loads((tmp_path / "sbom.cdx.json").read_text())
+    assert sbom["bomFormat"] == "CycloneDX"
+    assert [component["purl"] for component in sbom["components"]] == ["pkg:golang/github.com/pkg/errors@v0.9.1"]
+
+
+@pytest.mark.unit
+def test_json_to_stdout_is_the_only_stdout_output(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+    monkeypatch.setattr(
+        sys,
+        "argv",
+        ["gardener", repo, "--offline", "--no-cache", "-l", "go", "--json-out", "-"]
+        + ["--csv-out", "deps.csv", "--sbom-out", "sbom.spdx.json", "--sbom-format", "spdx"],
+    )
+
+    with offline_mode.set_responses({}):
+        main_cli.main()
+
+    results = json.loads(capsys.readouterr().out)
+    assert list(results["ecosystems"]["go"]) == ["github.com/pkg/errors"]
+    assert (tmp_path / "deps.csv").read_text().splitlines()[0].startswith("ecosystem,package,version")
+    assert json.loads((tmp_path / "sbom.spdx.json").read_text())["spdxVersion"] == "SPDX-2.3"
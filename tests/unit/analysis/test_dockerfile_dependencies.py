@@ -0,0 +1,121 @@
+"""
+Base images and installed packages declared in Dockerfiles
+"""
+
+import pytest
+
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.treewalk.docker import DockerfileAnalyzer, parse_dockerfile, parse_image_reference
+
+DOCKERFILE = """\
+# syntax=docker/dockerfile:1
+ARG GO_VERSION=1.22
+FROM --platform=$BUILDPLATFORM golang:${GO_VERSION}-alpine AS build
+RUN apk add --no-cache git=2.43.0-r0 \\
+    # cgo toolchain
+    build-base && \\
+    go install github.com/go-delve/delve/cmd/dlv@v1.22.1
+RUN npm ci && npm install -g typescript@5.3.3
+RUN pip install --no-cache-dir -r requirements.txt poetry==1.8.2
+
+FROM build AS test
+RUN DEBIAN_FRONTEND=noninteractive apt-get install -y --no-install-recommends curl ca-certificates
+
+FROM gcr.io/distroless/static-debian12@sha256:0123abcd
+COPY --from=build /out/app /app
+"""
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "reference, expected",
+    [
+        ("golang:1.22-alpine", ("golang", "1.22-alpine", None, "docker.io/library/golang")),
+        ("bitnami/redis", ("bitnami/redis", None, None, "docker.io/bitnami/redis")),
+        ("localhost:5000/app:dev", ("localhost:5000/app", "dev", None, "localhost:5000/app")),
+        ("ghcr.io/org/app@sha256:ff", ("ghcr.io/org/app", None, "sha256:ff", "ghcr.io/org/app")),
+    ],
+)
+def test_image_references_are_split_and_qualified(reference, expected):
+    parts = parse_image_reference(reference)
+    assert (parts["image"], parts["tag"], parts["digest"], parts["name"]) == expected
+
+
+@pytest.mark.unit
+def test_base_images_and_installs_are_extracted():
+    dependencies = {dependency["key"]: dependency for dependency in parse_dockerfile(DOCKERFILE)}
+
+    assert sorted(dependencies) == [
+        "apk:build-base",
+        "apk:git",
+        "apt:ca-certificates",
+        "apt:curl",
+        "docker.io/library/golang",
+        "gcr.io/distroless/static-debian12",
+        "go:github.com/go-delve/delve/cmd/dlv",
+        "npm:typescript",
+        "pip:poetry",
+    ]
+    golang = dependencies["docker.io/library/golang"]
+    assert golang["line"] == 3
+    assert golang["package"] == {
+        "ecosystem": "docker",
+        "scope": "base-image",
+        "image": "golang",
+        "tag": "1.22-alpine",
+        "version": "1.22-alpine",
+        "stage": "build",
+    }
+    distroless = dependencies["gcr.io/distroless/static-debian12"]["package"]
+    assert (distroless["digest"], distroless["version"]) == ("sha256:0123abcd", "sha256:0123abcd")
+    git = dependencies["apk:git"]
+    assert (git["reference"], git["line"], git["scope"]) == ("git=2.43.0-r0", 4, "installed-package")
+    assert git["package"]["installer"] == "apk"
+    assert git["package"]["version"] == "2.43.0-r0"
+    assert dependencies["go:github.com/go-delve/delve/cmd/dlv"]["package"]["version"] == "v1.22.1"
+    assert dependencies["pip:poetry"]["package"]["version"] == "1.8.2"
+    assert dependencies["apt:curl"]["package"]["stage"] == "test"
+    assert "version" not in dependencies["apt:curl"]["package"]
+
+
+@pytest.mark.unit
+def test_dockerfiles_are_claimed_by_name():
+    analyzer = DockerfileAnalyzer(None)
+
+    assert analyzer.matches("Dockerfile")
+    assert analyzer.matches("deploy/Dockerfile.prod")
+    assert analyzer.matches("images/api.Dockerfile")
+    assert analyzer.matches("Containerfile")
+    assert not analyzer.matches("docs/Dockerfile-notes.md")
+    assert not analyzer.matches("docker-compose.yml")
+
+
+@pytest.mark.unit
+def test_dockerfile_dependencies_join_the_analysis(tmp_path, offline_mode):
+    (tmp_path / "Dockerfile").write_text(DOCKERFILE)
+    (tmp_path / "deploy").mkdir()
+    (tmp_path / "deploy" / "Dockerfile.dev").write_text("FROM golang:1.21\nRUN apt-get install -y curl\n")
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True))
+
+    golang = result.external_packages["docker.io/library/golang"]
+    assert (golang["ecosystem"], golang["scope"], golang["version"]) == ("docker", "base-image", "1.22-alpine")
+    assert [conflict["version"] for conflict in golang["version_conflicts"]] == ["1.21"]
+    curl = result.external_packages["apt:curl"]
+    assert (curl["ecosystem"], curl["scope"], curl["installer"]) == ("docker", "installed-package", "apt")
+    assert len(curl["found_in_manifests"]) == 2
+
+    details = result.raw["analyzer_details"]
+    assert details["languages_detected"] == ["docker"]
+    assert sorted(details["file_imports"]["deploy/Dockerfile.dev"]) == ["apt:curl", "docker.io/library/golang"]
+    evidence = details["file_import_evidence"]["Dockerfile"][0]
+    assert evidence == {
+        "import": "golang:1.22-alpine",
+        "scope": "external",
+        "module": "docker.io/library/golang",
+        "kind": "base-image",
+        "line": 3,
+        "id": "file:Dockerfile#golang:1.22-alpine",
+    }
+    assert {entry["file"] for entry in curl["seen_in"]} == {"Dockerfile", "deploy/Dockerfile.dev"}
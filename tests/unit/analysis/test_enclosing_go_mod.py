@@ -0,0 +1,76 @@
+"""
+Analyzing a subdirectory of a Go module whose go.mod is above it
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.main import run_analysis
+from gardener.analysis.manifests import find_enclosing_go_mod
+from gardener.analysis.output_schema import validate_document
+from gardener.persistence.file import FilePersistence
+
+
+def _make_module(root):
+    (root / "internal" / "foo" / "bar").mkdir(parents=True)
+    (root / "pkg" / "util").mkdir(parents=True)
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "pkg" / "util" / "util.go").write_text("package util\n")
+    (root / "internal" / "foo" / "bar" / "bar.go").write_text("package bar\n")
+    (root / "internal" / "foo" / "foo.go").write_text(
+        'package foo\n\nimport (\n\t"github.com/pkg/errors"\n\t"example.com/app/internal/foo/bar"\n'
+        '\t"example.com/app/pkg/util"\n\t"./bar"\n)\n'
+    )
+
+
+@pytest.mark.unit
+def test_subdirectory_uses_the_enclosing_go_mod(tmp_path, offline_mode):
+    module = tmp_path / "app"
+    _make_module(module)
+    target = module / "internal" / "foo"
+
+    with offline_mode.set_responses({}):
+        results = run_analysis(
+            str(target),
+            output_prefix="foo",
+            persistence=FilePersistence(output_dir=str(tmp_path / "out"), verbose=False),
+            focus_languages_str="go",
+            config_overrides={"OFFLINE": True},
+        )
+
+    assert list(results["external_packages"]) == ["github.com/pkg/errors"]
+    assert results["external_packages"]["github.com/pkg/errors"]["version"] == "v0.9.1"
+    assert results["enclosing_go_mod"] == {
+        "path": (module / "go.mod").as_posix(),
+        "module": "example.com/app",
+        "directory": "internal/foo",
+    }
+    assert results["tool"]["go_mod"] == (module / "go.mod").as_posix()
+    assert results["go_toolchain"] == {"go_version": "1.21"}
+    evidence = results["analyzer_details"]["file_import_evidence"]["foo.go"]
+    assert {entry["import"]: entry["scope"] for entry in evidence} == {
+        "github.com/pkg/errors": "external",
+        "example.com/app/internal/foo/bar": "local",
+        "example.com/app/pkg/util": "local",
+        "./bar": "local",
+    }
+    assert results["analyzer_details"]["local_imports_map"]["foo.go"].count("bar/bar.go") == 2
+    document = json.loads((tmp_path / "out" / "foo_dependency_analysis.json").read_text())
+    assert validate_document(document) == []
+
+
+@pytest.mark.unit
+def test_enclosing_go_mod_search_stops_at_git_boundaries(tmp_path):
+    module = tmp_path / "app"
+    _make_module(module)
+    target = module / "internal" / "foo"
+
+    assert find_enclosing_go_mod(str(target)) == str(module / "go.mod")
+    assert find_enclosing_go_mod(str(module)) is None
+
+    # A work tree root inside the module hides the go.mod above it
+    (module / "internal" / ".git").mkdir()
+    assert find_enclosing_go_mod(str(target)) is None
+    (target / ".git").mkdir()
+    assert find_enclosing_go_mod(str(target)) is None
@@ -0,0 +1,103 @@
+"""
+--exclude globs and the .gardenerignore file
+"""
+
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.scanner import ExcludeRules, parse_gardener_ignore, scan_repository
+from gardener.common.defaults import ConfigOverride
+from gardener.common.secure_file_ops import SecureFileOps
+from gardener.treewalk.go import GoLanguageHandler
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text("module example.com/app\n")
+    for rel_path in (
+        "main.go",
+        "main_test.go",
+        "internal/store/store.go",
+        "internal/store/store_test.go",
+        "tests/fixtures/app/go.mod",
+        "tests/fixtures/app/main.go",
+        "gen/api.pb.go",
+        "web/gen/api.go",
+        "kept.go",
+    ):
+        path = root / rel_path
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text("package x\n" if rel_path.endswith(".go") else "module example.com/fixture\n")
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "pattern, path, excluded",
+    [
+        ("tests/fixtures/**", "tests/fixtures/app/main.go", True),
+        ("tests/fixtures/**", "tests/fixtures", True),
+        ("tests/fixtures/**", "tests/unit/fixtures_test.go", False),
+        ("**/*_test.go", "main_test.go", True),
+        ("**/*_test.go", "internal/store/store_test.go", True),
+        ("**/*_test.go", "internal/store/store.go", False),
+        ("*.go", "main.go", True),
+        ("*.go", "internal/store/store.go", False),
+        ("gen", "gen/api.pb.go", True),
+        ("gen", "web/gen/api.go", False),
+        ("**/gen/**", "web/gen/api.go", True),
+        ("internal/**/store.go", "internal/store/store.go", True),
+        ("internal/*/st?re.go", "internal/store/store.go", True),
+        ("./gen/*.pb.go", "gen/api.pb.go", True),
+        ("gen/[ab]pi.pb.go", "gen/api.pb.go", True),
+    ],
+)
+def test_globs_match_repo_relative_paths(pattern, path, excluded):
+    assert ExcludeRules([pattern]).match_file(path) is excluded
+
+
+@pytest.mark.unit
+def test_ignore_file_lists_one_glob_per_line():
+    assert parse_gardener_ignore("# shared excludes\n\ntests/fixtures/**\n  **/*_test.go  \n") == [
+        "tests/fixtures/**",
+        "**/*_test.go",
+    ]
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("secure", [False, True])
+def test_excluded_paths_are_not_scanned(tmp_path, secure):
+    _make_repo(tmp_path)
+    (tmp_path / ".gardenerignore").write_text("# generated code\ngen/**\n")
+    secure_file_ops = SecureFileOps(str(tmp_path)) if secure else None
+
+    with ConfigOverride({"EXCLUDE_PATTERNS": ["tests/fixtures/**", "**/*_test.go"]}):
+        result = scan_repository(str(tmp_path), secure_file_ops, ["go"], {"go": GoLanguageHandler()}, None)
+
+    assert set(result["source_files"]) == {"main.go", "internal/store/store.go", "web/gen/api.go", "kept.go"}
+    # Manifests under excluded directories are skipped as well
+    assert [path for path in result["manifest_files"] if "fixtures" in path] == []
+
+
+@pytest.mark.unit
+def test_cli_flags_set_the_exclude_overrides(tmp_path, monkeypatch):
+    ignore_file = tmp_path / "shared-ignore"
+    ignore_file.write_text("gen/**\n")
+    captured = {}
+
+    def _run_analysis(*args, **kwargs):
+        captured["overrides"] = args[5]
+        return {"analyzer_details": {"total_files": 1}}
+
+    monkeypatch.setattr(main_cli, "run_analysis", _run_analysis)
+    argv = ["gardener", str(tmp_path), "--exclude", "a/**", "--exclude", "**/*_test.go"]
+    monkeypatch.setattr(sys, "argv", argv + ["--gardener-ignore", str(ignore_file)])
+    main_cli.main()
+
+    assert captured["overrides"]["EXCLUDE_PATTERNS"] == ["a/**", "**/*_test.go"]
+    assert captured["overrides"]["GARDENER_IGNORE_FILE"] == str(ignore_file)
+
+    monkeypatch.setattr(sys, "argv", argv + ["--gardener-ignore", str(tmp_path / "missing")])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    assert excinfo.value.code == 2
@@ -0,0 +1,210 @@
+"""
+CLI exit statuses and the structured error record on stderr
+"""
+
+import json
+import os
+import signal
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.common.exit_codes import (
+    EXIT_ANALYSIS_ERRORS,
+    EXIT_INTERRUPTED,
+    EXIT_INVALID_ARGUMENTS,
+    EXIT_NETWORK_FAILURE,
+    EXIT_NO_ANALYZABLE_FILES,
+    EXIT_UNRESOLVED_URLS,
+)
+from gardener.common.utils import RepositoryCloneError
+from gardener.package_metadata import url_resolver
+
+MALFORMED_REPO_PATH = os.path.abspath("tests/fixtures/malformed_go")
+
+
+def _make_repo(root):
+    root.mkdir()
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire golang.org/x/net v0.17.0\n")
+    (root / "main.go").write_text('package main\n\nimport "golang.org/x/net/html"\n')
+    return str(root)
+
+
+def _run_cli(monkeypatch, capsys, *argv):
+    monkeypatch.setattr(sys, "argv", ["gardener", *argv])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    record = json.loads(capsys.readouterr().err.strip().splitlines()[-1])
+    assert record["exit_code"] == excinfo.value.code
+    return record
+
+
+@pytest.mark.unit
+def test_success_exits_normally(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+    monkeypatch.setattr(sys, "argv", ["gardener", repo, "--offline", "--no-cache", "-l", "go"])
+
+    with offline_mode.set_responses({}):
+        main_cli.main()
+
+    assert '"error_code"' not in capsys.readouterr().err
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "argv",
+    [
+        ["--no-such-flag"],
+        ["--jobs", "0"],
+        ["--config", "{not json"],
+        ["--config", "[1]"],
+        ["--config", '{"NO_SUCH_PARAMETER": 1}'],
+        ["--config", '{"MAX_RETRIES": "many"}'],
+        ["--languages", "cobol"],
+        ["--json-out", "-", "--table-out", "-"],
+        ["--fail-on-unresolved-urls=-1"],
+        ["--fail-on-unresolved-urls-pct", "150"],
+        ["--unresolved-urls-ignore", "offline-skipped"],
+    ],
+)
+def test_invalid_arguments_exit_2(tmp_path, monkeypatch, capsys, argv):
+    record = _run_cli(monkeypatch, capsys, str(tmp_path), *argv)
+
+    assert record["exit_code"] == EXIT_INVALID_ARGUMENTS
+    assert record["error_code"] == "invalid-arguments"
+    assert record["message"]
+
+
+@pytest.mark.unit
+def test_unusable_repository_path_exits_2(tmp_path, monkeypatch, capsys):
+    (tmp_path / "file.txt").write_text("not a directory\n")
+
+    record = _run_cli(monkeypatch, capsys, str(tmp_path / "file.txt"))
+
+    assert record["exit_code"] == EXIT_INVALID_ARGUMENTS
+    assert "is not a directory" in record["message"]
+
+
+@pytest.mark.unit
+def test_no_source_files_exit_3(tmp_path, monkeypatch, capsys):
+    (tmp_path / "repo").mkdir()
+    (tmp_path / "repo" / "README.md").write_text("docs only\n")
+    monkeypatch.chdir(tmp_path)
+
+    record = _run_cli(monkeypatch, capsys, str(tmp_path / "repo"), "--offline", "--no-cache")
+
+    assert record["exit_code"] == EXIT_NO_ANALYZABLE_FILES
+    assert record["error_code"] == "no-analyzable-files"
+
+
+@pytest.mark.integration
+def test_failed_files_with_fail_on_error_exit_1(tmp_path, monkeypatch, capsys):
+    monkeypatch.chdir(tmp_path)
+
+    argv = [MALFORMED_REPO_PATH, "--offline", "--no-cache", "-l", "go", "--fail-on-error"]
+    record = _run_cli(monkeypatch, capsys, *argv)
+
+    assert record["exit_code"] == EXIT_ANALYSIS_ERRORS
+    assert record["error_code"] == "analysis-errors"
+
+
+@pytest.mark.unit
+def test_unresolved_packages_after_network_errors_exit_4(tmp_path, monkeypatch, capsys):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+
+    def _unreachable(url):
+        raise OSError("connection refused")
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _unreachable)
+    record = _run_cli(monkeypatch, capsys, repo, "--no-cache", "-l", "go", "--max-retries", "0")
+
+    assert record["exit_code"] == EXIT_NETWORK_FAILURE
+    assert record["error_code"] == "network-failure"
+    assert "golang.org/x/net" in record["message"]
+
+    # The same failures are expected, not fatal, offline
+    monkeypatch.setattr(sys, "argv", ["gardener", repo, "--no-cache", "-l", "go", "--offline"])
+    main_cli.main()
+
+
+@pytest.mark.unit
+def test_unresolved_urls_gate_exits_5(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    (repo / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (repo / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    (repo / "package.json").write_text('{"dependencies": {"left-pad": "^1.3.0"}}')
+    (repo / "index.js").write_text('const leftPad = require("left-pad");\n')
+    monkeypatch.chdir(tmp_path)
+    argv = [str(repo), "--offline", "--no-cache", "-l", "go,js"]
+
+    with offline_mode.set_responses({}):
+        record = _run_cli(monkeypatch, capsys, *argv, "--fail-on-unresolved-urls")
+        assert (record["error_code"], record["exit_code"]) == ("unresolved-urls", EXIT_UNRESOLVED_URLS)
+        assert record["message"] == "1 of 2 packages (50.0%) have no repository URL: left-pad"
+
+        record = _run_cli(monkeypatch, capsys, *argv, "--fail-on-unresolved-urls-pct", "40")
+        assert record["error_code"] == "unresolved-urls"
+
+        # Within the thresholds, or skipped because of --offline, which is an expected gap
+        for gate in (["--fail-on-unresolved-urls=1"], ["--fail-on-unresolved-urls-pct", "50"]):
+            monkeypatch.setattr(sys, "argv", ["gardener", *argv, *gate])
+            main_cli.main()
+        gate = ["--fail-on-unresolved-urls", "--unresolved-urls-ignore", "offline-skipped"]
+        monkeypatch.setattr(sys, "argv", ["gardener", *argv, *gate])
+        main_cli.main()
+
+
+@pytest.mark.unit
+def test_clone_failure_exits_4(monkeypatch, capsys):
+    def _run_analysis(*args, **kwargs):
+        raise RepositoryCloneError("Failed to clone repository: could not resolve host")
+
+    monkeypatch.setattr(main_cli, "run_analysis", _run_analysis)
+    record = _run_cli(monkeypatch, capsys, "https://example.com/owner/repo")
+
+    assert record == {
+        "error_code": "network-failure",
+        "exit_code": EXIT_NETWORK_FAILURE,
+        "message": "Failed to clone repository: could not resolve host",
+    }
+
+
+@pytest.mark.unit
+def test_interrupted_run_writes_partial_results_and_exits_130(tmp_path, monkeypatch, capsys):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+
+    interrupts = []
+
+    def _interrupting(url):
+        # Ctrl-C pressed once, while the first lookup is in flight
+        if not interrupts:
+            interrupts.append(url)
+            os.kill(os.getpid(), signal.SIGINT)
+        return None
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _interrupting)
+    record = _run_cli(monkeypatch, capsys, repo, "--no-cache", "-l", "go", "--max-retries", "0")
+
+    assert record["exit_code"] == EXIT_INTERRUPTED
+    assert record["error_code"] == "interrupted"
+    [output] = tmp_path.rglob("*_dependency_analysis.json")
+    results = json.loads(output.read_text())
+    assert results["partial"] is True
+    assert "golang.org/x/net" in results["ecosystems"]["go"]
+    assert results["analyzer_details"]["file_imports"] == {}
+
+
+@pytest.mark.unit
+def test_interrupt_outside_the_analysis_exits_130(monkeypatch, capsys):
+    def _run_analysis(*args, **kwargs):
+        raise KeyboardInterrupt
+
+    monkeypatch.setattr(main_cli, "run_analysis", _run_analysis)
+    record = _run_cli(monkeypatch, capsys, "https://example.com/owner/repo")
+
+    assert (record["error_code"], record["exit_code"]) == ("interrupted", EXIT_INTERRUPTED)
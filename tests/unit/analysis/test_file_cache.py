@@ -0,0 +1,78 @@
+"""
+Incremental analysis cache keyed on file content hashes
+"""
+
+import pytest
+
+from gardener.analysis import file_cache
+from gardener.analysis.tree import RepositoryAnalyzer
+from gardener.common.defaults import ConfigOverride
+from gardener.treewalk.go import GoLanguageHandler
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text("module example.com/app\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "main.go").write_text('package main\n\nimport (\n\t"fmt"\n\t"github.com/pkg/errors"\n)\n')
+    (root / "util.go").write_text('package main\n\nimport "strings"\n')
+
+
+def _analyze(root, cache_dir):
+    analyzer = RepositoryAnalyzer(str(root))
+    analyzer.register_language_handler("go", GoLanguageHandler())
+    with ConfigOverride({"CACHE_DIR": str(cache_dir), "ANALYSIS_JOBS": 1}):
+        analyzer.scan_repo()
+        analyzer.process_manifest_files()
+        analyzer.extract_imports_from_all_files()
+    return analyzer
+
+
+@pytest.mark.unit
+def test_unchanged_files_are_served_from_cache(tmp_path):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    _make_repo(repo)
+    cache_dir = tmp_path / "cache"
+
+    first = _analyze(repo, cache_dir)
+    second = _analyze(repo, cache_dir)
+
+    assert first.file_cache_stats == {"hits": 0, "misses": 2}
+    assert second.file_cache_stats == {"hits": 2, "misses": 0}
+    assert dict(second.file_imports) == dict(first.file_imports)
+    assert dict(second.file_package_components) == dict(first.file_package_components)
+    assert dict(second.file_import_evidence) == dict(first.file_import_evidence)
+
+    (repo / "util.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    third = _analyze(repo, cache_dir)
+
+    assert third.file_cache_stats == {"hits": 1, "misses": 1}
+    assert third.file_imports["util.go"] == ["github.com/pkg/errors"]
+
+
+@pytest.mark.unit
+def test_cache_is_invalidated_by_schema_and_context_changes(tmp_path, monkeypatch):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    _make_repo(repo)
+    cache_dir = tmp_path / "cache"
+    _analyze(repo, cache_dir)
+
+    monkeypatch.setattr(file_cache, "CACHE_SCHEMA_VERSION", file_cache.CACHE_SCHEMA_VERSION + 1)
+    assert _analyze(repo, cache_dir).file_cache_stats == {"hits": 0, "misses": 2}
+
+    # A new source file can change how the others resolve local imports
+    (repo / "extra.go").write_text("package main\n")
+    assert _analyze(repo, cache_dir).file_cache_stats == {"hits": 0, "misses": 3}
+
+
+@pytest.mark.unit
+def test_cache_disabled_by_default(tmp_path):
+    _make_repo(tmp_path)
+
+    analyzer = RepositoryAnalyzer(str(tmp_path))
+    analyzer.register_language_handler("go", GoLanguageHandler())
+    analyzer.scan_repo()
+    analyzer.process_manifest_files()
+    analyzer.extract_imports_from_all_files()
+
+    assert analyzer.file_cache_stats is None
@@ -0,0 +1,75 @@
+"""
+Analyzing an explicit file list (--files-from) instead of walking the tree
+"""
+
+import io
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.scanner import parse_file_list
+from gardener.api import AnalysisOptions, analyze_repo
+
+
+def _make_repo(root):
+    root.mkdir()
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgithub.com/google/uuid v1.6.0\n)\n"
+    )
+    (root / ".gitignore").write_text("gen/\n")
+    (root / "util").mkdir()
+    (root / "util" / "util.go").write_text('package util\n\nimport "github.com/google/uuid"\n')
+    (root / "gen").mkdir()
+    (root / "gen" / "gen.go").write_text(
+        'package gen\n\nimport (\n\t"example.com/app/util"\n\t"github.com/pkg/errors"\n)\n'
+    )
+
+
+@pytest.mark.unit
+def test_only_listed_files_are_analyzed_even_when_ignored(tmp_path, offline_mode):
+    repo = tmp_path / "repo"
+    _make_repo(repo)
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(
+            str(repo),
+            AnalysisOptions(offline=True, languages=["go"], files=["./gen/gen.go", "README.md", "../outside.go"]),
+        )
+
+    assert sorted(result.raw["analyzer_details"]["file_imports"]) == ["gen/gen.go"]
+    assert result.analysis_scope == {
+        "mode": "files",
+        "listed_files": 3,
+        "missing_files": ["README.md", "../outside.go"],
+    }
+    # The unlisted util package is still first-party code, found through go.mod
+    entries = result.raw["analyzer_details"]["file_import_evidence"]["gen/gen.go"]
+    evidence = {entry["import"]: entry["scope"] for entry in entries}
+    assert evidence == {"example.com/app/util": "local", "github.com/pkg/errors": "external"}
+    # Manifests are read in full
+    assert "github.com/google/uuid" in result.external_packages
+
+
+@pytest.mark.unit
+def test_files_from_reads_the_list_from_stdin(tmp_path, monkeypatch, offline_mode, capsys):
+    repo = tmp_path / "repo"
+    _make_repo(repo)
+    monkeypatch.chdir(tmp_path)
+    monkeypatch.setattr(sys, "stdin", io.StringIO("util/util.go\r\n\n"))
+    argv = ["gardener", str(repo), "--offline", "--no-cache", "-m", "--files-from", "-", "--json-out", "-"]
+    monkeypatch.setattr(sys, "argv", argv)
+
+    with offline_mode.set_responses({}):
+        main_cli.main()
+
+    results = json.loads(capsys.readouterr().out)
+    assert results["analysis_scope"] == {"mode": "files", "listed_files": 1, "missing_files": []}
+    assert results["analyzer_details"]["total_files"] == 1
+
+
+@pytest.mark.unit
+def test_file_list_skips_blank_lines_and_duplicates():
+    assert parse_file_list("a.go\n\n  b.go \r\na.go\n") == ["a.go", "b.go"]
@@ -0,0 +1,66 @@
+"""
+Git submodules declared in .gitmodules
+"""
+
+import pytest
+
+from gardener.analysis.scanner import read_gitmodules
+from gardener.api import AnalysisOptions, analyze_repo
+
+GITMODULES = """\
+[submodule "third_party/lib"]
+\tpath = third_party/lib
+\turl = git@github.com:acme/lib.git
+\tbranch = stable
+[submodule "tools"]
+\tpath = ./tools/
+\turl = https://gitlab.com/acme/tools.git
+[submodule "broken"]
+\tpath = broken
+"""
+
+
+def _make_repo(root):
+    (root / ".gitmodules").write_text(GITMODULES)
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n")
+    (root / "main.go").write_text('package main\n\nimport "fmt"\n')
+    (root / "third_party" / "lib").mkdir(parents=True)
+    (root / "third_party" / "lib" / "lib.go").write_text('package lib\n\nimport "github.com/pkg/errors"\n')
+
+
+@pytest.mark.unit
+def test_gitmodules_entries_are_read_with_their_branch(tmp_path):
+    _make_repo(tmp_path)
+
+    assert read_gitmodules(str(tmp_path), None, None) == [
+        {"path": "third_party/lib", "url": "git@github.com:acme/lib.git", "branch": "stable"},
+        {"path": "tools", "url": "https://gitlab.com/acme/tools.git", "branch": None},
+    ]
+
+
+@pytest.mark.unit
+def test_submodules_are_dependencies_and_their_sources_are_skipped(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    lib = result.external_packages["third_party/lib"]
+    assert (lib["ecosystem"], lib["submodule_path"], lib["ref"]) == ("git-submodule", "third_party/lib", "stable")
+    assert lib["repository_url"] == "https://github.com/acme/lib"
+    assert result.external_packages["tools"]["ref"] is None
+    # Code inside the submodule checkout is not first-party evidence
+    assert "third_party/lib/lib.go" not in result.raw["analyzer_details"]["file_import_evidence"]
+    assert "github.com/pkg/errors" not in result.external_packages
+
+
+@pytest.mark.unit
+def test_scan_submodules_parses_their_sources(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}):
+        options = AnalysisOptions(offline=True, languages=["go"], config_overrides={"SCAN_SUBMODULES": True})
+        result = analyze_repo(str(tmp_path), options)
+
+    assert "third_party/lib/lib.go" in result.raw["analyzer_details"]["file_import_evidence"]
+    assert result.external_packages["third_party/lib"]["ecosystem"] == "git-submodule"
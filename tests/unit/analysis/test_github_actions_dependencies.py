@@ -0,0 +1,128 @@
+"""
+Actions, reusable workflows and images referenced by GitHub Actions workflows
+"""
+
+import pytest
+
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.treewalk.github_actions import GitHubActionsAnalyzer, parse_action_reference
+
+SHA = "b4ffde65f46336ab88eb53be808477a3936bae11"
+
+WORKFLOW = f"""\
+name: ci
+on: [push]
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: Set up Go
+        uses: "actions/setup-go@{SHA}" # v5.0.0
+      - uses: ./.github/actions/setup
+      - uses: github/codeql-action/init@v3
+      - uses: docker://alpine:3.19
+      - uses: ${{{{ matrix.action }}}}
+      - run: echo "uses: not/an-action@v1"
+  release:
+    uses: acme/shared/.github/workflows/release.yml@main
+"""
+
+SETUP_ACTION = """\
+name: setup
+runs:
+  using: composite
+  steps:
+    - uses: actions/cache@v3
+"""
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "reference, expected",
+    [
+        ("actions/checkout@v4", {"kind": "action", "action": "actions/checkout", "ref": "v4", "pinned": False}),
+        (f"actions/checkout@{SHA}", {"kind": "action", "action": "actions/checkout", "ref": SHA, "pinned": True}),
+        (
+            "acme/shared/.github/workflows/release.yml@main",
+            {
+                "kind": "reusable-workflow",
+                "action": "acme/shared/.github/workflows/release.yml",
+                "ref": "main",
+                "pinned": False,
+            },
+        ),
+        ("./.github/actions/setup", {"kind": "local", "path": "./.github/actions/setup"}),
+        ("actions/checkout", None),
+        ("${{ matrix.action }}", None),
+    ],
+)
+def test_uses_references_are_classified(reference, expected):
+    assert parse_action_reference(reference) == expected
+
+
+@pytest.mark.unit
+def test_docker_references_are_images():
+    parsed = parse_action_reference("docker://ghcr.io/acme/tool:1.2")
+    assert (parsed["kind"], parsed["name"], parsed["tag"]) == ("docker", "ghcr.io/acme/tool", "1.2")
+
+
+@pytest.mark.unit
+def test_workflows_and_actions_are_claimed_by_path():
+    analyzer = GitHubActionsAnalyzer(None)
+
+    assert analyzer.matches(".github/workflows/ci.yml")
+    assert analyzer.matches(".github/workflows/release.yaml")
+    assert analyzer.matches(".github/actions/setup/action.yml")
+    assert not analyzer.matches(".github/workflows/scripts/helper.yml")
+    assert not analyzer.matches(".github/dependabot.yml")
+    assert not analyzer.matches("config/ci.yml")
+
+
+@pytest.mark.unit
+def test_workflow_dependencies_join_the_analysis(tmp_path, offline_mode):
+    workflows = tmp_path / ".github" / "workflows"
+    workflows.mkdir(parents=True)
+    (workflows / "ci.yml").write_text(WORKFLOW)
+    setup = tmp_path / ".github" / "actions" / "setup"
+    setup.mkdir(parents=True)
+    (setup / "action.yml").write_text(SETUP_ACTION)
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True))
+
+    packages = result.external_packages
+    assert sorted(name for name, info in packages.items() if info["ecosystem"] == "github-actions") == [
+        "acme/shared/.github/workflows/release.yml",
+        "actions/cache",
+        "actions/checkout",
+        "actions/setup-go",
+        "github/codeql-action/init",
+    ]
+    checkout = packages["actions/checkout"]
+    assert (checkout["action"], checkout["ref"], checkout["repository_url"]) == (
+        "actions/checkout",
+        "v4",
+        "https://github.com/actions/checkout",
+    )
+    assert checkout["resolution"]["source"] == "action-reference"
+    assert packages["actions/setup-go"]["pinned"] is True
+    assert packages["github/codeql-action/init"]["repository_subpath"] == "init"
+    assert packages["acme/shared/.github/workflows/release.yml"]["kind"] == "reusable-workflow"
+    alpine = packages["docker.io/library/alpine"]
+    assert (alpine["ecosystem"], alpine["scope"], alpine["tag"]) == ("docker", "action-image", "3.19")
+
+    details = result.raw["analyzer_details"]
+    assert dict(details["local_imports_map"]) == {".github/workflows/ci.yml": [".github/actions/setup/action.yml"]}
+    assert details["file_imports"][".github/actions/setup/action.yml"] == ["actions/cache"]
+    evidence = {entry["import"]: entry for entry in details["file_import_evidence"][".github/workflows/ci.yml"]}
+    assert evidence["actions/checkout@v4"] == {
+        "import": "actions/checkout@v4",
+        "kind": "action",
+        "line": 7,
+        "scope": "external",
+        "module": "actions/checkout",
+        "id": "file:.github/workflows/ci.yml#actions/checkout@v4",
+    }
+    assert evidence["./.github/actions/setup"]["scope"] == "local"
+    assert "not/an-action@v1" not in str(evidence)
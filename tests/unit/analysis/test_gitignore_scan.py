@@ -0,0 +1,81 @@
+"""
+.gitignore handling during repository scans
+"""
+
+import pytest
+
+from gardener.analysis.scanner import GitignoreRules, scan_repository
+from gardener.common.defaults import ConfigOverride
+from gardener.common.secure_file_ops import SecureFileOps
+from gardener.treewalk.go import GoLanguageHandler
+
+
+def _make_repo(root):
+    (root / ".gitignore").write_text("# build output\nnode_modules/\ndist/\nvendor/\n*.gen.go\n!keep.gen.go\n")
+    (root / "go.mod").write_text("module example.com/app\n")
+    for rel_path in (
+        "main.go",
+        "api.gen.go",
+        "keep.gen.go",
+        "node_modules/left-pad/index.go",
+        "dist/bundle.go",
+        "vendor/github.com/pkg/errors/errors.go",
+        "web/app.go",
+        "web/scratch/tmp.go",
+        "web/fixtures/data.go",
+        "web/fixtures/golden.go",
+    ):
+        path = root / rel_path
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text("package x\n")
+    # Nested rules apply below their own directory only, and may re-include files
+    (root / "web" / ".gitignore").write_text("scratch/\n/fixtures/*\n!/fixtures/golden.go\n")
+    (root / "docs").mkdir()
+    (root / "docs" / "scratch").mkdir()
+    (root / "docs" / "scratch" / "notes.go").write_text("package notes\n")
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("secure", [False, True])
+def test_gitignored_paths_are_skipped(tmp_path, secure):
+    _make_repo(tmp_path)
+    secure_file_ops = SecureFileOps(str(tmp_path)) if secure else None
+
+    with ConfigOverride({"SCAN_VENDOR": True}):
+        result = scan_repository(str(tmp_path), secure_file_ops, ["go"], {"go": GoLanguageHandler()}, None)
+
+    assert set(result["source_files"]) == {
+        "main.go",
+        "keep.gen.go",
+        "web/app.go",
+        "web/fixtures/golden.go",
+        "docs/scratch/notes.go",
+    }
+
+
+@pytest.mark.unit
+def test_no_gitignore_override_scans_ignored_paths(tmp_path):
+    _make_repo(tmp_path)
+
+    with ConfigOverride({"RESPECT_GITIGNORE": False, "SCAN_VENDOR": True}):
+        result = scan_repository(str(tmp_path), None, ["go"], {"go": GoLanguageHandler()}, None)
+
+    assert result["gitignore_spec"] is None
+    assert {"node_modules/left-pad/index.go", "dist/bundle.go", "web/scratch/tmp.go"} <= set(result["source_files"])
+    assert "vendor/github.com/pkg/errors/errors.go" in result["source_files"]
+
+
+@pytest.mark.unit
+def test_nested_patterns_are_anchored_to_their_directory():
+    rules = GitignoreRules()
+    rules.add(".", "*.log\n")
+    rules.add("pkg", "build/\n/local.txt\n!debug.log\n")
+
+    assert rules.match_file("app.log")
+    assert not rules.match_file("pkg/debug.log")
+    assert rules.match_file("other/debug.log")
+    assert rules.match_file("pkg/sub/build", is_dir=True)
+    assert not rules.match_file("pkg/sub/build")
+    assert rules.match_file("pkg/local.txt")
+    assert not rules.match_file("pkg/sub/local.txt")
+    assert not rules.match_file("local.txt")
@@ -0,0 +1,84 @@
+"""
+Go dependencies declared by Gazelle go_repository rules in Bazel files
+"""
+
+import pytest
+
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.treewalk.go import parse_bazel_go_repositories
+
+DEPS_BZL = '''\
+load("@bazel_gazelle//:deps.bzl", "go_repository")
+
+def go_dependencies():
+    go_repository(
+        name = "com_github_pkg_errors",
+        importpath = "github.com/pkg/errors",
+        sum = "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=",
+        version = "v0.9.1",
+    )
+    # go_repository(name = "com_example_old", importpath = "example.com/old", version = "v1.0.0")
+    go_repository(
+        name = "org_golang_x_net",
+        build_directives = ["gazelle:exclude testdata"],  # keeps (parens) in strings and comments
+        importpath = "golang.org/x/net",
+        replace = "github.com/fork/net",
+        version = "v0.17.0",
+    )
+    go_repository(name = "computed", importpath = IMPORTPATH, version = VERSION)
+'''
+
+
+@pytest.mark.unit
+def test_go_repository_rules_are_parsed_from_string_attributes():
+    assert parse_bazel_go_repositories(DEPS_BZL) == [
+        {
+            "name": "com_github_pkg_errors",
+            "importpath": "github.com/pkg/errors",
+            "sum": "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=",
+            "version": "v0.9.1",
+        },
+        {
+            "name": "org_golang_x_net",
+            "importpath": "golang.org/x/net",
+            "replace": "github.com/fork/net",
+            "version": "v0.17.0",
+        },
+    ]
+
+
+@pytest.mark.unit
+def test_bazel_dependencies_feed_the_go_pipeline_without_a_go_mod(tmp_path, offline_mode):
+    (tmp_path / "WORKSPACE").write_text('load("//:deps.bzl", "go_dependencies")\n\ngo_dependencies()\n')
+    (tmp_path / "deps.bzl").write_text(DEPS_BZL)
+    (tmp_path / "main.go").write_text('package main\n\nimport (\n\t"github.com/pkg/errors"\n\t"golang.org/x/net/html"\n)\n')
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    errors = result.external_packages["github.com/pkg/errors"]
+    assert (errors["version"], errors["bazel_rule"]) == ("v0.9.1", "com_github_pkg_errors")
+    assert errors["checksum"] == "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4="
+    assert errors["repository_url"] == "https://github.com/pkg/errors"
+    net = result.external_packages["golang.org/x/net"]
+    assert net["replace"] == {"path": "github.com/fork/net", "version": "v0.17.0", "local": False}
+    assert net["repository_url"] == "https://github.com/fork/net"
+    evidence = {entry["import"]: entry for entry in result.raw["analyzer_details"]["file_import_evidence"]["main.go"]}
+    assert evidence["golang.org/x/net/html"]["version"] == "v0.17.0"
+    assert evidence["golang.org/x/net/html"]["replaced_to"] == "github.com/fork/net@v0.17.0"
+
+
+@pytest.mark.unit
+def test_bazel_rules_merge_with_go_mod_requirements(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.8.1\n")
+    (tmp_path / "deps.bzl").write_text(DEPS_BZL)
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    errors = result.external_packages["github.com/pkg/errors"]
+    # Differing versions are reconciled like those of any package found in several manifests
+    assert (errors["version"], errors["bazel_rule"]) == ("v0.9.1", "com_github_pkg_errors")
+    assert [conflict["version"] for conflict in errors["version_conflicts"]] == ["v0.8.1"]
+    assert result.external_packages["golang.org/x/net"]["version"] == "v0.17.0"
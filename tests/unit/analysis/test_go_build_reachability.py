@@ -0,0 +1,111 @@
+"""
+Imports of Go files no common build configuration compiles (build_reachability)
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.common.defaults import ConfigOverride, GoAnalysisConfig
+from gardener.treewalk.go import go_build_reachable
+
+PLATFORMS = ("linux/amd64", "darwin/arm64", "windows/amd64", "android/arm64", "js/wasm")
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/lib/pq v1.10.9\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgolang.org/x/sys v0.15.0\n"
+        ")\n"
+    )
+    (root / "app.go").write_text('package app\n\nimport "github.com/pkg/errors"\n')
+    (root / "dead.go").write_text(
+        '//go:build linux && windows\n\npackage app\n\nimport (\n\t"github.com/pkg/errors"\n'
+        '\t"golang.org/x/sys/unix"\n)\n'
+    )
+    (root / "db_test.go").write_text('//go:build integration\n\npackage app\n\nimport _ "github.com/lib/pq"\n')
+
+
+def _analyze(root):
+    analyzer = DependencyAnalyzer()
+    return analyzer.analyze_dependencies(analyzer.discover_packages(str(root), ["go"]))
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "expression, expected",
+    [
+        ("linux", True),
+        ("linux && windows", False),
+        ("!linux && !darwin && !windows && !android && !js", False),
+        ("android && !linux", False),
+        ("ios", False),
+        ("unix && !wasm", True),
+        ("js && wasm", True),
+        ("ignore && linux", False),
+        ("integration", False),
+        ("race || integration", True),
+        ("cgo && linux", True),
+        ("gc && gccgo", False),
+        ("!go1.18", True),
+        ("linux && (", None),
+        ("", True),
+    ],
+)
+def test_constraints_are_tried_on_each_platform(expression, expected):
+    assert go_build_reachable(expression, PLATFORMS, ["race"]) is expected
+
+
+@pytest.mark.unit
+def test_imports_of_unreachable_files_are_flagged_unlikely(tmp_path):
+    _make_repo(tmp_path)
+
+    results = _analyze(tmp_path)
+
+    external = results["external_packages"]
+    # Only imported by files no build compiles
+    assert external["golang.org/x/sys"]["build_reachability"] == "unlikely"
+    assert external["github.com/lib/pq"]["build_reachability"] == "unlikely"
+    assert "build_reachability" not in external["github.com/pkg/errors"]
+    seen_in = external["github.com/pkg/errors"]["seen_in"]
+    assert {entry["file"]: entry.get("build_reachability") for entry in seen_in} == {
+        "app.go": None,
+        "dead.go": "unlikely",
+    }
+    evidence = results["analyzer_details"]["file_import_evidence"]
+    assert {entry.get("build_reachability") for entry in evidence["dead.go"]} == {"unlikely"}
+    assert "build_reachability" not in evidence["app.go"][0]
+
+
+@pytest.mark.unit
+def test_custom_build_tags_keep_their_files_reachable(tmp_path):
+    _make_repo(tmp_path)
+
+    with ConfigOverride({"BUILD_TAGS": list(GoAnalysisConfig.BUILD_TAGS) + ["integration"]}):
+        results = _analyze(tmp_path)
+
+    external = results["external_packages"]
+    assert "build_reachability" not in external["github.com/lib/pq"]
+    assert "build_reachability" not in external["github.com/lib/pq"]["seen_in"][0]
+    assert external["golang.org/x/sys"]["build_reachability"] == "unlikely"
+
+
+@pytest.mark.unit
+def test_cgo_files_keep_their_build_constraint(tmp_path):
+    _make_repo(tmp_path)
+    # The native library parsed from the preamble comes first in the file's evidence
+    (tmp_path / "native.go").write_text(
+        "//go:build linux && windows\n\npackage app\n\n// #cgo LDFLAGS: -lz\n"
+        'import "C"\n\nimport "github.com/pkg/errors"\n'
+    )
+
+    results = _analyze(tmp_path)
+
+    seen_in = {entry["file"]: entry for entry in results["external_packages"]["github.com/pkg/errors"]["seen_in"]}
+    assert seen_in["native.go"]["build_constraint"] == "linux && windows"
+    assert seen_in["native.go"]["build_tags"] == ["linux", "windows"]
+    assert seen_in["native.go"]["build_reachability"] == "unlikely"
+    evidence = results["analyzer_details"]["file_import_evidence"]["native.go"]
+    assert [entry.get("native_dependency") for entry in evidence] == ["z", None]
+    assert {entry.get("build_reachability") for entry in evidence} == {"unlikely"}
@@ -0,0 +1,133 @@
+"""
+Deprecated Go modules and retracted versions
+"""
+
+import pytest
+
+from gardener.analysis.go_deprecations import deprecation_fields, find_retraction
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.package_metadata import url_resolver
+from gardener.treewalk.go import parse_go_mod_file
+
+LIB_GO_MOD = """\
+// Package lib is the shared client.
+//
+// Deprecated: use example.com/lib/v2 instead.
+// It receives security fixes only.
+module example.com/lib
+
+go 1.21
+
+// Published by accident.
+retract v1.0.0
+
+retract [v1.1.0, v1.1.5] // data race in Client
+
+retract (
+    // Broken build.
+    v1.2.0
+    [v1.3.0-rc.1, v1.3.2]
+)
+"""
+
+
+@pytest.fixture
+def no_goproxy(monkeypatch):
+    for name in url_resolver.GO_PRIVATE_ENV_VARS + ("GOPROXY",):
+        monkeypatch.delenv(name, raising=False)
+
+
+@pytest.mark.unit
+def test_deprecation_comment_and_retract_directives_are_parsed():
+    parsed = parse_go_mod_file(LIB_GO_MOD)
+
+    assert parsed["deprecated"] == "use example.com/lib/v2 instead. It receives security fixes only."
+    assert parsed["retract"] == [
+        {"low": "v1.0.0", "high": "v1.0.0", "rationale": "Published by accident."},
+        {"low": "v1.1.0", "high": "v1.1.5", "rationale": "data race in Client"},
+        {"low": "v1.2.0", "high": "v1.2.0", "rationale": "Broken build."},
+        {"low": "v1.3.0-rc.1", "high": "v1.3.2", "rationale": ""},
+    ]
+    assert parse_go_mod_file("module example.com/old // Deprecated: gone\n")["deprecated"] == "gone"
+    # A blank line detaches the comment from the module directive
+    assert parse_go_mod_file("// Deprecated: stale\n\nmodule example.com/app\n")["deprecated"] == ""
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "version, rationale",
+    [
+        ("v1.0.0", "Published by accident."),
+        ("v1.1.3", "data race in Client"),
+        ("v1.1.6", None),
+        ("v1.3.0-rc.2", ""),
+        ("v1.3.0-beta", None),
+        ("v1.10.0", None),
+        ("not-a-version", None),
+    ],
+)
+def test_retractions_cover_closed_semver_intervals(version, rationale):
+    retraction = find_retraction(parse_go_mod_file(LIB_GO_MOD)["retract"], version)
+    if rationale is None:
+        assert retraction is None
+    else:
+        assert retraction["rationale"] == rationale
+
+
+@pytest.mark.unit
+def test_deprecation_fields_combine_retraction_and_module_notice():
+    assert deprecation_fields({"deprecated": "", "retract": []}, "v1.0.0") == {}
+    retract = [{"low": "v1.0.0", "high": "v1.0.0", "rationale": ""}]
+    assert deprecation_fields({"deprecated": "use v2", "retract": retract}, "v1.0.0") == {
+        "deprecated": True,
+        "retracted": True,
+        "deprecation_message": "v1.0.0 is retracted; module deprecated: use v2",
+    }
+
+
+@pytest.mark.unit
+def test_repository_go_mod_notices_are_listed_with_their_module(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text(LIB_GO_MOD + "\nrequire github.com/pkg/errors v0.9.1\n")
+    (tmp_path / "lib.go").write_text('package lib\n\nimport "github.com/pkg/errors"\n')
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    module = result.raw["go_modules"]["example.com/lib"]
+    assert module["deprecated"] == "use example.com/lib/v2 instead. It receives security fixes only."
+    assert [retraction["low"] for retraction in module["retract"]] == ["v1.0.0", "v1.1.0", "v1.2.0", "v1.3.0-rc.1"]
+    assert "deprecated" not in result.external_packages["github.com/pkg/errors"]
+
+
+@pytest.mark.unit
+def test_latest_go_mod_from_the_proxy_flags_dependencies_online(tmp_path, offline_mode, no_goproxy):
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\nrequire (\n\tgithub.com/old/kit v0.4.0\n\tgithub.com/pkg/errors v0.9.1\n)\n"
+    )
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/old/kit"\n')
+    proxy = "https://proxy.golang.org/github.com/old/kit/@v/"
+    responses = {
+        proxy + "list": "v0.4.0\nv0.5.0\n",
+        proxy + "v0.5.0.info": '{"Version": "v0.5.0", "Time": "2024-01-02T03:04:05Z"}',
+        proxy + "v0.5.0.mod": "module github.com/old/kit\n\nretract v0.4.0 // leaks goroutines\n",
+    }
+
+    with offline_mode.set_responses(responses):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(languages=["go"], config_overrides={"MAX_RETRIES": 0}))
+
+    kit = result.external_packages["github.com/old/kit"]
+    assert (kit["deprecated"], kit["retracted"]) == (True, True)
+    assert kit["deprecation_message"] == "v0.4.0 is retracted: leaks goroutines"
+    assert "deprecated" not in result.external_packages["github.com/pkg/errors"]
+
+
+@pytest.mark.unit
+def test_offline_analysis_does_not_query_the_proxy(tmp_path, monkeypatch):
+    (tmp_path / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/old/kit v0.4.0\n")
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", lambda url: requested.append(url))
+
+    result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    assert "deprecated" not in result.external_packages["github.com/old/kit"]
+    assert requested == []
@@ -0,0 +1,73 @@
+"""
+Example scope for Go dependencies only imported by documentation examples
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.treewalk.go import is_go_example_file
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/fatih/color v1.16.0\n"
+        "\tgithub.com/google/go-cmp v0.6.0\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgithub.com/stretchr/testify v1.8.4\n"
+        ")\n"
+    )
+    (root / "app.go").write_text('package app\n\nimport "github.com/pkg/errors"\n')
+    (root / "example_test.go").write_text(
+        'package app_test\n\nimport (\n\t"fmt"\n\n\t"github.com/fatih/color"\n\t"github.com/pkg/errors"\n)\n\n'
+        'func Example() {\n\tfmt.Println(color.RedString("x"), errors.New("y"))\n\t// Output: x y\n}\n'
+    )
+    # Example functions next to a test function make a regular test file
+    (root / "app_test.go").write_text(
+        'package app\n\nimport (\n\t"testing"\n\n\t"github.com/google/go-cmp/cmp"\n)\n\n'
+        "func TestApp(t *testing.T) { _ = cmp.Diff(1, 2) }\n\nfunc ExampleApp() {}\n"
+    )
+    (root / "render_test.go").write_text(
+        'package app\n\nimport "github.com/stretchr/testify/assert"\n\nfunc ExampleRender_json() { _ = assert.True }\n'
+    )
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "rel_path, source, expected",
+    [
+        ("example_test.go", "package app_test\n", True),
+        ("pkg/example_usage.go", "package main\n", True),
+        ("app_test.go", "package app\n\nfunc ExampleApp() {}\nfunc ExampleApp_second() {}\n", True),
+        ("app_test.go", "package app\n\nfunc ExampleApp() {}\nfunc BenchmarkApp(b *testing.B) {}\n", False),
+        ("app_test.go", "package app\n\nfunc Examples() {}\n", False),
+        ("app_test.go", "package app\n", False),
+        ("app.go", "package app\n\nfunc ExampleApp() {}\n", False),
+    ],
+)
+def test_example_files_only_hold_examples(rel_path, source, expected):
+    assert is_go_example_file(rel_path, source) is expected
+
+
+@pytest.mark.unit
+def test_imports_only_in_examples_get_example_scope(tmp_path):
+    _make_repo(tmp_path)
+
+    analyzer = DependencyAnalyzer()
+    results = analyzer.analyze_dependencies(analyzer.discover_packages(str(tmp_path), ["go"]))
+
+    external = results["external_packages"]
+    assert external["github.com/fatih/color"]["scope"] == "example"
+    # Also imported by production code, so it stays a production dependency
+    assert external["github.com/pkg/errors"]["scope"] == "production"
+    assert {entry["file"]: entry["scope"] for entry in external["github.com/pkg/errors"]["seen_in"]} == {
+        "app.go": "production",
+        "example_test.go": "example",
+    }
+    assert external["github.com/google/go-cmp"]["scope"] == "test"
+    # A test framework only used by an example is still example-scoped, not test tooling
+    assert external["github.com/stretchr/testify"]["scope"] == "example"
+    evidence = results["analyzer_details"]["file_import_evidence"]["example_test.go"]
+    assert {entry["scope"] for entry in evidence} == {"example"}
+    assert results["summary"]["scopes"]["example"] == 2
+    assert results["summary"]["scopes"]["test"] == 1
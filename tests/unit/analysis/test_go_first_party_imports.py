@@ -0,0 +1,70 @@
+"""
+Module-qualified Go imports of the repository's own packages
+"""
+
+import pytest
+
+from gardener.api import AnalysisOptions, analyze_repo
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module github.com/myorg/myapp\n\ngo 1.21\n\n"
+        "require (\n\tgithub.com/myorg/myapp/sdk v1.4.0\n\tgithub.com/pkg/errors v0.9.1\n)\n"
+    )
+    for directory, files in {
+        "internal/db": ["db.go", "conn.go"],
+        "internal/config": ["config.go"],
+        "pkg/util": ["strings.go", "numbers.go"],
+    }.items():
+        (root / directory).mkdir(parents=True)
+        for name in files:
+            (root / directory / name).write_text(f"package {directory.rsplit('/', 1)[-1]}\n")
+    (root / "cmd").mkdir()
+    (root / "cmd" / "main.go").write_text(
+        "package main\n\nimport (\n"
+        '\t"github.com/myorg/myapp"\n'
+        '\t"github.com/myorg/myapp/internal/config"\n'
+        '\tdb "github.com/myorg/myapp/internal/db"\n'
+        '\t"github.com/myorg/myapp/pkg/util"\n'
+        '\t"github.com/myorg/myapp/sdk/client"\n'
+        '\t"github.com/myorg/myapp-tools/lint"\n'
+        '\t"github.com/pkg/errors"\n'
+        ")\n"
+    )
+    (root / "app.go").write_text("package myapp\n")
+
+
+@pytest.mark.unit
+def test_imports_under_the_module_path_are_first_party(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True))
+
+    details = result.raw["analyzer_details"]
+    scopes = {entry["import"]: entry for entry in details["file_import_evidence"]["cmd/main.go"]}
+    for local_import in (
+        "github.com/myorg/myapp",
+        "github.com/myorg/myapp/internal/config",
+        "github.com/myorg/myapp/internal/db",
+        "github.com/myorg/myapp/pkg/util",
+    ):
+        assert scopes[local_import]["scope"] == "local"
+        assert scopes[local_import]["module"] == "github.com/myorg/myapp"
+        assert scopes[local_import]["resolved_import"] == local_import
+    assert scopes["github.com/myorg/myapp/internal/db"]["alias"] == "db"
+
+    # A nested module required from go.mod, and a module merely sharing the prefix, are dependencies
+    sdk = scopes["github.com/myorg/myapp/sdk/client"]
+    assert (sdk["scope"], sdk["module"]) == ("external", "github.com/myorg/myapp/sdk")
+    assert scopes["github.com/myorg/myapp-tools/lint"]["scope"] == "external"
+    assert scopes["github.com/pkg/errors"]["scope"] == "external"
+
+    assert set(details["file_imports"]["cmd/main.go"]) == {
+        "github.com/myorg/myapp/sdk/client",
+        "github.com/myorg/myapp-tools/lint",
+        "github.com/pkg/errors",
+    }
+    assert sorted(details["local_imports_map"]["cmd/main.go"]) == ["app.go", "internal/config/config.go", "internal/db/db.go"]
+    assert not any(name.startswith("github.com/myorg/myapp/internal") for name in result.external_packages)
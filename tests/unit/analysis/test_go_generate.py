@@ -0,0 +1,114 @@
+"""
+Tools invoked by //go:generate directives
+"""
+
+import pytest
+
+from gardener.analysis.go_generate import attach_go_generate_tools
+from gardener.analysis.sbom import package_purl
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.treewalk.go import go_generate_tool, parse_go_generate_directives
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "command, tool",
+    [
+        ("mockgen -source=store.go -destination=mock_store.go", ("mockgen", "")),
+        ("$GOPATH/bin/protoc --go_out=. api.proto", ("protoc", "")),
+        ("go run golang.org/x/tools/cmd/stringer@v0.17.0 -type=Pill", ("golang.org/x/tools/cmd/stringer", "v0.17.0")),
+        ("go run -mod=mod github.com/99designs/gqlgen generate", ("github.com/99designs/gqlgen", "")),
+        ("go tool mockery --name Store", ("mockery", "")),
+        ("go vet ./...", ("go", "")),
+    ],
+)
+def test_the_first_word_names_the_tool(command, tool):
+    assert go_generate_tool(command) == tool
+
+
+@pytest.mark.unit
+def test_only_directives_at_line_start_are_parsed():
+    source = (
+        "package store\n\n"
+        "//go:generate mockgen -source=store.go\n"
+        "  //go:generate indented\n"
+        "// //go:generate commented\n"
+        "//go:generateother\n"
+        "//go:generate\n"
+        "//go:generate\tstringer -type=Kind\n"
+    )
+    assert parse_go_generate_directives(source) == [
+        {"line": 3, "command": "mockgen -source=store.go", "tool": "mockgen"},
+        {"line": 8, "command": "stringer -type=Kind", "tool": "stringer"},
+    ]
+
+
+@pytest.mark.unit
+def test_tools_are_mapped_to_the_modules_providing_them():
+    external_packages = {"github.com/golang/mock": {"ecosystem": "go", "version": "v1.6.0"}}
+    evidence = {
+        "store.go": [
+            {"import": "context", "scope": "stdlib"},
+            {"generate": "mockgen", "command": "mockgen -source=store.go", "line": 3, "scope": "generate"},
+            {"generate": "protoc", "command": "protoc --go_out=. api.proto", "line": 4, "scope": "generate"},
+            {"generate": "sh", "command": "sh -c 'echo hi'", "line": 5, "scope": "generate"},
+            {"generate": "gen-docs", "command": "gen-docs ./...", "line": 6, "scope": "generate"},
+        ]
+    }
+
+    added = attach_go_generate_tools(external_packages, evidence)
+
+    assert added == ["generate:protoc", "generate:gen-docs"]
+    mock = external_packages["github.com/golang/mock"]
+    assert (mock["version"], mock["scope"]) == ("v1.6.0", "generate")
+    assert mock["generate"] == [{"file": "store.go", "line": 3, "command": "mockgen -source=store.go"}]
+    protoc = external_packages["generate:protoc"]
+    assert protoc["repository_url"] == "https://github.com/protocolbuffers/protobuf"
+    assert package_purl("generate:protoc", protoc) == "pkg:generic/protoc"
+    assert external_packages["generate:gen-docs"]["resolution"] == {"reason": "unknown-generate-tool"}
+
+
+@pytest.mark.unit
+def test_go_run_packages_resolve_to_their_module():
+    external_packages = {"golang.org/x/tools": {"ecosystem": "go", "scope": "production"}}
+    evidence = {
+        "a.go": [{"generate": "golang.org/x/tools/cmd/stringer", "command": "go run ...", "line": 1}],
+        "b.go": [
+            {"generate": "github.com/acme/gen/cmd/gen", "command": "go run ...@v1.2.0", "line": 2, "version": "v1.2.0"}
+        ],
+    }
+
+    assert attach_go_generate_tools(external_packages, evidence) == ["github.com/acme/gen"]
+    assert external_packages["golang.org/x/tools"]["scope"] == "production"
+    gen = external_packages["github.com/acme/gen"]
+    assert (gen["version"], gen["repository_url"]) == ("v1.2.0", "https://github.com/acme/gen")
+    assert gen["resolution"] == {"source": "go-run", "confidence": "medium"}
+
+
+@pytest.mark.unit
+def test_generate_tools_are_reported_from_analysis(tmp_path):
+    (tmp_path / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire go.uber.org/mock v0.4.0\n")
+    (tmp_path / "store.go").write_text(
+        "package app\n\n"
+        "//go:generate mockgen -source=store.go -destination=mock_store.go\n"
+        "//go:generate go run golang.org/x/tools/cmd/stringer@v0.17.0 -type=Kind\n\n"
+        'import "context"\n\n'
+        "type Store interface{ Get(ctx context.Context) }\n"
+    )
+
+    result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    mock = result.external_packages["go.uber.org/mock"]
+    assert (mock["version"], mock["scope"]) == ("v0.4.0", "generate")
+    assert mock["generate"] == [
+        {"file": "store.go", "line": 3, "command": "mockgen -source=store.go -destination=mock_store.go"}
+    ]
+    tools = result.external_packages["golang.org/x/tools"]
+    assert (tools["version"], tools["scope"], tools["purl"]) == (
+        "v0.17.0",
+        "generate",
+        "pkg:golang/golang.org/x/tools@v0.17.0",
+    )
+    assert tools["license_reason"] == "offline-skipped"
+    assert result.raw["summary"]["scopes"]["generate"] == 2
+    assert result.raw["import_graph"]["edges"] == []
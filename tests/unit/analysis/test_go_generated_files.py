@@ -0,0 +1,54 @@
+"""
+Tagging and exclusion of machine-generated Go files
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.common.defaults import ConfigOverride
+
+
+def _analyze(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\nrequire (\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgoogle.golang.org/protobuf v1.33.0\n"
+        ")\n"
+    )
+    (root / "app.go").write_text('package app\n\nimport "github.com/pkg/errors"\n')
+    (root / "app.pb.go").write_text(
+        "// Code generated by protoc-gen-go. DO NOT EDIT.\n// source: app.proto\n\npackage app\n\n"
+        'import (\n\t"github.com/pkg/errors"\n\t"google.golang.org/protobuf/proto"\n)\n'
+    )
+    analyzer = DependencyAnalyzer()
+    packages = analyzer.discover_packages(str(root), ["go"])
+    return analyzer.analyze_dependencies(packages)
+
+
+@pytest.mark.unit
+def test_generated_file_imports_are_tagged_by_default(tmp_path):
+    results = _analyze(tmp_path)
+
+    evidence = results["analyzer_details"]["file_import_evidence"]
+    assert all(entry["generated"] for entry in evidence["app.pb.go"])
+    assert "generated" not in evidence["app.go"][0]
+    protobuf = results["external_packages"]["google.golang.org/protobuf"]
+    assert protobuf["seen_in"] == [
+        {
+            "file": "app.pb.go",
+            "imports": ["google.golang.org/protobuf/proto"],
+            "scope": "production",
+            "generated": True,
+        }
+    ]
+
+
+@pytest.mark.unit
+def test_exclude_generated_drops_generated_file_imports(tmp_path):
+    with ConfigOverride({"EXCLUDE_GENERATED": True}):
+        results = _analyze(tmp_path)
+
+    assert "app.pb.go" not in results["analyzer_details"]["file_import_evidence"]
+    errors = results["external_packages"]["github.com/pkg/errors"]
+    assert [entry["file"] for entry in errors["seen_in"]] == ["app.go"]
+    assert "seen_in" not in results["external_packages"]["google.golang.org/protobuf"]
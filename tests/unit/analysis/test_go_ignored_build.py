@@ -0,0 +1,84 @@
+"""
+Imports of Go files tagged `//go:build ignore` (--include-ignored-build)
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.common.defaults import GoAnalysisConfig
+from gardener.treewalk.go import go_build_constraint_scope
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/dave/jennifer v1.7.0\n"
+        "\tgithub.com/golang/mock v1.6.0\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        ")\n"
+    )
+    (root / "app.go").write_text('package app\n\nimport "github.com/pkg/errors"\n')
+    (root / "gen.go").write_text(
+        '//go:build ignore\n\npackage main\n\nimport (\n\t"os"\n\n\t"github.com/dave/jennifer/jen"\n'
+        '\t"github.com/pkg/errors"\n)\n'
+    )
+    (root / "example.go").write_text('// +build ignore\n\npackage main\n\nimport _ "github.com/golang/mock/gomock"\n')
+
+
+def _analyze(root):
+    analyzer = DependencyAnalyzer()
+    return analyzer.analyze_dependencies(analyzer.discover_packages(str(root), ["go"]))
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "expression, expected",
+    [
+        ("ignore", "ignored-build"),
+        ("ignore && linux", "ignored-build"),
+        ("ignore && tools", "ignored-build"),
+        ("tools", "tool"),
+        ("ignore || linux", ""),
+        ("!ignore", ""),
+        ("", ""),
+    ],
+)
+def test_ignore_and_tools_constraints_map_to_their_scopes(expression, expected):
+    assert go_build_constraint_scope(expression) == expected
+
+
+@pytest.mark.unit
+def test_ignored_build_files_are_left_out_by_default(tmp_path):
+    _make_repo(tmp_path)
+
+    results = _analyze(tmp_path)
+
+    external = results["external_packages"]
+    # Still required by go.mod, but imported by no file of the build
+    assert "seen_in" not in external["github.com/dave/jennifer"]
+    assert "scope" not in external["github.com/dave/jennifer"]
+    assert [entry["file"] for entry in external["github.com/pkg/errors"]["seen_in"]] == ["app.go"]
+    assert results["summary"]["scopes"]["ignored-build"] == 0
+    assert "gen.go" not in results["analyzer_details"]["file_import_evidence"]
+
+
+@pytest.mark.unit
+def test_included_ignored_build_imports_get_their_own_scope(tmp_path, monkeypatch):
+    _make_repo(tmp_path)
+    monkeypatch.setattr(GoAnalysisConfig, "INCLUDE_IGNORED_BUILD", True)
+
+    results = _analyze(tmp_path)
+
+    external = results["external_packages"]
+    assert external["github.com/dave/jennifer"]["scope"] == "ignored-build"
+    assert external["github.com/golang/mock"]["scope"] == "ignored-build"
+    # Production code importing it too keeps the package a production dependency
+    assert external["github.com/pkg/errors"]["scope"] == "production"
+    assert {entry["file"]: entry["scope"] for entry in external["github.com/pkg/errors"]["seen_in"]} == {
+        "app.go": "production",
+        "gen.go": "ignored-build",
+    }
+    evidence = results["analyzer_details"]["file_import_evidence"]["gen.go"]
+    assert {entry["scope"] for entry in evidence} == {"ignored-build"}
+    assert results["summary"]["scopes"]["ignored-build"] == 2
+    assert results["summary"]["scopes"]["production"] == 1
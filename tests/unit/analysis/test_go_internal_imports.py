@@ -0,0 +1,84 @@
+"""
+Go imports of internal packages from outside the tree that may use them
+"""
+
+import pytest
+
+from gardener.analysis.go_internal_imports import find_go_internal_imports, internal_import_root
+from gardener.api import AnalysisOptions, analyze_repo
+
+
+def _write(path, text):
+    path.parent.mkdir(parents=True, exist_ok=True)
+    path.write_text(text)
+
+
+@pytest.mark.unit
+def test_first_party_and_external_internal_imports_are_reported(tmp_path, offline_mode):
+    _write(tmp_path / "go.mod", "module example.com/app\n\ngo 1.22\n\nrequire golang.org/x/tools v0.20.0\n")
+    _write(tmp_path / "a" / "internal" / "x" / "x.go", "package x\n")
+    _write(tmp_path / "a" / "sub" / "sub.go", 'package sub\n\nimport "example.com/app/a/internal/x"\n')
+    _write(
+        tmp_path / "b" / "b.go",
+        'package b\n\nimport (\n\t"example.com/app/a/internal/x"\n\t"golang.org/x/tools/internal/event"\n)\n',
+    )
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    # a/sub lives under a, the parent of the internal directory, so its import is allowed
+    assert result.warnings == [
+        {
+            "warning": "internal-import",
+            "import": "example.com/app/a/internal/x",
+            "importer": "example.com/app/b",
+            "allowed_under": "example.com/app/a",
+            "target": "example.com/app/a/internal/x",
+            "external": False,
+            "files": ["b/b.go"],
+            "message": "example.com/app/b imports example.com/app/a/internal/x, which is internal to "
+            "example.com/app/a (b/b.go)",
+        },
+        {
+            "warning": "internal-import",
+            "import": "golang.org/x/tools/internal/event",
+            "importer": "example.com/app/b",
+            "allowed_under": "golang.org/x/tools",
+            "target": "golang.org/x/tools",
+            "external": True,
+            "files": ["b/b.go"],
+            "message": "example.com/app/b imports golang.org/x/tools/internal/event, which is internal to "
+            "golang.org/x/tools (b/b.go)",
+        },
+    ]
+
+
+@pytest.mark.unit
+def test_only_go_files_with_module_qualified_importers_are_checked():
+    import_graph = {
+        "nodes": [
+            {"id": "example.com/app/cmd", "kind": "first_party", "path": "cmd"},
+            {"id": "web", "kind": "first_party", "path": "web"},
+            {"id": "internal/cpu", "kind": "stdlib"},
+            {"id": "lodash", "kind": "external"},
+        ],
+        "edges": [
+            {"source": "example.com/app/cmd", "target": "internal/cpu", "import": "internal/cpu", "files": ["cmd/m.go"]},
+            {"source": "web", "target": "internal/cpu", "import": "internal/cpu", "files": ["web/m.go"]},
+            {"source": "example.com/app/cmd", "target": "lodash", "import": "lodash/internal/a", "files": ["cmd/a.js"]},
+        ],
+    }
+
+    warnings = find_go_internal_imports(import_graph)
+
+    assert [(warning["importer"], warning["allowed_under"], warning["external"]) for warning in warnings] == [
+        ("example.com/app/cmd", "", True)
+    ]
+    assert "internal to the standard library" in warnings[0]["message"]
+
+
+@pytest.mark.unit
+def test_deepest_internal_element_decides_who_may_import():
+    assert internal_import_root("example.com/a/internal/b/internal/c") == "example.com/a/internal/b"
+    assert internal_import_root("example.com/a/internal") == "example.com/a"
+    assert internal_import_root("example.com/internals/x") is None
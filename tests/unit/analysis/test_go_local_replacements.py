@@ -0,0 +1,80 @@
+"""
+Go modules replaced by a local directory
+"""
+
+import pytest
+
+from gardener.analysis.tree import RepositoryAnalyzer
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.treewalk.go import GoLanguageHandler
+
+APP_GO_MOD = """\
+module example.com/app
+
+go 1.22
+
+require (
+\tgithub.com/org/lib v0.0.0
+\tgithub.com/org/gone v0.0.0
+\tgithub.com/pkg/errors v0.9.1
+)
+
+replace github.com/org/lib => ../lib
+
+replace github.com/org/gone => ./third_party/gone
+"""
+
+
+def _process_manifests(repo):
+    analyzer = RepositoryAnalyzer(str(repo))
+    analyzer.register_language_handler("go", GoLanguageHandler())
+    analyzer.scan_repo()
+    return analyzer, analyzer.process_manifest_files()
+
+
+@pytest.mark.unit
+def test_replacement_paths_are_recorded_and_missing_targets_flagged(tmp_path):
+    repo = tmp_path / "app"
+    repo.mkdir()
+    (repo / "go.mod").write_text(APP_GO_MOD)
+    (repo / "main.go").write_text('package main\n\nimport "github.com/org/lib/client"\n')
+    (tmp_path / "lib").mkdir()
+    (tmp_path / "lib" / "go.mod").write_text("module github.com/org/lib\n\ngo 1.22\n")
+
+    _, packages = _process_manifests(repo)
+
+    # The sibling checkout lies outside the analyzed repository, so it stays a dependency
+    assert packages["github.com/org/lib"]["replacement_path"] == "../lib"
+    assert "replacement_missing" not in packages["github.com/org/lib"]
+    assert packages["github.com/org/gone"]["replacement_path"] == "third_party/gone"
+    assert packages["github.com/org/gone"]["replacement_missing"] is True
+    assert "replacement_path" not in packages["github.com/pkg/errors"]
+
+
+@pytest.mark.unit
+def test_replacement_inside_the_repository_is_a_local_module(tmp_path, offline_mode):
+    (tmp_path / "go.work").write_text("go 1.22\n\nuse ./app\n")
+    (tmp_path / "app").mkdir()
+    (tmp_path / "app" / "go.mod").write_text(APP_GO_MOD)
+    (tmp_path / "app" / "main.go").write_text(
+        'package main\n\nimport (\n\t"github.com/org/lib/client"\n\t"github.com/pkg/errors"\n)\n'
+    )
+    (tmp_path / "lib" / "client").mkdir(parents=True)
+    (tmp_path / "lib" / "go.mod").write_text("module github.com/org/lib\n\ngo 1.22\n")
+    (tmp_path / "lib" / "client" / "client.go").write_text("package client\n")
+
+    analyzer, packages = _process_manifests(tmp_path)
+
+    assert "github.com/org/lib" not in packages
+    assert analyzer.go_workspace_modules == {"example.com/app": "app", "github.com/org/lib": "lib"}
+    assert packages["github.com/org/gone"]["replacement_missing"] is True
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    assert "github.com/org/lib" not in result.external_packages
+    assert result.raw["go_modules"]["example.com/app"]["local_replacements"] == {
+        "github.com/org/gone": "app/third_party/gone",
+        "github.com/org/lib": "lib",
+    }
+    assert "local_replacements" not in result.raw["go_modules"]["github.com/org/lib"]
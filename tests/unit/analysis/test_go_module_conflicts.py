@@ -0,0 +1,153 @@
+"""
+Duplicate and shadowed Go module declarations, and diverging requirements, across a repository
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.go_module_conflicts import find_go_module_conflicts, find_go_version_conflicts
+from gardener.analysis.output_schema import validate_document
+from gardener.api import AnalysisOptions, analyze_repo
+
+
+def _write_module(directory, module_path, extra=""):
+    directory.mkdir(parents=True, exist_ok=True)
+    (directory / "go.mod").write_text(f"module {module_path}\n\ngo 1.22\n{extra}")
+    (directory / "main.go").write_text('package main\n\nimport "fmt"\n')
+
+
+@pytest.mark.unit
+def test_workspace_using_two_directories_with_the_same_module_is_reported(tmp_path, offline_mode):
+    (tmp_path / "go.work").write_text("go 1.22\n\nuse (\n\t./api\n\t./api-v2\n)\n")
+    _write_module(tmp_path / "api", "example.com/api")
+    _write_module(tmp_path / "api-v2", "example.com/api")
+    _write_module(tmp_path / "testdata" / "api", "example.com/api")
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    assert result.warnings == [
+        {
+            "warning": "duplicate-module",
+            "module": "example.com/api",
+            "go_mod_files": ["api-v2/go.mod", "api/go.mod"],
+            "go_work": ["go.work"],
+            "message": "Go module example.com/api is declared by 2 go.mod files: api-v2/go.mod, api/go.mod",
+        }
+    ]
+    assert result.raw["warnings"] == result.warnings
+
+
+@pytest.mark.unit
+def test_replace_redirecting_a_repository_module_elsewhere_is_reported(tmp_path, offline_mode):
+    _write_module(tmp_path / "lib", "example.com/lib")
+    _write_module(
+        tmp_path / "app",
+        "example.com/app",
+        "\nrequire example.com/lib v1.0.0\n\nreplace example.com/lib => github.com/fork/lib v1.0.1\n",
+    )
+    _write_module(
+        tmp_path / "tool",
+        "example.com/tool",
+        "\nrequire example.com/lib v1.0.0\n\nreplace example.com/lib => ../lib\n",
+    )
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    # tool/go.mod points the replace at the module's own directory, which is not a conflict
+    assert result.warnings == [
+        {
+            "warning": "replace-shadows-module",
+            "module": "example.com/lib",
+            "go_mod_files": ["lib/go.mod"],
+            "replace": {"path": "github.com/fork/lib", "version": "v1.0.1", "local": False},
+            "declared_in": "app/go.mod",
+            "message": (
+                "app/go.mod replaces Go module example.com/lib, declared by lib/go.mod, "
+                "with github.com/fork/lib v1.0.1"
+            ),
+        }
+    ]
+    assert validate_document(json.loads(json.dumps(result.raw, default=str))) == []
+
+
+@pytest.mark.unit
+def test_repositories_without_conflicts_have_no_warnings_section(tmp_path, offline_mode):
+    _write_module(tmp_path, "example.com/app")
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    assert result.warnings == []
+    assert "warnings" not in result.raw
+
+
+@pytest.mark.unit
+def test_versioned_replace_and_ignored_directories(tmp_path):
+    root = str(tmp_path)
+    go_mod_files = {
+        f"{root}/go.mod": "example.com/app",
+        f"{root}/_old/go.mod": "example.com/app",
+        f"{root}/lib/go.mod": "example.com/lib",
+    }
+    replace_directives = {
+        f"{root}/go.mod": {"example.com/lib@v1.2.0": {"path": "../elsewhere", "version": "", "local": True}},
+    }
+
+    warnings = find_go_module_conflicts(go_mod_files, replace_directives, {}, root)
+
+    assert [(warning["warning"], warning["module"]) for warning in warnings] == [
+        ("replace-shadows-module", "example.com/lib")
+    ]
+    assert warnings[0]["message"].endswith("with ../elsewhere")
+
+
+@pytest.mark.unit
+def test_modules_requiring_different_versions_of_a_dependency_are_listed(tmp_path, offline_mode):
+    (tmp_path / "go.work").write_text("go 1.22\n\nuse (\n\t./api\n\t./worker\n)\n")
+    _write_module(
+        tmp_path / "api",
+        "example.com/api",
+        "\nrequire (\n\tgithub.com/x/y v1.2.0\n\tgithub.com/pkg/errors v0.9.1\n\texample.com/worker v0.1.0\n)\n",
+    )
+    _write_module(
+        tmp_path / "worker",
+        "example.com/worker",
+        "\nrequire (\n\tgithub.com/x/y v1.10.0\n\tgithub.com/pkg/errors v0.9.1\n)\n",
+    )
+    _write_module(tmp_path / "testdata" / "old", "example.com/old", "\nrequire github.com/x/y v1.0.0\n")
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    # Equal versions are no conflict, and a go.mod under testdata is not one of the repository's modules
+    assert result.version_conflicts == [
+        {
+            "dependency": "github.com/x/y",
+            "versions": {"example.com/api": "v1.2.0", "example.com/worker": "v1.10.0"},
+            "selected": "v1.10.0",
+        }
+    ]
+    assert result.raw["version_conflicts"] == result.version_conflicts
+
+
+@pytest.mark.unit
+def test_version_conflicts_skip_repository_modules_and_single_requirers():
+    module_requirements = {
+        "example.com/app": {"example.com/lib": "v0.1.0", "github.com/a/b": "v1.0.0-rc.1"},
+        "example.com/tool": {"example.com/lib": "v0.2.0", "github.com/a/b": "v1.0.0"},
+        "example.com/lib": {"github.com/c/d": "v2.0.0"},
+    }
+    go_modules = {"example.com/app": "app", "example.com/tool": "tool", "example.com/lib": "lib"}
+
+    conflicts = find_go_version_conflicts(module_requirements, go_modules)
+
+    assert conflicts == [
+        {
+            "dependency": "github.com/a/b",
+            "versions": {"example.com/app": "v1.0.0-rc.1", "example.com/tool": "v1.0.0"},
+            "selected": "v1.0.0",
+        }
+    ]
@@ -0,0 +1,99 @@
+"""
+Repositories holding several go.mod modules without a go.work file
+"""
+
+import pytest
+
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.treewalk.go import find_go_module_for_file
+
+
+def _make_monorepo(root):
+    (root / "go.mod").write_text(
+        "module example.com/mono\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n"
+    )
+    (root / "main.go").write_text(
+        'package main\n\nimport (\n\t"example.com/mono/services/api/handlers"\n\t"github.com/pkg/errors"\n)\n'
+    )
+    api = root / "services" / "api"
+    (api / "handlers").mkdir(parents=True)
+    (api / "go.mod").write_text(
+        "module example.com/mono/services/api\n\ngo 1.21\n\nrequire (\n"
+        "\texample.com/lib v0.3.0\n\tgithub.com/pkg/errors v0.8.0\n\tgithub.com/gorilla/mux v1.8.1\n)\n"
+    )
+    (api / "handlers" / "handlers.go").write_text(
+        "package handlers\n\nimport (\n"
+        '\t"example.com/lib/strutil"\n'
+        '\t"example.com/mono/services/api/internal/auth"\n'
+        '\t"github.com/gorilla/mux"\n'
+        '\t"github.com/pkg/errors"\n'
+        ")\n"
+    )
+    (api / "internal" / "auth").mkdir(parents=True)
+    (api / "internal" / "auth" / "auth.go").write_text("package auth\n")
+    lib = root / "lib"
+    (lib / "strutil").mkdir(parents=True)
+    (lib / "go.mod").write_text("module example.com/lib\n\ngo 1.21\n")
+    (lib / "strutil" / "strutil.go").write_text("package strutil\n")
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "rel_path, expected",
+    [
+        ("main.go", ("example.com/mono", ".")),
+        ("services/api/handlers/handlers.go", ("example.com/mono/services/api", "services/api")),
+        ("services/apiserver/x.go", ("example.com/mono", ".")),
+        ("lib/strutil/strutil.go", ("example.com/lib", "lib")),
+    ],
+)
+def test_nearest_go_mod_owns_a_file(rel_path, expected):
+    modules = {"example.com/mono": ".", "example.com/mono/services/api": "services/api", "example.com/lib": "lib"}
+    assert find_go_module_for_file(rel_path, modules) == expected
+    assert find_go_module_for_file(rel_path, {}) == (None, None)
+
+
+@pytest.mark.unit
+def test_modules_of_one_repository_resolve_each_other_locally(tmp_path, offline_mode):
+    _make_monorepo(tmp_path)
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True))
+
+    external = result.external_packages
+    assert "example.com/lib" not in external
+    assert external["github.com/gorilla/mux"]["go_modules"] == ["example.com/mono/services/api"]
+    assert sorted(external["github.com/pkg/errors"]["go_modules"]) == [
+        "example.com/mono",
+        "example.com/mono/services/api",
+    ]
+
+    evidence = result.raw["analyzer_details"]["file_import_evidence"]
+    handlers = {entry["import"]: entry for entry in evidence["services/api/handlers/handlers.go"]}
+    assert (handlers["example.com/lib/strutil"]["scope"], handlers["example.com/lib/strutil"]["module"]) == (
+        "local",
+        "example.com/lib",
+    )
+    auth = handlers["example.com/mono/services/api/internal/auth"]
+    assert (auth["scope"], auth["module"]) == ("local", "example.com/mono/services/api")
+    # Each file sees the versions its own go.mod requires
+    assert handlers["github.com/pkg/errors"]["version"] == "v0.8.0"
+    root = {entry["import"]: entry for entry in evidence["main.go"]}
+    assert root["github.com/pkg/errors"]["version"] == "v0.9.1"
+    assert root["example.com/mono/services/api/handlers"]["module"] == "example.com/mono/services/api"
+
+    local_imports = result.raw["analyzer_details"]["local_imports_map"]
+    assert local_imports["main.go"] == ["services/api/handlers/handlers.go"]
+    assert sorted(local_imports["services/api/handlers/handlers.go"]) == [
+        "lib/strutil/strutil.go",
+        "services/api/internal/auth/auth.go",
+    ]
+
+    assert result.raw["go_modules"] == {
+        "example.com/lib": {"directory": "lib", "dependencies": []},
+        "example.com/mono": {"directory": ".", "dependencies": ["github.com/pkg/errors"]},
+        "example.com/mono/services/api": {
+            "directory": "services/api",
+            "dependencies": ["github.com/gorilla/mux", "github.com/pkg/errors"],
+        },
+    }
@@ -0,0 +1,118 @@
+"""
+Indirect go.mod requires reconciled with the imports of the repository
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.go_prunable import find_go_require_mismatches
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.output_schema import validate_document
+from gardener.common.defaults import ConfigOverride
+
+PROXY = "https://proxy.golang.org"
+GO_MOD = (
+    "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+    "\tgithub.com/gin-gonic/gin v1.9.1\n"
+    "\tgithub.com/google/uuid v1.3.0 // indirect\n"
+    "\tgolang.org/x/net v0.12.0 // indirect\n"
+    "\tgithub.com/stale/dep v1.0.0 // indirect\n"
+    "\tgithub.com/unused/direct v0.1.0\n"
+    ")\n"
+)
+MAIN_GO = 'package main\n\nimport (\n\t"github.com/gin-gonic/gin"\n\t"github.com/google/uuid"\n)\n'
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(GO_MOD)
+    (root / "main.go").write_text(MAIN_GO)
+
+
+@pytest.mark.unit
+def test_offline_reconciliation_uses_the_imports_alone(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}), ConfigOverride({"CHECK_PRUNABLE": True, "OFFLINE": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path), ["go"])
+
+    # Without the go.mod files of the requires, x/net may still be needed by gin
+    assert results["prunable_candidates"] == [
+        {"module": "github.com/stale/dep", "version": "v1.0.0", "go_modules": ["example.com/app"], "verified": False},
+        {"module": "golang.org/x/net", "version": "v0.12.0", "go_modules": ["example.com/app"], "verified": False},
+    ]
+    assert results["warnings"] == [
+        {
+            "warning": "imported-indirect",
+            "module": "github.com/google/uuid",
+            "go_modules": ["example.com/app"],
+            "files": ["main.go"],
+            "message": "go.mod marks github.com/google/uuid as indirect, but it is used by main.go; "
+            "go mod tidy drops the // indirect comment",
+        }
+    ]
+    assert validate_document(json.loads(json.dumps(results, default=str))) == []
+
+
+@pytest.mark.unit
+def test_online_check_keeps_requires_of_imported_modules(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+    responses = {
+        f"{PROXY}/github.com/gin-gonic/gin/@v/v1.9.1.mod": "module github.com/gin-gonic/gin\n\n"
+        "require golang.org/x/net v0.10.0\n",
+        f"{PROXY}/github.com/google/uuid/@v/v1.3.0.mod": "module github.com/google/uuid\n",
+        f"{PROXY}/golang.org/x/net/@v/v0.12.0.mod": "module golang.org/x/net\n",
+        f"{PROXY}/github.com/stale/dep/@v/v1.0.0.mod": "module github.com/stale/dep\n",
+        f"{PROXY}/github.com/unused/direct/@v/v0.1.0.mod": "module github.com/unused/direct\n\n"
+        "require github.com/stale/dep v1.0.0\n",
+    }
+
+    with offline_mode.set_responses(responses), ConfigOverride({"CHECK_PRUNABLE": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path), ["go"])
+
+    assert results["prunable_candidates"] == [
+        {
+            "module": "github.com/stale/dep",
+            "version": "v1.0.0",
+            "go_modules": ["example.com/app"],
+            "verified": True,
+            "required_by": ["github.com/unused/direct"],
+        }
+    ]
+
+
+@pytest.mark.unit
+def test_the_check_is_off_by_default_and_skipped_for_partial_analyses(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path), ["go"])
+    assert "prunable_candidates" not in results
+    assert "warnings" not in results
+
+    with offline_mode.set_responses({}), ConfigOverride({"CHECK_PRUNABLE": True, "OFFLINE": True}):
+        results = DependencyAnalyzer(files=["main.go"]).analyze(str(tmp_path), ["go"])
+    assert "prunable_candidates" not in results
+
+
+@pytest.mark.unit
+def test_unreadable_go_mod_of_a_used_module_leaves_candidates_unverified():
+    packages = {
+        "example.com/used": {"ecosystem": "go", "go_modules": ["example.com/app"], "direct": True, "seen_in": [{}]},
+        "example.com/tool": {
+            "ecosystem": "go",
+            "go_modules": ["example.com/app"],
+            "direct": False,
+            "generate": [{"file": "gen.go", "line": 3, "command": "go run example.com/tool"}],
+        },
+        "example.com/left": {"ecosystem": "go", "go_modules": ["example.com/app"], "direct": False},
+        "left-pad": {"ecosystem": "npm", "direct": False},
+    }
+    graph = {name: {"required_by": []} for name in packages}
+    graph["example.com/used"]["fetch_reason"] = "proxy-not-found"
+
+    candidates, warnings = find_go_require_mismatches(packages, graph)
+
+    assert [(candidate["module"], candidate["verified"]) for candidate in candidates] == [("example.com/left", False)]
+    # A tool `go run` by a go:generate directive is used like an import
+    assert [(warning["module"], warning["files"]) for warning in warnings] == [("example.com/tool", ["gen.go"])]
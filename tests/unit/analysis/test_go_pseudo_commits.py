@@ -0,0 +1,118 @@
+"""
+Verification of the commits Go pseudo-versions pin
+"""
+
+import json
+import urllib.error
+
+import pytest
+
+from gardener.analysis.go_pseudo_commits import attach_go_pseudo_commits
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.resolution_cache import ResolutionCache
+
+SHA = "abc123def4567890abc123def4567890abc12345"
+FORK_SHA = "0123456789ab0000000000000000000000000000"
+COMMITS = {
+    "https://api.github.com/repos/acme/lib/commits/abc123def456": {
+        "sha": SHA,
+        "commit": {"committer": {"date": "2023-06-15T12:00:00Z"}},
+    },
+    "https://api.github.com/repos/fork/bar/commits/0123456789ab": {"sha": FORK_SHA, "commit": {}},
+}
+
+
+def _github(requested):
+    def _request(url):
+        requested.append(url)
+        if url.endswith("/commits/ffffffffffff"):
+            raise urllib.error.HTTPError(url, 422, "No commit found for SHA: ffffffffffff", {}, None)
+        payload = COMMITS.get(url)
+        return json.dumps(payload) if payload else None
+
+    return _request
+
+
+def _packages():
+    return {
+        "github.com/acme/lib": {
+            "ecosystem": "go",
+            "version": "v0.0.0-20230615120000-abc123def456",
+            "repository_url": "https://github.com/acme/lib",
+        },
+        "github.com/acme/lib/v2": {
+            "ecosystem": "go",
+            "version": "v2.0.0-20230615120000-abc123def456",
+            "repository_url": "https://github.com/acme/lib",
+        },
+        "github.com/acme/forged": {
+            "ecosystem": "go",
+            "version": "v0.0.0-20230615120000-ffffffffffff",
+            "repository_url": "https://github.com/acme/forged",
+        },
+        "github.com/foo/bar": {
+            "ecosystem": "go",
+            "version": "v1.0.0",
+            "replace": {"path": "github.com/fork/bar", "version": "v0.0.0-20240101000000-0123456789ab"},
+            "repository_url": "https://github.com/fork/bar",
+        },
+        "github.com/acme/tagged": {
+            "ecosystem": "go",
+            "version": "v1.2.3",
+            "repository_url": "https://github.com/acme/tagged",
+        },
+        "gitlab.com/acme/mod": {
+            "ecosystem": "go",
+            "version": "v0.0.0-20230615120000-abc123def456",
+            "repository_url": "https://gitlab.com/acme/mod",
+        },
+    }
+
+
+@pytest.mark.unit
+def test_pinned_commits_are_verified_or_flagged_missing(monkeypatch):
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _github(requested))
+    packages = _packages()
+
+    with ConfigOverride({"MAX_RETRIES": 0, "RESOLVER_CONCURRENCY": 2}):
+        assert attach_go_pseudo_commits(packages) == ["github.com/acme/forged"]
+
+    lib = packages["github.com/acme/lib"]
+    assert (lib["commit_sha"], lib["commit_date"], lib["commit_verified"]) == (SHA, "2023-06-15T12:00:00Z", True)
+    assert packages["github.com/acme/lib/v2"]["commit_sha"] == SHA
+    assert packages["github.com/acme/forged"]["commit_verified"] is False
+    assert packages["github.com/acme/forged"]["resolution"] == {"commit_reason": "commit-not-found"}
+    # A replaced module pins the commit of the replacement repository
+    assert packages["github.com/foo/bar"]["commit_sha"] == FORK_SHA
+    assert "commit_date" not in packages["github.com/foo/bar"]
+    for name in ("github.com/acme/tagged", "gitlab.com/acme/mod"):
+        assert not {"commit_sha", "commit_verified", "resolution"} & set(packages[name])
+    # One request per repository and revision
+    assert sorted(requested) == [
+        "https://api.github.com/repos/acme/forged/commits/ffffffffffff",
+        "https://api.github.com/repos/acme/lib/commits/abc123def456",
+        "https://api.github.com/repos/fork/bar/commits/0123456789ab",
+    ]
+
+
+@pytest.mark.unit
+def test_cached_commits_are_reused_even_offline(tmp_path, monkeypatch):
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _github(requested))
+    cache = ResolutionCache(str(tmp_path), 0)
+    with ConfigOverride({"MAX_RETRIES": 0}):
+        attach_go_pseudo_commits({"github.com/acme/lib": _packages()["github.com/acme/lib"]}, resolution_cache=cache)
+    cache.save()
+    assert len(requested) == 1
+
+    packages = _packages()
+    # The TTL only applies to repository URLs: a commit never changes
+    missing = attach_go_pseudo_commits(packages, offline=True, resolution_cache=ResolutionCache(str(tmp_path), 0))
+
+    assert missing == []
+    assert len(requested) == 1
+    assert packages["github.com/acme/lib"]["commit_verified"] is True
+    assert packages["github.com/acme/forged"]["resolution"] == {"commit_reason": "offline-skipped"}
+    assert "commit_verified" not in packages["github.com/acme/forged"]
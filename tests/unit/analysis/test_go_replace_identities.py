@@ -0,0 +1,71 @@
+"""
+Import and resolved identities of Go modules redirected by `replace`
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.output_schema import validate_document
+from gardener.common.defaults import ConfigOverride
+
+GO_MOD = """\
+module example.com/app
+
+go 1.21
+
+require (
+\tgithub.com/foo/bar v1.0.0
+\tgo.uber.org/zap v1.26.0
+\tgithub.com/org/lib v0.0.0
+\tgithub.com/pkg/errors v0.9.1
+)
+
+replace github.com/foo/bar => github.com/fork/bar v1.2.0
+
+replace go.uber.org/zap => github.com/acme/zap v1.26.1-patched
+
+replace github.com/org/lib => ./third_party/lib
+"""
+
+
+@pytest.mark.unit
+def test_replaced_modules_keep_their_import_and_resolved_identities(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text(GO_MOD)
+    (tmp_path / "main.go").write_text(
+        'package main\n\nimport (\n\t"github.com/foo/bar/sub"\n\t"go.uber.org/zap"\n'
+        '\t"github.com/org/lib"\n\t"github.com/pkg/errors"\n)\n'
+    )
+
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path), ["go"])
+
+    packages = results["external_packages"]
+    fields = ("import_path", "import_url", "resolved_module", "resolved_version", "resolved_url")
+
+    def identities(name):
+        return {key: packages[name][key] for key in fields if key in packages[name]}
+
+    assert identities("github.com/foo/bar") == {
+        "import_path": "github.com/foo/bar",
+        "import_url": "https://github.com/foo/bar",
+        "resolved_module": "github.com/fork/bar",
+        "resolved_version": "v1.2.0",
+        "resolved_url": "https://github.com/fork/bar",
+    }
+    # A vanity import path needs a lookup to name its repository
+    assert identities("go.uber.org/zap") == {
+        "import_path": "go.uber.org/zap",
+        "resolved_module": "github.com/acme/zap",
+        "resolved_version": "v1.26.1-patched",
+        "resolved_url": "https://github.com/acme/zap",
+    }
+    assert identities("github.com/org/lib") == {
+        "import_path": "github.com/org/lib",
+        "import_url": "https://github.com/org/lib",
+        "resolved_module": "third_party/lib",
+        "resolved_url": None,
+    }
+    assert identities("github.com/pkg/errors") == {}
+    assert validate_document(json.loads(json.dumps(results, default=str))) == []
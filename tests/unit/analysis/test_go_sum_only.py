@@ -0,0 +1,72 @@
+"""
+Modules listed in go.sum but required by no go.mod (--include-go-sum-only)
+"""
+
+import pytest
+
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.treewalk.go import parse_go_sum_modules
+
+GO_SUM = """\
+github.com/pkg/errors v0.9.1 h1:errors-zip=
+github.com/pkg/errors v0.9.1/go.mod h1:errors-mod=
+github.com/old/lib v1.2.0 h1:lib-120-zip=
+github.com/old/lib v1.3.0 h1:lib-130-zip=
+github.com/old/lib v1.10.0/go.mod h1:lib-1100-mod=
+github.com/graph/only v0.1.0/go.mod h1:graph-010-mod=
+github.com/graph/only v0.2.0/go.mod h1:graph-020-mod=
+github.com/fork/bar v1.2.1 h1:fork-zip=
+example.com/app/tools v0.0.0-20240101000000-abcdefabcdef h1:tools-zip=
+"""
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n\n"
+        "replace github.com/foo/bar => github.com/fork/bar v1.2.1\n"
+    )
+    (root / "go.sum").write_text(GO_SUM)
+    (root / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    (root / "tools").mkdir()
+    (root / "tools" / "go.mod").write_text("module example.com/app/tools\n\ngo 1.21\n")
+
+
+@pytest.mark.unit
+def test_go_sum_versions_keep_whether_the_code_was_hashed():
+    modules = parse_go_sum_modules(GO_SUM)
+
+    assert modules["github.com/pkg/errors"] == {"v0.9.1": {"checksum": "h1:errors-zip=", "zip": True}}
+    assert modules["github.com/old/lib"]["v1.10.0"] == {"checksum": "h1:lib-1100-mod=", "zip": False}
+
+
+@pytest.mark.unit
+def test_go_sum_only_modules_are_left_out_by_default(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, languages=["go"]))
+
+    assert set(result.external_packages) == {"github.com/pkg/errors"}
+
+
+@pytest.mark.unit
+def test_go_sum_only_modules_are_reported_with_a_representative_version(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}):
+        options = AnalysisOptions(offline=True, languages=["go"], config_overrides={"INCLUDE_GO_SUM_ONLY": True})
+        result = analyze_repo(str(tmp_path), options)
+
+    # Replacement targets and the repository's own modules are not leftovers
+    assert set(result.external_packages) == {"github.com/pkg/errors", "github.com/old/lib", "github.com/graph/only"}
+    assert "source" not in result.external_packages["github.com/pkg/errors"]
+    lib = result.external_packages["github.com/old/lib"]
+    # The highest version whose code go.sum hashes wins over a higher go.mod-only entry
+    assert (lib["source"], lib["version"], lib["checksum"], lib["direct"]) == (
+        "go.sum-only",
+        "v1.3.0",
+        "h1:lib-130-zip=",
+        False,
+    )
+    assert lib["repository_url"] == "https://github.com/old/lib"
+    assert result.external_packages["github.com/graph/only"]["version"] == "v0.2.0"
@@ -0,0 +1,138 @@
+"""
+Test-only scope annotation for Go dependencies
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.common.defaults import ConfigOverride
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgithub.com/smartystreets/goconvey v1.7.2\n"
+        "\tgithub.com/stretchr/testify v1.8.4\n"
+        ")\n"
+    )
+    (root / "app.go").write_text('package app\n\nimport "github.com/pkg/errors"\n')
+    (root / "app_test.go").write_text(
+        'package app\n\nimport (\n\t"testing"\n\n\t"github.com/pkg/errors"\n\t"github.com/stretchr/testify/assert"\n)\n'
+    )
+    (root / "convey_test.go").write_text(
+        'package app_test\n\nimport . "github.com/smartystreets/goconvey/convey"\n'
+    )
+
+
+@pytest.mark.unit
+def test_imports_only_from_test_files_get_test_scope(tmp_path):
+    _make_repo(tmp_path)
+
+    analyzer = DependencyAnalyzer()
+    packages = analyzer.discover_packages(str(tmp_path), ["go"])
+    results = analyzer.analyze_dependencies(packages)
+
+    external = results["external_packages"]
+    assert external["github.com/stretchr/testify"]["scope"] == "test"
+    assert external["github.com/smartystreets/goconvey"]["scope"] == "test"
+    # Imported by both app.go and app_test.go
+    assert external["github.com/pkg/errors"]["scope"] == "production"
+
+
+@pytest.mark.unit
+def test_imports_from_many_files_are_coalesced_into_seen_in(tmp_path):
+    _make_repo(tmp_path)
+    (tmp_path / "errors_linux.go").write_text(
+        '//go:build linux\n\npackage app\n\nimport (\n\t"github.com/pkg/errors"\n\t"github.com/pkg/errors"\n)\n'
+    )
+
+    analyzer = DependencyAnalyzer()
+    packages = analyzer.discover_packages(str(tmp_path), ["go"])
+    results = analyzer.analyze_dependencies(packages)
+
+    errors = results["external_packages"]["github.com/pkg/errors"]
+    assert errors["seen_in"] == [
+        {"file": "app.go", "imports": ["github.com/pkg/errors"], "scope": "production"},
+        {"file": "app_test.go", "imports": ["github.com/pkg/errors"], "scope": "test"},
+        {
+            "file": "errors_linux.go",
+            "imports": ["github.com/pkg/errors"],
+            "scope": "production",
+            "build_tags": ["linux"],
+            "build_constraint": "linux",
+        },
+    ]
+    assert errors["usage_count"] == 3
+    testify = results["external_packages"]["github.com/stretchr/testify"]
+    assert testify["seen_in"] == [
+        {
+            "file": "app_test.go",
+            "imports": ["github.com/stretchr/testify/assert"],
+            "scope": "test",
+            "category": "test-framework",
+        }
+    ]
+    assert testify["usage_count"] == 1
+    assert "usage_count" not in results["external_packages"]["github.com/pkg/errors"]["seen_in"][0]
+
+
+@pytest.mark.unit
+def test_usage_count_can_count_import_paths_instead_of_files(tmp_path):
+    _make_repo(tmp_path)
+    (tmp_path / "require_test.go").write_text(
+        'package app\n\nimport (\n\t"github.com/stretchr/testify/assert"\n\t"github.com/stretchr/testify/require"\n)\n'
+    )
+
+    analyzer = DependencyAnalyzer()
+    with ConfigOverride({"USAGE_COUNT_BASIS": "imports"}):
+        packages = analyzer.discover_packages(str(tmp_path), ["go"])
+        results = analyzer.analyze_dependencies(packages)
+
+    testify = results["external_packages"]["github.com/stretchr/testify"]
+    assert [entry["file"] for entry in testify["seen_in"]] == ["app_test.go", "require_test.go"]
+    assert testify["usage_count"] == 3
+
+
+@pytest.mark.unit
+def test_test_frameworks_imported_by_production_files_stay_production(tmp_path):
+    _make_repo(tmp_path)
+    # A production file dot-importing goconvey, as in the Go fixture
+    (tmp_path / "main.go").write_text('package app\n\nimport . "github.com/smartystreets/goconvey/convey"\n')
+
+    analyzer = DependencyAnalyzer()
+    packages = analyzer.discover_packages(str(tmp_path), ["go"])
+    results = analyzer.analyze_dependencies(packages)
+
+    external = results["external_packages"]
+    convey = external["github.com/smartystreets/goconvey"]
+    assert convey["scope"] == "production"
+    assert "category" not in convey
+    assert [(entry["file"], entry["scope"], entry.get("category")) for entry in convey["seen_in"]] == [
+        ("convey_test.go", "test", "test-framework"),
+        ("main.go", "production", None),
+    ]
+    assert external["github.com/stretchr/testify"]["category"] == "test-framework"
+    assert "category" not in external["github.com/pkg/errors"]
+    assert results["summary"]["scopes"]["test"] == 1
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("configured", [False, True])
+def test_teams_can_list_their_own_test_helpers(tmp_path, configured):
+    _make_repo(tmp_path)
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\nrequire (\n\tgithub.com/pkg/errors v0.9.1\n\tcorp.example.com/testkit v1.0.0\n)\n"
+    )
+    (tmp_path / "fixtures_test.go").write_text('package app\n\nimport "corp.example.com/testkit/fakes"\n')
+    frameworks_file = tmp_path / ("frameworks.txt" if configured else ".gardener-test-frameworks")
+    frameworks_file.write_text("# Internal test helpers\ncorp.example.com/testkit\n")
+
+    analyzer = DependencyAnalyzer()
+    with ConfigOverride({"TEST_FRAMEWORKS_FILE": str(frameworks_file) if configured else ""}):
+        packages = analyzer.discover_packages(str(tmp_path), ["go"])
+        results = analyzer.analyze_dependencies(packages)
+
+    testkit = results["external_packages"]["corp.example.com/testkit"]
+    assert (testkit["scope"], testkit["category"]) == ("test", "test-framework")
+    assert "category" not in results["external_packages"]["github.com/pkg/errors"]
@@ -0,0 +1,73 @@
+"""
+Tool scope for Go dependencies pinned in tools.go files
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.treewalk.go import is_go_tools_constraint
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/golang/mock v1.6.0\n"
+        "\tgithub.com/golangci/golangci-lint v1.55.2\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgolang.org/x/tools v0.16.0\n"
+        ")\n"
+    )
+    (root / "app.go").write_text('package app\n\nimport "github.com/pkg/errors"\n')
+    (root / "tools.go").write_text(
+        "//go:build tools\n\npackage app\n\nimport (\n"
+        '\t_ "github.com/golangci/golangci-lint/cmd/golangci-lint"\n'
+        '\t_ "golang.org/x/tools/cmd/stringer"\n'
+        ")\n"
+    )
+    (root / "legacy_tools.go").write_text(
+        '// +build tools\n\npackage app\n\nimport _ "github.com/golang/mock/mockgen"\n'
+    )
+    # Also imported by production code, so it stays a production dependency
+    (root / "gen.go").write_text('package app\n\nimport "golang.org/x/tools/go/packages"\n')
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "expression, expected",
+    [
+        ("tools", True),
+        ("tools && !windows", True),
+        ("(tools && linux) || (tools && darwin)", True),
+        ("tools || linux", False),
+        ("!tools", False),
+        ("integration", False),
+        ("", False),
+    ],
+)
+def test_tools_constraints_require_the_tools_tag(expression, expected):
+    assert is_go_tools_constraint(expression) is expected
+
+
+@pytest.mark.unit
+def test_blank_imports_in_tools_files_get_tool_scope(tmp_path):
+    _make_repo(tmp_path)
+
+    analyzer = DependencyAnalyzer()
+    packages = analyzer.discover_packages(str(tmp_path), ["go"])
+    results = analyzer.analyze_dependencies(packages)
+
+    external = results["external_packages"]
+    assert external["github.com/golangci/golangci-lint"]["scope"] == "tool"
+    assert external["github.com/golang/mock"]["scope"] == "tool"
+    assert external["github.com/pkg/errors"]["scope"] == "production"
+    assert external["golang.org/x/tools"]["scope"] == "production"
+    assert {entry["file"]: entry["scope"] for entry in external["golang.org/x/tools"]["seen_in"]} == {
+        "gen.go": "production",
+        "tools.go": "tool",
+    }
+
+    evidence = results["analyzer_details"]["file_import_evidence"]["tools.go"]
+    assert {entry["scope"] for entry in evidence} == {"tool"}
+    assert evidence[0]["build_constraint"] == "tools"
+    assert results["summary"]["scopes"]["tool"] == 2
+    assert results["summary"]["scopes"]["production"] == 2
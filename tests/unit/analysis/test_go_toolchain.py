@@ -0,0 +1,53 @@
+"""
+Go version and toolchain requirements reported from the root go.mod
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+
+
+def _analyze(root):
+    analyzer = DependencyAnalyzer()
+    packages = analyzer.discover_packages(str(root), ["go", "javascript"])
+    return analyzer.analyze_dependencies(packages)
+
+
+@pytest.mark.unit
+def test_root_go_mod_version_and_toolchain_are_reported(tmp_path):
+    (tmp_path / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\ntoolchain go1.22.3\n")
+    (tmp_path / "tools").mkdir()
+    (tmp_path / "tools" / "go.mod").write_text("module example.com/app/tools\n\ngo 1.23\n")
+    (tmp_path / "main.go").write_text('package main\n\nimport "fmt"\n')
+
+    assert _analyze(tmp_path)["go_toolchain"] == {"go_version": "1.21", "toolchain": "go1.22.3"}
+
+
+@pytest.mark.unit
+def test_go_toolchain_is_omitted_without_a_root_go_mod(tmp_path):
+    (tmp_path / "package.json").write_text('{"name": "web"}')
+    (tmp_path / "tools").mkdir()
+    (tmp_path / "tools" / "go.mod").write_text("module example.com/tools\n\ngo 1.21\n")
+
+    assert "go_toolchain" not in _analyze(tmp_path)
+
+
+@pytest.mark.unit
+def test_deprecated_stdlib_imports_are_flagged_for_the_declared_go_version(tmp_path):
+    (tmp_path / "go.mod").write_text("module example.com/app\n\ngo 1.18\n")
+    (tmp_path / "main.go").write_text('package main\n\nimport (\n\t"io/ioutil"\n\t"os"\n)\n')
+    (tmp_path / "cmd").mkdir()
+    (tmp_path / "cmd" / "tool.go").write_text('package cmd\n\nimport "io/ioutil"\n')
+
+    assert _analyze(tmp_path)["go_stdlib_deprecations"] == [
+        {
+            "import": "io/ioutil",
+            "deprecated_since": "1.16",
+            "suggested_replacement": "io, os",
+            "files": ["cmd/tool.go", "main.go"],
+        }
+    ]
+
+    # Before Go 1.16 there is nothing to move to yet
+    (tmp_path / "go.mod").write_text("module example.com/app\n\ngo 1.15\n")
+    assert "go_stdlib_deprecations" not in _analyze(tmp_path)
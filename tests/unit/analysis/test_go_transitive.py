@@ -0,0 +1,133 @@
+"""
+Transitive Go module resolution from the go.mod require graph
+"""
+
+import pytest
+
+from gardener.analysis.go_modules import resolve_go_transitive
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.common.defaults import ConfigOverride
+
+GO_MODS = {
+    ("github.com/gin-gonic/gin", "v1.9.1"): (
+        "module github.com/gin-gonic/gin\n\nrequire (\n"
+        "\tgithub.com/go-playground/validator/v10 v10.14.0\n"
+        "\tgolang.org/x/net v0.10.0\n"
+        ")\n"
+    ),
+    ("github.com/go-playground/validator/v10", "v10.14.0"): (
+        "module github.com/go-playground/validator/v10\n\nrequire golang.org/x/net v0.12.0\n"
+    ),
+    ("golang.org/x/net", "v0.12.0"): "module golang.org/x/net\n\nrequire golang.org/x/text v0.11.0\n",
+    ("golang.org/x/net", "v0.10.0"): "module golang.org/x/net\n\nrequire golang.org/x/text v0.9.0\n",
+    ("golang.org/x/text", "v0.11.0"): "module golang.org/x/text\n",
+    ("golang.org/x/text", "v0.9.0"): "module golang.org/x/text\n",
+}
+
+
+class _RecordingFetcher:
+    def __init__(self, go_mods):
+        self.go_mods = go_mods
+        self.calls = []
+
+    def __call__(self, module_path, version):
+        self.calls.append((module_path, version))
+        content = self.go_mods.get((module_path, version))
+        return (content, None) if content else (None, "proxy-not-found")
+
+
+@pytest.mark.unit
+def test_transitive_walk_selects_highest_version_and_records_requirers():
+    fetcher = _RecordingFetcher(GO_MODS)
+
+    modules = resolve_go_transitive({"github.com/gin-gonic/gin": "v1.9.1"}, 4, fetch_mod=fetcher)
+
+    assert modules["golang.org/x/net"]["version"] == "v0.12.0"
+    assert modules["golang.org/x/net"]["depth"] == 2
+    assert modules["golang.org/x/net"]["required_by"] == [
+        "github.com/gin-gonic/gin",
+        "github.com/go-playground/validator/v10",
+    ]
+    assert modules["golang.org/x/text"]["version"] == "v0.11.0"
+    assert len(fetcher.calls) == len(set(fetcher.calls))
+
+
+@pytest.mark.unit
+def test_transitive_walk_is_bounded_by_depth():
+    fetcher = _RecordingFetcher(GO_MODS)
+
+    modules = resolve_go_transitive({"github.com/gin-gonic/gin": "v1.9.1"}, 2, fetch_mod=fetcher)
+
+    assert set(modules) == {"github.com/gin-gonic/gin", "github.com/go-playground/validator/v10", "golang.org/x/net"}
+    # Only the root requires' go.mod files are needed to report depth 2
+    assert fetcher.calls == [("github.com/gin-gonic/gin", "v1.9.1")]
+
+
+@pytest.mark.unit
+def test_transitive_walk_honors_root_replaces():
+    fetcher = _RecordingFetcher({("github.com/fork/gin", "v1.9.2"): "module github.com/fork/gin\n"})
+    replacements = {
+        "github.com/gin-gonic/gin": {"path": "github.com/fork/gin", "version": "v1.9.2", "local": False},
+        "example.com/local": {"path": "./local", "version": "", "local": True},
+    }
+
+    modules = resolve_go_transitive(
+        {"github.com/gin-gonic/gin": "v1.9.1", "example.com/local": "v0.0.0"}, 3, replacements, fetch_mod=fetcher
+    )
+
+    assert fetcher.calls == [("github.com/fork/gin", "v1.9.2")]
+    assert "fetch_reason" not in modules["github.com/gin-gonic/gin"]
+
+
+@pytest.mark.unit
+def test_analyzer_marks_direct_and_transitive_modules(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/gin-gonic/gin v1.9.1\n"
+        "\tgolang.org/x/net v0.12.0 // indirect\n"
+        "\tgithub.com/stale/dep v1.0.0 // indirect\n"
+        ")\n"
+    )
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/gin-gonic/gin"\n')
+    proxy = "https://proxy.golang.org"
+    responses = {
+        f"{proxy}/{module_path}/@v/{version}.mod": content for (module_path, version), content in GO_MODS.items()
+    }
+    responses[f"{proxy}/github.com/stale/dep/@v/v1.0.0.mod"] = "module github.com/stale/dep\n"
+
+    analyzer = DependencyAnalyzer()
+    with offline_mode.set_responses(responses), ConfigOverride({"MAX_TRANSITIVE_DEPTH": 3}):
+        results = analyzer.analyze(str(tmp_path), ["go"])
+
+    packages = results["external_packages"]
+    assert packages["github.com/gin-gonic/gin"]["direct"] is True
+    assert packages["golang.org/x/net"]["direct"] is False
+    assert packages["golang.org/x/net"]["required_by"] == [
+        "github.com/gin-gonic/gin",
+        "github.com/go-playground/validator/v10",
+    ]
+    validator = packages["github.com/go-playground/validator/v10"]
+    assert validator["direct"] is False
+    assert validator["depth"] == 2
+    assert packages["golang.org/x/text"]["depth"] == 2
+    # Flagged indirect, yet nothing in the resolved graph requires it
+    assert "required_by" not in packages["github.com/stale/dep"]
+
+
+@pytest.mark.unit
+def test_transitive_resolution_is_off_by_default(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\n"
+        "require github.com/gin-gonic/gin v1.9.1\n"
+        "require golang.org/x/net v0.12.0 // indirect\n"
+    )
+
+    with offline_mode.set_responses({}):
+        results = DependencyAnalyzer().analyze(str(tmp_path), ["go"])
+
+    packages = results["external_packages"]
+    assert set(packages) == {"github.com/gin-gonic/gin", "golang.org/x/net"}
+    # go.mod comments alone decide `direct` when the require graph is not followed
+    assert packages["github.com/gin-gonic/gin"]["direct"] is True
+    assert packages["golang.org/x/net"]["direct"] is False
+    assert "depth" not in packages["github.com/gin-gonic/gin"]
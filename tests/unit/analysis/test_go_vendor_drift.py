@@ -0,0 +1,104 @@
+"""
+vendor/modules.txt versions checked against go.mod and go.sum
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.output_schema import validate_document
+from gardener.common.defaults import ConfigOverride
+
+GO_MOD = """\
+module example.com/app
+
+go 1.21
+
+require (
+\tgithub.com/acme/a v1.0.0
+\tgithub.com/acme/b v1.1.0
+\tgithub.com/acme/c v0.2.0 // indirect
+)
+
+replace github.com/acme/c => github.com/fork/c v0.3.0
+"""
+
+GO_SUM = """\
+github.com/acme/a v1.0.0 h1:aaaa=
+github.com/acme/a v1.0.0/go.mod h1:aaab=
+github.com/acme/a v1.0.1/go.mod h1:aaac=
+github.com/acme/b v1.1.0 h1:bbbb=
+github.com/fork/c v0.3.0 h1:cccc=
+"""
+
+CLEAN = """\
+# github.com/acme/a v1.0.0
+## explicit; go 1.20
+github.com/acme/a
+# github.com/acme/b v1.1.0
+## explicit
+github.com/acme/b/pkg
+# github.com/acme/c v0.2.0 => github.com/fork/c v0.3.0
+## explicit
+github.com/acme/c
+# github.com/acme/c => github.com/fork/c v0.3.0
+"""
+
+DRIFTED = """\
+# github.com/acme/a v1.0.1
+## explicit; go 1.20
+github.com/acme/a
+# github.com/acme/c v0.2.0 => github.com/fork/c v0.2.9
+## explicit
+github.com/acme/c
+# github.com/acme/d v2.0.0
+## explicit
+github.com/acme/d
+"""
+
+
+def _analyze(root, modules_txt):
+    (root / "vendor").mkdir(parents=True)
+    (root / "go.mod").write_text(GO_MOD)
+    (root / "go.sum").write_text(GO_SUM)
+    (root / "vendor" / "modules.txt").write_text(modules_txt)
+    (root / "main.go").write_text('package main\n\nimport "github.com/acme/a"\n')
+    return DependencyAnalyzer().analyze(str(root), ["go"])
+
+
+@pytest.mark.unit
+def test_clean_vendor_directory_has_no_drift(tmp_path, offline_mode):
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = _analyze(tmp_path / "app", CLEAN)
+
+    assert [w for w in results.get("warnings", []) if w["warning"] == "vendor-drift"] == []
+
+
+@pytest.mark.unit
+def test_drifted_vendor_directory_names_each_module_and_version(tmp_path, offline_mode):
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = _analyze(tmp_path / "app", DRIFTED)
+
+    drift = [w for w in results["warnings"] if w["warning"] == "vendor-drift"]
+    assert [(w["module"], w["drift"]) for w in drift] == [
+        ("github.com/acme/a", "version"),
+        ("github.com/acme/a", "go-sum"),
+        ("github.com/acme/b", "not-vendored"),
+        ("github.com/acme/c", "replace"),
+        ("github.com/acme/c", "go-sum"),
+        ("github.com/acme/d", "not-required"),
+        ("github.com/acme/d", "go-sum"),
+    ]
+    assert {key: drift[0][key] for key in ("modules_txt", "go_mod", "go_mod_version", "vendored_version")} == {
+        "modules_txt": "vendor/modules.txt",
+        "go_mod": "go.mod",
+        "go_mod_version": "v1.0.0",
+        "vendored_version": "v1.0.1",
+    }
+    assert drift[0]["message"] == "github.com/acme/a: go.mod requires v1.0.0 but v1.0.1 is vendored"
+    # Only a /go.mod hash exists for v1.0.1, so the vendored code is unverified
+    assert drift[1]["go_sum_versions"] == ["v1.0.0"]
+    assert drift[3]["go_mod_replace"] == {"path": "github.com/fork/c", "version": "v0.3.0", "local": False}
+    assert drift[3]["vendored_replace"]["version"] == "v0.2.9"
+    assert validate_document(json.loads(json.dumps(results, default=str))) == []
@@ -0,0 +1,41 @@
+"""
+Go vendor/ directory handling during repository scans
+"""
+
+import pytest
+
+from gardener.analysis.scanner import scan_repository
+from gardener.common.defaults import ConfigOverride
+from gardener.common.secure_file_ops import SecureFileOps
+from gardener.treewalk.go import GoLanguageHandler
+
+
+def _make_vendored_repo(root):
+    (root / "go.mod").write_text("module example.com/app\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    vendored = root / "vendor" / "github.com" / "pkg" / "errors"
+    vendored.mkdir(parents=True)
+    (vendored / "errors.go").write_text('package errors\n\nimport "fmt"\n')
+    (root / "vendor" / "modules.txt").write_text("# github.com/pkg/errors v0.9.1\n## explicit\ngithub.com/pkg/errors\n")
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("secure", [False, True])
+def test_vendored_go_sources_are_skipped_by_default(tmp_path, secure):
+    _make_vendored_repo(tmp_path)
+    secure_file_ops = SecureFileOps(str(tmp_path)) if secure else None
+
+    result = scan_repository(str(tmp_path), secure_file_ops, ["go"], {"go": GoLanguageHandler()}, None)
+
+    assert set(result["source_files"]) == {"main.go"}
+    assert any(path.endswith("vendor/modules.txt") for path in result["manifest_files"])
+
+
+@pytest.mark.unit
+def test_scan_vendor_override_includes_vendored_sources(tmp_path):
+    _make_vendored_repo(tmp_path)
+
+    with ConfigOverride({"SCAN_VENDOR": True}):
+        result = scan_repository(str(tmp_path), None, ["go"], {"go": GoLanguageHandler()}, None)
+
+    assert "vendor/github.com/pkg/errors/errors.go" in result["source_files"]
@@ -0,0 +1,105 @@
+"""
+Publish time and age of pinned Go module versions
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.go_version_age import attach_go_version_times, version_age_days
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.resolution_cache import ResolutionCache
+
+PROXY = "https://proxy.golang.org"
+# 2025-01-01T00:00:00Z
+NOW = 1_735_689_600
+
+
+def _info(module_path, version, published_at):
+    escaped = "".join(f"!{char.lower()}" if char.isupper() else char for char in module_path)
+    return {f"{PROXY}/{escaped}/@v/{version}.info": json.dumps({"Version": version, "Time": published_at})}
+
+
+def _packages():
+    return {
+        "github.com/pkg/errors": {"ecosystem": "go", "version": "v0.9.1"},
+        "github.com/BurntSushi/toml": {"ecosystem": "go", "version": "v1.3.2"},
+        "github.com/foo/bar": {
+            "ecosystem": "go",
+            "version": "v1.0.0",
+            "replace": {"path": "github.com/fork/bar", "version": "v1.2.1"},
+        },
+        "github.com/foo/local": {
+            "ecosystem": "go",
+            "version": "v1.0.0",
+            "replace": {"path": "../local", "local": True},
+        },
+        "github.com/gone/away": {"ecosystem": "go", "version": "v0.1.0"},
+        "lodash": {"ecosystem": "npm", "version": "4.17.21"},
+    }
+
+
+@pytest.fixture(autouse=True)
+def _default_goproxy(monkeypatch):
+    monkeypatch.delenv("GOPROXY", raising=False)
+    monkeypatch.delenv("GOPRIVATE", raising=False)
+    monkeypatch.delenv("GONOPROXY", raising=False)
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "published_at, expected",
+    [
+        ("2021-03-01T10:00:00Z", 1401),
+        ("2024-12-31T00:30:00.5+01:00", 1),
+        ("2025-06-01T00:00:00Z", 0),
+        ("yesterday", None),
+    ],
+)
+def test_age_is_whole_days_since_publication(published_at, expected):
+    assert version_age_days(published_at, NOW) == expected
+
+
+@pytest.mark.unit
+def test_pinned_versions_get_their_publish_time_and_age(offline_mode):
+    responses = {
+        **_info("github.com/pkg/errors", "v0.9.1", "2020-01-14T19:47:44Z"),
+        **_info("github.com/BurntSushi/toml", "v1.3.2", "2023-06-08T06:11:08Z"),
+        **_info("github.com/fork/bar", "v1.2.1", "2024-12-25T00:00:00Z"),
+    }
+    packages = _packages()
+
+    with offline_mode.set_responses(responses):
+        assert attach_go_version_times(packages, now=NOW) == 3
+
+    assert packages["github.com/pkg/errors"]["version_published_at"] == "2020-01-14T19:47:44Z"
+    assert packages["github.com/pkg/errors"]["version_age_days"] == 1813
+    assert packages["github.com/BurntSushi/toml"]["version_age_days"] == 572
+    # A replaced module is dated by the replacement version it builds with
+    assert packages["github.com/foo/bar"]["version_age_days"] == 7
+    assert packages["github.com/gone/away"]["resolution"] == {"version_time_reason": "proxy-not-found"}
+    assert "version_published_at" not in packages["github.com/foo/local"]
+    assert "resolution" not in packages["github.com/foo/local"]
+    assert "version_published_at" not in packages["lodash"]
+
+
+@pytest.mark.unit
+def test_cached_times_are_reused_even_offline(tmp_path, monkeypatch):
+    requested = []
+
+    def _proxy(url):
+        requested.append(url)
+        return _info("github.com/pkg/errors", "v0.9.1", "2020-01-14T19:47:44Z").get(url)
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _proxy)
+    cache = ResolutionCache(str(tmp_path), 0)
+    attach_go_version_times({"github.com/pkg/errors": {"ecosystem": "go", "version": "v0.9.1"}}, resolution_cache=cache)
+    cache.save()
+    assert requested == [f"{PROXY}/github.com/pkg/errors/@v/v0.9.1.info"]
+
+    packages = _packages()
+    # The TTL only applies to repository URLs: a published version's time never changes
+    attach_go_version_times(packages, offline=True, resolution_cache=ResolutionCache(str(tmp_path), 0), now=NOW)
+
+    assert packages["github.com/pkg/errors"]["version_age_days"] == 1813
+    assert packages["github.com/BurntSushi/toml"]["resolution"] == {"version_time_reason": "offline-skipped"}
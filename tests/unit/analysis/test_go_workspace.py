@@ -0,0 +1,68 @@
+"""
+Go workspace (go.work) handling across multiple modules
+"""
+
+import pytest
+
+from gardener.analysis.tree import RepositoryAnalyzer
+from gardener.treewalk.go import GoLanguageHandler, parse_go_work
+
+
+def _make_workspace_repo(root):
+    (root / "go.work").write_text(
+        "go 1.22\n\nuse (\n\t./moduleA\n\t./moduleB\n)\n\n"
+        "replace github.com/foo/bar v1.2.0 => github.com/fork/bar v1.2.1\n"
+    )
+    module_a = root / "moduleA"
+    module_a.mkdir()
+    (module_a / "go.mod").write_text(
+        "module example.com/moduleA\n\ngo 1.22\n\n"
+        "require (\n\texample.com/moduleB v0.0.0\n\tgithub.com/foo/bar v1.2.0\n)\n"
+    )
+    (module_a / "main.go").write_text(
+        'package main\n\nimport (\n\t"example.com/moduleB/pkg"\n\t"github.com/foo/bar"\n)\n'
+    )
+    pkg = root / "moduleB" / "pkg"
+    pkg.mkdir(parents=True)
+    (root / "moduleB" / "go.mod").write_text("module example.com/moduleB\n\ngo 1.22\n")
+    (pkg / "pkg.go").write_text("package pkg\n")
+
+
+def test_parse_go_work_reads_use_and_replace_directives():
+    content = (
+        "go 1.22\n\nuse ./tools\nuse (\n\t./moduleA // main service\n\t\"./moduleB\"\n)\n\n"
+        "replace example.com/old => ../old\n"
+    )
+
+    parsed = parse_go_work(content)
+
+    assert parsed["go"] == "1.22"
+    assert parsed["use"] == ["./tools", "./moduleA", "./moduleB"]
+    assert parsed["replace"]["example.com/old"] == {"path": "../old", "version": "", "local": True}
+
+
+@pytest.mark.unit
+def test_workspace_modules_are_local_and_work_replaces_apply(tmp_path):
+    _make_workspace_repo(tmp_path)
+
+    analyzer = RepositoryAnalyzer(str(tmp_path))
+    analyzer.register_language_handler("go", GoLanguageHandler())
+    analyzer.scan_repo()
+    packages = analyzer.process_manifest_files()
+
+    assert analyzer.go_workspace_modules == {"example.com/moduleA": "moduleA", "example.com/moduleB": "moduleB"}
+    assert "example.com/moduleB" not in packages
+    assert packages["github.com/foo/bar"]["replace"] == {
+        "path": "github.com/fork/bar",
+        "version": "v1.2.1",
+        "local": False,
+    }
+
+    analyzer.extract_imports_from_all_files()
+
+    assert analyzer.file_imports["moduleA/main.go"] == ["github.com/foo/bar"]
+    assert analyzer.local_imports_map["moduleA/main.go"] == ["moduleB/pkg/pkg.go"]
+    scopes = {entry["import"]: entry for entry in analyzer.file_import_evidence["moduleA/main.go"]}
+    assert scopes["example.com/moduleB/pkg"]["scope"] == "local"
+    assert scopes["example.com/moduleB/pkg"]["module"] == "example.com/moduleB"
+    assert scopes["github.com/foo/bar"]["replaced_to"] == "github.com/fork/bar@v1.2.1"
@@ -0,0 +1,130 @@
+"""
+Package-level import graph built from import evidence
+"""
+
+import pytest
+
+from gardener.analysis.import_graph import build_import_graph, find_import_cycles
+
+
+def _evidence(import_path, scope, import_kind="named", module=""):
+    return {"import": import_path, "import_kind": import_kind, "scope": scope, "module": module, "version": ""}
+
+
+@pytest.mark.unit
+def test_edges_run_from_importing_package_to_dependency():
+    evidence = {
+        "main.go": [
+            _evidence("github.com/gin-gonic/gin", "external", module="github.com/gin-gonic/gin"),
+            _evidence("github.com/lib/pq", "external", "blank", module="github.com/lib/pq"),
+            _evidence("./utils", "local"),
+        ],
+        "cmd.go": [_evidence("github.com/gin-gonic/gin", "external", module="github.com/gin-gonic/gin")],
+        "api/client.go": [_evidence("net/http", "stdlib")],
+    }
+
+    graph = build_import_graph(evidence, "example.com/app")
+
+    assert graph["nodes"] == [
+        {"id": "example.com/app", "kind": "first_party", "path": "."},
+        {"id": "example.com/app/api", "kind": "first_party", "path": "api"},
+        {"id": "example.com/app/utils", "kind": "first_party"},
+        {"id": "github.com/gin-gonic/gin", "kind": "external"},
+        {"id": "github.com/lib/pq", "kind": "external"},
+        {"id": "net/http", "kind": "stdlib"},
+    ]
+    edges = {(edge["source"], edge["target"]): edge for edge in graph["edges"]}
+    assert set(edges) == {
+        ("example.com/app", "github.com/gin-gonic/gin"),
+        ("example.com/app", "github.com/lib/pq"),
+        ("example.com/app", "example.com/app/utils"),
+        ("example.com/app/api", "net/http"),
+    }
+    assert edges[("example.com/app", "github.com/gin-gonic/gin")]["files"] == ["cmd.go", "main.go"]
+    assert edges[("example.com/app", "github.com/lib/pq")]["import_kind"] == "blank"
+
+
+@pytest.mark.unit
+def test_subpackage_imports_collapse_onto_their_module():
+    evidence = {
+        "svc/handler.go": [
+            _evidence("github.com/aws/aws-sdk-go/aws/session", "external", module="github.com/aws/aws-sdk-go"),
+            _evidence("github.com/aws/aws-sdk-go/service/s3", "external", "dot", module="github.com/aws/aws-sdk-go"),
+        ]
+    }
+
+    graph = build_import_graph(evidence)
+
+    assert [node["id"] for node in graph["nodes"]] == ["github.com/aws/aws-sdk-go", "svc"]
+    assert [(edge["import"], edge["import_kind"]) for edge in graph["edges"]] == [
+        ("github.com/aws/aws-sdk-go/aws/session", "named"),
+        ("github.com/aws/aws-sdk-go/service/s3", "dot"),
+    ]
+
+
+@pytest.mark.unit
+def test_workspace_member_files_are_named_by_their_module():
+    evidence = {
+        "tools/lint/main.go": [_evidence("example.com/app/pkg", "local")],
+        "pkg/pkg.go": [],
+        "main.go": [_evidence("../outside", "local")],
+    }
+
+    graph = build_import_graph(evidence, "example.com/app", {"example.com/tools": "tools"})
+
+    nodes = {node["id"]: node for node in graph["nodes"]}
+    assert nodes["example.com/tools/lint"]["path"] == "tools/lint"
+    assert nodes["example.com/app/pkg"]["path"] == "pkg"
+    # Relative imports that leave the repository keep their directory-style id
+    assert ("example.com/app", "../outside") in {(edge["source"], edge["target"]) for edge in graph["edges"]}
+
+
+def _graph(edges, externals=()):
+    nodes = {source for source, _ in edges} | {target for _, target in edges}
+    return {
+        "nodes": [{"id": node, "kind": "external" if node in externals else "first_party"} for node in sorted(nodes)],
+        "edges": [{"source": source, "target": target} for source, target in edges],
+    }
+
+
+@pytest.mark.unit
+def test_cycles_are_reported_once_from_their_smallest_package():
+    graph = _graph(
+        [
+            ("app/store", "app/model"),
+            ("app/model", "app/api"),
+            ("app/api", "app/store"),
+            ("app/api", "app/model"),
+            ("app/cmd", "app/api"),
+        ]
+    )
+
+    assert find_import_cycles(graph) == [
+        ["app/api", "app/model"],
+        ["app/api", "app/store", "app/model"],
+    ]
+
+
+@pytest.mark.unit
+def test_external_edges_do_not_close_cycles():
+    graph = _graph(
+        [("pkg/a", "github.com/x/y"), ("github.com/x/y", "pkg/b"), ("pkg/b", "pkg/a")],
+        externals={"github.com/x/y"},
+    )
+
+    assert find_import_cycles(graph) == []
+
+
+@pytest.mark.unit
+def test_cycles_are_found_across_build_tag_variants_of_one_package():
+    # Files behind different build tags can each import the other package, which the go command never sees
+    evidence = {
+        "a/a_linux.go": [_evidence("example.com/app/b", "local")],
+        "b/b.go": [_evidence("example.com/app/c", "local")],
+        "c/c_windows.go": [_evidence("example.com/app/a", "local"), _evidence("fmt", "stdlib")],
+    }
+
+    graph = build_import_graph(evidence, "example.com/app")
+
+    assert find_import_cycles(graph) == [["example.com/app/a", "example.com/app/b", "example.com/app/c"]]
+    assert find_import_cycles(graph, max_cycles=0) == []
@@ -0,0 +1,47 @@
+"""
+Per-language filtering of analysis
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer, _parse_focus_languages
+from gardener.common.language_detection import SUPPORTED_LANGUAGES, parse_language_filter
+from gardener.common.utils import Logger
+
+
+@pytest.mark.unit
+def test_language_filter_normalizes_aliases_and_case():
+    assert parse_language_filter("go, JS,ts,javascript") == ["go", "javascript", "typescript"]
+    assert parse_language_filter("tsx,svelte") == ["typescript", "svelte"]
+    # Package ecosystems, the keys of the grouped output, select their languages
+    assert parse_language_filter("npm,pypi,go") == ["javascript", "typescript", "python", "go"]
+    assert parse_language_filter("") is None
+    assert parse_language_filter(None) is None
+
+
+@pytest.mark.unit
+def test_unknown_languages_are_rejected_with_the_supported_set():
+    with pytest.raises(ValueError) as excinfo:
+        _parse_focus_languages("go,cobol,fortran", Logger())
+
+    message = str(excinfo.value)
+    assert "cobol, fortran" in message
+    assert all(language in message for language in SUPPORTED_LANGUAGES)
+
+
+@pytest.mark.unit
+def test_go_filter_skips_other_languages_sources_and_manifests(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text("module example.com/app\n\nrequire github.com/gin-gonic/gin v1.9.1\n")
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/gin-gonic/gin"\n')
+    (tmp_path / "package.json").write_text(json.dumps({"name": "web", "dependencies": {"lodash": "^4.17.21"}}))
+    (tmp_path / "index.js").write_text("const _ = require('lodash')\n")
+
+    with offline_mode.set_responses({}):
+        results = DependencyAnalyzer().analyze(str(tmp_path), parse_language_filter("go"))
+
+    assert set(results["external_packages"]) == {"github.com/gin-gonic/gin"}
+    details = results["analyzer_details"]
+    assert details["languages_detected"] == ["go"]
+    assert set(details["file_import_evidence"]) == {"main.go"}
@@ -0,0 +1,109 @@
+"""
+Go tools installed or run by Makefiles' `go install` and `go run` lines
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.output_schema import validate_document
+from gardener.common.defaults import ConfigOverride
+from gardener.treewalk.makefile import is_makefile, parse_makefile_go_tools
+
+MAKEFILE = """\
+GOLANGCI_VERSION ?= v1.55.2
+
+# go install github.com/commented/out@v1.0.0
+tools:
+\t@go install github.com/golangci/golangci-lint/cmd/golangci-lint@$(GOLANGCI_VERSION)
+\t$(GO) install -v \\
+\t\tmvdan.cc/gofumpt@latest
+\tgo install github.com/unset/tool@$(UNSET)
+
+generate:
+\tgo run golang.org/x/tools/cmd/stringer -type=Kind ./...
+\tgo run ./cmd/gen && go run main.go
+\tcargo install ripgrep
+"""
+
+
+@pytest.mark.unit
+def test_go_install_and_run_lines_are_extracted():
+    assert parse_makefile_go_tools(MAKEFILE) == [
+        {
+            "line": 5,
+            "command": "go install github.com/golangci/golangci-lint/cmd/golangci-lint@$(GOLANGCI_VERSION)",
+            "subcommand": "install",
+            "package": "github.com/golangci/golangci-lint/cmd/golangci-lint",
+            "version": "v1.55.2",
+        },
+        {
+            "line": 6,
+            "command": "$(GO) install -v mvdan.cc/gofumpt@latest",
+            "subcommand": "install",
+            "package": "mvdan.cc/gofumpt",
+            "version": "latest",
+        },
+        {
+            "line": 8,
+            "command": "go install github.com/unset/tool@$(UNSET)",
+            "subcommand": "install",
+            "package": "github.com/unset/tool",
+        },
+        {
+            "line": 11,
+            "command": "go run golang.org/x/tools/cmd/stringer -type=Kind ./...",
+            "subcommand": "run",
+            "package": "golang.org/x/tools/cmd/stringer",
+        },
+    ]
+    assert [path for path in ("Makefile", "build/tools.mk", "GNUmakefile", "Makefile.am") if is_makefile(path)] == [
+        "Makefile",
+        "build/tools.mk",
+        "GNUmakefile",
+    ]
+
+
+@pytest.mark.unit
+def test_makefile_tools_become_tool_scoped_go_modules(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgolang.org/x/tools v0.15.0\n"
+        ")\n"
+    )
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n\nvar _ = errors.New\n')
+    (tmp_path / "Makefile").write_text(MAKEFILE)
+
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path))
+
+    packages = results["external_packages"]
+    lint = packages["github.com/golangci/golangci-lint"]
+    assert {key: lint[key] for key in ("ecosystem", "scope", "version", "repository_url", "pin_kind")} == {
+        "ecosystem": "go",
+        "scope": "tool",
+        "version": "v1.55.2",
+        "repository_url": "https://github.com/golangci/golangci-lint",
+        "pin_kind": "pinned-release",
+    }
+    assert lint["make"] == [
+        {
+            "file": "Makefile",
+            "line": 5,
+            "command": "go install github.com/golangci/golangci-lint/cmd/golangci-lint@$(GOLANGCI_VERSION)",
+        }
+    ]
+    assert packages["mvdan.cc/gofumpt"]["pin_kind"] == "floating"
+    assert "version" not in packages["github.com/unset/tool"]
+    # A required module keeps its go.mod version and is scoped by its use
+    tools = packages["golang.org/x/tools"]
+    assert (tools["scope"], tools["version"], [entry["line"] for entry in tools["make"]]) == ("tool", "v0.15.0", [11])
+    assert packages["github.com/pkg/errors"]["scope"] == "production"
+    assert "make" not in packages["github.com/pkg/errors"]
+    assert validate_document(json.loads(json.dumps(results, default=str))) == []
+
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path), ["go"])
+    assert "github.com/golangci/golangci-lint" not in results["external_packages"]
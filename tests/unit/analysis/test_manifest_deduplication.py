@@ -9,6 +9,7 @@ import tempfile
 import pytest
 
 from gardener.analysis.tree import RepositoryAnalyzer
+from gardener.treewalk.go import GoLanguageHandler
 from gardener.treewalk.javascript import JavaScriptLanguageHandler
 
 
@@ -95,6 +96,21 @@ def test_deduplicate_package_no_conflict():
         assert len(packages["react"]["found_in_manifests"]) == 2
 
 
+def test_go_module_direct_in_any_go_mod_stays_direct(tmp_path):
+    """A module one go.mod marks `// indirect` is still direct when another requires it directly"""
+    (tmp_path / "go.mod").write_text("module example.com/app\n\nrequire github.com/pkg/errors v0.9.1 // indirect\n")
+    (tmp_path / "tools").mkdir()
+    (tmp_path / "tools" / "go.mod").write_text("module example.com/app/tools\n\nrequire github.com/pkg/errors v0.9.1\n")
+
+    analyzer = RepositoryAnalyzer(str(tmp_path))
+    analyzer.register_language_handler("go", GoLanguageHandler())
+    analyzer.scan_repo()
+    packages = analyzer.process_manifest_files()
+
+    assert len(packages["github.com/pkg/errors"]["found_in_manifests"]) == 2
+    assert packages["github.com/pkg/errors"]["direct"] is True
+
+
 def test_version_conflict_resolution_strategies():
     """Test various version conflict resolution strategies"""
     analyzer = RepositoryAnalyzer("/tmp")  # Dummy path
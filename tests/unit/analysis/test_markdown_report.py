@@ -0,0 +1,118 @@
+"""
+Markdown dependency report of analysis results
+"""
+
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.main import save_markdown_report
+from gardener.analysis.markdown_report import escape_markdown, markdown_code, markdown_link, render_markdown_report
+from gardener.common.utils import Logger
+from gardener.persistence.file import FilePersistence
+
+EXTERNAL_PACKAGES = {
+    "github.com/stretchr/testify": {
+        "ecosystem": "go",
+        "version": "v1.8.4",
+        "repository_url": "",
+        "scope": "test",
+        "resolution": {"reason": "offline-skipped"},
+    },
+    "lodash": {
+        "ecosystem": "npm",
+        "version": "^4.17.21",
+        "repository_url": "https://github.com/lodash/lodash",
+        "license": "MIT",
+    },
+    "github.com/gin-gonic/gin": {
+        "ecosystem": "go",
+        "version": "v1.9.1",
+        "repository_url": "https://github.com/gin-gonic/gin",
+        "scope": "production",
+    },
+    "odd|name`_x": {"ecosystem": "npm", "version": "1.0.0", "license": "MIT OR Apache-2.0 | <b>"},
+}
+
+RESULTS = {"external_packages": EXTERNAL_PACKAGES, "summary": {"files_analyzed": 3}}
+
+
+@pytest.mark.unit
+def test_report_has_a_summary_a_table_per_ecosystem_and_the_unresolved_packages():
+    report = render_markdown_report(RESULTS, "widgets_app")
+
+    assert report.splitlines()[:6] == [
+        "# Dependency report: widgets\\_app",
+        "",
+        "- Source files analyzed: 3",
+        "- External packages: 4 (2 resolved, 2 unresolved)",
+        "- Ecosystems: go 2, npm 2",
+        "",
+    ]
+    go_section = report[report.index("## go (2)") : report.index("## npm (2)")]
+    assert go_section.splitlines()[2:6] == [
+        "| Package | Version | Repository | Scope | License |",
+        "| --- | --- | --- | --- | --- |",
+        "| `github.com/gin-gonic/gin` | `v1.9.1` | <https://github.com/gin-gonic/gin> | production |  |",
+        "| `github.com/stretchr/testify` | `v1.8.4` |  | test |  |",
+    ]
+    assert report.endswith(
+        "## Unresolved repository URLs (2)\n\n"
+        "| Package | Ecosystem | Reason |\n"
+        "| --- | --- | --- |\n"
+        "| `github.com/stretchr/testify` | go | offline-skipped |\n"
+        "| ``odd\\|name`_x`` | npm | unresolved |\n"
+    )
+
+
+@pytest.mark.unit
+def test_cells_cannot_break_the_table_or_inject_markup():
+    assert escape_markdown("a|b_c *d* <b>\nnext") == "a\\|b\\_c \\*d\\* \\<b\\> next"
+    assert markdown_code("a``b") == "```a``b```"
+    assert markdown_code("`edge`") == "`` `edge` ``"
+    assert markdown_code("") == ""
+    assert markdown_link("https://github.com/a/b") == "<https://github.com/a/b>"
+    assert markdown_link("javascript:alert(1)") == "javascript:alert\\(1\\)"
+    # Rows keep their column count whatever the package fields hold
+    rows = [line for line in render_markdown_report(RESULTS, "x").splitlines() if "odd" in line]
+    assert [line.replace("\\|", "").count("|") for line in rows] == [6, 4]
+
+
+@pytest.mark.unit
+def test_report_is_the_same_whatever_order_the_packages_come_in():
+    reordered = {name: EXTERNAL_PACKAGES[name] for name in reversed(list(EXTERNAL_PACKAGES))}
+
+    assert render_markdown_report({"external_packages": reordered}, "x") == render_markdown_report(
+        {"external_packages": EXTERNAL_PACKAGES}, "x"
+    )
+    assert "Every repository URL was resolved." in render_markdown_report({"external_packages": {}}, "x")
+
+
+@pytest.mark.unit
+def test_report_is_saved_under_the_output_prefix(tmp_path):
+    persistence = FilePersistence(output_dir=str(tmp_path), verbose=False)
+
+    assert save_markdown_report(RESULTS, "widgets", "demo", persistence, Logger())
+
+    assert (tmp_path / "demo_dependency_report.md").read_text(encoding="utf-8") == render_markdown_report(
+        RESULTS, "widgets"
+    )
+
+
+@pytest.mark.unit
+def test_cli_writes_the_report_to_stdout(tmp_path, monkeypatch, capsys):
+    repo = tmp_path / "app"
+    repo.mkdir()
+    (repo / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (repo / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    monkeypatch.chdir(tmp_path)
+    argv = ["gardener", str(repo), "-o", "demo", "--offline", "--no-cache", "-m", "--format", "markdown", "--quiet"]
+    monkeypatch.setattr(sys, "argv", argv)
+
+    main_cli.main()
+
+    report = capsys.readouterr().out
+    assert report.startswith("# Dependency report: app\n")
+    assert "| `github.com/pkg/errors` | `v0.9.1` |" in report
+    assert (tmp_path / "output" / "demo_dependency_report.md").read_text(encoding="utf-8") == report
@@ -0,0 +1,62 @@
+"""
+Directory depth limit (--max-depth) during repository scans
+"""
+
+import pytest
+
+from gardener.analysis.scanner import scan_repository
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.common.defaults import ConfigOverride
+from gardener.common.secure_file_ops import SecureFileOps
+from gardener.treewalk.go import GoLanguageHandler
+
+
+def _make_nested_repo(root):
+    (root / "go.mod").write_text("module example.com/app\n")
+    (root / "main.go").write_text("package main\n")
+    (root / "README.md").write_text("# app\n")
+    deep = root / "internal" / "store" / "sql"
+    deep.mkdir(parents=True)
+    (root / "internal" / "internal.go").write_text("package internal\n")
+    (root / "internal" / "store" / "store.go").write_text("package store\n")
+    (deep / "sql.go").write_text("package sql\n")
+    (deep / "schema.sql").write_text("-- not a source file\n")
+    (root / "tools").mkdir()
+    (root / "tools" / "go.mod").write_text("module example.com/app/tools\n")
+    (root / "generated").mkdir()
+    (root / "generated" / "gen.go").write_text("package generated\n")
+    (root / ".gitignore").write_text("generated/\n")
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("secure", [False, True])
+@pytest.mark.parametrize(
+    "depth, scanned, skipped",
+    [
+        (0, {"main.go"}, 4),
+        (1, {"main.go", "internal/internal.go"}, 2),
+        (2, {"main.go", "internal/internal.go", "internal/store/store.go"}, 1),
+        (-1, {"main.go", "internal/internal.go", "internal/store/store.go", "internal/store/sql/sql.go"}, 0),
+    ],
+)
+def test_directories_beyond_the_depth_limit_are_counted_not_scanned(tmp_path, secure, depth, scanned, skipped):
+    _make_nested_repo(tmp_path)
+    secure_file_ops = SecureFileOps(str(tmp_path)) if secure else None
+
+    with ConfigOverride({"MAX_SCAN_DEPTH": depth}):
+        result = scan_repository(str(tmp_path), secure_file_ops, ["go"], {"go": GoLanguageHandler()}, None)
+
+    assert set(result["source_files"]) == scanned
+    assert result["skipped_by_depth"] == skipped
+    assert any(path.endswith("tools/go.mod") for path in result["manifest_files"]) == (depth != 0)
+
+
+@pytest.mark.unit
+def test_skipped_files_are_reported_in_the_summary(tmp_path, offline_mode):
+    _make_nested_repo(tmp_path)
+
+    with offline_mode.set_responses({}), ConfigOverride({"MAX_SCAN_DEPTH": 1}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True))
+
+    assert result.raw["summary"]["files_analyzed"] == 2
+    assert result.raw["summary"]["skipped_by_depth"] == 2
@@ -0,0 +1,136 @@
+"""
+Combined results of several repository roots
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.multi_root import combine_root_results, default_output_prefix, root_names
+
+
+def _root_results(version, files, extra=None):
+    errors_package = {
+        "ecosystem": "go",
+        "version": version,
+        "repository_url": "https://github.com/pkg/errors",
+        "scope": "test" if files[0].endswith("_test.go") else "production",
+        "usage_count": len(files),
+        "seen_in": [{"file": path, "imports": ["github.com/pkg/errors"]} for path in files],
+    }
+    results = {
+        "external_packages": {"github.com/pkg/errors": errors_package, **(extra or {})},
+        "dependency_graph": {},
+        "import_graph": {
+            "nodes": [
+                {"id": "github.com/pkg/errors", "kind": "external"},
+                {"id": "tools", "kind": "first_party", "path": "tools"},
+            ],
+            "edges": [
+                {
+                    "source": "tools",
+                    "target": "github.com/pkg/errors",
+                    "import": "github.com/pkg/errors",
+                    "import_kind": "named",
+                    "files": files,
+                }
+            ],
+        },
+        "analyzer_details": {
+            "file_imports": {path: ["github.com/pkg/errors"] for path in files},
+            "total_files": len(files),
+            "skipped_by_depth": 0,
+            "languages_detected": ["go"],
+        },
+        "go_toolchain": {"go_version": "1.21"},
+    }
+    results["summary"] = {"files_analyzed": len(files)}
+    return results
+
+
+@pytest.mark.unit
+def test_shared_packages_are_kept_once_with_their_roots():
+    api = _root_results("v0.9.1", ["tools/main.go"])
+    web = _root_results(
+        "v0.9.2",
+        ["tools/errors_test.go"],
+        extra={"left-pad": {"ecosystem": "npm", "version": "1.3.0", "seen_in": [{"file": "index.js"}]}},
+    )
+    web["errors"] = [{"file": "broken.go", "error": "parse-failed", "detail": "syntax"}]
+    web["tool"] = {"target": "https://github.com/acme/web", "commit": "abc", "options": {"format": "json"}}
+
+    combined = combine_root_results([("api", api), ("web", web)])
+
+    errors_package = combined["external_packages"]["github.com/pkg/errors"]
+    assert errors_package["roots"] == ["api", "web"]
+    assert errors_package["root_versions"] == {"api": "v0.9.1", "web": "v0.9.2"}
+    # The first root's record leads; the strongest scope any root gives the package wins
+    assert (errors_package["version"], errors_package["scope"], errors_package["usage_count"]) == (
+        "v0.9.1",
+        "production",
+        2,
+    )
+    assert errors_package["seen_in"] == [
+        {"file": "api/tools/main.go", "imports": ["github.com/pkg/errors"], "root": "api"},
+        {"file": "web/tools/errors_test.go", "imports": ["github.com/pkg/errors"], "root": "web"},
+    ]
+    assert combined["external_packages"]["left-pad"]["roots"] == ["web"]
+    assert combined["reverse_index"]["left-pad"] == ["web/index.js"]
+    assert combined["errors"] == [{"file": "web/broken.go", "error": "parse-failed", "detail": "syntax"}]
+    # Directory-named first-party packages of different roots stay apart
+    assert [node["id"] for node in combined["import_graph"]["nodes"]] == [
+        "api/tools",
+        "github.com/pkg/errors",
+        "web/tools",
+    ]
+
+    assert combined["summary"]["files_analyzed"] == 2
+    assert combined["summary"]["external_packages"] == 2
+    assert combined["summary"]["roots"] == {"api": {"files_analyzed": 1}, "web": {"files_analyzed": 1}}
+    assert combined["roots"] == {
+        "api": {"target": "api", "go_toolchain": {"go_version": "1.21"}},
+        "web": {"target": "https://github.com/acme/web", "commit": "abc", "go_toolchain": {"go_version": "1.21"}},
+    }
+    assert combined["tool"] == {
+        "target": "https://github.com/acme/web",
+        "options": {"format": "json"},
+        "targets": ["https://github.com/acme/web"],
+    }
+
+
+@pytest.mark.unit
+def test_roots_are_named_uniquely():
+    assert root_names(["app", "lib", "app", "app"]) == ["app", "lib", "app-2", "app-3"]
+    assert default_output_prefix(["a", "b"]) == "a-b"
+    assert default_output_prefix(["a", "b", "c", "d", "e"]) == "a-b-c-and-2-more"
+
+
+@pytest.mark.unit
+def test_cli_combines_several_roots_into_one_report(tmp_path, monkeypatch):
+    for name, version in (("api", "v0.9.1"), ("worker", "v0.9.2")):
+        repo = tmp_path / name
+        repo.mkdir()
+        go_mod = f"module example.com/{name}\n\ngo 1.21\n\nrequire github.com/pkg/errors {version}\n"
+        (repo / "go.mod").write_text(go_mod)
+        (repo / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    monkeypatch.chdir(tmp_path)
+    argv = ["gardener", str(tmp_path / "api"), str(tmp_path / "worker"), "--offline", "--no-cache", "--flat", "-m"]
+    monkeypatch.setattr(sys, "argv", argv + ["--validate-output"])
+
+    main_cli.main()
+
+    results = json.loads((tmp_path / "output" / "api-worker_dependency_analysis.json").read_text())
+    assert results["external_packages"]["github.com/pkg/errors"]["root_versions"] == {
+        "api": "v0.9.1",
+        "worker": "v0.9.2",
+    }
+    assert sorted(results["summary"]["roots"]) == ["api", "worker"]
+    assert results["summary"]["files_analyzed"] == 2
+    assert results["reverse_index"]["github.com/pkg/errors"] == ["api/main.go", "worker/main.go"]
+
+    monkeypatch.setattr(sys, "argv", argv + ["--since", "HEAD~1"])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    assert excinfo.value.code == 2
@@ -0,0 +1,99 @@
+"""
+NDJSON streaming output
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.imports import LocalImportResolver, extract_imports
+from gardener.analysis.main import run_analysis
+from gardener.analysis.ndjson_export import NDJSON_SUFFIX
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.persistence.file import FilePersistence
+from gardener.treewalk.go import GoLanguageHandler
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/gin-gonic/gin v1.9.1\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        ")\n"
+    )
+    (root / "main.go").write_text('package main\n\nimport (\n\t"fmt"\n\n\t"github.com/gin-gonic/gin"\n)\n')
+    (root / "util").mkdir()
+    (root / "util" / "errors.go").write_text('package util\n\nimport "github.com/pkg/errors"\n')
+    (root / "util" / "broken.go").write_text('package util\n\nimport (\n\t"strings"\n')
+
+
+@pytest.mark.unit
+def test_ndjson_stream_has_file_package_and_summary_records(tmp_path, offline_mode):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    _make_repo(repo)
+    persistence = FilePersistence(output_dir=str(tmp_path / "out"), verbose=False)
+
+    with offline_mode.set_responses({}):
+        run_analysis(str(repo), "demo", persistence=persistence, output_format="ndjson", focus_languages_str="go")
+
+    with open(tmp_path / "out" / f"demo{NDJSON_SUFFIX}", encoding="utf-8") as handle:
+        lines = handle.read().splitlines()
+    records = [json.loads(line) for line in lines]
+    types = [record["type"] for record in records]
+    assert types == ["file_evidence"] * 3 + ["package"] * 2 + ["summary"]
+
+    files = {record["file"]: record for record in records if record["type"] == "file_evidence"}
+    assert files["main.go"]["imports"] == ["fmt", "github.com/gin-gonic/gin"]
+    assert files["main.go"]["language"] == "go"
+    # Standard-library evidence follows --include-stdlib, like the JSON output
+    assert [entry["import"] for entry in files["main.go"]["evidence"]] == ["github.com/gin-gonic/gin"]
+
+    packages = [record for record in records if record["type"] == "package"]
+    assert [record["name"] for record in packages] == ["github.com/gin-gonic/gin", "github.com/pkg/errors"]
+    assert packages[0]["repository_url"] == "https://github.com/gin-gonic/gin"
+    assert packages[0]["resolution_status"] == "resolved"
+
+    summary = records[-1]
+    assert summary["total_files"] == 3
+    assert summary["files_with_imports"] == 3
+    assert summary["packages"] == 2
+    assert summary["resolved_packages"] == 2
+    assert [error["file"] for error in summary["errors"]] == ["util/broken.go"]
+    # The complete JSON results are still written alongside
+    assert (tmp_path / "out" / "demo_dependency_analysis.json").exists()
+
+
+@pytest.mark.unit
+def test_file_evidence_is_delivered_per_file_during_extraction(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+    records = []
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(on_file_evidence=records.append))
+
+    assert [record["file"] for record in records] == sorted(result.raw["analyzer_details"]["file_imports"])
+    assert all(record["type"] == "file_evidence" for record in records)
+
+
+@pytest.mark.unit
+def test_on_file_callback_runs_in_source_order_with_parallel_workers(tmp_path):
+    source_files = {}
+    for index in range(8):
+        path = tmp_path / f"f{index}.go"
+        path.write_text(f'package p\n\nimport "example.com/dep{index}"\n')
+        source_files[f"f{index}.go"] = {"absolute_path": str(path), "language": "go"}
+    resolver = LocalImportResolver(str(tmp_path), source_files, None, None, {}, None, {}, {}, None, None)
+    seen = []
+    extract_imports(
+        source_files,
+        {"go": GoLanguageHandler()},
+        str(tmp_path),
+        None,
+        resolver,
+        None,
+        jobs=4,
+        on_file=lambda rel_path, result: seen.append((rel_path, result["external"])),
+    )
+
+    assert seen == [(f"f{index}.go", [f"example.com/dep{index}"]) for index in range(8)]
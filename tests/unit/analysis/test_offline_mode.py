@@ -0,0 +1,88 @@
+"""
+--offline resolves from local signals only and never opens a connection
+"""
+
+import json
+import os
+import socket
+import sys
+import urllib.request
+
+import pytest
+import requests
+
+from gardener import main_cli
+from gardener.package_metadata import url_resolver
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/gin-gonic/gin v1.9.1\n"
+        "\tgolang.org/x/net v0.17.0\n"
+        "\tgopkg.in/yaml.v3 v3.0.1\n"
+        ")\n"
+    )
+    (root / "main.go").write_text(
+        'package main\n\nimport (\n\t"github.com/gin-gonic/gin"\n\t"golang.org/x/net/html"\n\t"gopkg.in/yaml.v3"\n)\n'
+    )
+    (root / "requirements.txt").write_text("flask==3.0.0\n")
+    (root / "package.json").write_text(json.dumps({"dependencies": {"lodash": "^4.17.21", "@types/node": "^20.0.0"}}))
+
+
+@pytest.fixture
+def network_attempts(monkeypatch):
+    """Fail and record every way gardener could reach the network"""
+    attempts = []
+
+    def _refuse(*args, **kwargs):
+        attempts.append(args[0] if args else kwargs)
+        raise OSError("network access attempted in offline mode")
+
+    def _refuse_connect(self, address):
+        _refuse(address)
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _refuse)
+    monkeypatch.setattr(urllib.request, "urlopen", _refuse)
+    monkeypatch.setattr(url_resolver, "_urlopen", _refuse)
+    monkeypatch.setattr(requests, "get", _refuse)
+    monkeypatch.setattr(socket.socket, "connect", _refuse_connect)
+    return attempts
+
+
+@pytest.mark.unit
+def test_offline_run_makes_no_network_calls(tmp_path, monkeypatch, network_attempts):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    _make_repo(repo)
+    monkeypatch.chdir(tmp_path)
+    argv = ["gardener", str(repo), "-o", "offline", "--offline", "--no-cache", "--max-transitive-depth", "2"]
+    argv.append("--flat")
+    monkeypatch.setattr(sys, "argv", argv)
+
+    main_cli.main()
+
+    assert network_attempts == []
+    with open(os.path.join("output", "offline_dependency_analysis.json")) as handle:
+        packages = json.load(handle)["external_packages"]
+
+    # Local signals still resolve
+    assert packages["github.com/gin-gonic/gin"]["repository_url"] == "https://github.com/gin-gonic/gin"
+    assert packages["gopkg.in/yaml.v3"]["repository_url"] == "https://github.com/go-yaml/yaml"
+    assert packages["@types/node"]["repository_url"] == "https://github.com/DefinitelyTyped/DefinitelyTyped"
+    # Everything else is reported as skipped rather than silently missing
+    for name in ("golang.org/x/net", "flask", "lodash"):
+        assert packages[name]["repository_url"] == ""
+        assert packages[name]["resolution"]["reason"] == "offline-skipped"
+    assert packages["github.com/gin-gonic/gin"]["go_mod_reason"] == "offline-skipped"
+
+
+@pytest.mark.unit
+def test_offline_refuses_to_clone_remote_repositories(tmp_path, monkeypatch, network_attempts):
+    monkeypatch.chdir(tmp_path)
+    monkeypatch.setattr(sys, "argv", ["gardener", "https://github.com/drips-network/gardener", "--offline"])
+
+    with pytest.raises(SystemExit):
+        main_cli.main()
+
+    assert network_attempts == []
@@ -0,0 +1,81 @@
+"""
+OSV ecosystem and package name of each dependency
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.osv_coordinates import osv_coordinates
+from gardener.analysis.pin_kinds import classify_pin
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "name, info, expected",
+    [
+        ("github.com/gin-gonic/gin", {"ecosystem": "go", "version": "v1.9.1"}, ("Go", "github.com/gin-gonic/gin")),
+        ("github.com/go-redis/redis/v8", {"ecosystem": "go"}, ("Go", "github.com/go-redis/redis/v8")),
+        # The replacement module is what gets built
+        (
+            "github.com/foo/bar",
+            {"ecosystem": "go", "replace": {"path": "github.com/fork/bar", "version": "v1.0.1"}},
+            ("Go", "github.com/fork/bar"),
+        ),
+        ("example.com/local", {"ecosystem": "go", "replace": {"path": "../local", "local": True}}, None),
+        ("@babel/core", {"ecosystem": "npm", "version": "^7.0.0"}, ("npm", "@babel/core")),
+        ("my-lib", {"ecosystem": "npm", "version": "file:../lib"}, None),
+        ("Django_Rest.Framework", {"ecosystem": "pypi"}, ("PyPI", "django-rest-framework")),
+        ("serde", {"ecosystem": "cargo", "version": "1.0.188"}, ("crates.io", "serde")),
+        (
+            "github/codeql-action/init",
+            {"ecosystem": "github-actions", "version": "v3"},
+            ("GitHub Actions", "github/codeql-action"),
+        ),
+        (
+            "pip:Flask_Login",
+            {"ecosystem": "docker", "installer": "pip", "package": "Flask_Login"},
+            ("PyPI", "flask-login"),
+        ),
+        ("gem:rails", {"ecosystem": "docker", "installer": "gem", "package": "rails"}, ("RubyGems", "rails")),
+        ("apt:curl", {"ecosystem": "docker", "installer": "apt", "package": "curl"}, None),
+        # `go install` names a package, not its module
+        (
+            "go:golang.org/x/tools/cmd/goimports",
+            {"ecosystem": "docker", "installer": "go", "package": "golang.org/x/tools/cmd/goimports"},
+            None,
+        ),
+        ("docker.io/library/golang", {"ecosystem": "docker", "image": "golang", "version": "1.22"}, None),
+        ("forge-std", {"ecosystem": "solidity"}, None),
+        ("lib/forge-std", {"ecosystem": "git-submodule"}, None),
+    ],
+)
+def test_coordinates_follow_each_ecosystem(name, info, expected):
+    info = dict(info, **classify_pin(info))
+    coordinates = osv_coordinates(name, info)
+
+    if expected is None:
+        assert coordinates == {}
+    else:
+        assert coordinates == {"osv_ecosystem": expected[0], "osv_name": expected[1]}
+
+
+@pytest.mark.unit
+def test_analyzed_packages_carry_their_coordinates(tmp_path):
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/gin-gonic/gin v1.9.1\n"
+        "\texample.com/shared v0.0.0\n"
+        ")\n\nreplace example.com/shared => ./shared\n"
+    )
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/gin-gonic/gin"\n')
+
+    analyzer = DependencyAnalyzer()
+    results = analyzer.analyze_dependencies(analyzer.discover_packages(str(tmp_path), ["go"]))
+
+    gin = results["external_packages"]["github.com/gin-gonic/gin"]
+    assert (gin["purl"], gin["osv_ecosystem"], gin["osv_name"]) == (
+        "pkg:golang/github.com/gin-gonic/gin@v1.9.1",
+        "Go",
+        "github.com/gin-gonic/gin",
+    )
+    assert "osv_ecosystem" not in results["external_packages"]["example.com/shared"]
@@ -0,0 +1,150 @@
+"""
+Published JSON Schema of the analysis JSON and --validate-output
+"""
+
+import json
+import os
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis import main as analysis_main
+from gardener.analysis.output_schema import SUPPORTED_KEYWORDS, load_output_schema, validate_document
+from gardener.analysis.provenance import TOOL_FIELDS
+from gardener.analysis.summary import ECOSYSTEM_FIELDS, MULTI_ROOT_SUMMARY_FIELDS, SCOPE_FIELDS, SUMMARY_FIELDS
+from gardener.analysis.timings import PHASES, TIMINGS_FIELDS
+from gardener.common.exit_codes import EXIT_INVALID_OUTPUT
+
+
+def _make_repo(root):
+    (root / "app" / "internal" / "db").mkdir(parents=True)
+    (root / "tool").mkdir()
+    (root / "go.work").write_text("go 1.21\n\nuse (\n\t./app\n\t./tool\n)\n")
+    (root / "app" / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "app" / "internal" / "db" / "db.go").write_text(
+        'package db\n\nimport "io/ioutil"\n\nvar _ = ioutil.Discard\n'
+    )
+    (root / "tool" / "go.mod").write_text(
+        "module example.com/tool\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.8.1\n"
+    )
+    (root / "tool" / "main.go").write_text(
+        'package main\n\nimport (\n\t"example.com/app/internal/db"\n\t"github.com/pkg/errors"\n)\n'
+    )
+
+
+def _keywords(schema):
+    if isinstance(schema, dict):
+        for key, value in schema.items():
+            if key in ("properties", "$defs"):
+                for child in value.values():
+                    yield from _keywords(child)
+            else:
+                yield key
+                yield from _keywords(value)
+    elif isinstance(schema, list):
+        for child in schema:
+            yield from _keywords(child)
+
+
+@pytest.mark.unit
+def test_schema_stays_in_sync_with_the_documented_fields():
+    schema = load_output_schema()
+    definitions = schema["$defs"]
+
+    assert set(_keywords(schema)) <= SUPPORTED_KEYWORDS
+    assert list(definitions["summary"]["properties"]) == list(SUMMARY_FIELDS) + list(MULTI_ROOT_SUMMARY_FIELDS)
+    assert definitions["summary"]["required"] == list(SUMMARY_FIELDS)
+    assert definitions["summary"]["properties"]["scopes"]["required"] == list(SCOPE_FIELDS)
+    assert definitions["summary_ecosystem"]["required"] == list(ECOSYSTEM_FIELDS)
+    assert definitions["timings"]["required"] == list(TIMINGS_FIELDS)
+    assert list(definitions["timings"]["properties"]["phases"]["properties"]) == list(PHASES)
+    assert list(definitions["tool"]["properties"]) == list(TOOL_FIELDS)
+
+
+@pytest.mark.unit
+def test_a_run_touching_every_optional_section_validates(tmp_path, monkeypatch, offline_mode):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    _make_repo(repo)
+    (tmp_path / "old.json").write_text(json.dumps({"external_packages": {"left-pad": {"ecosystem": "npm"}}}))
+    monkeypatch.chdir(tmp_path)
+    argv = ["gardener", str(repo), "-o", "demo", "--offline", "--no-cache", "-m", "--timings", "--validate-output"]
+    argv += ["--baseline", "old.json", "--files-from", "files.txt"]
+    (tmp_path / "files.txt").write_text("app/internal/db/db.go\ntool/main.go\n")
+    monkeypatch.setattr(sys, "argv", argv)
+
+    with offline_mode.set_responses({}):
+        main_cli.main()
+
+    with open(os.path.join("output", "demo_dependency_analysis.json")) as handle:
+        document = json.load(handle)
+    sections = ["warnings", "go_stdlib_deprecations", "go_modules", "version_conflicts", "analysis_scope"]
+    for section in sections + ["baseline_diff", "timings"]:
+        assert section in document
+    assert validate_document(document) == []
+    # With --flat and --only-unresolved the packages are laid out differently, still within the schema
+    monkeypatch.setattr(sys, "argv", argv + ["--flat", "--only-unresolved"])
+    with offline_mode.set_responses({}):
+        main_cli.main()
+    with open(os.path.join("output", "demo_dependency_analysis.json")) as handle:
+        assert validate_document(json.load(handle)) == []
+
+
+@pytest.mark.unit
+def test_renamed_and_mistyped_fields_are_reported():
+    document = {
+        "ecosystems": {"go": {"github.com/pkg/errors": {"id": "go:github.com/pkg/errors", "ecosystem": "go"}}},
+        "dependency_graph": {},
+        "import_graph": {"nodes": [], "edges": []},
+        "cycles": [],
+        "top_dependencies": [],
+        "analyzer_details": {
+            "local_imports_map": {},
+            "file_imports": {},
+            "file_package_components": {},
+            "file_import_evidence": {},
+            "total_files": "1",
+            "skipped_by_depth": 0,
+            "languages_detected": ["go"],
+        },
+        "summary": {name: 0 for name in SUMMARY_FIELDS if name not in ("scopes", "ecosystems")},
+        "partial": True,
+        "warning": [],
+    }
+    document["summary"].update(scopes={name: 0 for name in SCOPE_FIELDS}, ecosystems={})
+
+    problems = validate_document(document)
+
+    assert problems == [
+        "$.ecosystems.go.github.com/pkg/errors: missing required field 'purl'",
+        "$.ecosystems.go.github.com/pkg/errors: missing required field 'repository_url'",
+        "$.ecosystems.go.github.com/pkg/errors: missing required field 'repository'",
+        "$.analyzer_details.total_files: expected integer, got string",
+        "$: unexpected field 'warning'",
+    ]
+    document.pop("ecosystems")
+    assert "$: matches none of the allowed forms" in validate_document(document)
+
+
+@pytest.mark.unit
+def test_validate_output_fails_without_writing_a_nonconforming_document(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    _make_repo(repo)
+    monkeypatch.chdir(tmp_path)
+    build_summary = analysis_main.build_summary
+    monkeypatch.setattr(analysis_main, "build_summary", lambda results: dict(build_summary(results), files="all"))
+    argv = ["gardener", str(repo), "-o", "demo", "--offline", "--no-cache", "-m", "--validate-output"]
+    monkeypatch.setattr(sys, "argv", argv + ["--json-out", "results.json"])
+
+    with offline_mode.set_responses({}):
+        with pytest.raises(SystemExit) as excinfo:
+            main_cli.main()
+
+    assert excinfo.value.code == EXIT_INVALID_OUTPUT
+    record = json.loads(capsys.readouterr().err.strip().splitlines()[-1])
+    assert record["error_code"] == "invalid-output"
+    assert "$.summary: unexpected field 'files'" in record["message"]
+    assert not os.path.exists("results.json")
+    assert not os.path.exists(os.path.join("output", "demo_dependency_analysis.json"))
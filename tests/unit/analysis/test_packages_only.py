@@ -0,0 +1,98 @@
+"""
+Compact package list of --packages-only runs
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.package_list import package_list
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+
+GO_MOD = """\
+module example.com/app
+
+go 1.21
+
+require (
+\tgithub.com/pkg/errors v0.9.1
+\tgolang.org/x/tools v0.17.0
+)
+"""
+
+MAIN_GO = """\
+package main
+
+import "github.com/pkg/errors"
+
+//go:generate stringer -type=Color
+
+func main() { _ = errors.New("x") }
+"""
+
+PACKAGE_JSON = '{"name": "web", "dependencies": {"left-pad": "1.3.0", "local-lib": "file:../lib"}}'
+
+
+def _make_repo(root):
+    root.mkdir()
+    (root / "go.mod").write_text(GO_MOD)
+    (root / "main.go").write_text(MAIN_GO)
+    (root / "package.json").write_text(PACKAGE_JSON)
+    (root / "index.js").write_text("const pad = require('left-pad');\n")
+
+
+@pytest.mark.unit
+def test_package_list_is_deduplicated_and_sorted():
+    packages = {
+        "lodash": {"ecosystem": "npm", "version": "4.17.21", "repository_url": "https://github.com/lodash/lodash"},
+        "github.com/pkg/errors": {"ecosystem": "go", "version": "v0.9.1", "seen_in": [{"file": "main.go"}]},
+        "@local/pkg": {"ecosystem": "npm", "version": {"path": "../pkg"}},
+    }
+
+    assert package_list(packages) == [
+        {"ecosystem": "go", "name": "github.com/pkg/errors", "version": "v0.9.1"},
+        {"ecosystem": "npm", "name": "@local/pkg", "version": None},
+        {"ecosystem": "npm", "name": "lodash", "version": "4.17.21"},
+    ]
+
+
+@pytest.mark.unit
+def test_packages_only_skips_resolution_and_honors_the_language_filter(tmp_path, monkeypatch):
+    _make_repo(tmp_path / "app")
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", lambda url: requested.append(url))
+
+    with ConfigOverride({"SERIALIZE_PACKAGES_ONLY": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path / "app"), ["go"])
+
+    assert results["packages"] == [
+        {"ecosystem": "go", "name": "github.com/pkg/errors", "version": "v0.9.1"},
+        {"ecosystem": "go", "name": "golang.org/x/tools", "version": "v0.17.0"},
+    ]
+    assert results["analyzer_details"]["total_files"] == 1
+    assert not {"external_packages", "dependency_graph", "import_graph", "top_dependencies"} & set(results)
+    assert requested == []
+
+
+@pytest.mark.unit
+def test_cli_writes_the_package_list(tmp_path, monkeypatch, capsys):
+    _make_repo(tmp_path / "app")
+    monkeypatch.chdir(tmp_path)
+    argv = ["gardener", str(tmp_path / "app"), "-o", "demo", "--offline", "--no-cache", "--packages-only", "--quiet"]
+    monkeypatch.setattr(sys, "argv", argv)
+
+    main_cli.main()
+
+    listed = json.loads(capsys.readouterr().out)
+    assert {"ecosystem": "npm", "name": "left-pad", "version": "1.3.0"} in listed
+    assert json.loads((tmp_path / "output" / "demo_packages.json").read_text()) == listed
+    assert not (tmp_path / "output" / "demo_dependency_analysis.json").exists()
+
+    monkeypatch.setattr(sys, "argv", argv + ["--format", "csv", "--only-unresolved"])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    assert excinfo.value.code == 2
@@ -0,0 +1,46 @@
+"""
+Parallel per-file import extraction produces the same output as serial extraction
+"""
+
+import json
+import os
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.imports import _dedupe
+
+FIXTURE_DIR = os.path.abspath("tests/fixtures/go")
+
+
+def _run_cli(monkeypatch, jobs):
+    prefix = f"jobs_{jobs}"
+    argv = ["gardener", FIXTURE_DIR, "-o", prefix, "--jobs", str(jobs), "-l", "go", "--no-cache"]
+    monkeypatch.setattr(sys, "argv", argv)
+    main_cli.main()
+    with open(os.path.join("output", f"{prefix}_dependency_analysis.json"), "rb") as handle:
+        document = json.loads(handle.read())
+    # The tool section records the run's --jobs
+    assert document.pop("tool")["config"]["ResourceLimits"]["ANALYSIS_JOBS"] == jobs
+    return json.dumps(document, indent=2).encode()
+
+
+@pytest.mark.slow
+def test_jobs_1_and_jobs_8_write_identical_json(tmp_path, monkeypatch, offline_mode):
+    monkeypatch.chdir(tmp_path)
+
+    with offline_mode.set_responses({}):
+        serial = _run_cli(monkeypatch, 1)
+        parallel = _run_cli(monkeypatch, 8)
+
+    assert serial == parallel
+    assert b"github.com/gin-gonic/gin" in serial
+
+
+@pytest.mark.unit
+def test_dedupe_keeps_first_occurrence_order():
+    first = {"import": "fmt", "build_tags": ["linux"]}
+    second = {"import": "os", "build_tags": []}
+
+    assert _dedupe([first, second, dict(first)]) == [first, second]
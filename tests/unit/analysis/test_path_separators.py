@@ -0,0 +1,82 @@
+"""
+Backslash-separated paths in local import resolution and reported file paths
+"""
+
+import pytest
+
+from gardener.analysis.imports import LocalImportResolver, extract_imports
+from gardener.analysis.manifests import process_manifests
+from gardener.treewalk.go import GoLanguageHandler
+from gardener.treewalk.javascript import JavaScriptLanguageHandler
+
+
+def _resolver(repo_path, source_files, go_module_path=None):
+    return LocalImportResolver(str(repo_path), source_files, None, None, {}, go_module_path, {}, {}, None, None)
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "language, importing_file, import_path, expected",
+    [
+        ("js", "src\\app.js", "./utils", "src/utils.js"),
+        ("js", "src\\app.js", "./lib", "src/lib/index.js"),
+        ("js", "src/lib\\index.js", "../utils", "src/utils.js"),
+        ("js", "src\\app.js", "lodash", None),
+        ("python", "pkg\\main.py", "helpers", "pkg/helpers.py"),
+        ("go", "cmd\\app\\main.go", "../../internal/db", "internal/db/db.go"),
+        ("go", "cmd\\app\\main.go", "example.com/app/internal/db", "internal/db/db.go"),
+    ],
+)
+def test_backslash_paths_resolve_to_posix_source_files(tmp_path, language, importing_file, import_path, expected):
+    source_files = {
+        "src\\app.js": {},
+        "src\\utils.js": {},
+        "src\\lib\\index.js": {},
+        "pkg\\main.py": {},
+        "pkg\\helpers.py": {},
+        "cmd\\app\\main.go": {},
+        "internal\\db\\db.go": {},
+    }
+    resolver = _resolver(tmp_path, source_files, go_module_path="example.com/app")
+
+    if language == "js":
+        resolved = resolver.resolve_js(importing_file, import_path)
+    elif language == "python":
+        resolved = resolver.resolve_python(importing_file, import_path, 1)
+    else:
+        resolved = resolver.resolve_go(importing_file, import_path)
+
+    assert resolved == expected
+    assert not any("\\" in rel_path for rel_path in source_files)
+
+
+@pytest.mark.unit
+def test_extracted_imports_are_keyed_by_posix_paths(tmp_path):
+    (tmp_path / "cmd").mkdir()
+    (tmp_path / "internal" / "db").mkdir(parents=True)
+    (tmp_path / "cmd" / "main.go").write_text(
+        'package main\n\nimport (\n\t"github.com/pkg/errors"\n\t"../internal/db"\n)\n'
+    )
+    (tmp_path / "internal" / "db" / "db.go").write_text("package db\n")
+    source_files = {
+        "cmd\\main.go": {"absolute_path": str(tmp_path / "cmd" / "main.go"), "language": "go"},
+        "internal\\db\\db.go": {"absolute_path": str(tmp_path / "internal" / "db" / "db.go"), "language": "go"},
+    }
+
+    file_imports, local_imports_map, _, file_import_evidence, _ = extract_imports(
+        source_files, {"go": GoLanguageHandler()}, str(tmp_path), None, _resolver(tmp_path, dict(source_files)), None
+    )
+
+    assert dict(file_imports) == {"cmd/main.go": ["github.com/pkg/errors"]}
+    assert dict(local_imports_map) == {"cmd/main.go": ["internal/db/db.go"]}
+    assert list(file_import_evidence) == ["cmd/main.go"]
+
+
+@pytest.mark.unit
+def test_manifests_read_by_several_handlers_are_listed_once(tmp_path):
+    (tmp_path / "package.json").write_text('{"dependencies": {"lodash": "4.17.21"}}')
+    handlers = {"javascript": JavaScriptLanguageHandler(), "typescript": JavaScriptLanguageHandler()}
+
+    packages = process_manifests([str(tmp_path / "package.json")], handlers, None, None)
+
+    assert packages["lodash"]["found_in_manifests"] == [(tmp_path / "package.json").as_posix()]
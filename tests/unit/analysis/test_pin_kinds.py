@@ -0,0 +1,97 @@
+"""
+Pinned, floating and local dependency references
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.output_schema import validate_document
+from gardener.analysis.pin_kinds import classify_pin, parse_go_pseudo_version
+from gardener.common.defaults import ConfigOverride
+
+SHA = "0123456789abcdef0123456789abcdef01234567"
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "package_info, expected",
+    [
+        ({"ecosystem": "go", "version": "v1.2.3"}, "pinned-release"),
+        ({"ecosystem": "go", "version": "v2.0.0+incompatible"}, "pinned-release"),
+        ({"ecosystem": "go", "version": "v1.0.0", "replace": {"path": "../x", "version": "", "local": True}}, "local"),
+        ({"ecosystem": "go", "version": "", "bazel_rule": "com_x", "commit": SHA}, "pinned-commit"),
+        ({"ecosystem": "go-generate", "version": "latest"}, "floating"),
+        ({"ecosystem": "npm", "version": "4.17.21"}, "pinned-release"),
+        ({"ecosystem": "npm", "version": "^4.17.0"}, "floating"),
+        ({"ecosystem": "npm", "version": "latest"}, "floating"),
+        ({"ecosystem": "npm", "version": "npm:@scope/pkg@1.0.0"}, "pinned-release"),
+        ({"ecosystem": "npm", "version": f"github:acme/lib#{SHA}"}, "pinned-commit"),
+        ({"ecosystem": "npm", "version": "git+https://github.com/acme/lib.git#main"}, "floating"),
+        ({"ecosystem": "npm", "version": "workspace:*"}, "local"),
+        ({"ecosystem": "npm", "version": "file:../shared"}, "local"),
+        ({"ecosystem": "docker", "tag": "1.22.3-alpine", "version": "1.22.3-alpine"}, "pinned-release"),
+        ({"ecosystem": "docker", "tag": "3.12", "version": "3.12"}, "floating"),
+        ({"ecosystem": "docker", "tag": "latest", "version": "latest"}, "floating"),
+        ({"ecosystem": "docker", "digest": "sha256:abc", "version": "sha256:abc"}, "pinned-commit"),
+        ({"ecosystem": "docker", "installer": "pip", "package": "requests", "version": None}, "floating"),
+        ({"ecosystem": "github-actions", "ref": SHA, "version": SHA}, "pinned-commit"),
+        ({"ecosystem": "github-actions", "ref": "v4.1.0", "version": "v4.1.0"}, "pinned-release"),
+        ({"ecosystem": "github-actions", "ref": "v4", "version": "v4"}, "floating"),
+        ({"ecosystem": "git-submodule", "ref": "main"}, "pinned-commit"),
+    ],
+)
+def test_references_are_classified_by_how_firmly_they_pin(package_info, expected):
+    assert classify_pin(package_info)["pin_kind"] == expected
+
+
+@pytest.mark.unit
+def test_unrecorded_references_are_not_classified():
+    assert classify_pin({"ecosystem": "pypi"}) == {}
+    assert classify_pin({"ecosystem": "cargo", "version": None}) == {}
+    assert classify_pin({"ecosystem": "npm", "version": "bundled"}) == {}
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "version, expected",
+    [
+        ("v0.0.0-20230101000000-abcdef123456", {"commit": "abcdef123456", "time": "2023-01-01T00:00:00Z"}),
+        ("v1.4.1-0.20210315083000-0123456789ab", {"commit": "0123456789ab", "time": "2021-03-15T08:30:00Z"}),
+        (
+            "v2.0.0-rc.1.0.20220102030405-abcdefabcdef+incompatible",
+            {"commit": "abcdefabcdef", "time": "2022-01-02T03:04:05Z"},
+        ),
+        ("v1.2.3", None),
+        ("v0.0.0-20231301000000-abcdef123456", None),
+        ("v1.2.3-20230101000000-abcdef123456", None),
+    ],
+)
+def test_go_pseudo_versions_give_their_commit_and_time(version, expected):
+    assert parse_go_pseudo_version(version) == expected
+
+
+@pytest.mark.unit
+def test_go_requires_carry_their_pin_kind(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgolang.org/x/sys v0.0.0-20230101000000-abcdef123456\n"
+        "\texample.com/shared v1.0.0\n"
+        ")\n\nreplace example.com/shared => ../shared\n"
+    )
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n\nvar _ = errors.New\n')
+
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = DependencyAnalyzer().analyze(str(tmp_path), ["go"])
+
+    packages = results["external_packages"]
+    assert packages["github.com/pkg/errors"]["pin_kind"] == "pinned-release"
+    assert {key: packages["golang.org/x/sys"][key] for key in ("pin_kind", "pinned_commit", "pinned_commit_time")} == {
+        "pin_kind": "pinned-commit",
+        "pinned_commit": "abcdef123456",
+        "pinned_commit_time": "2023-01-01T00:00:00Z",
+    }
+    assert packages["example.com/shared"]["pin_kind"] == "local"
+    assert validate_document(json.loads(json.dumps(results, default=str))) == []
@@ -0,0 +1,149 @@
+"""
+Programmatic API: analyze_file, analyze_repo and resolve_repository_url
+"""
+
+import json
+
+import pytest
+
+import gardener
+from gardener.api import (
+    AnalysisOptions,
+    FileEvidence,
+    RepoResult,
+    ResolutionResult,
+    analyze_file,
+    analyze_repo,
+    resolve_repository_url,
+)
+from gardener.package_metadata import url_resolver
+
+
+def _make_module(root):
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire golang.org/x/net v0.17.0\n")
+    (root / "cmd").mkdir()
+    (root / "cmd" / "main.go").write_text(
+        'package main\n\nimport (\n\t"fmt"\n\n\t"golang.org/x/net/html"\n\n\t"../util"\n)\n'
+    )
+    (root / "util").mkdir()
+    (root / "util" / "util.go").write_text("package util\n")
+
+
+@pytest.mark.unit
+def test_analyze_file_reads_go_imports_in_their_module_context(tmp_path):
+    _make_module(tmp_path)
+
+    evidence = analyze_file(str(tmp_path / "cmd" / "main.go"))
+
+    assert isinstance(evidence, FileEvidence)
+    assert evidence.path == "cmd/main.go"
+    assert evidence.language == "go"
+    assert evidence.external_imports == ["fmt", "golang.org/x/net/html"]
+    assert evidence.local_imports == ["util/util.go"]
+    by_import = {entry["import"]: entry for entry in evidence.import_evidence}
+    assert by_import["golang.org/x/net/html"]["version"] == "v0.17.0"
+    assert by_import["../util"]["resolved_import"] == "example.com/app/util"
+    assert evidence.errors == []
+    assert evidence.generated is False
+
+
+@pytest.mark.unit
+def test_analyze_file_rejects_unknown_languages(tmp_path):
+    (tmp_path / "notes.txt").write_text("hello\n")
+
+    with pytest.raises(ValueError):
+        analyze_file(str(tmp_path / "notes.txt"))
+    with pytest.raises(FileNotFoundError):
+        analyze_file(str(tmp_path / "missing.go"))
+
+
+@pytest.mark.unit
+def test_analyze_repo_returns_typed_results_through_a_custom_http_client(tmp_path):
+    _make_module(tmp_path)
+    requested = []
+
+    def http_client(url):
+        requested.append(url)
+        if url.startswith("https://golang.org/x/net"):
+            return '<meta name="go-import" content="golang.org/x/net git https://go.googlesource.com/net">'
+        return None
+
+    previous = url_resolver.get_request_fn()
+    result = analyze_repo(str(tmp_path), AnalysisOptions(languages=["golang"], http_client=http_client))
+
+    assert isinstance(result, RepoResult)
+    assert url_resolver.get_request_fn() is previous
+    assert requested
+    net = result.external_packages["golang.org/x/net"]
+    assert net["repository_url"] == "https://go.googlesource.com/net"
+    assert result.go_toolchain == {"go_version": "1.21"}
+    assert result.errors == []
+    # The raw results are exactly what the CLI serializes
+    assert json.loads(json.dumps(result.raw))["external_packages"]["golang.org/x/net"]["version"] == "v0.17.0"
+
+
+@pytest.mark.unit
+def test_api_is_reexported_from_the_package_root():
+    assert gardener.analyze_file is analyze_file
+    assert gardener.analyze_repo is analyze_repo
+    assert gardener.resolve_repository_url is resolve_repository_url
+
+
+@pytest.mark.unit
+def test_monorepo_module_packages_carry_their_repository_subpath(tmp_path):
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/kubernetes/kubernetes/staging/src/k8s.io/api v0.0.0-20240101000000-abcdef123456\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        ")\n"
+    )
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+
+    result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True))
+
+    api = result.external_packages["github.com/kubernetes/kubernetes/staging/src/k8s.io/api"]
+    assert api["repository_url"] == "https://github.com/kubernetes/kubernetes"
+    assert api["repository_subpath"] == "staging/src/k8s.io/api"
+    assert "repository_subpath" not in api["resolution"]
+    assert "repository_subpath" not in result.external_packages["github.com/pkg/errors"]
+
+
+@pytest.mark.unit
+def test_resolve_repository_url_returns_the_url_and_its_receipt(tmp_path):
+    npm_meta = {
+        "dist-tags": {"latest": "1.3.0"},
+        "versions": {"1.3.0": {"repository": {"url": "git+https://github.com/stevemao/left-pad.git"}}},
+    }
+    requested = []
+
+    def http_client(url):
+        requested.append(url)
+        return json.dumps(npm_meta) if url == "https://registry.npmjs.org/left-pad" else None
+
+    options = AnalysisOptions(http_client=http_client, config_overrides={"CACHE_DIR": str(tmp_path / "cache")})
+    first = resolve_repository_url("npm", "left-pad", "1.3.0", options)
+    second = resolve_repository_url("npm", "left-pad", "1.3.0", options)
+
+    assert isinstance(first, ResolutionResult)
+    assert (first.ecosystem, first.name, first.version) == ("npm", "left-pad", "1.3.0")
+    assert first.repository_url == "https://github.com/stevemao/left-pad"
+    assert (first.resolution["source"], first.resolution["cache"]) == ("registry", "miss")
+    assert first.resolution["confidence"] == 0.9
+    # The second lookup is answered by the on-disk resolution cache
+    assert second.repository_url == first.repository_url
+    assert second.resolution["cache"] == "hit"
+    assert requested == ["https://registry.npmjs.org/left-pad"]
+
+
+@pytest.mark.unit
+def test_resolve_repository_url_honors_offline_mode():
+    subpath = resolve_repository_url("go", "github.com/kubernetes/kubernetes/staging/src/k8s.io/api")
+    skipped = resolve_repository_url("go", "golang.org/x/net", "v0.17.0", AnalysisOptions(offline=True))
+
+    assert subpath.repository_url == "https://github.com/kubernetes/kubernetes"
+    assert subpath.repository_subpath == "staging/src/k8s.io/api"
+    assert subpath.resolution["source"] == "import-path"
+    assert skipped.repository_url is None
+    assert skipped.resolution["reason"] == "offline-skipped"
+    with pytest.raises(ValueError, match="supported: cargo"):
+        resolve_repository_url("maven", "org.slf4j:slf4j-api")
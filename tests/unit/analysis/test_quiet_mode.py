@@ -0,0 +1,71 @@
+"""
+--quiet: only the data document on stdout, for callers parsing it
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.output_schema import validate_document
+from gardener.common.utils import configure_logging
+
+
+@pytest.fixture(autouse=True)
+def reset_logging():
+    yield
+    configure_logging()
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n\nvar _ = errors.New\n')
+    return str(root)
+
+
+@pytest.mark.unit
+def test_quiet_json_writes_exactly_one_document(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = _make_repo(tmp_path)
+    monkeypatch.setattr(sys, "argv", ["gardener", repo, "--quiet", "--offline", "--no-cache", "-l", "go"])
+
+    with offline_mode.set_responses({}):
+        main_cli.main()
+
+    captured = capsys.readouterr()
+    document = json.loads(captured.out)
+    assert captured.out == json.dumps(document, indent=2) + "\n"
+    assert validate_document(document) == []
+    assert captured.err == ""
+
+
+@pytest.mark.unit
+def test_quiet_ndjson_streams_only_records(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = _make_repo(tmp_path)
+    argv = [repo, "-q", "--format", "ndjson", "--offline", "--no-cache", "-l", "go"]
+    monkeypatch.setattr(sys, "argv", ["gardener", *argv])
+
+    with offline_mode.set_responses({}):
+        main_cli.main()
+
+    captured = capsys.readouterr()
+    records = [json.loads(line) for line in captured.out.splitlines()]
+    assert captured.out.endswith("\n")
+    assert records[-1]["type"] == "summary"
+    assert captured.err == ""
+
+
+@pytest.mark.unit
+def test_quiet_rejects_verbose_and_a_second_stdout_writer(tmp_path, monkeypatch, capsys):
+    repo = _make_repo(tmp_path)
+    monkeypatch.setattr(
+        sys, "argv", ["gardener", repo, "--quiet", "-v", "--format", "ndjson", "--table-out", "-", "--no-cache"]
+    )
+
+    with pytest.raises(SystemExit):
+        main_cli.main()
+
+    captured = capsys.readouterr()
+    assert captured.out == ""
+    assert "--quiet and --verbose are mutually exclusive" in captured.err
+    assert "no --*-out can be '-'" in captured.err
@@ -0,0 +1,55 @@
+"""
+Stable IDs on package and file-evidence records
+"""
+
+import pytest
+
+from gardener.analysis.record_ids import file_id, package_id, with_evidence_ids
+from gardener.api import AnalysisOptions, analyze_repo
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    (root / "util").mkdir()
+    (root / "util" / "wrap.go").write_text('package util\n\nimport "github.com/pkg/errors"\n')
+
+
+@pytest.mark.unit
+def test_ids_are_canonical_strings():
+    assert package_id("github.com/pkg/errors", {"ecosystem": "go"}) == "go:github.com/pkg/errors"
+    assert package_id("left-pad", {}) == "unknown:left-pad"
+    assert file_id("cmd\\server\\main.go") == "file:cmd/server/main.go"
+
+    entries = [{"import": "fmt"}, {"import": "os"}, {"import": "fmt", "import_kind": "dot"}]
+    assert [entry["id"] for entry in with_evidence_ids("main.go", entries)] == [
+        "file:main.go#fmt",
+        "file:main.go#os",
+        "file:main.go#fmt~2",
+    ]
+    assert "id" not in entries[0]
+
+
+@pytest.mark.unit
+def test_ids_do_not_depend_on_traversal_or_concurrency(tmp_path, offline_mode):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    _make_repo(repo)
+    records = []
+
+    with offline_mode.set_responses({}):
+        serial = analyze_repo(str(repo), AnalysisOptions(languages=["go"], config_overrides={"ANALYSIS_JOBS": 1}))
+        parallel = analyze_repo(
+            str(repo),
+            AnalysisOptions(languages=["go"], config_overrides={"ANALYSIS_JOBS": 4}, on_file_evidence=records.append),
+        )
+
+    assert serial.external_packages["github.com/pkg/errors"]["id"] == "go:github.com/pkg/errors"
+    evidence = serial.raw["analyzer_details"]["file_import_evidence"]
+    assert evidence["util/wrap.go"][0]["id"] == "file:util/wrap.go#github.com/pkg/errors"
+    assert evidence == parallel.raw["analyzer_details"]["file_import_evidence"]
+
+    # Streamed records carry the same IDs as the JSON results
+    streamed = {record["id"]: record for record in records}
+    assert sorted(streamed) == ["file:main.go", "file:util/wrap.go"]
+    assert streamed["file:main.go"]["evidence"][0]["id"] == evidence["main.go"][0]["id"]
@@ -0,0 +1,149 @@
+"""
+Repository settings files (.gardener.yaml, .gardener.toml) under the command line
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis import main as analysis_main
+
+
+def _make_repo(root, settings_name=None, settings=""):
+    root.mkdir()
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n")
+    (root / "main.go").write_text('package main\n\nimport "fmt"\n')
+    if settings_name:
+        (root / settings_name).write_text(settings)
+    return str(root)
+
+
+@pytest.fixture
+def no_analysis(monkeypatch):
+    runs = []
+    monkeypatch.setattr(main_cli, "run_analysis", lambda *args, **kwargs: runs.append(args))
+    monkeypatch.setattr(analysis_main.RepositoryAnalyzer, "scan_repo", lambda self: runs.append("scan"))
+    return runs
+
+
+def _check_config(monkeypatch, capsys, *argv):
+    monkeypatch.setattr(sys, "argv", ["gardener", *argv, "--check-config"])
+    main_cli.main()
+    return json.loads(capsys.readouterr().out)
+
+
+@pytest.mark.unit
+def test_settings_file_replaces_defaults_and_flags_win(tmp_path, monkeypatch, capsys, no_analysis):
+    settings = (
+        "languages: [go, js]\n"
+        "offline: true\n"
+        "exclude:\n  - 'vendor/**'\n"
+        "deny-registry: ['*.corp.example']\n"
+        "format: cyclonedx\n"
+        "jobs: 2\n"
+        "no-sort: true\n"
+        "json-out: reports/deps.json\n"
+        "config:\n  PAGERANK_ALPHA: 0.9\n"
+    )
+    repo = _make_repo(tmp_path / "repo", ".gardener.yaml", settings)
+
+    configuration = _check_config(monkeypatch, capsys, repo, "--jobs", "3", "--format", "csv")
+
+    assert configuration["config_file"] == str(tmp_path / "repo" / ".gardener.yaml")
+    assert configuration["analyzers"] == ["go", "javascript"]
+    assert configuration["outputs"]["format"] == "csv"
+    assert configuration["outputs"]["destinations"] == {"json": str(tmp_path / "repo" / "reports" / "deps.json")}
+    config = configuration["config"]
+    assert config["ResourceLimits"]["ANALYSIS_JOBS"] == 3
+    assert config["ResourceLimits"]["EXCLUDE_PATTERNS"] == ["vendor/**"]
+    assert config["NetworkConfig"]["OFFLINE"] is True
+    assert config["NetworkConfig"]["DENY_REGISTRIES"] == ["*.corp.example"]
+    assert config["GraphAnalysisConfig"]["SERIALIZE_SORT_KEYS"] is False
+    assert config["GraphAnalysisConfig"]["PAGERANK_ALPHA"] == 0.9
+    assert no_analysis == []
+
+
+@pytest.mark.unit
+def test_toml_settings_and_no_repo_config(tmp_path, monkeypatch, capsys, no_analysis):
+    repo = _make_repo(tmp_path / "repo", ".gardener.toml", 'languages = "go"\nmax-depth = 2\nsort = false\n')
+
+    configuration = _check_config(monkeypatch, capsys, repo)
+    assert configuration["analyzers"] == ["go"]
+    assert configuration["config"]["ResourceLimits"]["MAX_SCAN_DEPTH"] == 2
+    assert configuration["config"]["GraphAnalysisConfig"]["SERIALIZE_SORT_KEYS"] is False
+
+    configuration = _check_config(monkeypatch, capsys, repo, "--no-repo-config")
+    assert configuration["config_file"] is None
+    assert configuration["config"]["ResourceLimits"]["MAX_SCAN_DEPTH"] == -1
+
+
+@pytest.mark.unit
+def test_unknown_settings_warn_unless_strict(tmp_path, monkeypatch, capsys, no_analysis):
+    repo = _make_repo(tmp_path / "repo", ".gardener.yaml", "languages: go\nexcludes: ['docs/**']\n")
+
+    configuration = _check_config(monkeypatch, capsys, repo)
+    assert configuration["analyzers"] == ["go"]
+
+    monkeypatch.setattr(sys, "argv", ["gardener", repo, "--strict-config"])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == 2
+    record = json.loads(capsys.readouterr().err.strip().splitlines()[-1])
+    assert record["message"] == ".gardener.yaml: unknown setting 'excludes'"
+    assert no_analysis == []
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "settings, expected",
+    [
+        ("jobs: many\n", ".gardener.yaml: jobs: invalid int value 'many'"),
+        ("format: xml\n", ".gardener.yaml: format: invalid choice 'xml'"),
+        ("offline: yes please\n", ".gardener.yaml: offline: expected true or false"),
+        ("- go\n", ".gardener.yaml must hold a mapping"),
+        ("languages: [go\n", "Cannot read .gardener.yaml"),
+        # Where tokens, requests and results go is only up to the command line
+        ("auth-token: s3cret\n", ".gardener.yaml: 'auth-token' can only be given on the command line"),
+        ("auth-host: [evil.example]\n", "'auth-host' can only be given on the command line"),
+        ("allow_registry: ['*']\n", "'allow_registry' can only be given on the command line"),
+        ("url-rule: ['^x https://evil.example/{0}']\n", "'url-rule' can only be given on the command line"),
+        ("webhook-url: https://evil.example/hook\n", "'webhook-url' can only be given on the command line"),
+        ("webhook-header: ['X-Token: t']\n", "'webhook-header' can only be given on the command line"),
+        ("config:\n  AUTH_HOSTS: [evil.example]\n", "config: AUTH_HOSTS can only be set on the command line"),
+        # Paths stay inside the repository
+        ("json-out: ../deps.json\n", "is outside the repository"),
+        ("cache-dir: /tmp/gardener-cache\n", "is outside the repository"),
+        ("baseline: ~/old.json\n", "is outside the repository"),
+        ("files-from: reports/../../list.txt\n", "is outside the repository"),
+        ("output: ../../escaped\n", "is outside the output directory"),
+        ("output: /tmp/escaped\n", "is outside the output directory"),
+    ],
+)
+def test_invalid_settings_exit_2(tmp_path, monkeypatch, capsys, no_analysis, settings, expected):
+    repo = _make_repo(tmp_path / "repo", ".gardener.yaml", settings)
+    monkeypatch.setattr(sys, "argv", ["gardener", repo])
+
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == 2
+    assert expected in json.loads(capsys.readouterr().err.strip().splitlines()[-1])["message"]
+    assert no_analysis == []
+
+
+@pytest.mark.unit
+def test_paths_through_symlinks_out_of_the_repository_are_rejected(tmp_path, monkeypatch, capsys, no_analysis):
+    repo = _make_repo(tmp_path / "repo", ".gardener.yaml", "baseline: shared/old.json\n")
+    (tmp_path / "elsewhere").mkdir()
+    (tmp_path / "repo" / "shared").symlink_to(tmp_path / "elsewhere")
+    monkeypatch.setattr(sys, "argv", ["gardener", repo])
+
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == 2
+    message = json.loads(capsys.readouterr().err.strip().splitlines()[-1])["message"]
+    assert ".gardener.yaml: baseline: " in message and "is outside the repository" in message
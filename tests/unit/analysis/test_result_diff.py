@@ -0,0 +1,121 @@
+"""
+Dependency changes between two analysis runs (`gardener diff`, --baseline)
+"""
+
+import json
+import os
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.result_diff import diff_results, render_diff_summary
+from gardener.common.exit_codes import EXIT_ADDED_DEPENDENCIES, EXIT_INVALID_ARGUMENTS
+
+# Written by an older run: flat, and without package IDs
+OLD = {
+    "external_packages": {
+        "github.com/pkg/errors": {"ecosystem": "go", "version": "v0.9.0"},
+        "left-pad": {"ecosystem": "npm", "version": "1.3.0"},
+    }
+}
+
+NEW = {
+    "ecosystems": {
+        "go": {
+            "github.com/pkg/errors": {"id": "go:github.com/pkg/errors", "ecosystem": "go", "version": "v0.9.1"},
+            "github.com/google/uuid": {"id": "go:github.com/google/uuid", "ecosystem": "go", "version": "v1.6.0"},
+        }
+    }
+}
+
+
+def _write(path, document):
+    path.write_text(json.dumps(document))
+    return str(path)
+
+
+@pytest.mark.unit
+def test_packages_are_matched_by_id_across_layouts():
+    diff = diff_results(OLD, NEW)
+
+    assert diff["added"] == [
+        {"id": "go:github.com/google/uuid", "name": "github.com/google/uuid", "ecosystem": "go", "version": "v1.6.0"}
+    ]
+    assert diff["removed"] == [{"id": "npm:left-pad", "name": "left-pad", "ecosystem": "npm", "version": "1.3.0"}]
+    assert diff["changed"] == [
+        {
+            "id": "go:github.com/pkg/errors",
+            "name": "github.com/pkg/errors",
+            "ecosystem": "go",
+            "old_version": "v0.9.0",
+            "new_version": "v0.9.1",
+        }
+    ]
+    assert render_diff_summary(diff) == (
+        "1 added, 1 removed, 1 version changes\n"
+        "+ go:github.com/google/uuid v1.6.0\n"
+        "- npm:left-pad 1.3.0\n"
+        "~ go:github.com/pkg/errors v0.9.0 -> v0.9.1\n"
+    )
+    assert render_diff_summary(diff_results(NEW, NEW)) == "No dependency changes\n"
+
+
+@pytest.mark.unit
+def test_diff_command_prints_json_and_gates_on_added(tmp_path, monkeypatch, capsys):
+    old, new = _write(tmp_path / "old.json", OLD), _write(tmp_path / "new.json", NEW)
+
+    monkeypatch.setattr(sys, "argv", ["gardener", "diff", old, new, "--format", "json"])
+    main_cli.main()
+    assert json.loads(capsys.readouterr().out)["summary"] == {"added": 1, "removed": 1, "changed": 1}
+
+    monkeypatch.setattr(sys, "argv", ["gardener", "diff", new, old, "--fail-on-added"])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    captured = capsys.readouterr()
+    assert excinfo.value.code == EXIT_ADDED_DEPENDENCIES
+    assert captured.out.startswith("1 added, 1 removed, 1 version changes\n+ npm:left-pad 1.3.0\n")
+    record = json.loads(captured.err.strip().splitlines()[-1])
+    assert record["message"] == "1 dependencies added since the baseline: npm:left-pad"
+
+
+@pytest.mark.unit
+def test_diff_command_rejects_files_that_are_not_results(tmp_path, monkeypatch):
+    old = _write(tmp_path / "old.json", OLD)
+    monkeypatch.setattr(sys, "argv", ["gardener", "diff", old, str(tmp_path / "missing.json")])
+
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == EXIT_INVALID_ARGUMENTS
+
+
+@pytest.mark.unit
+def test_baseline_run_reports_and_gates_on_added_packages(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    (repo / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n\tgithub.com/pkg/errors v0.9.1\n"
+        "\tgithub.com/google/uuid v1.6.0\n)\n"
+    )
+    (repo / "main.go").write_text('package main\n\nimport (\n\t"github.com/google/uuid"\n\t"github.com/pkg/errors"\n)\n')
+    baseline = _write(tmp_path / "baseline.json", OLD)
+    monkeypatch.chdir(tmp_path)
+    argv = ["gardener", str(repo), "-o", "pr", "--offline", "--no-cache", "--baseline", baseline]
+
+    with offline_mode.set_responses({}):
+        monkeypatch.setattr(sys, "argv", argv)
+        main_cli.main()
+        with open(os.path.join("output", "pr_dependency_analysis.json")) as handle:
+            baseline_diff = json.load(handle)["baseline_diff"]
+
+        monkeypatch.setattr(sys, "argv", argv + ["--fail-on-added"])
+        with pytest.raises(SystemExit) as excinfo:
+            main_cli.main()
+
+    assert [package["id"] for package in baseline_diff["added"]] == ["go:github.com/google/uuid"]
+    assert [package["id"] for package in baseline_diff["removed"]] == ["npm:left-pad"]
+    assert baseline_diff["changed"][0]["new_version"] == "v0.9.1"
+    assert excinfo.value.code == EXIT_ADDED_DEPENDENCIES
+    record = json.loads(capsys.readouterr().err.strip().splitlines()[-1])
+    assert record["error_code"] == "added-dependencies"
@@ -0,0 +1,54 @@
+"""
+The reverse dependency index: external package -> files importing it
+"""
+
+import os
+
+import pytest
+
+from gardener.analysis.import_graph import build_reverse_index
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.common.defaults import ConfigOverride
+
+
+@pytest.mark.unit
+def test_go_fixture_packages_map_to_their_importing_files(offline_mode):
+    with offline_mode.set_responses({}), ConfigOverride({"OFFLINE": True}):
+        results = DependencyAnalyzer().analyze(os.path.abspath("tests/fixtures/go"), ["go"])
+
+    assert results["reverse_index"]["github.com/gin-gonic/gin"] == ["main.go"]
+    assert results["reverse_index_scopes"]["github.com/gin-gonic/gin"] == {"production": ["main.go"]}
+    imported = {name for name, info in results["external_packages"].items() if info.get("seen_in")}
+    assert set(results["reverse_index"]) == imported
+
+
+@pytest.mark.unit
+def test_files_are_sorted_and_grouped_by_scope():
+    packages = {
+        "github.com/stretchr/testify": {
+            "seen_in": [
+                {"file": "svc/svc_test.go", "imports": ["github.com/stretchr/testify/assert"], "scope": "test"},
+                {"file": "api/api_test.go", "imports": ["github.com/stretchr/testify/require"], "scope": "test"},
+            ]
+        },
+        "github.com/pkg/errors": {
+            "seen_in": [
+                {"file": "svc/svc.go", "imports": ["github.com/pkg/errors"], "scope": "production"},
+                {"file": "api/api_test.go", "imports": ["github.com/pkg/errors"], "scope": "test"},
+            ]
+        },
+        "lodash": {"seen_in": [{"file": "web/index.js", "imports": ["lodash"]}]},
+        "github.com/unused/mod": {"version": "v1.0.0"},
+    }
+
+    index, scopes = build_reverse_index(packages)
+
+    assert index == {
+        "github.com/pkg/errors": ["api/api_test.go", "svc/svc.go"],
+        "github.com/stretchr/testify": ["api/api_test.go", "svc/svc_test.go"],
+        "lodash": ["web/index.js"],
+    }
+    assert scopes == {
+        "github.com/pkg/errors": {"production": ["svc/svc.go"], "test": ["api/api_test.go"]},
+        "github.com/stretchr/testify": {"test": ["api/api_test.go", "svc/svc_test.go"]},
+    }
@@ -0,0 +1,182 @@
+"""
+SBOM serialization of analysis results
+"""
+
+import os
+import re
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.sbom import exact_version, package_purl, to_cyclonedx, to_spdx
+from gardener.treewalk.go import GoLanguageHandler
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "name,info,expected",
+    [
+        (
+            "github.com/gin-gonic/gin",
+            {"ecosystem": "go", "version": "v1.7.7"},
+            "pkg:golang/github.com/gin-gonic/gin@v1.7.7",
+        ),
+        ("lodash", {"ecosystem": "npm", "version": "4.17.21"}, "pkg:npm/lodash@4.17.21"),
+        ("@babel/core", {"ecosystem": "npm", "version": "^7.0.0"}, "pkg:npm/%40babel/core"),
+        ("Django_Rest", {"ecosystem": "pypi", "version": "==3.14.0"}, "pkg:pypi/django-rest@3.14.0"),
+        ("serde", {"ecosystem": "cargo", "version": "1.0.188"}, "pkg:cargo/serde@1.0.188"),
+        ("forge-std", {"ecosystem": "solidity"}, "pkg:generic/forge-std"),
+        # Major-version suffixes stay part of the module path
+        (
+            "github.com/go-redis/redis/v8",
+            {"ecosystem": "go", "version": "v8.11.5"},
+            "pkg:golang/github.com/go-redis/redis/v8@v8.11.5",
+        ),
+        ("gopkg.in/yaml.v3", {"ecosystem": "go", "version": "v3.0.1"}, "pkg:golang/gopkg.in/yaml.v3@v3.0.1"),
+        (
+            "github.com/docker/docker",
+            {"ecosystem": "go", "version": "v24.0.7+incompatible"},
+            "pkg:golang/github.com/docker/docker@v24.0.7%2Bincompatible",
+        ),
+        (
+            "golang.org/x/sys",
+            {"ecosystem": "go", "version": "v0.0.0-20240104172133-2a1c1abb5d0f"},
+            "pkg:golang/golang.org/x/sys@v0.0.0-20240104172133-2a1c1abb5d0f",
+        ),
+        ("github.com/pkg/errors", {"ecosystem": "go", "version": ""}, "pkg:golang/github.com/pkg/errors"),
+        # Scoped npm packages put the percent-encoded scope in the namespace
+        ("@types/node", {"ecosystem": "npm", "version": "20.10.5"}, "pkg:npm/%40types/node@20.10.5"),
+        ("@angular/core", {"ecosystem": "npm", "version": "17.0.0-rc.1"}, "pkg:npm/%40angular/core@17.0.0-rc.1"),
+        (
+            "docker.io/library/golang",
+            {"ecosystem": "docker", "image": "golang", "tag": "1.22-alpine", "version": "1.22-alpine"},
+            "pkg:docker/library/golang@1.22-alpine",
+        ),
+        (
+            "ghcr.io/Org/app",
+            {"ecosystem": "docker", "image": "ghcr.io/Org/app", "digest": "sha256:abc", "version": "sha256:abc"},
+            "pkg:docker/org/app@sha256%3Aabc?repository_url=ghcr.io",
+        ),
+        (
+            "pip:requests",
+            {"ecosystem": "docker", "installer": "pip", "package": "requests", "version": "2.31.0"},
+            "pkg:pypi/requests@2.31.0",
+        ),
+        ("apt:curl", {"ecosystem": "docker", "installer": "apt", "package": "curl"}, "pkg:generic/apt/curl"),
+        ("actions/checkout", {"ecosystem": "github-actions", "version": "v4"}, "pkg:github/actions/checkout@v4"),
+        (
+            "github/codeql-action/init",
+            {"ecosystem": "github-actions", "version": "v3"},
+            "pkg:github/github/codeql-action@v3#init",
+        ),
+    ],
+)
+def test_package_purl_uses_ecosystem_purl_types(name, info, expected):
+    assert package_purl(name, info) == expected
+
+
+@pytest.mark.unit
+def test_every_external_package_carries_its_purl(tmp_path):
+    (tmp_path / "go.mod").write_text("module example.com/app\n\nrequire github.com/go-redis/redis/v8 v8.11.5\n")
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/go-redis/redis/v8"\n')
+    (tmp_path / "package.json").write_text('{"dependencies": {"@babel/core": "^7.0.0"}}')
+
+    analyzer = DependencyAnalyzer()
+    results = analyzer.analyze_dependencies(analyzer.discover_packages(str(tmp_path), ["go", "javascript"]))
+
+    purls = {name: info["purl"] for name, info in results["external_packages"].items()}
+    assert purls == {
+        "github.com/go-redis/redis/v8": "pkg:golang/github.com/go-redis/redis/v8@v8.11.5",
+        "@babel/core": "pkg:npm/%40babel/core",
+    }
+
+
+@pytest.mark.unit
+def test_cyclonedx_bom_refs_stay_unique_when_purls_repeat():
+    external_packages = {
+        "requests": {"ecosystem": "pypi", "version": "2.31.0"},
+        "pip:requests": {"ecosystem": "docker", "installer": "pip", "package": "requests", "version": "2.31.0"},
+    }
+
+    bom = to_cyclonedx({"external_packages": external_packages}, "app")
+
+    refs = {component["name"]: component["bom-ref"] for component in bom["components"]}
+    assert refs == {"pip:requests": "pkg:pypi/requests@2.31.0", "requests": "gardener:package:requests"}
+    assert {component["purl"] for component in bom["components"]} == {"pkg:pypi/requests@2.31.0"}
+
+
+@pytest.mark.unit
+def test_exact_version_rejects_ranges():
+    assert exact_version("v1.2.3") == "v1.2.3"
+    assert exact_version("=1.0.0-rc.1") == "1.0.0-rc.1"
+    assert exact_version(">=2,<3") is None
+    assert exact_version("") is None
+    assert exact_version(None) is None
+
+
+@pytest.mark.unit
+def test_cyclonedx_document_lists_go_modules_and_unversioned_components():
+    fixture_dir = "tests/fixtures/go"
+    external_packages = GoLanguageHandler().process_manifest(os.path.join(fixture_dir, "go.mod"), {})
+    external_packages["github.com/gin-gonic/gin"]["repository_url"] = "https://github.com/gin-gonic/gin"
+    external_packages["left-pad"] = {"ecosystem": "npm", "version": "*", "repository_url": ""}
+
+    bom = to_cyclonedx({"external_packages": external_packages}, "go-fixture")
+
+    assert bom["bomFormat"] == "CycloneDX"
+    assert bom["specVersion"] == "1.5"
+    assert bom["metadata"]["tools"]["components"][0]["name"] == "gardener"
+    components = {component["name"]: component for component in bom["components"]}
+    assert set(components) == set(external_packages)
+
+    gin = components["github.com/gin-gonic/gin"]
+    assert gin["purl"] == "pkg:golang/github.com/gin-gonic/gin@v1.7.7"
+    assert gin["version"] == "v1.7.7"
+    assert gin["externalReferences"] == [{"type": "vcs", "url": "https://github.com/gin-gonic/gin"}]
+
+    # Unresolved versions are omitted, never dropped
+    assert "version" not in components["left-pad"]
+    assert "externalReferences" not in components["left-pad"]
+    assert components["left-pad"]["purl"] == "pkg:npm/left-pad"
+
+    root = bom["dependencies"][0]
+    assert root["ref"] == bom["metadata"]["component"]["bom-ref"]
+    assert sorted(root["dependsOn"]) == sorted(component["bom-ref"] for component in bom["components"])
+
+
+@pytest.mark.unit
+def test_spdx_document_has_deterministic_ids_and_noassertion_locations():
+    external_packages = {
+        "github.com/gin-gonic/gin": {
+            "ecosystem": "go",
+            "version": "v1.7.7",
+            "repository_url": "https://github.com/gin-gonic/gin",
+        },
+        "@a/b": {"ecosystem": "npm", "version": "1.0.0", "repository_url": ""},
+        "a-b": {"ecosystem": "npm", "version": "^2.0.0"},
+    }
+
+    first = to_spdx({"external_packages": external_packages}, "demo")
+    second = to_spdx({"external_packages": dict(reversed(list(external_packages.items())))}, "demo")
+
+    assert first["spdxVersion"] == "SPDX-2.3"
+    assert first["creationInfo"]["creators"][0].startswith("Tool: gardener-")
+    assert [p["SPDXID"] for p in first["packages"]] == [p["SPDXID"] for p in second["packages"]]
+
+    packages = {package["name"]: package for package in first["packages"]}
+    ids = [package["SPDXID"] for package in first["packages"]]
+    assert len(ids) == len(set(ids))
+    assert all(re.match(r"^SPDXRef-[A-Za-z0-9.-]+$", spdx_id) for spdx_id in ids)
+
+    assert packages["github.com/gin-gonic/gin"]["downloadLocation"] == "https://github.com/gin-gonic/gin"
+    assert packages["github.com/gin-gonic/gin"]["versionInfo"] == "v1.7.7"
+    assert packages["@a/b"]["downloadLocation"] == "NOASSERTION"
+    assert packages["a-b"]["downloadLocation"] == "NOASSERTION"
+    assert "versionInfo" not in packages["a-b"]
+
+    root_id = packages["demo"]["SPDXID"]
+    depends_on = {
+        rel["relatedSpdxElement"] for rel in first["relationships"] if rel["relationshipType"] == "DEPENDS_ON"
+    }
+    assert all(rel["spdxElementId"] == root_id for rel in first["relationships"][1:])
+    assert depends_on == {packages[name]["SPDXID"] for name in external_packages}
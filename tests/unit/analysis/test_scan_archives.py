@@ -0,0 +1,101 @@
+"""
+Source files inside archives committed to the repository (--scan-archives)
+"""
+
+import io
+import json
+import tarfile
+import zipfile
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.analysis.output_schema import validate_document
+from gardener.common.defaults import ConfigOverride
+
+GO_MOD = "module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n"
+WRAP_GO = 'package errwrap\n\nimport "github.com/pkg/errors"\n\nvar _ = errors.New\n'
+
+
+def _write_zip(path, files):
+    path.parent.mkdir(parents=True, exist_ok=True)
+    with zipfile.ZipFile(path, "w") as archive:
+        for name, text in files.items():
+            archive.writestr(name, text)
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(GO_MOD)
+    (root / "main.go").write_text("package main\n\nfunc main() {}\n")
+
+
+def _analyze(root, **overrides):
+    with ConfigOverride({"OFFLINE": True, **overrides}):
+        analyzer = DependencyAnalyzer()
+        results = analyzer.analyze(str(root), ["go"])
+    return analyzer, results
+
+
+@pytest.mark.unit
+def test_archive_sources_are_analyzed_with_their_archive_marked(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+    _write_zip(tmp_path / "third_party" / "errwrap.zip", {"errwrap/wrap.go": WRAP_GO, "README": "docs\n"})
+    with tarfile.open(tmp_path / "third_party" / "tools.tar.gz", "w:gz") as archive:
+        data = WRAP_GO.encode()
+        info = tarfile.TarInfo("tools/wrap.go")
+        info.size = len(data)
+        archive.addfile(info, io.BytesIO(data))
+
+    with offline_mode.set_responses({}):
+        analyzer, results = _analyze(tmp_path, SCAN_ARCHIVES=True)
+
+    seen_in = results["external_packages"]["github.com/pkg/errors"]["seen_in"]
+    assert [(entry["file"], entry["archive_source"]) for entry in seen_in] == [
+        ("third_party/errwrap.zip/errwrap/wrap.go", "third_party/errwrap.zip"),
+        ("third_party/tools.tar.gz/tools/wrap.go", "third_party/tools.tar.gz"),
+    ]
+    evidence = results["analyzer_details"]["file_import_evidence"]["third_party/errwrap.zip/errwrap/wrap.go"]
+    assert [(entry["import"], entry["archive_source"]) for entry in evidence] == [
+        ("github.com/pkg/errors", "third_party/errwrap.zip")
+    ]
+    assert "errors" not in results
+    # The extracted files are gone once they are parsed
+    assert analyzer.repo_analyzer.archive_dir is None
+    assert validate_document(json.loads(json.dumps(results, default=str))) == []
+
+
+@pytest.mark.unit
+def test_unsafe_and_oversized_archives_are_skipped_with_an_error(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+    _write_zip(tmp_path / "slip.zip", {"wrap.go": WRAP_GO, "../escape.go": "package escape\n"})
+    _write_zip(tmp_path / "big.jar", {"wrap.go": WRAP_GO + "// " + "x" * 2048 + "\n"})
+    _write_zip(tmp_path / "ok.zip", {"wrap.go": WRAP_GO})
+    (tmp_path / "fake.zip").write_text("not an archive")
+
+    with offline_mode.set_responses({}):
+        _, results = _analyze(tmp_path, SCAN_ARCHIVES=True, MAX_ARCHIVE_EXTRACT_SIZE=1024)
+
+    assert [(error["file"], error["error"]) for error in results["errors"]] == [
+        ("big.jar", "archive-skipped"),
+        ("slip.zip", "archive-skipped"),
+    ]
+    assert "more than the limit of 1024" in results["errors"][0]["detail"]
+    assert "escapes the extraction directory" in results["errors"][1]["detail"]
+    assert not (tmp_path.parent / "escape.go").exists()
+    seen_in = results["external_packages"]["github.com/pkg/errors"]["seen_in"]
+    assert [entry["file"] for entry in seen_in] == ["ok.zip/wrap.go"]
+
+
+@pytest.mark.unit
+def test_archives_are_left_closed_by_default_and_when_ignored(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+    _write_zip(tmp_path / "third_party" / "errwrap.zip", {"errwrap/wrap.go": WRAP_GO})
+
+    with offline_mode.set_responses({}):
+        _, results = _analyze(tmp_path)
+    assert "seen_in" not in results["external_packages"]["github.com/pkg/errors"]
+
+    (tmp_path / ".gitignore").write_text("third_party/\n")
+    with offline_mode.set_responses({}):
+        _, results = _analyze(tmp_path, SCAN_ARCHIVES=True)
+    assert "seen_in" not in results["external_packages"]["github.com/pkg/errors"]
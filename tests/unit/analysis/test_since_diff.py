@@ -0,0 +1,95 @@
+"""
+--since: analyze only the files changed since a git ref
+"""
+
+import subprocess
+
+import pytest
+
+from gardener.analysis.git_diff import changed_files_since
+from gardener.analysis.main import run_analysis
+from gardener.common.utils import RepositoryError
+from gardener.persistence.file import FilePersistence
+
+
+def _git(repo, *args):
+    subprocess.run(
+        ["git", "-c", "user.name=test", "-c", "user.email=test@example.com", *args],
+        cwd=repo,
+        check=True,
+        capture_output=True,
+    )
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgithub.com/gin-gonic/gin v1.9.1\n"
+        "\tgithub.com/pkg/errors v0.9.1\n"
+        ")\n"
+    )
+    (root / "main.go").write_text('package main\n\nimport "github.com/gin-gonic/gin"\n')
+    (root / "util").mkdir()
+    (root / "util" / "errors.go").write_text('package util\n\nimport "github.com/pkg/errors"\n')
+    (root / "util" / "old.go").write_text("package util\n")
+    _git(root, "init", "-q", "-b", "main")
+    _git(root, "add", "-A")
+    _git(root, "commit", "-q", "-m", "base")
+    _git(root, "checkout", "-q", "-b", "feature")
+    (root / "util" / "errors.go").write_text('package util\n\nimport (\n\t"fmt"\n\n\t"github.com/pkg/errors"\n)\n')
+    (root / "util" / "old.go").unlink()
+    (root / "README.md").write_text("notes\n")
+    _git(root, "add", "-A")
+    _git(root, "commit", "-q", "-m", "change")
+
+
+@pytest.mark.unit
+def test_changed_files_skip_deleted_paths(tmp_path):
+    _make_repo(tmp_path)
+
+    scope = changed_files_since(str(tmp_path), "main")
+
+    assert scope == {
+        "since": "main",
+        "changed_files": ["README.md", "util/errors.go"],
+        "deleted_files": ["util/old.go"],
+    }
+
+
+@pytest.mark.unit
+def test_since_analyzes_only_changed_sources_and_marks_the_results(tmp_path, offline_mode):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    _make_repo(repo)
+    persistence = FilePersistence(output_dir=str(tmp_path / "out"), verbose=False)
+
+    with offline_mode.set_responses({}):
+        results = run_analysis(
+            str(repo), "demo", persistence=persistence, focus_languages_str="go", minimal_outputs=True, since="main"
+        )
+
+    assert results["analysis_scope"]["mode"] == "diff"
+    assert results["analysis_scope"]["since"] == "main"
+    assert results["analysis_scope"]["deleted_files"] == ["util/old.go"]
+    assert sorted(results["analyzer_details"]["file_imports"]) == ["util/errors.go"]
+    assert results["analyzer_details"]["total_files"] == 1
+    # Manifests are still read in full
+    assert "github.com/gin-gonic/gin" in results["external_packages"]
+
+
+@pytest.mark.unit
+def test_since_rejects_paths_outside_a_git_repository(tmp_path):
+    (tmp_path / "main.go").write_text("package main\n")
+
+    with pytest.raises(RepositoryError, match="not inside a git work tree"):
+        changed_files_since(str(tmp_path), "main")
+
+
+@pytest.mark.unit
+def test_since_rejects_unknown_refs(tmp_path):
+    _make_repo(tmp_path)
+
+    with pytest.raises(RepositoryError, match="Cannot diff against 'nope'"):
+        changed_files_since(str(tmp_path), "nope")
+    with pytest.raises(RepositoryError, match="Invalid git ref"):
+        changed_files_since(str(tmp_path), "--output=/tmp/x")
@@ -0,0 +1,166 @@
+"""
+Top-level summary statistics
+"""
+
+import json
+import os
+import sys
+
+import pytest
+
+from gardener import main_cli
+
+from gardener.analysis.summary import (
+    ECOSYSTEM_FIELDS,
+    SCOPE_FIELDS,
+    SUMMARY_FIELDS,
+    build_summary,
+    group_by_ecosystem,
+    only_unresolved,
+)
+
+
+def _results():
+    return {
+        "external_packages": {
+            "github.com/pkg/errors": {
+                "ecosystem": "go",
+                "repository_url": "https://github.com/pkg/errors",
+                "scope": "production",
+            },
+            "github.com/stretchr/testify": {"ecosystem": "go", "repository_url": "", "scope": "test"},
+            "requests": {"ecosystem": "pypi", "repository_url": "https://github.com/psf/requests"},
+        },
+        "dependency_graph": {
+            "nodes": [
+                {"id": "main.go", "type": "file", "language": "go"},
+                {"id": "main_test.go", "type": "file", "language": "go"},
+                {"id": "app.py", "type": "file", "language": "python"},
+                {"id": "fmt", "type": "package", "ecosystem": "go_stdlib"},
+                {"id": "os", "type": "package", "ecosystem": "python_stdlib"},
+                {"id": "github.com/pkg/errors", "type": "package", "ecosystem": "go"},
+            ]
+        },
+        "analyzer_details": {
+            "total_files": 4,
+            "file_imports": {
+                "main.go": ["fmt", "github.com/pkg/errors"],
+                "main_test.go": ["github.com/stretchr/testify/assert"],
+                "app.py": ["os", "requests"],
+            },
+            "local_imports_map": {"main_test.go": ["main.go"], "app.py": ["util.py"], "util.py": []},
+        },
+    }
+
+
+@pytest.mark.unit
+def test_summary_counts_packages_scopes_and_ecosystems():
+    summary = build_summary(_results())
+
+    assert list(summary) == list(SUMMARY_FIELDS)
+    assert summary["files_analyzed"] == 4
+    assert summary["total_imports"] == 7
+    assert summary["external_packages"] == 3
+    assert (summary["resolved_urls"], summary["unresolved_urls"]) == (2, 1)
+    assert summary["scopes"] == {
+        "production": 1,
+        "test": 1,
+        "tool": 0,
+        "generate": 0,
+        "example": 0,
+        "ignored-build": 0,
+        "local": 2,
+        "stdlib": 2,
+    }
+    assert summary["ecosystems"] == {
+        "go": {"external_packages": 2, "resolved_urls": 1, "unresolved_urls": 1, "stdlib": 1, "local": 1},
+        "pypi": {"external_packages": 1, "resolved_urls": 1, "unresolved_urls": 0, "stdlib": 1, "local": 1},
+    }
+    assert all(list(entry) == list(ECOSYSTEM_FIELDS) for entry in summary["ecosystems"].values())
+    assert list(summary["scopes"]) == list(SCOPE_FIELDS)
+
+
+@pytest.mark.unit
+def test_summary_of_an_empty_analysis_keeps_every_field():
+    summary = build_summary({})
+
+    assert summary == {
+        "files_analyzed": 0,
+        "skipped_by_depth": 0,
+        "total_imports": 0,
+        "external_packages": 0,
+        "resolved_urls": 0,
+        "unresolved_urls": 0,
+        "scopes": {
+            "production": 0,
+            "test": 0,
+            "tool": 0,
+            "generate": 0,
+            "example": 0,
+            "ignored-build": 0,
+            "local": 0,
+            "stdlib": 0,
+        },
+        "ecosystems": {},
+    }
+
+
+@pytest.mark.unit
+def test_grouped_packages_line_up_with_the_summary_ecosystems():
+    results = _results()
+    results["external_packages"]["mystery"] = {"repository_url": ""}
+    summary = build_summary(results)
+
+    grouped = group_by_ecosystem(results)
+
+    assert "external_packages" not in grouped
+    assert list(grouped["ecosystems"]) == ["go", "pypi", "unknown"]
+    assert set(grouped["ecosystems"]["go"]) == {"github.com/pkg/errors", "github.com/stretchr/testify"}
+    for ecosystem, packages in grouped["ecosystems"].items():
+        assert summary["ecosystems"][ecosystem]["external_packages"] == len(packages)
+    assert grouped["dependency_graph"] is results["dependency_graph"]
+
+
+@pytest.mark.unit
+def test_only_unresolved_keeps_unresolved_packages_grouped_by_reason():
+    results = _results()
+    results["external_packages"]["go.example.com/lib"] = {
+        "ecosystem": "go",
+        "repository_url": "",
+        "resolution": {"reason": "vanity-meta-missing", "source": "go-import"},
+    }
+    results["summary"] = build_summary(results)
+
+    narrowed = only_unresolved(results)
+
+    assert sorted(narrowed["external_packages"]) == ["github.com/stretchr/testify", "go.example.com/lib"]
+    assert narrowed["external_packages"]["go.example.com/lib"]["resolution"]["source"] == "go-import"
+    assert narrowed["unresolved"] == {
+        "unresolved": ["github.com/stretchr/testify"],
+        "vanity-meta-missing": ["go.example.com/lib"],
+    }
+    # The totals still describe the whole analysis
+    assert narrowed["summary"]["external_packages"] == 4
+    assert len(results["external_packages"]) == 4
+
+
+@pytest.mark.unit
+def test_only_unresolved_cli_flag_filters_the_analysis_json(tmp_path, monkeypatch):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    (repo / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\n"
+        "require (\n\tgithub.com/pkg/errors v0.9.1\n\tgolang.org/x/net v0.17.0\n)\n"
+    )
+    (repo / "main.go").write_text('package main\n\nimport (\n\t"github.com/pkg/errors"\n\t"golang.org/x/net/html"\n)\n')
+    monkeypatch.chdir(tmp_path)
+    argv = ["gardener", str(repo), "-o", "unresolved", "--offline", "--no-cache", "--only-unresolved", "--flat"]
+    monkeypatch.setattr(sys, "argv", argv)
+
+    main_cli.main()
+
+    with open(os.path.join("output", "unresolved_dependency_analysis.json")) as handle:
+        document = json.load(handle)
+    assert list(document["external_packages"]) == ["golang.org/x/net"]
+    assert document["unresolved"] == {"offline-skipped": ["golang.org/x/net"]}
+    assert (document["summary"]["external_packages"], document["summary"]["unresolved_urls"]) == (2, 1)
@@ -0,0 +1,69 @@
+"""
+Symlinked files and directories during repository scans (--follow-symlinks)
+"""
+
+import pytest
+
+from gardener.analysis.scanner import scan_repository
+from gardener.common.defaults import ConfigOverride
+from gardener.common.secure_file_ops import SecureFileOps
+from gardener.common.utils import Logger, configure_logging
+from gardener.treewalk.go import GoLanguageHandler
+
+
+@pytest.fixture(autouse=True)
+def reset_logging():
+    yield
+    configure_logging()
+
+
+def _make_linked_repo(root, outside):
+    (root / "go.mod").write_text("module example.com/app\n")
+    (root / "main.go").write_text("package main\n")
+    (root / "pkg").mkdir()
+    (root / "pkg" / "lib.go").write_text("package pkg\n")
+    (outside / "stray.go").write_text("package stray\n")
+    try:
+        (root / "main_link.go").symlink_to(root / "main.go")
+        (root / "pkg_link").symlink_to(root / "pkg")
+        (root / "pkg" / "back").symlink_to(root)
+        (root / "outside").symlink_to(outside)
+    except OSError:
+        pytest.skip("Cannot create symlinks on this platform")
+
+
+def _scan(root, secure):
+    secure_file_ops = SecureFileOps(str(root)) if secure else None
+    result = scan_repository(str(root), secure_file_ops, ["go"], {"go": GoLanguageHandler()}, Logger())
+    return set(result["source_files"])
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("secure", [False, True])
+def test_symlinks_are_skipped_by_default(tmp_path, secure):
+    repo, outside = tmp_path / "repo", tmp_path / "outside"
+    repo.mkdir()
+    outside.mkdir()
+    _make_linked_repo(repo, outside)
+
+    assert _scan(repo, secure) == {"main.go", "pkg/lib.go"}
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("secure", [False, True])
+def test_followed_symlinks_skip_cycles_duplicates_and_outside_targets(tmp_path, capsys, secure):
+    repo, outside = tmp_path / "repo", tmp_path / "outside"
+    repo.mkdir()
+    outside.mkdir()
+    _make_linked_repo(repo, outside)
+    configure_logging("debug")
+
+    with ConfigOverride({"FOLLOW_SYMLINKS": True}):
+        scanned = _scan(repo, secure)
+
+    # Files reached through several paths are scanned once
+    assert scanned == {"main.go", "pkg/lib.go"}
+    err = capsys.readouterr().err
+    assert "Skipping symlink cycle: pkg/back links to its enclosing directory ." in err
+    assert "(symlink-outside-repo)" in err
+    assert "(duplicate)" in err
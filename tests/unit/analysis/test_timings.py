@@ -0,0 +1,97 @@
+"""
+Per-phase timings of an analysis run
+"""
+
+import json
+
+import pytest
+
+from gardener.analysis.main import run_analysis
+from gardener.analysis.timings import PHASES, PhaseTimings
+from gardener.package_metadata import url_resolver
+from gardener.persistence.file import FilePersistence
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    (root / "util.go").write_text('package main\n\nimport "fmt"\n')
+
+
+def _run(repo, output_dir, **kwargs):
+    persistence = FilePersistence(output_dir=str(output_dir), verbose=False)
+    return run_analysis(str(repo), output_prefix="app", persistence=persistence, **kwargs)
+
+
+@pytest.mark.unit
+def test_phases_accumulate_in_run_order():
+    ticks = iter([0.0, 1.0, 1.5, 2.0, 4.0, 10.0])
+    timings = PhaseTimings(clock=lambda: next(ticks))
+
+    with timings.phase("parsing"):
+        pass
+    with timings.phase("directory_walk"):
+        pass
+    timings.add("parsing", 0.25)
+    timings.add_language("go", 0.1)
+    section = timings.section(files=20)
+
+    assert list(section["phases"]) == ["directory_walk", "parsing"]
+    assert section["phases"] == {"directory_walk": 2.0, "parsing": 0.75}
+    assert section["parsing_by_language"] == {"go": 0.1}
+    assert (section["total_seconds"], section["files"], section["files_per_second"]) == (10.0, 20, 2.0)
+
+
+@pytest.mark.unit
+def test_timings_section_is_added_without_changing_the_dependency_data(tmp_path, offline_mode):
+    repo = tmp_path / "app"
+    repo.mkdir()
+    _make_repo(repo)
+
+    with offline_mode.set_responses({}):
+        plain = _run(repo, tmp_path / "plain", config_overrides={"OFFLINE": True})
+        timed = _run(repo, tmp_path / "timed", config_overrides={"OFFLINE": True}, timings=True)
+
+    assert "timings" not in plain
+    section = timed.pop("timings")
+    assert set(section["phases"]) == set(PHASES)
+    assert list(section["parsing_by_language"]) == ["go"]
+    assert section["files"] == 2
+    assert section["network"] == {"requests": 0, "connections": 0, "seconds": 0.0}
+    assert timed["external_packages"] == plain["external_packages"]
+    written = json.loads((tmp_path / "timed" / "app_dependency_analysis.json").read_text())
+    assert list(written) == sorted(written)
+    assert written["timings"]["files"] == 2
+
+
+@pytest.mark.unit
+def test_network_time_counts_resolution_requests(tmp_path, offline_mode, monkeypatch):
+    for name in url_resolver.GO_PRIVATE_ENV_VARS + ("GOPROXY",):
+        monkeypatch.delenv(name, raising=False)
+    repo = tmp_path / "app"
+    repo.mkdir()
+    _make_repo(repo)
+
+    with offline_mode.set_responses({}):
+        results = _run(repo, tmp_path / "out", config_overrides={"MAX_RETRIES": 0}, timings=True)
+
+    network = results["timings"]["network"]
+    assert network["requests"] > 0
+    assert network["seconds"] >= 0
+
+
+@pytest.mark.unit
+def test_timings_output_file_keeps_them_out_of_the_results(tmp_path, offline_mode):
+    repo = tmp_path / "app"
+    repo.mkdir()
+    _make_repo(repo)
+    timings_file = tmp_path / "timings.json"
+
+    with offline_mode.set_responses({}):
+        results = _run(repo, tmp_path / "out", config_overrides={"OFFLINE": True}, timings_output=str(timings_file))
+
+    assert "timings" not in results
+    assert "timings" not in json.loads((tmp_path / "out" / "app_dependency_analysis.json").read_text())
+    section = json.loads(timings_file.read_text())
+    assert section["phases"]["serialization"] > 0
+    assert section["files_per_second"] > 0
@@ -0,0 +1,165 @@
+"""
+Delivery of the finished results to --webhook-url
+"""
+
+import http.server
+import json
+import sys
+import threading
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis import webhook
+from gardener.analysis.webhook import WebhookError, deliver_webhook, parse_webhook_header
+from gardener.common.defaults import NetworkConfig
+from gardener.common.exit_codes import EXIT_INVALID_ARGUMENTS, EXIT_WEBHOOK_FAILURE
+
+WEBHOOK_URL = "https://hooks.example.com/gardener"
+
+
+@pytest.fixture
+def webhook_server():
+    """Local endpoint answering with the queued statuses (then 200), recording each request"""
+    statuses = []
+    requests = []
+
+    class Handler(http.server.BaseHTTPRequestHandler):
+        def do_POST(self):
+            body = self.rfile.read(int(self.headers["Content-Length"]))
+            requests.append({"headers": dict(self.headers), "body": body.decode("utf-8")})
+            self.send_response(statuses.pop(0) if statuses else 200)
+            self.end_headers()
+
+        def log_message(self, *args):
+            pass
+
+    server = http.server.HTTPServer(("127.0.0.1", 0), Handler)
+    thread = threading.Thread(target=server.serve_forever, daemon=True)
+    thread.start()
+    yield f"http://127.0.0.1:{server.server_port}/hook", statuses, requests
+    server.shutdown()
+    server.server_close()
+
+
+def _make_repo(root):
+    root.mkdir()
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+    (root / "main.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    return str(root)
+
+
+@pytest.fixture
+def posts(monkeypatch):
+    """Replace the webhook POST, recording (url, body, headers); set `status` to change the answer"""
+    class Sent(list):
+        status = 200
+
+    sent = Sent()
+
+    def _post(url, body, headers):
+        sent.append((url, body.decode("utf-8"), headers))
+        return sent.status, ""
+
+    monkeypatch.setattr(webhook, "_post", _post)
+    return sent
+
+
+@pytest.mark.unit
+def test_transient_failures_are_retried_with_the_headers(webhook_server, monkeypatch):
+    url, statuses, requests = webhook_server
+    statuses.extend([503, 429])
+    monkeypatch.setattr(NetworkConfig, "RETRY_BASE_DELAY", 0)
+
+    status = deliver_webhook(url, '{"summary": {}}', headers=[parse_webhook_header("Authorization: Bearer s3cret")])
+
+    assert status == 200
+    assert len(requests) == 3
+    assert requests[-1]["body"] == '{"summary": {}}'
+    assert requests[-1]["headers"]["Authorization"] == "Bearer s3cret"
+    assert requests[-1]["headers"]["Content-Type"] == "application/json"
+
+
+@pytest.mark.unit
+def test_client_errors_fail_without_retrying(webhook_server, monkeypatch):
+    url, statuses, requests = webhook_server
+    statuses.append(401)
+    monkeypatch.setattr(NetworkConfig, "RETRY_BASE_DELAY", 0)
+
+    with pytest.raises(WebhookError, match="HTTP 401"):
+        deliver_webhook(url, "{}")
+
+    assert len(requests) == 1
+
+
+@pytest.mark.unit
+def test_webhook_header_needs_a_name_and_a_colon():
+    assert parse_webhook_header("X-Run-Id:  42 ") == ("X-Run-Id", "42")
+    for value in ("Authorization", ": value", "X-A: b\r\nX-B: c"):
+        with pytest.raises(ValueError):
+            parse_webhook_header(value)
+
+
+@pytest.mark.unit
+def test_cli_posts_the_saved_json_and_ndjson_records(tmp_path, monkeypatch, offline_mode, posts):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+
+    with offline_mode.set_responses({}):
+        for output_format in ("json", "ndjson"):
+            argv = ["gardener", repo, "--no-cache", "--format", output_format, "--webhook-url", WEBHOOK_URL]
+            monkeypatch.setattr(sys, "argv", argv + ["--webhook-header", "X-Run-Id: 42"])
+            main_cli.main()
+
+    (json_url, json_body, json_headers), (_, ndjson_body, ndjson_headers) = posts
+    assert json_url == WEBHOOK_URL
+    assert json_headers["X-Run-Id"] == "42"
+    document = json.loads(json_body)
+    assert "github.com/pkg/errors" in document["ecosystems"]["go"]
+    assert document["summary"]["external_packages"] == 1
+    assert ndjson_headers["Content-Type"] == "application/x-ndjson"
+    records = [json.loads(line) for line in ndjson_body.splitlines()]
+    assert [record["type"] for record in records] == ["file_evidence", "package", "summary"]
+
+
+@pytest.mark.unit
+def test_failed_delivery_exits_6_only_when_required(tmp_path, monkeypatch, capsys, offline_mode, posts):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+    monkeypatch.setattr(NetworkConfig, "MAX_RETRIES", 0)
+    posts.status = 500
+    argv = ["gardener", repo, "--no-cache", "--webhook-url", WEBHOOK_URL]
+
+    with offline_mode.set_responses({}):
+        monkeypatch.setattr(sys, "argv", argv)
+        main_cli.main()
+
+        monkeypatch.setattr(sys, "argv", argv + ["--require-webhook"])
+        with pytest.raises(SystemExit) as excinfo:
+            main_cli.main()
+
+    assert excinfo.value.code == EXIT_WEBHOOK_FAILURE
+    record = json.loads(capsys.readouterr().err.strip().splitlines()[-1])
+    assert record["error_code"] == "webhook-failed"
+    assert record["message"] == f"Webhook delivery to {WEBHOOK_URL} failed after 1 attempts: HTTP 500"
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "extra",
+    [
+        ["--require-webhook"],
+        ["--webhook-header", "X-Run-Id: 42"],
+        ["--webhook-url", "ftp://hooks.example.com/gardener"],
+        ["--webhook-url", WEBHOOK_URL, "--webhook-header", "no colon"],
+        ["--webhook-url", WEBHOOK_URL, "--offline"],
+    ],
+)
+def test_webhook_option_problems_are_invalid_arguments(tmp_path, monkeypatch, capsys, extra):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.setattr(sys, "argv", ["gardener", repo, *extra])
+
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == EXIT_INVALID_ARGUMENTS
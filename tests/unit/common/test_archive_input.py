@@ -0,0 +1,127 @@
+"""
+Analyzing zip and tar source archives
+"""
+
+import io
+import json
+import os
+import sys
+import tarfile
+import zipfile
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.main import run_analysis
+from gardener.common.archives import archive_format, archive_root_name, extracted_archive
+from gardener.common.utils import RepositoryError
+from gardener.persistence.file import FilePersistence
+
+SOURCES = {
+    "go.mod": "module example.com/widgets\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n",
+    "main.go": 'package main\n\nimport "github.com/pkg/errors"\n',
+}
+
+
+def _write_tar(path, files, mode="w:gz"):
+    with tarfile.open(path, mode) as archive:
+        for name, text in files.items():
+            data = text.encode()
+            info = tarfile.TarInfo(name)
+            info.size = len(data)
+            archive.addfile(info, io.BytesIO(data))
+    return str(path)
+
+
+def _write_zip(path, files):
+    with zipfile.ZipFile(path, "w") as archive:
+        for name, text in files.items():
+            archive.writestr(name, text)
+    return str(path)
+
+
+@pytest.mark.unit
+def test_archives_are_detected_by_content_not_name(tmp_path):
+    gzip_tar = _write_tar(tmp_path / "source.bin", SOURCES)
+    plain_tar = _write_tar(tmp_path / "source.tar", SOURCES, mode="w")
+    zip_file = _write_zip(tmp_path / "source", SOURCES)
+    (tmp_path / "fake.zip").write_text("not an archive")
+
+    assert archive_format(gzip_tar) == "tar.gz"
+    assert archive_format(plain_tar) == "tar"
+    assert archive_format(zip_file) == "zip"
+    assert archive_format(str(tmp_path / "fake.zip")) is None
+    assert archive_format(str(tmp_path)) is None
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "name, root",
+    [("widgets-1.2.0.tar.gz", "widgets-1.2.0"), ("widgets.TGZ", "widgets"), ("widgets.zip", "widgets"), ("src", "src")],
+)
+def test_root_name_drops_the_archive_suffix(name, root):
+    assert archive_root_name(f"dist/{name}") == root
+
+
+@pytest.mark.unit
+def test_tarball_is_analyzed_under_the_archive_name(tmp_path, offline_mode):
+    nested = {f"widgets-abc123/{name}": text for name, text in SOURCES.items()}
+    archive = _write_tar(tmp_path / "widgets.tar.gz", nested)
+    persistence = FilePersistence(output_dir=str(tmp_path / "out"), verbose=False)
+
+    with offline_mode.set_responses({}):
+        results = run_analysis(archive, persistence=persistence, output_format="cyclonedx")
+
+    assert results["analysis_root"] == {"name": "widgets", "archive": "widgets.tar.gz", "format": "tar.gz"}
+    assert "github.com/pkg/errors" in results["external_packages"]
+    assert "main.go" in results["analyzer_details"]["file_imports"]
+    with open(tmp_path / "out" / "widgets_sbom.cdx.json") as handle:
+        assert json.load(handle)["metadata"]["component"]["name"] == "widgets"
+
+
+@pytest.mark.unit
+def test_extraction_directory_is_removed_even_on_error(tmp_path):
+    archive = _write_zip(tmp_path / "widgets.zip", SOURCES)
+
+    with pytest.raises(RuntimeError):
+        with extracted_archive(archive) as root:
+            assert sorted(os.listdir(root)) == ["go.mod", "main.go"]
+            raise RuntimeError("analysis failed")
+
+    assert not os.path.exists(os.path.dirname(root))
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("entry", ["../escape.go", "/etc/escape.go", "src/../../escape.go", "C:\\escape.go"])
+def test_entries_outside_the_extraction_directory_are_rejected(tmp_path, entry):
+    for archive in (_write_zip(tmp_path / "evil.zip", {entry: "x"}), _write_tar(tmp_path / "evil.tgz", {entry: "x"})):
+        with pytest.raises(RepositoryError, match="escapes the extraction directory"):
+            with extracted_archive(archive):
+                pass
+    assert not (tmp_path.parent / "escape.go").exists()
+
+
+@pytest.mark.unit
+def test_links_out_of_the_archive_are_rejected(tmp_path):
+    path = tmp_path / "links.tar"
+    with tarfile.open(path, "w") as archive:
+        link = tarfile.TarInfo("src/passwd")
+        link.type = tarfile.SYMTYPE
+        link.linkname = "../../etc/passwd"
+        archive.addfile(link)
+
+    with pytest.raises(RepositoryError):
+        with extracted_archive(str(path)):
+            pass
+
+
+@pytest.mark.unit
+def test_cli_rejects_corrupt_archives(tmp_path, monkeypatch):
+    corrupt = tmp_path / "broken.tar.gz"
+    corrupt.write_bytes(b"\x1f\x8b" + b"\x00" * 64)
+    monkeypatch.setattr(sys, "argv", ["gardener", str(corrupt), "--no-cache"])
+
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == 2
@@ -0,0 +1,83 @@
+"""
+Cancelling an analysis part-way through
+"""
+
+import os
+import signal
+
+import pytest
+
+from gardener.api import AnalysisOptions, CancellationToken, analyze_repo
+from gardener.common.cancellation import cancel_on_interrupt
+
+
+def _make_repo(root):
+    (root / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\n"
+        "require (\n\tgithub.com/pkg/errors v0.9.1\n\tgolang.org/x/net v0.17.0\n)\n"
+    )
+    (root / "a.go").write_text('package main\n\nimport "github.com/pkg/errors"\n')
+    (root / "b.go").write_text('package main\n\nimport "golang.org/x/net/html"\n')
+    (root / "c.go").write_text('package main\n\nimport "fmt"\n')
+
+
+@pytest.mark.unit
+def test_cancelling_during_extraction_keeps_the_files_already_done(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+    token = CancellationToken()
+    extracted = []
+
+    def _on_file(record):
+        extracted.append(record["file"])
+        token.cancel()
+
+    options = AnalysisOptions(
+        offline=True, config_overrides={"ANALYSIS_JOBS": 1}, on_file_evidence=_on_file, cancellation=token
+    )
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), options)
+
+    assert result.partial and result.raw["partial"] is True
+    assert extracted == ["a.go"]
+    assert list(result.raw["analyzer_details"]["file_imports"]) == ["a.go"]
+    assert set(result.external_packages) == {"github.com/pkg/errors", "golang.org/x/net"}
+
+
+@pytest.mark.unit
+def test_cancelled_before_resolution_skips_every_lookup(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+    token = CancellationToken()
+    token.cancel()
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, cancellation=token))
+
+    assert result.partial
+    assert {info["resolution"]["reason"] for info in result.external_packages.values()} == {"cancelled"}
+    assert result.raw["analyzer_details"]["file_imports"] == {}
+    assert result.raw["summary"]["external_packages"] == 2
+
+
+@pytest.mark.unit
+def test_complete_analysis_is_not_partial(tmp_path, offline_mode):
+    _make_repo(tmp_path)
+
+    with offline_mode.set_responses({}):
+        result = analyze_repo(str(tmp_path), AnalysisOptions(offline=True, cancellation=CancellationToken()))
+
+    assert not result.partial
+    assert "partial" not in result.raw
+
+
+@pytest.mark.unit
+def test_first_interrupt_cancels_and_second_one_aborts():
+    token = CancellationToken()
+    previous = signal.getsignal(signal.SIGINT)
+
+    with pytest.raises(KeyboardInterrupt):
+        with cancel_on_interrupt(token):
+            os.kill(os.getpid(), signal.SIGINT)
+            assert token.cancelled
+            os.kill(os.getpid(), signal.SIGINT)
+
+    assert signal.getsignal(signal.SIGINT) is previous
@@ -0,0 +1,49 @@
+"""
+Decoding of source files with a byte order mark or invalid UTF-8
+"""
+
+import codecs
+
+import pytest
+
+from gardener.common.file_helpers import decode_text, normalize_line_endings, read_file_content, safe_json_load
+from gardener.common.secure_file_ops import SecureFileOps
+
+
+class _Recorder:
+    def __init__(self):
+        self.warnings = []
+
+    def warning(self, message, **fields):
+        self.warnings.append((message, fields))
+
+
+@pytest.mark.unit
+def test_bom_is_dropped_and_invalid_bytes_are_replaced():
+    logger = _Recorder()
+
+    assert decode_text(codecs.BOM_UTF8 + b"package main\n", "main.go", logger) == "package main\n"
+    assert logger.warnings == []
+    assert decode_text("// Olá\n".encode("latin-1"), "old.go", logger) == "// Ol�\n"
+    message, fields = logger.warnings[0]
+    assert message.startswith("old.go is not valid UTF-8")
+    assert fields == {"event": "file-decoded-lossy", "path": "old.go"}
+
+
+@pytest.mark.unit
+def test_readers_tolerate_bom_and_latin1(tmp_path):
+    (tmp_path / "package.json").write_bytes(codecs.BOM_UTF8 + b'{"name": "app"}')
+    (tmp_path / "notes.go").write_bytes("// señor\npackage notes\n".encode("latin-1"))
+    secure_file_ops = SecureFileOps(str(tmp_path))
+
+    assert safe_json_load(str(tmp_path / "package.json")) == {"name": "app"}
+    assert safe_json_load("package.json", secure_file_ops) == {"name": "app"}
+    assert read_file_content(str(tmp_path / "notes.go")).endswith("package notes\n")
+    assert secure_file_ops.read_file("notes.go") == "// se�or\npackage notes\n"
+
+
+@pytest.mark.unit
+def test_crlf_and_lone_cr_line_endings_become_lf():
+    assert normalize_line_endings('import (\r\n\t"gin"\r\n)\r\n') == 'import (\n\t"gin"\n)\n'
+    assert normalize_line_endings("a\r\nb\nc\rd\r\r\n") == "a\nb\nc\nd\n\n"
+    assert normalize_line_endings("package main\n") == "package main\n"
@@ -0,0 +1,39 @@
+"""
+Go standard library classification
+"""
+
+import pytest
+
+from gardener.common.go_stdlib import go_stdlib_deprecation, is_go_stdlib
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("path", ["fmt", "os", "net/http", "io/ioutil", "encoding/json", "context"])
+def test_fixture_stdlib_imports_are_recognized(path):
+    assert is_go_stdlib(path)
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "path",
+    ["github.com/gin-gonic/gin", "golang.org/x/sys/unix", "C", "internal/poll", "mycompany/lib", "net/http/v2"],
+)
+def test_non_stdlib_imports_are_rejected(path):
+    assert not is_go_stdlib(path)
+
+
+@pytest.mark.unit
+def test_release_gating_uses_introducing_version():
+    assert is_go_stdlib("slices", go_version="1.21")
+    assert is_go_stdlib("slices", go_version="go1.22.3")
+    assert not is_go_stdlib("slices", go_version="1.18")
+    assert is_go_stdlib("fmt", go_version="1.0")
+
+
+@pytest.mark.unit
+def test_deprecations_apply_from_the_deprecating_release():
+    notice = {"import": "io/ioutil", "deprecated_since": "1.16", "suggested_replacement": "io, os"}
+    assert go_stdlib_deprecation("io/ioutil") == notice
+    assert go_stdlib_deprecation("io/ioutil", go_version="go1.21.0") == notice
+    assert go_stdlib_deprecation("io/ioutil", go_version="1.15") is None
+    assert go_stdlib_deprecation("io", go_version="1.21") is None
@@ -0,0 +1,128 @@
+"""
+Leveled, structured logging to stderr
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.common.utils import Logger, configure_logging, verbosity_log_level
+from gardener.package_metadata import url_resolver
+
+
+@pytest.fixture(autouse=True)
+def reset_logging():
+    yield
+    configure_logging()
+
+
+def _make_repo(root):
+    root.mkdir()
+    (root / "go.mod").write_text("module example.com/app\n\ngo 1.21\n\nrequire golang.org/x/net v0.17.0\n")
+    (root / "main.go").write_text('package main\n\nimport "golang.org/x/net/html"\n')
+    (root / ".gitignore").write_text("build/\n")
+    (root / "build").mkdir()
+    (root / "build" / "gen.go").write_text("package build\n")
+    return str(root)
+
+
+def _records(err):
+    return [json.loads(line) for line in err.splitlines() if line.strip()]
+
+
+@pytest.mark.unit
+def test_records_go_to_stderr_at_the_configured_level(capsys):
+    logger = Logger()
+    logger.info("starting", event="run-started")
+    logger.debug("hidden")
+
+    captured = capsys.readouterr()
+    assert captured.out == ""
+    assert captured.err == "starting [event=run-started]\n"
+
+    Logger(verbose=True).debug("shown")
+    configure_logging("warning")
+    Logger(verbose=True).info("quiet")
+    assert capsys.readouterr().err == "... Debug: shown\n"
+
+
+@pytest.mark.unit
+def test_json_records_carry_their_fields(capsys):
+    configure_logging("trace", "json")
+    logger = Logger(name="resolver")
+
+    logger.trace("GET https://proxy.golang.org", event="http-request", status=200)
+    try:
+        raise RuntimeError("boom")
+    except RuntimeError:
+        logger.exception("lookup failed", package="left-pad")
+
+    first, second = _records(capsys.readouterr().err)
+    assert first["level"] == "trace"
+    assert first["logger"] == "resolver"
+    assert (first["event"], first["status"], first["message"]) == ("http-request", 200, "GET https://proxy.golang.org")
+    assert first["time"].endswith("Z")
+    assert second["level"] == "error"
+    assert second["package"] == "left-pad"
+    assert "RuntimeError: boom" in second["traceback"]
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("verbosity, level", [(0, None), (1, "debug"), (2, "trace"), (3, "trace")])
+def test_verbosity_maps_to_levels(verbosity, level):
+    assert verbosity_log_level(verbosity) == level
+
+
+@pytest.mark.unit
+def test_unknown_levels_and_formats_are_rejected():
+    with pytest.raises(ValueError):
+        configure_logging("chatty")
+    with pytest.raises(ValueError):
+        configure_logging(None, "xml")
+
+
+@pytest.mark.unit
+def test_cli_debug_records_explain_skips_resolutions_and_retries(tmp_path, monkeypatch, capsys):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+
+    def _unavailable(url):
+        raise OSError("connection reset")
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _unavailable)
+    argv = [repo, "--no-cache", "-l", "go", "--max-retries", "1", "--retry-base-delay", "0"]
+    monkeypatch.setattr(sys, "argv", ["gardener", *argv, "-vv", "--log-format", "json"])
+    with pytest.raises(SystemExit):
+        main_cli.main()
+
+    captured = capsys.readouterr()
+    assert captured.out == ""
+    records = _records(captured.err)
+    events = {}
+    for record in records:
+        events.setdefault(record.get("event"), []).append(record)
+
+    assert {"path": "build", "reason": "gitignore"}.items() <= events["path-skipped"][0].items()
+    assert any(record["attempt"] == 2 for record in events["http-request"])
+    assert events["http-retry"][0]["delay"] == 0
+    unresolved = {record["package"]: record for record in events["url-unresolved"]}
+    assert unresolved["golang.org/x/net"]["network_errors"] > 0
+    # The exit record stays the last line
+    assert records[-1]["error_code"] == "network-failure"
+
+
+@pytest.mark.unit
+def test_cli_log_level_overrides_verbosity(tmp_path, monkeypatch, capsys, offline_mode):
+    repo = _make_repo(tmp_path / "repo")
+    monkeypatch.chdir(tmp_path)
+    monkeypatch.setattr(sys, "argv", ["gardener", repo, "--offline", "--no-cache", "-v", "--log-level", "warning"])
+
+    with offline_mode.set_responses({}):
+        main_cli.main()
+
+    captured = capsys.readouterr()
+    assert captured.out == ""
+    assert "Analyzing repository" not in captured.err
+    assert "... Debug:" not in captured.err
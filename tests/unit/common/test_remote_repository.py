@@ -0,0 +1,171 @@
+"""
+Analyzing a remote git repository URL from a temporary clone
+"""
+
+import base64
+import os
+import sys
+
+import git
+import pytest
+
+from gardener import main_cli
+from gardener.analysis.main import run_analysis
+from gardener.common.utils import RepositoryCloneError, cloned_repository, is_repository_url, split_repository_ref
+from gardener.persistence.file import FilePersistence
+
+
+class _FakeGit:
+    def __init__(self, calls):
+        self.calls = calls
+        self.env = None
+
+    def custom_environment(self, **env):
+        self.env = env
+        return _NullContext()
+
+    def fetch(self, *args, **kwargs):
+        self.calls.append(("fetch", args, kwargs, self.env))
+
+    def checkout(self, *args):
+        self.calls.append(("checkout", args))
+
+
+class _NullContext:
+    def __enter__(self):
+        return self
+
+    def __exit__(self, *exc):
+        return False
+
+
+class _FakeRepo:
+    def __init__(self, calls):
+        self.git = _FakeGit(calls)
+
+
+@pytest.fixture
+def clones(monkeypatch):
+    """Replace git clones with a checkout of a one-file Go module, recording each call"""
+    calls = []
+    monkeypatch.delenv("GITHUB_TOKEN", raising=False)
+
+    def _clone_from(url, to_path, env=None, **kwargs):
+        calls.append(("clone", url, to_path, env, kwargs))
+        os.makedirs(to_path)
+        with open(os.path.join(to_path, "go.mod"), "w") as handle:
+            handle.write("module github.com/acme/widgets\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n")
+        with open(os.path.join(to_path, "main.go"), "w") as handle:
+            handle.write('package main\n\nimport "github.com/pkg/errors"\n')
+        return _FakeRepo(calls)
+
+    monkeypatch.setattr(git.Repo, "clone_from", staticmethod(_clone_from))
+    return calls
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "repo_input, expected",
+    [
+        ("https://github.com/acme/widgets", ("https://github.com/acme/widgets", None)),
+        ("https://github.com/acme/widgets@v1.2.0", ("https://github.com/acme/widgets", "v1.2.0")),
+        ("https://github.com/acme/widgets.git@release/2.x", ("https://github.com/acme/widgets.git", "release/2.x")),
+        ("https://user@gitlab.com/acme/widgets", ("https://user@gitlab.com/acme/widgets", None)),
+        ("./widgets@v1", ("./widgets@v1", None)),
+    ],
+)
+def test_refs_are_split_off_the_url_path(repo_input, expected):
+    assert split_repository_ref(repo_input) == expected
+
+
+@pytest.mark.unit
+def test_url_shaped_inputs_are_told_apart_from_local_paths(tmp_path):
+    assert is_repository_url("https://github.com/acme/widgets@main")
+    assert not is_repository_url(str(tmp_path))
+    assert not is_repository_url("widgets")
+
+
+@pytest.mark.unit
+def test_url_is_analyzed_from_a_temporary_clone_that_is_removed(tmp_path, clones, offline_mode):
+    persistence = FilePersistence(output_dir=str(tmp_path / "out"), verbose=False)
+
+    with offline_mode.set_responses({}):
+        results = run_analysis("https://github.com/acme/widgets@v1.2.0", persistence=persistence)
+
+    (_, url, to_path, env, options) = clones[0]
+    assert url == "https://github.com/acme/widgets.git"
+    assert options == {"no_checkout": False, "depth": 1, "branch": "v1.2.0"}
+    assert os.path.basename(to_path) == "acme_widgets"
+    assert env == {"GIT_TERMINAL_PROMPT": "0"}
+    assert "github.com/pkg/errors" in results["external_packages"]
+    assert (tmp_path / "out" / "acme_widgets_dependency_analysis.json").exists()
+    assert not os.path.exists(os.path.dirname(to_path))
+
+
+@pytest.mark.unit
+def test_temporary_clone_is_removed_when_analysis_fails(tmp_path, clones, monkeypatch):
+    from gardener import api
+
+    def _crash(*args, **kwargs):
+        raise RuntimeError("analyzer crashed")
+
+    monkeypatch.setattr(api, "analyze_repo", _crash)
+    with pytest.raises(RuntimeError):
+        run_analysis("https://github.com/acme/widgets", persistence=FilePersistence(output_dir=str(tmp_path)))
+
+    assert not os.path.exists(os.path.dirname(clones[0][2]))
+
+
+@pytest.mark.unit
+def test_failed_clone_leaves_nothing_behind(monkeypatch):
+    created = []
+
+    def _unreachable(url, to_path, env=None, **kwargs):
+        created.append(to_path)
+        os.makedirs(to_path)
+        raise git.GitCommandError("clone", 128)
+
+    monkeypatch.setattr(git.Repo, "clone_from", staticmethod(_unreachable))
+    with pytest.raises(RepositoryCloneError):
+        with cloned_repository("https://gitlab.com/acme/widgets"):
+            pass
+
+    assert len(created) == 1
+    assert not os.path.exists(os.path.dirname(created[0]))
+
+
+@pytest.mark.unit
+def test_commits_are_fetched_with_the_auth_token(clones, monkeypatch):
+    commit = "0123456789abcdef0123456789abcdef01234567"
+    monkeypatch.setenv("GITHUB_TOKEN", "s3cret")
+
+    with cloned_repository(f"https://github.com/acme/widgets@{commit}", depth=0):
+        pass
+
+    clone, fetch, checkout = clones
+    expected_header = "Authorization: Basic " + base64.b64encode(b"x-access-token:s3cret").decode()
+    assert clone[3]["GIT_CONFIG_VALUE_0"] == expected_header
+    assert all("s3cret" not in str(arg) for arg in clone[1:3])
+    assert clone[4] == {"no_checkout": False}
+    assert fetch[1] == ("origin", commit) and fetch[3]["GIT_CONFIG_KEY_0"] == "http.extraHeader"
+    assert checkout == ("checkout", ("FETCH_HEAD",))
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "argv",
+    [
+        ["https://github.com/acme/widgets@v1", "--branch", "v2"],
+        ["https://github.com/acme/widgets", "--depth", "-1"],
+        [".", "--depth", "5"],
+        [".", "--branch", "main"],
+    ],
+)
+def test_cli_rejects_conflicting_clone_options(argv, clones, monkeypatch):
+    monkeypatch.setattr(sys, "argv", ["gardener", *argv, "--no-cache"])
+
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+
+    assert excinfo.value.code == 2
+    assert clones == []
@@ -7,7 +7,20 @@ from collections import defaultdict
 
 import pytest
 
-from gardener.treewalk.go import GoLanguageHandler
+from gardener.common.defaults import ConfigOverride
+from gardener.treewalk.go import (
+    GoLanguageHandler,
+    find_go_module_for_import,
+    is_go_generated_file,
+    parse_cgo_directives,
+    parse_cgo_native_dependencies,
+    parse_go_build_constraints,
+    parse_go_mod,
+    parse_go_mod_file,
+    parse_go_sum,
+    parse_vendor_modules_txt,
+)
+from tests.support.go_imports import SEED_CORPUS, extract_go_import_specs
 
 
 def mock_resolve_local_go(importing_file_rel_path, module_str):
@@ -63,3 +76,659 @@ def test_main_go_fixture_imports_extracted(tree_parser, logger):
     file_key = f"tests/fixtures/go/{fixture_rel_path}"
     assert file_key in components_dict
     assert ("github.com/gin-gonic/gin", "github.com/gin-gonic/gin.gin") in set(components_dict[file_key])
+
+
+def test_parse_go_mod_reads_block_and_single_line_requires():
+    """Both require forms are parsed; comments and other directives are ignored"""
+    content = """module example.com/app
+
+go 1.21
+
+require github.com/pkg/errors v0.9.1 // indirect
+
+require (
+    // leading comment
+    github.com/gin-gonic/gin v1.9.1
+    github.com/jackc/pgx/v5 v5.5.0 // indirect
+)
+
+exclude github.com/old/thing v1.0.0
+"""
+    assert parse_go_mod(content) == {
+        "github.com/pkg/errors": "v0.9.1",
+        "github.com/gin-gonic/gin": "v1.9.1",
+        "github.com/jackc/pgx/v5": "v5.5.0",
+    }
+
+
+def test_parse_go_mod_file_reads_indirect_comments():
+    """Only requires whose comment starts with `indirect` are indirect"""
+    content = """module example.com/app
+
+require github.com/pkg/errors v0.9.1 // indirect
+
+require (
+    github.com/gin-gonic/gin v1.9.1 // pinned for CVE fix
+    github.com/jackc/pgx/v5 v5.5.0 // indirect; pulled in by sqlc
+    golang.org/x/text v0.14.0 //indirect
+)
+"""
+    assert parse_go_mod_file(content)["indirect"] == [
+        "github.com/pkg/errors",
+        "github.com/jackc/pgx/v5",
+        "golang.org/x/text",
+    ]
+
+
+def test_parse_go_mod_file_reads_go_and_toolchain_directives():
+    """`go` and `toolchain` are captured verbatim; missing directives stay empty"""
+    parsed = parse_go_mod_file("module example.com/app\n\ngo 1.21\n\ntoolchain go1.22.3 // pinned\n")
+    assert (parsed["go"], parsed["toolchain"]) == ("1.21", "go1.22.3")
+
+    parsed = parse_go_mod_file("module example.com/app\n\ngo 1.22.0\n")
+    assert (parsed["go"], parsed["toolchain"]) == ("1.22.0", "")
+
+
+def test_go_mod_requires_are_marked_direct_unless_indirect(tmp_path, logger):
+    """Single-line and grouped requires both carry `direct`, false only for `// indirect` ones"""
+    go_mod = tmp_path / "go.mod"
+    go_mod.write_text(
+        "module example.com/app\n\n"
+        "require github.com/pkg/errors v0.9.1 // indirect\n"
+        "require github.com/spf13/cobra v1.8.0\n\n"
+        "require (\n"
+        "    github.com/gin-gonic/gin v1.9.1 // pinned for CVE fix\n"
+        "    github.com/jackc/pgx/v5 v5.5.0 // indirect\n"
+        ")\n"
+    )
+    packages = GoLanguageHandler(logger=logger).process_manifest(str(go_mod), {})
+
+    assert {module_path: info["direct"] for module_path, info in packages.items()} == {
+        "github.com/pkg/errors": False,
+        "github.com/spf13/cobra": True,
+        "github.com/gin-gonic/gin": True,
+        "github.com/jackc/pgx/v5": False,
+    }
+
+
+def test_find_go_module_for_import_matches_on_segment_boundaries():
+    """Longest module prefix wins and partial segments never match"""
+    modules = ["github.com/jackc/pgx", "github.com/jackc/pgx/v5", "github.com/a/b"]
+
+    assert find_go_module_for_import("github.com/jackc/pgx/v5/pgxpool", modules) == "github.com/jackc/pgx/v5"
+    assert find_go_module_for_import("github.com/jackc/pgx/pgtype", modules) == "github.com/jackc/pgx"
+    assert find_go_module_for_import("github.com/a/bc", modules) is None
+
+
+def test_sub_package_imports_inherit_the_version_of_their_module(tmp_path, tree_parser, logger):
+    """Only the module is required in go.mod; each of its packages gets its version"""
+    go_mod = tmp_path / "go.mod"
+    go_mod.write_text(
+        "module example.com/app\n\n"
+        "require (\n"
+        "\tgoogle.golang.org/grpc v1.60.1\n"
+        "\tgithub.com/a/b v1.0.0\n"
+        "\tgithub.com/jackc/pgx v3.6.2+incompatible\n"
+        "\tgithub.com/jackc/pgx/v5 v5.5.0\n"
+        ")\n"
+    )
+    handler = GoLanguageHandler(logger=logger)
+    handler.process_manifest(str(go_mod), {})
+    code = """package main
+
+import (
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials/insecure"
+    "github.com/a/bc"
+    "github.com/jackc/pgx/v5/pgxpool"
+    "github.com/jackc/pgx/pgtype"
+)
+"""
+    evidence = defaultdict(list)
+    handler.extract_imports(
+        tree_parser("go", code), "main.go", defaultdict(list), mock_resolve_local_go, import_evidence_dict=evidence
+    )
+
+    modules = {entry["import"]: (entry["module"], entry["version"]) for entry in evidence["main.go"]}
+    assert modules == {
+        "google.golang.org/grpc": ("google.golang.org/grpc", "v1.60.1"),
+        "google.golang.org/grpc/credentials/insecure": ("google.golang.org/grpc", "v1.60.1"),
+        "github.com/a/bc": ("", ""),
+        "github.com/jackc/pgx/v5/pgxpool": ("github.com/jackc/pgx/v5", "v5.5.0"),
+        "github.com/jackc/pgx/pgtype": ("github.com/jackc/pgx", "v3.6.2+incompatible"),
+    }
+
+
+def test_main_go_fixture_import_evidence_carries_go_mod_versions(tree_parser, logger):
+    """Imports covered by go.mod get its version; unmatched imports keep an empty version"""
+    fixture_dir = "tests/fixtures/go"
+    with open(os.path.join(fixture_dir, "main.go"), "r") as f:
+        root_node = tree_parser("go", f.read())
+
+    handler = GoLanguageHandler(logger=logger)
+    packages = handler.process_manifest(os.path.join(fixture_dir, "go.mod"), {})
+    assert packages["github.com/gin-gonic/gin"]["version"] == "v1.7.7"
+
+    evidence = defaultdict(list)
+    handler.extract_imports(
+        root_node,
+        "main.go",
+        defaultdict(list),
+        mock_resolve_local_go,
+        import_evidence_dict=evidence,
+    )
+
+    versions = {entry["import"]: entry["version"] for entry in evidence["main.go"]}
+    assert versions["github.com/gin-gonic/gin"] == "v1.7.7"
+    assert versions["github.com/smartystreets/goconvey/convey"] == "v1.7.2"
+    assert versions["fmt"] == ""
+
+    scopes = {entry["import"]: entry["scope"] for entry in evidence["main.go"]}
+    assert scopes["fmt"] == "stdlib"
+    assert scopes["io/ioutil"] == "stdlib"
+    assert scopes["github.com/gin-gonic/gin"] == "external"
+
+
+@pytest.mark.parametrize(
+    "header,expected",
+    [
+        ("//go:build linux\n\npackage p\n", ("linux", ["linux"])),
+        ("//go:build linux && amd64\n\npackage p\n", ("linux && amd64", ["linux", "amd64"])),
+        (
+            "//go:build (linux || darwin) && !cgo\n\npackage p\n",
+            ("(linux || darwin) && !cgo", ["linux", "darwin", "!cgo"]),
+        ),
+        ("// +build linux,amd64 darwin\n\npackage p\n", ("(linux && amd64) || darwin", ["linux", "amd64", "darwin"])),
+        (
+            "// +build linux\n// +build amd64 arm64\n\npackage p\n",
+            ("linux && (amd64 || arm64)", ["linux", "amd64", "arm64"]),
+        ),
+        # Without a blank line the +build comment is package documentation, not a constraint
+        ("// +build linux\npackage p\n", ("", [])),
+        # //go:build wins over legacy lines
+        ("//go:build windows\n// +build linux\n\npackage p\n", ("windows", ["windows"])),
+        # Constraints after the package clause are ignored
+        ("package p\n\n//go:build linux\n", ("", [])),
+        ("package p\n", ("", [])),
+    ],
+)
+def test_parse_go_build_constraints(header, expected):
+    """Header rules of go/build are honored for both constraint syntaxes"""
+    assert parse_go_build_constraints(header) == expected
+
+
+def test_import_evidence_carries_build_tags(tree_parser, logger):
+    """Imports in constrained files are labeled; unconstrained files report empty tags"""
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    sources = {
+        "sys_linux.go": '//go:build linux && amd64\n\npackage sys\n\nimport "golang.org/x/sys/unix"\n',
+        "plain.go": 'package sys\n\nimport "github.com/pkg/errors"\n',
+    }
+    for rel_path, code in sources.items():
+        handler.extract_imports(
+            tree_parser("go", code),
+            rel_path,
+            defaultdict(list),
+            mock_resolve_local_go,
+            import_evidence_dict=evidence,
+        )
+
+    assert evidence["sys_linux.go"][0]["import"] == "golang.org/x/sys/unix"
+    assert evidence["sys_linux.go"][0]["build_tags"] == ["linux", "amd64"]
+    assert evidence["sys_linux.go"][0]["build_constraint"] == "linux && amd64"
+    assert evidence["plain.go"][0]["build_tags"] == []
+
+
+@pytest.mark.parametrize(
+    "header,expected",
+    [
+        ("// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage pb\n", True),
+        ("// Copyright 2024 Example\n\n// Code generated by mockgen. DO NOT EDIT.\npackage mocks\n", True),
+        ("//go:build linux\n\n// Code generated by stringer; DO NOT EDIT.\n\npackage p\n", True),
+        # Near misses of the exact pattern
+        ("// Code generated by hand. DO NOT EDIT\n\npackage p\n", False),
+        ("// Code generated DO NOT EDIT.\n\npackage p\n", False),
+        ("// code generated by tool. DO NOT EDIT.\n\npackage p\n", False),
+        ("// Code generated by tool. DO NOT EDIT. Really.\n\npackage p\n", False),
+        ("/* Code generated by tool. DO NOT EDIT. */\npackage p\n", False),
+        ("// This file was Code generated by tool. DO NOT EDIT.\npackage p\n", False),
+        # The marker only counts before the package clause
+        ("package p\n\n// Code generated by tool. DO NOT EDIT.\n", False),
+    ],
+)
+def test_is_go_generated_file(header, expected):
+    """Only Go's exact generated-code marker in the file header counts"""
+    assert is_go_generated_file(header) is expected
+
+
+def test_generated_file_imports_are_tagged(tree_parser, logger):
+    """Every import of a generated file is tagged; hand-written files carry no tag"""
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    sources = {
+        "api.pb.go": (
+            "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n\n"
+            'import (\n\t"google.golang.org/protobuf/proto"\n\t"./internal"\n)\n'
+        ),
+        "api.go": 'package api\n\nimport "github.com/pkg/errors"\n',
+    }
+    for rel_path, code in sources.items():
+        handler.extract_imports(
+            tree_parser("go", code),
+            rel_path,
+            defaultdict(list),
+            mock_resolve_local_go,
+            import_evidence_dict=evidence,
+            go_module_path="example.com/api",
+        )
+
+    assert [entry.get("generated") for entry in evidence["api.pb.go"]] == [True, True]
+    assert "generated" not in evidence["api.go"][0]
+
+
+def test_commented_out_imports_are_not_reported(tree_parser, logger):
+    """Line and block comments never yield imports; trailing comments do not hide real ones"""
+    handler = GoLanguageHandler(logger=logger)
+    results = {}
+    for rel_path in ("main.go", "comments/comments.go"):
+        with open(os.path.join("tests/fixtures/go", rel_path), "r") as f:
+            root_node = tree_parser("go", f.read())
+        external_imports, _ = handler.extract_imports(root_node, rel_path, defaultdict(list), mock_resolve_local_go)
+        results[rel_path] = set(external_imports)
+
+    assert "io/ioutil" in results["main.go"]
+    assert not {"archive/zip", "path/filepath"} & results["main.go"]
+
+    assert results["comments/comments.go"] == {"strings", "unicode/utf8", "sort", "bytes"}
+
+
+def test_parse_cgo_directives_reads_pkg_config_and_ldflags():
+    """pkg-config names and -l flags are extracted; other flags are ignored"""
+    preamble = """// #cgo pkg-config: libssl libcrypto
+// #cgo linux LDFLAGS: -L/usr/lib -lz -l pthread
+// #cgo CFLAGS: -I/usr/include
+// #include <zlib.h>"""
+
+    assert parse_cgo_directives(preamble) == [
+        ("libssl", "cgo-pkg-config"),
+        ("libcrypto", "cgo-pkg-config"),
+        ("z", "cgo-ldflags"),
+        ("pthread", "cgo-ldflags"),
+    ]
+
+
+def test_cgo_import_is_not_an_external_package(tree_parser, logger):
+    """import "C" is dropped and its preamble surfaces native dependencies"""
+    code = """package native
+
+// #cgo pkg-config: libssl
+// #cgo LDFLAGS: -lz
+// #include <openssl/ssl.h>
+import "C"
+
+import "fmt"
+"""
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    components = defaultdict(list)
+    external_imports, _ = handler.extract_imports(
+        tree_parser("go", code), "native.go", components, mock_resolve_local_go, import_evidence_dict=evidence
+    )
+
+    assert external_imports == ["fmt"]
+    assert all(path != "C" for path, _ in components["native.go"])
+    native = [entry for entry in evidence["native.go"] if "native_dependency" in entry]
+    assert native == [
+        {"native_dependency": "libssl", "source": "cgo-pkg-config", "requires_cgo": True, "build_tags": ["cgo"]},
+        {"native_dependency": "z", "source": "cgo-ldflags", "requires_cgo": True, "build_tags": ["cgo"]},
+    ]
+    # Only built with cgo enabled, like the native libraries
+    assert [entry.get("requires_cgo") for entry in evidence["native.go"] if "import" in entry] == [True]
+
+
+def test_cgo_gated_files_tie_their_imports_to_the_native_dependencies(tree_parser, logger):
+    """A //go:build cgo file marks its imports and native libraries as requiring cgo"""
+    code = """//go:build cgo && linux
+
+package tls
+
+import "github.com/acme/fallback"
+
+// #cgo pkg-config: libssl
+// #cgo amd64 LDFLAGS: -lcrypto
+import "C"
+"""
+    pure = """//go:build !cgo
+
+package tls
+
+import "github.com/acme/fallback"
+"""
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    for rel_path, source in (("tls_cgo.go", code), ("tls_pure.go", pure)):
+        handler.extract_imports(
+            tree_parser("go", source), rel_path, defaultdict(list), mock_resolve_local_go, import_evidence_dict=evidence
+        )
+
+    assert [entry for entry in evidence["tls_cgo.go"] if "native_dependency" in entry] == [
+        {
+            "native_dependency": "libssl",
+            "source": "cgo-pkg-config",
+            "requires_cgo": True,
+            "build_tags": ["cgo", "linux"],
+        },
+        {
+            "native_dependency": "crypto",
+            "source": "cgo-ldflags",
+            "requires_cgo": True,
+            "build_tags": ["cgo", "linux", "amd64"],
+        },
+    ]
+    imports = [entry for entry in evidence["tls_cgo.go"] if entry.get("import") == "github.com/acme/fallback"]
+    assert imports[0]["requires_cgo"] is True
+    assert "requires_cgo" not in evidence["tls_pure.go"][0]
+    assert parse_cgo_native_dependencies("// #cgo linux,!arm64 LDFLAGS: -lz") == [
+        ("z", "cgo-ldflags", ["linux", "!arm64"])
+    ]
+
+
+def test_major_version_modules_keep_distinct_module_paths(tree_parser, logger):
+    """/v2 and /v3 imports map to their own modules; a /v2 subdirectory stays in its parent module"""
+    handler = GoLanguageHandler(logger=logger)
+    handler.module_versions = {
+        "github.com/foo/bar/v2": "v2.1.0",
+        "github.com/foo/bar/v3": "v3.0.0",
+        "example.com/tools": "v1.0.0",
+    }
+    code = """package p
+
+import (
+    "github.com/foo/bar/v2/client"
+    "github.com/foo/bar/v3"
+    "example.com/tools/v2"
+)
+"""
+    evidence = defaultdict(list)
+    handler.extract_imports(
+        tree_parser("go", code), "p.go", defaultdict(list), mock_resolve_local_go, import_evidence_dict=evidence
+    )
+
+    modules = {entry["import"]: (entry["module"], entry["version"]) for entry in evidence["p.go"]}
+    assert modules["github.com/foo/bar/v2/client"] == ("github.com/foo/bar/v2", "v2.1.0")
+    assert modules["github.com/foo/bar/v3"] == ("github.com/foo/bar/v3", "v3.0.0")
+    assert modules["example.com/tools/v2"] == ("example.com/tools", "v1.0.0")
+
+
+def test_parse_go_mod_file_reads_replace_directives():
+    """Block and single-line replaces are parsed, keyed by source path and optional version"""
+    content = """module example.com/app
+
+require (
+    github.com/foo/bar v1.0.0
+    github.com/foo/local v0.1.0
+)
+
+replace github.com/foo/bar => github.com/fork/bar v1.2.3
+
+replace (
+    github.com/foo/local => ./local/path
+    github.com/pinned/mod v1.1.0 => ../pinned // only this version
+)
+"""
+    parsed = parse_go_mod_file(content)
+
+    assert parsed["module"] == "example.com/app"
+    assert parsed["replace"] == {
+        "github.com/foo/bar": {"path": "github.com/fork/bar", "version": "v1.2.3", "local": False},
+        "github.com/foo/local": {"path": "./local/path", "version": "", "local": True},
+        "github.com/pinned/mod@v1.1.0": {"path": "../pinned", "version": "", "local": True},
+    }
+
+
+def test_replaced_modules_are_annotated_in_packages_and_evidence(tmp_path, tree_parser, logger):
+    """Replacements flow into the packages dict and into each matching import's evidence"""
+    go_mod = tmp_path / "go.mod"
+    go_mod.write_text(
+        "module example.com/app\n\n"
+        "require github.com/foo/bar v1.0.0\n"
+        "require github.com/foo/local v0.1.0\n\n"
+        "replace github.com/foo/bar => github.com/fork/bar v1.2.3\n"
+        "replace github.com/foo/local => ./local/path\n"
+    )
+    handler = GoLanguageHandler(logger=logger)
+    packages = handler.process_manifest(str(go_mod), {})
+    assert packages["github.com/foo/bar"]["replace"]["path"] == "github.com/fork/bar"
+    assert packages["github.com/foo/local"]["replace"]["local"] is True
+
+    code = 'package app\n\nimport (\n    "github.com/foo/bar/sub"\n    "github.com/foo/local"\n)\n'
+    evidence = defaultdict(list)
+    handler.extract_imports(
+        tree_parser("go", code), "app.go", defaultdict(list), mock_resolve_local_go, import_evidence_dict=evidence
+    )
+
+    by_import = {entry["import"]: entry for entry in evidence["app.go"]}
+    assert by_import["github.com/foo/bar/sub"]["replaced_from"] == "github.com/foo/bar"
+    assert by_import["github.com/foo/bar/sub"]["replaced_to"] == "github.com/fork/bar@v1.2.3"
+    assert by_import["github.com/foo/local"]["replaced_to"] == "./local/path"
+
+
+def test_parse_go_sum_prefers_zip_hash_over_go_mod_hash():
+    """Zip hashes win; a go.mod-only entry still yields its hash"""
+    content = """github.com/gin-gonic/gin v1.7.7 h1:3DoBmSbJbZAWqXJC3SLjAPfutPJJRN1U5pALB7EeTTs=
+github.com/gin-gonic/gin v1.7.7/go.mod h1:axIBovoeJpVj8S3BwE0uPMTeReE4+AfFtqpqaZ1qq1U=
+github.com/go-playground/locales v0.13.0/go.mod h1:taPMhCMXrRLJO55olJkUXHZBHCxTMfnGwq/HNwmWNS8=
+
+malformed line
+"""
+    assert parse_go_sum(content) == {
+        "github.com/gin-gonic/gin@v1.7.7": "h1:3DoBmSbJbZAWqXJC3SLjAPfutPJJRN1U5pALB7EeTTs=",
+        "github.com/go-playground/locales@v0.13.0": "h1:taPMhCMXrRLJO55olJkUXHZBHCxTMfnGwq/HNwmWNS8=",
+    }
+
+
+def test_go_sum_checksums_attach_to_packages_and_evidence(tmp_path, tree_parser, logger):
+    """Checksums from the sibling go.sum are attached; imports without an entry omit the field"""
+    (tmp_path / "go.mod").write_text("module example.com/app\n\nrequire github.com/gin-gonic/gin v1.7.7\n")
+    (tmp_path / "go.sum").write_text(
+        "github.com/gin-gonic/gin v1.7.7 h1:zip=\ngithub.com/gin-gonic/gin v1.7.7/go.mod h1:mod=\n"
+    )
+    handler = GoLanguageHandler(logger=logger)
+    packages = handler.process_manifest(str(tmp_path / "go.mod"), {})
+    assert packages["github.com/gin-gonic/gin"]["checksum"] == "h1:zip="
+
+    code = 'package app\n\nimport (\n    "fmt"\n    "github.com/gin-gonic/gin"\n)\n'
+    evidence = defaultdict(list)
+    handler.extract_imports(
+        tree_parser("go", code), "app.go", defaultdict(list), mock_resolve_local_go, import_evidence_dict=evidence
+    )
+
+    by_import = {entry["import"]: entry for entry in evidence["app.go"]}
+    assert by_import["github.com/gin-gonic/gin"]["checksum"] == "h1:zip="
+    assert "checksum" not in by_import["fmt"]
+
+
+def test_parse_vendor_modules_txt_reads_module_and_explicit_lines():
+    """Module headers, ## annotations, package lists and replacements are captured"""
+    content = """# github.com/pkg/errors v0.9.1
+## explicit
+github.com/pkg/errors
+# golang.org/x/sys v0.5.0
+## explicit; go 1.17
+golang.org/x/sys/unix
+golang.org/x/sys/windows
+# github.com/davecgh/go-spew v1.1.1
+github.com/davecgh/go-spew/spew
+# example.com/local v0.0.0 => ./local
+## explicit
+"""
+    modules = parse_vendor_modules_txt(content)
+
+    assert modules["github.com/pkg/errors"] == {
+        "version": "v0.9.1",
+        "explicit": True,
+        "go_version": "",
+        "packages": ["github.com/pkg/errors"],
+        "replace": None,
+    }
+    assert modules["golang.org/x/sys"]["go_version"] == "1.17"
+    assert modules["golang.org/x/sys"]["packages"] == ["golang.org/x/sys/unix", "golang.org/x/sys/windows"]
+    assert modules["github.com/davecgh/go-spew"]["explicit"] is False
+    assert modules["example.com/local"]["replace"] == {"path": "./local", "version": "", "local": True}
+
+
+def test_vendor_modules_txt_yields_vendored_packages(tmp_path, logger):
+    """Only modules.txt inside a vendor/ directory is treated as a Go manifest"""
+    vendor_dir = tmp_path / "vendor"
+    vendor_dir.mkdir()
+    (vendor_dir / "modules.txt").write_text("# github.com/pkg/errors v0.9.1\n## explicit\ngithub.com/pkg/errors\n")
+    (tmp_path / "modules.txt").write_text("# github.com/not/vendored v1.0.0\n")
+
+    handler = GoLanguageHandler(logger=logger)
+    packages = handler.process_manifest(str(vendor_dir / "modules.txt"), {})
+    assert packages["github.com/pkg/errors"]["version"] == "v0.9.1"
+    assert packages["github.com/pkg/errors"]["vendored"] is True
+    assert packages["github.com/pkg/errors"]["explicit"] is True
+
+    assert handler.process_manifest(str(tmp_path / "modules.txt"), {}) == {}
+
+
+def test_relative_imports_are_local_and_qualified_by_module_path(tree_parser, logger):
+    """./x imports resolve against the module path, are scoped local and never become external packages"""
+    source = 'package main\n\nimport (\n\t"./utils"\n\t"../shared"\n\t"./missing"\n\t"github.com/foo/bar"\n)\n'
+    root_node = tree_parser("go", source)
+
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    components = defaultdict(list)
+    imports, _ = handler.extract_imports(
+        root_node,
+        "cmd/app/main.go",
+        components,
+        lambda rel_path, module_str: None,
+        import_evidence_dict=evidence,
+        go_module_path="example.com/go-fixture",
+    )
+
+    assert imports == ["github.com/foo/bar"]
+    assert [package for package, _ in components["cmd/app/main.go"]] == ["github.com/foo/bar"]
+    local = {entry["import"]: entry for entry in evidence["cmd/app/main.go"] if entry["scope"] == "local"}
+    assert local["./utils"]["resolved_import"] == "example.com/go-fixture/cmd/app/utils"
+    assert local["../shared"]["resolved_import"] == "example.com/go-fixture/cmd/shared"
+    assert local["./missing"]["resolved_import"] == "example.com/go-fixture/cmd/app/missing"
+
+
+def test_relative_imports_without_module_path_stay_local(tree_parser, logger):
+    """Without a go.mod, relative imports are still local and carry no qualified path"""
+    root_node = tree_parser("go", 'package main\n\nimport "../../outside"\n')
+
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    imports, _ = handler.extract_imports(
+        root_node, "main.go", defaultdict(list), lambda rel_path, module_str: None, import_evidence_dict=evidence
+    )
+
+    assert imports == []
+    assert evidence["main.go"][0]["scope"] == "local"
+    assert evidence["main.go"][0]["resolved_import"] == ""
+
+
+def test_blank_database_driver_import_is_flagged(tree_parser, logger):
+    """`import _ "github.com/lib/pq"` registers a driver for side effects and is reported as a blank import"""
+    source = 'package main\n\nimport (\n\t"database/sql"\n\n\t_ "github.com/lib/pq"\n)\n'
+    root_node = tree_parser("go", source)
+
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    imports, _ = handler.extract_imports(
+        root_node, "db.go", defaultdict(list), lambda rel_path, module_str: None, import_evidence_dict=evidence
+    )
+
+    # Blank imports are still real runtime dependencies
+    assert "github.com/lib/pq" in imports
+    kinds = {entry["import"]: entry["import_kind"] for entry in evidence["db.go"]}
+    assert kinds == {"database/sql": "named", "github.com/lib/pq": "blank"}
+
+
+def test_main_go_fixture_distinguishes_import_kinds(tree_parser, logger):
+    """Dot, blank, aliased and plain imports in one file each get their own import_kind"""
+    with open(os.path.join("tests/fixtures/go", "main.go"), "r") as f:
+        root_node = tree_parser("go", f.read())
+
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    handler.extract_imports(
+        root_node, "main.go", defaultdict(list), mock_resolve_local_go, import_evidence_dict=evidence
+    )
+
+    kinds = {entry["import"]: entry["import_kind"] for entry in evidence["main.go"]}
+    assert kinds["github.com/smartystreets/goconvey/convey"] == "dot"
+    assert kinds["github.com/lib/pq"] == "blank"
+    assert kinds["github.com/sirupsen/logrus"] == "aliased"
+    assert kinds["github.com/gin-gonic/gin"] == "named"
+    assert kinds["fmt"] == "named"
+
+
+@pytest.mark.parametrize(
+    "source",
+    [
+        'package main\n\nimport log "github.com/sirupsen/logrus"\nimport "fmt"\n',
+        'package main\n\nimport (\n\tlog "github.com/sirupsen/logrus"\n\t"fmt"\n)\n',
+    ],
+)
+def test_aliased_import_records_alias(tree_parser, logger, source):
+    """The alias bound by `import log "..."` is captured; unaliased imports omit the field"""
+    root_node = tree_parser("go", source)
+
+    handler = GoLanguageHandler(logger=logger)
+    evidence = defaultdict(list)
+    handler.extract_imports(
+        root_node, "main.go", defaultdict(list), lambda rel_path, module_str: None, import_evidence_dict=evidence
+    )
+
+    by_import = {entry["import"]: entry for entry in evidence["main.go"]}
+    assert by_import["github.com/sirupsen/logrus"]["alias"] == "log"
+    assert "alias" not in by_import["fmt"]
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("source, expected", [seed[1:] for seed in SEED_CORPUS], ids=[seed[0] for seed in SEED_CORPUS])
+def test_import_syntax_variants_are_extracted(source, expected):
+    """Whitespace, comment placement, CRLF endings and literal forms do not change what is extracted"""
+    assert extract_go_import_specs(source) == expected
+
+
+def test_import_positions_point_at_each_spec(tree_parser, logger):
+    """With RECORD_IMPORT_POSITIONS, grouped specs report their own line and single imports their declaration"""
+    source = (
+        "package main\n\n// héllo\nimport \"fmt\"\n\nimport (\n"
+        '\t"github.com/pkg/errors"\n'
+        '\tpe "github.com/pkg/errors" /* alias */\n'
+        '\t_ "github.com/lib/pq"\n'
+        "\t. \"./util\"\n"
+        ")\n\nimport x \"github.com/sirupsen/logrus\"\n"
+    )
+    root_node = tree_parser("go", source)
+    handler = GoLanguageHandler(logger=logger)
+
+    evidence = defaultdict(list)
+    with ConfigOverride({"RECORD_IMPORT_POSITIONS": True}):
+        handler.extract_imports(
+            root_node, "main.go", defaultdict(list), mock_resolve_local_go, import_evidence_dict=evidence
+        )
+    positions = [(entry["import_kind"], entry["line"], entry["column"]) for entry in evidence["main.go"]]
+    assert positions == [
+        ("named", 4, 1),
+        ("named", 7, 2),
+        ("aliased", 8, 2),
+        ("blank", 9, 2),
+        ("dot", 10, 2),
+        ("aliased", 13, 1),
+    ]
+
+    evidence = defaultdict(list)
+    handler.extract_imports(
+        root_node, "main.go", defaultdict(list), mock_resolve_local_go, import_evidence_dict=evidence
+    )
+    assert not any("line" in entry or "column" in entry for entry in evidence["main.go"])
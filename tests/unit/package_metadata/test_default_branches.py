@@ -0,0 +1,110 @@
+"""
+Default branch lookup for resolved GitHub repositories
+"""
+
+import json
+import urllib.error
+
+import pytest
+
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.resolution_cache import ResolutionCache
+from gardener.package_metadata.url_resolver import fetch_default_branch, resolve_default_branches
+
+REPOSITORIES = {
+    "https://api.github.com/repos/pkg/errors": {"full_name": "pkg/errors", "default_branch": "master"},
+    "https://api.github.com/repos/acme/lib": {"full_name": "acme/lib", "default_branch": "trunk"},
+    "https://api.github.com/repos/acme/odd": {"full_name": "acme/odd"},
+}
+DAY = 86400
+
+
+def _github(requested):
+    def _request(url):
+        requested.append(url)
+        if url.endswith("/limited"):
+            raise urllib.error.HTTPError(url, 403, "rate limit exceeded", {}, None)
+        payload = REPOSITORIES.get(url)
+        return json.dumps(payload) if payload else None
+
+    return _request
+
+
+@pytest.mark.unit
+def test_github_packages_get_their_default_branch_or_a_reason(monkeypatch):
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _github(requested))
+    packages = {
+        "github.com/pkg/errors": {"ecosystem": "go", "repository_url": "https://github.com/pkg/errors"},
+        "github.com/pkg/errors/v2": {"ecosystem": "go", "repository_url": "https://github.com/pkg/errors"},
+        "lib": {"ecosystem": "npm", "repository_url": "https://github.com/acme/lib/tree/trunk/packages/lib"},
+        "gone": {"ecosystem": "npm", "repository_url": "https://github.com/acme/gone"},
+        "odd": {"ecosystem": "npm", "repository_url": "https://github.com/acme/odd"},
+        "limited": {"ecosystem": "pypi", "repository_url": "https://github.com/acme/limited"},
+        "gitlab-hosted": {"ecosystem": "cargo", "repository_url": "https://gitlab.com/acme/crate"},
+        "unresolved": {"ecosystem": "npm", "repository_url": ""},
+    }
+
+    with ConfigOverride({"MAX_RETRIES": 0, "RESOLVER_CONCURRENCY": 3}):
+        assert resolve_default_branches(packages) == 3
+
+    outcomes = {
+        name: (info.get("default_branch"), info.get("default_branch_reason")) for name, info in packages.items()
+    }
+    assert outcomes == {
+        "github.com/pkg/errors": ("master", None),
+        "github.com/pkg/errors/v2": ("master", None),
+        "lib": ("trunk", None),
+        "gone": (None, "repository-not-found"),
+        "odd": (None, "github-unreachable"),
+        "limited": (None, "github-rate-limited"),
+        "gitlab-hosted": (None, None),
+        "unresolved": (None, None),
+    }
+    # Never a guessed branch, and one request per GitHub repository
+    assert "default_branch" not in packages["gone"]
+    assert sorted(requested) == sorted(set(requested))
+    assert len(requested) == 5
+
+
+@pytest.mark.unit
+def test_branches_are_cached_across_runs_and_reused_offline(tmp_path, monkeypatch):
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _github(requested))
+    clock = [1_800_000_000]
+
+    def _packages():
+        return {"github.com/pkg/errors": {"ecosystem": "go", "repository_url": "https://github.com/pkg/errors"}}
+
+    def _cache():
+        return ResolutionCache(str(tmp_path), 7 * DAY, clock=lambda: clock[0])
+
+    cache = _cache()
+    resolve_default_branches(_packages(), resolution_cache=cache)
+    cache.save()
+    assert requested == ["https://api.github.com/repos/pkg/errors"]
+
+    packages = _packages()
+    resolve_default_branches(packages, offline=True, resolution_cache=_cache())
+    assert packages["github.com/pkg/errors"]["default_branch"] == "master"
+
+    clock[0] += 8 * DAY
+    packages = _packages()
+    resolve_default_branches(packages, offline=True, resolution_cache=_cache())
+    assert packages["github.com/pkg/errors"] == {
+        "ecosystem": "go",
+        "repository_url": "https://github.com/pkg/errors",
+        "default_branch_reason": "offline-skipped",
+    }
+    assert len(requested) == 1
+
+
+@pytest.mark.unit
+def test_lookup_uses_the_canonical_repository_and_skips_other_hosts(monkeypatch):
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _github(requested))
+
+    assert fetch_default_branch("https://gitlab.com/acme/crate") == (None, "unsupported-host")
+    assert fetch_default_branch("https://github.com/pkg/errors/tree/v1/sub") == ("master", None)
+    assert requested == ["https://api.github.com/repos/pkg/errors"]
@@ -0,0 +1,84 @@
+"""
+`gardener doctor`: connectivity checks of the resolver backends
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.common.exit_codes import EXIT_NETWORK_FAILURE
+from gardener.common.utils import configure_logging
+from gardener.package_metadata import url_resolver
+
+
+@pytest.fixture(autouse=True)
+def _reset_logging(monkeypatch):
+    monkeypatch.setenv("GOPROXY", "https://proxy.golang.org,direct")
+    monkeypatch.delenv("GITHUB_TOKEN", raising=False)
+    yield
+    configure_logging()
+
+
+def _doctor(monkeypatch, capsys, hook, *args):
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", hook)
+    monkeypatch.setattr(sys, "argv", ["gardener", "doctor", "--format", "json", "--max-retries", "0", *args])
+    code = 0
+    try:
+        main_cli.main()
+    except SystemExit as e:
+        code = e.code
+    report = json.loads(capsys.readouterr().out)
+    return code, {check["backend"]: check for check in report["checks"]}, report["failed"]
+
+
+@pytest.mark.unit
+def test_reachable_backends_pass(monkeypatch, capsys):
+    seen = []
+
+    def hook(url):
+        seen.append(url)
+        return "{}"
+
+    code, checks, failed = _doctor(monkeypatch, capsys, hook, "--auth-token", "secret")
+
+    assert (code, failed) == (0, [])
+    assert list(checks) == ["npm", "pypi", "crates.io", "go-proxy", "pkg.go.dev", "github-api"]
+    assert {check["status"] for check in checks.values()} == {"ok"}
+    assert checks["go-proxy"]["url"] == "https://proxy.golang.org/github.com/pkg/errors/@latest"
+    assert (checks["github-api"]["auth"], checks["npm"]["auth"]) == ("token", "none")
+    assert checks["npm"]["attempts"] == 1 and checks["npm"]["latency_seconds"] >= 0
+    assert len(seen) == 6
+
+
+@pytest.mark.unit
+def test_unreachable_required_backend_fails_and_blocked_hosts_are_not_contacted(monkeypatch, capsys):
+    seen = []
+
+    def hook(url):
+        seen.append(url)
+        if url.startswith("https://crates.io/"):
+            raise ConnectionError("connection refused")
+        return "{}"
+
+    code, checks, failed = _doctor(monkeypatch, capsys, hook, "--deny-registry", "pypi.org")
+
+    assert (code, failed) == (EXIT_NETWORK_FAILURE, ["crates.io"])
+    assert checks["crates.io"]["status"] == "unreachable" and "http_status" not in checks["crates.io"]
+    assert checks["pypi"]["status"] == "blocked"
+    assert not any(url.startswith("https://pypi.org/") for url in seen)
+
+
+@pytest.mark.unit
+def test_offline_sends_nothing(monkeypatch, capsys):
+    def hook(url):
+        raise AssertionError(f"unexpected request to {url}")
+
+    code, checks, failed = _doctor(monkeypatch, capsys, hook, "--offline", "--backend", "npm", "--backend", "go-proxy")
+
+    assert (code, failed) == (0, [])
+    assert {name: check["status"] for name, check in checks.items()} == {
+        "npm": "offline-skipped",
+        "go-proxy": "offline-skipped",
+    }
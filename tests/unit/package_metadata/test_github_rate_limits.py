@@ -0,0 +1,117 @@
+"""
+GitHub API rate limits: Retry-After, X-RateLimit-Reset and secondary limits
+"""
+
+import json
+import time
+import urllib.error
+
+import pytest
+
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import (
+    auth_token_for,
+    fetch_repository_license,
+    github_rate_limit_delay,
+    resolve_licenses,
+)
+
+LICENSE = json.dumps({"license": {"spdx_id": "MIT"}})
+
+
+@pytest.fixture
+def github(monkeypatch):
+    """
+    Serve queued GitHub responses, record the requests and the waits, and start without a pending limit
+    """
+
+    class GitHub:
+        def __init__(self):
+            self.requested, self.slept, self.queue = [], [], []
+
+        def _request(self, url):
+            self.requested.append(url)
+            status, headers = self.queue.pop(0) if self.queue else (200, {})
+            if status != 200:
+                raise urllib.error.HTTPError(url, status, "rate limited", headers, None)
+            return LICENSE
+
+    server = GitHub()
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", server._request)
+    monkeypatch.setattr(url_resolver.time, "sleep", server.slept.append)
+    monkeypatch.setitem(url_resolver._GITHUB_BLOCKED_UNTIL, "at", 0.0)
+    return server
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "status, headers, attempt, expected",
+    [
+        (403, {"retry-after": "30"}, 0, 30.0),
+        (429, {"retry-after": "Thu, 01 Jan 2026 00:01:00 GMT"}, 0, 60.0),
+        (403, {"x-ratelimit-remaining": "0", "x-ratelimit-reset": "1767225900"}, 0, 300.0),
+        # Secondary limits that name no time: a minute, doubled per attempt
+        (403, {}, 1, 120.0),
+        (403, {"x-ratelimit-remaining": "12"}, 0, 60.0),
+        (404, {"retry-after": "30"}, 0, None),
+        (200, {"x-ratelimit-remaining": "0", "x-ratelimit-reset": "1767225900"}, 0, None),
+    ],
+)
+def test_rate_limit_delay_follows_the_response_headers(status, headers, attempt, expected):
+    assert github_rate_limit_delay(status, headers, attempt, now=1767225600.0) == expected
+
+
+@pytest.mark.unit
+def test_secondary_limit_is_waited_out_and_retried(github):
+    github.queue = [(403, {"Retry-After": "7"})]
+
+    assert fetch_repository_license("https://github.com/pkg/errors") == ("MIT", None)
+    assert len(github.slept) == 1 and 6 < github.slept[0] <= 7
+    assert len(github.requested) == 2
+
+
+@pytest.mark.unit
+def test_used_up_limit_gives_up_without_hammering(github):
+    reset = str(int(time.time()) + 3600)
+    github.queue = [(403, {"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset})]
+    packages = {
+        name: {"ecosystem": "go", "repository_url": f"https://github.com/acme/{name}"} for name in ("a", "b", "c")
+    }
+
+    with ConfigOverride({"RESOLVER_CONCURRENCY": 1}):
+        resolve_licenses(packages)
+
+    assert {info["license_reason"] for info in packages.values()} == {"github-rate-limited"}
+    # The reset is an hour away, past GITHUB_RATE_LIMIT_MAX_WAIT, so nothing else is sent or waited for
+    assert github.requested == ["https://api.github.com/repos/acme/a/license"]
+    assert github.slept == []
+
+
+@pytest.mark.unit
+def test_waits_within_the_limit_are_sat_out(github):
+    reset = str(int(time.time()) + 3600)
+    github.queue = [(429, {"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset})]
+
+    with ConfigOverride({"GITHUB_RATE_LIMIT_MAX_WAIT": 7200}):
+        assert fetch_repository_license("https://github.com/pkg/errors") == ("MIT", None)
+
+    assert len(github.slept) == 1 and 3500 < github.slept[0] <= 3600
+
+
+@pytest.mark.unit
+def test_bare_rate_limit_without_retries_left_is_reported(github):
+    github.queue = [(429, {})]
+
+    with ConfigOverride({"MAX_RETRIES": 0}):
+        assert fetch_repository_license("https://github.com/pkg/errors") == (None, "github-rate-limited")
+    assert github.slept == []
+
+
+@pytest.mark.unit
+def test_auth_token_goes_to_the_github_api(monkeypatch):
+    monkeypatch.delenv("GITHUB_TOKEN", raising=False)
+
+    with ConfigOverride({"AUTH_TOKEN": "ghp_secret"}):
+        assert auth_token_for("https://api.github.com/repos/pkg/errors/license") == "ghp_secret"
+        assert auth_token_for("https://registry.npmjs.org/left-pad") is None
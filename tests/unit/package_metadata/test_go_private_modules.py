@@ -0,0 +1,161 @@
+"""
+GOPRIVATE / GONOSUMDB handling and authenticated go-import lookups
+"""
+
+import pytest
+
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import (
+    fetch_go_mod,
+    fetch_go_proxy_metadata,
+    is_go_private_module,
+    resolve_package_urls,
+)
+
+PRIVATE_MODULE = "github.corp.example.com/team/lib"
+META_URL = f"https://{PRIVATE_MODULE}?go-get=1"
+META_PAGE = (
+    '<meta name="go-import" content="github.corp.example.com/team/lib git https://github.corp.example.com/team/lib.git">'
+)
+
+
+@pytest.fixture
+def go_env(monkeypatch):
+    for name in url_resolver.GO_PRIVATE_ENV_VARS + ("GITHUB_TOKEN",):
+        monkeypatch.delenv(name, raising=False)
+    return monkeypatch
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "globs, module_path, private",
+    [
+        ("*.corp.example.com", PRIVATE_MODULE, True),
+        ("*.corp.example.com", "corp.example.com/lib", False),
+        ("github.com/acme", "github.com/acme/secret/v2", True),
+        ("github.com/acme", "github.com/acme-public/lib", False),
+        ("github.com/acme/*", "github.com/acme", False),
+        ("rsc.io/private,  *.corp.example.com/team ", PRIVATE_MODULE, True),
+        ("", "github.com/acme/secret", False),
+    ],
+)
+def test_private_globs_match_leading_path_elements(globs, module_path, private):
+    assert is_go_private_module(module_path, goprivate=globs) is private
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("env_var", url_resolver.GO_PRIVATE_ENV_VARS)
+def test_private_modules_skip_every_public_lookup(go_env, env_var):
+    go_env.setenv(env_var, "*.corp.example.com")
+    requested = []
+
+    def _record(url):
+        requested.append(url)
+
+    go_env.setattr(url_resolver, "_REQUEST_FN", _record)
+    receipts = {}
+    resolved = resolve_package_urls({PRIVATE_MODULE: {"ecosystem": "go"}}, receipts=receipts)
+
+    assert resolved == {}
+    assert receipts[PRIVATE_MODULE] == {"private": True, "reason": "private-module-skipped"}
+    assert fetch_go_proxy_metadata(PRIVATE_MODULE) == (None, "private-module-skipped")
+    assert fetch_go_mod(PRIVATE_MODULE, "v1.0.0") == (None, "private-module-skipped")
+    assert requested == []
+
+
+@pytest.mark.unit
+def test_private_modules_on_code_hosts_still_resolve_from_the_import_path(go_env):
+    go_env.setenv("GOPRIVATE", "github.com/acme")
+    receipts = {}
+
+    resolved = resolve_package_urls({"github.com/acme/secret": {"ecosystem": "go"}}, receipts=receipts)
+
+    assert resolved == {"github.com/acme/secret": "https://github.com/acme/secret"}
+    assert receipts["github.com/acme/secret"]["private"] is True
+    assert receipts["github.com/acme/secret"]["source"] == "import-path"
+
+
+@pytest.mark.unit
+def test_auth_token_enables_go_import_lookups_on_private_hosts(go_env, offline_mode):
+    go_env.setenv("GOPRIVATE", "*.corp.example.com")
+    receipts = {}
+
+    config = {"AUTH_TOKEN": "s3cret", "AUTH_HOSTS": ["corp.example.com"]}
+    with ConfigOverride(config), offline_mode.set_responses({META_URL: META_PAGE}):
+        resolved = resolve_package_urls({PRIVATE_MODULE: {"ecosystem": "go"}}, receipts=receipts)
+
+    assert resolved == {PRIVATE_MODULE: "https://github.corp.example.com/team/lib"}
+    assert receipts[PRIVATE_MODULE]["private"] is True
+    assert receipts[PRIVATE_MODULE]["source"] == "go-import-meta"
+
+
+@pytest.mark.unit
+def test_auth_token_is_only_sent_to_configured_hosts(go_env):
+    go_env.setenv("GOPRIVATE", "*.corp.example.com")
+    go_env.setenv("GITHUB_TOKEN", "gh-token")
+
+    assert url_resolver.auth_token_for("https://api.github.com/repos/acme/secret") == "gh-token"
+    # $GITHUB_TOKEN never leaves github.com, not even for GOPRIVATE hosts
+    assert url_resolver.auth_token_for(META_URL) is None
+    assert url_resolver.auth_token_for("https://proxy.golang.org/x/@v/list") is None
+    assert url_resolver.auth_token_for("https://registry.npmjs.org/lodash") is None
+
+    with ConfigOverride({"AUTH_TOKEN": "flag-token"}):
+        assert url_resolver.auth_token_for("https://github.com/acme/secret") == "flag-token"
+        assert url_resolver.auth_token_for(META_URL) is None
+
+    with ConfigOverride({"AUTH_TOKEN": "flag-token", "AUTH_HOSTS": ["git.example.org"]}):
+        assert url_resolver.auth_token_for("https://mirror.git.example.org/x?go-get=1") == "flag-token"
+        assert url_resolver.auth_token_for(META_URL) is None
+        # The flag token only goes to the listed hosts
+        assert url_resolver.auth_token_for("https://api.github.com/repos/acme/secret") == "gh-token"
+
+
+@pytest.mark.unit
+def test_auth_header_is_attached_to_real_requests(go_env):
+    captured = {}
+
+    class _Response:
+        status = 200
+
+        def __enter__(self):
+            return self
+
+        def __exit__(self, *exc):
+            return False
+
+        def read(self):
+            return META_PAGE.encode()
+
+    def _urlopen(request, timeout=None):
+        captured["authorization"] = request.get_header("Authorization")
+        return _Response()
+
+    go_env.setenv("GOPRIVATE", "*.corp.example.com")
+    go_env.setattr(url_resolver, "_REQUEST_FN", None)
+    go_env.setattr(url_resolver, "_urlopen", _urlopen)
+    with ConfigOverride({"AUTH_TOKEN": "s3cret", "AUTH_HOSTS": ["corp.example.com"]}):
+        status, _ = url_resolver._http_get(META_URL)
+
+    assert status == 200
+    assert captured["authorization"] == "Bearer s3cret"
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "location, authorization",
+    [
+        ("https://github.corp.example.com/team/lib/?go-get=1", "Bearer s3cret"),
+        ("https://attacker.example.net/collect", None),
+    ],
+)
+def test_auth_header_is_dropped_on_redirects_to_other_hosts(location, authorization):
+    request = url_resolver.urllib.request.Request(META_URL, headers={"Authorization": "Bearer s3cret"})
+
+    redirected = url_resolver._AuthorizationRedirectHandler().redirect_request(
+        request, None, 302, "Found", {"Location": location}, location
+    )
+
+    assert redirected.full_url == location
+    assert redirected.get_header("Authorization") == authorization
@@ -0,0 +1,113 @@
+"""
+Go repository URLs from the Origin the module proxy records for each version
+"""
+
+import json
+
+import pytest
+
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import fetch_go_version_origin, resolve_package_urls
+
+PROXY = "https://proxy.golang.org"
+
+TEXT_INFO = {
+    "Version": "v0.14.0",
+    "Time": "2023-10-11T17:04:45Z",
+    "Origin": {
+        "VCS": "git",
+        "URL": "https://go.googlesource.com/text",
+        "Ref": "refs/tags/v0.14.0",
+        "Hash": "2a8e8a1b8d8b",
+    },
+}
+
+METRIC_INFO = {
+    "Version": "v1.21.0",
+    "Time": "2023-11-16T17:00:00Z",
+    "Origin": {"VCS": "git", "URL": "https://github.com/open-telemetry/opentelemetry-go", "Subdir": "metric"},
+}
+
+# Proxies before Go 1.21 answer without an Origin
+YAML_INFO = {"Version": "v3.0.1", "Time": "2022-05-27T08:35:30Z"}
+YAML_PAGE = '<html><head><meta name="go-import" content="example.com/yaml git https://gopkg.in/yaml.v3"></head></html>'
+
+
+class _Proxy:
+    """Request hook serving .info documents and go-import pages, recording what was asked"""
+
+    def __init__(self, responses):
+        self.responses = responses
+        self.requested = []
+
+    def __call__(self, url):
+        self.requested.append(url)
+        return self.responses.get(url)
+
+
+@pytest.fixture
+def proxy(monkeypatch):
+    monkeypatch.setenv("GOPROXY", PROXY)
+    monkeypatch.delenv("GOPRIVATE", raising=False)
+    monkeypatch.delenv("GONOPROXY", raising=False)
+    monkeypatch.delenv("GONOSUMDB", raising=False)
+    hook = _Proxy(
+        {
+            f"{PROXY}/golang.org/x/text/@v/v0.14.0.info": json.dumps(TEXT_INFO),
+            f"{PROXY}/go.opentelemetry.io/otel/metric/@v/v1.21.0.info": json.dumps(METRIC_INFO),
+            f"{PROXY}/example.com/yaml/@v/v3.0.1.info": json.dumps(YAML_INFO),
+            "https://example.com/yaml?go-get=1": YAML_PAGE,
+        }
+    )
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", hook)
+    return hook
+
+
+@pytest.mark.unit
+def test_proxy_origin_wins_over_vanity_and_pkggodev(proxy):
+    receipts = {}
+    resolved = resolve_package_urls(
+        {
+            "golang.org/x/text": {"ecosystem": "go", "version": "v0.14.0"},
+            "go.opentelemetry.io/otel/metric": {"ecosystem": "go", "version": "v1.21.0"},
+        },
+        receipts=receipts,
+    )
+
+    assert resolved == {
+        "golang.org/x/text": "https://go.googlesource.com/text",
+        "go.opentelemetry.io/otel/metric": "https://github.com/open-telemetry/opentelemetry-go",
+    }
+    assert receipts["golang.org/x/text"]["source"] == "go-proxy-origin"
+    assert receipts["golang.org/x/text"]["confidence"] == 0.95
+    assert receipts["golang.org/x/text"]["derivation"] == "remote"
+    assert receipts["go.opentelemetry.io/otel/metric"]["repository_subpath"] == "metric"
+    assert not any("go-get=1" in url or "pkg.go.dev" in url for url in proxy.requested)
+
+
+@pytest.mark.unit
+def test_proxies_without_origin_fall_through_to_the_resolver_chain(proxy):
+    receipts = {}
+    resolved = resolve_package_urls({"example.com/yaml": {"ecosystem": "go", "version": "v3.0.1"}}, receipts=receipts)
+
+    assert resolved == {"example.com/yaml": "https://gopkg.in/yaml.v3"}
+    assert receipts["example.com/yaml"]["source"] == "go-import-meta"
+    assert receipts["example.com/yaml"]["origin_reason"] == "origin-missing"
+    assert fetch_go_version_origin("example.com/missing", "v1.0.0") == (None, "proxy-not-found")
+
+
+@pytest.mark.unit
+def test_replaced_modules_use_the_origin_of_the_replacement_version(proxy):
+    receipts = {}
+    packages = {
+        "example.com/fork-of-text": {
+            "ecosystem": "go",
+            "version": "v0.1.0",
+            "replace": {"path": "golang.org/x/text", "version": "v0.14.0", "local": False},
+        }
+    }
+    resolved = resolve_package_urls(packages, receipts=receipts)
+
+    assert resolved == {"example.com/fork-of-text": "https://go.googlesource.com/text"}
+    assert receipts["example.com/fork-of-text"]["source"] == "go-proxy-origin"
+    assert f"{PROXY}/golang.org/x/text/@v/v0.14.0.info" in proxy.requested
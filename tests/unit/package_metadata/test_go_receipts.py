@@ -0,0 +1,72 @@
+"""
+Resolution receipts of Go modules, whether the URL was read off the path or served by a host
+"""
+
+import pytest
+
+from gardener.package_metadata.resolution_cache import ResolutionCache
+from gardener.package_metadata.url_resolver import (
+    URL_CONFIDENCE_DECLARED,
+    URL_CONFIDENCE_HEURISTIC,
+    URL_CONFIDENCE_VANITY,
+    resolve_package_urls,
+)
+
+VANITY_PAGE = (
+    '<html><head><meta name="go-import" content="go.example.org/lib git https://git.example.org/lib.git">'
+    "</head></html>"
+)
+PACKAGES = {
+    "github.com/pkg/errors": {"ecosystem": "go", "version": "v0.9.1"},
+    "gopkg.in/yaml.v3": {"ecosystem": "go", "version": "v3.0.1"},
+    "github.com/upstream/lib": {
+        "ecosystem": "go",
+        "version": "v1.0.0",
+        "replace": {"path": "github.com/fork/lib", "version": "v1.0.1", "local": False},
+    },
+    "go.example.org/lib": {"ecosystem": "go", "version": "v0.2.0"},
+}
+
+
+def _fields(receipt):
+    return (receipt["source"], receipt["confidence"], receipt["cache"], receipt.get("derivation"))
+
+
+@pytest.mark.unit
+def test_every_resolved_go_url_says_how_it_was_obtained(offline_mode):
+    receipts = {}
+    with offline_mode.set_responses({"https://go.example.org/lib?go-get=1": VANITY_PAGE}):
+        resolved = resolve_package_urls(dict(PACKAGES), receipts=receipts)
+
+    assert resolved["github.com/upstream/lib"] == "https://github.com/fork/lib"
+    assert {name: _fields(receipt) for name, receipt in receipts.items()} == {
+        "github.com/pkg/errors": ("import-path", URL_CONFIDENCE_DECLARED, "disabled", "local"),
+        "gopkg.in/yaml.v3": ("gopkg-in", URL_CONFIDENCE_VANITY, "disabled", "local"),
+        "github.com/upstream/lib": ("gomod-replace", URL_CONFIDENCE_DECLARED, "disabled", "local"),
+        "go.example.org/lib": ("go-import-meta", URL_CONFIDENCE_VANITY, "disabled", "remote"),
+    }
+    assert receipts["github.com/upstream/lib"]["replaced_to"] == "github.com/fork/lib@v1.0.1"
+
+
+@pytest.mark.unit
+def test_cached_go_urls_keep_their_derivation(tmp_path, offline_mode):
+    packages = {name: PACKAGES[name] for name in ("github.com/pkg/errors", "go.example.org/lib")}
+    with offline_mode.set_responses({"https://go.example.org/lib?go-get=1": VANITY_PAGE}):
+        cache = ResolutionCache(str(tmp_path), 3600)
+        resolve_package_urls(dict(packages), resolution_cache=cache)
+        cache.save()
+
+        receipts = {}
+        resolve_package_urls(dict(packages), receipts=receipts, resolution_cache=ResolutionCache(str(tmp_path), 3600))
+    assert _fields(receipts["go.example.org/lib"]) == ("go-import-meta", URL_CONFIDENCE_VANITY, "hit", "remote")
+
+    # A URL handed in through the in-memory cache is only vouched for when the path gives it too
+    url_cache = {
+        "go:github.com/pkg/errors": "https://github.com/pkg/errors",
+        "go:go.example.org/lib": "https://git.example.org/lib",
+    }
+    receipts = {}
+    with offline_mode.set_responses({}):
+        resolve_package_urls(dict(packages), cache=url_cache, receipts=receipts)
+    assert _fields(receipts["github.com/pkg/errors"]) == ("import-path", URL_CONFIDENCE_DECLARED, "hit", "local")
+    assert _fields(receipts["go.example.org/lib"]) == ("cache", URL_CONFIDENCE_HEURISTIC, "hit", None)
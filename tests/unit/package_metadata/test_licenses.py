@@ -0,0 +1,81 @@
+"""
+License lookup for resolved GitHub repositories
+"""
+
+import json
+import urllib.error
+
+import pytest
+
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import fetch_repository_license, resolve_licenses
+
+LICENSES = {
+    "https://api.github.com/repos/pkg/errors/license": {"license": {"key": "bsd-2-clause", "spdx_id": "BSD-2-Clause"}},
+    "https://api.github.com/repos/acme/custom/license": {"license": {"key": "other", "spdx_id": "NOASSERTION"}},
+}
+
+
+def _github(requested):
+    def _request(url):
+        requested.append(url)
+        if url.endswith("/limited/license"):
+            raise urllib.error.HTTPError(url, 403, "rate limit exceeded", {}, None)
+        payload = LICENSES.get(url)
+        return json.dumps(payload) if payload else None
+
+    return _request
+
+
+@pytest.mark.unit
+def test_every_package_gets_a_license_or_a_reason(monkeypatch):
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _github(requested))
+    packages = {
+        "github.com/pkg/errors": {"ecosystem": "go", "repository_url": "https://github.com/pkg/errors"},
+        "github.com/pkg/errors/v2": {"ecosystem": "go", "repository_url": "https://github.com/pkg/errors"},
+        "custom": {"ecosystem": "npm", "repository_url": "https://github.com/acme/custom"},
+        "unlicensed": {"ecosystem": "npm", "repository_url": "https://github.com/acme/unlicensed"},
+        "limited": {"ecosystem": "pypi", "repository_url": "https://github.com/acme/limited"},
+        "gitlab-hosted": {"ecosystem": "cargo", "repository_url": "https://gitlab.com/acme/crate"},
+        "unresolved": {"ecosystem": "npm", "repository_url": ""},
+    }
+
+    with ConfigOverride({"MAX_RETRIES": 0, "RESOLVER_CONCURRENCY": 3}):
+        resolve_licenses(packages)
+
+    assert packages["github.com/pkg/errors"]["license"] == "BSD-2-Clause"
+    assert packages["github.com/pkg/errors"]["license_source"] == "github-api"
+    assert packages["github.com/pkg/errors/v2"]["license"] == "BSD-2-Clause"
+    outcomes = {name: (info["license"], info.get("license_reason")) for name, info in packages.items()}
+    assert outcomes["custom"] == (None, "unrecognized-license")
+    assert outcomes["unlicensed"] == (None, "license-not-found")
+    assert outcomes["limited"] == (None, "github-rate-limited")
+    assert outcomes["gitlab-hosted"] == (None, "unsupported-host")
+    assert outcomes["unresolved"] == (None, "no-repository-url")
+    # One request per repository, none for hosts without a license endpoint
+    assert sorted(requested) == sorted(set(requested))
+    assert len(requested) == 4
+
+
+@pytest.mark.unit
+def test_offline_records_why_licenses_are_missing(monkeypatch):
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _github(requested))
+    packages = {"github.com/pkg/errors": {"ecosystem": "go", "repository_url": "https://github.com/pkg/errors"}}
+
+    resolve_licenses(packages, offline=True)
+
+    assert packages["github.com/pkg/errors"]["license"] is None
+    assert packages["github.com/pkg/errors"]["license_reason"] == "offline-skipped"
+    assert requested == []
+
+
+@pytest.mark.unit
+def test_license_lookup_uses_the_canonical_repository(monkeypatch):
+    requested = []
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _github(requested))
+
+    assert fetch_repository_license("https://github.com/pkg/errors/tree/master/sub") == ("BSD-2-Clause", None)
+    assert requested == ["https://api.github.com/repos/pkg/errors/license"]
@@ -0,0 +1,144 @@
+"""
+npm registry lookups over kept-alive connections (NPM_BATCHED_RESOLUTION)
+"""
+
+import json
+import threading
+import urllib.error
+import urllib.request
+
+import pytest
+
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import network_time, resolve_package_urls
+
+REGISTRY = {
+    "/left-pad": {"repository": {"type": "git", "url": "git+https://github.com/left-pad/left-pad.git"}},
+    "/lodash": {
+        "dist-tags": {"latest": "4.17.21"},
+        "versions": {"4.17.21": {"repository": "github:lodash/lodash"}},
+    },
+    "/only-bugs": {"bugs": {"url": "https://github.com/acme/only-bugs/issues"}},
+    "/@acme%2Futil": {"description": "See https://github.com/acme/util for docs"},
+    "/no-links": {"description": "nothing to find"},
+}
+
+
+class _Response:
+    def __init__(self, path):
+        document = REGISTRY.get(path)
+        self.status = 200 if document is not None else 404
+        self.headers = {"Content-Type": "application/json"}
+        self.will_close = False
+        self._body = json.dumps(document).encode() if document is not None else b""
+
+    def read(self):
+        return self._body
+
+    def __enter__(self):
+        return self
+
+    def __exit__(self, *exc):
+        return False
+
+
+class _Server:
+    """Registry stand-in counting the connections opened to it and tracking those left open"""
+
+    def __init__(self, idle_close_after=None):
+        self.lock = threading.Lock()
+        self.connections = 0
+        self.open = set()
+        self.idle_close_after = idle_close_after
+
+    def connection(self, host, port=None, timeout=None):
+        server = self
+
+        class Connection:
+            def __init__(self):
+                with server.lock:
+                    server.connections += 1
+                    server.open.add(self)
+                self.served = 0
+
+            def request(self, method, path, headers=None):
+                assert (host, method, headers["User-Agent"]) == ("registry.npmjs.org", "GET", url_resolver.USER_AGENT)
+                # The server drops idle connections after a few requests; the client reconnects
+                if server.idle_close_after is not None and self.served == server.idle_close_after:
+                    raise ConnectionResetError("closed while idle")
+                self.served += 1
+                self.path = path
+
+            def getresponse(self):
+                return _Response(self.path)
+
+            def close(self):
+                with server.lock:
+                    server.open.discard(self)
+
+        return Connection()
+
+    def urlopen(self, request, timeout=None):
+        with self.lock:
+            self.connections += 1
+        path = request.full_url[len("https://registry.npmjs.org") :]
+        response = _Response(path)
+        if response.status != 200:
+            raise urllib.error.HTTPError(request.full_url, response.status, "Not Found", {}, None)
+        return response
+
+
+def _packages():
+    packages = {name: {"ecosystem": "npm"} for name in ("left-pad", "lodash", "only-bugs", "@acme/util", "no-links")}
+    packages["@types/node"] = {"ecosystem": "npm"}
+    return packages
+
+
+def _resolve(monkeypatch, server, batched, concurrency):
+    monkeypatch.setattr(url_resolver.http.client, "HTTPSConnection", server.connection)
+    monkeypatch.setattr(url_resolver, "_urlopen", server.urlopen)
+    monkeypatch.setattr(urllib.request, "getproxies", lambda: {})
+    receipts = {}
+    before = network_time()
+    config = {"NPM_BATCHED_RESOLUTION": batched, "RESOLVER_CONCURRENCY": concurrency, "MAX_RETRIES": 0}
+    with ConfigOverride(config):
+        resolved = resolve_package_urls(_packages(), receipts=receipts)
+    after = network_time()
+    return resolved, receipts, after["requests"] - before["requests"], after["connections"] - before["connections"]
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("concurrency", [1, 3])
+def test_batched_resolution_matches_per_request_connections(monkeypatch, concurrency):
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", None)
+
+    unbatched = _resolve(monkeypatch, _Server(), False, concurrency)
+    server = _Server()
+    batched = _resolve(monkeypatch, server, True, concurrency)
+
+    assert batched[:3] == unbatched[:3]
+    assert batched[0] == {
+        "left-pad": "https://github.com/left-pad/left-pad",
+        "lodash": "https://github.com/lodash/lodash",
+        "only-bugs": "https://github.com/acme/only-bugs",
+        "@acme/util": "https://github.com/acme/util",
+        "@types/node": "https://github.com/DefinitelyTyped/DefinitelyTyped",
+    }
+    requests = unbatched[2]
+    assert requests == 5
+    assert unbatched[3] == requests
+    assert batched[3] <= concurrency < requests
+    # Connections are closed once the lookups finish, those of worker threads included
+    assert server.open == set()
+
+
+@pytest.mark.unit
+def test_connections_closed_by_the_registry_are_reopened(monkeypatch):
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", None)
+
+    resolved, receipts, requests, connections = _resolve(monkeypatch, _Server(idle_close_after=2), True, 1)
+
+    assert len(resolved) == 5
+    assert all("network_errors" not in receipt for receipt in receipts.values())
+    assert (requests, connections) == (5, 3)
@@ -0,0 +1,97 @@
+"""
+Registry allow and deny lists gating outbound resolution requests
+"""
+
+import json
+
+import pytest
+
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import (
+    fetch_go_mod,
+    fetch_go_proxy_metadata,
+    fetch_repository_license,
+    registry_allowed,
+    resolve_package_urls,
+)
+
+NPM_METADATA = json.dumps({"repository": {"type": "git", "url": "git+https://github.com/lodash/lodash.git"}})
+
+
+@pytest.fixture
+def requested(monkeypatch):
+    for name in url_resolver.GO_PRIVATE_ENV_VARS + ("GOPROXY",):
+        monkeypatch.delenv(name, raising=False)
+    urls = []
+
+    def _record(url):
+        urls.append(url)
+        return NPM_METADATA if url.startswith("https://registry.npmjs.org/") else None
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _record)
+    return urls
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "allow, deny, url, allowed",
+    [
+        ((), (), "https://registry.npmjs.org/lodash", True),
+        ((), ("registry.npmjs.org",), "https://registry.npmjs.org/lodash", False),
+        ((), ("*.golang.org",), "https://proxy.golang.org/x/@v/list", False),
+        ((), ("*.golang.org",), "https://golang.org/x/net?go-get=1", True),
+        (("*.corp.example.com",), (), "https://npm.corp.example.com/lodash", True),
+        (("*.corp.example.com",), (), "https://pypi.org/pypi/requests/json", False),
+        (("*.corp.example.com",), ("npm.corp.example.com",), "https://npm.corp.example.com/lodash", False),
+        (("PKG.GO.DEV",), (), "https://pkg.go.dev/golang.org/x/net", True),
+    ],
+)
+def test_hosts_are_matched_against_the_lists(allow, deny, url, allowed):
+    with ConfigOverride({"ALLOW_REGISTRIES": allow, "DENY_REGISTRIES": deny}):
+        assert registry_allowed(url) is allowed
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "package_name, ecosystem",
+    [
+        ("lodash", "npm"),
+        ("requests", "pypi"),
+        ("serde", "cargo"),
+        ("go.example.org/lib", "go"),
+    ],
+)
+def test_blocked_hosts_are_never_contacted(requested, package_name, ecosystem):
+    receipts = {}
+
+    with ConfigOverride({"DENY_REGISTRIES": ["*"]}):
+        resolved = resolve_package_urls({package_name: {"ecosystem": ecosystem}}, receipts=receipts)
+
+    assert resolved == {}
+    assert requested == []
+    assert receipts[package_name]["reason"] == "registry-blocked"
+    assert "network_errors" not in receipts[package_name]
+
+
+@pytest.mark.unit
+def test_allow_list_blocks_every_other_host(requested):
+    receipts = {}
+    packages = {"lodash": {"ecosystem": "npm"}, "go.example.org/lib": {"ecosystem": "go"}}
+
+    with ConfigOverride({"ALLOW_REGISTRIES": ["registry.npmjs.org"]}):
+        resolved = resolve_package_urls(packages, receipts=receipts)
+
+    assert resolved == {"lodash": "https://github.com/lodash/lodash"}
+    assert requested == ["https://registry.npmjs.org/lodash"]
+    assert receipts["go.example.org/lib"]["reason"] == "registry-blocked"
+
+
+@pytest.mark.unit
+def test_blocked_go_proxy_and_license_lookups_report_the_block(requested):
+    with ConfigOverride({"DENY_REGISTRIES": ["proxy.golang.org", "api.github.com"]}):
+        assert fetch_go_proxy_metadata("golang.org/x/net") == (None, "registry-blocked")
+        assert fetch_go_mod("golang.org/x/net", "v0.17.0") == (None, "registry-blocked")
+        assert fetch_repository_license("https://github.com/golang/net") == (None, "registry-blocked")
+
+    assert requested == []
@@ -0,0 +1,52 @@
+"""
+Host, owner and repository name split out of repository URLs
+"""
+
+import pytest
+
+from gardener.analysis.main import DependencyAnalyzer
+from gardener.package_metadata.url_resolver import repository_coordinates
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "url, expected",
+    [
+        ("https://github.com/gin-gonic/gin", ("github.com", "gin-gonic", "gin")),
+        ("git@github.com:gin-gonic/gin.git", ("github.com", "gin-gonic", "gin")),
+        ("https://github.com/org/repo/tree/main/packages/core", ("github.com", "org", "repo")),
+        ("https://github.acme.com/team/service", ("github.acme.com", "team", "service")),
+        ("https://bitbucket.org/team/repo.git", ("bitbucket.org", "team", "repo")),
+        ("https://gitlab.com/group/proj", ("gitlab.com", "group", "proj")),
+        ("https://gitlab.com/group/sub/proj/-/tree/main", ("gitlab.com", "group/sub", "proj")),
+        ("https://gitlab.gnome.org/GNOME/glib.git", ("gitlab.gnome.org", "GNOME", "glib")),
+        ("https://gitea.com/gitea/tea", ("gitea.com", "gitea", "tea")),
+        ("https://codeberg.org/forgejo/forgejo/src/branch/forgejo", ("codeberg.org", "forgejo", "forgejo")),
+        ("https://git.example.org/team/repo", (None, None, None)),
+        ("https://github.com/owner-only", (None, None, None)),
+        ("", (None, None, None)),
+        (None, (None, None, None)),
+    ],
+)
+def test_repository_coordinates(url, expected):
+    coordinates = repository_coordinates(url)
+    assert (coordinates["host"], coordinates["owner"], coordinates["repo"]) == expected
+
+
+@pytest.mark.unit
+def test_every_external_package_carries_its_repository_coordinates(tmp_path):
+    (tmp_path / "go.mod").write_text("module example.com/app\n\nrequire github.com/gin-gonic/gin v1.9.1\n")
+    (tmp_path / "main.go").write_text('package main\n\nimport "github.com/gin-gonic/gin"\n')
+    (tmp_path / "package.json").write_text('{"dependencies": {"left-pad": "^1.3.0"}}')
+
+    analyzer = DependencyAnalyzer()
+    discovered = analyzer.discover_packages(str(tmp_path), ["go", "javascript"])
+    discovered["github.com/gin-gonic/gin"]["repository_url"] = "https://github.com/gin-gonic/gin"
+    packages = analyzer.analyze_dependencies(discovered)["external_packages"]
+
+    assert packages["github.com/gin-gonic/gin"]["repository"] == {
+        "host": "github.com",
+        "owner": "gin-gonic",
+        "repo": "gin",
+    }
+    assert packages["left-pad"]["repository"] == {"host": None, "owner": None, "repo": None}
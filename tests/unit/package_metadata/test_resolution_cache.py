@@ -0,0 +1,131 @@
+"""
+On-disk repository URL resolution cache with a TTL
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.resolution_cache import ResolutionCache, cache_path, parse_ttl
+from gardener.package_metadata.url_resolver import resolve_package_urls
+
+NPM_URL = "https://registry.npmjs.org/lodash"
+NPM_META = json.dumps(
+    {
+        "dist-tags": {"latest": "4.17.21"},
+        "versions": {"4.17.21": {"repository": "git+https://github.com/lodash/lodash.git"}},
+    }
+)
+PACKAGES = {"lodash": {"ecosystem": "npm", "version": "^4.17.0"}}
+DAY = 86400
+
+
+class _Clock:
+    def __init__(self):
+        self.now = 1_800_000_000
+
+    def __call__(self):
+        return self.now
+
+
+def _resolve(cache_dir, clock, monkeypatch):
+    requested = []
+
+    def _registry(url):
+        requested.append(url)
+        return NPM_META if url == NPM_URL else None
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _registry)
+    cache = ResolutionCache(str(cache_dir), 7 * DAY, clock=clock)
+    receipts = {}
+    resolved = resolve_package_urls(PACKAGES, receipts=receipts, resolution_cache=cache)
+    cache.save()
+    return resolved, receipts["lodash"], requested
+
+
+@pytest.mark.unit
+def test_fresh_entries_short_circuit_resolution(tmp_path, monkeypatch):
+    clock = _Clock()
+
+    resolved, receipt, requested = _resolve(tmp_path, clock, monkeypatch)
+    assert resolved == {"lodash": "https://github.com/lodash/lodash"}
+    assert receipt["cache"] == "miss"
+    assert requested == [NPM_URL]
+    with open(cache_path(str(tmp_path))) as handle:
+        entry = json.load(handle)["entries"]["npm:lodash@^4.17.0"]
+    assert entry == {
+        "ecosystem": "npm",
+        "package": "lodash",
+        "version": "^4.17.0",
+        "url": "https://github.com/lodash/lodash",
+        "source": "registry",
+        "checked_at": "2027-01-15T08:00:00Z",
+    }
+
+    clock.now += 6 * DAY
+    resolved, receipt, requested = _resolve(tmp_path, clock, monkeypatch)
+    assert resolved == {"lodash": "https://github.com/lodash/lodash"}
+    assert receipt == {"cache": "hit", "source": "registry", "confidence": url_resolver.URL_CONFIDENCE_REGISTRY}
+    assert requested == []
+
+
+@pytest.mark.unit
+def test_expired_entries_are_resolved_again_and_updated(tmp_path, monkeypatch):
+    clock = _Clock()
+    _resolve(tmp_path, clock, monkeypatch)
+
+    clock.now += 8 * DAY
+    resolved, receipt, requested = _resolve(tmp_path, clock, monkeypatch)
+
+    assert receipt["cache"] == "expired"
+    assert requested == [NPM_URL]
+    with open(cache_path(str(tmp_path))) as handle:
+        assert json.load(handle)["entries"]["npm:lodash@^4.17.0"]["checked_at"] == "2027-01-23T08:00:00Z"
+
+
+@pytest.mark.unit
+def test_unresolved_packages_are_not_cached(tmp_path, monkeypatch):
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", lambda url: None)
+    cache = ResolutionCache(str(tmp_path), 7 * DAY)
+
+    assert resolve_package_urls({"left-pad": {"ecosystem": "npm"}}, resolution_cache=cache) == {}
+    cache.save()
+
+    assert not (tmp_path / "resolution_cache.json").exists()
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "text, seconds",
+    [("7d", 7 * DAY), ("12h", 43200), ("30m", 1800), ("45s", 45), ("90", 90), ("0", 0), ("1.5d", 129600)],
+)
+def test_ttl_accepts_unit_suffixes(text, seconds):
+    assert parse_ttl(text) == seconds
+
+
+@pytest.mark.unit
+def test_cli_sets_the_ttl_and_clears_the_cache(tmp_path, monkeypatch):
+    cache_file = tmp_path / "resolution_cache.json"
+    cache_file.write_text("{}")
+    captured = {}
+
+    def _run_analysis(*args, **kwargs):
+        captured["overrides"] = args[5]
+        return {"analyzer_details": {"total_files": 1}}
+
+    monkeypatch.setattr(main_cli, "run_analysis", _run_analysis)
+    argv = ["gardener", str(tmp_path), "--cache-dir", str(tmp_path)]
+    monkeypatch.setattr(sys, "argv", argv + ["--resolution-cache-ttl", "12h", "--clear-resolution-cache"])
+    main_cli.main()
+
+    assert captured["overrides"]["RESOLUTION_CACHE_TTL"] == 43200
+    assert not cache_file.exists()
+
+    for ttl in ("soon", "-1d"):
+        monkeypatch.setattr(sys, "argv", argv + [f"--resolution-cache-ttl={ttl}"])
+        with pytest.raises(SystemExit) as excinfo:
+            main_cli.main()
+        assert excinfo.value.code == 2
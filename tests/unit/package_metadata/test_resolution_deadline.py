@@ -0,0 +1,94 @@
+"""
+Per-request HTTP timeouts and the global resolution deadline
+"""
+
+import json
+import sys
+import time
+import urllib.error
+
+import pytest
+
+from gardener import main_cli
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import request_timeout, resolution_deadline, resolve_package_urls
+
+
+def _slow_registry(delay):
+    def _request(url):
+        time.sleep(delay)
+        name = url.rsplit("/", 1)[-1]
+        return json.dumps({"repository": f"git+https://github.com/acme/{name}.git"})
+
+    return _request
+
+
+@pytest.mark.unit
+def test_packages_left_at_the_deadline_are_marked_and_the_run_finishes(monkeypatch):
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _slow_registry(0.2))
+    packages = {f"npm-{index}": {"ecosystem": "npm"} for index in range(20)}
+    receipts = {}
+
+    started = time.monotonic()
+    with ConfigOverride({"RESOLVER_CONCURRENCY": 2, "MAX_RETRIES": 0}), resolution_deadline(0.5):
+        resolved = resolve_package_urls(packages, receipts=receipts)
+    elapsed = time.monotonic() - started
+
+    # Without the deadline the 20 lookups would take 2 seconds on 2 slots
+    assert elapsed < 1.2
+    assert 2 <= len(resolved) < 20
+    unresolved = [name for name in packages if name not in resolved]
+    assert {receipts[name]["reason"] for name in unresolved} == {"resolution-deadline-exceeded"}
+    assert resolved["npm-0"] == "https://github.com/acme/npm-0"
+
+
+@pytest.mark.unit
+def test_retry_backoff_does_not_outlast_the_deadline(monkeypatch):
+    def _unavailable(url):
+        raise urllib.error.HTTPError(url, 503, "Service Unavailable", {}, None)
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _unavailable)
+    url_resolver.reset_request_attempts()
+
+    started = time.monotonic()
+    with ConfigOverride({"MAX_RETRIES": 3, "RETRY_BASE_DELAY": 5.0}), resolution_deadline(0.3):
+        status, _ = url_resolver._http_get_with_retries("https://registry.npmjs.org/slow")
+    assert time.monotonic() - started < 1.0
+    # The first retry wakes at the deadline and is not sent
+    assert status is None
+    assert url_resolver.request_attempts() == 1
+    assert url_resolver.request_deadline_hits() == 1
+
+
+@pytest.mark.unit
+def test_request_timeout_is_cut_to_the_time_left():
+    with ConfigOverride({"HTTP_TIMEOUT": 4.0}):
+        assert request_timeout() == 4.0
+        with resolution_deadline(60):
+            assert request_timeout() == 4.0
+        with resolution_deadline(1):
+            assert request_timeout() <= 1.0
+        with resolution_deadline(0):
+            assert request_timeout() == 4.0
+
+
+@pytest.mark.unit
+def test_cli_sets_the_timeouts(tmp_path, monkeypatch):
+    captured = {}
+
+    def _run_analysis(*args, **kwargs):
+        captured["overrides"] = args[5]
+        return {"analyzer_details": {"total_files": 1}}
+
+    monkeypatch.setattr(main_cli, "run_analysis", _run_analysis)
+    argv = ["gardener", str(tmp_path), "--no-cache", "--http-timeout", "2.5", "--resolution-deadline", "30"]
+    monkeypatch.setattr(sys, "argv", argv)
+    main_cli.main()
+    assert captured["overrides"]["HTTP_TIMEOUT"] == 2.5
+    assert captured["overrides"]["RESOLUTION_DEADLINE"] == 30
+
+    monkeypatch.setattr(sys, "argv", ["gardener", str(tmp_path), "--resolution-deadline", "0"])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    assert excinfo.value.code == 2
@@ -0,0 +1,104 @@
+"""
+Bounded concurrency of outbound resolution requests
+"""
+
+import json
+import sys
+import threading
+import time
+
+import pytest
+
+from gardener import main_cli
+from gardener.common.defaults import ConfigOverride
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import resolve_package_urls
+
+
+class _CountingClient:
+    """HTTP hook that records the peak number of requests in flight"""
+
+    def __init__(self, delay=0.02):
+        self.delay = delay
+        self.lock = threading.Lock()
+        self.in_flight = 0
+        self.peak = 0
+        self.requested = []
+
+    def __call__(self, url):
+        with self.lock:
+            self.in_flight += 1
+            self.peak = max(self.peak, self.in_flight)
+            self.requested.append(url)
+        try:
+            time.sleep(self.delay)
+            if url.startswith("https://registry.npmjs.org/"):
+                name = url.rsplit("/", 1)[-1]
+                return json.dumps({"repository": f"git+https://github.com/acme/{name}.git"})
+            return None
+        finally:
+            with self.lock:
+                self.in_flight -= 1
+
+
+def _packages():
+    packages = {f"npm-{index}": {"ecosystem": "npm"} for index in range(12)}
+    packages.update({f"example.com/mod{index}": {"ecosystem": "go"} for index in range(6)})
+    return packages
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("limit", [1, 3])
+def test_requests_in_flight_never_exceed_the_limit(monkeypatch, limit):
+    client = _CountingClient()
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", client)
+    receipts = {}
+
+    with ConfigOverride({"RESOLVER_CONCURRENCY": limit, "MAX_RETRIES": 0}):
+        resolved = resolve_package_urls(_packages(), receipts=receipts)
+
+    assert client.peak == limit
+    # Go modules go to meta-tag hosts and pkg.go.dev through the same slots
+    assert any("pkg.go.dev" in url for url in client.requested)
+    assert any("go-get=1" in url for url in client.requested)
+    assert list(receipts) == list(_packages())
+    assert list(resolved) == [f"npm-{index}" for index in range(12)]
+    assert resolved["npm-7"] == "https://github.com/acme/npm-7"
+
+
+@pytest.mark.unit
+def test_request_slots_are_shared_across_threads(monkeypatch):
+    client = _CountingClient()
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", client)
+
+    with ConfigOverride({"RESOLVER_CONCURRENCY": 2}):
+        threads = [
+            threading.Thread(target=url_resolver._http_get, args=(f"https://registry.npmjs.org/p{index}",))
+            for index in range(8)
+        ]
+        for thread in threads:
+            thread.start()
+        for thread in threads:
+            thread.join()
+
+    assert client.peak == 2
+    assert len(client.requested) == 8
+
+
+@pytest.mark.unit
+def test_cli_sets_the_resolver_concurrency(tmp_path, monkeypatch):
+    captured = {}
+
+    def _run_analysis(*args, **kwargs):
+        captured["overrides"] = args[5]
+        return {"analyzer_details": {"total_files": 1}}
+
+    monkeypatch.setattr(main_cli, "run_analysis", _run_analysis)
+    monkeypatch.setattr(sys, "argv", ["gardener", str(tmp_path), "--no-cache", "--resolver-concurrency", "2"])
+    main_cli.main()
+    assert captured["overrides"]["RESOLVER_CONCURRENCY"] == 2
+
+    monkeypatch.setattr(sys, "argv", ["gardener", str(tmp_path), "--resolver-concurrency", "0"])
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    assert excinfo.value.code == 2
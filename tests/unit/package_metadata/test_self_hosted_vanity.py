@@ -0,0 +1,128 @@
+"""
+Go vanity imports served by self-hosted GitLab and Gitea instances
+"""
+
+import pytest
+
+from gardener.api import AnalysisOptions, analyze_repo
+from gardener.package_metadata.url_resolver import (
+    repository_coordinates,
+    resolve_go_vanity_import,
+    resolve_package_urls,
+)
+
+# As GitLab's go-get middleware renders it for a project in a nested subgroup
+GITLAB_SUBGROUP_PAGE = (
+    "<html><head>"
+    '<meta name="go-import" content="git.corp.example/platform/backend/billing git '
+    'https://git.corp.example/platform/backend/billing.git" />'
+    '<meta name="go-source" content="git.corp.example/platform/backend/billing '
+    "https://git.corp.example/platform/backend/billing "
+    "https://git.corp.example/platform/backend/billing/-/tree/main{/dir} "
+    'https://git.corp.example/platform/backend/billing/-/blob/main{/dir}/{file}#L{line}" />'
+    "</head><body>go get https://git.corp.example/platform/backend/billing</body></html>"
+)
+
+# As Gitea renders it, with the content attribute first and a `_` home page
+GITEA_PAGE = """<!DOCTYPE html>
+<html lang="en-US">
+<head>
+\t<meta content="code.corp.example/tools/cli git https://code.corp.example/tools/cli.git" name="go-import">
+\t<meta content="code.corp.example/tools/cli _ https://code.corp.example/tools/cli/src/branch/main{/dir} \
+https://code.corp.example/tools/cli/src/branch/main{/dir}/{file}#L{line}" name="go-source">
+</head>
+<body>go get --insecure code.corp.example/tools/cli</body>
+</html>
+"""
+
+RESPONSES = {
+    "https://git.corp.example/platform/backend/billing/client?go-get=1": GITLAB_SUBGROUP_PAGE,
+    "https://git.corp.example/platform/backend/billing?go-get=1": GITLAB_SUBGROUP_PAGE,
+    "https://code.corp.example/tools/cli?go-get=1": GITEA_PAGE,
+}
+
+
+@pytest.mark.unit
+def test_gitlab_subgroup_page_resolves_the_project_and_its_forge(offline_mode):
+    receipt = {}
+    with offline_mode.set_responses(RESPONSES):
+        url, reason = resolve_go_vanity_import("git.corp.example/platform/backend/billing/client", receipt=receipt)
+
+    assert (url, reason) == ("https://git.corp.example/platform/backend/billing", None)
+    assert receipt == {"repo_root": "git.corp.example/platform/backend/billing", "code_host": "gitlab"}
+    assert repository_coordinates(url, receipt["code_host"]) == {
+        "host": "git.corp.example",
+        "owner": "platform/backend",
+        "repo": "billing",
+    }
+    # The host name alone does not tell GitLab apart from any other server
+    assert repository_coordinates(url)["owner"] is None
+
+
+@pytest.mark.unit
+def test_gitea_page_resolves_with_attributes_in_any_order(offline_mode):
+    packages = {"code.corp.example/tools/cli": {"ecosystem": "go"}}
+    receipts = {}
+    with offline_mode.set_responses(RESPONSES):
+        resolved = resolve_package_urls(packages, cache={}, receipts=receipts)
+
+    assert resolved == {"code.corp.example/tools/cli": "https://code.corp.example/tools/cli"}
+    receipt = receipts["code.corp.example/tools/cli"]
+    assert (receipt["source"], receipt["code_host"]) == ("go-import-meta", "gitea")
+
+
+@pytest.mark.unit
+def test_sibling_paths_reuse_the_forge_from_the_host_cache(offline_mode):
+    cache, first, second = {}, {}, {}
+    with offline_mode.set_responses(RESPONSES):
+        resolve_go_vanity_import("git.corp.example/platform/backend/billing/client", cache=cache, receipt=first)
+        with offline_mode.set_responses({}):
+            url, _ = resolve_go_vanity_import("git.corp.example/platform/backend/billing/api", cache=cache, receipt=second)
+
+    assert url == "https://git.corp.example/platform/backend/billing"
+    assert second == first
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "page",
+    [
+        "<meta name=go-import content='example.org/lib git https://src.example.org/lib.git'>",
+        '<META NAME="go-import" CONTENT="  example.org/lib   git\n https://src.example.org/lib.git ">',
+        # A module proxy root is not a repository, so the git root after it is used
+        '<meta name="go-import" content="example.org/lib mod https://proxy.example.org">'
+        '<meta name="go-import" content="example.org/lib git https://src.example.org/lib.git">',
+    ],
+)
+def test_go_import_meta_tags_are_parsed_leniently(page, offline_mode):
+    with offline_mode.set_responses({"https://example.org/lib?go-get=1": page}):
+        assert resolve_go_vanity_import("example.org/lib") == ("https://src.example.org/lib", None)
+
+
+@pytest.mark.unit
+def test_analysis_splits_self_hosted_repositories_into_owner_and_repo(tmp_path, offline_mode):
+    (tmp_path / "go.mod").write_text(
+        "module example.com/app\n\ngo 1.21\n\nrequire (\n"
+        "\tgit.corp.example/platform/backend/billing v1.4.0\n"
+        "\tcode.corp.example/tools/cli v0.3.0\n"
+        ")\n"
+    )
+    (tmp_path / "main.go").write_text(
+        'package main\n\nimport (\n\t"code.corp.example/tools/cli"\n'
+        '\t"git.corp.example/platform/backend/billing/client"\n)\n'
+    )
+
+    with offline_mode.set_responses(RESPONSES):
+        options = AnalysisOptions(languages=["go"], config_overrides={"CACHE_DIR": "", "CHECK_VERSION_TIMES": False})
+        packages = analyze_repo(str(tmp_path), options).external_packages
+
+    assert packages["git.corp.example/platform/backend/billing"]["repository"] == {
+        "host": "git.corp.example",
+        "owner": "platform/backend",
+        "repo": "billing",
+    }
+    assert packages["code.corp.example/tools/cli"]["repository"] == {
+        "host": "code.corp.example",
+        "owner": "tools",
+        "repo": "cli",
+    }
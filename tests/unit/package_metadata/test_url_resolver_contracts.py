@@ -1,8 +1,18 @@
 import json
+import urllib.error
 
 import pytest
 
-from gardener.package_metadata.url_resolver import resolve_package_urls
+from gardener.common.defaults import NetworkConfig
+from gardener.package_metadata import url_resolver
+from gardener.package_metadata.url_resolver import (
+    fetch_go_mod,
+    fetch_go_proxy_metadata,
+    fetch_go_version_info,
+    gopkg_in_coordinates,
+    resolve_go_vanity_import,
+    resolve_package_urls,
+)
 
 
 @pytest.mark.unit
@@ -73,3 +83,430 @@ def test_go_go_get_meta_tag_parsing(offline_mode):
     with offline_mode.set_responses({fetch_url: html}):
         resolved = resolve_package_urls(packages, logger=None, cache={})
     assert resolved["golang.org/x/crypto"] == "https://github.com/golang/crypto"
+
+
+@pytest.mark.unit
+def test_go_gopkg_in_rewrite_needs_no_network(offline_mode):
+    packages = {
+        "gopkg.in/yaml.v3": {"ecosystem": "go"},
+        "gopkg.in/alecthomas/kingpin.v2": {"ecosystem": "go"},
+    }
+    receipts = {}
+    with offline_mode.set_responses({}):
+        resolved = resolve_package_urls(packages, logger=None, cache={}, receipts=receipts)
+    assert resolved["gopkg.in/yaml.v3"] == "https://github.com/go-yaml/yaml"
+    assert resolved["gopkg.in/alecthomas/kingpin.v2"] == "https://github.com/alecthomas/kingpin"
+    assert receipts["gopkg.in/yaml.v3"]["source"] == "gopkg-in"
+    assert receipts["gopkg.in/yaml.v3"]["major_version"] == "v3"
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "import_path, owner, repo, major_version, repo_root",
+    [
+        ("gopkg.in/yaml.v2", "go-yaml", "yaml", "v2", "gopkg.in/yaml.v2"),
+        ("gopkg.in/check.v1", "go-check", "check", "v1", "gopkg.in/check.v1"),
+        ("gopkg.in/mgo.v2", "go-mgo", "mgo", "v2", "gopkg.in/mgo.v2"),
+        ("gopkg.in/tomb.v0", "go-tomb", "tomb", "v0", "gopkg.in/tomb.v0"),
+        ("gopkg.in/mgo.v2/bson", "go-mgo", "mgo", "v2", "gopkg.in/mgo.v2"),
+        ("gopkg.in/qml.v1-unstable", "go-qml", "qml", "v1", "gopkg.in/qml.v1-unstable"),
+        ("gopkg.in/alecthomas/kingpin.v2", "alecthomas", "kingpin", "v2", "gopkg.in/alecthomas/kingpin.v2"),
+        ("gopkg.in/src-d/go-git.v4/plumbing", "src-d", "go-git", "v4", "gopkg.in/src-d/go-git.v4"),
+        ("gopkg.in/DataDog/dd-trace-go.v1", "DataDog", "dd-trace-go", "v1", "gopkg.in/DataDog/dd-trace-go.v1"),
+        ("gopkg.in/juju/names.v0", "juju", "names", "v0", "gopkg.in/juju/names.v0"),
+    ],
+)
+def test_gopkg_in_rewrite_rules(import_path, owner, repo, major_version, repo_root):
+    assert gopkg_in_coordinates(import_path) == {
+        "owner": owner,
+        "repo": repo,
+        "major_version": major_version,
+        "repo_root": repo_root,
+    }
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "import_path", ["gopkg.in/yaml", "gopkg.in/yaml.v2.1", "gopkg.in/a/b/c.v1", "github.com/go-yaml/yaml"]
+)
+def test_non_gopkg_in_paths_have_no_coordinates(import_path):
+    assert gopkg_in_coordinates(import_path) is None
+
+
+@pytest.mark.unit
+def test_go_vanity_lookup_is_cached_per_host(monkeypatch):
+    html = '<meta name="go-import" content="k8s.io/client-go git https://github.com/kubernetes/client-go">'
+    fetched = []
+
+    def _hook(url):
+        fetched.append(url)
+        return html if url == "https://k8s.io/client-go/kubernetes?go-get=1" else None
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _hook)
+    cache = {}
+    first = resolve_go_vanity_import("k8s.io/client-go/kubernetes", cache=cache)
+    second = resolve_go_vanity_import("k8s.io/client-go/rest", cache=cache)
+
+    assert first == ("https://github.com/kubernetes/client-go", None)
+    assert second == first
+    assert fetched == ["https://k8s.io/client-go/kubernetes?go-get=1"]
+
+
+@pytest.mark.unit
+def test_go_vanity_without_matching_meta_reports_reason(offline_mode):
+    packages = {"example.org/lib": {"ecosystem": "go"}}
+    # The only meta tag is for a different prefix, so it must not be used
+    html = '<meta name="go-import" content="example.org/other git https://github.com/example/other">'
+    receipts = {}
+    with offline_mode.set_responses({"https://example.org/lib?go-get=1": html}):
+        resolved = resolve_package_urls(packages, logger=None, cache={}, receipts=receipts)
+    assert "example.org/lib" not in resolved
+    assert receipts["example.org/lib"]["reason"] == "vanity-meta-missing"
+
+
+@pytest.mark.unit
+def test_go_major_versions_share_repository_url(offline_mode):
+    packages = {
+        "github.com/foo/bar/v2": {"ecosystem": "go", "module_path": "github.com/foo/bar/v2"},
+        "github.com/foo/bar/v3": {"ecosystem": "go", "module_path": "github.com/foo/bar/v3"},
+        "gitlab.com/group/proj/v2": {"ecosystem": "go"},
+    }
+    with offline_mode.set_responses({}):
+        resolved = resolve_package_urls(packages, logger=None, cache={})
+    assert resolved["github.com/foo/bar/v2"] == "https://github.com/foo/bar"
+    assert resolved["github.com/foo/bar/v3"] == "https://github.com/foo/bar"
+    assert resolved["gitlab.com/group/proj/v2"] == "https://gitlab.com/group/proj"
+
+
+@pytest.mark.unit
+def test_go_monorepo_modules_record_their_repository_subpath(offline_mode):
+    packages = {
+        "github.com/kubernetes/kubernetes/staging/src/k8s.io/api": {"ecosystem": "go"},
+        "github.com/foo/bar/tools/v2": {"ecosystem": "go", "module_path": "github.com/foo/bar/tools/v2"},
+        "github.com/foo/bar/v2": {"ecosystem": "go", "module_path": "github.com/foo/bar/v2"},
+        "go.example.org/mono/sdk": {"ecosystem": "go"},
+        "go.example.org/lib": {"ecosystem": "go"},
+    }
+    responses = {
+        "https://go.example.org/mono/sdk?go-get=1": (
+            '<meta name="go-import" content="go.example.org/mono git https://github.com/example/mono">'
+        ),
+        "https://go.example.org/lib?go-get=1": (
+            '<meta name="go-import" content="go.example.org/lib git https://github.com/example/lib">'
+        ),
+    }
+    receipts = {}
+    with offline_mode.set_responses(responses):
+        resolved = resolve_package_urls(packages, logger=None, cache={}, receipts=receipts)
+
+    k8s_api = "github.com/kubernetes/kubernetes/staging/src/k8s.io/api"
+    assert resolved[k8s_api] == "https://github.com/kubernetes/kubernetes"
+    assert receipts[k8s_api]["repository_subpath"] == "staging/src/k8s.io/api"
+    # The major-version suffix is not a directory of the repository
+    assert receipts["github.com/foo/bar/tools/v2"]["repository_subpath"] == "tools"
+    assert "repository_subpath" not in receipts["github.com/foo/bar/v2"]
+    # Vanity paths split at the meta tag's VCS root
+    assert resolved["go.example.org/mono/sdk"] == "https://github.com/example/mono"
+    assert receipts["go.example.org/mono/sdk"]["repo_root"] == "go.example.org/mono"
+    assert receipts["go.example.org/mono/sdk"]["repository_subpath"] == "sdk"
+    assert "repository_subpath" not in receipts["go.example.org/lib"]
+
+
+@pytest.mark.unit
+def test_go_replace_directives_resolve_from_target(offline_mode):
+    packages = {
+        "github.com/foo/bar": {
+            "ecosystem": "go",
+            "replace": {"path": "github.com/fork/bar", "version": "v1.2.3", "local": False},
+        },
+        "github.com/foo/local": {
+            "ecosystem": "go",
+            "replace": {"path": "./local/path", "version": "", "local": True},
+        },
+    }
+    receipts = {}
+    # A stale upstream entry in the cache must not shadow the replacement
+    cache = {"go:github.com/foo/bar": "https://github.com/foo/bar"}
+    with offline_mode.set_responses({}):
+        resolved = resolve_package_urls(packages, logger=None, cache=cache, receipts=receipts)
+
+    assert resolved["github.com/foo/bar"] == "https://github.com/fork/bar"
+    assert receipts["github.com/foo/bar"]["replaced_from"] == "github.com/foo/bar"
+    assert receipts["github.com/foo/bar"]["replaced_to"] == "github.com/fork/bar@v1.2.3"
+
+    assert "github.com/foo/local" not in resolved
+    assert receipts["github.com/foo/local"]["reason"] == "replaced-local"
+    assert receipts["github.com/foo/local"]["local_path"] == "./local/path"
+
+
+@pytest.mark.unit
+def test_go_proxy_metadata_picks_highest_release(offline_mode):
+    base = "https://proxy.golang.org/github.com/!burnt!sushi/toml/@v"
+    responses = {
+        f"{base}/list": "v1.2.0\nv1.10.0\nv1.11.0-rc.1\nv1.9.3\n",
+        f"{base}/v1.10.0.info": json.dumps({"Version": "v1.10.0", "Time": "2024-05-01T12:00:00Z"}),
+    }
+    with offline_mode.set_responses(responses):
+        metadata, reason = fetch_go_proxy_metadata("github.com/BurntSushi/toml", goproxy="")
+
+    assert reason is None
+    assert metadata == {"latest_version": "v1.10.0", "published_at": "2024-05-01T12:00:00Z"}
+
+
+@pytest.mark.unit
+def test_fetch_go_mod_reads_the_version_mod_endpoint(offline_mode):
+    go_mod = "module github.com/BurntSushi/toml\n\ngo 1.18\n"
+    responses = {"https://proxy.golang.org/github.com/!burnt!sushi/toml/@v/v1.3.2.mod": go_mod}
+    with offline_mode.set_responses(responses):
+        found = fetch_go_mod("github.com/BurntSushi/toml", "v1.3.2", goproxy="")
+        missing = fetch_go_mod("github.com/BurntSushi/toml", "v9.9.9", goproxy="")
+
+    assert found == (go_mod, None)
+    assert missing == (None, "proxy-not-found")
+
+
+@pytest.mark.unit
+def test_fetch_go_version_info_reads_the_version_info_endpoint(offline_mode):
+    info = {"Version": "v1.3.2", "Time": "2023-06-08T06:11:08Z"}
+    responses = {"https://proxy.golang.org/github.com/!burnt!sushi/toml/@v/v1.3.2.info": json.dumps(info)}
+    with offline_mode.set_responses(responses):
+        found = fetch_go_version_info("github.com/BurntSushi/toml", "v1.3.2", goproxy="")
+        missing = fetch_go_version_info("github.com/BurntSushi/toml", "v9.9.9", goproxy="")
+
+    assert found == ({"version": "v1.3.2", "published_at": "2023-06-08T06:11:08Z"}, None)
+    assert missing == (None, "proxy-not-found")
+
+
+@pytest.mark.unit
+def test_go_proxy_metadata_falls_back_to_latest_and_along_goproxy_chain(offline_mode, monkeypatch):
+    monkeypatch.setenv("GOPROXY", "https://goproxy.example.com,https://proxy.golang.org")
+    latest = {"Version": "v0.0.0-20240101000000-abcdef123456", "Time": "2024-01-01T00:00:00Z"}
+    responses = {
+        # goproxy.example.com 404s, so the next proxy in the chain is consulted
+        "https://proxy.golang.org/example.org/untagged/@v/list": "",
+        "https://proxy.golang.org/example.org/untagged/@latest": json.dumps(latest),
+    }
+    with offline_mode.set_responses(responses):
+        metadata, reason = fetch_go_proxy_metadata("example.org/untagged")
+
+    assert metadata["latest_version"] == latest["Version"]
+    assert metadata["published_at"] == latest["Time"]
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "goproxy,expected",
+    [
+        ("off", "proxy-off"),
+        ("direct", "proxy-direct"),
+        ("https://proxy.golang.org,direct", "proxy-not-found"),
+        ("https://proxy.golang.org,off", "proxy-not-found"),
+    ],
+)
+def test_go_proxy_metadata_reports_reason_when_unresolved(offline_mode, goproxy, expected):
+    with offline_mode.set_responses({}):
+        metadata, reason = fetch_go_proxy_metadata("example.org/missing", goproxy=goproxy)
+
+    assert metadata is None
+    assert reason == expected
+
+
+@pytest.mark.unit
+def test_go_proxy_unreachable_only_falls_through_on_pipe(monkeypatch):
+    monkeypatch.setattr(NetworkConfig, "RETRY_BASE_DELAY", 0)
+
+    def _unreachable(url):
+        if url.startswith("https://down.example.com/"):
+            raise OSError("connection refused")
+        if url.endswith("/@v/list"):
+            return "v1.0.0\n"
+        return json.dumps({"Version": "v1.0.0", "Time": "2023-03-03T00:00:00Z"})
+
+    url_resolver.set_request_fn(_unreachable)
+    try:
+        comma = fetch_go_proxy_metadata("example.org/mod", goproxy="https://down.example.com,https://proxy.golang.org")
+        pipe = fetch_go_proxy_metadata("example.org/mod", goproxy="https://down.example.com|https://proxy.golang.org")
+    finally:
+        url_resolver.set_request_fn(None)
+
+    assert comma == (None, "proxy-unreachable")
+    assert pipe[0]["latest_version"] == "v1.0.0"
+
+
+@pytest.mark.unit
+def test_go_pkggodev_fallback_after_meta_tags_fail(monkeypatch):
+    page = (
+        '<div class="UnitMeta-repo">\n'
+        '  <a href="https://sr.ht/~owner/project" title="https://sr.ht/~owner/project" target="_blank">'
+        "https://sr.ht/~owner/project</a>\n</div>"
+    )
+    fetched = []
+
+    def _hook(url):
+        fetched.append(url)
+        if url == "https://pkg.go.dev/example.org/hosted":
+            return page
+        return None  # neither go-get meta tags nor pkg.go.dev pages for other modules
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _hook)
+    packages = {
+        "example.org/hosted": {"ecosystem": "go"},
+        "example.org/unknown": {"ecosystem": "go"},
+        "example.org/unknown/sub": {"ecosystem": "go", "module_path": "example.org/unknown"},
+    }
+    receipts = {}
+    resolved = resolve_package_urls(packages, logger=None, cache={}, receipts=receipts)
+
+    assert resolved["example.org/hosted"] == "https://sr.ht/~owner/project"
+    assert receipts["example.org/hosted"]["source"] == "pkg.go.dev"
+    assert "example.org/unknown" not in resolved
+    assert receipts["example.org/unknown"]["fallback_reason"] == "pkggodev-fetch-failed"
+    # The failed lookup for the module is cached and not repeated for its packages
+    assert fetched.count("https://pkg.go.dev/example.org/unknown") == 1
+
+
+@pytest.mark.unit
+def test_go_pkggodev_page_without_repository_link_is_cached(monkeypatch):
+    fetched = []
+
+    def _hook(url):
+        fetched.append(url)
+        return "<html><body>No repository here</body></html>"
+
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", _hook)
+    cache = {}
+    first = url_resolver.resolve_go_pkggodev("example.org/norepo", cache=cache)
+    second = url_resolver.resolve_go_pkggodev("example.org/norepo", cache=cache)
+
+    assert first == second == (None, "pkggodev-no-repo-link")
+    assert fetched == ["https://pkg.go.dev/example.org/norepo"]
+
+
+@pytest.mark.unit
+def test_resolved_urls_carry_confidence_by_source(monkeypatch):
+    vanity = '<meta name="go-import" content="golang.org/x/crypto git https://github.com/golang/crypto">'
+    pkggodev = '<div class="UnitMeta-repo"><a href="https://sr.ht/~owner/project">project</a></div>'
+    npm_scoped = {"versions": {}, "description": "Mirror of https://github.com/acme/widgets"}
+    responses = {
+        "https://golang.org/x/crypto?go-get=1": vanity,
+        "https://pkg.go.dev/example.org/hosted": pkggodev,
+        "https://registry.npmjs.org/@acme%2Fwidgets": json.dumps(npm_scoped),
+    }
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", responses.get)
+    packages = {
+        "github.com/gin-gonic/gin": {"ecosystem": "go"},
+        "golang.org/x/crypto": {"ecosystem": "go"},
+        "example.org/hosted": {"ecosystem": "go"},
+        "@acme/widgets": {"ecosystem": "npm"},
+        "forge-std": {"ecosystem": "solidity", "gitmodules_url": "https://github.com/foundry-rs/forge-std"},
+        "example.org/missing": {"ecosystem": "go"},
+    }
+    receipts = {}
+    resolve_package_urls(packages, logger=None, cache={}, receipts=receipts)
+
+    confidence = {name: receipt.get("confidence") for name, receipt in receipts.items()}
+    assert confidence == {
+        "github.com/gin-gonic/gin": url_resolver.URL_CONFIDENCE_DECLARED,
+        "golang.org/x/crypto": url_resolver.URL_CONFIDENCE_VANITY,
+        "example.org/hosted": url_resolver.URL_CONFIDENCE_PKGGODEV,
+        "@acme/widgets": url_resolver.URL_CONFIDENCE_HEURISTIC,
+        "forge-std": url_resolver.URL_CONFIDENCE_DECLARED,
+        "example.org/missing": None,
+    }
+    assert (
+        url_resolver.URL_CONFIDENCE_DECLARED
+        > url_resolver.URL_CONFIDENCE_VANITY
+        > url_resolver.URL_CONFIDENCE_PKGGODEV
+        > url_resolver.URL_CONFIDENCE_HEURISTIC
+    )
+
+
+@pytest.mark.unit
+def test_registry_repository_field_outranks_homepage_links(offline_mode):
+    pypi_meta = {"info": {"project_urls": {}, "home_page": "https://github.com/pallets/flask"}}
+    npm_meta = {"dist-tags": {"latest": "1.0.0"}, "versions": {"1.0.0": {"repository": "github:lodash/lodash"}}}
+    responses = {
+        "https://pypi.org/pypi/flask/json": json.dumps(pypi_meta),
+        "https://registry.npmjs.org/lodash": json.dumps(npm_meta),
+    }
+    receipts = {}
+    with offline_mode.set_responses(responses):
+        resolve_package_urls(
+            {"flask": {"ecosystem": "pypi"}, "lodash": {"ecosystem": "npm"}}, logger=None, cache={}, receipts=receipts
+        )
+
+    assert receipts["lodash"] == {
+        "source": "registry",
+        "attempts": 1,
+        "confidence": url_resolver.URL_CONFIDENCE_REGISTRY,
+    }
+    assert receipts["flask"] == {
+        "source": "registry-link",
+        "attempts": 1,
+        "confidence": url_resolver.URL_CONFIDENCE_REGISTRY_LINK,
+    }
+
+
+def _flaky(failures, payload):
+    """Request hook failing with each of `failures` (HTTP status or None for no response) before succeeding"""
+    calls = []
+
+    def _hook(url):
+        calls.append(url)
+        if len(calls) <= len(failures):
+            status = failures[len(calls) - 1]
+            if status is None:
+                raise OSError("connection reset")
+            raise urllib.error.HTTPError(url, status, "error", {}, None)
+        return payload
+
+    return _hook, calls
+
+
+@pytest.mark.unit
+def test_transient_registry_failures_are_retried_and_counted(monkeypatch):
+    monkeypatch.setattr(NetworkConfig, "RETRY_BASE_DELAY", 0)
+    npm_meta = {"versions": {}, "repository": "https://github.com/lodash/lodash"}
+    hook, calls = _flaky([503, None, 429], json.dumps(npm_meta))
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", hook)
+
+    receipts = {}
+    resolved = resolve_package_urls({"lodash": {"ecosystem": "npm"}}, logger=None, cache={}, receipts=receipts)
+
+    assert resolved["lodash"] == "https://github.com/lodash/lodash"
+    assert receipts["lodash"]["attempts"] == 4
+    assert len(calls) == 4
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize("status", [404, 400, 403])
+def test_client_errors_are_never_retried(monkeypatch, status):
+    monkeypatch.setattr(NetworkConfig, "RETRY_BASE_DELAY", 0)
+    hook, calls = _flaky([status] * 5, "{}")
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", hook)
+
+    receipts = {}
+    resolved = resolve_package_urls({"left-pad": {"ecosystem": "npm"}}, logger=None, cache={}, receipts=receipts)
+
+    assert "left-pad" not in resolved
+    assert len(calls) == 1
+    assert receipts["left-pad"]["attempts"] == 1
+
+
+@pytest.mark.unit
+def test_retries_stop_at_max_retries_with_jittered_exponential_backoff(monkeypatch):
+    monkeypatch.setattr(NetworkConfig, "MAX_RETRIES", 2)
+    monkeypatch.setattr(NetworkConfig, "RETRY_BASE_DELAY", 0.5)
+    hook, calls = _flaky([502] * 10, "{}")
+    monkeypatch.setattr(url_resolver, "_REQUEST_FN", hook)
+    jitter_ranges = []
+    sleeps = []
+    monkeypatch.setattr(url_resolver.random, "uniform", lambda low, high: jitter_ranges.append((low, high)) or high)
+    monkeypatch.setattr(url_resolver.time, "sleep", sleeps.append)
+
+    status, _ = url_resolver._go_proxy_fetch("https://proxy.golang.org/example.org/mod/@v/list")
+
+    assert status == 502
+    assert len(calls) == 3
+    assert jitter_ranges == [(0.25, 0.5), (0.5, 1.0)]
+    assert sleeps == [0.5, 1.0]
@@ -0,0 +1,122 @@
+"""
+Custom repository URL rules tried before the resolvers (--url-rule, AnalysisOptions.url_rules)
+"""
+
+import json
+import sys
+
+import pytest
+
+from gardener import main_cli
+from gardener.analysis import main as analysis_main
+from gardener.api import AnalysisOptions, resolve_repository_url
+from gardener.common.defaults import NetworkConfig
+from gardener.common.exit_codes import EXIT_INVALID_ARGUMENTS
+from gardener.package_metadata.url_resolver import URL_CONFIDENCE_DECLARED, resolve_package_urls
+from gardener.package_metadata.url_rules import parse_url_rule
+
+BITBUCKET_PATTERN = r"code\.internal\.example/(?P<team>[^/]+)/(?P<repo>[^/]+)"
+BITBUCKET_RULE = f"{BITBUCKET_PATTERN} https://bitbucket.internal.example/{{team}}/{{repo}}"
+
+
+@pytest.mark.unit
+def test_rules_fill_their_template_from_the_groups():
+    rule = parse_url_rule(BITBUCKET_RULE)
+    positional = parse_url_rule({"pattern": "@acme/(.+)", "url": "https://git.acme.example/js/{1}", "ecosystem": "npm"})
+
+    assert rule.apply("go", "code.internal.example/payments/ledger/v2") == (
+        "https://bitbucket.internal.example/payments/ledger"
+    )
+    assert rule.apply("go", "github.com/code.internal.example/a/b") is None
+    assert positional.apply("npm", "@acme/widgets") == "https://git.acme.example/js/widgets"
+    assert positional.apply("pypi", "@acme/widgets") is None
+
+
+@pytest.mark.unit
+@pytest.mark.parametrize(
+    "value",
+    [
+        "https://example.com/{1}",
+        "([a-z https://example.com/{1}",
+        "(?P<team>[a-z]+) https://example.com/{repo}",
+        "([a-z]+) https://example.com/{2}",
+        '{"pattern": "x"}',
+    ],
+)
+def test_invalid_rules_are_rejected(value):
+    with pytest.raises(ValueError):
+        parse_url_rule(value)
+
+
+@pytest.mark.unit
+def test_matching_rules_resolve_before_the_network_and_offline(monkeypatch):
+    monkeypatch.setattr(NetworkConfig, "URL_RULES", (BITBUCKET_RULE,))
+    packages = {
+        "code.internal.example/payments/ledger": {"ecosystem": "go", "version": "v1.4.0"},
+        "example.com/old": {
+            "ecosystem": "go",
+            "version": "v1.0.0",
+            "replace": {"path": "code.internal.example/platform/old", "version": "v1.1.0"},
+        },
+        "go.example.com/lib": {"ecosystem": "go", "version": "v0.1.0"},
+    }
+    receipts = {}
+
+    resolved = resolve_package_urls(packages, receipts=receipts, offline=True)
+
+    assert resolved == {
+        "code.internal.example/payments/ledger": "https://bitbucket.internal.example/payments/ledger",
+        "example.com/old": "https://bitbucket.internal.example/platform/old",
+    }
+    receipt = receipts["code.internal.example/payments/ledger"]
+    assert receipt["source"] == "custom-rule"
+    assert receipt["rule"] == BITBUCKET_PATTERN
+    assert receipt["confidence"] == URL_CONFIDENCE_DECLARED
+    assert receipts["example.com/old"]["replaced_to"] == "code.internal.example/platform/old@v1.1.0"
+    assert receipts["go.example.com/lib"]["reason"] == "offline-skipped"
+
+
+@pytest.mark.unit
+def test_api_callbacks_act_as_rules(offline_mode):
+    calls = []
+
+    def internal_repos(ecosystem, name, version):
+        calls.append((ecosystem, name, version))
+        return f"https://git.acme.example/{name.split('/')[-1]}" if name.startswith("acme.example/") else None
+
+    options = AnalysisOptions(offline=True, url_rules=[internal_repos])
+    result = resolve_repository_url("go", "acme.example/tools/lint", "v0.3.0", options=options)
+
+    assert result.repository_url == "https://git.acme.example/lint"
+    assert result.resolution["source"] == "custom-rule"
+    assert result.resolution["rule"] == "internal_repos"
+    assert calls == [("go", "acme.example/tools/lint", "v0.3.0")]
+    with pytest.raises(ValueError):
+        resolve_repository_url("go", "acme.example/x", options=AnalysisOptions(url_rules=["no-url"]))
+
+
+@pytest.mark.unit
+def test_settings_file_rules_are_rejected_and_invalid_rules(tmp_path, monkeypatch, capsys):
+    repo = tmp_path / "repo"
+    repo.mkdir()
+    (repo / "go.mod").write_text("module example.com/app\n\ngo 1.21\n")
+    (repo / ".gardener.yaml").write_text(
+        "url-rule:\n"
+        "  - pattern: 'code\\.internal\\.example/([^/]+)/([^/]+)'\n"
+        "    url: 'https://bitbucket.internal.example/{1}/{2}'\n"
+        "    ecosystem: go\n"
+    )
+    monkeypatch.setattr(analysis_main.RepositoryAnalyzer, "scan_repo", lambda self: None)
+    monkeypatch.setattr(sys, "argv", ["gardener", str(repo), "--check-config"])
+    # An analyzed repository must not pick where its own packages resolve to
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    assert excinfo.value.code == EXIT_INVALID_ARGUMENTS
+    message = json.loads(capsys.readouterr().err.strip().splitlines()[-1])["message"]
+    assert message == ".gardener.yaml: 'url-rule' can only be given on the command line"
+
+    argv = ["gardener", str(repo), "--no-repo-config", "--url-rule", "(unclosed https://example.com/{1}"]
+    monkeypatch.setattr(sys, "argv", argv)
+    with pytest.raises(SystemExit) as excinfo:
+        main_cli.main()
+    assert excinfo.value.code == EXIT_INVALID_ARGUMENTS
@@ -0,0 +1,93 @@
+"""
+Fuzzing the Go import extractor with randomized but well-formed import blocks
+
+Each generated block is rendered with random spacing, comment placement, literal
+forms and line endings, and the extracted imports are checked against the specs
+it was rendered from
+"""
+
+import pytest
+
+from tests.support.go_imports import SEED_CORPUS, extract_go_import_specs
+
+hypothesis = pytest.importorskip("hypothesis")
+st = hypothesis.strategies
+
+GO_KEYWORDS = frozenset(
+    "break case chan const continue default defer else fallthrough for func go goto if import interface map "
+    "package range return select struct switch type var".split()
+)
+
+SPACES = st.sampled_from([" ", "\t", "  ", " \t"])
+# Comments that must not be read as imports, wherever they are placed
+COMMENTS = st.sampled_from(["", "// note", '// import "fake/line"', '/* "fake/block" */', "/*\n\t\"fake/multi\"\n*/"])
+SEGMENT = st.text(alphabet="abcdefghijklmnopqrstuvwxyz0123456789-_.~", min_size=1, max_size=8).filter(
+    lambda segment: not segment.startswith(".")
+)
+IMPORT_PATH = st.lists(SEGMENT, min_size=1, max_size=4).map("/".join).filter(lambda path: path != "C")
+IDENTIFIER = st.from_regex(r"[a-z][a-z0-9_]{0,6}", fullmatch=True).filter(lambda name: name not in GO_KEYWORDS)
+
+
+@st.composite
+def import_specs(draw):
+    """(path, kind, alias) of one import spec"""
+    path = draw(IMPORT_PATH)
+    kind = draw(st.sampled_from(["named", "aliased", "dot", "blank"]))
+    return path, kind, draw(IDENTIFIER) if kind == "aliased" else None
+
+
+def _literal(draw, path):
+    style = draw(st.sampled_from(["interpreted", "raw", "hex", "octal", "unicode"]))
+    if style == "raw":
+        return f"`{path}`"
+    escape = {"interpreted": path[0], "hex": f"\\x{ord(path[0]):02x}", "octal": f"\\{ord(path[0]):03o}"}
+    return '"' + escape.get(style, f"\\u{ord(path[0]):04x}") + path[1:] + '"'
+
+
+def _render_spec(draw, spec):
+    path, kind, alias = spec
+    name = {"dot": ".", "blank": "_", "aliased": alias}.get(kind)
+    text = _literal(draw, path)
+    if name:
+        text = name + draw(SPACES) + text
+    trailing = draw(COMMENTS)
+    return text + (draw(SPACES) + trailing if trailing and "\n" not in trailing else "")
+
+
+@st.composite
+def import_blocks(draw):
+    """(source, expected specs) of a file with several import declarations, single and grouped"""
+    declarations = draw(st.lists(st.tuples(st.booleans(), st.lists(import_specs(), max_size=4)), max_size=4))
+    newline = draw(st.sampled_from(["\n", "\r\n"]))
+    lines = ["package main", ""]
+    expected = []
+    for grouped, specs in declarations:
+        if not grouped and len(specs) == 1:
+            lines.append("import" + draw(SPACES) + _render_spec(draw, specs[0]))
+        else:
+            lines.append("import" + draw(st.sampled_from(["", " ", "\t"])) + "(")
+            for spec in specs:
+                comment = draw(COMMENTS)
+                if comment:
+                    lines.append("\t" + comment)
+                lines.append(draw(SPACES) + _render_spec(draw, spec))
+            lines.append(")")
+        expected.extend(specs)
+        lines.append(draw(COMMENTS))
+    lines.append("func main() {}")
+    return "\n".join(lines).replace("\n", newline), expected
+
+
+def _examples(test):
+    for _, source, expected in SEED_CORPUS:
+        test = hypothesis.example((source, expected))(test)
+    return test
+
+
+@pytest.mark.unit
+@hypothesis.settings(max_examples=300, deadline=None)
+@hypothesis.given(import_blocks())
+@_examples
+def test_randomized_import_blocks_extract_like_the_reference(block):
+    source, expected = block
+    assert extract_go_import_specs(source) == expected